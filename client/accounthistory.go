@@ -0,0 +1,20 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// AccountHistory fetches a page of the history of the movements of an L2 account
+func (c *WaspClient) AccountHistory(chainID *isc.ChainID, agentID isc.AgentID, offset, limit uint32) (*model.AccountHistoryResponse, error) {
+	res := &model.AccountHistoryResponse{}
+	route := fmt.Sprintf("%s?offset=%d&limit=%d", routes.AccountHistory(chainID.String(), agentID.String()), offset, limit)
+	if err := c.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
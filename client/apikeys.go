@@ -0,0 +1,35 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+func (c *WaspClient) GetAPIKeys() ([]*model.APIKey, error) {
+	var response []*model.APIKey
+	err := c.do(http.MethodGet, routes.AdmAPIKeys(), nil, &response)
+	return response, err
+}
+
+func (c *WaspClient) GetAPIKey(key string) (*model.APIKey, error) {
+	var response *model.APIKey
+	err := c.do(http.MethodGet, routes.AdmAPIKey(key), nil, &response)
+	return response, err
+}
+
+// PutAPIKey creates or updates the API key. The node generates the key, if it
+// is empty, and uses the default quota, if the rate is 0.
+func (c *WaspClient) PutAPIKey(apiKey *model.APIKey) (*model.APIKey, error) {
+	var response model.APIKey
+	err := c.do(http.MethodPost, routes.AdmAPIKeys(), apiKey, &response)
+	return &response, err
+}
+
+func (c *WaspClient) DeleteAPIKey(key string) error {
+	return c.do(http.MethodDelete, routes.AdmAPIKey(key), nil, nil)
+}
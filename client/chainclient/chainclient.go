@@ -45,6 +45,10 @@ type PostRequestParams struct {
 	NFT       *isc.NFT
 	Allowance *isc.Allowance
 	GasBudget *uint64
+	// Sponsor countersigns the off-ledger request and pays its gas fee, up to SponsorMaxFee tokens.
+	// The chain owner must allow the sponsor, otherwise the sender pays
+	Sponsor       *cryptolib.KeyPair
+	SponsorMaxFee uint64
 }
 
 func defaultParams(params ...PostRequestParams) PostRequestParams {
@@ -171,6 +175,33 @@ func (c *Client) PostOffLedgerRequest(
 	}
 	req.WithNonce(par.Nonce)
 	signed := req.Sign(c.KeyPair)
+	if par.Sponsor != nil {
+		signed = signed.(isc.SponsoredOffLedgerRequest).WithSponsor(par.Sponsor, par.SponsorMaxFee)
+	}
+	return signed, c.WaspClient.PostOffLedgerRequest(c.ChainID, signed)
+}
+
+// PostMultiCallRequest sends an off-ledger multi-call request via the wasp node web api.
+// The calls are run atomically, in the given order. There must be 1 to isc.MaxCallsInMultiCallRequest calls.
+// Only the nonce and the gas budget are taken from the params
+func (c *Client) PostMultiCallRequest(
+	calls []*isc.Call,
+	params ...PostRequestParams,
+) (isc.MultiCallRequest, error) {
+	req, err := isc.NewOffLedgerMultiCallRequest(c.ChainID, calls, 0)
+	if err != nil {
+		return nil, err
+	}
+	par := defaultParams(params...)
+	if par.Nonce == 0 {
+		c.nonces[c.KeyPair.Address().Key()]++
+		par.Nonce = c.nonces[c.KeyPair.Address().Key()]
+	}
+	req = req.WithNonce(par.Nonce)
+	if par.GasBudget != nil {
+		req = req.WithGasBudget(*par.GasBudget)
+	}
+	signed := req.Sign(c.KeyPair)
 	return signed, c.WaspClient.PostOffLedgerRequest(c.ChainID, signed)
 }
 
@@ -18,6 +18,7 @@ type WaspClient struct {
 	httpClient http.Client
 	baseURL    string
 	token      string
+	apiKey     string
 }
 
 // NewWaspClient returns a new *WaspClient with the given baseURL and httpClient.
@@ -37,6 +38,13 @@ func (c *WaspClient) WithToken(token string) *WaspClient {
 	return c
 }
 
+// WithAPIKey sets the API key, which gives the client its own rate limit quota
+func (c *WaspClient) WithAPIKey(apiKey string) *WaspClient {
+	c.apiKey = apiKey
+
+	return c
+}
+
 func processResponse(res *http.Response, decodeTo interface{}) error {
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -91,6 +99,10 @@ func (c *WaspClient) do(method, route string, reqObj, resObj interface{}) error
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.token))
 	}
 
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
 	// make the request
 	res, err := c.httpClient.Do(req)
 	if err != nil {
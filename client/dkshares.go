@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 )
@@ -21,6 +22,13 @@ func (c *WaspClient) DKSharesPost(request *model.DKSharesPostRequest) (*model.DK
 	return &response, err
 }
 
+// DKSharesReshare moves an existing DKShare to a new set of nodes and returns its new state.
+func (c *WaspClient) DKSharesReshare(addr iotago.Address, request *model.DKSharesReshareRequest) (*model.DKSharesInfo, error) {
+	var response model.DKSharesInfo
+	err := c.do(http.MethodPost, routes.DKSharesReshare(addr.Bech32(parameters.L1().Protocol.Bech32HRP)), request, &response)
+	return &response, err
+}
+
 // DKSharesGet retrieves the representation of an existing DKShare.
 func (c *WaspClient) DKSharesGet(addr iotago.Address) (*model.DKSharesInfo, error) {
 	var response model.DKSharesInfo
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/grpcapi"
+	"github.com/iotaledger/wasp/packages/grpcapi/pb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCClient allows to make requests to the gRPC API of the Wasp node. It
+// streams the blocks and the events of the chains.
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client pb.WaspClient
+	apiKey string
+}
+
+// NewGRPCClient connects to the gRPC API at the target address. The connection
+// is not encrypted, unless the options say otherwise.
+func NewGRPCClient(target string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCClient{conn: conn, client: pb.NewWaspClient(conn)}, nil
+}
+
+// WithAPIKey sets the API key, which gives the client its own rate limit quota
+func (c *GRPCClient) WithAPIKey(apiKey string) *GRPCClient {
+	c.apiKey = apiKey
+
+	return c
+}
+
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *GRPCClient) context(ctx context.Context) context.Context {
+	if c.apiKey == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpcapi.APIKeyMetadata, c.apiKey)
+}
+
+func (c *GRPCClient) GetChainInfo(chainID *isc.ChainID) (*pb.ChainInfo, error) {
+	return c.client.GetChainInfo(c.context(context.Background()), &pb.GetChainInfoRequest{ChainId: chainID.String()})
+}
+
+func (c *GRPCClient) CallView(chainID *isc.ChainID, hContract isc.Hname, functionName string, args dict.Dict) (dict.Dict, error) {
+	return c.CallViewByHname(chainID, hContract, isc.Hn(functionName), args)
+}
+
+func (c *GRPCClient) CallViewByHname(chainID *isc.ChainID, hContract, hFunction isc.Hname, args dict.Dict) (dict.Dict, error) {
+	params := make(map[string][]byte, len(args))
+	for k, v := range args {
+		params[string(k)] = v
+	}
+	res, err := c.client.CallView(c.context(context.Background()), &pb.CallViewRequest{
+		ChainId:       chainID.String(),
+		ContractHname: uint32(hContract),
+		FunctionHname: uint32(hFunction),
+		Params:        params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	for k, v := range res.Result {
+		ret.Set(kv.Key(k), v)
+	}
+	return ret, nil
+}
+
+// StateGet fetches the raw value associated with the given key in the chain state
+func (c *GRPCClient) StateGet(chainID *isc.ChainID, key string) ([]byte, error) {
+	res, err := c.client.StateGet(c.context(context.Background()), &pb.StateGetRequest{
+		ChainId: chainID.String(),
+		Key:     []byte(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Value, nil
+}
+
+func (c *GRPCClient) PostOffLedgerRequest(chainID *isc.ChainID, req isc.OffLedgerRequest) error {
+	_, err := c.client.SubmitRequest(c.context(context.Background()), &pb.SubmitRequestRequest{
+		ChainId: chainID.String(),
+		Request: req.Bytes(),
+	})
+	return err
+}
+
+// RequestReceipt fetches the processing status of a request.
+func (c *GRPCClient) RequestReceipt(chainID *isc.ChainID, reqID isc.RequestID) (*isc.Receipt, error) {
+	res, err := c.client.GetRequestReceipt(c.context(context.Background()), &pb.GetRequestReceiptRequest{
+		ChainId:   chainID.String(),
+		RequestId: reqID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receiptFromPB(res), nil
+}
+
+// WaitUntilRequestProcessed blocks until the request has been processed by the node
+func (c *GRPCClient) WaitUntilRequestProcessed(chainID *isc.ChainID, reqID isc.RequestID, timeout time.Duration) (*isc.Receipt, error) {
+	if timeout == 0 {
+		timeout = grpcapi.WaitRequestProcessedDefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.context(context.Background()), timeout)
+	defer cancel()
+	res, err := c.client.WaitRequestProcessed(ctx, &pb.GetRequestReceiptRequest{
+		ChainId:   chainID.String(),
+		RequestId: reqID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receiptFromPB(res), nil
+}
+
+func receiptFromPB(res *pb.GetRequestReceiptResponse) *isc.Receipt {
+	if !res.Found {
+		return nil
+	}
+	return &isc.Receipt{
+		Request:       res.Receipt.Request,
+		GasBudget:     res.Receipt.GasBudget,
+		GasBurned:     res.Receipt.GasBurned,
+		GasFeeCharged: res.Receipt.GasFeeCharged,
+		BlockIndex:    res.Receipt.BlockIndex,
+		RequestIndex:  uint16(res.Receipt.RequestIndex),
+		ResolvedError: res.Receipt.Error,
+	}
+}
+
+// SubscribeBlocks calls f with each new block of the chain, until the context
+// is canceled or the stream fails
+func (c *GRPCClient) SubscribeBlocks(ctx context.Context, chainID *isc.ChainID, f func(*pb.Block)) error {
+	stream, err := c.client.SubscribeBlocks(c.context(ctx), &pb.SubscribeBlocksRequest{ChainId: chainID.String()})
+	if err != nil {
+		return err
+	}
+	for {
+		block, err := stream.Recv()
+		if err != nil {
+			return streamError(ctx, err)
+		}
+		f(block)
+	}
+}
+
+// SubscribeEvents calls f with each event emitted by the contracts of the
+// chain, until the context is canceled or the stream fails
+func (c *GRPCClient) SubscribeEvents(ctx context.Context, chainID *isc.ChainID, f func(string)) error {
+	stream, err := c.client.SubscribeEvents(c.context(ctx), &pb.SubscribeEventsRequest{ChainId: chainID.String()})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return streamError(ctx, err)
+		}
+		f(event.Message)
+	}
+}
+
+// streamError returns nil, when the stream was closed by the client
+func streamError(ctx context.Context, err error) error {
+	if err == io.EOF || ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
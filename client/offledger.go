@@ -14,3 +14,19 @@ func (c *WaspClient) PostOffLedgerRequest(chainID *isc.ChainID, req isc.OffLedge
 	}
 	return c.do(http.MethodPost, routes.NewRequest(chainID.String()), data, nil)
 }
+
+// PostOffLedgerRequests posts a batch of off-ledger requests, and returns the
+// result of each request
+func (c *WaspClient) PostOffLedgerRequests(chainID *isc.ChainID, reqs []isc.OffLedgerRequest) ([]*model.OffLedgerRequestResult, error) {
+	data := model.OffLedgerRequestBatchBody{
+		Requests: make([]model.Bytes, len(reqs)),
+	}
+	for i, req := range reqs {
+		data.Requests[i] = model.NewBytes(req.Bytes())
+	}
+	var res model.OffLedgerRequestBatchResponse
+	if err := c.do(http.MethodPost, routes.NewRequestBatch(chainID.String()), data, &res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
@@ -69,3 +69,12 @@ func (c *WaspClient) WaitUntilAllRequestsProcessed(chainID *isc.ChainID, tx *iot
 	}
 	return ret, nil
 }
+
+// RequestLifecycle fetches the current stage of a request in the node, and its recent history
+func (c *WaspClient) RequestLifecycle(chainID *isc.ChainID, reqID isc.RequestID) (*model.RequestLifecycleResponse, error) {
+	var res model.RequestLifecycleResponse
+	if err := c.do(http.MethodGet, routes.RequestLifecycle(chainID.String(), reqID.String()), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -3,9 +3,28 @@ package scclient
 import (
 	"time"
 
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 )
 
 func (c *SCClient) CallView(functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
 	return c.ChainClient.CallView(c.ContractHname, functionName, args, optimisticReadTimeout...)
 }
+
+// CallViewAllPages calls the list-style view page by page, until the last page. The result of each page is
+// passed to f. See coreutil.ParamPageCursor
+func (c *SCClient) CallViewAllPages(functionName string, args dict.Dict, f func(page dict.Dict) error) error {
+	var cursor []byte
+	for {
+		res, err := c.CallView(functionName, coreutil.PageParams(args, cursor, 0))
+		if err != nil {
+			return err
+		}
+		if err := f(res); err != nil {
+			return err
+		}
+		if cursor = coreutil.NextPageCursor(res); cursor == nil {
+			return nil
+		}
+	}
+}
@@ -85,7 +85,7 @@ func TestListBlobs(t *testing.T) {
 
 	fList := coreblob.ScFuncs.ListBlobs(ctx)
 	fList.Func.Call()
-	size := fList.Results.BlobSizes().GetInt32(wasmtypes.HashFromString(expectedHash)).Value()
+	size := fList.Results.BlobSetSizes().GetInt32(wasmtypes.HashFromString(expectedHash)).Value()
 	// The sum of the size of the value of `key0` and `key1` is len("val0")+len("_val1") = 9
 	require.Equal(t, int32(9), size)
 }
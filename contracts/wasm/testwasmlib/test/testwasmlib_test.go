@@ -377,6 +377,16 @@ func TestWasmTypes(t *testing.T) {
 	agentID = isc.NewContractAgentID(chainID, isc.Hname(testwasmlib.HScName))
 	checkAgentID(t, ctx, scAgentID, agentID)
 
+	// check agent id of multisig account
+	policy, err := isc.NewMultisigPolicy(1, ctx.Chain.OriginatorPrivateKey.GetPublicKey())
+	require.NoError(t, err)
+	multisigAgentID := isc.NewMultisigAgentID(policy)
+	scAgentID = ctx.Cvt.ScAgentID(multisigAgentID)
+	require.True(t, scAgentID.IsMultisig())
+	require.True(t, scAgentID == wasmtypes.NewScAgentIDMultisig(wasmtypes.HashFromBytes(multisigAgentID.ID().Bytes())))
+	require.True(t, multisigAgentID.Equals(ctx.Cvt.IscAgentID(&scAgentID)))
+	checkAgentID(t, ctx, scAgentID, multisigAgentID)
+
 	// check nil agent id
 	scAgentID = wasmtypes.ScAgentID{}
 	agentID = &isc.NilAgentID{}
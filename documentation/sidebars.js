@@ -108,6 +108,11 @@ module.exports = {
                             label: 'evm',
                             id: 'guide/core_concepts/core_contracts/evm',
                         },
+                        {
+                            type: 'doc',
+                            label: 'scheduler',
+                            id: 'guide/core_concepts/core_contracts/scheduler',
+                        },
                     ],
                 },
                 {
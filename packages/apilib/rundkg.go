@@ -41,3 +41,18 @@ func RunDKG(apiHosts, peerPubKeys []string, threshold, initiatorIndex uint16, ti
 
 	return addr, nil
 }
+
+// ReshareDKG moves the distributed key with the given address to a new set of nodes
+// and/or threshold, keeping the address. The initiator must hold a share of the key.
+func ReshareDKG(initiatorHost string, sharedAddress iotago.Address, peerPubKeys []string, threshold uint16, timeout ...time.Duration) error {
+	to := uint32(60 * 1000)
+	if len(timeout) > 0 {
+		to = uint32(timeout[0].Milliseconds())
+	}
+	_, err := client.NewWaspClient(initiatorHost).DKSharesReshare(sharedAddress, &model.DKSharesReshareRequest{
+		PeerPubKeys: peerPubKeys,
+		Threshold:   threshold,
+		TimeoutMS:   to,
+	})
+	return err
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/authentication/shared"
 
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/users"
@@ -18,6 +19,8 @@ const (
 	AuthNone        = "none"
 )
 
+const jwtSecretLabel = "wasp-jwt-secret"
+
 type AuthConfiguration struct {
 	Scheme    string `koanf:"scheme"`
 	AddRoutes bool   `koanf:"addRoutes"`
@@ -79,10 +82,16 @@ func AddAuthentication(webAPI WebAPI, registryProvider registry.Provider, config
 	case AuthJWT:
 		nodeIdentity := registryProvider().GetNodeIdentity()
 
-		privateKey := nodeIdentity.GetPrivateKey().AsBytes()
+		// The identity key is held by the signer, thus the JWT secret is derived from its
+		// signature of a fixed label. The Ed25519 signatures are deterministic.
+		signature, err := nodeIdentity.Sign([]byte(jwtSecretLabel))
+		if err != nil {
+			panic(fmt.Sprintf("Cannot derive the JWT secret: %v", err))
+		}
+		jwtSecret := hashing.HashData(signature)
 
 		// The primary claim is the one mandatory claim that gives access to api/webapi/alike
-		jwtAuth := AddJWTAuth(webAPI, config.JWTConfig, privateKey, userMap, claimValidator)
+		jwtAuth := AddJWTAuth(webAPI, config.JWTConfig, jwtSecret[:], userMap, claimValidator)
 
 		authHandler := &AuthHandler{Jwt: jwtAuth, Users: userMap}
 		webAPI.POST(shared.AuthRoute(), authHandler.CrossAPIAuthHandler)
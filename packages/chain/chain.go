@@ -12,9 +12,11 @@ import (
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/nodeclient"
 	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
@@ -55,6 +57,9 @@ type ChainRequests interface {
 	AttachToRequestProcessed(func(isc.RequestID)) (attachID *events.Closure)
 	DetachFromRequestProcessed(attachID *events.Closure)
 	EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgIn)
+	EnqueueOffLedgerRequestMsgs(msgs []*messages.OffLedgerRequestMsgIn)
+	GetMempoolRequests() []isc.Request
+	RequestLifecycle() *lifecycle.Tracker
 }
 
 type ChainMetrics interface {
@@ -67,6 +72,10 @@ type ChainRunner interface {
 	GetAnchorOutput() *isc.AliasOutputWithID
 	GetTimeData() time.Time
 	GetDB() kvstore.KVStore
+	// GetEVMIndex returns the node-side archive of the EVM blocks, or nil if it is not enabled
+	GetEVMIndex() *evmindex.Index
+	// GetSyncInfo returns the state synchronization status, or nil if it is not known yet
+	GetSyncInfo() *SyncInfo
 }
 
 type Chain interface {
@@ -143,7 +152,10 @@ type ChainNodeConnection interface {
 type StateManager interface {
 	Ready() *ready.Ready
 	EnqueueGetBlockMsg(msg *messages.GetBlockMsgIn)
+	EnqueueGetBlocksMsg(msg *messages.GetBlocksMsgIn)
 	EnqueueBlockMsg(msg *messages.BlockMsgIn)
+	EnqueueGetTrieNodesMsg(msg *messages.GetTrieNodesMsgIn)
+	EnqueueTrieNodesMsg(msg *messages.TrieNodesMsgIn)
 	EnqueueAliasOutput(*isc.AliasOutputWithID)
 	EnqueueStateCandidateMsg(state.VirtualStateAccess, *iotago.UTXOInput)
 	EnqueueTimerMsg(msg messages.TimerTick)
@@ -187,6 +199,19 @@ type SyncInfo struct {
 	StateOutput           *isc.AliasOutputWithID
 	StateOutputCommitment trie.VCommitment
 	StateOutputTimestamp  time.Time
+	// FastSync is the progress of downloading the state trie, nil if the state is not being fast synced
+	FastSync *FastSyncInfo
+}
+
+// FastSyncInfo is the progress of the fast sync: the state with the commitment
+// TargetStateCommitment is downloaded node by node, instead of applying the blocks
+type FastSyncInfo struct {
+	TargetBlockIndex      uint32
+	TargetStateCommitment trie.VCommitment
+	StartTime             time.Time
+	NodesDownloaded       int // nodes received from the peers and verified
+	NodesReused           int // nodes already in the DB, which match the target state
+	NodesPending          int // nodes known to be missing, being requested from the peers
 }
 
 type ConsensusInfo struct {
@@ -13,11 +13,14 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
 	dss_node_pkg "github.com/iotaledger/wasp/packages/chain/dss/node"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	mempool_pkg "github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/chain/nodeconnchain"
 	"github.com/iotaledger/wasp/packages/chain/statemgr"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
@@ -28,6 +31,7 @@ import (
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util/pipe"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/processors"
@@ -83,6 +87,9 @@ type chainObj struct {
 	timerTickMsgPipe                   pipe.Pipe
 	consensusJournalRegistry           journal.Registry
 	wal                                chain.WAL
+	requestLifecycle                   *lifecycle.Tracker
+	evmIndex                           *evmindex.Index
+	vmRunner                           vm.VMRunner
 }
 
 type committeeStruct struct {
@@ -105,6 +112,8 @@ func NewChain(
 	chainMetrics metrics.ChainMetrics,
 	consensusJournalRegistry journal.Registry,
 	wal chain.WAL,
+	evmIndexEnabled bool,
+	vmRunner vm.VMRunner,
 ) chain.Chain {
 	var err error
 	log.Debugf("creating chain object for %s", chainID.String())
@@ -146,7 +155,9 @@ func NewChain(
 		timerTickMsgPipe:                 pipe.NewLimitInfinitePipe(1),
 		consensusJournalRegistry:         consensusJournalRegistry,
 		wal:                              wal,
+		requestLifecycle:                 lifecycle.New(lifecycle.DefaultMaxRequests),
 		dssNode:                          dss_node_pkg.New(&peeringID, netProvider, nodeIdentity, log),
+		vmRunner:                         vmRunner,
 	}
 	ret.nodeConn, err = nodeconnchain.NewChainNodeConnection(chainID, nc, chainLog)
 	if err != nil {
@@ -156,6 +167,15 @@ func NewChain(
 
 	ret.committee.Store(&committeeStruct{})
 
+	if evmIndexEnabled {
+		evmIndexStore, err := db.WithRealm(append(db.Realm(), dbkeys.ObjectTypeEVMIndex))
+		if err != nil {
+			ret.log.Errorf("NewChain: unable to create the EVM index store: %v", err)
+			return nil
+		}
+		ret.evmIndex = evmindex.New(evmIndexStore)
+	}
+
 	chainPeerNodes := []*cryptolib.PublicKey{netProvider.Self().PubKey()}
 	ret.chainPeers, err = netProvider.PeerDomain(peeringID, chainPeerNodes)
 	if err != nil {
@@ -195,7 +215,9 @@ func (c *chainObj) startTimer() {
 
 func (c *chainObj) receiveOnLedgerRequest(request isc.OnLedgerRequest) {
 	c.log.Debugf("receiveOnLedgerRequest: %s", request.ID())
-	c.mempool.ReceiveRequest(request)
+	if c.mempool.ReceiveRequest(request) {
+		c.requestLifecycle.Record(lifecycle.StageMempool, 0, "", request.ID())
+	}
 }
 
 func (c *chainObj) receiveCommitteePeerMessages(peerMsg *peering.PeerMessageGroupIn) {
@@ -277,6 +299,7 @@ func (c *chainObj) processChainTransition(msg *chain.ChainTransitionEventData) {
 			// remove processed requests from the mempool
 			c.log.Debugf("processChainTransition state %d cleaning state %d: removing %d requests", stateIndex, i, len(reqids))
 			c.mempool.RemoveRequests(reqids...)
+			c.requestLifecycle.Record(lifecycle.StageConfirmed, i, "", reqids...)
 			chain.PublishRequestsSettled(&chainID, i, reqids)
 			// publish events
 			for _, reqid := range reqids {
@@ -287,6 +310,15 @@ func (c *chainObj) processChainTransition(msg *chain.ChainTransitionEventData) {
 			c.log.Debugf("processChainTransition state %d: state %d cleaned, deleted requests: %+v",
 				stateIndex, i, isc.ShortRequestIDs(reqids))
 		}
+		if c.evmIndex != nil {
+			gap, err := c.evmIndex.Update(c.stateReader.KVStoreReader())
+			if err != nil {
+				c.log.Warnf("processChainTransition state %d: unable to update the EVM index: %v", stateIndex, err)
+			}
+			if gap != nil {
+				c.log.Warnf("processChainTransition state %d: EVM blocks %d-%d were pruned before they were indexed", stateIndex, gap.From, gap.To)
+			}
+		}
 		chain.PublishStateTransition(&chainID, msg.ChainOutput, len(reqids))
 		chain.LogStateTransition(stateIndex, oidStr, rootCommitment, reqids, c.log)
 
@@ -28,6 +28,7 @@ func (c *chainObj) Dismiss(reason string) {
 		if c.consensus != nil {
 			c.consensus.Close()
 		}
+		c.dssNode.Close()
 
 		c.eventRequestProcessed.DetachAll()
 		c.eventChainTransition.DetachAll()
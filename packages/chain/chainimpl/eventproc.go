@@ -15,6 +15,7 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/committee"
 	"github.com/iotaledger/wasp/packages/chain/consensus"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
@@ -52,7 +53,14 @@ func (c *chainObj) recvLoop() {
 		case msg, ok := <-offLedgerRequestMsgChannel:
 			if ok {
 				c.log.Debugf("Chainimpl::recvLoop, handleOffLedgerRequestMsg...")
-				c.handleOffLedgerRequestMsg(msg.(*messages.OffLedgerRequestMsgIn))
+				switch msgT := msg.(type) {
+				case *messages.OffLedgerRequestMsgIn:
+					c.handleOffLedgerRequestMsg(msgT)
+				case []*messages.OffLedgerRequestMsgIn:
+					for _, m := range msgT {
+						c.handleOffLedgerRequestMsg(m)
+					}
+				}
 				c.log.Debugf("Chainimpl::recvLoop, handleOffLedgerRequestMsg... Done")
 			} else {
 				offLedgerRequestMsgChannel = nil
@@ -246,7 +254,7 @@ func (c *chainObj) createNewCommitteeAndConsensus(dkShare tcrypto.DKShare) error
 	if err != nil {
 		return xerrors.Errorf("cannot load consensus journal: %w", err)
 	}
-	c.consensus = consensus.New(c, c.mempool, cmt, cmtPeerGroup, c.nodeConn, c.pullMissingRequestsFromCommittee, c.chainMetrics, c.dssNode, consensusJournal, c.wal)
+	c.consensus = consensus.New(c, c.mempool, cmt, cmtPeerGroup, c.nodeConn, c.pullMissingRequestsFromCommittee, c.chainMetrics, c.dssNode, consensusJournal, c.wal, c.requestLifecycle, c.vmRunner)
 	c.setCommittee(cmt)
 	return nil
 }
@@ -256,6 +264,13 @@ func (c *chainObj) EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgI
 	c.chainMetrics.CountMessages()
 }
 
+// EnqueueOffLedgerRequestMsgs enqueues the requests as a single message, so that
+// a large batch takes one slot of the pipe
+func (c *chainObj) EnqueueOffLedgerRequestMsgs(msgs []*messages.OffLedgerRequestMsgIn) {
+	c.offLedgerRequestPeerMsgPipe.In() <- msgs
+	c.chainMetrics.CountMessages()
+}
+
 // addToPeersHaveReq adds a peer to the list of known peers that have a given request, DOES NOT LOCK THE MUTEX
 func (c *chainObj) addToPeersHaveReq(reqID isc.RequestID, peer *cryptolib.PublicKey) {
 	if c.offLedgerPeersHaveReq[reqID] == nil {
@@ -284,6 +299,7 @@ func (c *chainObj) handleOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgIn
 		return
 	}
 	c.log.Debugf("handleOffLedgerRequestMsg: request %s added to mempool", msg.Req.ID())
+	c.requestLifecycle.Record(lifecycle.StageMempool, 0, "", msg.Req.ID())
 	c.broadcastOffLedgerRequest(msg.Req)
 	c.log.Debugf("handleOffLedgerRequestMsg: request %s broadcasted", msg.Req.ID())
 }
@@ -397,7 +413,9 @@ func (c *chainObj) handleMissingRequestMsg(msg *messages.MissingRequestMsg) {
 		return
 	}
 	if c.consensus.ShouldReceiveMissingRequest(msg.Request) {
-		c.mempool.ReceiveRequest(msg.Request)
+		if c.mempool.ReceiveRequest(msg.Request) {
+			c.requestLifecycle.Record(lifecycle.StageMempool, 0, "", msg.Request.ID())
+		}
 		c.log.Warnf("handleMissingRequestMsg request with ID %v added to mempool", msg.Request.ID().String())
 	} else {
 		c.log.Warnf("handleMissingRequestMsg ignored: consensus denied the need of request with ID %v", msg.Request.ID().String())
@@ -7,6 +7,7 @@ package chainimpl
 import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
@@ -40,6 +41,14 @@ func (c *chainObj) ResolveError(e *isc.UnresolvedVMError) (*isc.VMError, error)
 	return errors.ResolveFromState(errorsStateReader, e)
 }
 
+func (c *chainObj) GetMempoolRequests() []isc.Request {
+	return c.mempool.Requests()
+}
+
+func (c *chainObj) RequestLifecycle() *lifecycle.Tracker {
+	return c.requestLifecycle
+}
+
 func (c *chainObj) AttachToRequestProcessed(handler func(isc.RequestID)) *events.Closure {
 	closure := events.NewClosure(handler)
 	c.eventRequestProcessed.Attach(closure)
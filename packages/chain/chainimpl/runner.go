@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/isc"
 )
 
@@ -11,6 +13,10 @@ func (c *chainObj) GetAnchorOutput() *isc.AliasOutputWithID {
 	return c.stateMgr.GetStatusSnapshot().StateOutput
 }
 
+func (c *chainObj) GetSyncInfo() *chain.SyncInfo {
+	return c.stateMgr.GetStatusSnapshot()
+}
+
 func (c *chainObj) GetTimeData() time.Time {
 	return c.consensus.GetStatusSnapshot().TimeData
 }
@@ -18,3 +24,7 @@ func (c *chainObj) GetTimeData() time.Time {
 func (c *chainObj) GetDB() kvstore.KVStore {
 	return c.db
 }
+
+func (c *chainObj) GetEVMIndex() *evmindex.Index {
+	return c.evmIndex
+}
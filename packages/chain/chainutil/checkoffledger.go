@@ -0,0 +1,68 @@
+package chainutil
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+)
+
+// CheckOffLedgerRequests checks that the requests were not processed yet, that
+// the gas fee payers (the senders, or the allowed sponsors) have on-chain
+// balances and that the nonces are not too old. All
+// the requests are checked against the same state. It returns the reason to
+// reject each request, or nil if the request is acceptable.
+func CheckOffLedgerRequests(ch chain.ChainCore, reqs []isc.OffLedgerRequest) (rejected []error, err error) {
+	err = optimism.RetryOnStateInvalidated(func() (err error) {
+		rejected, err = checkOffLedgerRequests(ch.GetStateReader(), reqs)
+		return err
+	})
+	return rejected, err
+}
+
+func checkOffLedgerRequests(stateReader state.OptimisticStateReader, reqs []isc.OffLedgerRequest) (rejected []error, err error) {
+	err = panicutil.CatchPanicReturnError(func() {
+		blockIndex, e := stateReader.BlockIndex()
+		if e != nil {
+			panic(e)
+		}
+		chainState := stateReader.KVStoreReader()
+		accountsState := subrealm.NewReadOnly(chainState, kv.Key(accounts.Contract.Hname().Bytes()))
+		rejected = make([]error, len(reqs))
+		for i, req := range reqs {
+			rejected[i] = checkOffLedgerRequest(chainState, accountsState, req)
+		}
+		// all the requests must be checked against the same state
+		if bi, e := stateReader.BlockIndex(); e != nil || bi != blockIndex {
+			panic(coreutil.ErrorStateInvalidated)
+		}
+	}, coreutil.ErrorStateInvalidated)
+	return rejected, err
+}
+
+func checkOffLedgerRequest(chainState, accountsState kv.KVStoreReader, req isc.OffLedgerRequest) error {
+	reqID := req.ID()
+	processed, err := blocklog.IsRequestProcessed(chainState, &reqID)
+	if err != nil {
+		panic(err)
+	}
+	if processed {
+		return fmt.Errorf("request already processed")
+	}
+	if payer := gasFeePayer(chainState, req); accounts.GetAccountAssets(accountsState, payer).IsEmpty() {
+		return fmt.Errorf("no balance on account %s", payer.String())
+	}
+	if err := vmcontext.CheckNonce(req, accounts.GetMaxAssumedNonce(accountsState, req.SenderAccount())); err != nil {
+		return fmt.Errorf("invalid nonce, %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package chainutil
+
+import (
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// GasFeePayer returns the account the VM charges the gas fee of the request
+// from: the sponsor, if the request is sponsored and the chain owner allows
+// the sponsor, otherwise the sender
+func GasFeePayer(ch chain.ChainCore, req isc.OffLedgerRequest) (payer isc.AgentID, err error) {
+	err = optimism.RetryOnStateInvalidated(func() error {
+		return panicutil.CatchPanicReturnError(func() {
+			payer = gasFeePayer(ch.GetStateReader().KVStoreReader(), req)
+		}, coreutil.ErrorStateInvalidated)
+	})
+	return payer, err
+}
+
+// gasFeePayer mirrors the resolution of the gas fee payer in the VM
+func gasFeePayer(chainState kv.KVStoreReader, req isc.OffLedgerRequest) isc.AgentID {
+	sponsored, ok := req.(isc.SponsoredOffLedgerRequest)
+	if !ok {
+		return req.SenderAccount()
+	}
+	sponsor, _ := sponsored.Sponsor()
+	if sponsor == nil || sponsor.Equals(req.SenderAccount()) {
+		return req.SenderAccount()
+	}
+	governanceState := subrealm.NewReadOnly(chainState, kv.Key(governance.Contract.Hname().Bytes()))
+	if !governance.IsGasSponsorAllowed(governanceState, sponsor) {
+		return req.SenderAccount()
+	}
+	return sponsor
+}
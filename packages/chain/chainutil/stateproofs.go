@@ -0,0 +1,16 @@
+package chainutil
+
+import (
+	"github.com/iotaledger/trie.go/models/trie_blake2b"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/vm/viewcontext"
+)
+
+func GetStateProofs(ch chain.ChainCore, keys [][]byte) (blockIndex uint32, values [][]byte, proofs []*trie_blake2b.Proof, err error) {
+	err = optimism.RetryOnStateInvalidated(func() (err error) {
+		blockIndex, values, proofs, err = viewcontext.New(ch).GetStateProofs(keys)
+		return err
+	})
+	return blockIndex, values, proofs, err
+}
@@ -14,6 +14,7 @@ import (
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
@@ -95,6 +96,7 @@ func (c *consensus) proposeBatchIfNeeded() {
 
 	c.log.Infof("proposeBatch: proposed batch len = %d, ACS session ID: %d, state index: %d, timestamp: %v",
 		len(reqs), c.acsSessionID, c.stateOutput.GetStateIndex(), proposal.TimeData)
+	c.requestLifecycle.Record(lifecycle.StageProposed, 0, "", proposal.RequestIDs...)
 	c.workflow.setBatchProposalSent()
 }
 
@@ -162,6 +164,11 @@ func (c *consensus) runVMIfNeeded() { // nolint:funlen
 		"estimate gas mode", vmTask.EstimateGasMode,
 		"state commitment", state.RootCommitment(vmTask.VirtualStateAccess.TrieNodeStore()),
 	)
+	if c.consensusDecision != nil {
+		c.consensusDecision.Requests = reqs
+		c.consensusJournal.DecisionReached(c.consensusDecision)
+		c.consensusDecision = nil
+	}
 	c.workflow.setVMStarted()
 	c.consensusMetrics.CountVMRuns()
 	go func() {
@@ -170,6 +177,9 @@ func (c *consensus) runVMIfNeeded() { // nolint:funlen
 			c.log.Errorf("runVM result: VM task failed: %v", err)
 			return
 		}
+		for _, skipped := range vmTask.SkippedRequests {
+			c.requestLifecycle.Record(lifecycle.StageSkipped, 0, skipped.Reason.Error(), skipped.Request.ID())
+		}
 		finalRequestsCount := len(vmTask.Results)
 		if finalRequestsCount == 0 {
 			c.log.Debugf("runVM result: no requests included, ignoring the result and restarting the workflow")
@@ -590,6 +600,14 @@ func (c *consensus) receiveACS(values [][]byte, sessionID uint64, logIndex journ
 		DSSNonceIndexProposal: nil, // Not needed in the final batch proposal.
 	}
 	c.consensusEntropy = par.entropy
+	c.consensusDecision = &journal.Decision{
+		LogIndex:           logIndex,
+		BaseAliasOutput:    c.stateOutput,
+		Proposals:          values,
+		TimeAssumption:     par.timeData,
+		Entropy:            par.entropy,
+		ValidatorFeeTarget: par.feeDestination,
+	}
 
 	c.iAmContributor = iAmContributor
 	c.myContributionSeqNumber = myContributionSeqNumber
@@ -634,6 +652,7 @@ func (c *consensus) processTxInclusionState(msg *messages.TxInclusionStateMsg) {
 	case "conflicting":
 		c.workflow.setTransactionSeen()
 		c.log.Infof("processTxInclusionState: transaction id %s is conflicting; restarting consensus.", finalTxIDStr)
+		c.requestLifecycle.Record(lifecycle.StageMempool, 0, "block transaction is conflicting on L1", c.resultRequestIDs...)
 		c.resetWorkflow()
 	default:
 		c.log.Warnf("processTxInclusionState: unknown inclusion state %s for transaction id %s; ignoring", msg.State, finalTxIDStr)
@@ -781,6 +800,7 @@ func (c *consensus) resetWorkflowNoCheck() {
 
 	c.acsSessionID++
 	c.resultState = nil
+	c.resultRequestIDs = nil
 	c.resultTxEssence = nil
 	c.finalTx = nil
 	c.consensusBatch = nil
@@ -813,6 +833,8 @@ func (c *consensus) processVMResult(result *vm.VMTask) {
 		c.assert.Requiref(result.ResultTransactionEssence != nil, "processVMResult: result.ResultTransactionEssence != nil")
 		c.resultTxEssence = result.ResultTransactionEssence
 		c.resultState = result.VirtualStateAccess
+		c.resultRequestIDs = result.GetProcessedRequestIDs()
+		c.requestLifecycle.Record(lifecycle.StageProcessed, c.resultState.BlockIndex(), "", c.resultRequestIDs...)
 	}
 
 	signingMsg, err := c.resultTxEssence.SigningMessage()
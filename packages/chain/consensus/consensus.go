@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
 	dss_node "github.com/iotaledger/wasp/packages/chain/dss/node"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	mempool_pkg "github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -23,7 +24,6 @@ import (
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util/pipe"
 	"github.com/iotaledger/wasp/packages/vm"
-	"github.com/iotaledger/wasp/packages/vm/runvm"
 	"go.uber.org/atomic"
 )
 
@@ -42,6 +42,7 @@ type consensus struct {
 	acsSessionID                     uint64
 	consensusBatch                   *BatchProposal
 	consensusEntropy                 hashing.HashValue
+	consensusDecision                *journal.Decision // Recorded in the journal, when the VM is started.
 	iAmContributor                   bool
 	myContributionSeqNumber          uint16
 	contributors                     []uint16
@@ -51,6 +52,7 @@ type consensus struct {
 	delaySendingSignedResult         time.Time
 	resultTxEssence                  *iotago.TransactionEssence
 	resultState                      state.VirtualStateAccess
+	resultRequestIDs                 []isc.RequestID
 	finalTx                          *iotago.Transaction
 	postTxDeadline                   time.Time
 	pullInclusionStateDeadline       time.Time
@@ -80,6 +82,7 @@ type consensus struct {
 	consensusJournal                 journal.ConsensusJournal
 	consensusJournalLogIndex         journal.LogIndex // Index of the currently running log index.
 	wal                              chain.WAL
+	requestLifecycle                 *lifecycle.Tracker
 }
 
 var _ chain.Consensus = &consensus{}
@@ -101,6 +104,8 @@ func New(
 	dssNode dss_node.DSSNode,
 	consensusJournal journal.ConsensusJournal,
 	wal chain.WAL,
+	requestLifecycle *lifecycle.Tracker,
+	vmRunner vm.VMRunner,
 	timersOpt ...ConsensusTimers,
 ) chain.Consensus {
 	var timers ConsensusTimers
@@ -116,7 +121,7 @@ func New(
 		committeePeerGroup:               peerGroup,
 		mempool:                          mempool,
 		nodeConn:                         nodeConn,
-		vmRunner:                         runvm.NewVMRunner(),
+		vmRunner:                         vmRunner,
 		workflow:                         newWorkflowStatus(false),
 		timers:                           timers,
 		log:                              log,
@@ -134,6 +139,7 @@ func New(
 		dssNode:                          dssNode,
 		consensusJournal:                 consensusJournal,
 		wal:                              wal,
+		requestLifecycle:                 requestLifecycle,
 	}
 	ret.receivePeerMessagesAttachID = ret.committeePeerGroup.Attach(peering.PeerMessageReceiverConsensus, ret.receiveCommitteePeerMessages) // TODO: Don't need to attach here at all.
 	ret.nodeConn.AttachToMilestones(func(milestonePointer *nodeclient.MilestoneInfo) {
@@ -0,0 +1,140 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Here we implement the record of a consensus decision. It is not needed for
+// the consensus itself, it is kept to inspect and replay the consensus log of
+// a node, e.g. when a chain stalls.
+
+package journal
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// DecisionsToKeep is the number of the latest decisions, kept in the journal.
+const DecisionsToKeep = 1000
+
+// Decision records the outcome of the consensus at a particular log index:
+// the batches proposed to the ACS and the inputs of the VM, derived from them.
+type Decision struct {
+	LogIndex           LogIndex
+	BaseAliasOutput    *isc.AliasOutputWithID // The alias output the batch is built on.
+	Proposals          [][]byte               // Serialized batch proposals, as decided by the ACS.
+	TimeAssumption     time.Time
+	Entropy            hashing.HashValue
+	ValidatorFeeTarget isc.AgentID
+	Requests           []isc.Request // The batch, in the order it is run by the VM.
+}
+
+func NewDecisionFromBytes(data []byte) (*Decision, error) {
+	r := bytes.NewBuffer(data)
+	d := &Decision{}
+	var li uint32
+	if err := util.ReadUint32(r, &li); err != nil {
+		return nil, err
+	}
+	d.LogIndex = LogIndex(li)
+	var oid iotago.OutputID
+	if _, err := r.Read(oid[:]); err != nil {
+		return nil, err
+	}
+	aoBytes, err := util.ReadBytes32(r)
+	if err != nil {
+		return nil, err
+	}
+	ao := &iotago.AliasOutput{}
+	if _, err := ao.Deserialize(aoBytes, serializer.DeSeriModeNoValidation, nil); err != nil {
+		return nil, xerrors.Errorf("cannot deserialize the alias output: %w", err)
+	}
+	d.BaseAliasOutput = isc.NewAliasOutputWithID(ao, oid.UTXOInput())
+	var proposalCount uint16
+	if err := util.ReadUint16(r, &proposalCount); err != nil {
+		return nil, err
+	}
+	d.Proposals = make([][]byte, proposalCount)
+	for i := range d.Proposals {
+		if d.Proposals[i], err = util.ReadBytes32(r); err != nil {
+			return nil, err
+		}
+	}
+	if err := util.ReadTime(r, &d.TimeAssumption); err != nil {
+		return nil, err
+	}
+	if err := util.ReadHashValue(r, &d.Entropy); err != nil {
+		return nil, err
+	}
+	agentIDBytes, err := util.ReadBytes16(r)
+	if err != nil {
+		return nil, err
+	}
+	if d.ValidatorFeeTarget, err = isc.AgentIDFromBytes(agentIDBytes); err != nil {
+		return nil, xerrors.Errorf("cannot deserialize the validator fee target: %w", err)
+	}
+	var requestCount uint16
+	if err := util.ReadUint16(r, &requestCount); err != nil {
+		return nil, err
+	}
+	d.Requests = make([]isc.Request, requestCount)
+	for i := range d.Requests {
+		reqBytes, err := util.ReadBytes32(r)
+		if err != nil {
+			return nil, err
+		}
+		if d.Requests[i], err = isc.NewRequestFromBytes(reqBytes); err != nil {
+			return nil, xerrors.Errorf("cannot deserialize request %v: %w", i, err)
+		}
+	}
+	return d, nil
+}
+
+func (d *Decision) AsBytes() ([]byte, error) {
+	w := bytes.NewBuffer([]byte{})
+	if err := util.WriteUint32(w, d.LogIndex.AsUint32()); err != nil {
+		return nil, err
+	}
+	oid := d.BaseAliasOutput.OutputID()
+	if _, err := w.Write(oid[:]); err != nil {
+		return nil, err
+	}
+	aoBytes, err := d.BaseAliasOutput.GetAliasOutput().Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot serialize the alias output: %w", err)
+	}
+	if err := util.WriteBytes32(w, aoBytes); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint16(w, uint16(len(d.Proposals))); err != nil {
+		return nil, err
+	}
+	for _, p := range d.Proposals {
+		if err := util.WriteBytes32(w, p); err != nil {
+			return nil, err
+		}
+	}
+	if err := util.WriteTime(w, d.TimeAssumption); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(d.Entropy[:]); err != nil {
+		return nil, err
+	}
+	if err := util.WriteBytes16(w, d.ValidatorFeeTarget.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint16(w, uint16(len(d.Requests))); err != nil {
+		return nil, err
+	}
+	for _, req := range d.Requests {
+		if err := util.WriteBytes32(w, req.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return w.Bytes(), nil
+}
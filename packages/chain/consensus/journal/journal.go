@@ -74,6 +74,11 @@ type ConsensusJournal interface {
 	// Notify, when a particular log index has been completed (decided).
 	ConsensusReached(logIndex LogIndex)
 	//
+	// Records the inputs of the VM, decided at a particular log index.
+	// The decisions are kept for inspection and replay only, they are
+	// not used when recovering the consensus.
+	DecisionReached(decision *Decision)
+	//
 	// Upon reception of F+1 Log indexes higher that ours, we have to move
 	// to the next log index That's to catch up with the chain, if a consensus
 	// has failed in some way for us (e.g. maybe this node was down or restarted).
@@ -93,6 +98,8 @@ type Registry interface {
 	LoadConsensusJournal(id ID) (LogIndex, LocalView, error) // Can return ErrConsensusJournalNotFound
 	SaveConsensusJournalLogIndex(id ID, logIndex LogIndex) error
 	SaveConsensusJournalLocalView(id ID, localView LocalView) error
+	SaveConsensusJournalDecision(id ID, decision *Decision) error
+	PruneConsensusJournalDecisions(id ID, before LogIndex) error // Removes the decision with log index before-1, the older ones are pruned already.
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -178,6 +185,20 @@ func (j *consensusJournalImpl) ConsensusReached(logIndex LogIndex) {
 	}
 }
 
+// Implements the ConsensusJournal interface.
+func (j *consensusJournalImpl) DecisionReached(decision *Decision) {
+	if err := j.registry.SaveConsensusJournalDecision(j.id, decision); err != nil {
+		j.log.Warnf("Cannot store the decision for logIndex=%v: %v", decision.LogIndex, err)
+		return
+	}
+	if decision.LogIndex.AsUint32() < DecisionsToKeep {
+		return
+	}
+	if err := j.registry.PruneConsensusJournalDecisions(j.id, decision.LogIndex-DecisionsToKeep+1); err != nil {
+		j.log.Warnf("Cannot prune the decisions before logIndex=%v: %v", decision.LogIndex, err)
+	}
+}
+
 // Implements the ConsensusJournal interface.
 func (j *consensusJournalImpl) PeerLogIndexReceived(peerIndex uint16, peerLogIndex LogIndex) bool {
 	//
@@ -16,7 +16,7 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/peering"
-	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testchain"
@@ -35,6 +35,7 @@ type MockedEnv struct {
 	Nodes            []*mockedNode
 	NodeIDs          []string
 	NodeKeyPairs     []*cryptolib.KeyPair
+	NodeSigners      []signer.Signer
 	NetworkProviders []peering.NetworkProvider
 	NetworkBehaviour *testutil.PeeringNetDynamic
 	DKShares         []tcrypto.DKShare
@@ -79,11 +80,10 @@ func newMockedEnv(t *testing.T, n, quorum uint16, debug, mockACS bool) *MockedEn
 	log.Infof("running DKG and setting up mocked network..")
 	ret.NodeIDs, ret.NodeKeyPairs = testpeers.SetupKeys(n)
 	var err error
-	var dksRegistries []registry.DKShareRegistryProvider
-	ret.StateAddress, dksRegistries = testpeers.SetupDkgPregenerated(t, quorum, ret.NodeKeyPairs)
-	ret.DKShares = make([]tcrypto.DKShare, len(dksRegistries))
-	for i := range dksRegistries {
-		ret.DKShares[i], err = dksRegistries[i].LoadDKShare(ret.StateAddress)
+	ret.StateAddress, ret.NodeSigners = testpeers.SetupDkgPregenerated(t, quorum, ret.NodeKeyPairs)
+	ret.DKShares = make([]tcrypto.DKShare, len(ret.NodeSigners))
+	for i := range ret.NodeSigners {
+		ret.DKShares[i], err = ret.NodeSigners[i].LoadDKShare(ret.StateAddress)
 		require.NoError(t, err)
 	}
 	ret.NetworkProviders, _ = testpeers.SetupNet(ret.NodeIDs, ret.NodeKeyPairs, ret.NetworkBehaviour, log)
@@ -11,6 +11,7 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/committee"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
 	dss_node "github.com/iotaledger/wasp/packages/chain/dss/node"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/chain/nodeconnchain"
@@ -95,13 +96,12 @@ func NewNode(env *MockedEnv, nodeIndex uint16, timers ConsensusTimers) *mockedNo
 	require.NoError(env.T, err)
 	var peeringID peering.PeeringID
 	copy(peeringID[:], env.ChainID[:])
-	dss := dss_node.New(&peeringID, env.NetworkProviders[nodeIndex], ret.NodeKeyPair, log)
+	dss := dss_node.New(&peeringID, env.NetworkProviders[nodeIndex], env.NodeSigners[nodeIndex], log)
 	cmtN := int(cmt.Size())
 	cmtF := cmtN - int(cmt.Quorum())
 	registry, err := journal.LoadConsensusJournal(*env.ChainID, cmt.Address(), testchain.NewMockedConsensusJournalRegistry(), cmtN, cmtF, log)
 	require.NoError(env.T, err)
-	cons := New(ret.ChainCore, ret.Mempool, cmt, cmtPeerGroup, chainNodeConn, true, metrics.DefaultChainMetrics(), dss, registry, wal.NewDefault(), timers)
-	cons.(*consensus).vmRunner = testchain.NewMockedVMRunner(env.T, log)
+	cons := New(ret.ChainCore, ret.Mempool, cmt, cmtPeerGroup, chainNodeConn, true, metrics.DefaultChainMetrics(), dss, registry, wal.NewDefault(), lifecycle.New(lifecycle.DefaultMaxRequests), testchain.NewMockedVMRunner(env.T, log), timers)
 	ret.Consensus = cons
 
 	ret.doStateApproved(originState, env.InitStateOutput)
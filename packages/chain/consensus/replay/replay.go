@@ -0,0 +1,176 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replay re-runs the VM over a batch, recorded in the consensus journal
+// of a node. It is used to investigate stalled chains: the replay shows, if the
+// batch is processed deterministically, i.e. if it produces the same state
+// commitment as the block, produced by the committee.
+package replay
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
+	"golang.org/x/xerrors"
+)
+
+// RawBlock is a block, written to the debug.rawblocksDirectory by a node.
+type RawBlock struct {
+	Block           state.Block
+	StateCommitment string // State commitment after the block, as recorded in the file name.
+	FileName        string
+}
+
+// Result of the replay of a single decision.
+type Result struct {
+	BlockIndex         uint32
+	StateCommitment    trie.VCommitment // Produced by the replay.
+	ExpectedCommitment trie.VCommitment // Produced by the committee, nil if the block is not known.
+	Results            []*vm.RequestResult
+}
+
+// Matches returns true, if the replay produced the state commitment of the recorded block.
+func (r *Result) Matches() bool {
+	return r.ExpectedCommitment != nil && state.EqualCommitments(r.StateCommitment, r.ExpectedCommitment)
+}
+
+// LoadBlocks reads all the blocks, saved in the chain database.
+func LoadBlocks(store kvstore.KVStore) (map[uint32]state.Block, error) {
+	var indexes []uint32
+	err := state.ForEachBlockIndex(store, func(blockIndex uint32) bool {
+		indexes = append(indexes, blockIndex)
+		return true
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list the blocks: %w", err)
+	}
+	blocks := make(map[uint32]state.Block, len(indexes))
+	for _, blockIndex := range indexes {
+		block, err := state.LoadBlock(store, blockIndex)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot load block %v: %w", blockIndex, err)
+		}
+		blocks[blockIndex] = block
+	}
+	return blocks, nil
+}
+
+// LoadRawBlocks reads the blocks from the raw blocks directory of a chain.
+// The files are named <blockIndex>.<stateCommitment>.<blockHash>.mut.
+func LoadRawBlocks(dir string) (map[uint32]*RawBlock, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read the raw blocks directory: %w", err)
+	}
+	blocks := make(map[uint32]*RawBlock)
+	for _, entry := range entries {
+		parts := strings.Split(entry.Name(), ".")
+		if entry.IsDir() || len(parts) != 4 || parts[3] != "mut" {
+			continue
+		}
+		blockIndex, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid block index in %v: %w", entry.Name(), err)
+		}
+		data, err := os.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read %v: %w", entry.Name(), err)
+		}
+		block, err := state.BlockFromBytes(data)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot decode %v: %w", entry.Name(), err)
+		}
+		if block.BlockIndex() != uint32(blockIndex) {
+			return nil, xerrors.Errorf("block %v is stored in %v", block.BlockIndex(), entry.Name())
+		}
+		blocks[uint32(blockIndex)] = &RawBlock{
+			Block:           block,
+			StateCommitment: parts[1],
+			FileName:        entry.Name(),
+		}
+	}
+	return blocks, nil
+}
+
+// Decision runs the VM over the batch of the decision. The state to run it on is
+// reconstructed from the origin by applying the blocks up to the base alias output
+// of the decision. The block, following it, is used to get the expected commitment.
+func Decision(
+	chainID *isc.ChainID,
+	blocks map[uint32]state.Block,
+	decision *journal.Decision,
+	procConfig *processors.Config,
+	log *logger.Logger,
+) (*Result, error) {
+	baseIndex := decision.BaseAliasOutput.GetStateIndex()
+	vs, err := state.CreateOriginState(mapdb.NewMapDB(), chainID)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create the origin state: %w", err)
+	}
+	for i := uint32(1); i <= baseIndex; i++ {
+		block, ok := blocks[i]
+		if !ok {
+			return nil, xerrors.Errorf("block %v is missing", i)
+		}
+		if err := vs.ApplyBlock(block); err != nil {
+			return nil, xerrors.Errorf("cannot apply block %v: %w", i, err)
+		}
+		if err := vs.Save(block); err != nil {
+			return nil, xerrors.Errorf("cannot save block %v: %w", i, err)
+		}
+	}
+	result := &Result{BlockIndex: baseIndex + 1}
+	if nextBlock, ok := blocks[baseIndex+1]; ok {
+		nextState := vs.Copy()
+		if err := nextState.ApplyBlock(nextBlock); err != nil {
+			return nil, xerrors.Errorf("cannot apply block %v: %w", baseIndex+1, err)
+		}
+		nextState.Commit()
+		result.ExpectedCommitment = state.RootCommitment(nextState.TrieNodeStore())
+	}
+	task := &vm.VMTask{
+		Processors:             processors.MustNew(procConfig),
+		AnchorOutput:           decision.BaseAliasOutput.GetAliasOutput(),
+		AnchorOutputID:         decision.BaseAliasOutput.OutputID(),
+		SolidStateBaseline:     coreutil.NewChainStateSync().SetSolidIndex(baseIndex).GetSolidIndexBaseline(),
+		Entropy:                decision.Entropy,
+		ValidatorFeeTarget:     decision.ValidatorFeeTarget,
+		Requests:               decision.Requests,
+		TimeAssumption:         decision.TimeAssumption,
+		VirtualStateAccess:     vs.Copy(),
+		Log:                    log,
+		MaintenanceModeEnabled: maintenanceModeEnabled(vs.KVStore()),
+	}
+	if err := runvm.NewVMRunner().Run(task); err != nil {
+		return nil, xerrors.Errorf("VM failed: %w", err)
+	}
+	result.StateCommitment = state.RootCommitment(task.VirtualStateAccess.TrieNodeStore())
+	result.Results = task.Results
+	return result, nil
+}
+
+// maintenanceModeEnabled reads the maintenance status the same way, as the consensus does.
+func maintenanceModeEnabled(store kv.KVStore) bool {
+	r := subrealm.New(store, kv.Key(governance.Contract.Hname().Bytes())).MustGet(governance.VarMaintenanceStatus)
+	if r == nil {
+		return false
+	}
+	return codec.MustDecodeBool(r)
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package replay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain/consensus/replay"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/testutil/testcluster"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/coreprocessors"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayJournal(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the in-process cluster test in the short mode")
+	}
+	log := testlogger.WithLevel(testlogger.NewLogger(t), logger.LevelInfo, false)
+	defer log.Sync()
+	c := testcluster.New(t, &testcluster.Config{N: 4, T: 3, Log: log})
+
+	user := cryptolib.NewKeyPair()
+	require.NoError(t, c.L1.GetFundsFromFaucet(user.Address()))
+	for i := 0; i < 3; i++ {
+		reqID, err := c.PostRequest(user, &isc.SendMetadata{
+			TargetContract: accounts.Contract.Hname(),
+			EntryPoint:     accounts.FuncDeposit.Hname(),
+			GasBudget:      gas.MaxGasPerCall,
+		}, isc.NewFungibleBaseTokens(1*isc.Million))
+		require.NoError(t, err)
+		require.NoError(t, c.AwaitRequestProcessed(reqID, 60*time.Second))
+	}
+	c.KillNode(0)
+	node := c.Nodes[0]
+
+	journals, err := registry.ConsensusJournals(node.RegistryDB())
+	require.NoError(t, err)
+	require.Len(t, journals, 1)
+	blocks, err := replay.LoadBlocks(node.ChainDB())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(blocks), 4)
+
+	rawBlocksDir := t.TempDir()
+	saveRawBlock := state.SaveRawBlockClosure(rawBlocksDir, log)
+	replayed := map[uint32]bool{}
+	for id := range journals {
+		decisions, err := registry.ConsensusJournalDecisions(node.RegistryDB(), id)
+		require.NoError(t, err)
+		for _, decision := range decisions {
+			result, err := replay.Decision(c.ChainID, blocks, decision, coreprocessors.Config(), log)
+			require.NoError(t, err)
+			if result.Matches() {
+				replayed[result.BlockIndex] = true
+				saveRawBlock(result.StateCommitment, blocks[result.BlockIndex])
+			}
+		}
+	}
+	for blockIndex := range blocks {
+		require.True(t, replayed[blockIndex], "block %v was not reproduced by the replay", blockIndex)
+	}
+
+	rawBlocks, err := replay.LoadRawBlocks(rawBlocksDir)
+	require.NoError(t, err)
+	require.Len(t, rawBlocks, len(blocks))
+	for blockIndex, rawBlock := range rawBlocks {
+		require.Equal(t, blocks[blockIndex].Bytes(), rawBlock.Block.Bytes())
+	}
+}
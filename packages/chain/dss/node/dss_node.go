@@ -23,11 +23,10 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/logger"
-	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/gpa"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
-	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
@@ -45,11 +44,10 @@ type recvMsg struct {
 
 type dssNodeImpl struct {
 	lock      *sync.RWMutex
-	suite     suites.Suite
 	net       peering.NetworkProvider
 	netAttach interface{}
 	peeringID *peering.PeeringID
-	nid       *cryptolib.KeyPair
+	signer    signer.Signer                 // Hosts the DSS instances.
 	series    map[string]*dssSeriesImpl     // Particular protocol instances.
 	seriesBuf map[string]map[int][]*recvMsg // Messages received for future DSS instances.
 	seriesLog []string                      // Ordered list of series started (for cleanup).
@@ -62,15 +60,14 @@ var _ DSSNode = &dssNodeImpl{}
 
 const maxSeriesHist = 10
 
-func New(peeringID *peering.PeeringID, net peering.NetworkProvider, nid *cryptolib.KeyPair, log *logger.Logger) DSSNode {
+func New(peeringID *peering.PeeringID, net peering.NetworkProvider, nodeSigner signer.Signer, log *logger.Logger) DSSNode {
 	ctx, ctcCancel := context.WithCancel(context.Background())
 	n := &dssNodeImpl{
 		lock:      &sync.RWMutex{},
-		suite:     tcrypto.DefaultEd25519Suite(),
 		net:       net,
 		netAttach: nil, // Set bellow.
 		peeringID: peeringID,
-		nid:       nid,
+		signer:    nodeSigner,
 		series:    map[string]*dssSeriesImpl{},
 		seriesBuf: map[string]map[int][]*recvMsg{},
 		seriesLog: []string{},
@@ -135,6 +132,7 @@ func (n *dssNodeImpl) cleanupOldSeries() {
 				}
 			}
 			if !found {
+				n.series[key].close()
 				delete(n.series, key)
 			}
 		}
@@ -158,6 +156,11 @@ func (n *dssNodeImpl) StatusString(key string, index int) string {
 
 func (n *dssNodeImpl) Close() {
 	n.ctxCancel()
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for key := range n.series {
+		n.series[key].close()
+	}
 }
 
 func (n *dssNodeImpl) recv(key string, index int, msgData []byte, sender gpa.NodeID) {
@@ -6,10 +6,12 @@ package node_test
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
-	"github.com/iotaledger/hive.go/crypto/bls"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
 	iotago "github.com/iotaledger/iota.go/v3"
 	dss_node "github.com/iotaledger/wasp/packages/chain/dss/node"
@@ -17,7 +19,9 @@ import (
 	"github.com/iotaledger/wasp/packages/gpa"
 	"github.com/iotaledger/wasp/packages/gpa/adkg"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/keystore"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
@@ -26,10 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/share"
-	"go.dedis.ch/kyber/v3/sign/dss"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
-	"go.dedis.ch/kyber/v3/sign/tbls"
-	"golang.org/x/xerrors"
 )
 
 const ( // HT = High Threshold, LT = Low Threshold.
@@ -39,14 +40,15 @@ const ( // HT = High Threshold, LT = Low Threshold.
 )
 
 func TestBasic(t *testing.T) {
-	t.Run("n=4,f=1,reliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypePregeneratedHT) })
-	t.Run("n=4,f=1,reliable,dkgTypeRobustLT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypeRobustLT) })
-	t.Run("n=4,f=1,reliable,dkgTypeTrivialHT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypeTrivialHT) })
-	t.Run("n=4,f=1,unreliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 4, 1, false, dkgTypePregeneratedHT) })
-	t.Run("n=10,f=3,unreliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 10, 3, false, dkgTypePregeneratedHT) })
+	t.Run("n=4,f=1,reliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypePregeneratedHT, false) })
+	t.Run("n=4,f=1,reliable,dkgTypeRobustLT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypeRobustLT, false) })
+	t.Run("n=4,f=1,reliable,dkgTypeTrivialHT", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypeTrivialHT, false) })
+	t.Run("n=4,f=1,unreliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 4, 1, false, dkgTypePregeneratedHT, false) })
+	t.Run("n=10,f=3,unreliable,dkgTypePregeneratedHT", func(tt *testing.T) { testGeneric(tt, 10, 3, false, dkgTypePregeneratedHT, false) })
+	t.Run("n=4,f=1,reliable,dkgTypePregeneratedHT,remote", func(tt *testing.T) { testGeneric(tt, 4, 1, true, dkgTypePregeneratedHT, true) })
 }
 
-func testGeneric(t *testing.T, n, f int, reliable bool, dkgType byte) {
+func testGeneric(t *testing.T, n, f int, reliable bool, dkgType byte, remote bool) {
 	log := testlogger.NewLogger(t)
 	defer log.Sync()
 	//
@@ -71,17 +73,30 @@ func testGeneric(t *testing.T, n, f int, reliable bool, dkgType byte) {
 	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
 	peeringID := peering.RandomPeeringID()
 	//
+	// Generate the long term key, the shares are kept by the signers of the nodes.
+	address, signers := longTermDKG(dkgType, t, peerIdentities, f, log)
+	if remote {
+		for i := range signers {
+			signers[i] = remoteSigner(t, signers[i])
+		}
+	}
+	dkShares := make([]tcrypto.DKShare, len(signers))
+	for i := range signers {
+		var err error
+		dkShares[i], err = signers[i].LoadDKShare(address)
+		require.NoError(t, err)
+	}
+	//
 	// Initialize the DSS subsystem in each node / chain.
 	dssNodes := make([]dss_node.DSSNode, len(peerIdentities))
 	for i := range peerIdentities {
-		dssNodes[i] = dss_node.New(&peeringID, networkProviders[i], peerIdentities[i], log.Named(fmt.Sprintf("dssNode#%v", i)))
+		dssNodes[i] = dss_node.New(&peeringID, networkProviders[i], signers[i], log.Named(fmt.Sprintf("dssNode#%v", i)))
 	}
 	defer func() {
 		for _, n := range dssNodes {
 			n.Close()
 		}
 	}()
-	dkShares := longTermDKG(dkgType, t, peerIdentities, f, log)
 	//
 	//	Start the DSS instances.
 	key := hashing.HashData([]byte{1, 2, 3}).String()
@@ -122,39 +137,40 @@ func testGeneric(t *testing.T, n, f int, reliable bool, dkgType byte) {
 	}
 }
 
-func longTermDKG(dkgType byte, t *testing.T, peerIdentities []*cryptolib.KeyPair, f int, log *logger.Logger) []tcrypto.DKShare {
+// remoteSigner serves the signer over a unix socket and connects to it,
+// as if it was running in a separate process.
+func remoteSigner(t *testing.T, localSigner signer.Signer) signer.Signer {
+	socket := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- signer.Serve(listener, localSigner, &signer.ServeConfig{}) }()
+	remote, err := signer.NewRemote(socket, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, remote.Close())
+		require.NoError(t, listener.Close())
+		require.NoError(t, <-served)
+	})
+	return remote
+}
+
+func longTermDKG(dkgType byte, t *testing.T, peerIdentities []*cryptolib.KeyPair, f int, log *logger.Logger) (iotago.Address, []signer.Signer) {
 	switch dkgType {
 	case dkgTypePregeneratedHT:
-		return longTermDKGPregeneratedHT(t, peerIdentities, f)
+		return testpeers.SetupDkgPregenerated(t, uint16(len(peerIdentities)-f), peerIdentities)
 	case dkgTypeRobustLT:
 		return longTermDKGRobustLT(t, peerIdentities, f, log)
 	case dkgTypeTrivialHT:
-		return longTermDKGTrivialHT(peerIdentities, f)
+		return longTermDKGTrivialHT(t, peerIdentities, f)
 	}
 	panic("unknown dkg type")
 }
 
-func longTermDKGPregeneratedHT(t *testing.T, peerIdentities []*cryptolib.KeyPair, f int) []tcrypto.DKShare {
-	n := len(peerIdentities)
-	dkShares := make([]tcrypto.DKShare, len(peerIdentities))
-	address, dkSharesRegProviders := testpeers.SetupDkgPregenerated(t, uint16(n-f), peerIdentities)
-	for i := range peerIdentities {
-		dkShare, err := dkSharesRegProviders[i].LoadDKShare(address)
-		require.NoError(t, err)
-		dkShares[i] = dkShare
-	}
-	return dkShares
-}
-
-func longTermDKGRobustLT(t *testing.T, peerIdentities []*cryptolib.KeyPair, f int, log *logger.Logger) []tcrypto.DKShare {
-	dkShares := make([]tcrypto.DKShare, len(peerIdentities))
+func longTermDKGRobustLT(t *testing.T, peerIdentities []*cryptolib.KeyPair, f int, log *logger.Logger) (iotago.Address, []signer.Signer) {
 	nodeIDs := make([]gpa.NodeID, len(peerIdentities))
 	nodePKs := map[gpa.NodeID]kyber.Point{}
 	nodeSKs := map[gpa.NodeID]kyber.Scalar{}
-	peerPubKeys := make([]*cryptolib.PublicKey, len(peerIdentities))
-	for i := range peerPubKeys {
-		peerPubKeys[i] = peerIdentities[i].GetPublicKey()
-	}
 	for i := range nodeIDs {
 		kyberEdDSSA := eddsa.EdDSA{}
 		nodeIDs[i] = gpa.NodeID(peerIdentities[i].GetPublicKey().String())
@@ -162,159 +178,60 @@ func longTermDKGRobustLT(t *testing.T, peerIdentities []*cryptolib.KeyPair, f in
 		nodePKs[nodeIDs[i]] = kyberEdDSSA.Public
 		nodeSKs[nodeIDs[i]] = kyberEdDSSA.Secret
 	}
-	longTermPK, longTermSecretShares := adkg.MakeTestDistributedKey(t, tcrypto.DefaultEd25519Suite(), nodeIDs, nodeSKs, nodePKs, f, log)
-	for i := range dkShares {
-		dkShares[i] = &fakeDKShare{nodePubKeys: peerPubKeys, index: uint16(i), dssSecretShare: longTermSecretShares[nodeIDs[i]], dssSharedPublic: longTermPK}
+	_, longTermSecretShares := adkg.MakeTestDistributedKey(t, tcrypto.DefaultEd25519Suite(), nodeIDs, nodeSKs, nodePKs, f, log)
+	priShares := make([]*share.PriShare, len(nodeIDs))
+	for i := range nodeIDs {
+		priShares[i] = longTermSecretShares[nodeIDs[i]].PriShare()
 	}
-	return dkShares
+	return storeDKShares(t, peerIdentities, f, longTermSecretShares[nodeIDs[0]].Commitments(), priShares)
 }
 
-func longTermDKGTrivialHT(peerIdentities []*cryptolib.KeyPair, f int) []tcrypto.DKShare {
+func longTermDKGTrivialHT(t *testing.T, peerIdentities []*cryptolib.KeyPair, f int) (iotago.Address, []signer.Signer) {
 	n := len(peerIdentities)
-	dkShares := make([]tcrypto.DKShare, len(peerIdentities))
-	peerPubKeys := make([]*cryptolib.PublicKey, len(peerIdentities))
-	for i := range peerPubKeys {
-		peerPubKeys[i] = peerIdentities[i].GetPublicKey()
-	}
 	suite := tcrypto.DefaultEd25519Suite()
 	priPoly := share.NewPriPoly(suite, n-f, nil, suite.RandomStream())
-	priShares := priPoly.Shares(len(peerIdentities))
 	_, commits := priPoly.Commit(suite.Point().Base()).Info()
-	pubKey := commits[0]
-	for i := range dkShares {
-		secretShare := &fakeSecretShare{priShares[i], commits}
-		dkShares[i] = &fakeDKShare{nodePubKeys: peerPubKeys, index: uint16(i), dssSecretShare: secretShare, dssSharedPublic: pubKey}
+	return storeDKShares(t, peerIdentities, f, commits, priPoly.Shares(n))
+}
+
+// storeDKShares makes the key shares from the DSS key shares generated outside of the DKG,
+// and stores them to the signers of the nodes. The BLS part is not used in these tests.
+func storeDKShares(t *testing.T, peerIdentities []*cryptolib.KeyPair, f int, edCommits []kyber.Point, edPriShares []*share.PriShare) (iotago.Address, []signer.Signer) {
+	n := uint16(len(peerIdentities))
+	edSuite := tcrypto.DefaultEd25519Suite()
+	blsSuite := tcrypto.DefaultBLSSuite()
+	blsPriPoly := share.NewPriPoly(blsSuite.G2(), int(n)-f, nil, blsSuite.RandomStream())
+	_, blsCommits := blsPriPoly.Commit(blsSuite.G2().Point().Base()).Info()
+	blsPriShares := blsPriPoly.Shares(int(n))
+	edPublicShares := tcrypto.ResharedPublicShares(edSuite, edCommits, n)
+	blsPublicShares := tcrypto.ResharedPublicShares(blsSuite.G2(), blsCommits, n)
+	peerPubKeys := testpeers.PublicKeys(peerIdentities)
+	signers := make([]signer.Signer, n)
+	var address iotago.Address
+	for i := range signers {
+		dkShare, err := tcrypto.NewDKShare(
+			uint16(i),                         // Index
+			n,                                 // N
+			n-uint16(f),                       // T
+			peerIdentities[i].GetPrivateKey(), // NodePrivKey
+			peerPubKeys,                       // NodePubKeys
+			edSuite,                           // Ed25519: Suite
+			edCommits[0],                      // Ed25519: SharedPublic
+			edCommits,                         // Ed25519: PublicCommits
+			edPublicShares,                    // Ed25519: PublicShares
+			edPriShares[i].V,                  // Ed25519: PrivateShare
+			blsSuite,                          // BLS: Suite
+			blsCommits[0],                     // BLS: SharedPublic
+			blsCommits,                        // BLS: PublicCommits
+			blsPublicShares,                   // BLS: PublicShares
+			blsPriShares[i].V,                 // BLS: PrivateShare
+		)
+		require.NoError(t, err)
+		keyStore := keystore.NewDBKeyStore(mapdb.NewMapDB())
+		require.NoError(t, signer.StoreDKShare(keyStore, dkShare))
+		signers[i], err = signer.NewLocalWithIdentity(keyStore, peerIdentities[i], testlogger.NewSilentLogger(t.Name(), true))
+		require.NoError(t, err)
+		address = dkShare.GetAddress()
 	}
-	return dkShares
-}
-
-type fakeDKShare struct {
-	nodePubKeys     []*cryptolib.PublicKey
-	index           uint16
-	dssSecretShare  tcrypto.SecretShare
-	dssSharedPublic kyber.Point
-}
-
-var _ tcrypto.DKShare = &fakeDKShare{}
-
-func (f *fakeDKShare) Bytes() []byte {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) GetAddress() iotago.Address {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) GetIndex() *uint16 {
-	return &f.index
-}
-
-func (f *fakeDKShare) GetN() uint16 {
-	return uint16(len(f.nodePubKeys))
-}
-
-func (f *fakeDKShare) GetT() uint16 {
-	cmtN := f.GetN()
-	cmtF := (cmtN - 1) / 3
-	cmtT := cmtN - cmtF
-	return cmtT
-}
-
-func (f *fakeDKShare) GetNodePubKeys() []*cryptolib.PublicKey {
-	return f.nodePubKeys
-}
-
-func (f *fakeDKShare) GetSharedPublic() *cryptolib.PublicKey {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) SetPublicShares(edPublicShares, blsPublicShares []kyber.Point) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSPublicShares() []kyber.Point {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSSharedPublic() kyber.Point {
-	return f.dssSharedPublic
-}
-
-func (f *fakeDKShare) DSSSignShare(data []byte, nonce tcrypto.SecretShare) (*dss.PartialSig, error) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSVerifySigShare(data []byte, sigShare *dss.PartialSig) error {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSRecoverMasterSignature(sigShares []*dss.PartialSig, data []byte, nonce tcrypto.SecretShare) ([]byte, error) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSVerifyMasterSignature(data, signature []byte) error {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) DSSSecretShare() tcrypto.SecretShare {
-	return f.dssSecretShare
-}
-
-func (f *fakeDKShare) BLSSharedPublic() kyber.Point {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSPublicShares() []kyber.Point {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSSignShare(data []byte) (tbls.SigShare, error) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSVerifySigShare(data []byte, sigShare tbls.SigShare) error {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSRecoverMasterSignature(sigShares [][]byte, data []byte) (*bls.SignatureWithPublicKey, error) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSVerifyMasterSignature(data, signature []byte) error {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSSign(data []byte) ([]byte, error) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) BLSVerify(signer kyber.Point, data, signature []byte) error {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) AssignNodePubKeys(nodePubKeys []*cryptolib.PublicKey) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) AssignCommonData(dks tcrypto.DKShare) {
-	panic(xerrors.New("not important"))
-}
-
-func (f *fakeDKShare) ClearCommonData() {
-	panic(xerrors.New("not important"))
-}
-
-type fakeSecretShare struct {
-	priShare    *share.PriShare
-	commitments []kyber.Point
-}
-
-var _ tcrypto.SecretShare = &fakeSecretShare{}
-
-func (f *fakeSecretShare) PriShare() *share.PriShare {
-	return f.priShare
-}
-
-func (f *fakeSecretShare) Commitments() []kyber.Point {
-	return f.commitments
+	return address, signers
 }
@@ -0,0 +1,94 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain/dss"
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/signer"
+)
+
+// dssProxy represents the DSS instance hosted by the signer as a GPA, so that
+// the messages to and from it can be delivered reliably using the AckHandler.
+// The node only relays the messages, it cannot read nor sign them.
+type dssProxy struct {
+	inst   signer.DSS
+	output *dss.Output
+	log    *logger.Logger
+}
+
+var _ gpa.GPA = &dssProxy{}
+
+func newDSSProxy(inst signer.DSS, log *logger.Logger) *dssProxy {
+	return &dssProxy{inst: inst, log: log}
+}
+
+func (p *dssProxy) Input(input gpa.Input) gpa.OutMessages {
+	return p.handleResult(p.inst.Input())
+}
+
+func (p *dssProxy) Message(msg gpa.Message) gpa.OutMessages {
+	msgT, ok := msg.(*msgDSSProxy)
+	if !ok {
+		p.log.Warnf("Unexpected message type: %T", msg)
+		return nil
+	}
+	return p.handleResult(p.inst.Message(msgT.sender, msgT.data))
+}
+
+func (p *dssProxy) decided(decidedIndexProposals map[gpa.NodeID][]int, messageToSign []byte) gpa.OutMessages {
+	return p.handleResult(p.inst.Decided(decidedIndexProposals, messageToSign))
+}
+
+func (p *dssProxy) handleResult(result *signer.DSSResult, err error) gpa.OutMessages {
+	if err != nil {
+		p.log.Warnf("DSS instance of the signer failed: %v", err)
+		return nil
+	}
+	msgs := gpa.NoMessages()
+	for _, m := range result.Messages {
+		msgs.Add(&msgDSSProxy{recipient: m.Recipient, data: m.Data})
+	}
+	if result.Output != nil {
+		p.output = result.Output
+	}
+	return msgs
+}
+
+func (p *dssProxy) Output() gpa.Output {
+	if p.output == nil {
+		return nil
+	}
+	return p.output
+}
+
+func (p *dssProxy) StatusString() string {
+	return "{DSS, hosted by the signer}"
+}
+
+func (p *dssProxy) UnmarshalMessage(data []byte) (gpa.Message, error) {
+	return &msgDSSProxy{data: data}, nil
+}
+
+// msgDSSProxy carries a message of the DSS instance, as sealed by the signer.
+type msgDSSProxy struct {
+	recipient gpa.NodeID
+	sender    gpa.NodeID
+	data      []byte
+}
+
+var _ gpa.Message = &msgDSSProxy{}
+
+func (m *msgDSSProxy) Recipient() gpa.NodeID {
+	return m.recipient
+}
+
+func (m *msgDSSProxy) SetSender(sender gpa.NodeID) {
+	m.sender = sender
+}
+
+func (m *msgDSSProxy) MarshalBinary() ([]byte, error) {
+	return m.data, nil
+}
@@ -10,9 +10,8 @@ import (
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/gpa"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
-	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"golang.org/x/xerrors"
 )
 
@@ -21,7 +20,8 @@ const (
 )
 
 type dssInstance struct {
-	inst      dss.DSS
+	inst      signer.DSS
+	proxy     *dssProxy
 	asGPA     gpa.AckHandler
 	hadInput  bool
 	outPart   []int
@@ -80,16 +80,11 @@ func (s *dssSeriesImpl) start(index int, partCB func([]int), sigCB func([]byte))
 	//
 	// Create new instance, if not yet created.
 	if _, ok := s.dssInsts[index]; !ok {
-		var dssInst dss.DSS
-		var dssAsGPA gpa.AckHandler
-		if dssInst, dssAsGPA, err = s.newDSSImpl(); err != nil {
+		var dssInst *dssInstance
+		if dssInst, err = s.newDSSImpl(index); err != nil {
 			return err
 		}
-		s.dssInsts[index] = &dssInstance{
-			inst:     dssInst,
-			asGPA:    dssAsGPA,
-			hadInput: false,
-		}
+		s.dssInsts[index] = dssInst
 	}
 	//
 	// Assign (or reassign) the output callback, invoke callbacks, if data is pending.
@@ -111,49 +106,20 @@ func (s *dssSeriesImpl) start(index int, partCB func([]int), sigCB func([]byte))
 	return nil
 }
 
-func (s *dssSeriesImpl) newDSSImpl() (dss.DSS, gpa.AckHandler, error) {
-	nodePKs := s.dkShare.GetNodePubKeys()
-	nodeIDs := make([]gpa.NodeID, len(nodePKs))
-	for i := range nodeIDs {
-		nodeIDs[i] = pubKeyAsNodeID(nodePKs[i])
-	}
-	myPK := nodePKs[*s.dkShare.GetIndex()]
-	mySK := s.node.nid.GetPrivateKey()
-	me := pubKeyAsNodeID(myPK)
-	//
-	// CryptoLib -> Kyber.
-	kyberEdDSSA := eddsa.EdDSA{}
-	if err := kyberEdDSSA.UnmarshalBinary(mySK.AsBytes()); err != nil {
-		return nil, nil, err
-	}
-	kyberNodePKs := make(map[gpa.NodeID]kyber.Point, len(nodePKs))
-	for i, nid := range nodeIDs {
-		kyberNodePKs[nid] = s.node.suite.Point()
-		if err := kyberNodePKs[nid].UnmarshalBinary(nodePKs[i].AsBytes()); err != nil {
-			return nil, nil, err
-		}
+// newDSSImpl asks the signer to host a DSS instance, this node only relays its messages.
+func (s *dssSeriesImpl) newDSSImpl(index int) (*dssInstance, error) {
+	s.node.log.Debugf("Constructing DSS instance, CommitteeAddress=%v, key=%v, index=%v", s.dkShare.GetAddress(), s.key, index)
+	inst, err := s.node.signer.NewDSS(s.dkShare.GetAddress(), s.key, index)
+	if err != nil {
+		return nil, err
 	}
-	//
-	// Construct the DSS protocol instance.
-	n := len(nodeIDs)
-	f := n - int(s.dkShare.GetT())
-	s.node.log.Debugf("Constructing DSS instance, CommitteeAddress=%v, n=%v, f=%v, nodeIDs=%v", s.dkShare.DSSSharedPublic(), n, f, nodeIDs)
-	d := dss.New(
-		tcrypto.DefaultEd25519Suite(), // suite
-		nodeIDs,                       // nodeIDs
-		kyberNodePKs,                  // nodePKs
-		f,                             // f
-		me,                            // me
-		kyberEdDSSA.Secret,            // mySK
-		s.dkShare.DSSSecretShare(),    // longTermSecretShare
-		s.node.log,
-	)
-	dAsGPA := gpa.NewAckHandler(
-		me,            // me
-		d.AsGPA(),     // nested
+	proxy := newDSSProxy(inst, s.node.log)
+	asGPA := gpa.NewAckHandler(
+		pubKeyAsNodeID(s.node.signer.GetPublicKey()), // me
+		proxy,         // nested
 		1*time.Second, // resendPeriod
 	)
-	return d, dAsGPA, nil
+	return &dssInstance{inst: inst, proxy: proxy, asGPA: asGPA}, nil
 }
 
 func (s *dssSeriesImpl) sendMessages(msgs gpa.OutMessages, index int) {
@@ -182,8 +148,7 @@ func (s *dssSeriesImpl) recvMessage(index int, msgData []byte, sender gpa.NodeID
 	var err error
 	dssInst, ok := s.dssInsts[index]
 	if !ok {
-		dssInst = &dssInstance{}
-		dssInst.inst, dssInst.asGPA, err = s.newDSSImpl()
+		dssInst, err = s.newDSSImpl(index)
 		if err != nil {
 			s.node.log.Errorf("Cannot create DSS instance: %v", err)
 			return
@@ -230,13 +195,23 @@ func (s *dssSeriesImpl) decidedIndexProposals(index int, decidedIndexProposals [
 				mappedIndexProposals[s.peerNIDs[i]] = decidedIndexProposals[i]
 			}
 		}
-		s.sendMessages(dssInst.asGPA.NestedMessage(dssInst.inst.NewMsgDecided(mappedIndexProposals, messageToSign)), index)
+		s.sendMessages(dssInst.asGPA.NestedCall(func(gpa.GPA) gpa.OutMessages {
+			return dssInst.proxy.decided(mappedIndexProposals, messageToSign)
+		}), index)
 		s.tryReportOutput(dssInst)
 		return nil
 	}
 	return xerrors.Errorf("DSS instance for index=%v not found", index)
 }
 
+func (s *dssSeriesImpl) close() {
+	for index, dssInst := range s.dssInsts {
+		if err := dssInst.inst.Close(); err != nil {
+			s.node.log.Warnf("Cannot close DSS instance key=%v, index=%v: %v", s.key, index, err)
+		}
+	}
+}
+
 func (s *dssSeriesImpl) statusString(index int) string {
 	return s.dssInsts[index].asGPA.StatusString()
 }
@@ -0,0 +1,153 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lifecycle tracks the stages a request passes through on the node,
+// from its arrival to the mempool until its block is confirmed on L1.
+package lifecycle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/wasp/packages/isc"
+)
+
+type Stage string
+
+const (
+	// StageUnknown the node does not know the request
+	StageUnknown = Stage("unknown")
+	// StageMempool the request is in the mempool, waiting to be proposed
+	StageMempool = Stage("mempool")
+	// StageProposed the request is in a batch proposed by the node to the consensus
+	StageProposed = Stage("proposed")
+	// StageSkipped the VM skipped the request, it stays in the mempool. The reason is in the event
+	StageSkipped = Stage("skipped")
+	// StageProcessed the request is in a block produced by the committee, waiting for the L1 confirmation
+	StageProcessed = Stage("processed")
+	// StageConfirmed the block with the request is confirmed on L1
+	StageConfirmed = Stage("confirmed")
+)
+
+const (
+	// DefaultMaxRequests is the number of requests tracked by default, the oldest ones are forgotten
+	DefaultMaxRequests = 10000
+	// maxHistoryConst limits the events kept per request, a request may be skipped many times
+	maxHistoryConst = 32
+)
+
+type Event struct {
+	RequestID  isc.RequestID
+	Stage      Stage
+	Time       time.Time
+	BlockIndex uint32 // block of the request, in StageProcessed and StageConfirmed
+	Reason     string // why the request was skipped, or returned to the mempool
+}
+
+type Status struct {
+	RequestID isc.RequestID
+	Stage     Stage
+	History   []*Event
+}
+
+// Tracker keeps the stages of the most recent requests of a chain. A nil
+// Tracker records nothing.
+type Tracker struct {
+	mutex       sync.Mutex
+	statuses    map[isc.RequestID]*Status
+	order       []isc.RequestID // in the order of arrival, to forget the oldest ones
+	maxRequests int
+	event       *events.Event
+}
+
+func New(maxRequests int) *Tracker {
+	return &Tracker{
+		statuses:    make(map[isc.RequestID]*Status),
+		maxRequests: maxRequests,
+		event: events.NewEvent(func(handler interface{}, params ...interface{}) {
+			handler.(func(*Event))(params[0].(*Event))
+		}),
+	}
+}
+
+// Record records the stage of the requests and notifies the subscribers. The
+// events of a confirmed request are ignored.
+func (t *Tracker) Record(stage Stage, blockIndex uint32, reason string, reqIDs ...isc.RequestID) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	recorded := make([]*Event, 0, len(reqIDs))
+	t.mutex.Lock()
+	for _, reqID := range reqIDs {
+		if ev := t.recordOne(&Event{RequestID: reqID, Stage: stage, Time: now, BlockIndex: blockIndex, Reason: reason}); ev != nil {
+			recorded = append(recorded, ev)
+		}
+	}
+	t.mutex.Unlock()
+
+	for _, ev := range recorded {
+		t.event.Trigger(ev)
+	}
+}
+
+func (t *Tracker) recordOne(ev *Event) *Event {
+	status, ok := t.statuses[ev.RequestID]
+	if !ok {
+		status = &Status{RequestID: ev.RequestID}
+		t.statuses[ev.RequestID] = status
+		t.order = append(t.order, ev.RequestID)
+		for len(t.statuses) > t.maxRequests {
+			delete(t.statuses, t.order[0])
+			t.order = t.order[1:]
+		}
+	}
+	if status.Stage == StageConfirmed {
+		return nil
+	}
+	// the request is received again from the peers or L1, while it is already tracked
+	if ev.Stage == StageMempool && status.Stage != "" && ev.Reason == "" {
+		return nil
+	}
+	status.Stage = ev.Stage
+	status.History = append(status.History, ev)
+	if len(status.History) > maxHistoryConst {
+		status.History = status.History[len(status.History)-maxHistoryConst:]
+	}
+	return ev
+}
+
+// Get returns the stage and the history of the request
+func (t *Tracker) Get(reqID isc.RequestID) *Status {
+	if t == nil {
+		return &Status{RequestID: reqID, Stage: StageUnknown}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status, ok := t.statuses[reqID]
+	if !ok {
+		return &Status{RequestID: reqID, Stage: StageUnknown}
+	}
+	return &Status{
+		RequestID: status.RequestID,
+		Stage:     status.Stage,
+		History:   append([]*Event(nil), status.History...),
+	}
+}
+
+// Attach subscribes to the events of all requests. The handler must not block.
+func (t *Tracker) Attach(handler func(*Event)) *events.Closure {
+	closure := events.NewClosure(handler)
+	if t != nil {
+		t.event.Attach(closure)
+	}
+	return closure
+}
+
+func (t *Tracker) Detach(closure *events.Closure) {
+	if t != nil {
+		t.event.Detach(closure)
+	}
+}
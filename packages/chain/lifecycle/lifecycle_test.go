@@ -0,0 +1,67 @@
+package lifecycle
+
+import (
+	"testing"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/stretchr/testify/require"
+)
+
+func reqID(i int) isc.RequestID {
+	return isc.NewRequestID(iotago.TransactionID{byte(i)}, 0)
+}
+
+func TestLifecycle(t *testing.T) {
+	tr := New(DefaultMaxRequests)
+	var received []*Event
+	closure := tr.Attach(func(ev *Event) {
+		received = append(received, ev)
+	})
+
+	require.Equal(t, StageUnknown, tr.Get(reqID(1)).Stage)
+
+	tr.Record(StageMempool, 0, "", reqID(1), reqID(2))
+	tr.Record(StageProposed, 0, "", reqID(1))
+	tr.Record(StageSkipped, 0, "nonce too high", reqID(1))
+	tr.Record(StageMempool, 0, "", reqID(1)) // received again, ignored
+	tr.Record(StageProposed, 0, "", reqID(1))
+	tr.Record(StageProcessed, 5, "", reqID(1))
+	tr.Record(StageConfirmed, 5, "", reqID(1))
+	tr.Record(StageMempool, 0, "", reqID(1)) // already confirmed, ignored
+
+	status := tr.Get(reqID(1))
+	require.Equal(t, StageConfirmed, status.Stage)
+	stages := make([]Stage, len(status.History))
+	for i, ev := range status.History {
+		stages[i] = ev.Stage
+	}
+	require.Equal(t, []Stage{StageMempool, StageProposed, StageSkipped, StageProposed, StageProcessed, StageConfirmed}, stages)
+	require.Equal(t, "nonce too high", status.History[2].Reason)
+	require.EqualValues(t, 5, status.History[5].BlockIndex)
+	require.Equal(t, StageMempool, tr.Get(reqID(2)).Stage)
+	require.Len(t, received, 7)
+
+	tr.Detach(closure)
+	tr.Record(StageProposed, 0, "", reqID(2))
+	require.Len(t, received, 7)
+}
+
+func TestLifecycleLimits(t *testing.T) {
+	tr := New(3)
+	for i := 0; i < 5; i++ {
+		tr.Record(StageMempool, 0, "", reqID(i))
+	}
+	require.Equal(t, StageUnknown, tr.Get(reqID(0)).Stage)
+	require.Equal(t, StageUnknown, tr.Get(reqID(1)).Stage)
+	require.Equal(t, StageMempool, tr.Get(reqID(4)).Stage)
+
+	for i := 0; i < 2*maxHistoryConst; i++ {
+		tr.Record(StageSkipped, 0, "skipped", reqID(4))
+	}
+	require.Len(t, tr.Get(reqID(4)).History, maxHistoryConst)
+
+	var nilTracker *Tracker
+	nilTracker.Record(StageMempool, 0, "", reqID(1))
+	require.Equal(t, StageUnknown, nilTracker.Get(reqID(1)).Stage)
+}
@@ -0,0 +1,56 @@
+package mempool
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// evmNonceCache keeps the nonces of the Ethereum accounts read from the state, so that each nonce is read
+// once per state. The cache is reset when the state changes
+type evmNonceCache struct {
+	stateIndex func() (uint32, error)
+	readNonce  func(common.Address) (uint64, error)
+
+	mutex      sync.Mutex
+	blockIndex uint32
+	cache      map[common.Address]uint64
+}
+
+func newEVMNonceCache(stateIndex func() (uint32, error), readNonce func(common.Address) (uint64, error)) *evmNonceCache {
+	return &evmNonceCache{
+		stateIndex: stateIndex,
+		readNonce:  readNonce,
+		cache:      make(map[common.Address]uint64),
+	}
+}
+
+// nonces returns the function reading the nonces of the accounts in the current state
+func (c *evmNonceCache) nonces() func(common.Address) (uint64, error) {
+	blockIndex, err := c.stateIndex()
+	if err != nil {
+		// may be invalidated state
+		return func(common.Address) (uint64, error) { return 0, err }
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if blockIndex != c.blockIndex {
+		c.blockIndex = blockIndex
+		c.cache = make(map[common.Address]uint64)
+	}
+	return func(addr common.Address) (uint64, error) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if nonce, ok := c.cache[addr]; ok {
+			return nonce, nil
+		}
+		nonce, err := c.readNonce(addr)
+		if err != nil {
+			return 0, err
+		}
+		if c.blockIndex == blockIndex {
+			c.cache[addr] = nonce
+		}
+		return nonce, nil
+	}
+}
@@ -14,6 +14,7 @@ type Mempool interface {
 	ReadyFromIDs(currentTime time.Time, reqIDs ...isc.RequestID) ([]isc.Request, []int, bool)
 	HasRequest(id isc.RequestID) bool
 	GetRequest(id isc.RequestID) isc.Request
+	Requests() []isc.Request
 	Info(currentTime time.Time) MempoolInfo
 	WaitRequestInPool(reqid isc.RequestID, timeout ...time.Duration) bool // for testing
 	WaitInBufferEmpty(timeout ...time.Duration) bool                      // for testing
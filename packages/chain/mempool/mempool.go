@@ -9,13 +9,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/hive.go/logger"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/rotate"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
 )
 
 type mempool struct {
@@ -28,6 +34,7 @@ type mempool struct {
 	inPoolCounter      int
 	outPoolCounter     int
 	isRequestProcessed func(*isc.RequestID) (bool, error)
+	evmNonces          *evmNonceCache
 	pool               map[isc.RequestID]*requestRef
 	chStop             chan struct{}
 	log                *logger.Logger
@@ -43,6 +50,9 @@ type requestRef struct {
 
 const (
 	moveToPoolLoopDelay = 20 * time.Millisecond
+	// evmTransactionAheadTimeout is how long an Ethereum transaction with a nonce ahead of the nonce of its sender
+	// waits in the pool for the transactions with the previous nonces, before it is removed
+	evmTransactionAheadTimeout = 10 * time.Minute
 )
 
 func New(
@@ -61,12 +71,24 @@ func New(
 		}
 		return ret, nil
 	}
-	return newMempool(chainAddress, isRequestProcessed, log, mempoolMetrics)
+	stateIndex := func() (uint32, error) {
+		stateReader.SetBaseline()
+		return stateReader.BlockIndex()
+	}
+	evmNonce := func(addr common.Address) (ret uint64, err error) {
+		err = panicutil.CatchPanic(func() {
+			stateReader.SetBaseline()
+			ret = evmimpl.GetNonce(subrealm.NewReadOnly(stateReader.KVStoreReader(), kv.Key(evm.Contract.Hname().Bytes())), addr)
+		})
+		return ret, err
+	}
+	return newMempool(chainAddress, isRequestProcessed, newEVMNonceCache(stateIndex, evmNonce), log, mempoolMetrics)
 }
 
 func newMempool(
 	chainAddress iotago.Address,
 	isRequestProcessed func(*isc.RequestID) (bool, error),
+	evmNonces *evmNonceCache,
 	log *logger.Logger,
 	mempoolMetrics metrics.MempoolMetrics,
 ) Mempool {
@@ -74,6 +96,7 @@ func newMempool(
 		chainAddress:       chainAddress,
 		inBuffer:           make(map[isc.RequestID]isc.Request),
 		isRequestProcessed: isRequestProcessed,
+		evmNonces:          evmNonces,
 		pool:               make(map[isc.RequestID]*requestRef),
 		chStop:             make(chan struct{}),
 		log:                log.Named("mempool"),
@@ -257,6 +280,22 @@ func (m *mempool) isRequestReady(ref *requestRef, currentTime time.Time) (isRead
 	return isc.RequestIsUnlockable(onLedgerReq, m.chainAddress, currentTime), false
 }
 
+// isEVMTransactionAhead checks if the request is an Ethereum transaction with a nonce ahead of the nonce of
+// its sender, it must wait in the pool until the transactions with the previous nonces are processed.
+// The VM skips such transactions anyway, this only avoids proposing them
+func (m *mempool) isEVMTransactionAhead(req isc.Request, nonces func(common.Address) (uint64, error)) bool {
+	tx, ok := isc.EVMTransaction(req)
+	if !ok {
+		return false
+	}
+	nonce, err := nonces(req.SenderAccount().(*isc.EthereumAddressAgentID).EthAddress())
+	if err != nil {
+		// may be invalidated state, let the VM decide
+		return false
+	}
+	return tx.Nonce() > nonce
+}
+
 // ReadyNow returns preliminary batch of requests for consensus.
 // Note that later status of request may change due to the time change and time constraints
 // If there's at least one committee rotation request in the mempool, the ReadyNow returns
@@ -270,6 +309,8 @@ func (m *mempool) ReadyNow(currentTime time.Time) []isc.Request {
 	toRemove := []isc.RequestID{}
 
 	ret := make([]isc.Request, 0, len(m.pool))
+	// the Ethereum transactions are checked against the state after the pool is released
+	evmTransactions := []*requestRef{}
 	for _, ref := range m.pool {
 		rdy, shouldBeRemoved := m.isRequestReady(ref, currentTime)
 		if shouldBeRemoved {
@@ -279,6 +320,10 @@ func (m *mempool) ReadyNow(currentTime time.Time) []isc.Request {
 		if !rdy {
 			continue
 		}
+		if _, ok := isc.EVMTransaction(ref.req); ok {
+			evmTransactions = append(evmTransactions, ref)
+			continue
+		}
 		ret = append(ret, ref.req)
 		if !rotate.IsRotateStateControllerRequest(ref.req) {
 			continue
@@ -302,6 +347,18 @@ func (m *mempool) ReadyNow(currentTime time.Time) []isc.Request {
 		}
 	}
 	m.poolMutex.RUnlock()
+
+	nonces := m.evmNonces.nonces()
+	for _, ref := range evmTransactions {
+		if !m.isEVMTransactionAhead(ref.req, nonces) {
+			ret = append(ret, ref.req)
+			continue
+		}
+		if currentTime.Sub(ref.whenReceived) > evmTransactionAheadTimeout {
+			// the transactions with the previous nonces never arrived
+			toRemove = append(toRemove, ref.req.ID())
+		}
+	}
 	go m.RemoveRequests(toRemove...)
 
 	if oldestRotate != nil {
@@ -361,6 +418,26 @@ func (m *mempool) GetRequest(id isc.RequestID) isc.Request {
 	return nil
 }
 
+// Requests returns the requests waiting in the in-buffer and in the pool, in no particular order
+func (m *mempool) Requests() []isc.Request {
+	m.poolMutex.RLock()
+	ret := make([]isc.Request, 0, len(m.pool))
+	for _, ref := range m.pool {
+		ret = append(ret, ref.req)
+	}
+	m.poolMutex.RUnlock()
+
+	m.inMutex.RLock()
+	defer m.inMutex.RUnlock()
+	for reqid, req := range m.inBuffer {
+		// the request may be moving to the pool right now
+		if !m.HasRequest(reqid) {
+			ret = append(ret, req)
+		}
+	}
+	return ret
+}
+
 const waitRequestInPoolTimeoutDefault = 2 * time.Second
 
 // WaitRequestInPool waits until the request appears in the pool but no longer than timeout
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/iota.go/v3/tpkg"
 	"github.com/iotaledger/wasp/packages/cryptolib"
@@ -291,6 +292,26 @@ func TestAddRemoveRequests(t *testing.T) {
 	require.EqualValues(t, 4, mempoolMetrics.processedRequestCounter)
 }
 
+// Test that Requests lists the requests of the pool once
+func TestRequests(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
+	rdr, _ := createStateReader(t, glb)
+	pool := New(chainAddress, rdr, log, new(MockMempoolMetrics))
+	requests := getRequestsOnLedger(t, 3)
+
+	pool.ReceiveRequests(requests[0], requests[1], requests[2])
+	require.True(t, pool.WaitInBufferEmpty())
+	require.Len(t, pool.Requests(), 3)
+
+	pool.RemoveRequests(requests[1].ID())
+	ids := make(map[isc.RequestID]bool)
+	for _, req := range pool.Requests() {
+		ids[req.ID()] = true
+	}
+	require.Equal(t, map[isc.RequestID]bool{requests[0].ID(): true, requests[2].ID(): true}, ids)
+}
+
 // Test if ReadyNow and ReadyFromIDs functions respect the time lock of the request
 func TestTimeLock(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
@@ -617,3 +638,34 @@ func TestRotateRequest(t *testing.T) {
 	require.True(t, result)
 	require.True(t, len(ready) == 5)
 }
+
+// Test that the nonces of the Ethereum accounts are read once per state
+func TestEVMNonceCache(t *testing.T) {
+	blockIndex := uint32(1)
+	reads := 0
+	cache := newEVMNonceCache(
+		func() (uint32, error) { return blockIndex, nil },
+		func(common.Address) (uint64, error) {
+			reads++
+			return uint64(blockIndex), nil
+		},
+	)
+	addr1, addr2 := common.Address{1}, common.Address{2}
+
+	nonces := cache.nonces()
+	for i := 0; i < 3; i++ {
+		n, err := nonces(addr1)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+	}
+	_, err := cache.nonces()(addr2)
+	require.NoError(t, err)
+	require.Equal(t, 2, reads)
+
+	// the state changed
+	blockIndex = 2
+	n, err := cache.nonces()(addr1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	require.Equal(t, 3, reads)
+}
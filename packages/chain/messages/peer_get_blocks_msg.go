@@ -0,0 +1,44 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// GetBlocksMsg StateManager queries a range of blocks from another peer (access node).
+// The peer streams the blocks back as separate BlockMsg messages in increasing index order.
+// StateManager -> StateManager
+type GetBlocksMsg struct {
+	FromIndex uint32
+	ToIndex   uint32
+}
+
+type GetBlocksMsgIn struct {
+	GetBlocksMsg
+	SenderPubKey *cryptolib.PublicKey
+}
+
+func NewGetBlocksMsg(data []byte) (*GetBlocksMsg, error) {
+	msg := &GetBlocksMsg{}
+	r := bytes.NewReader(data)
+	if err := util.ReadUint32(r, &msg.FromIndex); err != nil {
+		return nil, err
+	}
+	if err := util.ReadUint32(r, &msg.ToIndex); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (msg *GetBlocksMsg) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, msg.FromIndex); err != nil {
+		return err
+	}
+	return util.WriteUint32(w, msg.ToIndex)
+}
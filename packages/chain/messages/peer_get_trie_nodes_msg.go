@@ -0,0 +1,67 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// GetTrieNodesMsg StateManager queries nodes of the state trie from another peer
+// (access node) during fast sync. Each node is identified by its key and the
+// expected commitment; the peer responds by a TrieNodesMsg with the nodes it has.
+// StateManager -> StateManager
+type GetTrieNodesMsg struct {
+	Nodes []*state.TrieNodeRef
+}
+
+type GetTrieNodesMsgIn struct {
+	GetTrieNodesMsg
+	SenderPubKey *cryptolib.PublicKey
+}
+
+func NewGetTrieNodesMsg(data []byte) (*GetTrieNodesMsg, error) {
+	msg := &GetTrieNodesMsg{}
+	r := bytes.NewReader(data)
+	var size uint16
+	if err := util.ReadUint16(r, &size); err != nil {
+		return nil, err
+	}
+	msg.Nodes = make([]*state.TrieNodeRef, size)
+	for i := range msg.Nodes {
+		key, err := util.ReadBytes16(r)
+		if err != nil {
+			return nil, err
+		}
+		commitmentBytes, err := util.ReadBytes16(r)
+		if err != nil {
+			return nil, err
+		}
+		commitment, err := state.VCommitmentFromBytes(commitmentBytes)
+		if err != nil {
+			return nil, err
+		}
+		msg.Nodes[i] = &state.TrieNodeRef{Key: key, Commitment: commitment}
+	}
+	return msg, nil
+}
+
+func (msg *GetTrieNodesMsg) Write(w io.Writer) error {
+	if err := util.WriteUint16(w, uint16(len(msg.Nodes))); err != nil {
+		return err
+	}
+	for _, node := range msg.Nodes {
+		if err := util.WriteBytes16(w, node.Key); err != nil {
+			return err
+		}
+		if err := util.WriteBytes16(w, node.Commitment.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// TrieNodesMsg StateManager sends the requested nodes of the state trie
+// together with the values of their terminals.
+// StateManager -> StateManager
+type TrieNodesMsg struct {
+	Nodes []*state.TrieNode
+}
+
+type TrieNodesMsgIn struct {
+	TrieNodesMsg
+	SenderPubKey *cryptolib.PublicKey
+}
+
+func NewTrieNodesMsg(data []byte) (*TrieNodesMsg, error) {
+	msg := &TrieNodesMsg{}
+	r := bytes.NewReader(data)
+	var size uint16
+	if err := util.ReadUint16(r, &size); err != nil {
+		return nil, err
+	}
+	msg.Nodes = make([]*state.TrieNode, size)
+	for i := range msg.Nodes {
+		msg.Nodes[i] = &state.TrieNode{}
+		if err := msg.Nodes[i].Read(r); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+func (msg *TrieNodesMsg) Write(w io.Writer) error {
+	if err := util.WriteUint16(w, uint16(len(msg.Nodes))); err != nil {
+		return err
+	}
+	for _, node := range msg.Nodes {
+		if err := node.Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
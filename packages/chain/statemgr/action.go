@@ -8,6 +8,7 @@ import (
 
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/state"
 )
@@ -103,7 +104,7 @@ func (sm *stateManager) addStateCandidateFromConsensus(nextState state.VirtualSt
 	}
 	block.SetApprovingOutputID(approvingOutputID)
 	sm.syncingBlocks.startSyncingIfNeeded(nextState.BlockIndex())
-	sm.syncingBlocks.addBlockCandidate(block, nextState) // TODO: is it needed? State candidate should have already been put in wal by consensus and retrieved by startSyncingIfNeeded
+	sm.syncingBlocks.addBlockCandidate(block, nextState, nil) // TODO: is it needed? State candidate should have already been put in wal by consensus and retrieved by startSyncingIfNeeded
 	sm.delayRequestBlockRetry(block.BlockIndex())
 
 	if sm.stateOutput == nil || sm.stateOutput.GetStateIndex() < nextState.BlockIndex() {
@@ -123,7 +124,7 @@ func (sm *stateManager) delayRequestBlockRetry(stateIndex uint32) {
 	sm.syncingBlocks.setRequestBlockRetryTime(stateIndex, time.Now().Add(sm.timers.GetBlockRetry))
 }
 
-func (sm *stateManager) addBlockFromPeer(block state.Block) bool {
+func (sm *stateManager) addBlockFromPeer(block state.Block, sender *cryptolib.PublicKey) bool {
 	sm.log.Debugf("addBlockFromPeer: adding block index %v", block.BlockIndex())
 	if !sm.syncingBlocks.isSyncing(block.BlockIndex()) {
 		// not asked
@@ -131,7 +132,7 @@ func (sm *stateManager) addBlockFromPeer(block state.Block) bool {
 		return false
 	}
 
-	sm.syncingBlocks.addBlockCandidate(block, nil)
+	sm.syncingBlocks.addBlockCandidate(block, nil, sender)
 	if !sm.syncingBlocks.hasApprovedBlockCandidate(block.BlockIndex()) { // TODO: make the timer to not spam L1
 		// ask for approving output
 		sm.log.Debugf("addBlockFromPeer: requesting approving output ID %v", isc.OID(block.ApprovingOutputID()))
@@ -162,6 +163,7 @@ func (sm *stateManager) storeSyncingData() {
 		StateOutput:           sm.stateOutput,
 		StateOutputCommitment: outputStateCommitment,
 		StateOutputTimestamp:  sm.stateOutputTimestamp,
+		FastSync:              sm.getFastSyncInfo(),
 	})
 	sm.log.Debugf("storeSyncingData: values stored")
 }
@@ -13,7 +13,7 @@ import (
 )
 
 type approvalInfo struct {
-	outputID            *iotago.UTXOInput
+	outputID            *iotago.UTXOInput // nil, if the approval is derived from the next block
 	nextStateCommitment trie.VCommitment
 	blockHash           state.BlockHash
 }
@@ -30,6 +30,15 @@ func newApprovalInfo(output *isc.AliasOutputWithID) (*approvalInfo, error) {
 	}, nil
 }
 
+// newApprovalInfoFromL1Commitment returns the approval of the index preceding a
+// verified block, taken from the previous L1 commitment of the block
+func newApprovalInfoFromL1Commitment(l1Commitment *state.L1Commitment) *approvalInfo {
+	return &approvalInfo{
+		nextStateCommitment: l1Commitment.StateCommitment,
+		blockHash:           l1Commitment.BlockHash,
+	}
+}
+
 func (aiT *approvalInfo) getNextStateCommitment() trie.VCommitment {
 	return aiT.nextStateCommitment
 }
@@ -39,6 +48,9 @@ func (aiT *approvalInfo) getBlockHash() state.BlockHash {
 }
 
 func (aiT *approvalInfo) String() string {
+	if aiT.outputID == nil {
+		return fmt.Sprintf("next block, next state commitment %s, block hash %s", aiT.nextStateCommitment, aiT.blockHash)
+	}
 	return fmt.Sprintf("output ID: %v, next state commitment %s, block hash %s",
 		isc.OID(aiT.outputID), aiT.nextStateCommitment, aiT.blockHash)
 }
@@ -5,18 +5,21 @@ package statemgr
 
 import (
 	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/state"
 )
 
 type candidateBlock struct {
 	block     state.Block
 	nextState state.VirtualStateAccess
+	sender    *cryptolib.PublicKey // nil, if the block was not received from a peer
 }
 
-func newCandidateBlock(block state.Block, nextStateIfProvided state.VirtualStateAccess) *candidateBlock {
+func newCandidateBlock(block state.Block, nextStateIfProvided state.VirtualStateAccess, sender *cryptolib.PublicKey) *candidateBlock {
 	return &candidateBlock{
 		block:     block,
 		nextState: nextStateIfProvided,
+		sender:    sender,
 	}
 }
 
@@ -24,6 +27,10 @@ func (cT *candidateBlock) getBlock() state.Block {
 	return cT.block
 }
 
+func (cT *candidateBlock) getSender() *cryptolib.PublicKey {
+	return cT.sender
+}
+
 func (cT *candidateBlock) getNextState(currentState state.VirtualStateAccess) (state.VirtualStateAccess, error) {
 	if cT.nextState == nil {
 		err := currentState.ApplyBlock(cT.block)
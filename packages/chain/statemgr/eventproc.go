@@ -47,6 +47,55 @@ func (sm *stateManager) handleGetBlockMsg(msg *messages.GetBlockMsgIn) {
 	sm.domain.SendMsgByPubKey(msg.SenderPubKey, peering.PeerMessageReceiverStateManager, peerMsgTypeBlock, util.MustBytes(blockMsg))
 }
 
+// EventGetBlocksMsg is a request for a range of blocks while syncing
+func (sm *stateManager) EnqueueGetBlocksMsg(msg *messages.GetBlocksMsgIn) {
+	sm.eventGetBlocksMsgPipe.In() <- msg
+}
+
+// handleGetBlocksMsg streams the requested blocks to the peer, one BlockMsg per
+// block, stopping at the first block not found
+func (sm *stateManager) handleGetBlocksMsg(msg *messages.GetBlocksMsgIn) {
+	sm.log.Debugw("handleGetBlocksMsg: ",
+		"sender", msg.SenderPubKey.String(),
+		"from index", msg.FromIndex,
+		"to index", msg.ToIndex,
+	)
+	if sm.stateOutput == nil { // Not a necessary check, only for optimization.
+		sm.log.Debugf("handleGetBlocksMsg: message ignored: stateOutput is nil")
+		return
+	}
+	if msg.FromIndex > msg.ToIndex {
+		sm.log.Warnf("handleGetBlocksMsg ignored: invalid range %v-%v requested by peer %s", msg.FromIndex, msg.ToIndex, msg.SenderPubKey.String())
+		return
+	}
+	if msg.FromIndex > sm.stateOutput.GetStateIndex() { // Not a necessary check, only for optimization.
+		sm.log.Debugf("handleGetBlocksMsg ignored: current state output index #%d is older than requested block index #%d",
+			sm.stateOutput.GetStateIndex(), msg.FromIndex)
+		return
+	}
+	to := msg.ToIndex
+	if to > sm.stateOutput.GetStateIndex() {
+		to = sm.stateOutput.GetStateIndex()
+	}
+	if to-msg.FromIndex >= maxBlocksPerRequestConst {
+		to = msg.FromIndex + maxBlocksPerRequestConst - 1
+	}
+	for i := msg.FromIndex; i <= to; i++ {
+		blockBytes, err := state.LoadBlockBytes(sm.store, i)
+		if err != nil {
+			sm.log.Errorf("handleGetBlocksMsg: LoadBlockBytes error: %v", err)
+			return
+		}
+		if blockBytes == nil {
+			sm.log.Debugf("handleGetBlocksMsg: block index #%d not found", i)
+			return
+		}
+		blockMsg := &messages.BlockMsg{BlockBytes: blockBytes}
+		sm.domain.SendMsgByPubKey(msg.SenderPubKey, peering.PeerMessageReceiverStateManager, peerMsgTypeBlock, util.MustBytes(blockMsg))
+	}
+	sm.log.Debugf("handleGetBlocksMsg: responded to peer %s with blocks %v-%v", msg.SenderPubKey.String(), msg.FromIndex, to)
+}
+
 // EventBlockMsg
 func (sm *stateManager) EnqueueBlockMsg(msg *messages.BlockMsgIn) {
 	sm.eventBlockMsgPipe.In() <- msg
@@ -64,6 +113,7 @@ func (sm *stateManager) handleBlockMsg(msg *messages.BlockMsgIn) {
 	block, err := state.BlockFromBytes(msg.BlockBytes)
 	if err != nil {
 		sm.log.Warnf("handleBlockMsg: message ignored: wrong block received from peer %s. Err: %v", msg.SenderPubKey.String(), err)
+		sm.syncingBlocks.blockInvalid(msg.SenderPubKey)
 		return
 	}
 	sm.log.Debugw("handleBlockMsg: adding block from peer ",
@@ -71,7 +121,75 @@ func (sm *stateManager) handleBlockMsg(msg *messages.BlockMsgIn) {
 		"block index", block.BlockIndex(),
 		"approving output", isc.OID(block.ApprovingOutputID()),
 	)
-	if sm.addBlockFromPeer(block) {
+	if sm.addBlockFromPeer(block, msg.SenderPubKey) {
+		sm.takeAction()
+	}
+}
+
+// EventGetTrieNodesMsg is a request for nodes of the state trie during fast sync
+func (sm *stateManager) EnqueueGetTrieNodesMsg(msg *messages.GetTrieNodesMsgIn) {
+	sm.eventGetTrieNodesMsgPipe.In() <- msg
+}
+
+// handleGetTrieNodesMsg responds with the requested nodes of the solid state.
+// The nodes, which are not committed by the requested commitments anymore, are
+// not sent.
+func (sm *stateManager) handleGetTrieNodesMsg(msg *messages.GetTrieNodesMsgIn) {
+	sm.log.Debugw("handleGetTrieNodesMsg: ",
+		"sender", msg.SenderPubKey.String(),
+		"nodes", len(msg.Nodes),
+	)
+	if sm.fastSync != nil {
+		sm.log.Debugf("handleGetTrieNodesMsg ignored: the state is being fast synced")
+		return
+	}
+	refs := msg.Nodes
+	if len(refs) > maxTrieNodesPerRequestConst {
+		refs = refs[:maxTrieNodesPerRequestConst]
+	}
+	nodes := make([]*state.TrieNode, 0, len(refs))
+	size := 0
+	for _, ref := range refs {
+		node, err := state.LoadTrieNode(sm.store, ref.Key)
+		if err != nil {
+			sm.log.Errorf("handleGetTrieNodesMsg: LoadTrieNode error: %v", err)
+			continue
+		}
+		if node == nil {
+			continue
+		}
+		if _, err := node.Verify(ref.Commitment); err != nil {
+			continue
+		}
+		nodes = append(nodes, node)
+		if size += len(node.Key) + len(node.Bytes) + len(node.Value); size >= maxTrieNodesMsgSizeConst {
+			break
+		}
+	}
+	if len(nodes) == 0 {
+		sm.log.Debugf("handleGetTrieNodesMsg: none of the requested trie nodes found")
+		return
+	}
+	sm.log.Debugf("handleGetTrieNodesMsg: responding to peer %s by %v of %v requested trie nodes", msg.SenderPubKey.String(), len(nodes), len(msg.Nodes))
+	trieNodesMsg := &messages.TrieNodesMsg{Nodes: nodes}
+	sm.domain.SendMsgByPubKey(msg.SenderPubKey, peering.PeerMessageReceiverStateManager, peerMsgTypeTrieNodes, util.MustBytes(trieNodesMsg))
+}
+
+// EventTrieNodesMsg
+func (sm *stateManager) EnqueueTrieNodesMsg(msg *messages.TrieNodesMsgIn) {
+	sm.eventTrieNodesMsgPipe.In() <- msg
+}
+
+func (sm *stateManager) handleTrieNodesMsg(msg *messages.TrieNodesMsgIn) {
+	sm.log.Debugw("handleTrieNodesMsg: ",
+		"sender", msg.SenderPubKey.String(),
+		"nodes", len(msg.Nodes),
+	)
+	if sm.fastSync == nil {
+		sm.log.Debugf("handleTrieNodesMsg: message ignored: the state is not being fast synced")
+		return
+	}
+	if sm.trieNodesReceived(msg.Nodes, msg.SenderPubKey) {
 		sm.takeAction()
 	}
 }
@@ -0,0 +1,318 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package statemgr
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	maxTrieNodesPerRequestConst = 256     // max number of trie nodes requested and served in a single GetTrieNodesMsg
+	maxTrieNodesMsgSizeConst    = 1 << 20 // size of the served trie nodes, after which the TrieNodesMsg is not filled anymore
+	maxMissingTrieNodesConst    = 4096    // max number of trie nodes being requested from the peers at once
+	maxTrieNodesCheckedConst    = 10000   // max number of trie nodes looked up in the DB in one action
+)
+
+// fastSync downloads the state, committed by the target state output, node by
+// node from the peers, instead of applying all the blocks from origin. The trie
+// is traversed depth first from the root. The nodes are looked up in the DB
+// first, so that the nodes already downloaded for a previous target are not
+// requested again. Each received node is verified against the commitment known
+// by its parent before it is stored.
+type fastSync struct {
+	target           *isc.AliasOutputWithID
+	commitment       trie.VCommitment
+	queue            []*state.TrieNodeRef        // nodes to be looked up in the DB or requested
+	missing          map[string]*missingTrieNode // unpacked key -> node being requested from the peers
+	startTime        time.Time
+	lastProgressTime time.Time
+	downloaded       int
+	reused           int
+}
+
+type missingTrieNode struct {
+	ref       *state.TrieNodeRef
+	request   *blockRequest
+	retryTime time.Time
+}
+
+func (sm *stateManager) setFastSyncOptions() {
+	// parameters are not loaded in the context of unit tests
+	if !parameters.IsLoaded() {
+		return
+	}
+	if threshold := parameters.GetInt(parameters.StateManagerFastSyncThreshold); threshold > 0 {
+		sm.fastSyncThreshold = uint32(threshold)
+		sm.log.Infof("fast sync enabled for the chains at least %v blocks ahead", threshold)
+	}
+}
+
+// resumeInterruptedFastSync is called on start, if the state in the DB has been
+// left incomplete by an interrupted fast sync. If fast sync is enabled, the
+// solid state is kept at origin in memory and the download is resumed: the trie
+// nodes already in the DB are reused. Otherwise the incomplete state is deleted
+// and synced again from origin.
+func (sm *stateManager) resumeInterruptedFastSync() (bool, error) {
+	inProgress, err := state.IsFastSyncInProgress(sm.store)
+	if err != nil || !inProgress {
+		return false, err
+	}
+	if sm.fastSyncThreshold == 0 {
+		sm.log.Warnf("fast sync is disabled: state left incomplete by an interrupted fast sync is deleted")
+		if err := state.DeleteState(sm.store); err != nil {
+			return false, xerrors.Errorf("failed to delete incomplete state: %w", err)
+		}
+		return false, state.SetFastSyncInProgress(sm.store, false)
+	}
+	originState, err := state.CreateOriginState(mapdb.NewMapDB(), sm.chain.ID())
+	if err != nil {
+		return false, xerrors.Errorf("failed to create origin state: %w", err)
+	}
+	sm.log.Infof("state was left incomplete by an interrupted fast sync, resuming it")
+	sm.chain.GlobalStateSync().InvalidateSolidIndex()
+	sm.solidState = originState
+	sm.fastSyncIncomplete = true
+	return true, nil
+}
+
+// isFastSyncNeeded returns true, if fast sync is enabled, nothing but the
+// origin state is in the DB, and the chain is far enough ahead, or the state
+// in the DB is left incomplete by a previous fast sync
+func (sm *stateManager) isFastSyncNeeded() bool {
+	return sm.fastSyncThreshold > 0 &&
+		sm.solidState.BlockIndex() == 0 &&
+		(sm.fastSyncIncomplete || sm.stateOutput.GetStateIndex() >= sm.fastSyncThreshold)
+}
+
+func (sm *stateManager) doFastSyncAction() {
+	now := time.Now()
+	if sm.fastSync == nil {
+		if !sm.startFastSync(now) {
+			return
+		}
+	}
+	if now.Sub(sm.fastSync.lastProgressTime) > sm.timers.FastSyncRetarget &&
+		sm.stateOutput.GetStateIndex() > sm.fastSync.target.GetStateIndex() {
+		// the peers do not serve the nodes of the target anymore, as their state has moved on
+		sm.log.Infof("doFastSyncAction: no progress in %v, switching the target from state index %v to %v",
+			now.Sub(sm.fastSync.lastProgressTime), sm.fastSync.target.GetStateIndex(), sm.stateOutput.GetStateIndex())
+		if !sm.setFastSyncTarget(now) {
+			return
+		}
+	}
+	sm.checkTrieNodes()
+	if len(sm.fastSync.queue) == 0 && len(sm.fastSync.missing) == 0 {
+		sm.completeFastSync()
+		return
+	}
+	if !sm.domain.HaveMainPeers() {
+		sm.domain.SetFallbackMode(true)
+	}
+	sm.requestMissingTrieNodes(now)
+}
+
+// startFastSync starts downloading the state of the current state output. The
+// solid state is kept at origin in memory, until the download is completed. The
+// trie nodes in the DB are not deleted: the ones committed by the downloaded
+// state are reused, the rest is pruned when the download is completed.
+func (sm *stateManager) startFastSync(now time.Time) bool {
+	sm.log.Infof("startFastSync: solid state is at origin, state output is at index %v, starting fast sync", sm.stateOutput.GetStateIndex())
+	sm.chain.GlobalStateSync().InvalidateSolidIndex()
+	originState, err := state.CreateOriginState(mapdb.NewMapDB(), sm.chain.ID())
+	if err != nil {
+		sm.log.Errorf("startFastSync: failed to create origin state: %v", err)
+		return false
+	}
+	if err := state.SetFastSyncInProgress(sm.store, true); err != nil {
+		sm.log.Errorf("startFastSync: failed to mark the state as incomplete: %v", err)
+		return false
+	}
+	sm.fastSyncIncomplete = true
+	sm.solidState = originState
+	sm.syncingBlocks.restartSyncing()
+	sm.fastSync = &fastSync{startTime: now}
+	if !sm.setFastSyncTarget(now) {
+		sm.fastSync = nil
+		return false
+	}
+	return true
+}
+
+// setFastSyncTarget starts the traversal of the trie of the current state output
+func (sm *stateManager) setFastSyncTarget(now time.Time) bool {
+	l1Commitment, err := state.L1CommitmentFromAliasOutput(sm.stateOutput.GetAliasOutput())
+	if err != nil {
+		sm.log.Errorf("setFastSyncTarget: cannot obtain state commitment from state output: %v", err)
+		return false
+	}
+	for _, node := range sm.fastSync.missing {
+		if node.request != nil {
+			sm.syncingBlocks.peerScores.requestCancelled(node.request)
+		}
+	}
+	sm.fastSync.target = sm.stateOutput
+	sm.fastSync.commitment = l1Commitment.StateCommitment
+	sm.fastSync.queue = []*state.TrieNodeRef{{Key: nil, Commitment: l1Commitment.StateCommitment}}
+	sm.fastSync.missing = make(map[string]*missingTrieNode)
+	sm.fastSync.lastProgressTime = now
+	sm.log.Infof("setFastSyncTarget: downloading state index %v, commitment %s", sm.stateOutput.GetStateIndex(), l1Commitment.StateCommitment)
+	return true
+}
+
+// checkTrieNodes takes the nodes from the queue and looks them up in the DB.
+// The children of the nodes found are put to the queue, the nodes not found are
+// marked as missing.
+func (sm *stateManager) checkTrieNodes() {
+	fs := sm.fastSync
+	for checked := 0; checked < maxTrieNodesCheckedConst && len(fs.queue) > 0 && len(fs.missing) < maxMissingTrieNodesConst; checked++ {
+		ref := fs.queue[len(fs.queue)-1]
+		fs.queue = fs.queue[:len(fs.queue)-1]
+		node, err := state.LoadTrieNode(sm.store, ref.Key)
+		if err != nil {
+			sm.log.Debugf("checkTrieNodes: node %x is corrupted in the DB: %v", ref.Key, err)
+		}
+		if node != nil {
+			if children, err := node.Verify(ref.Commitment); err == nil {
+				fs.reused++
+				fs.queue = append(fs.queue, children...)
+				continue
+			}
+		}
+		fs.missing[string(ref.Key)] = &missingTrieNode{ref: ref}
+	}
+}
+
+// requestMissingTrieNodes requests the missing nodes, which are not requested
+// yet or whose request has timed out, in batches from the best scored peers
+func (sm *stateManager) requestMissingTrieNodes(now time.Time) {
+	peers := sm.domain.GetRandomOtherPeers(maxPeersToRequestBlocksFromConst)
+	batch := make([]*missingTrieNode, 0, maxTrieNodesPerRequestConst)
+	send := func() bool {
+		peer := sm.syncingBlocks.selectPeer(peers)
+		if peer == nil {
+			sm.log.Debugf("requestMissingTrieNodes: no peers to request trie nodes from, fallback=%v", sm.domain.GetFallbackMode())
+			return false
+		}
+		// the request of n trie nodes is scored in the same way as a request of n blocks
+		request := &blockRequest{peer: peer, from: 0, to: uint32(len(batch) - 1), sentTime: now}
+		sm.syncingBlocks.peerScores.requestSent(request)
+		msg := &messages.GetTrieNodesMsg{Nodes: make([]*state.TrieNodeRef, len(batch))}
+		for i, node := range batch {
+			if node.request != nil {
+				sm.syncingBlocks.peerScores.requestTimedOut(node.request)
+			}
+			node.request = request
+			node.retryTime = now.Add(sm.timers.GetBlockRetry)
+			msg.Nodes[i] = node.ref
+		}
+		sm.log.Debugf("requestMissingTrieNodes: requesting %v trie nodes from %v", len(batch), peer.String())
+		sm.domain.SendMsgByPubKey(peer, peering.PeerMessageReceiverStateManager, peerMsgTypeGetTrieNodes, util.MustBytes(msg))
+		batch = batch[:0]
+		return true
+	}
+	for _, node := range sm.fastSync.missing {
+		if now.Before(node.retryTime) {
+			continue
+		}
+		batch = append(batch, node)
+		if len(batch) == maxTrieNodesPerRequestConst && !send() {
+			return
+		}
+	}
+	if len(batch) > 0 {
+		send()
+	}
+}
+
+// trieNodesReceived verifies and stores the received nodes, which are missing
+func (sm *stateManager) trieNodesReceived(nodes []*state.TrieNode, sender *cryptolib.PublicKey) bool {
+	fs := sm.fastSync
+	now := time.Now()
+	verified := make([]*state.TrieNode, 0, len(nodes))
+	children := make([][]*state.TrieNodeRef, 0, len(nodes))
+	for _, node := range nodes {
+		missing, ok := fs.missing[string(node.Key)]
+		if !ok {
+			// not requested or already received
+			continue
+		}
+		nodeChildren, err := node.Verify(missing.ref.Commitment)
+		if err != nil {
+			sm.log.Warnf("trieNodesReceived: invalid trie node received from peer %s: %v", sender.String(), err)
+			sm.syncingBlocks.blockInvalid(sender)
+			continue
+		}
+		verified = append(verified, node)
+		children = append(children, nodeChildren)
+	}
+	if len(verified) == 0 {
+		return false
+	}
+	if err := state.SaveTrieNodes(sm.store, verified); err != nil {
+		sm.log.Errorf("trieNodesReceived: failed to store trie nodes: %v", err)
+		return false
+	}
+	for i, node := range verified {
+		missing := fs.missing[string(node.Key)]
+		if missing.request != nil && missing.request.peer.Equals(sender) {
+			sm.syncingBlocks.peerScores.blockReceived(missing.request, now)
+		}
+		delete(fs.missing, string(node.Key))
+		fs.queue = append(fs.queue, children[i]...)
+	}
+	fs.downloaded += len(verified)
+	fs.lastProgressTime = now
+	sm.log.Debugf("trieNodesReceived: %v trie nodes received from %v, downloaded %v, reused %v, missing %v",
+		len(verified), sender.String(), fs.downloaded, fs.reused, len(fs.missing))
+	return true
+}
+
+// completeFastSync loads the downloaded state as the solid state, so that the
+// blocks after it are synced as usual
+func (sm *stateManager) completeFastSync() {
+	fs := sm.fastSync
+	solidState, err := state.CompleteFastSync(sm.store, sm.chain.ID(), fs.commitment)
+	if err == nil && solidState.BlockIndex() != fs.target.GetStateIndex() {
+		err = xerrors.Errorf("downloaded state index %v does not match the target state index %v", solidState.BlockIndex(), fs.target.GetStateIndex())
+	}
+	if err != nil {
+		sm.log.Errorf("completeFastSync: failed to load the downloaded state, restarting fast sync: %v", err)
+		sm.fastSync = nil
+		return
+	}
+	sm.solidState = solidState
+	sm.fastSync = nil
+	sm.fastSyncIncomplete = false
+	sm.syncingBlocks.restartSyncing()
+	sm.setRawBlocksOptions()
+	sm.chain.GlobalStateSync().SetSolidIndex(solidState.BlockIndex())
+	sm.log.Infof("FAST SYNC completed in %v: block index #%d, state commitment: %s, trie nodes downloaded: %v, reused: %v",
+		time.Since(fs.startTime), solidState.BlockIndex(), fs.commitment, fs.downloaded, fs.reused)
+}
+
+func (sm *stateManager) getFastSyncInfo() *chain.FastSyncInfo {
+	if sm.fastSync == nil {
+		return nil
+	}
+	return &chain.FastSyncInfo{
+		TargetBlockIndex:      sm.fastSync.target.GetStateIndex(),
+		TargetStateCommitment: sm.fastSync.commitment,
+		StartTime:             sm.fastSync.startTime,
+		NodesDownloaded:       sm.fastSync.downloaded,
+		NodesReused:           sm.fastSync.reused,
+		NodesPending:          len(sm.fastSync.missing),
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package statemgr
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+)
+
+const (
+	// latency assumed for the peers, which have not delivered any block yet
+	initialPeerLatencyConst = 500 * time.Millisecond
+	// peers, which have sent that many invalid blocks, are asked only if there are no other peers
+	maxInvalidBlocksConst = 3
+	// peers, which have not answered that many range requests at all, are asked for blocks one by one
+	maxUnansweredRangeRequestsConst = 2
+)
+
+// blockRequest is a range of blocks requested from a single peer
+type blockRequest struct {
+	peer     *cryptolib.PublicKey
+	from     uint32
+	to       uint32
+	sentTime time.Time
+	received uint32
+	single   bool // the blocks were requested one by one, not as a range
+	done     bool
+}
+
+type peerScore struct {
+	latency  time.Duration // moving average of the time it takes the peer to deliver a block
+	pending  int           // number of requests sent to the peer and not yet completed
+	timeouts int           // number of consecutive requests not completed in time
+	invalid  int           // number of invalid blocks received from the peer
+
+	rangesAnswered   bool // the peer has delivered blocks of a range request
+	rangesUnanswered int  // number of consecutive range requests, of which no block was delivered
+}
+
+// peerScores keeps track of how well the peers serve block requests, so that
+// the ranges of blocks to be synced are spread among the fastest and most
+// reliable peers.
+type peerScores struct {
+	scores map[cryptolib.PublicKeyKey]*peerScore
+	log    *logger.Logger
+}
+
+func newPeerScores(log *logger.Logger) *peerScores {
+	return &peerScores{
+		scores: make(map[cryptolib.PublicKeyKey]*peerScore),
+		log:    log,
+	}
+}
+
+func (psT *peerScores) get(peer *cryptolib.PublicKey) *peerScore {
+	score, ok := psT.scores[peer.AsKey()]
+	if !ok {
+		score = &peerScore{latency: initialPeerLatencyConst}
+		psT.scores[peer.AsKey()] = score
+	}
+	return score
+}
+
+// cost estimates, how long it would take the peer to serve one more request;
+// the lower, the better
+func (psT *peerScores) cost(peer *cryptolib.PublicKey) time.Duration {
+	score := psT.get(peer)
+	return score.latency * time.Duration((1+score.pending)*(1+score.timeouts))
+}
+
+// selectPeer returns the peer with the lowest cost. Peers, which have sent
+// too many invalid blocks, are selected only if no other peer is available.
+func (psT *peerScores) selectPeer(peers []*cryptolib.PublicKey) *cryptolib.PublicKey {
+	var best *cryptolib.PublicKey
+	var bestCost time.Duration
+	var bestTrusted bool
+	for _, peer := range peers {
+		cost := psT.cost(peer)
+		trusted := psT.get(peer).invalid < maxInvalidBlocksConst
+		if best == nil || (trusted && !bestTrusted) || (trusted == bestTrusted && cost < bestCost) {
+			best, bestCost, bestTrusted = peer, cost, trusted
+		}
+	}
+	return best
+}
+
+func (psT *peerScores) requestSent(request *blockRequest) {
+	psT.get(request.peer).pending++
+}
+
+// blockReceived is called for every block of the request delivered by the
+// requested peer. The latency sample is the average time per delivered block,
+// as the blocks of a request are streamed one after another.
+func (psT *peerScores) blockReceived(request *blockRequest, now time.Time) {
+	if request.done {
+		return
+	}
+	score := psT.get(request.peer)
+	request.received++
+	sample := now.Sub(request.sentTime) / time.Duration(request.received)
+	score.latency = (3*score.latency + sample) / 4
+	score.timeouts = 0
+	if !request.single {
+		score.rangesAnswered = true
+		score.rangesUnanswered = 0
+	}
+	if request.received > request.to-request.from {
+		request.done = true
+		score.pending--
+	}
+}
+
+// requestTimedOut is called, when the request must be repeated, because not all
+// of its blocks were delivered in time
+func (psT *peerScores) requestTimedOut(request *blockRequest) {
+	if request.done {
+		return
+	}
+	request.done = true
+	score := psT.get(request.peer)
+	score.pending--
+	score.timeouts++
+	if !request.single && request.received == 0 && !score.rangesAnswered {
+		score.rangesUnanswered++
+	}
+	psT.log.Debugf("requestTimedOut: peer %s has not delivered blocks %v-%v in time, timeouts: %v",
+		request.peer.String(), request.from, request.to, score.timeouts)
+}
+
+// supportsRanges returns false, if the peer does not seem to answer range
+// requests (e.g., it runs an older version) and must be asked for the blocks
+// one by one
+func (psT *peerScores) supportsRanges(peer *cryptolib.PublicKey) bool {
+	score := psT.get(peer)
+	return score.rangesAnswered || score.rangesUnanswered < maxUnansweredRangeRequestsConst
+}
+
+// requestCancelled is called, when the blocks of the request are not needed anymore
+func (psT *peerScores) requestCancelled(request *blockRequest) {
+	if request.done {
+		return
+	}
+	request.done = true
+	psT.get(request.peer).pending--
+}
+
+func (psT *peerScores) blockInvalid(peer *cryptolib.PublicKey) {
+	score := psT.get(peer)
+	score.invalid++
+	psT.log.Warnf("blockInvalid: peer %s has sent an invalid block, invalid blocks: %v", peer.String(), score.invalid)
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package statemgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScores(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	scores := newPeerScores(log)
+	peer1 := cryptolib.NewKeyPair().GetPublicKey()
+	peer2 := cryptolib.NewKeyPair().GetPublicKey()
+	peers := []*cryptolib.PublicKey{peer1, peer2}
+
+	// pending requests spread the load among the peers
+	require.True(t, scores.selectPeer(peers).Equals(peer1))
+	request1 := &blockRequest{peer: peer1, from: 1, to: 2, sentTime: time.Now()}
+	scores.requestSent(request1)
+	require.True(t, scores.selectPeer(peers).Equals(peer2))
+
+	// completed requests are not pending anymore and lower the latency
+	scores.blockReceived(request1, request1.sentTime.Add(10*time.Millisecond))
+	require.False(t, request1.done)
+	scores.blockReceived(request1, request1.sentTime.Add(20*time.Millisecond))
+	require.True(t, request1.done)
+	require.Less(t, scores.get(peer1).latency, initialPeerLatencyConst)
+	require.True(t, scores.selectPeer(peers).Equals(peer1))
+
+	// timed out requests make the peer less preferable
+	request2 := &blockRequest{peer: peer1, from: 3, to: 3, sentTime: time.Now()}
+	scores.requestSent(request2)
+	scores.requestTimedOut(request2)
+	scores.requestTimedOut(request2)
+	require.EqualValues(t, 0, scores.get(peer1).pending)
+	require.EqualValues(t, 1, scores.get(peer1).timeouts)
+
+	// peers sending invalid blocks are used only if there is no other peer
+	for i := 0; i < maxInvalidBlocksConst; i++ {
+		scores.blockInvalid(peer2)
+	}
+	for i := 0; i < 5; i++ {
+		scores.requestSent(&blockRequest{peer: peer1, from: 4, to: 4, sentTime: time.Now()})
+	}
+	require.True(t, scores.selectPeer(peers).Equals(peer1))
+	require.True(t, scores.selectPeer([]*cryptolib.PublicKey{peer2}).Equals(peer2))
+	require.Nil(t, scores.selectPeer(nil))
+}
+
+func TestPeerScoresRangesUnanswered(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	scores := newPeerScores(log)
+	oldPeer := cryptolib.NewKeyPair().GetPublicKey()
+	newPeer := cryptolib.NewKeyPair().GetPublicKey()
+
+	// a peer, which answers none of the range requests, is asked for the blocks one by one
+	for i := 0; i < maxUnansweredRangeRequestsConst; i++ {
+		require.True(t, scores.supportsRanges(oldPeer))
+		request := &blockRequest{peer: oldPeer, from: 1, to: 10, sentTime: time.Now()}
+		scores.requestSent(request)
+		scores.requestTimedOut(request)
+	}
+	require.False(t, scores.supportsRanges(oldPeer))
+	request := &blockRequest{peer: oldPeer, from: 1, to: 10, single: true, sentTime: time.Now()}
+	scores.requestSent(request)
+	scores.blockReceived(request, time.Now())
+	scores.requestTimedOut(request)
+	require.False(t, scores.supportsRanges(oldPeer))
+
+	// a peer, which has answered a range request, is not downgraded because of timeouts
+	request = &blockRequest{peer: newPeer, from: 1, to: 10, sentTime: time.Now()}
+	scores.requestSent(request)
+	scores.blockReceived(request, time.Now())
+	scores.requestTimedOut(request)
+	for i := 0; i < maxUnansweredRangeRequestsConst; i++ {
+		request := &blockRequest{peer: newPeer, from: 1, to: 10, sentTime: time.Now()}
+		scores.requestSent(request)
+		scores.requestTimedOut(request)
+	}
+	require.True(t, scores.supportsRanges(newPeer))
+}
@@ -37,13 +37,21 @@ type stateManager struct {
 	currentSyncData             atomic.Value
 	notifiedAnchorOutputID      *iotago.UTXOInput
 	syncingBlocks               *syncingBlocks
+	syncWindow                  uint32                   // max number of blocks being synced at once
+	checkpoints                 map[uint32]*approvalInfo // approvals of the indices below the state output, verified backward from it
+	fastSyncThreshold           uint32 // 0, if fast sync is disabled
+	fastSync                    *fastSync
+	fastSyncIncomplete          bool // the state in the DB is being downloaded by fast sync
 	receivePeerMessagesAttachID interface{}
 	timers                      StateManagerTimers
 	log                         *logger.Logger
 
 	// Channels for accepting external events.
 	eventGetBlockMsgPipe       pipe.Pipe
+	eventGetBlocksMsgPipe      pipe.Pipe
 	eventBlockMsgPipe          pipe.Pipe
+	eventGetTrieNodesMsgPipe   pipe.Pipe
+	eventTrieNodesMsgPipe      pipe.Pipe
 	eventAliasOutputPipe       pipe.Pipe
 	eventStateCandidateMsgPipe pipe.Pipe
 	eventTimerMsgPipe          pipe.Pipe
@@ -54,12 +62,19 @@ type stateManager struct {
 var _ chain.StateManager = &stateManager{}
 
 const (
-	numberOfNodesToRequestBlockFromConst = 5
-	maxBlocksToCommitConst               = 10000 // 10k
-	maxMsgBuffer                         = 1000
+	maxPeersToRequestBlocksFromConst = 16   // number of random peers, among which the best scored ones are asked for blocks
+	maxBlocksPerRequestConst         = 100  // max number of blocks requested and served in a single GetBlocksMsg
+	maxSyncWindowConst               = 1000 // max number of blocks being synced at once
+	maxBlocksPerCommitConst          = 100  // max number of synced blocks committed to the DB at once
+	maxMsgBuffer                     = 1000
+)
 
+const (
 	peerMsgTypeGetBlock = iota
 	peerMsgTypeBlock
+	peerMsgTypeGetBlocks
+	peerMsgTypeGetTrieNodes
+	peerMsgTypeTrieNodes
 )
 
 func New(
@@ -85,11 +100,16 @@ func New(
 		nodeConn:                   nodeconn,
 		domain:                     domain,
 		syncingBlocks:              newSyncingBlocks(log, wal),
+		syncWindow:                 maxSyncWindowConst,
+		checkpoints:                make(map[uint32]*approvalInfo),
 		timers:                     timers,
 		log:                        log,
 		pullStateRetryTime:         time.Now(),
 		eventGetBlockMsgPipe:       pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventGetBlocksMsgPipe:      pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventBlockMsgPipe:          pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventGetTrieNodesMsgPipe:   pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventTrieNodesMsgPipe:      pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventAliasOutputPipe:       pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventStateCandidateMsgPipe: pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventTimerMsgPipe:          pipe.NewLimitInfinitePipe(1),
@@ -114,6 +134,16 @@ func (sm *stateManager) receiveChainPeerMessages(peerMsg *peering.PeerMessageIn)
 			GetBlockMsg:  *msg,
 			SenderPubKey: peerMsg.SenderPubKey,
 		})
+	case peerMsgTypeGetBlocks:
+		msg, err := messages.NewGetBlocksMsg(peerMsg.MsgData)
+		if err != nil {
+			sm.log.Error(err)
+			return
+		}
+		sm.EnqueueGetBlocksMsg(&messages.GetBlocksMsgIn{
+			GetBlocksMsg: *msg,
+			SenderPubKey: peerMsg.SenderPubKey,
+		})
 	case peerMsgTypeBlock:
 		msg, err := messages.NewBlockMsg(peerMsg.MsgData)
 		if err != nil {
@@ -124,6 +154,26 @@ func (sm *stateManager) receiveChainPeerMessages(peerMsg *peering.PeerMessageIn)
 			BlockMsg:     *msg,
 			SenderPubKey: peerMsg.SenderPubKey,
 		})
+	case peerMsgTypeGetTrieNodes:
+		msg, err := messages.NewGetTrieNodesMsg(peerMsg.MsgData)
+		if err != nil {
+			sm.log.Error(err)
+			return
+		}
+		sm.EnqueueGetTrieNodesMsg(&messages.GetTrieNodesMsgIn{
+			GetTrieNodesMsg: *msg,
+			SenderPubKey:    peerMsg.SenderPubKey,
+		})
+	case peerMsgTypeTrieNodes:
+		msg, err := messages.NewTrieNodesMsg(peerMsg.MsgData)
+		if err != nil {
+			sm.log.Error(err)
+			return
+		}
+		sm.EnqueueTrieNodesMsg(&messages.TrieNodesMsgIn{
+			TrieNodesMsg: *msg,
+			SenderPubKey: peerMsg.SenderPubKey,
+		})
 	default:
 		sm.log.Warnf("Wrong type of state manager message: %v, ignoring it", peerMsg.MsgType)
 	}
@@ -138,7 +188,10 @@ func (sm *stateManager) Close() {
 	sm.domain.Close()
 
 	sm.eventGetBlockMsgPipe.Close()
+	sm.eventGetBlocksMsgPipe.Close()
 	sm.eventBlockMsgPipe.Close()
+	sm.eventGetTrieNodesMsgPipe.Close()
+	sm.eventTrieNodesMsgPipe.Close()
 	sm.eventAliasOutputPipe.Close()
 	sm.eventStateCandidateMsgPipe.Close()
 	sm.eventTimerMsgPipe.Close()
@@ -146,20 +199,28 @@ func (sm *stateManager) Close() {
 
 // initial loading of the solid state
 func (sm *stateManager) initLoadState() {
-	solidState, stateExists, err := state.LoadSolidState(sm.store, sm.chain.ID())
+	sm.setFastSyncOptions()
+	fastSyncResumed, err := sm.resumeInterruptedFastSync()
 	if err != nil {
 		sm.chain.EnqueueDismissChain(fmt.Sprintf("StateManager.initLoadState: %v", err))
 		return
 	}
-	if stateExists {
-		sm.solidState = solidState
-		sm.chain.GlobalStateSync().SetSolidIndex(solidState.BlockIndex())
-		sm.log.Infof("SOLID STATE has been loaded. Block index: #%d, State commitment: %s",
-			solidState.BlockIndex(), state.RootCommitment(solidState.TrieNodeStore()))
-	} else if err := sm.createOriginState(); err != nil {
-		// create origin state in DB
-		sm.chain.EnqueueDismissChain(fmt.Sprintf("StateManager.initLoadState. Failed to create origin state: %v", err))
-		return
+	if !fastSyncResumed {
+		solidState, stateExists, err := state.LoadSolidState(sm.store, sm.chain.ID())
+		if err != nil {
+			sm.chain.EnqueueDismissChain(fmt.Sprintf("StateManager.initLoadState: %v", err))
+			return
+		}
+		if stateExists {
+			sm.solidState = solidState
+			sm.chain.GlobalStateSync().SetSolidIndex(solidState.BlockIndex())
+			sm.log.Infof("SOLID STATE has been loaded. Block index: #%d, State commitment: %s",
+				solidState.BlockIndex(), state.RootCommitment(solidState.TrieNodeStore()))
+		} else if err := sm.createOriginState(); err != nil {
+			// create origin state in DB
+			sm.chain.EnqueueDismissChain(fmt.Sprintf("StateManager.initLoadState. Failed to create origin state: %v", err))
+			return
+		}
 	}
 	sm.setRawBlocksOptions()
 	sm.recvLoop() // Check to process external events.
@@ -195,7 +256,10 @@ func (sm *stateManager) GetStatusSnapshot() *chain.SyncInfo {
 func (sm *stateManager) recvLoop() {
 	sm.ready.SetReady()
 	eventGetBlockMsgCh := sm.eventGetBlockMsgPipe.Out()
+	eventGetBlocksMsgCh := sm.eventGetBlocksMsgPipe.Out()
 	eventBlockMsgCh := sm.eventBlockMsgPipe.Out()
+	eventGetTrieNodesMsgCh := sm.eventGetTrieNodesMsgPipe.Out()
+	eventTrieNodesMsgCh := sm.eventTrieNodesMsgPipe.Out()
 	eventAliasOutputCh := sm.eventAliasOutputPipe.Out()
 	eventStateCandidateMsgCh := sm.eventStateCandidateMsgPipe.Out()
 	eventTimerMsgCh := sm.eventTimerMsgPipe.Out()
@@ -207,12 +271,30 @@ func (sm *stateManager) recvLoop() {
 			} else {
 				eventGetBlockMsgCh = nil
 			}
+		case msg, ok := <-eventGetBlocksMsgCh:
+			if ok {
+				sm.handleGetBlocksMsg(msg.(*messages.GetBlocksMsgIn))
+			} else {
+				eventGetBlocksMsgCh = nil
+			}
 		case msg, ok := <-eventBlockMsgCh:
 			if ok {
 				sm.handleBlockMsg(msg.(*messages.BlockMsgIn))
 			} else {
 				eventBlockMsgCh = nil
 			}
+		case msg, ok := <-eventGetTrieNodesMsgCh:
+			if ok {
+				sm.handleGetTrieNodesMsg(msg.(*messages.GetTrieNodesMsgIn))
+			} else {
+				eventGetTrieNodesMsgCh = nil
+			}
+		case msg, ok := <-eventTrieNodesMsgCh:
+			if ok {
+				sm.handleTrieNodesMsg(msg.(*messages.TrieNodesMsgIn))
+			} else {
+				eventTrieNodesMsgCh = nil
+			}
 		case msg, ok := <-eventAliasOutputCh:
 			if ok {
 				sm.handleAliasOutput(msg.(*isc.AliasOutputWithID))
@@ -233,7 +315,10 @@ func (sm *stateManager) recvLoop() {
 			}
 		}
 		if eventGetBlockMsgCh == nil &&
+			eventGetBlocksMsgCh == nil &&
 			eventBlockMsgCh == nil &&
+			eventGetTrieNodesMsgCh == nil &&
+			eventTrieNodesMsgCh == nil &&
 			eventAliasOutputCh == nil &&
 			eventStateCandidateMsgCh == nil &&
 			eventTimerMsgCh == nil {
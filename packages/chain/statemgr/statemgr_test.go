@@ -9,8 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/stretchr/testify/require"
@@ -239,6 +241,226 @@ func TestCatchUpNoConfirmedOutput(t *testing.T) {
 	waitSyncBlockIndexAndCheck(10*time.Second, t, node1, targetBlockIndex)
 }
 
+// A fresh node catches up over several ranges of blocks served by several
+// peers. Only the last state output is known to it, so the whole sync window
+// is approved by a single output and committed in several batches.
+func TestCatchUpManyBlocksFromSeveralPeers(t *testing.T) {
+	const numberOfServingPeers = 3
+	env := NewMockedEnv(numberOfServingPeers+1, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	servingNodes := make([]*MockedNode, numberOfServingPeers)
+	servingPubKeys := make([]*cryptolib.PublicKey, numberOfServingPeers)
+	for i := range servingNodes {
+		servingNodes[i] = NewMockedNode(env, i, NewStateManagerTimers())
+		servingNodes[i].StateManager.Ready().MustWait()
+		servingPubKeys[i] = servingNodes[i].PubKey
+	}
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(servingPubKeys)
+		env.AddNode(node)
+		node.Start()
+		waitSyncBlockIndexAndCheck(10*time.Second, t, node, 0)
+	}
+
+	const targetBlockIndex = 2*maxBlocksPerRequestConst + maxBlocksPerRequestConst/2
+	servingNodes[0].OnStateTransitionMakeNewStateTransition(targetBlockIndex)
+	servingNodes[0].MakeNewStateTransition()
+	for _, node := range servingNodes {
+		waitSyncBlockIndexAndCheck(60*time.Second, t, node, targetBlockIndex)
+	}
+
+	env.Ledgers.GetLedger(env.ChainID).SetPullOutputByIDAllowed(false)
+	catchingNode := NewMockedNode(env, numberOfServingPeers, NewStateManagerTimers())
+	catchingNode.StateManager.Ready().MustWait()
+	catchingNode.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	}
+	env.AddNode(catchingNode)
+	catchingNode.Start()
+	waitSyncBlockIndexAndCheck(20*time.Second, t, catchingNode, targetBlockIndex)
+}
+
+func TestFastSync(t *testing.T) {
+	const numberOfServingPeers = 3
+	env := NewMockedEnv(numberOfServingPeers+1, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	servingNodes := make([]*MockedNode, numberOfServingPeers)
+	servingPubKeys := make([]*cryptolib.PublicKey, numberOfServingPeers)
+	for i := range servingNodes {
+		servingNodes[i] = NewMockedNode(env, i, NewStateManagerTimers())
+		servingNodes[i].StateManager.Ready().MustWait()
+		servingPubKeys[i] = servingNodes[i].PubKey
+	}
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(servingPubKeys)
+		env.AddNode(node)
+		node.Start()
+		waitSyncBlockIndexAndCheck(10*time.Second, t, node, 0)
+	}
+
+	const targetBlockIndex1 = 60
+	servingNodes[0].OnStateTransitionMakeNewStateTransition(targetBlockIndex1)
+	servingNodes[0].MakeNewStateTransition()
+	for _, node := range servingNodes {
+		waitSyncBlockIndexAndCheck(20*time.Second, t, node, targetBlockIndex1)
+	}
+
+	catchingNode := NewMockedNode(env, numberOfServingPeers, NewStateManagerTimers())
+	catchingNode.StateManager.Ready().MustWait()
+	catchingNode.StateManager.(*stateManager).fastSyncThreshold = 50
+	catchingNode.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	}
+	env.AddNode(catchingNode)
+	catchingNode.Start()
+	si := waitSyncBlockIndexAndCheck(20*time.Second, t, catchingNode, targetBlockIndex1)
+	require.Nil(t, si.FastSync)
+
+	// the state is downloaded, not the blocks
+	store := catchingNode.StateManager.(*stateManager).store
+	for i := uint32(1); i <= targetBlockIndex1; i++ {
+		blockBytes, err := state.LoadBlockBytes(store, i)
+		require.NoError(t, err)
+		require.Nil(t, blockBytes)
+	}
+
+	// the blocks after the downloaded state are synced as usual
+	const targetBlockIndex2 = targetBlockIndex1 + 5
+	servingNodes[0].OnStateTransitionMakeNewStateTransition(targetBlockIndex2)
+	servingNodes[0].MakeNewStateTransition()
+	waitSyncBlockIndexAndCheck(20*time.Second, t, catchingNode, targetBlockIndex2)
+	blockBytes, err := state.LoadBlockBytes(store, targetBlockIndex2)
+	require.NoError(t, err)
+	require.NotNil(t, blockBytes)
+}
+
+// A node, whose fast sync has been interrupted, resumes it on start and
+// downloads only the trie nodes it does not have yet.
+func TestFastSyncResumed(t *testing.T) {
+	const numberOfServingPeers = 3
+	env := NewMockedEnv(numberOfServingPeers+1, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	servingNodes := make([]*MockedNode, numberOfServingPeers)
+	servingPubKeys := make([]*cryptolib.PublicKey, numberOfServingPeers)
+	for i := range servingNodes {
+		servingNodes[i] = NewMockedNode(env, i, NewStateManagerTimers())
+		servingNodes[i].StateManager.Ready().MustWait()
+		servingPubKeys[i] = servingNodes[i].PubKey
+	}
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(servingPubKeys)
+		env.AddNode(node)
+		node.Start()
+		waitSyncBlockIndexAndCheck(10*time.Second, t, node, 0)
+	}
+
+	const targetBlockIndex = 30
+	servingNodes[0].OnStateTransitionMakeNewStateTransition(targetBlockIndex)
+	servingNodes[0].MakeNewStateTransition()
+	for _, node := range servingNodes {
+		waitSyncBlockIndexAndCheck(20*time.Second, t, node, targetBlockIndex)
+	}
+
+	catchingNode := NewMockedNode(env, numberOfServingPeers, NewStateManagerTimers())
+	catchingNode.StateManager.Ready().MustWait()
+	sm := catchingNode.StateManager.(*stateManager)
+	// the chain is not far enough ahead to start fast sync, it is only resumed
+	sm.fastSyncThreshold = 1000
+
+	// half of the trie nodes have been downloaded before the interruption
+	srcStore := servingNodes[0].StateManager.(*stateManager).store
+	copied := 0
+	err := srcStore.IterateKeys([]byte{dbkeys.ObjectTypeTrie}, func(key kvstore.Key) bool {
+		if copied++; copied%2 == 0 {
+			value, err := srcStore.Get(key)
+			require.NoError(t, err)
+			require.NoError(t, sm.store.Set(key, value))
+		}
+		return true
+	})
+	require.NoError(t, err)
+	err = srcStore.IterateKeys([]byte{dbkeys.ObjectTypeState}, func(key kvstore.Key) bool {
+		value, err := srcStore.Get(key)
+		require.NoError(t, err)
+		require.NoError(t, sm.store.Set(key, value))
+		return true
+	})
+	require.NoError(t, err)
+	require.NoError(t, state.SetFastSyncInProgress(sm.store, true))
+	resumed, err := sm.resumeInterruptedFastSync()
+	require.NoError(t, err)
+	require.True(t, resumed)
+	require.EqualValues(t, 0, sm.solidState.BlockIndex())
+
+	catchingNode.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	}
+	env.AddNode(catchingNode)
+	catchingNode.Start()
+	si := waitSyncBlockIndexAndCheck(20*time.Second, t, catchingNode, targetBlockIndex)
+	require.Nil(t, si.FastSync)
+	inProgress, err := state.IsFastSyncInProgress(sm.store)
+	require.NoError(t, err)
+	require.False(t, inProgress)
+
+	// the state is downloaded, not the blocks
+	for i := uint32(1); i <= targetBlockIndex; i++ {
+		blockBytes, err := state.LoadBlockBytes(sm.store, i)
+		require.NoError(t, err)
+		require.Nil(t, blockBytes)
+	}
+}
+
+// A fresh node catches up a chain several sync windows ahead of it. Only the last
+// state output is known to it and the outputs can't be pulled from L1 by ID, so
+// the blocks below the window must be approved by verifying them backward from it.
+func TestCatchUpMoreBlocksThanSyncWindow(t *testing.T) {
+	const numberOfServingPeers = 3
+	env := NewMockedEnv(numberOfServingPeers+1, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	servingNodes := make([]*MockedNode, numberOfServingPeers)
+	servingPubKeys := make([]*cryptolib.PublicKey, numberOfServingPeers)
+	for i := range servingNodes {
+		servingNodes[i] = NewMockedNode(env, i, NewStateManagerTimers())
+		servingNodes[i].StateManager.Ready().MustWait()
+		servingPubKeys[i] = servingNodes[i].PubKey
+	}
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(servingPubKeys)
+		env.AddNode(node)
+		node.Start()
+		waitSyncBlockIndexAndCheck(10*time.Second, t, node, 0)
+	}
+
+	const syncWindow = 20
+	const targetBlockIndex = 4*syncWindow + syncWindow/2
+	servingNodes[0].OnStateTransitionMakeNewStateTransition(targetBlockIndex)
+	servingNodes[0].MakeNewStateTransition()
+	for _, node := range servingNodes {
+		waitSyncBlockIndexAndCheck(30*time.Second, t, node, targetBlockIndex)
+	}
+
+	env.Ledgers.GetLedger(env.ChainID).SetPullOutputByIDAllowed(false)
+	catchingNode := NewMockedNode(env, numberOfServingPeers, NewStateManagerTimers())
+	catchingNode.NodeConn.SetPullOutputByIDAllowed(false)
+	catchingNode.StateManager.Ready().MustWait()
+	catchingNode.StateManager.(*stateManager).syncWindow = syncWindow
+	catchingNode.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	for _, node := range servingNodes {
+		node.StateManager.SetChainPeers(append(servingPubKeys, catchingNode.PubKey))
+	}
+	env.AddNode(catchingNode)
+	catchingNode.Start()
+	waitSyncBlockIndexAndCheck(30*time.Second, t, catchingNode, targetBlockIndex)
+}
+
 func TestNodeDisconnected(t *testing.T) {
 	numberOfConnectedPeers := 5
 	env := NewMockedEnv(numberOfConnectedPeers+1, t, false)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/state"
 )
@@ -17,6 +18,8 @@ type syncingBlock struct {
 	blockCandidates       map[state.BlockHash]*candidateBlock
 	approvalInfo          *approvalInfo
 	receivedFromWAL       bool
+	request               *blockRequest // the pending request of the block, if any
+	refetch               bool          // the block candidates are known to be wrong
 	log                   *logger.Logger
 }
 
@@ -71,7 +74,7 @@ func (syncT *syncingBlock) getNextStateCommitment() trie.VCommitment {
 	return syncT.approvalInfo.getNextStateCommitment()
 }
 
-func (syncT *syncingBlock) addBlockCandidate(hash state.BlockHash, block state.Block, nextState state.VirtualStateAccess) (isBlockNew bool, candidate *candidateBlock) {
+func (syncT *syncingBlock) addBlockCandidate(hash state.BlockHash, block state.Block, nextState state.VirtualStateAccess, sender *cryptolib.PublicKey) (isBlockNew bool, candidate *candidateBlock) {
 	candidateExisting, ok := syncT.blockCandidates[hash]
 	if ok {
 		// already have block. Check consistency. If inconsistent, start from scratch
@@ -84,8 +87,9 @@ func (syncT *syncingBlock) addBlockCandidate(hash state.BlockHash, block state.B
 		syncT.log.Debugf("addBlockCandidate: existing block index %v with hash %s arrived, votes increased.", block.BlockIndex(), hash)
 		return false, candidateExisting
 	}
-	candidate = newCandidateBlock(block, nextState)
+	candidate = newCandidateBlock(block, nextState, sender)
 	syncT.blockCandidates[hash] = candidate
+	syncT.refetch = false
 	syncT.log.Debugf("addBlockCandidate: new block candidate created for block index: %d, hash: %s", block.BlockIndex(), hash)
 	return true, candidate
 }
@@ -107,3 +111,19 @@ func (syncT *syncingBlock) setReceivedFromWAL() {
 func (syncT *syncingBlock) isReceivedFromWAL() bool {
 	return syncT.receivedFromWAL
 }
+
+func (syncT *syncingBlock) getRequest() *blockRequest {
+	return syncT.request
+}
+
+func (syncT *syncingBlock) setRequest(request *blockRequest) {
+	syncT.request = request
+}
+
+func (syncT *syncingBlock) setRefetch() {
+	syncT.refetch = true
+}
+
+func (syncT *syncingBlock) isRefetchNeeded() bool {
+	return syncT.refetch
+}
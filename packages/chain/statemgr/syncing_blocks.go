@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/trie.go/trie"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/state"
 )
@@ -20,6 +21,7 @@ const (
 
 type syncingBlocks struct {
 	blocks       map[uint32]*syncingBlock // StateIndex -> BlockCandidates
+	peerScores   *peerScores
 	log          *logger.Logger
 	wal          chain.WAL
 	lastPullTime time.Time // Time, when we pulled for some block last time.
@@ -28,9 +30,10 @@ type syncingBlocks struct {
 
 func newSyncingBlocks(log *logger.Logger, wal chain.WAL) *syncingBlocks {
 	return &syncingBlocks{
-		blocks: make(map[uint32]*syncingBlock),
-		log:    log,
-		wal:    wal,
+		blocks:     make(map[uint32]*syncingBlock),
+		peerScores: newPeerScores(log),
+		log:        log,
+		wal:        wal,
 	}
 }
 
@@ -102,7 +105,9 @@ func (syncsT *syncingBlocks) hasBlockCandidatesNotOlderThan(index uint32) bool {
 	return false
 }
 
-func (syncsT *syncingBlocks) addBlockCandidate(block state.Block, nextState state.VirtualStateAccess) {
+// addBlockCandidate adds the block to the candidates of its index; sender is
+// nil, if the block was not received from a peer
+func (syncsT *syncingBlocks) addBlockCandidate(block state.Block, nextState state.VirtualStateAccess, sender *cryptolib.PublicKey) {
 	stateIndex := block.BlockIndex()
 	hash := state.BlockHashFromData(block.EssenceBytes())
 	syncsT.log.Debugf("addBlockCandidate: adding block candidate for index %v with essence hash %s; next state provided: %v", stateIndex, hash, nextState != nil)
@@ -111,7 +116,11 @@ func (syncsT *syncingBlocks) addBlockCandidate(block state.Block, nextState stat
 		syncsT.log.Errorf("addBlockCandidate: adding block candidate for index %v with essence hash %s failed: index is not syncing", stateIndex, hash)
 		return
 	}
-	sync.addBlockCandidate(hash, block, nextState)
+	if request := sync.getRequest(); sender != nil && request != nil && request.peer.Equals(sender) {
+		syncsT.peerScores.blockReceived(request, time.Now())
+		sync.setRequest(nil)
+	}
+	sync.addBlockCandidate(hash, block, nextState, sender)
 }
 
 func (syncsT *syncingBlocks) setApprovalInfo(output *isc.AliasOutputWithID) {
@@ -128,6 +137,18 @@ func (syncsT *syncingBlocks) setApprovalInfo(output *isc.AliasOutputWithID) {
 	sync.setApprovalInfo(output)
 }
 
+// setApproval sets the approval of the syncing index, if it is not approved yet
+func (syncsT *syncingBlocks) setApproval(stateIndex uint32, approval *approvalInfo) {
+	sync, ok := syncsT.blocks[stateIndex]
+	if !ok {
+		syncsT.log.Debugf("setApproval failed: state index %v is not syncing", stateIndex)
+		return
+	}
+	if sync.approvalInfo == nil {
+		sync.approvalInfo = approval
+	}
+}
+
 func (syncsT *syncingBlocks) isObtainedFromWAL(i uint32) bool {
 	sync, ok := syncsT.blocks[i]
 	if ok {
@@ -156,7 +177,7 @@ func (syncsT *syncingBlocks) startSyncingIfNeeded(stateIndex uint32) {
 			syncsT.log.Errorf("startSyncingIfNeeded: error obtaining block from block bytes in wal for index %d: %v", stateIndex, err)
 			return
 		}
-		syncsT.addBlockCandidate(block, nil)
+		syncsT.addBlockCandidate(block, nil, nil)
 		syncsT.blocks[stateIndex].setReceivedFromWAL()
 		syncsT.log.Debugf("startSyncingIfNeeded: block with index %d included from wal.", stateIndex)
 	}
@@ -168,21 +189,86 @@ func (syncsT *syncingBlocks) isSyncing(stateIndex uint32) bool {
 }
 
 func (syncsT *syncingBlocks) restartSyncing() {
+	for _, sync := range syncsT.blocks {
+		if request := sync.getRequest(); request != nil {
+			syncsT.peerScores.requestCancelled(request)
+		}
+	}
 	syncsT.blocks = make(map[uint32]*syncingBlock)
 }
 
 func (syncsT *syncingBlocks) deleteSyncingBlock(stateIndex uint32) {
+	if sync, ok := syncsT.blocks[stateIndex]; ok {
+		if request := sync.getRequest(); request != nil {
+			syncsT.peerScores.requestCancelled(request)
+		}
+	}
 	delete(syncsT.blocks, stateIndex)
 }
 
 //
-// Track poll/reception times, to determine, if no one responds to our polls.
+// Requesting ranges of blocks from the peers.
 //
 
-func (syncsT *syncingBlocks) blocksPulled() {
-	syncsT.lastPullTime = time.Now()
+// isBlockNeeded returns true, if the block must be requested from the peers:
+// it is neither in WAL nor received (or the received candidates are wrong),
+// and it is time to retry the request.
+func (syncsT *syncingBlocks) isBlockNeeded(stateIndex uint32, now time.Time) bool {
+	sync, ok := syncsT.blocks[stateIndex]
+	if !ok || sync.isReceivedFromWAL() {
+		return false
+	}
+	if sync.getBlockCandidatesCount() > 0 && !sync.isRefetchNeeded() {
+		return false
+	}
+	return now.After(sync.getRequestBlockRetryTime())
 }
 
+// setRefetchNeeded marks the block candidates of the index as wrong, so that
+// the block is requested again
+func (syncsT *syncingBlocks) setRefetchNeeded(stateIndex uint32) {
+	if sync, ok := syncsT.blocks[stateIndex]; ok {
+		sync.setRefetch()
+	}
+}
+
+func (syncsT *syncingBlocks) selectPeer(peers []*cryptolib.PublicKey) *cryptolib.PublicKey {
+	return syncsT.peerScores.selectPeer(peers)
+}
+
+func (syncsT *syncingBlocks) supportsRanges(peer *cryptolib.PublicKey) bool {
+	return syncsT.peerScores.supportsRanges(peer)
+}
+
+// blocksRequested registers the request of blocks from..to sent to the peer;
+// single is true, if the blocks were requested one by one. A pending older
+// request of any of the blocks is considered as timed out.
+func (syncsT *syncingBlocks) blocksRequested(peer *cryptolib.PublicKey, from, to uint32, single bool, requestBlockRetryTime time.Time) {
+	now := time.Now()
+	request := &blockRequest{peer: peer, from: from, to: to, single: single, sentTime: now}
+	syncsT.peerScores.requestSent(request)
+	for i := from; i <= to; i++ {
+		sync, ok := syncsT.blocks[i]
+		if !ok {
+			continue
+		}
+		if previous := sync.getRequest(); previous != nil {
+			syncsT.peerScores.requestTimedOut(previous)
+		}
+		sync.setRequest(request)
+		sync.setRequestBlockRetryTime(requestBlockRetryTime)
+	}
+	syncsT.lastPullTime = now
+}
+
+func (syncsT *syncingBlocks) blockInvalid(peer *cryptolib.PublicKey) {
+	syncsT.peerScores.blockInvalid(peer)
+}
+
+//
+// Track poll/reception times, to determine, if no one responds to our polls.
+//
+
 func (syncsT *syncingBlocks) blockReceived() {
 	syncsT.lastRecvTime = time.Now()
 }
@@ -1,11 +1,12 @@
 package statemgr
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
+	"github.com/iotaledger/trie.go/trie"
 	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/state"
@@ -18,22 +19,8 @@ func (sm *stateManager) aliasOutputReceived(aliasOutput *isc.AliasOutputWithID)
 	sm.log.Debugf("aliasOutputReceived: received output index %v, id %v", aliasOutputIndex, aliasOutputIDStr)
 	if sm.stateOutput == nil || sm.stateOutput.GetStateIndex() < aliasOutputIndex {
 		sm.log.Debugf("aliasOutputReceived: output index %v, id %v is new state output", aliasOutputIndex, aliasOutputIDStr)
-		var from uint32
-		if sm.stateOutput == nil {
-			from = 1
-		} else {
-			from = sm.stateOutput.GetStateIndex() + 1
-		}
-		solidStateIndex := sm.solidState.BlockIndex()
-		if solidStateIndex >= from {
-			newFrom := solidStateIndex + 1
-			sm.log.Debugf("aliasOutputReceived: according to stateOutput syncing should be started from index %v, but solidState is at index %v -> starting syncing from index %v",
-				from, solidStateIndex, newFrom)
-			from = newFrom
-		}
-		for i := from; i <= aliasOutputIndex; i++ {
-			sm.syncingBlocks.startSyncingIfNeeded(i)
-		}
+		// the blocks up to the output are started to be synced by doSyncActionIfNeeded,
+		// as the sync window reaches them
 		sm.syncingBlocks.setApprovalInfo(aliasOutput)
 		sm.stateOutput = aliasOutput
 		sm.stateOutputTimestamp = time.Now()
@@ -80,49 +67,172 @@ func (sm *stateManager) doSyncActionIfNeeded() {
 		sm.log.Panicf("doSyncAction inconsistency: solid state index is larger than state output index")
 	}
 	// not synced
-	startSyncFromIndex := sm.solidState.BlockIndex() + 1
-	sm.log.Debugf("doSyncAction: trying to sync state from index %v to %v", startSyncFromIndex, sm.stateOutput.GetStateIndex())
+	if sm.fastSync != nil || sm.isFastSyncNeeded() {
+		sm.doFastSyncAction()
+		return
+	}
 	if !sm.domain.HaveMainPeers() || sm.syncingBlocks.blockPollFallbackNeeded() {
 		sm.domain.SetFallbackMode(true)
 	}
-	for i := startSyncFromIndex; i <= sm.stateOutput.GetStateIndex(); i++ {
-		requestBlockRetryTime := sm.syncingBlocks.getRequestBlockRetryTime(i)
-		blockCandidatesCount := sm.syncingBlocks.getBlockCandidatesCount(i)
-		hasApprovedBlockCandidate := sm.syncingBlocks.hasApprovedBlockCandidate(i)
-		sm.log.Debugf("doSyncAction: trying to sync state for index %v; requestBlockRetryTime %v, blockCandidates count %v, has approved blockCandidate %v",
-			i, requestBlockRetryTime, blockCandidatesCount, hasApprovedBlockCandidate)
-		// TODO: temporary if. We need to find a solution to synchronize over large gaps. Making state snapshots may help.
-		if i > startSyncFromIndex+maxBlocksToCommitConst {
-			errorStr := fmt.Sprintf("StateManager.doSyncActionIfNeeded: too many blocks to catch up: %v", sm.stateOutput.GetStateIndex()-startSyncFromIndex+1)
-			sm.log.Errorf(errorStr)
-			sm.chain.EnqueueDismissChain(errorStr)
+	startSyncFromIndex := sm.solidState.BlockIndex() + 1
+	syncToIndex, approval := sm.syncAnchor(startSyncFromIndex)
+	if approval == nil {
+		return
+	}
+	// The approved index is too far above the solid state to sync all the blocks up to
+	// it at once: the blocks below it are verified backward window by window, until
+	// the approval of an index close enough to the solid state is known.
+	for syncToIndex-startSyncFromIndex >= sm.syncWindow {
+		windowFrom := syncToIndex - sm.syncWindow + 1
+		sm.log.Debugf("doSyncAction: verifying blocks from index %v to %v backward; solid state index is %v",
+			windowFrom, syncToIndex, sm.solidState.BlockIndex())
+		sm.startSyncing(windowFrom, syncToIndex)
+		sm.requestMissingBlocks(windowFrom, syncToIndex)
+		if !sm.verifyBlocksBackward(windowFrom, syncToIndex, approval.getBlockHash()) {
 			return
 		}
-		if !sm.syncingBlocks.isObtainedFromWAL(i) && time.Now().After(requestBlockRetryTime) {
-			// have to pull
-			sm.log.Debugf("doSyncAction: requesting block index %v, fallback=%v from %v random peers.", i, sm.domain.GetFallbackMode(), numberOfNodesToRequestBlockFromConst)
-			getBlockMsg := &messages.GetBlockMsg{BlockIndex: i}
-			for _, p := range sm.domain.GetRandomOtherPeers(numberOfNodesToRequestBlockFromConst) {
-				sm.domain.SendMsgByPubKey(p, peering.PeerMessageReceiverStateManager, peerMsgTypeGetBlock, util.MustBytes(getBlockMsg))
-				sm.syncingBlocks.blocksPulled()
-				sm.log.Debugf("doSyncAction: requesting block index %v, from %v", i, p.String())
+		syncToIndex, approval = sm.syncAnchor(startSyncFromIndex)
+	}
+	sm.log.Debugf("doSyncAction: trying to sync state from index %v to %v; state output index is %v",
+		startSyncFromIndex, syncToIndex, sm.stateOutput.GetStateIndex())
+	sm.startSyncing(startSyncFromIndex, syncToIndex)
+	sm.syncingBlocks.setApproval(syncToIndex, approval)
+	sm.requestMissingBlocks(startSyncFromIndex, syncToIndex)
+	sm.commitSyncedBlocks(startSyncFromIndex, syncToIndex)
+}
+
+func (sm *stateManager) startSyncing(fromIndex, toIndex uint32) {
+	for i := fromIndex; i <= toIndex; i++ {
+		sm.syncingBlocks.startSyncingIfNeeded(i)
+	}
+}
+
+// syncAnchor returns the lowest index not below fromIndex, whose approval is
+// known, and the approval: it is either the index of the state output or a
+// checkpoint found by verifying the blocks backward from it. The checkpoints
+// below fromIndex are no longer needed and are deleted.
+func (sm *stateManager) syncAnchor(fromIndex uint32) (uint32, *approvalInfo) {
+	index := sm.stateOutput.GetStateIndex()
+	approval, err := newApprovalInfo(sm.stateOutput)
+	if err != nil {
+		sm.log.Errorf("syncAnchor: cannot obtain the approval of the state output: %v", err)
+		return 0, nil
+	}
+	for i, checkpoint := range sm.checkpoints {
+		switch {
+		case i < fromIndex:
+			delete(sm.checkpoints, i)
+		case i < index:
+			index = i
+			approval = checkpoint
+		}
+	}
+	return index, approval
+}
+
+// verifyBlocksBackward follows the previous L1 commitments of the block candidates
+// from the approved block with index toIndex down to fromIndex. If every block is
+// found, the previous L1 commitment of the block with index fromIndex approves the
+// index fromIndex-1 and it is stored as a checkpoint. The blocks of the window are
+// then dropped to keep the memory bounded; they are requested again, when the
+// window reaches them from below.
+func (sm *stateManager) verifyBlocksBackward(fromIndex, toIndex uint32, blockHash state.BlockHash) bool {
+	var previous *state.L1Commitment
+	for i := toIndex; i >= fromIndex; i-- {
+		block := sm.syncingBlocks.getBlockCandidate(i, blockHash)
+		if block == nil {
+			if sm.syncingBlocks.getBlockCandidatesCount(i) > 0 {
+				sm.log.Warnf("verifyBlocksBackward: block index %v hash %s not found among the candidates", i, blockHash)
+				sm.syncingBlocks.setRefetchNeeded(i)
 			}
-			sm.delayRequestBlockRetry(i)
+			return false
+		}
+		previous = block.getPreviousL1Commitment()
+		blockHash = previous.BlockHash
+	}
+	sm.checkpoints[fromIndex-1] = newApprovalInfoFromL1Commitment(previous)
+	sm.log.Debugf("verifyBlocksBackward: blocks from index %v to %v verified, index %v is approved by %s",
+		fromIndex, toIndex, fromIndex-1, sm.checkpoints[fromIndex-1])
+	for i := fromIndex; i <= toIndex; i++ {
+		sm.syncingBlocks.deleteSyncingBlock(i)
+	}
+	return true
+}
+
+// requestMissingBlocks requests the missing blocks in ranges of at most
+// maxBlocksPerRequestConst blocks. Each range is requested from a single peer,
+// selected by its score, so the ranges are downloaded from several peers in parallel.
+// The peers, which do not answer range requests, are asked for every block of
+// the range with a separate GetBlockMsg.
+func (sm *stateManager) requestMissingBlocks(fromIndex, toIndex uint32) {
+	now := time.Now()
+	var peers []*cryptolib.PublicKey
+	for i := fromIndex; i <= toIndex; i++ {
+		if !sm.syncingBlocks.isBlockNeeded(i, now) {
+			continue
+		}
+		rangeTo := i
+		for rangeTo < toIndex && rangeTo-i+1 < maxBlocksPerRequestConst && sm.syncingBlocks.isBlockNeeded(rangeTo+1, now) {
+			rangeTo++
 		}
-		if blockCandidatesCount == 0 {
-			sm.log.Debugf("doSyncAction: no block candidates for index %v", i)
+		if peers == nil {
+			peers = sm.domain.GetRandomOtherPeers(maxPeersToRequestBlocksFromConst)
+		}
+		peer := sm.syncingBlocks.selectPeer(peers)
+		if peer == nil {
+			sm.log.Debugf("requestMissingBlocks: no peers to request blocks from, fallback=%v", sm.domain.GetFallbackMode())
 			return
 		}
-		if hasApprovedBlockCandidate {
-			sm.log.Debugf("doSyncAction: trying to find candidates to commit from index %v to %v", startSyncFromIndex, i)
-			candidates, ok := sm.getCandidatesToCommit(make([]*candidateBlock, i-startSyncFromIndex+1), startSyncFromIndex, i, sm.syncingBlocks.getApprovedBlockCandidateHash(i))
-			if ok {
-				sm.log.Debugf("doSyncAction: candidates to commit found, committing")
-				sm.commitCandidates(candidates)
+		single := !sm.syncingBlocks.supportsRanges(peer)
+		if single {
+			sm.log.Debugf("requestMissingBlocks: requesting block indices %v-%v one by one from %v, fallback=%v", i, rangeTo, peer.String(), sm.domain.GetFallbackMode())
+			for j := i; j <= rangeTo; j++ {
+				getBlockMsg := &messages.GetBlockMsg{BlockIndex: j}
+				sm.domain.SendMsgByPubKey(peer, peering.PeerMessageReceiverStateManager, peerMsgTypeGetBlock, util.MustBytes(getBlockMsg))
 			}
+		} else {
+			sm.log.Debugf("requestMissingBlocks: requesting block indices %v-%v from %v, fallback=%v", i, rangeTo, peer.String(), sm.domain.GetFallbackMode())
+			getBlocksMsg := &messages.GetBlocksMsg{FromIndex: i, ToIndex: rangeTo}
+			sm.domain.SendMsgByPubKey(peer, peering.PeerMessageReceiverStateManager, peerMsgTypeGetBlocks, util.MustBytes(getBlocksMsg))
+		}
+		sm.syncingBlocks.blocksRequested(peer, i, rangeTo, single, now.Add(sm.timers.GetBlockRetry))
+		i = rangeTo
+	}
+}
+
+// commitSyncedBlocks finds the highest approved block, such that there are block
+// candidates for every index from fromIndex to it, and commits the blocks up to
+// it in batches of at most maxBlocksPerCommitConst blocks.
+func (sm *stateManager) commitSyncedBlocks(fromIndex, toIndex uint32) {
+	var approvedIndex uint32
+	found := false
+	for i := fromIndex; i <= toIndex; i++ {
+		if sm.syncingBlocks.getBlockCandidatesCount(i) == 0 {
+			sm.log.Debugf("commitSyncedBlocks: no block candidates for index %v", i)
+			break
+		}
+		if sm.syncingBlocks.hasApprovedBlockCandidate(i) {
+			approvedIndex = i
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	sm.log.Debugf("commitSyncedBlocks: trying to find candidates to commit from index %v to %v", fromIndex, approvedIndex)
+	candidates, ok := sm.getCandidatesToCommit(make([]*candidateBlock, approvedIndex-fromIndex+1), fromIndex, approvedIndex, sm.syncingBlocks.getApprovedBlockCandidateHash(approvedIndex))
+	if !ok {
+		return
+	}
+	sm.log.Debugf("commitSyncedBlocks: candidates to commit found, committing")
+	for len(candidates) > maxBlocksPerCommitConst {
+		// the state commitment of the batch is confirmed by the next block
+		if !sm.commitCandidates(candidates[:maxBlocksPerCommitConst], candidates[maxBlocksPerCommitConst].getPreviousL1Commitment().StateCommitment) {
 			return
 		}
+		candidates = candidates[maxBlocksPerCommitConst:]
 	}
+	sm.commitCandidates(candidates, sm.syncingBlocks.getNextStateCommitment(approvedIndex))
 }
 
 func (sm *stateManager) getCandidatesToCommit(candidateAcc []*candidateBlock, fromStateIndex, toStateIndex uint32, lastBlockHash state.BlockHash) ([]*candidateBlock, bool) {
@@ -133,6 +243,7 @@ func (sm *stateManager) getCandidatesToCommit(candidateAcc []*candidateBlock, fr
 	block := sm.syncingBlocks.getBlockCandidate(toStateIndex, lastBlockHash)
 	if block == nil {
 		sm.log.Warnf("getCandidatesToCommit block index %v hash %s not found", toStateIndex, lastBlockHash)
+		sm.syncingBlocks.setRefetchNeeded(toStateIndex)
 		return nil, false
 	}
 	sm.log.Debugf("getCandidatesToCommit block index %v hash %s found", toStateIndex, lastBlockHash)
@@ -140,7 +251,9 @@ func (sm *stateManager) getCandidatesToCommit(candidateAcc []*candidateBlock, fr
 	return sm.getCandidatesToCommit(candidateAcc, fromStateIndex, toStateIndex-1, block.getPreviousL1Commitment().BlockHash)
 }
 
-func (sm *stateManager) commitCandidates(candidates []*candidateBlock) {
+// commitCandidates applies the candidate blocks to the solid state and commits
+// the result, if its commitment is finalStateCommitment
+func (sm *stateManager) commitCandidates(candidates []*candidateBlock, finalStateCommitment trie.VCommitment) bool {
 	blocks := make([]state.Block, len(candidates))
 	calculatedState := sm.solidState.Copy()
 	for i, candidate := range candidates {
@@ -151,8 +264,9 @@ func (sm *stateManager) commitCandidates(candidates []*candidateBlock) {
 		if !state.EqualCommitments(candidatePrevStateCommitment, calculatedStateCommitment) {
 			sm.log.Errorf("commitCandidates: candidate index %v previous state commitment does not match calculated state commitment: %s != %s",
 				block.BlockIndex(), candidatePrevStateCommitment, calculatedStateCommitment)
+			sm.candidateInvalid(candidate)
 			sm.syncingBlocks.restartSyncing()
-			return
+			return false
 		}
 		var err error
 		calculatedState, err = candidate.getNextState(calculatedState)
@@ -160,33 +274,31 @@ func (sm *stateManager) commitCandidates(candidates []*candidateBlock) {
 		if err != nil {
 			sm.log.Errorf("commitCandidates: failed to apply synced block index #%d: %v",
 				block.BlockIndex(), err)
+			sm.candidateInvalid(candidate)
 			sm.syncingBlocks.restartSyncing()
-			return
+			return false
 		}
 	}
 
 	// state commitments must be equal
 	from := blocks[0].BlockIndex()
 	to := blocks[len(blocks)-1].BlockIndex()
-	finalStateCommitment := state.RootCommitment(calculatedState.TrieNodeStore())
-	finalCandidateCommitment := sm.syncingBlocks.getNextStateCommitment(to)
-	if !state.EqualCommitments(finalStateCommitment, finalCandidateCommitment) {
+	calculatedStateCommitment := state.RootCommitment(calculatedState.TrieNodeStore())
+	if !state.EqualCommitments(calculatedStateCommitment, finalStateCommitment) {
 		sm.log.Debugf("commitCandidates: tentative state index %v obtained, however its commitment does not match last candidate expected state commitment: %s != %s",
-			to, finalStateCommitment, finalCandidateCommitment)
+			to, calculatedStateCommitment, finalStateCommitment)
+		sm.candidateInvalid(candidates[len(candidates)-1])
 		sm.syncingBlocks.restartSyncing()
-		return
+		return false
 	}
 	sm.log.Debugf("commitCandidates: tentative state index %v obtained, its commitment matches last candidate expected state commitment: %s",
-		to, finalStateCommitment)
+		to, calculatedStateCommitment)
 
 	for _, block := range blocks {
 		sm.syncingBlocks.deleteSyncingBlock(block.BlockIndex())
 		sm.log.Debugf("commitCandidates: syncing of state index %v is stopped", block.BlockIndex())
 	}
 
-	// TODO: maybe commit in 10 (or some const) block batches?
-	//      This would save from large commits and huge memory usage to store blocks
-
 	// invalidate solid state.
 	// - If any VM task is running with the assumption of the previous state, it is obsolete and will self-cancel
 	// - any view call will return 'state invalidated message'
@@ -202,10 +314,18 @@ func (sm *stateManager) commitCandidates(candidates []*candidateBlock) {
 			sm.log.Panicf("Terminating WASP, no space left on disc.")
 		}
 		sm.syncingBlocks.restartSyncing()
-		return
+		return false
 	}
 	sm.solidState = calculatedState
 	sm.chain.GlobalStateSync().SetSolidIndex(sm.solidState.BlockIndex())
 
 	sm.log.Debugf("commitCandidates: committing of block indices from %v to %v was successful", from, to)
+	return true
+}
+
+// candidateInvalid lowers the score of the peer, which has sent the block
+func (sm *stateManager) candidateInvalid(candidate *candidateBlock) {
+	if sender := candidate.getSender(); sender != nil {
+		sm.syncingBlocks.blockInvalid(sender)
+	}
 }
@@ -13,6 +13,8 @@ type StateManagerTimers struct {
 	// how long delay state pull after state candidate received
 	PullStateAfterStateCandidateDelay time.Duration
 	GetBlockRetry                     time.Duration
+	// how long fast sync waits for a trie node before switching to the newest state output
+	FastSyncRetarget time.Duration
 }
 
 func NewStateManagerTimers() StateManagerTimers {
@@ -20,5 +22,6 @@ func NewStateManagerTimers() StateManagerTimers {
 		PullStateRetry:                    1 * time.Second,
 		PullStateAfterStateCandidateDelay: 1 * time.Second,
 		GetBlockRetry:                     3 * time.Second,
+		FastSyncRetarget:                  30 * time.Second,
 	}
 }
@@ -16,7 +16,9 @@ import (
 	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
 	"github.com/iotaledger/wasp/packages/wal"
 	"golang.org/x/xerrors"
 )
@@ -40,6 +42,8 @@ type Chains struct {
 	offledgerBroadcastUpToNPeers     int
 	offledgerBroadcastInterval       time.Duration
 	pullMissingRequestsFromCommittee bool
+	evmIndexEnabled                  bool
+	vmWorkers                        int
 	networkProvider                  peering.NetworkProvider
 	getOrCreateKVStore               dbmanager.ChainKVStoreProvider
 }
@@ -50,6 +54,8 @@ func New(
 	offledgerBroadcastUpToNPeers int,
 	offledgerBroadcastInterval time.Duration,
 	pullMissingRequestsFromCommittee bool,
+	evmIndexEnabled bool,
+	vmWorkers int,
 	networkProvider peering.NetworkProvider,
 	getOrCreateKVStore dbmanager.ChainKVStoreProvider,
 ) *Chains {
@@ -60,6 +66,8 @@ func New(
 		offledgerBroadcastUpToNPeers:     offledgerBroadcastUpToNPeers,
 		offledgerBroadcastInterval:       offledgerBroadcastInterval,
 		pullMissingRequestsFromCommittee: pullMissingRequestsFromCommittee,
+		evmIndexEnabled:                  evmIndexEnabled,
+		vmWorkers:                        vmWorkers,
 		networkProvider:                  networkProvider,
 		getOrCreateKVStore:               getOrCreateKVStore,
 	}
@@ -145,6 +153,8 @@ func (c *Chains) Activate(chr *registry.ChainRecord, registryProvider registry.P
 		chainMetrics,
 		defaultRegistry,
 		chainWAL,
+		c.evmIndexEnabled,
+		c.newVMRunner(),
 	)
 	if newChain == nil {
 		return xerrors.New("Chains.Activate: failed to create chain object")
@@ -190,3 +200,12 @@ func (c *Chains) Get(chainID *isc.ChainID, includeDeactivated ...bool) chain.Cha
 func (c *Chains) GetNodeConnectionMetrics() nodeconnmetrics.NodeConnectionMetrics {
 	return c.nodeConn.GetMetrics()
 }
+
+// newVMRunner returns the parallel VM runner, when more than one worker is
+// configured, and the sequential one otherwise
+func (c *Chains) newVMRunner() vm.VMRunner {
+	if c.vmWorkers > 1 {
+		return runvm.NewParallelVMRunner(c.vmWorkers)
+	}
+	return runvm.NewVMRunner()
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/iotaledger/wasp/packages/vm/core/coreprocessors"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBasic(t *testing.T) {
@@ -20,5 +22,15 @@ func TestBasic(t *testing.T) {
 		return mapdb.NewMapDB()
 	}
 
-	_ = New(logger, coreprocessors.Config(), 10, time.Second, false, nil, getOrCreateKVStore)
+	_ = New(logger, coreprocessors.Config(), 10, time.Second, false, false, 0, nil, getOrCreateKVStore)
+}
+
+func TestVMRunner(t *testing.T) {
+	logger := testlogger.NewLogger(t)
+	for _, workers := range []int{0, 1} {
+		c := New(logger, coreprocessors.Config(), 10, time.Second, false, false, workers, nil, nil)
+		require.Equal(t, runvm.NewVMRunner(), c.newVMRunner())
+	}
+	c := New(logger, coreprocessors.Config(), 10, time.Second, false, false, 4, nil, nil)
+	require.Equal(t, runvm.NewParallelVMRunner(4), c.newVMRunner())
 }
@@ -0,0 +1,78 @@
+package cryptolib
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/xerrors"
+)
+
+// HardenedIndex is the offset of the hardened indices of a BIP-32 derivation path
+const HardenedIndex = 0x80000000
+
+// the SLIP-44 coin types of the IOTA networks
+const (
+	CoinTypeIOTA    = 4218
+	CoinTypeShimmer = 4219
+)
+
+// NewMnemonic generates a new random 24 words BIP-39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// SeedFromMnemonic validates the BIP-39 mnemonic and returns its 64 bytes seed, without passphrase
+func SeedFromMnemonic(mnemonic string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, xerrors.New("invalid mnemonic")
+	}
+	return bip39.NewSeedWithErrorChecking(mnemonic, "")
+}
+
+// IOTADerivationPath returns the BIP-44 path m/44'/coinType'/account'/0'/index' of the Ed25519 addresses
+// used by the IOTA wallets
+func IOTADerivationPath(coinType, account, index uint32) []uint32 {
+	return []uint32{
+		44 | HardenedIndex,
+		coinType | HardenedIndex,
+		account | HardenedIndex,
+		0 | HardenedIndex,
+		index | HardenedIndex,
+	}
+}
+
+// NewKeyPairFromMnemonicSeed derives the Ed25519 key pair of the path from the BIP-39 seed, as specified by SLIP-10.
+// Ed25519 only supports hardened derivation, all indices of the path must be hardened
+func NewKeyPairFromMnemonicSeed(seed []byte, path []uint32) (*KeyPair, error) {
+	key, chainCode := slip10Ed25519Key(seed)
+	for _, index := range path {
+		if index < HardenedIndex {
+			return nil, xerrors.Errorf("Ed25519 keys cannot be derived with the non-hardened index %d", index)
+		}
+		var data [1 + SeedSize + 4]byte
+		copy(data[1:], key)
+		binary.BigEndian.PutUint32(data[1+SeedSize:], index)
+		key, chainCode = HMACSHA512(chainCode, data[:])
+	}
+	return NewKeyPairFromSeed(NewSeedFromBytes(key)), nil
+}
+
+func slip10Ed25519Key(seed []byte) (key, chainCode []byte) {
+	return HMACSHA512([]byte("ed25519 seed"), seed)
+}
+
+// HMACSHA512 returns the two halves of the HMAC-SHA512 of the data, as used by the BIP-32 and SLIP-10 key derivations
+func HMACSHA512(key, data []byte) (left, right []byte) {
+	h := hmac.New(sha512.New, key)
+	h.Write(data)
+	sum := h.Sum(nil)
+	return sum[:32], sum[32:]
+}
@@ -0,0 +1,36 @@
+package cryptolib
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyPairFromMnemonicSeed(t *testing.T) {
+	// test vector 1 for ed25519 of SLIP-10
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	kp, err := NewKeyPairFromMnemonicSeed(seed, nil)
+	require.NoError(t, err)
+	require.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", hex.EncodeToString(kp.GetPrivateKey().AsStdKey().Seed()))
+
+	kp, err = NewKeyPairFromMnemonicSeed(seed, []uint32{0 | HardenedIndex})
+	require.NoError(t, err)
+	require.Equal(t, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", hex.EncodeToString(kp.GetPrivateKey().AsStdKey().Seed()))
+
+	_, err = NewKeyPairFromMnemonicSeed(seed, []uint32{0})
+	require.Error(t, err)
+}
+
+func TestSeedFromMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+	seed, err := SeedFromMnemonic(mnemonic)
+	require.NoError(t, err)
+	require.Len(t, seed, 64)
+
+	_, err = SeedFromMnemonic("test test test test test test test test test test test test")
+	require.Error(t, err)
+}
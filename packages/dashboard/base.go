@@ -56,6 +56,7 @@ type WaspServices interface {
 	GetNodeConnectionMetrics() (nodeconnmetrics.NodeConnectionMetrics, error)
 	GetChainConsensusWorkflowStatus(*isc.ChainID) (chain.ConsensusWorkflowStatus, error)
 	GetChainConsensusPipeMetrics(*isc.ChainID) (chain.ConsensusPipeMetrics, error)
+	GetChainSyncInfo(*isc.ChainID) (*chain.SyncInfo, error)
 }
 
 type Dashboard struct {
@@ -8,7 +8,9 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
@@ -53,7 +55,7 @@ func (d *Dashboard) handleChain(c echo.Context) error {
 	}
 
 	if result.Record != nil && result.Record.Active {
-		result.LatestBlock, err = d.getLatestBlock(chainID)
+		result.SyncInfo, err = d.wasp.GetChainSyncInfo(chainID)
 		if err != nil {
 			return err
 		}
@@ -62,6 +64,14 @@ func (d *Dashboard) handleChain(c echo.Context) error {
 		if err != nil {
 			return err
 		}
+	}
+
+	// the state can't be queried, while it is being downloaded by fast sync
+	if result.Record != nil && result.Record.Active && (result.SyncInfo == nil || result.SyncInfo.FastSync == nil) {
+		result.LatestBlock, err = d.getLatestBlock(chainID)
+		if err != nil {
+			return err
+		}
 
 		result.ChainInfo, err = d.fetchChainInfo(chainID)
 		if err != nil {
@@ -109,18 +119,17 @@ func (d *Dashboard) getLatestBlock(chainID *isc.ChainID) (*LatestBlock, error) {
 }
 
 func (d *Dashboard) fetchAccounts(chainID *isc.ChainID) ([]isc.AgentID, error) {
-	accs, err := d.wasp.CallView(chainID, accounts.Contract.Name, accounts.ViewAccounts.Name, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	ret := make([]isc.AgentID, 0)
-	for k := range accs {
-		agentid, err := codec.DecodeAgentID([]byte(k))
+	err := d.callViewAllPages(chainID, accounts.Contract.Name, accounts.ViewAccounts.Name, nil, func(page dict.Dict) error {
+		accs, err := accounts.DecodeAccounts(page)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		ret = append(ret, agentid)
+		ret = append(ret, accs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return ret, nil
 }
@@ -134,11 +143,38 @@ func (d *Dashboard) fetchTotalAssets(chainID *isc.ChainID) (*isc.FungibleTokens,
 }
 
 func (d *Dashboard) fetchBlobs(chainID *isc.ChainID) (map[hashing.HashValue]uint32, error) {
-	ret, err := d.wasp.CallView(chainID, blob.Contract.Name, blob.ViewListBlobs.Name, nil)
+	ret := make(map[hashing.HashValue]uint32)
+	err := d.callViewAllPages(chainID, blob.Contract.Name, blob.ViewListBlobs.Name, nil, func(page dict.Dict) error {
+		blobs, err := blob.DecodeDirectory(page)
+		if err != nil {
+			return err
+		}
+		for h, size := range blobs {
+			ret[h] = size
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return blob.DecodeDirectory(ret)
+	return ret, nil
+}
+
+// callViewAllPages calls a list-style core view page by page, until there are no more elements
+func (d *Dashboard) callViewAllPages(chainID *isc.ChainID, scName, fname string, params dict.Dict, f func(page dict.Dict) error) error {
+	var cursor []byte
+	for {
+		page, err := d.wasp.CallView(chainID, scName, fname, coreutil.PageParams(params, cursor, 0))
+		if err != nil {
+			return err
+		}
+		if err := f(page); err != nil {
+			return err
+		}
+		if cursor = coreutil.NextPageCursor(page); cursor == nil {
+			return nil
+		}
+	}
 }
 
 func (d *Dashboard) fetchEVMChainID(chainID *isc.ChainID) (uint16, error) {
@@ -167,4 +203,5 @@ type ChainTemplateParams struct {
 	TotalAssets *isc.FungibleTokens
 	Blobs       map[hashing.HashValue]uint32
 	Committee   *chain.CommitteeInfo
+	SyncInfo    *chain.SyncInfo
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/labstack/echo/v4"
@@ -57,23 +58,24 @@ func (d *Dashboard) handleChainContract(c echo.Context) error {
 		return fmt.Errorf("cannot decode contract record: %v", err)
 	}
 
-	r, err = d.wasp.CallView(chainID, blocklog.Contract.Name, blocklog.ViewGetEventsForContract.Name, codec.MakeDict(map[string]interface{}{
+	result.Log = make([]string, 0)
+	err = d.callViewAllPages(chainID, blocklog.Contract.Name, blocklog.ViewGetEventsForContract.Name, codec.MakeDict(map[string]interface{}{
 		blocklog.ParamContractHname: codec.EncodeHname(hname),
-	}))
+	}), func(page dict.Dict) error {
+		recs := collections.NewArray16ReadOnly(page, blocklog.ParamEvent)
+		for i := uint16(0); i < recs.MustLen(); i++ {
+			data, err := recs.GetAt(i)
+			if err != nil {
+				return err
+			}
+			result.Log = append(result.Log, string(data))
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("call view failed: %v", err)
 	}
 
-	recs := collections.NewArray16ReadOnly(r, blocklog.ParamEvent)
-	result.Log = make([]string, recs.MustLen())
-	for i := range result.Log {
-		data, err := recs.GetAt(uint16(i))
-		if err != nil {
-			return err
-		}
-		result.Log[i] = string(data)
-	}
-
 	return c.Render(http.StatusOK, c.Path(), result)
 }
 
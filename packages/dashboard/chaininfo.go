@@ -3,6 +3,7 @@ package dashboard
 import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 )
@@ -25,11 +26,17 @@ func (d *Dashboard) fetchChainInfo(chainID *isc.ChainID) (ret *ChainInfo, err er
 		return nil, err
 	}
 
-	recs, err := d.wasp.CallView(chainID, root.Contract.Name, root.ViewGetContractRecords.Name, nil)
-	if err != nil {
-		return
-	}
-	ret.Contracts, err = root.DecodeContractRegistry(collections.NewMapReadOnly(recs, root.StateVarContractRegistry))
+	ret.Contracts = make(map[isc.Hname]*root.ContractRecord)
+	err = d.callViewAllPages(chainID, root.Contract.Name, root.ViewGetContractRecords.Name, nil, func(page dict.Dict) error {
+		recs, err := root.DecodeContractRegistry(collections.NewMapReadOnly(page, root.StateVarContractRegistry))
+		if err != nil {
+			return err
+		}
+		for hname, rec := range recs {
+			ret.Contracts[hname] = rec
+		}
+		return nil
+	})
 	if err != nil {
 		return
 	}
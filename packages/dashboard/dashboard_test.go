@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/solo"
@@ -61,6 +62,23 @@ func TestDashboardChainView(t *testing.T) {
 	checkProperConversionsToString(t, html)
 }
 
+func TestDashboardChainViewFastSync(t *testing.T) {
+	env := initDashboardTest(t)
+	ch := env.newChain()
+	env.wasp.fastSync = &chain.FastSyncInfo{
+		TargetBlockIndex: 1000,
+		NodesDownloaded:  42,
+		NodesPending:     256,
+	}
+	html := testutil.CallHTMLRequestHandler(t, env.echo, env.dashboard.handleChain, "/chain/:chainid", map[string]string{
+		"chainid": ch.ChainID.String(),
+	})
+	require.Contains(t, html.Text(), "Fast sync")
+	require.Contains(t, html.Text(), "1000")
+	require.NotContains(t, html.Text(), "On-chain accounts")
+	checkProperConversionsToString(t, html)
+}
+
 func TestDashboardChainAccount(t *testing.T) {
 	env := initDashboardTest(t)
 	ch := env.newChain()
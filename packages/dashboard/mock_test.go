@@ -23,8 +23,9 @@ import (
 
 // waspServicesMock is a mock implementation of the WaspServices interface
 type waspServicesMock struct {
-	solo   *solo.Solo
-	chains map[[iotago.AliasIDLength]byte]*solo.Chain
+	solo     *solo.Solo
+	chains   map[[iotago.AliasIDLength]byte]*solo.Chain
+	fastSync *chain.FastSyncInfo
 }
 
 var _ WaspServices = &waspServicesMock{}
@@ -160,6 +161,20 @@ func (w *waspServicesMock) GetChainConsensusPipeMetrics(chainID *isc.ChainID) (c
 	panic("Not implemented")
 }
 
+func (w *waspServicesMock) GetChainSyncInfo(chainID *isc.ChainID) (*chain.SyncInfo, error) {
+	ch, ok := w.chains[*chainID]
+	if !ok {
+		return nil, xerrors.Errorf("chain not found")
+	}
+	if w.fastSync != nil {
+		return &chain.SyncInfo{FastSync: w.fastSync}, nil
+	}
+	return &chain.SyncInfo{
+		Synced:           true,
+		SyncedBlockIndex: ch.GetLatestBlockInfo().BlockIndex,
+	}, nil
+}
+
 type dashboardTestEnv struct {
 	wasp      *waspServicesMock
 	echo      *echo.Echo
@@ -17,6 +17,9 @@ const (
 	ObjectTypeBlobCacheTTL
 	ObjectTypeTrustedPeer
 	ObjectTypeConsensusJournal
+	ObjectTypeEVMIndex
+	ObjectTypeFastSync
+	ObjectTypeAPIKey
 )
 
 // MakeKey makes key within the partition. It consists of one byte for object type
@@ -19,11 +19,10 @@ import (
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/share"
 	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
-	rabin_vss "go.dedis.ch/kyber/v3/share/vss/rabin"
 	"golang.org/x/xerrors"
 )
 
@@ -66,6 +65,17 @@ const (
 	// NOTE: There is not enough bits to encode KeySetType and Echo flags as bits.
 	rabinKeySetTypeFrom = rabinEchoTill
 	rabinKeySetTypeTill = rabinKeySetTypeFrom + (rabinEchoTill - rabinMsgFrom)
+	//
+	// Initiator <-> Peer proc communication, specific to the key resharing.
+	initiatorReshareMsgFrom      = rabinKeySetTypeTill
+	initiatorReshareDealsMsgType = initiatorReshareMsgFrom + 0 // Peer -> Initiator; dealers, whose deals were accepted by the peer.
+	initiatorReshareQualMsgType  = initiatorReshareMsgFrom + 1 // Initiator -> Peer: dealers to use for the new shares, reply with initiatorPubShareMsgType.
+	initiatorReshareMsgTill      = initiatorReshareMsgFrom + 2 // Just a placeholder for first unallocated message type.
+	//
+	// Peer <-> Peer communication for the key resharing. The Rabin message types
+	// are reused here to have the same routing and echo handling for the rounds.
+	// The payload is interpreted according to the kind of the procedure.
+	reshareDealMsgType = rabinDealMsgType
 )
 
 type keySetType byte
@@ -89,7 +99,7 @@ func isDkgInitProcMsg(msgType byte) bool { //nolint:unused,deadcode
 
 // Check if that's a Initiator -> PeerProc message.
 func isDkgInitProcRecvMsg(msgType byte) bool {
-	return msgType == initiatorStepMsgType || msgType == initiatorDoneMsgType
+	return msgType == initiatorStepMsgType || msgType == initiatorDoneMsgType || msgType == initiatorReshareQualMsgType
 }
 
 // isDkgRabinRoundMsg detects, if the received MsgType is RabinDKG Peer <-> Peer message type and splits it into components.
@@ -187,7 +197,7 @@ func readInitiatorMsg(peerMessage *peering.PeerMessageData, edSuite, blsSuite ky
 	switch peerMessage.MsgType {
 	case initiatorInitMsgType:
 		msg := initiatorInitMsg{}
-		if err := msg.fromBytes(peerMessage.MsgData); err != nil {
+		if err := msg.fromBytes(peerMessage.MsgData, edSuite, blsSuite); err != nil {
 			return true, nil, err
 		}
 		return true, &msg, nil
@@ -215,6 +225,18 @@ func readInitiatorMsg(peerMessage *peering.PeerMessageData, edSuite, blsSuite ky
 			return true, nil, err
 		}
 		return true, &msg, nil
+	case initiatorReshareDealsMsgType:
+		msg := initiatorReshareDealsMsg{}
+		if err := msg.fromBytes(peerMessage.MsgData); err != nil {
+			return true, nil, err
+		}
+		return true, &msg, nil
+	case initiatorReshareQualMsgType:
+		msg := initiatorReshareQualMsg{}
+		if err := msg.fromBytes(peerMessage.MsgData); err != nil {
+			return true, nil, err
+		}
+		return true, &msg, nil
 	default:
 		return false, nil, nil
 	}
@@ -223,16 +245,25 @@ func readInitiatorMsg(peerMessage *peering.PeerMessageData, edSuite, blsSuite ky
 // initiatorInitMsg
 //
 // This is a message sent by the initiator to all the peers to
-// initiate the DKG process.
+// initiate the DKG process. If the sharedAddress is set, an existing
+// key is reshared from the oldPeerPubs to the peerPubs instead of
+// generating a new one.
 type initiatorInitMsg struct {
-	step         byte
-	dkgRef       string // Some unique string to identify duplicate initialization.
-	peeringID    peering.PeeringID
-	peerPubs     []*cryptolib.PublicKey
-	initiatorPub *cryptolib.PublicKey
-	threshold    uint16
-	timeout      time.Duration
-	roundRetry   time.Duration
+	step            byte
+	dkgRef          string // Some unique string to identify duplicate initialization.
+	peeringID       peering.PeeringID
+	peerPubs        []*cryptolib.PublicKey
+	initiatorPub    *cryptolib.PublicKey
+	threshold       uint16
+	timeout         time.Duration
+	roundRetry      time.Duration
+	sharedAddress   iotago.Address         // Only for resharing: the address of the key to reshare.
+	oldPeerPubs     []*cryptolib.PublicKey // Only for resharing: the current holders of the key shares.
+	oldThreshold    uint16                 // Only for resharing: the current threshold.
+	edOldPubShares  []kyber.Point          // Only for resharing: the current public shares.
+	blsOldPubShares []kyber.Point          // Only for resharing: the current public shares.
+	edSuite         kyber.Group            // Transient, for un-marshaling only.
+	blsSuite        kyber.Group            // Transient, for un-marshaling only.
 }
 
 type initiatorInitMsgIn struct {
@@ -281,7 +312,33 @@ func (m *initiatorInitMsg) Write(w io.Writer) error {
 	if err = util.WriteInt64(w, m.timeout.Milliseconds()); err != nil {
 		return err
 	}
-	return util.WriteInt64(w, m.roundRetry.Milliseconds())
+	if err = util.WriteInt64(w, m.roundRetry.Milliseconds()); err != nil {
+		return err
+	}
+	if err = util.WriteBoolByte(w, m.isReshare()); err != nil {
+		return err
+	}
+	if !m.isReshare() {
+		return nil
+	}
+	if err = util.WriteBytes16(w, isc.BytesFromAddress(m.sharedAddress)); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, uint16(len(m.oldPeerPubs))); err != nil {
+		return err
+	}
+	for i := range m.oldPeerPubs {
+		if err = util.WriteBytes16(w, m.oldPeerPubs[i].AsBytes()); err != nil {
+			return err
+		}
+	}
+	if err = util.WriteUint16(w, m.oldThreshold); err != nil {
+		return err
+	}
+	if err = tcrypto.WritePoints(w, m.edOldPubShares); err != nil {
+		return err
+	}
+	return tcrypto.WritePoints(w, m.blsOldPubShares)
 }
 
 //nolint:gocritic
@@ -339,14 +396,57 @@ func (m *initiatorInitMsg) Read(r io.Reader) error {
 		return err
 	}
 	m.roundRetry = time.Duration(roundRetryMS) * time.Millisecond
+	var isReshare bool
+	if err = util.ReadBoolByte(r, &isReshare); err != nil {
+		return err
+	}
+	if !isReshare {
+		return nil
+	}
+	var sharedAddressBin []byte
+	if sharedAddressBin, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	if m.sharedAddress, _, err = isc.AddressFromBytes(sharedAddressBin); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &arrLen); err != nil {
+		return err
+	}
+	m.oldPeerPubs = make([]*cryptolib.PublicKey, arrLen)
+	for i := range m.oldPeerPubs {
+		var oldPeerPubBytes []byte
+		if oldPeerPubBytes, err = util.ReadBytes16(r); err != nil {
+			return err
+		}
+		if m.oldPeerPubs[i], err = cryptolib.NewPublicKeyFromBytes(oldPeerPubBytes); err != nil {
+			return err
+		}
+	}
+	if err = util.ReadUint16(r, &m.oldThreshold); err != nil {
+		return err
+	}
+	if m.edOldPubShares, err = tcrypto.ReadPoints(r, m.edSuite); err != nil {
+		return xerrors.Errorf("failed to unmarshal initiatorInitMsg.edOldPubShares: %w", err)
+	}
+	if m.blsOldPubShares, err = tcrypto.ReadPoints(r, m.blsSuite); err != nil {
+		return xerrors.Errorf("failed to unmarshal initiatorInitMsg.blsOldPubShares: %w", err)
+	}
 	return nil
 }
 
-func (m *initiatorInitMsg) fromBytes(buf []byte) error {
+func (m *initiatorInitMsg) fromBytes(buf []byte, edSuite, blsSuite kyber.Group) error {
 	r := bytes.NewReader(buf)
+	m.edSuite = edSuite
+	m.blsSuite = blsSuite
 	return m.Read(r)
 }
 
+// isReshare returns true, if this message initiates resharing of an existing key.
+func (m *initiatorInitMsg) isReshare() bool {
+	return m.sharedAddress != nil
+}
+
 func (m *initiatorInitMsg) Error() error {
 	return nil
 }
@@ -679,75 +779,151 @@ func (m *initiatorStatusMsg) IsResponse() bool {
 	return true
 }
 
-// rabin_dkg.Deal
-type rabinDealMsg struct {
-	step byte
-	deal *rabin_dkg.Deal
+// initiatorReshareDealsMsg
+//
+// This is a message responded to the initiator by the peers after
+// receiving the resharing deals. It lists the dealers (by their
+// index in the old group), whose deals were verified successfully.
+type initiatorReshareDealsMsg struct {
+	step    byte
+	dealers []uint16
 }
 
-func (m *rabinDealMsg) MsgType() byte {
-	return rabinDealMsgType
+func (m *initiatorReshareDealsMsg) MsgType() byte {
+	return initiatorReshareDealsMsgType
 }
 
-func (m *rabinDealMsg) Step() byte {
+func (m *initiatorReshareDealsMsg) Step() byte {
 	return m.step
 }
 
-func (m *rabinDealMsg) SetStep(step byte) {
+func (m *initiatorReshareDealsMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
-func (m *rabinDealMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
-		return err
-	}
-	if err = util.WriteUint32(w, m.deal.Index); err != nil {
+func (m *initiatorReshareDealsMsg) Write(w io.Writer) error {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	if err = util.WriteMarshaled(w, m.deal.Deal.DHKey); err != nil {
+	return writeIndexes(w, m.dealers)
+}
+
+func (m *initiatorReshareDealsMsg) Read(r io.Reader) error {
+	var err error
+	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	if err = util.WriteBytes16(w, m.deal.Deal.Signature); err != nil {
+	if m.dealers, err = readIndexes(r); err != nil {
 		return err
 	}
-	if err = util.WriteBytes16(w, m.deal.Deal.Nonce); err != nil {
+	return nil
+}
+
+func (m *initiatorReshareDealsMsg) fromBytes(buf []byte) error {
+	r := bytes.NewReader(buf)
+	return m.Read(r)
+}
+
+func (m *initiatorReshareDealsMsg) Error() error {
+	return nil
+}
+
+func (m *initiatorReshareDealsMsg) IsResponse() bool {
+	return true
+}
+
+// initiatorReshareQualMsg
+//
+// This is a message sent by the initiator to all the peers to
+// fix the set of dealers, which deals have to be combined to get
+// the new key shares. All the nodes must use the same set.
+type initiatorReshareQualMsg struct {
+	step    byte
+	dealers []uint16
+}
+
+func (m *initiatorReshareQualMsg) MsgType() byte {
+	return initiatorReshareQualMsgType
+}
+
+func (m *initiatorReshareQualMsg) Step() byte {
+	return m.step
+}
+
+func (m *initiatorReshareQualMsg) SetStep(step byte) {
+	m.step = step
+}
+
+func (m *initiatorReshareQualMsg) Write(w io.Writer) error {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	return util.WriteBytes16(w, m.deal.Deal.Cipher)
+	return writeIndexes(w, m.dealers)
 }
 
-//nolint:gocritic
-func (m *rabinDealMsg) Read(r io.Reader) error {
+func (m *initiatorReshareQualMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	if err = util.ReadUint32(r, &m.deal.Index); err != nil {
-		return err
-	}
-	if err = util.ReadMarshaled(r, m.deal.Deal.DHKey); err != nil {
+	if m.dealers, err = readIndexes(r); err != nil {
 		return err
 	}
-	if m.deal.Deal.Signature, err = util.ReadBytes16(r); err != nil {
+	return nil
+}
+
+func (m *initiatorReshareQualMsg) fromBytes(buf []byte) error {
+	r := bytes.NewReader(buf)
+	return m.Read(r)
+}
+
+func (m *initiatorReshareQualMsg) Error() error {
+	return nil
+}
+
+func (m *initiatorReshareQualMsg) IsResponse() bool {
+	return false
+}
+
+// rabin_dkg.Deal
+type rabinDealMsg struct {
+	step    byte
+	deal    *rabin_dkg.Deal
+	edSuite kyber.Group // Just for un-marshaling.
+}
+
+func (m *rabinDealMsg) MsgType() byte {
+	return rabinDealMsgType
+}
+
+func (m *rabinDealMsg) Step() byte {
+	return m.step
+}
+
+func (m *rabinDealMsg) SetStep(step byte) {
+	m.step = step
+}
+
+func (m *rabinDealMsg) Write(w io.Writer) error {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	if m.deal.Deal.Nonce, err = util.ReadBytes16(r); err != nil {
+	return tcrypto.WriteRabinDeal(w, m.deal)
+}
+
+func (m *rabinDealMsg) Read(r io.Reader) error {
+	var err error
+	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	if m.deal.Deal.Cipher, err = util.ReadBytes16(r); err != nil {
+	if m.deal, err = tcrypto.ReadRabinDeal(r, m.edSuite); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (m *rabinDealMsg) fromBytes(buf []byte, edSuite kyber.Group) error {
-	m.deal = &rabin_dkg.Deal{
-		Deal: &rabin_vss.EncryptedDeal{
-			DHKey: edSuite.Point(),
-		},
-	}
+	m.edSuite = edSuite
 	rdr := bytes.NewReader(buf)
 	return m.Read(rdr)
 }
@@ -770,68 +946,21 @@ func (m *rabinResponseMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
 func (m *rabinResponseMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
-		return err
-	}
-	listLen := uint32(len(m.responses))
-	if err = util.WriteUint32(w, listLen); err != nil {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	for _, r := range m.responses {
-		if err = util.WriteUint32(w, r.Index); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, r.Response.SessionID); err != nil {
-			return err
-		}
-		if err = util.WriteUint32(w, r.Response.Index); err != nil {
-			return err
-		}
-		if err = util.WriteBoolByte(w, r.Response.Approved); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, r.Response.Signature); err != nil {
-			return err
-		}
-	}
-	return nil
+	return tcrypto.WriteRabinResponses(w, m.responses)
 }
 
-//nolint:gocritic
 func (m *rabinResponseMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	var listLen uint32
-	if err = util.ReadUint32(r, &listLen); err != nil {
+	if m.responses, err = tcrypto.ReadRabinResponses(r); err != nil {
 		return err
 	}
-	m.responses = make([]*rabin_dkg.Response, int(listLen))
-	for i := range m.responses {
-		response := rabin_dkg.Response{
-			Response: &rabin_vss.Response{},
-		}
-		m.responses[i] = &response
-		if err = util.ReadUint32(r, &response.Index); err != nil {
-			return err
-		}
-		if response.Response.SessionID, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-		if err = util.ReadUint32(r, &response.Response.Index); err != nil {
-			return err
-		}
-		if err = util.ReadBoolByte(r, &response.Response.Approved); err != nil {
-			return err
-		}
-		if response.Response.Signature, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
@@ -859,68 +988,21 @@ func (m *rabinJustificationMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
 func (m *rabinJustificationMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
-		return err
-	}
-	jLen := uint32(len(m.justifications))
-	if err = util.WriteUint32(w, jLen); err != nil {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	for _, j := range m.justifications {
-		if err = util.WriteUint32(w, j.Index); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, j.Justification.SessionID); err != nil {
-			return err
-		}
-		if err = util.WriteUint32(w, j.Justification.Index); err != nil {
-			return err
-		}
-		if err = writeVssDeal(w, j.Justification.Deal); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, j.Justification.Signature); err != nil {
-			return err
-		}
-	}
-	return nil
+	return tcrypto.WriteRabinJustifications(w, m.justifications)
 }
 
-//nolint:gocritic
 func (m *rabinJustificationMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	var jLen uint32
-	if err = util.ReadUint32(r, &jLen); err != nil {
+	if m.justifications, err = tcrypto.ReadRabinJustifications(r, m.blsSuite); err != nil {
 		return err
 	}
-	m.justifications = make([]*rabin_dkg.Justification, int(jLen))
-	for i := range m.justifications {
-		j := rabin_dkg.Justification{
-			Justification: &rabin_vss.Justification{},
-		}
-		m.justifications[i] = &j
-		if err = util.ReadUint32(r, &j.Index); err != nil {
-			return err
-		}
-		if j.Justification.SessionID, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-		if err = util.ReadUint32(r, &j.Justification.Index); err != nil {
-			return err
-		}
-		if err = readVssDeal(r, &j.Justification.Deal, m.blsSuite); err != nil {
-			return err
-		}
-		if j.Justification.Signature, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
@@ -949,68 +1031,19 @@ func (m *rabinSecretCommitsMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
 func (m *rabinSecretCommitsMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
-		return err
-	}
-	if err = util.WriteBoolByte(w, m.secretCommits == nil); err != nil {
-		return err
-	}
-	if m.secretCommits == nil {
-		return nil
-	}
-	if err = util.WriteUint32(w, m.secretCommits.Index); err != nil {
-		return err
-	}
-	if err = util.WriteUint32(w, uint32(len(m.secretCommits.Commitments))); err != nil {
-		return err
-	}
-	for i := range m.secretCommits.Commitments {
-		if err = util.WriteMarshaled(w, m.secretCommits.Commitments[i]); err != nil {
-			return err
-		}
-	}
-	if err = util.WriteBytes16(w, m.secretCommits.SessionID); err != nil {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	return util.WriteBytes16(w, m.secretCommits.Signature)
+	return tcrypto.WriteRabinSecretCommits(w, m.secretCommits)
 }
 
-//nolint:gocritic
 func (m *rabinSecretCommitsMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	var isNil bool
-	if err = util.ReadBoolByte(r, &isNil); err != nil {
-		return err
-	}
-	if isNil {
-		m.secretCommits = nil
-		return nil
-	}
-	m.secretCommits = &rabin_dkg.SecretCommits{}
-	if err = util.ReadUint32(r, &m.secretCommits.Index); err != nil {
-		return err
-	}
-	var cLen uint32
-	if err = util.ReadUint32(r, &cLen); err != nil {
-		return err
-	}
-	m.secretCommits.Commitments = make([]kyber.Point, cLen)
-	for i := range m.secretCommits.Commitments {
-		m.secretCommits.Commitments[i] = m.blsSuite.Point()
-		if err = util.ReadMarshaled(r, m.secretCommits.Commitments[i]); err != nil {
-			return err
-		}
-	}
-	if m.secretCommits.SessionID, err = util.ReadBytes16(r); err != nil {
-		return err
-	}
-	if m.secretCommits.Signature, err = util.ReadBytes16(r); err != nil {
+	if m.secretCommits, err = tcrypto.ReadRabinSecretCommits(r, m.blsSuite); err != nil {
 		return err
 	}
 	return nil
@@ -1041,58 +1074,21 @@ func (m *rabinComplaintCommitsMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
 func (m *rabinComplaintCommitsMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
-		return err
-	}
-	if err = util.WriteUint32(w, uint32(len(m.complaintCommits))); err != nil {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	for i := range m.complaintCommits {
-		if err = util.WriteUint32(w, m.complaintCommits[i].Index); err != nil {
-			return err
-		}
-		if err = util.WriteUint32(w, m.complaintCommits[i].DealerIndex); err != nil {
-			return err
-		}
-		if err = writeVssDeal(w, m.complaintCommits[i].Deal); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, m.complaintCommits[i].Signature); err != nil {
-			return err
-		}
-	}
-	return nil
+	return tcrypto.WriteRabinComplaintCommits(w, m.complaintCommits)
 }
 
-//nolint:gocritic
 func (m *rabinComplaintCommitsMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	var ccLen uint32
-	if err = util.ReadUint32(r, &ccLen); err != nil {
+	if m.complaintCommits, err = tcrypto.ReadRabinComplaintCommits(r, m.blsSuite); err != nil {
 		return err
 	}
-	m.complaintCommits = make([]*rabin_dkg.ComplaintCommits, ccLen)
-	for i := range m.complaintCommits {
-		m.complaintCommits[i] = &rabin_dkg.ComplaintCommits{}
-		if err = util.ReadUint32(r, &m.complaintCommits[i].Index); err != nil {
-			return err
-		}
-		if err = util.ReadUint32(r, &m.complaintCommits[i].DealerIndex); err != nil {
-			return err
-		}
-		if err = readVssDeal(r, &m.complaintCommits[i].Deal, m.blsSuite); err != nil {
-			return err
-		}
-		if m.complaintCommits[i].Signature, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
@@ -1106,6 +1102,7 @@ func (m *rabinComplaintCommitsMsg) fromBytes(buf []byte, blsSuite kyber.Group) e
 type rabinReconstructCommitsMsg struct {
 	step               byte
 	reconstructCommits []*rabin_dkg.ReconstructCommits
+	blsSuite           kyber.Group // Just for un-marshaling.
 }
 
 func (m *rabinReconstructCommitsMsg) MsgType() byte {
@@ -1120,72 +1117,89 @@ func (m *rabinReconstructCommitsMsg) SetStep(step byte) {
 	m.step = step
 }
 
-//nolint:gocritic
 func (m *rabinReconstructCommitsMsg) Write(w io.Writer) error {
-	var err error
-	if err = util.WriteByte(w, m.step); err != nil {
+	if err := util.WriteByte(w, m.step); err != nil {
 		return err
 	}
-	if err = util.WriteUint32(w, uint32(len(m.reconstructCommits))); err != nil {
+	return tcrypto.WriteRabinReconstructCommits(w, m.reconstructCommits)
+}
+
+func (m *rabinReconstructCommitsMsg) Read(r io.Reader) error {
+	var err error
+	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	for i := range m.reconstructCommits {
-		if err = util.WriteBytes16(w, m.reconstructCommits[i].SessionID); err != nil {
-			return err
-		}
-		if err = util.WriteUint32(w, m.reconstructCommits[i].Index); err != nil {
-			return err
-		}
-		if err = util.WriteUint32(w, m.reconstructCommits[i].DealerIndex); err != nil {
-			return err
-		}
-		if err = writePriShare(w, m.reconstructCommits[i].Share); err != nil {
-			return err
-		}
-		if err = util.WriteBytes16(w, m.reconstructCommits[i].Signature); err != nil {
-			return err
-		}
+	if m.reconstructCommits, err = tcrypto.ReadRabinReconstructCommits(r, m.blsSuite); err != nil {
+		return err
 	}
 	return nil
 }
 
-//nolint:gocritic
-func (m *rabinReconstructCommitsMsg) Read(r io.Reader) error {
+func (m *rabinReconstructCommitsMsg) fromBytes(buf []byte, blsSuite kyber.Group) error {
+	m.blsSuite = blsSuite
+	rdr := bytes.NewReader(buf)
+	return m.Read(rdr)
+}
+
+// reshareDealMsg
+//
+// A deal sent by a member of the old group to a member of the new
+// group while resharing a key. The sub-share is encrypted with the
+// node key of the receiver. The commits are empty, if the sender
+// is not a dealer or the receiver is not in the new group.
+type reshareDealMsg struct {
+	step     byte
+	commits  []kyber.Point
+	subShare []byte      // Encrypted.
+	suite    kyber.Group // Transient, for un-marshaling only.
+}
+
+func (m *reshareDealMsg) MsgType() byte {
+	return reshareDealMsgType
+}
+
+func (m *reshareDealMsg) Step() byte {
+	return m.step
+}
+
+func (m *reshareDealMsg) SetStep(step byte) {
+	m.step = step
+}
+
+func (m *reshareDealMsg) Write(w io.Writer) error {
+	if err := util.WriteByte(w, m.step); err != nil {
+		return err
+	}
+	if err := tcrypto.WritePoints(w, m.commits); err != nil {
+		return err
+	}
+	return util.WriteBytes16(w, m.subShare)
+}
+
+func (m *reshareDealMsg) Read(r io.Reader) error {
 	var err error
 	if m.step, err = util.ReadByte(r); err != nil {
 		return err
 	}
-	var ccLen uint32
-	if err = util.ReadUint32(r, &ccLen); err != nil {
+	if m.commits, err = tcrypto.ReadPoints(r, m.suite); err != nil {
 		return err
 	}
-	m.reconstructCommits = make([]*rabin_dkg.ReconstructCommits, ccLen)
-	for i := range m.reconstructCommits {
-		m.reconstructCommits[i] = &rabin_dkg.ReconstructCommits{}
-		if m.reconstructCommits[i].SessionID, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
-		if err = util.ReadUint32(r, &m.reconstructCommits[i].Index); err != nil {
-			return err
-		}
-		if err = util.ReadUint32(r, &m.reconstructCommits[i].DealerIndex); err != nil {
-			return err
-		}
-		if err = readPriShare(r, &m.reconstructCommits[i].Share); err != nil {
-			return err
-		}
-		if m.reconstructCommits[i].Signature, err = util.ReadBytes16(r); err != nil {
-			return err
-		}
+	if m.subShare, err = util.ReadBytes16(r); err != nil {
+		return err
 	}
 	return nil
 }
 
-func (m *rabinReconstructCommitsMsg) fromBytes(buf []byte) error {
+func (m *reshareDealMsg) fromBytes(buf []byte, suite kyber.Group) error {
+	m.suite = suite
 	rdr := bytes.NewReader(buf)
 	return m.Read(rdr)
 }
 
+func (m *reshareDealMsg) isEmpty() bool {
+	return len(m.commits) == 0
+}
+
 // multiKeySetMsg wraps messages of different protocol instances (for different key set types).
 // It is needed to cope with the round synchronization.
 type multiKeySetMsg struct {
@@ -1314,104 +1328,28 @@ func (m multiKeySetMsgs) AddBLSMsgs(msgs map[uint16]*peering.PeerMessageData, st
 	}
 }
 
-//	type PriShare struct {
-//		I int          // Index of the private share
-//		V kyber.Scalar // Value of the private share
-//	}
-//
-//nolint:gocritic
-func writePriShare(w io.Writer, val *share.PriShare) error {
-	var err error
-	if err = util.WriteBoolByte(w, val == nil); err != nil {
-		return err
-	}
-	if val == nil {
-		return nil
-	}
-	if err = util.WriteUint32(w, uint32(val.I)); err != nil {
+func writeIndexes(w io.Writer, indexes []uint16) error {
+	if err := util.WriteUint16(w, uint16(len(indexes))); err != nil {
 		return err
 	}
-	return util.WriteMarshaled(w, val.V)
-}
-
-//nolint:gocritic
-func readPriShare(r io.Reader, val **share.PriShare) error {
-	var err error
-	var valNil bool
-	if err = util.ReadBoolByte(r, &valNil); err != nil {
-		return err
-	}
-	if valNil {
-		*val = nil
-	}
-	var i uint32
-	if err = util.ReadUint32(r, &i); err != nil {
-		return err
-	}
-	(*val).I = int(i)
-	return util.ReadMarshaled(r, (*val).V)
-}
-
-//	type rabin_vvs.Deal struct {
-//		SessionID []byte			// Unique session identifier for this protocol run
-//		SecShare *share.PriShare	// Private share generated by the dealer
-//		RndShare *share.PriShare	// Random share generated by the dealer
-//		T uint32					// Threshold used for this secret sharing run
-//		Commitments []kyber.Point	// Commitments are the coefficients used to verify the shares against
-//	}
-//
-//nolint:gocritic
-func writeVssDeal(w io.Writer, d *rabin_vss.Deal) error {
-	var err error
-	if err = util.WriteBytes16(w, d.SessionID); err != nil {
-		return err
-	}
-	if err = writePriShare(w, d.SecShare); err != nil {
-		return err
-	}
-	if err = writePriShare(w, d.RndShare); err != nil {
-		return err
-	}
-	if err = util.WriteUint32(w, d.T); err != nil {
-		return err
-	}
-	if err = util.WriteUint32(w, uint32(len(d.Commitments))); err != nil {
-		return err
-	}
-	for i := range d.Commitments {
-		if err = util.WriteMarshaled(w, d.Commitments[i]); err != nil {
+	for i := range indexes {
+		if err := util.WriteUint16(w, indexes[i]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func readVssDeal(r io.Reader, d **rabin_vss.Deal, blsSuite kyber.Group) error {
-	var err error
-	dd := rabin_vss.Deal{}
-	if dd.SessionID, err = util.ReadBytes16(r); err != nil {
-		return err
-	}
-	if err := readPriShare(r, &dd.SecShare); err != nil {
-		return err
-	}
-	if err := readPriShare(r, &dd.RndShare); err != nil {
-		return err
-	}
-	if err := util.ReadUint32(r, &dd.T); err != nil {
-		return err
-	}
-	var commitmentCount uint32
-	if err := util.ReadUint32(r, &commitmentCount); err != nil {
-		return err
+func readIndexes(r io.Reader) ([]uint16, error) {
+	var arrLen uint16
+	if err := util.ReadUint16(r, &arrLen); err != nil {
+		return nil, err
 	}
-	dd.Commitments = make([]kyber.Point, int(commitmentCount))
-	for i := range dd.Commitments {
-		dd.Commitments[i] = blsSuite.Point()
-		if err := util.ReadMarshaled(r, dd.Commitments[i]); err != nil {
-			return err
+	indexes := make([]uint16, arrLen)
+	for i := range indexes {
+		if err := util.ReadUint16(r, &indexes[i]); err != nil {
+			return nil, err
 		}
 	}
-	*d = &dd
-	return nil
+	return indexes, nil
 }
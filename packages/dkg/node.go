@@ -6,17 +6,18 @@ package dkg
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/peering"
-	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
-	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
@@ -27,40 +28,30 @@ type NodeProvider func() *Node
 // It receives commands from the initiator as a dkg.NodeProvider,
 // and communicates with other DKG nodes via the peering network.
 type Node struct {
-	identity     *cryptolib.KeyPair               // Keys of the current node.
-	secKey       kyber.Scalar                     // Derived from the identity.
-	pubKey       kyber.Point                      // Derived from the identity.
-	blsSuite     Suite                            // Cryptography to use for the Pairing based operations.
-	edSuite      suites.Suite                     // Cryptography to use for the Ed25519 based operations.
-	netProvider  peering.NetworkProvider          // Network to communicate through.
-	registry     registry.DKShareRegistryProvider // Where to store the generated keys.
-	processes    map[string]*proc                 // Only for introspection.
-	procLock     *sync.RWMutex                    // To guard access to the process pool.
-	initMsgQueue chan *initiatorInitMsgIn         // Incoming events processed async.
-	attachID     interface{}                      // Peering attach ID
+	signer       signer.Signer            // Holds the identity of the node and the generated keys.
+	blsSuite     Suite                    // Cryptography to use for the Pairing based operations.
+	edSuite      suites.Suite             // Cryptography to use for the Ed25519 based operations.
+	netProvider  peering.NetworkProvider  // Network to communicate through.
+	processes    map[string]*proc         // Only for introspection.
+	procLock     *sync.RWMutex            // To guard access to the process pool.
+	initMsgQueue chan *initiatorInitMsgIn // Incoming events processed async.
+	attachID     interface{}              // Peering attach ID
 	log          *logger.Logger
 }
 
 // Init creates new node, that can participate in the DKG procedure.
-// The node then can run several DKG procedures.
+// The node then can run several DKG procedures. The cryptographic part
+// of the procedures is performed by the signer, which also stores the keys.
 func NewNode(
-	identity *cryptolib.KeyPair,
+	nodeSigner signer.Signer,
 	netProvider peering.NetworkProvider,
-	reg registry.DKShareRegistryProvider,
 	log *logger.Logger,
 ) (*Node, error) {
-	kyberEdDSSA := eddsa.EdDSA{}
-	if err := kyberEdDSSA.UnmarshalBinary(identity.GetPrivateKey().AsBytes()); err != nil {
-		return nil, err
-	}
 	n := Node{
-		identity:     identity,
-		secKey:       kyberEdDSSA.Secret,
-		pubKey:       kyberEdDSSA.Public,
+		signer:       nodeSigner,
 		blsSuite:     tcrypto.DefaultBLSSuite(),
 		edSuite:      edwards25519.NewBlakeSHA256Ed25519(),
 		netProvider:  netProvider,
-		registry:     reg,
 		processes:    make(map[string]*proc),
 		procLock:     &sync.RWMutex{},
 		initMsgQueue: make(chan *initiatorInitMsgIn),
@@ -80,7 +71,7 @@ func (n *Node) receiveInitMessage(peerMsg *peering.PeerMessageIn) {
 		panic(fmt.Errorf("Wrong type of DKG init message: %v", peerMsg.MsgType))
 	}
 	msg := &initiatorInitMsg{}
-	if err := msg.fromBytes(peerMsg.MsgData); err != nil {
+	if err := msg.fromBytes(peerMsg.MsgData, n.edSuite, n.blsSuite); err != nil {
 		n.log.Warnf("Dropping unknown message: %v", peerMsg)
 		return
 	}
@@ -157,7 +148,7 @@ func (n *Node) GenerateDistributedKey(
 				dkgRef:       dkgID.String(), // It could be some other identifier.
 				peeringID:    dkgID,
 				peerPubs:     peerPubs,
-				initiatorPub: n.identity.GetPublicKey(),
+				initiatorPub: n.signer.GetPublicKey(),
 				threshold:    threshold,
 				timeout:      timeout,
 				roundRetry:   roundRetry,
@@ -244,7 +235,7 @@ func (n *Node) GenerateDistributedKey(
 		sharedAddress,
 		peerCount,
 		threshold,
-		n.identity.GetPrivateKey(),
+		nil, // The node private key is not needed for the public part.
 		peerPubs,
 		n.edSuite,
 		edSharedPublic,
@@ -287,6 +278,264 @@ func (n *Node) GenerateDistributedKey(
 	return dkShare, nil
 }
 
+// ReshareDistributedKey moves an existing distributed key to a new set of nodes and/or
+// a new threshold. The shared public key, and therefore the shared address, remains
+// the same, so the chain does not have to be rotated to a new address. Calling it with
+// the same set of nodes just refreshes the key shares, making the previously leaked
+// shares useless. This function is executed on the initiator node, which must hold
+// a share of the key to be reshared.
+//
+// The nodes leaving the group keep their old shares, so they have to be removed
+// manually to get the full benefit of the resharing.
+//
+//nolint:funlen,gocritic
+func (n *Node) ReshareDistributedKey(
+	sharedAddress iotago.Address,
+	peerPubs []*cryptolib.PublicKey,
+	threshold uint16,
+	roundRetry time.Duration, // Retry for Peer <-> Peer communication.
+	stepRetry time.Duration, // Retry for Initiator -> Peer communication.
+	timeout time.Duration, // Timeout for the entire procedure.
+) (tcrypto.DKShare, error) {
+	n.log.Infof("Starting new key resharing procedure, initiator=%v, address=%v, peers=%+v", n.netProvider.Self().NetID(), sharedAddress, peerPubs)
+	var err error
+	peerCount := uint16(len(peerPubs))
+	//
+	// Some validation for the parameters.
+	if peerCount < 1 || threshold < 1 || threshold > peerCount {
+		return nil, invalidParams(fmt.Errorf("wrong DKG parameters: N = %d, T = %d", peerCount, threshold))
+	}
+	if threshold < peerCount/2+1 {
+		return nil, invalidParams(fmt.Errorf("wrong DKG parameters: for N = %d value T must be at least %d", peerCount, peerCount/2+1))
+	}
+	var oldDKShare tcrypto.DKShare
+	if oldDKShare, err = n.signer.LoadDKShare(sharedAddress); err != nil {
+		return nil, invalidParams(xerrors.Errorf("cannot load the key %v to reshare: %w", sharedAddress, err))
+	}
+	//
+	// Setup network connections.
+	dkgID := peering.RandomPeeringID()
+	var netGroup peering.GroupProvider
+	if netGroup, err = n.netProvider.PeerGroup(dkgID, reshareGroupPubs(peerPubs, oldDKShare.GetNodePubKeys())); err != nil {
+		return nil, err
+	}
+	defer netGroup.Close()
+	recvCh := make(chan *peering.PeerMessageIn, len(netGroup.AllNodes())*2)
+	attachID := n.netProvider.Attach(&dkgID, peering.PeerMessageReceiverDkg, func(recv *peering.PeerMessageIn) {
+		recvCh <- recv
+	})
+	defer n.netProvider.Detach(attachID)
+	rTimeout := stepRetry
+	gTimeout := timeout
+	//
+	// Initialize the peers.
+	if err = n.exchangeInitiatorAcks(netGroup, netGroup.AllNodes(), recvCh, rTimeout, gTimeout, rabinStep0Initialize,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", rabinStep0Initialize, peer.NetID())
+			peer.SendMsg(makePeerMessage(initPeeringID, peering.PeerMessageReceiverDkgInit, rabinStep0Initialize, &initiatorInitMsg{
+				dkgRef:          dkgID.String(),
+				peeringID:       dkgID,
+				peerPubs:        peerPubs,
+				initiatorPub:    n.signer.GetPublicKey(),
+				threshold:       threshold,
+				timeout:         timeout,
+				roundRetry:      roundRetry,
+				sharedAddress:   sharedAddress,
+				oldPeerPubs:     oldDKShare.GetNodePubKeys(),
+				oldThreshold:    oldDKShare.GetT(),
+				edOldPubShares:  oldDKShare.DSSPublicShares(),
+				blsOldPubShares: oldDKShare.BLSPublicShares(),
+			}))
+		},
+	); err != nil {
+		return nil, err
+	}
+	//
+	// Distribute the deals and collect the dealers accepted by each of the new members.
+	acceptedDealers := map[uint16][]uint16{}
+	if err = n.exchangeInitiatorMsgs(netGroup, netGroup.AllNodes(), recvCh, rTimeout, gTimeout, reshareStep1SendDeals,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep1SendDeals, peer.NetID())
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep1SendDeals, &initiatorStepMsg{}))
+		},
+		func(recv *peering.PeerMessageGroupIn, initMsg initiatorMsg) (bool, error) {
+			switch msg := initMsg.(type) {
+			case *initiatorReshareDealsMsg:
+				if recv.SenderIndex < peerCount {
+					acceptedDealers[recv.SenderIndex] = msg.dealers
+				}
+				return true, nil
+			default:
+				n.log.Errorf("unexpected message type instead of initiatorReshareDealsMsg: %v", msg)
+				return false, errors.New("unexpected message type instead of initiatorReshareDealsMsg")
+			}
+		},
+	); err != nil {
+		return nil, err
+	}
+	var qualDealers []uint16
+	if qualDealers, err = reshareQualDealers(acceptedDealers, threshold, oldDKShare.GetT()); err != nil {
+		return nil, err
+	}
+	//
+	// Recover the new shares from the deals of the agreed dealers.
+	pubShareResponses := map[int]*initiatorPubShareMsg{}
+	if err = n.exchangeInitiatorMsgs(netGroup, netGroup.AllNodes(), recvCh, rTimeout, gTimeout, reshareStep2RecoverShares,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep2RecoverShares, peer.NetID())
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep2RecoverShares, &initiatorReshareQualMsg{
+				dealers: qualDealers,
+			}))
+		},
+		func(recv *peering.PeerMessageGroupIn, initMsg initiatorMsg) (bool, error) {
+			switch msg := initMsg.(type) {
+			case *initiatorPubShareMsg:
+				if recv.SenderIndex >= peerCount {
+					return false, errors.New("unexpected initiatorPubShareMsg from a leaving node")
+				}
+				pubShareResponses[int(recv.SenderIndex)] = msg
+				return true, nil
+			case *initiatorStatusMsg:
+				if recv.SenderIndex < peerCount {
+					// A new member, which has not accepted all the qualified deals.
+					n.log.Warnf("Peer %v could not recover its reshared key share", recv.SenderPubKey.String())
+				}
+				return true, nil
+			default:
+				n.log.Errorf("unexpected message type instead of initiatorPubShareMsg: %v", msg)
+				return false, errors.New("unexpected message type instead of initiatorPubShareMsg")
+			}
+		},
+	); err != nil {
+		return nil, err
+	}
+	if len(pubShareResponses) < int(threshold) {
+		return nil, fmt.Errorf("only %v of the new members have recovered their shares, need %v", len(pubShareResponses), threshold)
+	}
+	edKnownShares := make(map[int]kyber.Point, len(pubShareResponses))
+	blsKnownShares := make(map[int]kyber.Point, len(pubShareResponses))
+	for i := range pubShareResponses {
+		if !sharedAddress.Equal(pubShareResponses[i].sharedAddress) {
+			return nil, fmt.Errorf("nodes reshared the key to a different address")
+		}
+		if !oldDKShare.DSSSharedPublic().Equal(pubShareResponses[i].edSharedPublic) {
+			return nil, fmt.Errorf("nodes reshared the key to a different Ed25519 shared public key")
+		}
+		if !oldDKShare.BLSSharedPublic().Equal(pubShareResponses[i].blsSharedPublic) {
+			return nil, fmt.Errorf("nodes reshared the key to a different BLS shared public key")
+		}
+		var blsPubShareBytes []byte
+		if blsPubShareBytes, err = pubShareResponses[i].blsPublicShare.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		err = oldDKShare.BLSVerify(
+			pubShareResponses[i].blsPublicShare,
+			blsPubShareBytes,
+			pubShareResponses[i].blsSignature,
+		)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to verify BLS signature: %w", err)
+		}
+		edKnownShares[i] = pubShareResponses[i].edPublicShare
+		blsKnownShares[i] = pubShareResponses[i].blsPublicShare
+	}
+	var edPublicShares, blsPublicShares []kyber.Point
+	if edPublicShares, err = tcrypto.RecoverMissingPublicShares(n.edSuite, edKnownShares, oldDKShare.DSSSharedPublic(), threshold, peerCount); err != nil {
+		return nil, err
+	}
+	if blsPublicShares, err = tcrypto.RecoverMissingPublicShares(n.blsSuite, blsKnownShares, oldDKShare.BLSSharedPublic(), threshold, peerCount); err != nil {
+		return nil, err
+	}
+	n.log.Debugf("Reshared SharedAddress=%v to peers=%+v", sharedAddress, peerPubs)
+	//
+	// CommitToNode the keys to persistent storage.
+	if err = n.exchangeInitiatorAcks(netGroup, netGroup.AllNodes(), recvCh, rTimeout, gTimeout, reshareStep3CommitAndTerminate,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep3CommitAndTerminate, peer.NetID())
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep3CommitAndTerminate, &initiatorDoneMsg{
+				edPubShares:  edPublicShares,
+				blsPubShares: blsPublicShares,
+			}))
+		},
+	); err != nil {
+		return nil, err
+	}
+	return tcrypto.NewDKSharePublic(
+		sharedAddress,
+		peerCount,
+		threshold,
+		nil, // The node private key is not needed for the public part.
+		peerPubs,
+		n.edSuite,
+		oldDKShare.DSSSharedPublic(),
+		edPublicShares,
+		n.blsSuite,
+		oldDKShare.BLSSharedPublic(),
+		blsPublicShares,
+	), nil
+}
+
+// reshareQualDealers selects the dealers, whose deals will be used by the new members
+// to recover their shares. Exactly oldThreshold dealers are taken, preferring the ones
+// accepted by the most new members, to make the choice the same at all the nodes. The
+// selection is accepted, if at least newThreshold new members have accepted all the
+// selected deals, so a member, which has not received or has rejected some of the deals,
+// does not abort the resharing. Such a member is left without a new share and has to
+// get it in a subsequent resharing.
+func reshareQualDealers(acceptedDealers map[uint16][]uint16, newThreshold, oldThreshold uint16) ([]uint16, error) {
+	acceptCounts := map[uint16]uint16{}
+	for i := range acceptedDealers {
+		for _, dealerIdx := range acceptedDealers[i] {
+			acceptCounts[dealerIdx]++
+		}
+	}
+	candidates := make([]uint16, 0, len(acceptCounts))
+	for dealerIdx, count := range acceptCounts {
+		if count >= newThreshold {
+			candidates = append(candidates, dealerIdx)
+		}
+	}
+	if len(candidates) < int(oldThreshold) {
+		return nil, fmt.Errorf("not enough valid resharing deals: have %v, need %v", len(candidates), oldThreshold)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if acceptCounts[candidates[i]] != acceptCounts[candidates[j]] {
+			return acceptCounts[candidates[i]] > acceptCounts[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	qual := candidates[:oldThreshold]
+	sort.Slice(qual, func(i, j int) bool { return qual[i] < qual[j] })
+	if recovering := reshareRecoveringMembers(acceptedDealers, qual); len(recovering) < int(newThreshold) {
+		return nil, fmt.Errorf("not enough new members can recover their shares: have %v, need %v", len(recovering), newThreshold)
+	}
+	return qual, nil
+}
+
+// reshareRecoveringMembers returns the new members, which have accepted the deals of all
+// the qualified dealers, and thus will be able to recover their new shares.
+func reshareRecoveringMembers(acceptedDealers map[uint16][]uint16, qual []uint16) []uint16 {
+	recovering := make([]uint16, 0, len(acceptedDealers))
+	for memberIdx, dealers := range acceptedDealers {
+		accepted := map[uint16]bool{}
+		for _, dealerIdx := range dealers {
+			accepted[dealerIdx] = true
+		}
+		hasAll := true
+		for _, dealerIdx := range qual {
+			if !accepted[dealerIdx] {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			recovering = append(recovering, memberIdx)
+		}
+	}
+	sort.Slice(recovering, func(i, j int) bool { return recovering[i] < recovering[j] })
+	return recovering
+}
+
 // Async recv is needed to avoid locking on the even publisher (Recv vs Attach in proc).
 func (n *Node) recvLoop() {
 	for recv := range n.initMsgQueue {
@@ -0,0 +1,37 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReshareQualDealers(t *testing.T) {
+	// The new member 3 has not received the deals of the dealers 0 and 1.
+	accepted := map[uint16][]uint16{
+		0: {0, 1, 2, 3},
+		1: {0, 1, 2, 3},
+		2: {0, 1, 2, 3},
+		3: {2, 3},
+	}
+	qual, err := reshareQualDealers(accepted, 3, 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0, 2, 3}, qual)
+	require.Equal(t, []uint16{0, 1, 2}, reshareRecoveringMembers(accepted, qual))
+
+	// The dealers accepted by all are preferred, so all the members recover.
+	qual, err = reshareQualDealers(accepted, 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{2, 3}, qual)
+	require.Len(t, reshareRecoveringMembers(accepted, qual), 4)
+
+	// Not enough members would recover their shares.
+	_, err = reshareQualDealers(accepted, 4, 3)
+	require.Error(t, err)
+	// Not enough dealers.
+	_, err = reshareQualDealers(accepted, 3, 5)
+	require.Error(t, err)
+}
@@ -11,14 +11,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/dkg"
-	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/iotaledger/wasp/packages/testutil/testpeers"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/share"
 )
 
 // TestBasic checks if DKG procedure is executed successfully in a common case.
@@ -40,11 +44,11 @@ func TestBasic(t *testing.T) {
 	//
 	// Initialize the DKG subsystem in each node.
 	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
-	registries := make([]registry.DKShareRegistryProvider, len(peerNetIDs))
+	signers := make([]signer.Signer, len(peerNetIDs))
 	for i := range peerNetIDs {
-		registries[i] = testutil.NewDkgRegistryProvider(peerIdentities[i].GetPrivateKey())
+		signers[i] = testpeers.NewSigner(t, peerIdentities[i])
 		dkgNode, err := dkg.NewNode(
-			peerIdentities[i], networkProviders[i], registries[i],
+			signers[i], networkProviders[i],
 			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 		)
 		require.NoError(t, err)
@@ -69,8 +73,8 @@ func TestBasic(t *testing.T) {
 	// dssPartSigs := make([]*dss.PartialSig, len(peerNetIDs))
 	blsPartSigs := make([][]byte, len(peerNetIDs))
 	var aggrDks tcrypto.DKShare
-	for i, r := range registries {
-		dks, err := r.LoadDKShare(dkShare.GetAddress())
+	for i, s := range signers {
+		dks, err := s.LoadDKShare(dkShare.GetAddress())
 		if i == 0 {
 			aggrDks = dks
 		}
@@ -123,9 +127,8 @@ func TestUnreliableNet(t *testing.T) {
 	// Initialize the DKG subsystem in each node.
 	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
 	for i := range peerNetIDs {
-		dksReg := testutil.NewDkgRegistryProvider(peerIdentities[i].GetPrivateKey())
 		dkgNode, err := dkg.NewNode(
-			peerIdentities[i], networkProviders[i], dksReg,
+			testpeers.NewSigner(t, peerIdentities[i]), networkProviders[i],
 			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 		)
 		require.NoError(t, err)
@@ -166,9 +169,8 @@ func TestLowN(t *testing.T) {
 		// Initialize the DKG subsystem in each node.
 		dkgNodes := make([]*dkg.Node, len(peerNetIDs))
 		for i := range peerNetIDs {
-			dksReg := testutil.NewDkgRegistryProvider(peerIdentities[i].GetPrivateKey())
 			dkgNode, err := dkg.NewNode(
-				peerIdentities[i], networkProviders[i], dksReg,
+				testpeers.NewSigner(t, peerIdentities[i]), networkProviders[i],
 				testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 			)
 			require.NoError(t, err)
@@ -188,3 +190,97 @@ func TestLowN(t *testing.T) {
 		require.NotNil(t, dkShare.GetSharedPublic())
 	}
 }
+
+// TestReshare checks, if an existing key can be moved to another set of nodes
+// and then refreshed, keeping the same shared address.
+func TestReshare(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+	//
+	// Create a fake network and keys for the tests.
+	timeout := 100 * time.Second
+	var peerCount uint16 = 7
+	peerNetIDs, peerIdentities := testpeers.SetupKeys(peerCount)
+	peeringNetwork := testutil.NewPeeringNetwork(
+		peerNetIDs, peerIdentities, 10000,
+		testutil.NewPeeringNetReliable(log),
+		testlogger.WithLevel(log, logger.LevelWarn, false),
+	)
+	networkProviders := peeringNetwork.NetworkProviders()
+	//
+	// Initialize the DKG subsystem in each node.
+	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
+	keyStores := make([]keystore.KeyStore, len(peerNetIDs))
+	signers := make([]signer.Signer, len(peerNetIDs))
+	for i := range peerNetIDs {
+		keyStores[i] = keystore.NewDBKeyStore(mapdb.NewMapDB())
+		signers[i] = testpeers.NewSignerWithKeyStore(t, keyStores[i], peerIdentities[i])
+		dkgNode, err := dkg.NewNode(
+			signers[i], networkProviders[i],
+			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelWarn, false),
+		)
+		require.NoError(t, err)
+		dkgNodes[i] = dkgNode
+	}
+	allPubKeys := testpeers.PublicKeys(peerIdentities)
+	//
+	// Generate the key for the nodes [0..4).
+	dkShare, err := dkgNodes[0].GenerateDistributedKey(allPubKeys[0:4], 3, 1*time.Second, 2*time.Second, timeout)
+	require.NoError(t, err)
+	// The secret shares are read from the key stores directly, the signers never return them.
+	readSecretShare := func(m int, sharedAddress iotago.Address) tcrypto.DKShare {
+		dks, err := signer.ReadDKShare(keyStores[m], sharedAddress, peerIdentities[m].GetPrivateKey())
+		require.NoError(t, err)
+		return dks
+	}
+	checkReshared := func(dks tcrypto.DKShare, members []int, threshold uint16) {
+		require.True(t, dkShare.GetAddress().Equal(dks.GetAddress()))
+		require.True(t, dkShare.DSSSharedPublic().Equal(dks.DSSSharedPublic()))
+		require.True(t, dkShare.BLSSharedPublic().Equal(dks.BLSSharedPublic()))
+		require.Equal(t, threshold, dks.GetT())
+		dataToSign := []byte{112, 117, 116, 105, 110, 32, 99, 104, 117, 105, 108, 111, 33}
+		blsPartSigs := make([][]byte, 0)
+		dssPriShares := make([]*share.PriShare, 0)
+		var aggrDks tcrypto.DKShare
+		for i, m := range members {
+			memberDks, err := signers[m].LoadDKShare(dks.GetAddress())
+			require.NoError(t, err)
+			require.Equal(t, uint16(i), *memberDks.GetIndex())
+			if aggrDks == nil {
+				aggrDks = memberDks
+			}
+			blsPartSig, err := memberDks.BLSSignShare(dataToSign)
+			require.NoError(t, err)
+			blsPartSigs = append(blsPartSigs, blsPartSig)
+			dssPriShares = append(dssPriShares, readSecretShare(m, dks.GetAddress()).DSSSecretShare().PriShare())
+		}
+		blsAggrSig, err := aggrDks.BLSRecoverMasterSignature(blsPartSigs, dataToSign)
+		require.NoError(t, err)
+		require.NoError(t, aggrDks.BLSVerifyMasterSignature(dataToSign, blsAggrSig.Signature[:]))
+		edSuite := tcrypto.DefaultEd25519Suite()
+		dssSecret, err := share.RecoverSecret(edSuite, dssPriShares, int(threshold), len(members))
+		require.NoError(t, err)
+		require.True(t, edSuite.Point().Mul(dssSecret, nil).Equal(dks.DSSSharedPublic()))
+	}
+	//
+	// Move the key to the nodes [2..7), initiated by a leaving node.
+	reshared, err := dkgNodes[1].ReshareDistributedKey(dkShare.GetAddress(), allPubKeys[2:7], 4, 1*time.Second, 2*time.Second, timeout)
+	require.NoError(t, err)
+	checkReshared(reshared, []int{2, 3, 4, 5, 6}, 4)
+	//
+	// Refresh the shares within the same group.
+	oldShare := readSecretShare(2, dkShare.GetAddress())
+	refreshed, err := dkgNodes[2].ReshareDistributedKey(dkShare.GetAddress(), allPubKeys[2:7], 4, 1*time.Second, 2*time.Second, timeout)
+	require.NoError(t, err)
+	checkReshared(refreshed, []int{2, 3, 4, 5, 6}, 4)
+	newShare := readSecretShare(2, dkShare.GetAddress())
+	require.False(t, oldShare.DSSSecretShare().PriShare().V.Equal(newShare.DSSSecretShare().PriShare().V))
+	//
+	// Shrink the group to a single node and grow it back.
+	reshared, err = dkgNodes[3].ReshareDistributedKey(dkShare.GetAddress(), allPubKeys[3:4], 1, 1*time.Second, 2*time.Second, timeout)
+	require.NoError(t, err)
+	checkReshared(reshared, []int{3}, 1)
+	reshared, err = dkgNodes[3].ReshareDistributedKey(dkShare.GetAddress(), allPubKeys[0:4], 3, 1*time.Second, 2*time.Second, timeout)
+	require.NoError(t, err)
+	checkReshared(reshared, []int{0, 1, 2, 3}, 3)
+}
@@ -8,20 +8,20 @@ package dkg
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/mr-tron/base58"
-	"go.dedis.ch/kyber/v3"
 	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
 	"go.dedis.ch/kyber/v3/suites"
-	"go.dedis.ch/kyber/v3/util/key"
-	"golang.org/x/xerrors"
 )
 
 const (
@@ -35,6 +35,12 @@ const (
 	rabinStep7CommitAndTerminate       = byte(7)
 )
 
+const (
+	reshareStep1SendDeals          = byte(1)
+	reshareStep2RecoverShares      = byte(2)
+	reshareStep3CommitAndTerminate = byte(3)
+)
+
 // Stands for a DKG procedure instance on a particular node.
 type proc struct {
 	dkgRef       string            // User supplied unique ID for this instance.
@@ -44,18 +50,36 @@ type proc struct {
 	nodeIndex    uint16            // Index of this node.
 	initiatorPub *cryptolib.PublicKey
 	threshold    uint16
-	roundRetry   time.Duration                              // Retry period for the Peer <-> Peer communication.
-	netGroup     peering.GroupProvider                      // A group for which the distributed key is generated.
-	dkgImpl      map[keySetType]*rabin_dkg.DistKeyGenerator // The cryptographic implementation to use.
-	dkgLock      *sync.RWMutex                              // Guard access to dkgImpl
-	attachID     interface{}                                // We keep it here to be able to detach from the network.
-	peerMsgCh    chan *peering.PeerMessageGroupIn           // A buffer for the received peer messages.
-	log          *logger.Logger                             // A logger to use.
-	myPubKey     *cryptolib.PublicKey                       // Just to make logging easier.
-	steps        map[byte]*procStep                         // All the steps for the procedure.
+	roundRetry   time.Duration                    // Retry period for the Peer <-> Peer communication.
+	netGroup     peering.GroupProvider            // A group for which the distributed key is generated.
+	session      signer.Session                   // The new key share is produced and kept by the signer.
+	dkg          signer.DKG                       // The DKG session, nil, if resharing.
+	singleNode   bool                             // We use real DKG only if N >= 2.
+	qual         map[keySetType][]int             // The QUAL sets, once the deal phase is closed.
+	dkgLock      *sync.RWMutex                    // Guard access to qual and the accepted deals.
+	attachID     interface{}                      // We keep it here to be able to detach from the network.
+	peerMsgCh    chan *peering.PeerMessageGroupIn // A buffer for the received peer messages.
+	log          *logger.Logger                   // A logger to use.
+	myPubKey     *cryptolib.PublicKey             // Just to make logging easier.
+	steps        map[byte]*procStep               // All the steps for the procedure.
+	reshare      *procReshare                     // Only set, if an existing key is reshared.
+}
+
+// Part of the procedure state, specific to the key resharing.
+type procReshare struct {
+	session       signer.Reshare                 // Makes and accepts the deals, the shares are kept by the signer.
+	sharedAddress iotago.Address                 // Address of the key being reshared.
+	oldIndex      *uint16                        // Index of this node in the old group, if any.
+	oldPeerPubs   []*cryptolib.PublicKey         // Current holders of the key shares.
+	newPeerPubs   []*cryptolib.PublicKey         // The new holders of the key shares.
+	newIndex      *uint16                        // Index of this node in the new group, if any.
+	accepted      map[keySetType]map[uint16]bool // Dealers, whose deals were accepted by the signer.
 }
 
 func onInitiatorInit(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node) (*proc, error) {
+	if msg.isReshare() {
+		return onInitiatorInitReshare(dkgID, msg, node)
+	}
 	log := node.log.With("dkgID", dkgID.String())
 	var err error
 
@@ -63,23 +87,9 @@ func onInitiatorInit(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node)
 	if netGroup, err = node.netProvider.PeerGroup(dkgID, msg.peerPubs); err != nil {
 		return nil, err
 	}
-	var dkgImpl map[keySetType]*rabin_dkg.DistKeyGenerator
-	if len(msg.peerPubs) >= 2 {
-		// We use real DKG only if N >= 2. Otherwise we just generate key pair, and that's all.
-		dkgImpl = make(map[keySetType]*rabin_dkg.DistKeyGenerator)
-		kyberPeerPubs := make([]kyber.Point, len(msg.peerPubs))
-		for i := range kyberPeerPubs {
-			kyberPeerPubs[i] = node.edSuite.Point()
-			if err := kyberPeerPubs[i].UnmarshalBinary(msg.peerPubs[i].AsBytes()); err != nil {
-				return nil, err
-			}
-		}
-		if dkgImpl[keySetTypeEd25519], err = rabin_dkg.NewDistKeyGenerator(node.edSuite, node.edSuite, node.secKey, kyberPeerPubs, int(msg.threshold)); err != nil {
-			return nil, xerrors.Errorf("failed to instantiate DistKeyGenerator: %w", err)
-		}
-		if dkgImpl[keySetTypeBLS], err = rabin_dkg.NewDistKeyGenerator(node.blsSuite, node.edSuite, node.secKey, kyberPeerPubs, int(msg.threshold)); err != nil {
-			return nil, xerrors.Errorf("failed to instantiate DistKeyGenerator: %w", err)
-		}
+	var dkg signer.DKG
+	if dkg, err = node.signer.NewDKG(msg.peerPubs, msg.threshold); err != nil {
+		return nil, err
 	}
 	p := proc{
 		dkgRef:       msg.dkgRef,
@@ -90,7 +100,10 @@ func onInitiatorInit(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node)
 		threshold:    msg.threshold,
 		roundRetry:   msg.roundRetry,
 		netGroup:     netGroup,
-		dkgImpl:      dkgImpl,
+		session:      dkg,
+		dkg:          dkg,
+		singleNode:   len(msg.peerPubs) < 2,
+		qual:         make(map[keySetType][]int),
 		dkgLock:      &sync.RWMutex{},
 		peerMsgCh:    make(chan *peering.PeerMessageGroupIn, len(msg.peerPubs)),
 		log:          log,
@@ -99,7 +112,7 @@ func onInitiatorInit(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node)
 	p.log.Infof("Starting DKG Peer process at %v for DkgID=%v", p.myPubKey.String(), p.dkgID.String())
 	stepsStart := make(chan multiKeySetMsgs)
 	p.steps = make(map[byte]*procStep)
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		p.steps[rabinStep6R6SendReconstructCommits] = newProcStep(rabinStep6R6SendReconstructCommits, &p,
 			stepsStart,
 			p.rabinStep6R6SendReconstructCommitsMakeSent,
@@ -153,6 +166,82 @@ func onInitiatorInit(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node)
 	return &p, nil
 }
 
+func onInitiatorInitReshare(dkgID peering.PeeringID, msg *initiatorInitMsg, node *Node) (*proc, error) {
+	log := node.log.With("dkgID", dkgID.String())
+	var err error
+
+	var netGroup peering.GroupProvider
+	if netGroup, err = node.netProvider.PeerGroup(dkgID, reshareGroupPubs(msg.peerPubs, msg.oldPeerPubs)); err != nil {
+		return nil, err
+	}
+	var session signer.Reshare
+	if session, err = node.signer.NewReshare(&signer.ReshareParams{
+		SharedAddress:   msg.sharedAddress,
+		OldPeerPubs:     msg.oldPeerPubs,
+		OldThreshold:    msg.oldThreshold,
+		EdOldPubShares:  msg.edOldPubShares,
+		BLSOldPubShares: msg.blsOldPubShares,
+		NewPeerPubs:     msg.peerPubs,
+		NewThreshold:    msg.threshold,
+	}); err != nil {
+		return nil, err
+	}
+	reshare := procReshare{
+		session:       session,
+		sharedAddress: msg.sharedAddress,
+		oldPeerPubs:   msg.oldPeerPubs,
+		newPeerPubs:   msg.peerPubs,
+		accepted: map[keySetType]map[uint16]bool{
+			keySetTypeEd25519: make(map[uint16]bool),
+			keySetTypeBLS:     make(map[uint16]bool),
+		},
+	}
+	if oldIndex, ok := pubKeyIndex(msg.oldPeerPubs, node.signer.GetPublicKey()); ok {
+		reshare.oldIndex = &oldIndex
+	}
+	if newIndex, ok := pubKeyIndex(msg.peerPubs, node.signer.GetPublicKey()); ok {
+		reshare.newIndex = &newIndex
+	}
+	p := proc{
+		dkgRef:       msg.dkgRef,
+		dkgID:        dkgID,
+		node:         node,
+		nodeIndex:    netGroup.SelfIndex(),
+		initiatorPub: msg.initiatorPub,
+		threshold:    msg.threshold,
+		roundRetry:   msg.roundRetry,
+		netGroup:     netGroup,
+		session:      session,
+		dkgLock:      &sync.RWMutex{},
+		peerMsgCh:    make(chan *peering.PeerMessageGroupIn, len(netGroup.AllNodes())),
+		log:          log,
+		myPubKey:     node.netProvider.Self().PubKey(),
+		reshare:      &reshare,
+	}
+	p.log.Infof("Starting key resharing Peer process at %v for DkgID=%v", p.myPubKey.String(), p.dkgID.String())
+	stepsStart := make(chan multiKeySetMsgs)
+	p.steps = make(map[byte]*procStep)
+	p.steps[reshareStep1SendDeals] = newProcStep(reshareStep1SendDeals, &p,
+		stepsStart,
+		p.reshareStep1SendDealsMakeSent,
+		p.reshareStep1SendDealsMakeResp,
+	)
+	p.steps[reshareStep2RecoverShares] = newProcStep(reshareStep2RecoverShares, &p,
+		p.steps[reshareStep1SendDeals].doneCh,
+		p.reshareStep2RecoverSharesMakeSent,
+		p.reshareStep2RecoverSharesMakeResp,
+	)
+	p.steps[reshareStep3CommitAndTerminate] = newProcStep(reshareStep3CommitAndTerminate, &p,
+		p.steps[reshareStep2RecoverShares].doneCh,
+		p.rabinStep7CommitAndTerminateMakeSent,
+		p.reshareStep3CommitAndTerminateMakeResp,
+	)
+	go p.processLoop(msg.timeout, p.steps[reshareStep3CommitAndTerminate].doneCh)
+	p.attachID = p.netGroup.Attach(peering.PeerMessageReceiverDkg, p.onPeerMessage)
+	stepsStart <- make(multiKeySetMsgs)
+	return &p, nil
+}
+
 // Handles a message from a peer and pass it to the main thread.
 func (p *proc) onPeerMessage(peerMsg *peering.PeerMessageGroupIn) {
 	p.peerMsgCh <- peerMsg
@@ -185,6 +274,9 @@ func (p *proc) processLoop(timeout time.Duration, doneCh chan multiKeySetMsgs) {
 			for i := range p.steps {
 				p.steps[i].close()
 			}
+			if err := p.session.Close(); err != nil {
+				p.log.Warnf("Failed to close the signer session: %v", err)
+			}
 			if p.node.dropProcess(p) {
 				if done {
 					p.log.Debugf("Deleting completed DkgProc.")
@@ -200,17 +292,14 @@ func (p *proc) processLoop(timeout time.Duration, doneCh chan multiKeySetMsgs) {
 // rabinStep1R21SendDeals
 func (p *proc) rabinStep1R21SendDealsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		return nil, errors.New("unexpected step for n=1")
 	}
-	p.dkgLock.Lock()
 	var deals map[int]*rabin_dkg.Deal
-	if deals, err = p.dkgImpl[kst].Deals(); err != nil {
-		p.dkgLock.Unlock()
+	if deals, err = p.dkg.Deals(signer.KeySet(kst)); err != nil {
 		p.log.Errorf("Deals -> %+v", err)
 		return nil, err
 	}
-	p.dkgLock.Unlock()
 	sentMsgs := make(map[uint16]*peering.PeerMessageData)
 	for i := range deals {
 		sentMsgs[uint16(i)] = makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &rabinDealMsg{
@@ -227,7 +316,7 @@ func (p *proc) rabinStep1R21SendDealsMakeResp(step byte, initRecv *peering.PeerM
 // rabinStep2R22SendResponses
 func (p *proc) rabinStep2R22SendResponsesMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		return nil, errors.New("unexpected step for n=1")
 	}
 	//
@@ -245,13 +334,10 @@ func (p *proc) rabinStep2R22SendResponsesMakeSent(step byte, kst keySetType, ini
 	ourResponses := []*rabin_dkg.Response{}
 	for i := range recvDeals {
 		var r *rabin_dkg.Response
-		p.dkgLock.Lock()
-		if r, err = p.dkgImpl[kst].ProcessDeal(recvDeals[i].deal); err != nil {
-			p.dkgLock.Unlock()
+		if r, err = p.dkg.ProcessDeal(signer.KeySet(kst), recvDeals[i].deal); err != nil {
 			p.log.Errorf("ProcessDeal(%v) -> %+v", i, err)
 			return nil, err
 		}
-		p.dkgLock.Unlock()
 		p.log.Debugf("RabinDKG[%v] DealResponse[%v|%v]=%v", p.myPubKey.String(), r.Index, r.Response.Index, base58.Encode(r.Response.SessionID))
 		ourResponses = append(ourResponses, r)
 	}
@@ -273,7 +359,7 @@ func (p *proc) rabinStep2R22SendResponsesMakeResp(step byte, initRecv *peering.P
 // rabinStep3R23SendJustifications
 func (p *proc) rabinStep3R23SendJustificationsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		return nil, errors.New("unexpected step for n=1")
 	}
 	//
@@ -292,15 +378,12 @@ func (p *proc) rabinStep3R23SendJustificationsMakeSent(step byte, kst keySetType
 	ourJustifications := []*rabin_dkg.Justification{}
 	for i := range recvResponses {
 		for _, r := range recvResponses[i].responses {
-			p.dkgLock.Lock()
 			var j *rabin_dkg.Justification
 			p.log.Debugf("RabinDKG[%v] ProcResponse[%v|%v]=%v", p.myPubKey.String(), r.Index, r.Response.Index, base58.Encode(r.Response.SessionID))
-			if j, err = p.dkgImpl[kst].ProcessResponse(r); err != nil {
-				p.dkgLock.Unlock()
+			if j, err = p.dkg.ProcessResponse(signer.KeySet(kst), r); err != nil {
 				p.log.Errorf("ProcessResponse(%v) -> %+v, resp.SessionID=%v", i, err, base58.Encode(r.Response.SessionID))
 				return nil, err
 			}
-			p.dkgLock.Unlock()
 			if j != nil {
 				ourJustifications = append(ourJustifications, j)
 			}
@@ -324,7 +407,7 @@ func (p *proc) rabinStep3R23SendJustificationsMakeResp(step byte, initRecv *peer
 // rabinStep4R4SendSecretCommits
 func (p *proc) rabinStep4R4SendSecretCommitsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		return nil, errors.New("unexpected step for n=1")
 	}
 	//
@@ -339,35 +422,29 @@ func (p *proc) rabinStep4R4SendSecretCommitsMakeSent(step byte, kst keySetType,
 	}
 	//
 	// Process the received justifications.
-	p.dkgLock.Lock()
 	for i := range recvJustifications {
 		for _, j := range recvJustifications[i].justifications {
-			if err = p.dkgImpl[kst].ProcessJustification(j); err != nil {
-				p.dkgLock.Unlock()
+			if err = p.dkg.ProcessJustification(signer.KeySet(kst), j); err != nil {
 				return nil, fmt.Errorf("Justification: processing failed: %v", err)
 			}
 		}
 	}
-	p.dkgLock.Unlock()
 	p.log.Debugf("All justifications processed.")
 	//
 	// Take the QUAL set.
-	p.dkgLock.Lock()
-	p.dkgImpl[kst].SetTimeout()
-	if !p.dkgImpl[kst].Certified() {
-		p.dkgLock.Unlock()
-		return nil, fmt.Errorf("node not certified")
+	var qual []int
+	if qual, err = p.dkg.QUAL(signer.KeySet(kst)); err != nil {
+		return nil, err
 	}
+	p.dkgLock.Lock()
+	p.qual[kst] = qual
 	p.dkgLock.Unlock()
 	thisInQual := p.nodeInQUAL(kst, p.nodeIndex)
 	var ourSecretCommits *rabin_dkg.SecretCommits // Will be nil, if we are not in QUAL.
 	if thisInQual {
-		p.dkgLock.Lock()
-		if ourSecretCommits, err = p.dkgImpl[kst].SecretCommits(); err != nil {
-			p.dkgLock.Unlock()
+		if ourSecretCommits, err = p.dkg.SecretCommits(signer.KeySet(kst)); err != nil {
 			return nil, fmt.Errorf("SecretCommits: generation failed: %v", err)
 		}
-		p.dkgLock.Unlock()
 	}
 	//
 	// Produce the sent messages.
@@ -393,7 +470,7 @@ func (p *proc) rabinStep4R4SendSecretCommitsMakeResp(step byte, initRecv *peerin
 // rabinStep5R5SendComplaintCommits
 func (p *proc) rabinStep5R5SendComplaintCommitsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		return nil, errors.New("unexpected step for n=1")
 	}
 	//
@@ -413,13 +490,10 @@ func (p *proc) rabinStep5R5SendComplaintCommitsMakeSent(step byte, kst keySetTyp
 		for i := range recvSecretCommits {
 			sc := recvSecretCommits[i].secretCommits
 			if sc != nil {
-				p.dkgLock.Lock()
 				var cc *rabin_dkg.ComplaintCommits
-				if cc, err = p.dkgImpl[kst].ProcessSecretCommits(sc); err != nil {
-					p.dkgLock.Unlock()
+				if cc, err = p.dkg.ProcessSecretCommits(signer.KeySet(kst), sc); err != nil {
 					return nil, err
 				}
-				p.dkgLock.Unlock()
 				if cc != nil {
 					ourComplaintCommits = append(ourComplaintCommits, cc)
 				}
@@ -450,7 +524,7 @@ func (p *proc) rabinStep5R5SendComplaintCommitsMakeResp(step byte, initRecv *pee
 // rabinStep6R6SendReconstructCommits
 func (p *proc) rabinStep6R6SendReconstructCommitsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
+	if p.singleNode {
 		// Nothing to exchange in the round, if N=1
 		return make(map[uint16]*peering.PeerMessageData), nil
 	}
@@ -470,13 +544,10 @@ func (p *proc) rabinStep6R6SendReconstructCommitsMakeSent(step byte, kst keySetT
 	if p.nodeInQUAL(kst, p.nodeIndex) {
 		for i := range recvComplaintCommits {
 			for _, cc := range recvComplaintCommits[i].complaintCommits {
-				p.dkgLock.Lock()
 				var rc *rabin_dkg.ReconstructCommits
-				if rc, err = p.dkgImpl[kst].ProcessComplaintCommits(cc); err != nil {
-					p.dkgLock.Unlock()
+				if rc, err = p.dkg.ProcessComplaintCommits(signer.KeySet(kst), cc); err != nil {
 					return nil, err
 				}
-				p.dkgLock.Unlock()
 				if rc != nil {
 					ourReconstructCommits = append(ourReconstructCommits, rc)
 				}
@@ -500,114 +571,42 @@ func (p *proc) rabinStep6R6SendReconstructCommitsMakeSent(step byte, kst keySetT
 	return sentMsgs, nil
 }
 
-func (p *proc) rabinStep6R6SendReconstructCommitsMakeResp( //nolint:funlen
+func (p *proc) rabinStep6R6SendReconstructCommitsMakeResp(
 	step byte,
 	initRecv *peering.PeerMessageGroupIn,
 	recvMsgs multiKeySetMsgs,
 ) (*peering.PeerMessageData, error) {
 	var err error
-	if p.dkgImpl == nil {
-		// This is the case for N=1, just use simple BLS key pair.
-		keyPairE := key.NewKeyPair(p.node.edSuite)
-		keyPairB := key.NewKeyPair(p.node.blsSuite)
-		p.dkShare, err = tcrypto.NewDKShare(
-			0,                               // Index
-			1,                               // N
-			1,                               // T
-			p.node.identity.GetPrivateKey(), // NodePrivKey
-			p.nodePubKeys(),                 // NodePubKeys
-			p.node.edSuite,                  // Ed25519: Suite
-			keyPairE.Public,                 // Ed25519: SharedPublic
-			[]kyber.Point{keyPairE.Public},  // Ed25519: PublicCommits
-			[]kyber.Point{keyPairE.Public},  // Ed25519: PublicShares
-			keyPairE.Private,                // Ed25519: PrivateShare
-			p.node.blsSuite,                 // BLS: Suite
-			keyPairB.Public,                 // BLS: SharedPublic
-			make([]kyber.Point, 0),          // BLS: PublicCommits
-			[]kyber.Point{keyPairB.Public},  // BLS: PublicShares
-			keyPairB.Private,                // BLS: PrivateShare
-		)
-		if err != nil {
-			return nil, err
-		}
-	} else {
+	if !p.singleNode {
 		//
 		// Process the received reconstruct commits.
 		for _, recvMsg := range recvMsgs {
 			peerReconstructCommitsMsgEd := rabinReconstructCommitsMsg{}
-			if err := peerReconstructCommitsMsgEd.fromBytes(recvMsg.edMsg.MsgData); err != nil {
+			if err := peerReconstructCommitsMsgEd.fromBytes(recvMsg.edMsg.MsgData, p.node.edSuite); err != nil {
 				return nil, err
 			}
 			peerReconstructCommitsMsgBLS := rabinReconstructCommitsMsg{}
-			if err := peerReconstructCommitsMsgBLS.fromBytes(recvMsg.blsMsg.MsgData); err != nil {
+			if err := peerReconstructCommitsMsgBLS.fromBytes(recvMsg.blsMsg.MsgData, p.node.blsSuite); err != nil {
 				return nil, err
 			}
-			p.dkgLock.Lock()
 			for _, rc := range peerReconstructCommitsMsgEd.reconstructCommits {
-				if err = p.dkgImpl[keySetTypeEd25519].ProcessReconstructCommits(rc); err != nil {
-					p.dkgLock.Unlock()
+				if err = p.dkg.ProcessReconstructCommits(signer.KeySetEd25519, rc); err != nil {
 					return nil, err
 				}
 			}
 			for _, rc := range peerReconstructCommitsMsgBLS.reconstructCommits {
-				if err = p.dkgImpl[keySetTypeBLS].ProcessReconstructCommits(rc); err != nil {
-					p.dkgLock.Unlock()
+				if err = p.dkg.ProcessReconstructCommits(signer.KeySetBLS, rc); err != nil {
 					return nil, err
 				}
 			}
-			p.dkgLock.Unlock()
-		}
-		//
-		// Retrieve the generated DistKeyShare.
-		p.dkgLock.Lock()
-		if !p.dkgImpl[keySetTypeEd25519].Finished() {
-			p.dkgLock.Unlock()
-			return nil, fmt.Errorf("DKG procedure is not finished")
-		}
-		if !p.dkgImpl[keySetTypeBLS].Finished() {
-			p.dkgLock.Unlock()
-			return nil, fmt.Errorf("DKG procedure is not finished")
-		}
-		var distKeyShareDSS *rabin_dkg.DistKeyShare
-		var distKeyShareBLS *rabin_dkg.DistKeyShare
-		if distKeyShareDSS, err = p.dkgImpl[keySetTypeEd25519].DistKeyShare(); err != nil {
-			p.dkgLock.Unlock()
-			return nil, err
-		}
-		if distKeyShareBLS, err = p.dkgImpl[keySetTypeBLS].DistKeyShare(); err != nil {
-			p.dkgLock.Unlock()
-			return nil, err
-		}
-		p.dkgLock.Unlock()
-		//
-		// Save the needed info.
-		groupSize := uint16(len(p.netGroup.AllNodes()))
-		ownIndex := uint16(distKeyShareDSS.PriShare().I)
-		publicSharesDSS := make([]kyber.Point, groupSize)
-		publicSharesDSS[ownIndex] = p.node.edSuite.Point().Mul(distKeyShareDSS.PriShare().V, nil)
-		publicSharesBLS := make([]kyber.Point, groupSize)
-		publicSharesBLS[ownIndex] = p.node.blsSuite.Point().Mul(distKeyShareBLS.PriShare().V, nil)
-		p.dkShare, err = tcrypto.NewDKShare(
-			ownIndex,                        // Index
-			groupSize,                       // N
-			p.threshold,                     // T
-			p.node.identity.GetPrivateKey(), // NodePrivKey
-			p.nodePubKeys(),                 // NodePubKeys
-			p.node.edSuite,                  // Ed25519: Suite
-			distKeyShareDSS.Public(),        // Ed25519: SharedPublic
-			distKeyShareDSS.Commits,         // Ed25519: PublicCommits
-			publicSharesDSS,                 // Ed25519: PublicShares
-			distKeyShareDSS.PriShare().V,    // Ed25519: PrivateShare
-			p.node.blsSuite,                 // BLS: Suite
-			distKeyShareBLS.Public(),        // BLS: SharedPublic
-			distKeyShareBLS.Commits,         // BLS: PublicCommits
-			publicSharesBLS,                 // BLS: PublicShares
-			distKeyShareBLS.PriShare().V,    // BLS: PrivateShare
-		)
-		if err != nil {
-			return nil, err
 		}
 	}
+	//
+	// Retrieve the generated key share, the signer keeps its private part.
+	// For N=1 the signer just generates the key pairs.
+	if p.dkShare, err = p.dkg.DKShare(); err != nil {
+		return nil, err
+	}
 	p.log.Debugf(
 		"All reconstruct commits received, shared public: %v.",
 		p.dkShare.GetSharedPublic(),
@@ -634,25 +633,148 @@ func (p *proc) rabinStep7CommitAndTerminateMakeResp(step byte, initRecv *peering
 	if p.dkShare == nil {
 		return nil, errors.New("there is no dkShare to commit")
 	}
-	p.dkShare.SetPublicShares(doneMsg.edPubShares, doneMsg.blsPubShares) // Store public shares of all the other peers.
-	if err := p.node.registry.SaveDKShare(p.dkShare); err != nil {
+	// Store the public shares of all the other peers with the key share.
+	if err := p.session.Commit(doneMsg.edPubShares, doneMsg.blsPubShares); err != nil {
 		return nil, err
 	}
+	p.dkShare.SetPublicShares(doneMsg.edPubShares, doneMsg.blsPubShares)
 	return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorStatusMsg{error: nil}), nil
 }
 
+// reshareStep1SendDeals
+func (p *proc) reshareStep1SendDealsMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
+	r := p.reshare
+	newN := uint16(len(r.newPeerPubs))
+	// The signer accepts our own deal without sending it over the network.
+	commits, encryptedSubShares, err := r.session.Deal(signer.KeySet(kst))
+	if err != nil {
+		return nil, err
+	}
+	if commits != nil && r.newIndex != nil {
+		p.dkgLock.Lock()
+		r.accepted[kst][*r.oldIndex] = true
+		p.dkgLock.Unlock()
+	}
+	sentMsgs := make(map[uint16]*peering.PeerMessageData)
+	for i := range p.netGroup.OtherNodes() {
+		dealMsg := reshareDealMsg{}
+		if commits != nil && i < newN { // The new members are listed first in the group.
+			dealMsg.commits = commits
+			dealMsg.subShare = encryptedSubShares[i]
+		}
+		sentMsgs[i] = makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &dealMsg)
+	}
+	return sentMsgs, nil
+}
+
+func (p *proc) reshareStep1SendDealsMakeResp(step byte, initRecv *peering.PeerMessageGroupIn, recvMsgs multiKeySetMsgs) (*peering.PeerMessageData, error) {
+	r := p.reshare
+	if r.newIndex != nil {
+		for senderIdx, recvMsg := range recvMsgs {
+			senderPub, err := p.netGroup.PubKeyByIndex(senderIdx)
+			if err != nil {
+				return nil, err
+			}
+			dealerIdx, isDealer := pubKeyIndex(r.oldPeerPubs, senderPub)
+			if !isDealer {
+				continue
+			}
+			for kst, msgData := range map[keySetType]*peering.PeerMessageData{keySetTypeEd25519: recvMsg.edMsg, keySetTypeBLS: recvMsg.blsMsg} {
+				dealMsg := reshareDealMsg{}
+				if err := dealMsg.fromBytes(msgData.MsgData, p.keySetSuite(kst)); err != nil {
+					p.log.Warnf("Failed to decode a resharing deal from %v: %v", senderPub.String(), err)
+					continue
+				}
+				if dealMsg.isEmpty() {
+					p.log.Warnf("Dealer %v has not provided a resharing deal.", senderPub.String())
+					continue
+				}
+				if err := r.session.AcceptDeal(signer.KeySet(kst), dealerIdx, dealMsg.commits, dealMsg.subShare); err != nil {
+					p.log.Warnf("Rejecting a resharing deal from %v: %v", senderPub.String(), err)
+					continue
+				}
+				p.dkgLock.Lock()
+				r.accepted[kst][dealerIdx] = true
+				p.dkgLock.Unlock()
+			}
+		}
+	}
+	p.dkgLock.RLock()
+	dealers := make([]uint16, 0)
+	for dealerIdx := range r.accepted[keySetTypeEd25519] {
+		if r.accepted[keySetTypeBLS][dealerIdx] {
+			dealers = append(dealers, dealerIdx)
+		}
+	}
+	p.dkgLock.RUnlock()
+	sort.Slice(dealers, func(i, j int) bool { return dealers[i] < dealers[j] })
+	return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorReshareDealsMsg{dealers: dealers}), nil
+}
+
+// reshareStep2RecoverShares
+func (p *proc) reshareStep2RecoverSharesMakeSent(step byte, kst keySetType, initRecv *peering.PeerMessageGroupIn, prevMsgs map[uint16]*peering.PeerMessageData) (map[uint16]*peering.PeerMessageData, error) {
+	// Nothing to exchange with the peers, the shares are recovered from the already received deals.
+	return make(map[uint16]*peering.PeerMessageData), nil
+}
+
+func (p *proc) reshareStep2RecoverSharesMakeResp(step byte, initRecv *peering.PeerMessageGroupIn, recvMsgs multiKeySetMsgs) (*peering.PeerMessageData, error) {
+	var err error
+	r := p.reshare
+	if r.newIndex == nil {
+		// This node is leaving the group, nothing to recover.
+		return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorStatusMsg{error: nil}), nil
+	}
+	qualMsg := initiatorReshareQualMsg{}
+	if err = qualMsg.fromBytes(initRecv.MsgData); err != nil {
+		return nil, err
+	}
+	p.dkgLock.RLock()
+	for _, dealerIdx := range qualMsg.dealers {
+		if !r.accepted[keySetTypeEd25519][dealerIdx] || !r.accepted[keySetTypeBLS][dealerIdx] {
+			p.dkgLock.RUnlock()
+			// The other members can still complete the resharing without us.
+			p.log.Warnf("Have no accepted deal from the dealer %v, cannot recover the reshared key share.", dealerIdx)
+			return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorStatusMsg{error: nil}), nil
+		}
+	}
+	p.dkgLock.RUnlock()
+	if p.dkShare, err = r.session.Recover(qualMsg.dealers); err != nil {
+		return nil, err
+	}
+	p.log.Debugf("Key shares recovered, shared public: %v.", p.dkShare.GetSharedPublic())
+	var pubShareMsg *initiatorPubShareMsg
+	if pubShareMsg, err = p.makeInitiatorPubShareMsg(step); err != nil {
+		return nil, err
+	}
+	return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, pubShareMsg), nil
+}
+
+// reshareStep3CommitAndTerminate
+func (p *proc) reshareStep3CommitAndTerminateMakeResp(step byte, initRecv *peering.PeerMessageGroupIn, recvMsgs multiKeySetMsgs) (*peering.PeerMessageData, error) {
+	if p.reshare.newIndex == nil {
+		// This node is leaving the group, nothing to commit.
+		// NOTE: The old share is kept, the registry has no means to delete it.
+		return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorStatusMsg{error: nil}), nil
+	}
+	if p.dkShare == nil {
+		// The share was not recovered in the previous step, another resharing is needed to get it.
+		p.log.Warnf("Not committing the reshared key %v, this node has no share of it.", p.reshare.sharedAddress)
+		return makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, &initiatorStatusMsg{error: nil}), nil
+	}
+	return p.rabinStep7CommitAndTerminateMakeResp(step, initRecv, recvMsgs)
+}
+
 func (p *proc) nodeInQUAL(kst keySetType, nodeIdx uint16) bool {
-	if nodeIdx == 0 && p.dkgImpl == nil {
+	if nodeIdx == 0 && p.singleNode {
 		return true // If N=1, Idx=0 is in QUAL.
 	}
 	p.dkgLock.RLock()
-	for _, q := range p.dkgImpl[kst].QUAL() {
+	defer p.dkgLock.RUnlock()
+	for _, q := range p.qual[kst] {
 		if uint16(q) == nodeIdx {
-			p.dkgLock.RUnlock()
 			return true
 		}
 	}
-	p.dkgLock.RUnlock()
 	return false
 }
 
@@ -671,7 +793,7 @@ func (p *proc) makeInitiatorPubShareMsg(step byte) (*initiatorPubShareMsg, error
 	// 	return nil, err
 	// }
 	var blsSignature []byte
-	if blsSignature, err = p.dkShare.BLSSign(blsPublicShareBytes); err != nil {
+	if blsSignature, err = p.session.BLSSign(blsPublicShareBytes); err != nil {
 		return nil, err
 	}
 	return &initiatorPubShareMsg{
@@ -686,16 +808,6 @@ func (p *proc) makeInitiatorPubShareMsg(step byte) (*initiatorPubShareMsg, error
 	}, nil
 }
 
-func (p *proc) nodePubKeys() []*cryptolib.PublicKey {
-	allNodes := p.netGroup.AllNodes()
-	nodeCount := len(allNodes)
-	pubKeys := make([]*cryptolib.PublicKey, nodeCount)
-	for i := 0; i < nodeCount; i++ {
-		pubKeys[i] = allNodes[uint16(i)].PubKey()
-	}
-	return pubKeys
-}
-
 func (p *proc) keySetSuite(kst keySetType) suites.Suite {
 	switch kst {
 	case keySetTypeEd25519:
@@ -802,7 +914,7 @@ func (s *procStep) run() { //nolint:funlen, gocyclo
 					s.sendEcho(recv)
 					continue
 				}
-				s.log.Warnf("[%v -%v-> %v] Dropping unknown message.", recv.SenderPubKey.String(), recv.MsgType, s.proc.node.pubKey.String())
+				s.log.Warnf("[%v -%v-> %v] Dropping unknown message.", recv.SenderPubKey.String(), recv.MsgType, s.proc.myPubKey.String())
 				continue
 			}
 			//
@@ -3,7 +3,10 @@
 
 package dkg
 
-import "go.dedis.ch/kyber/v3"
+import (
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"go.dedis.ch/kyber/v3"
+)
 
 // InvalidParamsError is used to distinguish user errors from the execution errors.
 type InvalidParamsError struct {
@@ -73,3 +76,26 @@ func haveAll(buf []bool) bool {
 	}
 	return true
 }
+
+// reshareGroupPubs returns the nodes participating in the key resharing.
+// The new members are listed first, so their indexes in the group match
+// the indexes in the new group. They are followed by the leaving members.
+func reshareGroupPubs(newPubs, oldPubs []*cryptolib.PublicKey) []*cryptolib.PublicKey {
+	groupPubs := make([]*cryptolib.PublicKey, 0, len(newPubs)+len(oldPubs))
+	groupPubs = append(groupPubs, newPubs...)
+	for i := range oldPubs {
+		if _, ok := pubKeyIndex(newPubs, oldPubs[i]); !ok {
+			groupPubs = append(groupPubs, oldPubs[i])
+		}
+	}
+	return groupPubs
+}
+
+func pubKeyIndex(pubKeys []*cryptolib.PublicKey, pubKey *cryptolib.PublicKey) (uint16, bool) {
+	for i := range pubKeys {
+		if pubKeys[i].Equals(pubKey) {
+			return uint16(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,459 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evmindex implements an archive of the EVM blocks kept by the node outside the chain state.
+// The evm contract keeps only the latest blocks in its BlockchainDB (see the block keep amount), so
+// without the archive the older transactions, receipts and logs can't be queried anymore.
+package evmindex
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/evm/evmtypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/emulator"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
+)
+
+const (
+	// last indexed block number
+	keyLastBlockNumber = 'n'
+
+	// blocks:
+
+	keyBlockByNumber     = 'b'
+	keyBlockHashByNumber = 'k'
+	keyBloomByNumber     = 'l'
+	keyReceiptByNumber   = 'r'
+
+	// indexes:
+
+	keyBlockNumberByBlockHash = 'h'
+	keyTxLocationByTxHash     = 't'
+
+	// gaps: first block number => last block number
+	keyGapByFirstBlock = 'g'
+)
+
+// MaxLogsBlockRange is the maximum number of blocks scanned by Logs when the query has no lower bound
+const MaxLogsBlockRange = 1000
+
+// ErrPruned is returned for the blocks pruned from the chain state before they could be indexed
+var ErrPruned = errors.New("pruned from the chain state before it was indexed")
+
+// Gap is a range of blocks pruned from the chain state before they could be indexed
+type Gap struct {
+	From uint64
+	To   uint64
+}
+
+// Index stores the headers, transactions, receipts and log blooms of all the EVM blocks of a chain
+type Index struct {
+	mutex sync.RWMutex
+	store kvstore.KVStore
+}
+
+func New(store kvstore.KVStore) *Index {
+	return &Index{store: store}
+}
+
+func makeKey(prefix byte, parts ...[]byte) []byte {
+	ret := []byte{prefix}
+	for _, p := range parts {
+		ret = append(ret, p...)
+	}
+	return ret
+}
+
+func (idx *Index) get(key []byte) ([]byte, error) {
+	ret, err := idx.store.Get(key)
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+// LastBlockNumber returns the number of the latest indexed block
+func (idx *Index) LastBlockNumber() (uint64, bool, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.lastBlockNumber()
+}
+
+func (idx *Index) lastBlockNumber() (uint64, bool, error) {
+	b, err := idx.get(makeKey(keyLastBlockNumber))
+	if err != nil || b == nil {
+		return 0, false, err
+	}
+	n, err := codec.DecodeUint64(b)
+	return n, err == nil, err
+}
+
+// Update indexes the blocks of the chain state not indexed yet. The blocks already pruned from the
+// BlockchainDB, because the index was enabled late or the node fell behind by more than the block keep amount,
+// can't be indexed anymore: they are recorded as a gap, which is returned and reported by Gaps
+func (idx *Index) Update(chainState kv.KVStoreReader) (*Gap, error) {
+	var bc *emulator.BlockchainDB
+	var latest uint64
+	err := panicutil.CatchPanic(func() {
+		bc = evmimpl.GetBlockchainDB(subrealm.NewReadOnly(chainState, kv.Key(evm.Contract.Hname().Bytes())))
+		if bc.Initialized() {
+			latest = bc.GetNumber()
+		} else {
+			bc = nil
+		}
+	})
+	if err != nil || bc == nil {
+		return nil, err
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	next := uint64(0)
+	last, ok, err := idx.lastBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if last >= latest {
+			return nil, nil
+		}
+		next = last + 1
+	}
+
+	batch, err := idx.store.Batched()
+	if err != nil {
+		return nil, err
+	}
+	var gap *Gap
+	for n := next; n <= latest; n++ {
+		indexed, err := idx.indexBlock(batch, bc, n)
+		if err != nil {
+			batch.Cancel()
+			return nil, err
+		}
+		if indexed {
+			continue
+		}
+		// the pruned blocks are the oldest ones, so they are contiguous
+		if gap == nil {
+			gap = &Gap{From: n}
+		}
+		gap.To = n
+	}
+	if gap != nil {
+		if err := batch.Set(makeKey(keyGapByFirstBlock, codec.EncodeUint64(gap.From)), codec.EncodeUint64(gap.To)); err != nil {
+			batch.Cancel()
+			return nil, err
+		}
+	}
+	if err := batch.Set(makeKey(keyLastBlockNumber), codec.EncodeUint64(latest)); err != nil {
+		batch.Cancel()
+		return nil, err
+	}
+	return gap, batch.Commit()
+}
+
+// Gaps returns the ranges of blocks that are not indexed because they were pruned from the chain state
+func (idx *Index) Gaps() ([]*Gap, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.gaps()
+}
+
+func (idx *Index) gaps() ([]*Gap, error) {
+	var ret []*Gap
+	var decodeErr error
+	err := idx.store.Iterate(makeKey(keyGapByFirstBlock), func(key kvstore.Key, value kvstore.Value) bool {
+		gap := &Gap{}
+		if gap.From, decodeErr = codec.DecodeUint64(key[1:]); decodeErr != nil {
+			return false
+		}
+		if gap.To, decodeErr = codec.DecodeUint64(value); decodeErr != nil {
+			return false
+		}
+		ret = append(ret, gap)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, decodeErr
+}
+
+// checkPruned returns ErrPruned if any block in the range is in a gap
+func (idx *Index) checkPruned(from, to uint64) error {
+	gaps, err := idx.gaps()
+	if err != nil {
+		return err
+	}
+	for _, gap := range gaps {
+		if gap.From <= to && from <= gap.To {
+			return fmt.Errorf("blocks %d-%d: %w", gap.From, gap.To, ErrPruned)
+		}
+	}
+	return nil
+}
+
+// indexBlock adds the block to the batch, returns false if the block was already pruned from the chain state
+func (idx *Index) indexBlock(batch kvstore.BatchedMutations, bc *emulator.BlockchainDB, blockNumber uint64) (bool, error) {
+	var block *types.Block
+	var receipts []*types.Receipt
+	var blockHash common.Hash
+	err := panicutil.CatchPanic(func() {
+		block = bc.GetBlockByNumber(blockNumber)
+		if block == nil {
+			return
+		}
+		receipts = bc.GetReceiptsByBlockNumber(blockNumber)
+		blockHash = bc.GetBlockHashByBlockNumber(blockNumber)
+	})
+	if err != nil || block == nil {
+		return false, err
+	}
+
+	n := codec.EncodeUint64(blockNumber)
+	if err := batch.Set(makeKey(keyBlockByNumber, n), evmtypes.EncodeBlock(block)); err != nil {
+		return false, err
+	}
+	if err := batch.Set(makeKey(keyBloomByNumber, n), block.Bloom().Bytes()); err != nil {
+		return false, err
+	}
+	// the hash of the BlockchainDB is the one used by the lookups, it can differ from the hash of the
+	// stored header when the parent block was already pruned
+	if err := batch.Set(makeKey(keyBlockHashByNumber, n), blockHash.Bytes()); err != nil {
+		return false, err
+	}
+	if err := batch.Set(makeKey(keyBlockNumberByBlockHash, blockHash.Bytes()), n); err != nil {
+		return false, err
+	}
+	for i, tx := range block.Transactions() {
+		i32 := codec.EncodeUint32(uint32(i))
+		if err := batch.Set(makeKey(keyTxLocationByTxHash, tx.Hash().Bytes()), append(codec.EncodeUint64(blockNumber), i32...)); err != nil {
+			return false, err
+		}
+		if err := batch.Set(makeKey(keyReceiptByNumber, n, i32), evmtypes.EncodeReceiptFull(receipts[i])); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// BlockByNumber returns the indexed block, or nil if it is not indexed. Returns ErrPruned if the block is
+// in a gap
+func (idx *Index) BlockByNumber(blockNumber uint64) (*types.Block, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.blockByNumber(blockNumber)
+}
+
+func (idx *Index) blockByNumber(blockNumber uint64) (*types.Block, error) {
+	b, err := idx.get(makeKey(keyBlockByNumber, codec.EncodeUint64(blockNumber)))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, idx.checkPruned(blockNumber, blockNumber)
+	}
+	return evmtypes.DecodeBlock(b)
+}
+
+// BlockByHash returns the indexed block, or nil if it is not indexed
+func (idx *Index) BlockByHash(hash common.Hash) (*types.Block, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	blockNumber, ok, err := idx.blockNumberByHash(hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return idx.blockByNumber(blockNumber)
+}
+
+func (idx *Index) blockNumberByHash(hash common.Hash) (uint64, bool, error) {
+	b, err := idx.get(makeKey(keyBlockNumberByBlockHash, hash.Bytes()))
+	if err != nil || b == nil {
+		return 0, false, err
+	}
+	n, err := codec.DecodeUint64(b)
+	return n, err == nil, err
+}
+
+func (idx *Index) blockHash(blockNumber uint64) (common.Hash, error) {
+	b, err := idx.get(makeKey(keyBlockHashByNumber, codec.EncodeUint64(blockNumber)))
+	return common.BytesToHash(b), err
+}
+
+func (idx *Index) txLocation(txHash common.Hash) (blockNumber uint64, index uint32, ok bool, err error) {
+	b, err := idx.get(makeKey(keyTxLocationByTxHash, txHash.Bytes()))
+	if err != nil || b == nil {
+		return 0, 0, false, err
+	}
+	if blockNumber, err = codec.DecodeUint64(b[:8]); err != nil {
+		return 0, 0, false, err
+	}
+	if index, err = codec.DecodeUint32(b[8:]); err != nil {
+		return 0, 0, false, err
+	}
+	return blockNumber, index, true, nil
+}
+
+// TransactionByHash returns the indexed transaction and its location, or a nil transaction if it is not indexed
+func (idx *Index) TransactionByHash(hash common.Hash) (tx *types.Transaction, blockHash common.Hash, blockNumber, index uint64, err error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	n, i, ok, err := idx.txLocation(hash)
+	if err != nil || !ok {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	return idx.transactionByBlockNumberAndIndex(n, uint64(i))
+}
+
+// TransactionByBlockNumberAndIndex returns the indexed transaction and its location, or a nil transaction
+// if it is not indexed
+func (idx *Index) TransactionByBlockNumberAndIndex(blockNumber, index uint64) (tx *types.Transaction, blockHash common.Hash, blockNumberRet, indexRet uint64, err error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.transactionByBlockNumberAndIndex(blockNumber, index)
+}
+
+func (idx *Index) transactionByBlockNumberAndIndex(blockNumber, index uint64) (tx *types.Transaction, blockHash common.Hash, blockNumberRet, indexRet uint64, err error) {
+	block, err := idx.blockByNumber(blockNumber)
+	if err != nil || block == nil || index >= uint64(len(block.Transactions())) {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	blockHash, err = idx.blockHash(blockNumber)
+	if err != nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	return block.Transactions()[index], blockHash, blockNumber, index, nil
+}
+
+// TransactionByBlockHashAndIndex returns the indexed transaction and its location, or a nil transaction
+// if it is not indexed
+func (idx *Index) TransactionByBlockHashAndIndex(hash common.Hash, index uint64) (tx *types.Transaction, blockHash common.Hash, blockNumber, indexRet uint64, err error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	n, ok, err := idx.blockNumberByHash(hash)
+	if err != nil || !ok {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	return idx.transactionByBlockNumberAndIndex(n, index)
+}
+
+// TransactionReceipt returns the receipt of the indexed transaction, or nil if it is not indexed
+func (idx *Index) TransactionReceipt(txHash common.Hash) (*types.Receipt, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	n, i, ok, err := idx.txLocation(txHash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	receipts, err := idx.receipts(n)
+	if err != nil || int(i) >= len(receipts) {
+		return nil, err
+	}
+	return receipts[i], nil
+}
+
+func (idx *Index) receipt(blockNumber uint64, i uint32) (*types.Receipt, error) {
+	b, err := idx.get(makeKey(keyReceiptByNumber, codec.EncodeUint64(blockNumber), codec.EncodeUint32(i)))
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return evmtypes.DecodeReceiptFull(b)
+}
+
+// receipts returns the receipts of the block, with the derived fields of the logs filled in
+func (idx *Index) receipts(blockNumber uint64) ([]*types.Receipt, error) {
+	var ret []*types.Receipt
+	logIndex := uint(0)
+	for i := uint32(0); ; i++ {
+		r, err := idx.receipt(blockNumber, i)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return ret, nil
+		}
+		r.TransactionIndex = uint(i)
+		for _, log := range r.Logs {
+			log.BlockNumber = blockNumber
+			log.BlockHash = r.BlockHash
+			log.TxHash = r.TxHash
+			log.TxIndex = uint(i)
+			log.Index = logIndex
+			logIndex++
+		}
+		ret = append(ret, r)
+	}
+}
+
+// Logs returns the logs of the indexed blocks matching the query. latest is the number of the
+// latest block of the chain, used when the query has no upper bound. Without a lower bound, only the
+// latest MaxLogsBlockRange blocks are scanned. Returns ErrPruned if the range includes a gap
+func (idx *Index) Logs(q *ethereum.FilterQuery, latest uint64) ([]*types.Log, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	if q.BlockHash != nil {
+		n, ok, err := idx.blockNumberByHash(*q.BlockHash)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return idx.blockLogs(q, n)
+	}
+
+	// skip genesis since it has no logs
+	from := uint64(1)
+	if q.FromBlock != nil && q.FromBlock.Sign() > 0 {
+		from = q.FromBlock.Uint64()
+	}
+	to := latest
+	if q.ToBlock != nil && q.ToBlock.Sign() >= 0 && q.ToBlock.Cmp(new(big.Int).SetUint64(latest)) <= 0 {
+		to = q.ToBlock.Uint64()
+	}
+	if q.FromBlock == nil && to >= from+MaxLogsBlockRange {
+		from = to - MaxLogsBlockRange + 1
+	}
+	if from <= to {
+		if err := idx.checkPruned(from, to); err != nil {
+			return nil, err
+		}
+	}
+	var logs []*types.Log
+	for n := from; n <= to; n++ {
+		bloom, err := idx.get(makeKey(keyBloomByNumber, codec.EncodeUint64(n)))
+		if err != nil {
+			return nil, err
+		}
+		if bloom == nil || !emulator.BloomMatches(types.BytesToBloom(bloom), q) {
+			continue
+		}
+		blockLogs, err := idx.blockLogs(q, n)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, blockLogs...)
+	}
+	return logs, nil
+}
+
+func (idx *Index) blockLogs(q *ethereum.FilterQuery, blockNumber uint64) ([]*types.Log, error) {
+	receipts, err := idx.receipts(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return emulator.FilterReceiptLogs(q, receipts), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evmindex_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexGaps(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	chainOwner, _ := env.NewKeyPairWithFunds()
+	ch, _, _ := env.NewChainExt(chainOwner, 0, "chain1", solo.InitChainOptions{
+		InitRequestParameters: dict.Dict{
+			root.ParamEVM(evm.FieldBlockKeepAmount): codec.EncodeInt32(2),
+		},
+	})
+	userKey, _ := env.NewKeyPairWithFunds()
+
+	// the index is not updated by the chain, as if the node fell behind
+	idx := evmindex.New(mapdb.NewMapDB())
+	update := func() *evmindex.Gap {
+		ch.StateReader.SetBaseline()
+		gap, err := idx.Update(ch.StateReader.KVStoreReader())
+		require.NoError(t, err)
+		return gap
+	}
+	lastBlockNumber := func() uint64 {
+		n, ok, err := idx.LastBlockNumber()
+		require.NoError(t, err)
+		require.True(t, ok)
+		return n
+	}
+
+	update()
+	first := lastBlockNumber()
+	gaps, err := idx.Gaps()
+	require.NoError(t, err)
+	initialGaps := len(gaps)
+
+	for i := 0; i < 5; i++ {
+		ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+	}
+	gap := update()
+	require.NotNil(t, gap)
+	require.EqualValues(t, first+1, gap.From)
+	latest := lastBlockNumber()
+	require.Less(t, gap.To, latest)
+
+	gaps, err = idx.Gaps()
+	require.NoError(t, err)
+	require.Len(t, gaps, initialGaps+1)
+	require.Equal(t, gap, gaps[len(gaps)-1])
+
+	// the blocks of the gap are reported as pruned, the following ones are indexed
+	_, err = idx.BlockByNumber(gap.From)
+	require.ErrorIs(t, err, evmindex.ErrPruned)
+	block, err := idx.BlockByNumber(gap.To + 1)
+	require.NoError(t, err)
+	require.EqualValues(t, gap.To+1, block.NumberU64())
+	block, err = idx.BlockByNumber(latest + 1)
+	require.NoError(t, err)
+	require.Nil(t, block)
+
+	_, err = idx.Logs(&ethereum.FilterQuery{FromBlock: new(big.Int).SetUint64(gap.From)}, latest)
+	require.ErrorIs(t, err, evmindex.ErrPruned)
+	_, err = idx.Logs(&ethereum.FilterQuery{}, latest)
+	require.ErrorIs(t, err, evmindex.ErrPruned)
+	_, err = idx.Logs(&ethereum.FilterQuery{FromBlock: new(big.Int).SetUint64(gap.To + 1)}, latest)
+	require.NoError(t, err)
+	// without a lower bound only the latest blocks are scanned
+	_, err = idx.Logs(&ethereum.FilterQuery{}, gap.To+evmindex.MaxLogsBlockRange)
+	require.NoError(t, err)
+
+	// the index keeps up with the chain, no more gaps
+	require.Nil(t, update())
+	ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+	require.Nil(t, update())
+	require.EqualValues(t, latest+1, lastBlockNumber())
+	gaps, err = idx.Gaps()
+	require.NoError(t, err)
+	require.Len(t, gaps, initialGaps+1)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/iotaledger/hive.go/marshalutil"
 )
 
@@ -28,6 +29,14 @@ func EncodeCallMsg(c ethereum.CallMsg) []byte {
 		writeBytes(m, c.Value.Bytes())
 	}
 	writeBytes(m, c.Data)
+	m.WriteUint32(uint32(len(c.AccessList)))
+	for _, tuple := range c.AccessList {
+		m.WriteBytes(tuple.Address.Bytes())
+		m.WriteUint32(uint32(len(tuple.StorageKeys)))
+		for _, key := range tuple.StorageKeys {
+			m.WriteBytes(key.Bytes())
+		}
+	}
 	return m.Bytes()
 }
 
@@ -81,5 +90,28 @@ func DecodeCallMsg(callArgsBytes []byte) (ret ethereum.CallMsg, err error) {
 	if ret.Data, err = readBytes(m); err != nil {
 		return
 	}
+
+	var n uint32
+	if n, err = m.ReadUint32(); err != nil {
+		return
+	}
+	for i := uint32(0); i < n; i++ {
+		var tuple types.AccessTuple
+		if b, err = m.ReadBytes(common.AddressLength); err != nil {
+			return
+		}
+		tuple.Address.SetBytes(b)
+		var numKeys uint32
+		if numKeys, err = m.ReadUint32(); err != nil {
+			return
+		}
+		for j := uint32(0); j < numKeys; j++ {
+			if b, err = m.ReadBytes(common.HashLength); err != nil {
+				return
+			}
+			tuple.StorageKeys = append(tuple.StorageKeys, common.BytesToHash(b))
+		}
+		ret.AccessList = append(ret.AccessList, tuple)
+	}
 	return ret, err
 }
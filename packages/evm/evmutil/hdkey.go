@@ -0,0 +1,62 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evmutil
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"golang.org/x/xerrors"
+)
+
+// EthereumDerivationPath returns the BIP-44 path m/44'/60'/0'/0/index used by the Ethereum wallets
+func EthereumDerivationPath(index uint32) []uint32 {
+	return []uint32{
+		44 | cryptolib.HardenedIndex,
+		60 | cryptolib.HardenedIndex,
+		0 | cryptolib.HardenedIndex,
+		0,
+		index,
+	}
+}
+
+// NewKeyFromMnemonicSeed derives the secp256k1 key of the path from the BIP-39 seed, as specified by BIP-32
+func NewKeyFromMnemonicSeed(seed []byte, path []uint32) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+	key, chainCode := cryptolib.HMACSHA512([]byte("Bitcoin seed"), seed)
+	if k := new(big.Int).SetBytes(key); k.Sign() == 0 || k.Cmp(n) >= 0 {
+		return nil, xerrors.New("invalid master key")
+	}
+	for _, index := range path {
+		var data []byte
+		if index >= cryptolib.HardenedIndex {
+			data = append([]byte{0}, key...)
+		} else {
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		var indexBytes [4]byte
+		binary.BigEndian.PutUint32(indexBytes[:], index)
+		data = append(data, indexBytes[:]...)
+		var tweak []byte
+		tweak, chainCode = cryptolib.HMACSHA512(chainCode, data)
+		t := new(big.Int).SetBytes(tweak)
+		if t.Cmp(n) >= 0 {
+			return nil, xerrors.Errorf("invalid key at index %d", index)
+		}
+		k := t.Add(t, new(big.Int).SetBytes(key))
+		k.Mod(k, n)
+		if k.Sign() == 0 {
+			return nil, xerrors.Errorf("invalid key at index %d", index)
+		}
+		key = k.FillBytes(make([]byte, 32))
+	}
+	return crypto.ToECDSA(key)
+}
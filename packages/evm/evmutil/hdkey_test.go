@@ -0,0 +1,18 @@
+package evmutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyFromMnemonicSeed(t *testing.T) {
+	// the first development account of hardhat
+	seed, err := cryptolib.SeedFromMnemonic("test test test test test test test test test test test junk")
+	require.NoError(t, err)
+	key, err := NewKeyFromMnemonicSeed(seed, EthereumDerivationPath(0))
+	require.NoError(t, err)
+	require.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
@@ -0,0 +1,204 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evmutil
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotaledger/hive.go/marshalutil"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/trie.go/models/trie_blake2b"
+	"github.com/iotaledger/trie.go/models/trie_blake2b/trie_blake2b_verify"
+	"github.com/iotaledger/trie.go/trie"
+	"golang.org/x/xerrors"
+)
+
+// StateLayout describes where the data of an Ethereum account is stored in
+// the ISC state, and how it is encoded
+type StateLayout interface {
+	NonceKey(addr common.Address) []byte
+	CodeKey(addr common.Address) []byte
+	StorageKey(addr common.Address, slot common.Hash) []byte
+	BalanceKey(addr common.Address) []byte
+	DecodeNonce(b []byte) (uint64, error)
+	DecodeBalance(b []byte) (*big.Int, error)
+}
+
+// StateProof is a Merkle proof of a key of the ISC state. A nil Value means
+// that the proof is a proof of absence.
+type StateProof struct {
+	Key   []byte
+	Value []byte
+	Proof *trie_blake2b.Proof
+}
+
+// NewStateProofs pairs each key with its value and proof
+func NewStateProofs(keys, values [][]byte, proofs []*trie_blake2b.Proof) []*StateProof {
+	ret := make([]*StateProof, len(keys))
+	for i := range keys {
+		ret[i] = &StateProof{Key: keys[i], Value: values[i], Proof: proofs[i]}
+	}
+	return ret
+}
+
+type StorageProof struct {
+	Key   common.Hash
+	Value common.Hash
+	Proof *StateProof
+}
+
+// AccountProof is the EIP-1186 account proof, with each field backed by a
+// Merkle proof of the ISC state with index StateIndex
+type AccountProof struct {
+	Address      common.Address
+	StateIndex   uint32
+	Balance      *big.Int
+	CodeHash     common.Hash
+	Nonce        uint64
+	BalanceProof *StateProof
+	CodeProof    *StateProof
+	NonceProof   *StateProof
+	StorageProof []*StorageProof
+}
+
+// the ISC state trie is hexary, with 20 byte blake2b hashing
+const (
+	stateTrieArity    = trie.PathArity16
+	stateTrieHashSize = trie_blake2b.HashSize160
+)
+
+// L1StateCommitment returns the root commitment of the ISC state, stored in
+// the state metadata of the chain's AliasOutput
+func L1StateCommitment(aliasOutput *iotago.AliasOutput) ([]byte, error) {
+	// the state metadata starts with the root commitment, followed by the block hash
+	if len(aliasOutput.StateMetadata) < int(stateTrieHashSize) {
+		return nil, xerrors.New("invalid state metadata in alias output")
+	}
+	return aliasOutput.StateMetadata[:stateTrieHashSize], nil
+}
+
+// VerifyStateProof checks the proof against the state commitment of the AliasOutput
+func VerifyStateProof(aliasOutput *iotago.AliasOutput, key []byte, p *StateProof) error {
+	if p == nil || p.Proof == nil {
+		return xerrors.New("missing proof")
+	}
+	if p.Proof.PathArity != stateTrieArity || p.Proof.HashSize != stateTrieHashSize {
+		return xerrors.New("proof does not match the ISC state trie model")
+	}
+	// the trie proof holds the key unpacked into path elements
+	if !bytes.Equal(p.Key, key) || !bytes.Equal(p.Proof.Key, trie.UnpackBytes(key, stateTrieArity)) {
+		return xerrors.Errorf("proof is not for key %x", key)
+	}
+	root, err := L1StateCommitment(aliasOutput)
+	if err != nil {
+		return err
+	}
+	if p.Value == nil {
+		if err := trie_blake2b_verify.Validate(p.Proof, root); err != nil {
+			return err
+		}
+		if !trie_blake2b_verify.IsProofOfAbsence(p.Proof) {
+			return xerrors.Errorf("key %x is present in the state", key)
+		}
+		return nil
+	}
+	return trie_blake2b_verify.ValidateWithValue(p.Proof, root, p.Value)
+}
+
+// VerifyAccountProof checks that all fields of the account proof are committed
+// by the state commitment of the AliasOutput
+func VerifyAccountProof(aliasOutput *iotago.AliasOutput, layout StateLayout, p *AccountProof) error {
+	if aliasOutput.StateIndex != p.StateIndex {
+		return xerrors.Errorf("state index mismatch: proof is for %d, alias output is for %d", p.StateIndex, aliasOutput.StateIndex)
+	}
+
+	if err := VerifyStateProof(aliasOutput, layout.NonceKey(p.Address), p.NonceProof); err != nil {
+		return xerrors.Errorf("invalid nonce proof: %w", err)
+	}
+	nonce := uint64(0)
+	if p.NonceProof.Value != nil {
+		var err error
+		if nonce, err = layout.DecodeNonce(p.NonceProof.Value); err != nil {
+			return xerrors.Errorf("invalid nonce proof: %w", err)
+		}
+	}
+	if nonce != p.Nonce {
+		return xerrors.Errorf("nonce mismatch: got %d, proven %d", p.Nonce, nonce)
+	}
+
+	if err := VerifyStateProof(aliasOutput, layout.BalanceKey(p.Address), p.BalanceProof); err != nil {
+		return xerrors.Errorf("invalid balance proof: %w", err)
+	}
+	balance := new(big.Int)
+	if p.BalanceProof.Value != nil {
+		var err error
+		if balance, err = layout.DecodeBalance(p.BalanceProof.Value); err != nil {
+			return xerrors.Errorf("invalid balance proof: %w", err)
+		}
+	}
+	if p.Balance == nil || balance.Cmp(p.Balance) != 0 {
+		return xerrors.Errorf("balance mismatch: got %v, proven %v", p.Balance, balance)
+	}
+
+	if err := VerifyStateProof(aliasOutput, layout.CodeKey(p.Address), p.CodeProof); err != nil {
+		return xerrors.Errorf("invalid code proof: %w", err)
+	}
+	if codeHash := crypto.Keccak256Hash(p.CodeProof.Value); codeHash != p.CodeHash {
+		return xerrors.Errorf("code hash mismatch: got %s, proven %s", p.CodeHash, codeHash)
+	}
+
+	for _, sp := range p.StorageProof {
+		if err := VerifyStateProof(aliasOutput, layout.StorageKey(p.Address, sp.Key), sp.Proof); err != nil {
+			return xerrors.Errorf("invalid storage proof for slot %s: %w", sp.Key, err)
+		}
+		if value := common.BytesToHash(sp.Proof.Value); value != sp.Value {
+			return xerrors.Errorf("storage value mismatch for slot %s: got %s, proven %s", sp.Key, sp.Value, value)
+		}
+	}
+	return nil
+}
+
+func (p *StateProof) Bytes() []byte {
+	m := marshalutil.New()
+	m.WriteUint32(uint32(len(p.Key)))
+	m.WriteBytes(p.Key)
+	m.WriteBool(p.Value != nil)
+	if p.Value != nil {
+		m.WriteUint32(uint32(len(p.Value)))
+		m.WriteBytes(p.Value)
+	}
+	m.WriteBytes(p.Proof.Bytes())
+	return m.Bytes()
+}
+
+func StateProofFromBytes(data []byte) (*StateProof, error) {
+	m := marshalutil.New(data)
+	ret := &StateProof{}
+	n, err := m.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	if ret.Key, err = m.ReadBytes(int(n)); err != nil {
+		return nil, err
+	}
+	hasValue, err := m.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	if hasValue {
+		if n, err = m.ReadUint32(); err != nil {
+			return nil, err
+		}
+		if ret.Value, err = m.ReadBytes(int(n)); err != nil {
+			return nil, err
+		}
+	}
+	if ret.Proof, err = trie_blake2b.ProofFromBytes(m.ReadRemainingBytes()); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func Signer(chainID *big.Int) types.Signer {
-	return types.NewEIP155Signer(chainID)
+	return types.LatestSignerForChainID(chainID)
 }
 
 func GetSender(tx *types.Transaction) (common.Address, error) {
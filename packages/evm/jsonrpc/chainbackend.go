@@ -6,6 +6,9 @@ package jsonrpc
 import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
+	"github.com/iotaledger/wasp/packages/evm/evmutil"
+	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/parameters"
 )
@@ -14,5 +17,10 @@ type ChainBackend interface {
 	EVMSendTransaction(tx *types.Transaction) error
 	EVMEstimateGas(callMsg ethereum.CallMsg) (uint64, error)
 	ISCCallView(scName string, funName string, args dict.Dict) (dict.Dict, error)
+	ISCMempoolRequests() []isc.Request
+	// ISCStateProofs returns the index of the latest state, and the Merkle proofs of the keys in it
+	ISCStateProofs(keys [][]byte) (uint32, []*evmutil.StateProof, error)
+	// EVMIndex returns the node-side archive of the EVM blocks, or nil if it is not enabled
+	EVMIndex() *evmindex.Index
 	BaseToken() *parameters.BaseToken
 }
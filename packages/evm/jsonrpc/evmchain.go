@@ -10,19 +10,40 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/iotaledger/wasp/packages/evm/evmtypes"
 	"github.com/iotaledger/wasp/packages/evm/evmutil"
+	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/core/errors"
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
+// maxFeeHistory is the maximum amount of blocks returned by FeeHistory
+const maxFeeHistory = 1024
+
+const (
+	// maxNonceGap is how far ahead of the nonce of its sender the nonce of a transaction can be
+	maxNonceGap = 64
+	// maxTransactionsPerSender is the maximum number of transactions of a sender waiting in the mempool
+	maxTransactionsPerSender = 64
+)
+
+// FeeHistory is the result of EVMChain.FeeHistory
+type FeeHistory struct {
+	OldestBlock  *big.Int
+	Reward       [][]*big.Int
+	BaseFee      []*big.Int
+	GasUsedRatio []float64
+}
+
 type EVMChain struct {
 	backend ChainBackend
 	chainID uint16
@@ -85,37 +106,196 @@ func (e *EVMChain) SendTransaction(tx *types.Transaction) error {
 	if err != nil {
 		return fmt.Errorf("invalid transaction: %w", err)
 	}
-	if tx.Nonce() != expectedNonce {
-		return fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), expectedNonce)
+	// a transaction with a higher nonce waits in the mempool for the previous ones
+	if tx.Nonce() < expectedNonce {
+		return fmt.Errorf("invalid transaction nonce: got %d, want at least %d", tx.Nonce(), expectedNonce)
+	}
+	if tx.Nonce() >= expectedNonce+maxNonceGap {
+		return fmt.Errorf("invalid transaction nonce: got %d, want less than %d", tx.Nonce(), expectedNonce+maxNonceGap)
+	}
+	if err := e.checkTransactionsInMempool(sender, tx); err != nil {
+		return err
+	}
+
+	if tx.Type() == types.DynamicFeeTxType {
+		if err := e.checkLondonActive(); err != nil {
+			return err
+		}
 	}
 
-	if err := e.checkEnoughL2FundsForGasBudget(sender, tx.Gas()); err != nil {
+	gasRatio, err := e.GasRatio()
+	if err != nil {
+		return fmt.Errorf("could not fetch gas ratio: %w", err)
+	}
+	gasFeePolicy, err := e.GasFeePolicy()
+	if err != nil {
+		return fmt.Errorf("could not fetch the gas fee policy: %w", err)
+	}
+	// the fee is charged by ISC according to the fee policy; the maxFeePerGas
+	// of an EIP-1559 transaction is honored as a cap of the gas price
+	if gasPrice := evmGasPrice(gasFeePolicy, &gasRatio); tx.Type() == types.DynamicFeeTxType && tx.GasFeeCap().Cmp(gasPrice) < 0 {
+		return fmt.Errorf("max fee per gas too low: got %v, want at least %v", tx.GasFeeCap(), gasPrice)
+	}
+	if err := e.checkEnoughL2FundsForGasBudget(sender, tx.Gas(), gasFeePolicy, &gasRatio); err != nil {
 		return err
 	}
 	return e.backend.EVMSendTransaction(tx)
 }
 
-func (e *EVMChain) checkEnoughL2FundsForGasBudget(sender common.Address, evmGas uint64) error {
-	gasRatio, err := e.GasRatio()
+// LondonBlock returns the number of the block activating the London hard fork, nil if it is not scheduled
+func (e *EVMChain) LondonBlock() (*big.Int, error) {
+	ret, err := e.backend.ISCCallView(evm.Contract.Name, evm.FuncGetLondonBlock.Name, nil)
 	if err != nil {
-		return fmt.Errorf("could not fetch gas ratio: %w", err)
+		return nil, err
 	}
-	balance, err := e.Balance(sender, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if !ret.MustHas(evm.FieldResult) {
+		return nil, nil
+	}
+	n, err := codec.DecodeUint64(ret.MustGet(evm.FieldResult))
 	if err != nil {
-		return fmt.Errorf("could not fetch sender balance: %w", err)
+		return nil, err
 	}
-	gasFeePolicy, err := e.GasFeePolicy()
+	return new(big.Int).SetUint64(n), nil
+}
+
+// checkLondonActive checks that the London hard fork is active for the pending block, as required by
+// the EIP-1559 transactions
+func (e *EVMChain) checkLondonActive() error {
+	londonBlock, err := e.LondonBlock()
 	if err != nil {
-		return fmt.Errorf("could not fetch the gas fee policy: %w", err)
+		return fmt.Errorf("could not fetch the London block: %w", err)
+	}
+	latest, err := e.BlockNumber()
+	if err != nil {
+		return err
+	}
+	if londonBlock == nil || londonBlock.Cmp(new(big.Int).Add(latest, big.NewInt(1))) > 0 {
+		return fmt.Errorf("invalid transaction: %w: the London hard fork is not active", types.ErrTxTypeNotSupported)
+	}
+	return nil
+}
+
+// checkTransactionsInMempool limits the number of transactions of the sender waiting in the mempool. A transaction
+// replacing one with the same nonce is accepted anyway
+func (e *EVMChain) checkTransactionsInMempool(sender common.Address, tx *types.Transaction) error {
+	n := 0
+	for _, req := range e.backend.ISCMempoolRequests() {
+		other, ok := isc.EVMTransaction(req)
+		if !ok || req.SenderAccount().(*isc.EthereumAddressAgentID).EthAddress() != sender {
+			continue
+		}
+		if other.Nonce() == tx.Nonce() {
+			return nil
+		}
+		n++
+	}
+	if n >= maxTransactionsPerSender {
+		return fmt.Errorf("too many transactions of %s in the mempool: %d", sender, n)
+	}
+	return nil
+}
+
+func (e *EVMChain) checkEnoughL2FundsForGasBudget(sender common.Address, evmGas uint64, gasFeePolicy *gas.GasFeePolicy, gasRatio *util.Ratio32) error {
+	balance, err := e.Balance(sender, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		return fmt.Errorf("could not fetch sender balance: %w", err)
 	}
 	iscGasBudgetAffordable := gasFeePolicy.AffordableGasBudgetFromAvailableTokens(balance.Uint64())
-	iscGasBudgetTx := evmtypes.EVMGasToISC(evmGas, &gasRatio)
+	iscGasBudgetTx := evmtypes.EVMGasToISC(evmGas, gasRatio)
 	if iscGasBudgetAffordable < iscGasBudgetTx {
 		return fmt.Errorf("sender has not enough L2 funds to cover tx gas budget")
 	}
 	return nil
 }
 
+// GasPrice returns the price of one unit of EVM gas, in base tokens, derived
+// from the governance fee policy and the ISC/EVM gas ratio
+func (e *EVMChain) GasPrice() (*big.Int, error) {
+	gasRatio, err := e.GasRatio()
+	if err != nil {
+		return nil, err
+	}
+	gasFeePolicy, err := e.GasFeePolicy()
+	if err != nil {
+		return nil, err
+	}
+	return evmGasPrice(gasFeePolicy, &gasRatio), nil
+}
+
+// evmGasPrice converts the fee policy into a price per unit of EVM gas.
+// Since the balance of an Ethereum account is expressed in base tokens,
+// fractional prices are rounded up, so that gasPrice * gas covers the fee
+// charged by ISC for the gas.
+func evmGasPrice(gasFeePolicy *gas.GasFeePolicy, gasRatio *util.Ratio32) *big.Int {
+	if gasFeePolicy.GasPerToken == 0 {
+		return new(big.Int)
+	}
+	// tokens per EVM gas = ceil((ISC gas per EVM gas) / (ISC gas per token))
+	num := new(big.Int).SetUint64(uint64(gasRatio.A))
+	den := new(big.Int).SetUint64(uint64(gasRatio.B) * gasFeePolicy.GasPerToken)
+	num.Add(num, den)
+	num.Sub(num, big.NewInt(1))
+	return num.Div(num, den)
+}
+
+// FeeHistory returns the base fee and gas usage of blockCount blocks ending
+// at newestBlock (nil means latest). The base fee is the gas price derived
+// from the current fee policy; ISC does not have priority fees, so all
+// rewards are zero.
+func (e *EVMChain) FeeHistory(blockCount uint64, newestBlock *big.Int, rewardPercentiles []float64) (*FeeHistory, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid reward percentile: %f", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("invalid reward percentile: #%d:%f > #%d:%f", i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	latest, err := e.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if newestBlock == nil || newestBlock.Cmp(latest) > 0 {
+		newestBlock = latest
+	}
+	if blockCount > maxFeeHistory {
+		blockCount = maxFeeHistory
+	}
+	if blockCount > newestBlock.Uint64()+1 {
+		blockCount = newestBlock.Uint64() + 1
+	}
+	gasPrice, err := e.GasPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &FeeHistory{
+		OldestBlock: new(big.Int).SetUint64(newestBlock.Uint64() + 1 - blockCount),
+	}
+	for i := uint64(0); i < blockCount; i++ {
+		block, err := e.BlockByNumber(new(big.Int).SetUint64(ret.OldestBlock.Uint64() + i))
+		if err != nil {
+			return nil, err
+		}
+		gasUsedRatio := float64(0)
+		if block != nil && block.GasLimit() > 0 {
+			gasUsedRatio = float64(block.GasUsed()) / float64(block.GasLimit())
+		}
+		ret.GasUsedRatio = append(ret.GasUsedRatio, gasUsedRatio)
+		ret.BaseFee = append(ret.BaseFee, gasPrice)
+		if len(rewardPercentiles) > 0 {
+			rewards := make([]*big.Int, len(rewardPercentiles))
+			for j := range rewards {
+				rewards[j] = new(big.Int)
+			}
+			ret.Reward = append(ret.Reward, rewards)
+		}
+	}
+	// the base fee of the next block is included as well
+	ret.BaseFee = append(ret.BaseFee, gasPrice)
+	return ret, nil
+}
+
 func paramsWithOptionalBlockNumber(blockNumber *big.Int, params dict.Dict) dict.Dict {
 	ret := params
 	if params == nil {
@@ -170,6 +350,10 @@ func (e *EVMChain) BlockByNumber(blockNumber *big.Int) (*types.Block, error) {
 	}
 
 	if !ret.MustHas(evm.FieldResult) {
+		if idx := e.backend.EVMIndex(); idx != nil && blockNumber != nil {
+			// the block may have been pruned from the chain state
+			return idx.BlockByNumber(blockNumber.Uint64())
+		}
 		return nil, nil
 	}
 
@@ -205,22 +389,34 @@ func (e *EVMChain) getTransactionBy(funcName string, args dict.Dict) (tx *types.
 }
 
 func (e *EVMChain) TransactionByHash(hash common.Hash) (tx *types.Transaction, blockHash common.Hash, blockNumber, index uint64, err error) {
-	return e.getTransactionBy(evm.FuncGetTransactionByHash.Name, dict.Dict{
+	tx, blockHash, blockNumber, index, err = e.getTransactionBy(evm.FuncGetTransactionByHash.Name, dict.Dict{
 		evm.FieldTransactionHash: hash.Bytes(),
 	})
+	if idx := e.backend.EVMIndex(); idx != nil && err == nil && tx == nil {
+		return idx.TransactionByHash(hash)
+	}
+	return
 }
 
 func (e *EVMChain) TransactionByBlockHashAndIndex(hash common.Hash, index uint64) (tx *types.Transaction, blockHash common.Hash, blockNumber, indexRet uint64, err error) {
-	return e.getTransactionBy(evm.FuncGetTransactionByBlockHashAndIndex.Name, dict.Dict{
+	tx, blockHash, blockNumber, indexRet, err = e.getTransactionBy(evm.FuncGetTransactionByBlockHashAndIndex.Name, dict.Dict{
 		evm.FieldBlockHash:        hash.Bytes(),
 		evm.FieldTransactionIndex: codec.EncodeUint64(index),
 	})
+	if idx := e.backend.EVMIndex(); idx != nil && err == nil && tx == nil {
+		return idx.TransactionByBlockHashAndIndex(hash, index)
+	}
+	return
 }
 
 func (e *EVMChain) TransactionByBlockNumberAndIndex(blockNumber *big.Int, index uint64) (tx *types.Transaction, blockHash common.Hash, blockNumberRet, indexRet uint64, err error) {
-	return e.getTransactionBy(evm.FuncGetTransactionByBlockNumberAndIndex.Name, paramsWithOptionalBlockNumber(blockNumber, dict.Dict{
+	tx, blockHash, blockNumberRet, indexRet, err = e.getTransactionBy(evm.FuncGetTransactionByBlockNumberAndIndex.Name, paramsWithOptionalBlockNumber(blockNumber, dict.Dict{
 		evm.FieldTransactionIndex: codec.EncodeUint64(index),
 	}))
+	if idx := e.backend.EVMIndex(); idx != nil && err == nil && tx == nil && blockNumber != nil {
+		return idx.TransactionByBlockNumberAndIndex(blockNumber.Uint64(), index)
+	}
+	return
 }
 
 func (e *EVMChain) BlockByHash(hash common.Hash) (*types.Block, error) {
@@ -232,6 +428,9 @@ func (e *EVMChain) BlockByHash(hash common.Hash) (*types.Block, error) {
 	}
 
 	if !ret.MustHas(evm.FieldResult) {
+		if idx := e.backend.EVMIndex(); idx != nil {
+			return idx.BlockByHash(hash)
+		}
 		return nil, nil
 	}
 
@@ -251,6 +450,9 @@ func (e *EVMChain) TransactionReceipt(txHash common.Hash) (*types.Receipt, error
 	}
 
 	if !ret.MustHas(evm.FieldResult) {
+		if idx := e.backend.EVMIndex(); idx != nil {
+			return idx.TransactionReceipt(txHash)
+		}
 		return nil, nil
 	}
 
@@ -275,6 +477,88 @@ func (e *EVMChain) TransactionCount(address common.Address, blockNumberOrHash ..
 	return codec.DecodeUint64(ret.MustGet(evm.FieldResult), 0)
 }
 
+// PendingTransactionCount returns the nonce of the account after the processing of its transactions
+// waiting in the mempool
+func (e *EVMChain) PendingTransactionCount(address common.Address) (uint64, error) {
+	pending, _, err := e.TxPool(address)
+	if err != nil {
+		return 0, err
+	}
+	n, err := e.TransactionCount(address)
+	if err != nil {
+		return 0, err
+	}
+	return n + uint64(len(pending[address])), nil
+}
+
+// TxPool returns the Ethereum transactions waiting in the mempool, grouped by sender and nonce, optionally
+// only the ones of the given senders. The pending transactions are the ones that can be processed right away,
+// the queued ones have a gap between their nonce and the nonce of the account
+func (e *EVMChain) TxPool(senders ...common.Address) (pending, queued map[common.Address]map[uint64]*types.Transaction, err error) {
+	filter := make(map[common.Address]bool)
+	for _, sender := range senders {
+		filter[sender] = true
+	}
+	bySender := make(map[common.Address]map[uint64]*types.Transaction)
+	for _, req := range e.backend.ISCMempoolRequests() {
+		tx, ok := isc.EVMTransaction(req)
+		if !ok {
+			continue
+		}
+		sender, err := types.Sender(e.Signer(), tx)
+		if err != nil {
+			continue
+		}
+		if len(filter) > 0 && !filter[sender] {
+			continue
+		}
+		if bySender[sender] == nil {
+			bySender[sender] = make(map[uint64]*types.Transaction)
+		}
+		// with more transactions with the same nonce only one can succeed, show the one with the best price
+		if other, ok := bySender[sender][tx.Nonce()]; ok && other.GasPrice().Cmp(tx.GasPrice()) >= 0 {
+			continue
+		}
+		bySender[sender][tx.Nonce()] = tx
+	}
+
+	pending = make(map[common.Address]map[uint64]*types.Transaction)
+	queued = make(map[common.Address]map[uint64]*types.Transaction)
+	for sender, txs := range bySender {
+		next, err := e.TransactionCount(sender)
+		if err != nil {
+			return nil, nil, err
+		}
+		for ; txs[next] != nil; next++ {
+			if pending[sender] == nil {
+				pending[sender] = make(map[uint64]*types.Transaction)
+			}
+			pending[sender][next] = txs[next]
+		}
+		for n, tx := range txs {
+			// skip the pending ones, and the ones with a nonce lower than the account nonce, which will fail
+			if n < next {
+				continue
+			}
+			if queued[sender] == nil {
+				queued[sender] = make(map[uint64]*types.Transaction)
+			}
+			queued[sender][n] = tx
+		}
+	}
+	return pending, queued, nil
+}
+
+// MempoolTransactionByHash returns the Ethereum transaction waiting in the mempool with the given hash, if any
+func (e *EVMChain) MempoolTransactionByHash(hash common.Hash) *types.Transaction {
+	for _, req := range e.backend.ISCMempoolRequests() {
+		if tx, ok := isc.EVMTransaction(req); ok && tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
 func (e *EVMChain) CallContract(args ethereum.CallMsg, blockNumberOrHash rpc.BlockNumberOrHash) ([]byte, error) {
 	ret, err := e.backend.ISCCallView(evm.Contract.Name, evm.FuncCallContract.Name, paramsWithOptionalBlockNumberOrHash(blockNumberOrHash, dict.Dict{
 		evm.FieldCallMsg: evmtypes.EncodeCallMsg(args),
@@ -307,6 +591,11 @@ func (e *EVMChain) BlockTransactionCountByHash(blockHash common.Hash) (uint64, e
 	if err != nil {
 		return 0, err
 	}
+	if !ret.MustHas(evm.FieldResult) {
+		if idx := e.backend.EVMIndex(); idx != nil {
+			return indexedTransactionCount(idx.BlockByHash(blockHash))
+		}
+	}
 	return codec.DecodeUint64(ret.MustGet(evm.FieldResult), 0)
 }
 
@@ -315,10 +604,66 @@ func (e *EVMChain) BlockTransactionCountByNumber(blockNumber *big.Int) (uint64,
 	if err != nil {
 		return 0, err
 	}
+	if !ret.MustHas(evm.FieldResult) && blockNumber != nil {
+		if idx := e.backend.EVMIndex(); idx != nil {
+			return indexedTransactionCount(idx.BlockByNumber(blockNumber.Uint64()))
+		}
+	}
 	return codec.DecodeUint64(ret.MustGet(evm.FieldResult), 0)
 }
 
+func indexedTransactionCount(block *types.Block, err error) (uint64, error) {
+	if err != nil || block == nil {
+		return 0, err
+	}
+	return uint64(len(block.Transactions())), nil
+}
+
 func (e *EVMChain) Logs(q *ethereum.FilterQuery) ([]*types.Log, error) {
+	idx := e.backend.EVMIndex()
+	if idx == nil {
+		return e.stateLogs(q)
+	}
+	// the logs of the blocks pruned from the chain state are only in the index
+	if q.BlockHash != nil {
+		logs, err := idx.Logs(q, 0)
+		if err != nil || len(logs) > 0 {
+			return logs, err
+		}
+		return e.stateLogs(q)
+	}
+	latest, err := e.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	lastIndexed, ok, err := idx.LastBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return e.stateLogs(q)
+	}
+	logs, err := idx.Logs(q, lastIndexed)
+	if err != nil {
+		return nil, err
+	}
+	// the blocks not indexed yet are read from the chain state
+	stateQuery := *q
+	stateQuery.FromBlock = new(big.Int).SetUint64(lastIndexed + 1)
+	if q.FromBlock != nil && q.FromBlock.Cmp(stateQuery.FromBlock) > 0 {
+		stateQuery.FromBlock = q.FromBlock
+	}
+	if stateQuery.FromBlock.Cmp(latest) <= 0 && (q.ToBlock == nil || q.ToBlock.Cmp(stateQuery.FromBlock) >= 0 || q.ToBlock.Sign() < 0) {
+		stateLogs, err := e.stateLogs(&stateQuery)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, stateLogs...)
+	}
+	return logs, nil
+}
+
+func (e *EVMChain) stateLogs(q *ethereum.FilterQuery) ([]*types.Log, error) {
 	ret, err := e.backend.ISCCallView(evm.Contract.Name, evm.FuncGetLogs.Name, dict.Dict{
 		evm.FieldFilterQuery: evmtypes.EncodeFilterQuery(q),
 	})
@@ -328,6 +673,57 @@ func (e *EVMChain) Logs(q *ethereum.FilterQuery) ([]*types.Log, error) {
 	return evmtypes.DecodeLogs(ret.MustGet(evm.FieldResult))
 }
 
+// Proof returns the account and storage proofs of the address, anchored to
+// the commitment of the latest ISC state
+func (e *EVMChain) Proof(address common.Address, storageKeys []common.Hash) (*evmutil.AccountProof, error) {
+	gasFeePolicy, err := e.GasFeePolicy()
+	if err != nil {
+		return nil, err
+	}
+	layout := evmimpl.NewStateLayout(gasFeePolicy.GasFeeTokenID)
+	keys := [][]byte{
+		layout.NonceKey(address),
+		layout.BalanceKey(address),
+		layout.CodeKey(address),
+	}
+	for _, slot := range storageKeys {
+		keys = append(keys, layout.StorageKey(address, slot))
+	}
+	stateIndex, proofs, err := e.backend.ISCStateProofs(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &evmutil.AccountProof{
+		Address:      address,
+		StateIndex:   stateIndex,
+		Balance:      new(big.Int),
+		CodeHash:     crypto.Keccak256Hash(proofs[2].Value),
+		NonceProof:   proofs[0],
+		BalanceProof: proofs[1],
+		CodeProof:    proofs[2],
+	}
+	if proofs[0].Value != nil {
+		if ret.Nonce, err = layout.DecodeNonce(proofs[0].Value); err != nil {
+			return nil, err
+		}
+	}
+	if proofs[1].Value != nil {
+		if ret.Balance, err = layout.DecodeBalance(proofs[1].Value); err != nil {
+			return nil, err
+		}
+	}
+	for i, slot := range storageKeys {
+		p := proofs[3+i]
+		ret.StorageProof = append(ret.StorageProof, &evmutil.StorageProof{
+			Key:   slot,
+			Value: common.BytesToHash(p.Value),
+			Proof: p,
+		})
+	}
+	return ret, nil
+}
+
 func (e *EVMChain) BaseToken() *parameters.BaseToken {
 	return e.backend.BaseToken()
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"testing"
@@ -18,7 +19,6 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/iotaledger/wasp/packages/evm/evmtest"
 	"github.com/iotaledger/wasp/packages/evm/evmutil"
@@ -322,20 +322,30 @@ func (e *Env) TestRPCInvalidNonce(newAccountWithL2Funds FuncNewAccountWithL2Fund
 	from, fromAddress := newAccountWithL2Funds()
 	_, toAddress := newAccountWithL2Funds()
 	value := big.NewInt(0)
-	nonce := e.NonceAt(fromAddress) + 1
-	gasLimit := params.TxGas
-	tx, err := types.SignTx(
-		types.NewTransaction(nonce, toAddress, value, gasLimit, evm.GasPrice, nil),
-		e.Signer(),
-		from,
-	)
-	require.NoError(e.T, err)
+	gasLimit := uint64(100_000)
+	nonce := e.NonceAt(fromAddress)
+	newTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(
+			types.NewTransaction(nonce, toAddress, value, gasLimit, evm.GasPrice, nil),
+			e.Signer(),
+			from,
+		)
+		require.NoError(e.T, err)
+		return tx
+	}
+	e.mustSendTransactionAndWait(newTx(nonce))
 
-	_, err = e.SendTransactionAndWait(tx)
+	// a nonce ahead of the account nonce is accepted and waits in the mempool, a used one is not
+	_, err := e.SendTransactionAndWait(newTx(nonce))
 	require.Error(e.T, err)
-	require.Regexp(e.T, `invalid transaction nonce: got 1, want 0`, err.Error())
+	require.Regexp(e.T, fmt.Sprintf(`invalid transaction nonce: got %d, want at least %d`, nonce, nonce+1), err.Error())
 	_, ok := err.(*isc.VMError)
 	require.False(e.T, ok)
+
+	// a nonce too far ahead of the account nonce is not accepted
+	_, err = e.SendTransactionAndWait(newTx(nonce + 1 + 64))
+	require.Error(e.T, err)
+	require.Regexp(e.T, fmt.Sprintf(`invalid transaction nonce: got %d, want less than %d`, nonce+1+64, nonce+1+64), err.Error())
 }
 
 func (e *Env) TestRPCGasLimitTooLow(newAccountWithL2Funds FuncNewAccountWithL2Funds) {
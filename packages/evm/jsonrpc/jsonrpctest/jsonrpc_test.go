@@ -8,12 +8,14 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -22,8 +24,13 @@ import (
 	"github.com/iotaledger/wasp/packages/evm/evmutil"
 	"github.com/iotaledger/wasp/packages/evm/jsonrpc"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,12 +40,12 @@ type soloTestEnv struct {
 	soloChain *solo.Chain
 }
 
-func newSoloTestEnv(t *testing.T) *soloTestEnv {
+func newSoloTestEnv(t *testing.T, initOptions ...solo.InitChainOptions) *soloTestEnv {
 	evmtest.InitGoEthLogger(t)
 
 	s := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true, Debug: true, PrintStackTrace: true})
 	chainOwner, _ := s.NewKeyPairWithFunds()
-	chain, _, _ := s.NewChainExt(chainOwner, 0, "chain1")
+	chain, _, _ := s.NewChainExt(chainOwner, 0, "chain1", initOptions...)
 
 	accounts := jsonrpc.NewAccountManager(nil)
 	rpcsrv := jsonrpc.NewServer(chain.EVM(), accounts)
@@ -61,6 +68,17 @@ func newSoloTestEnv(t *testing.T) *soloTestEnv {
 	}
 }
 
+// activateLondon schedules the London hard fork, so that it is active from the next EVM block
+func (e *soloTestEnv) activateLondon() {
+	_, err := e.soloChain.PostRequestSync(
+		solo.NewCallParams(evm.Contract.Name, evm.FuncSetLondonBlock.Name,
+			evm.FieldBlockNumber, codec.EncodeUint64(e.BlockNumber()+2),
+		).WithGasBudget(100_000),
+		nil,
+	)
+	require.NoError(e.T, err)
+}
+
 func TestRPCGetBalance(t *testing.T) {
 	env := newSoloTestEnv(t)
 	_, emptyAddress := solo.NewEthereumAccount()
@@ -382,3 +400,328 @@ func TestRPCTxRejectedIfNotEnoughFunds(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "sender has not enough L2 funds to cover tx gas budget")
 }
+
+func TestRPCTxPool(t *testing.T) {
+	env := newSoloTestEnv(t)
+	from, fromAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	_, toAddress := solo.NewEthereumAccount()
+	newTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(
+			types.NewTransaction(nonce, toAddress, big.NewInt(0), 100_000, evm.GasPrice, nil),
+			env.Signer(),
+			from,
+		)
+		require.NoError(t, err)
+		return tx
+	}
+	txPoolStatus := func() map[string]hexutil.Uint {
+		var ret map[string]hexutil.Uint
+		require.NoError(t, env.RawClient.Call(&ret, "txpool_status"))
+		return ret
+	}
+	pendingNonce := func() uint64 {
+		n, err := env.Client.PendingNonceAt(context.Background(), fromAddress)
+		require.NoError(t, err)
+		return n
+	}
+
+	// the transactions with nonces 1 and 3 wait for the one with nonce 0
+	tx1, tx3 := newTx(1), newTx(3)
+	for _, tx := range []*types.Transaction{tx1, tx3} {
+		req, err := isc.NewEVMOffLedgerRequest(env.soloChain.ChainID, tx)
+		require.NoError(t, err)
+		env.solo.AddRequestsToChainMempoolWaitUntilInbufferEmpty(env.soloChain, []isc.Request{req})
+	}
+	require.Equal(t, map[string]hexutil.Uint{"pending": 0, "queued": 2}, txPoolStatus())
+	require.EqualValues(t, 0, pendingNonce())
+
+	var content map[string]map[string]map[string]*jsonrpc.RPCTransaction
+	require.NoError(t, env.RawClient.Call(&content, "txpool_content"))
+	require.Empty(t, content["pending"])
+	require.Len(t, content["queued"][fromAddress.Hex()], 2)
+	require.Equal(t, tx3.Hash(), content["queued"][fromAddress.Hex()]["3"].Hash)
+
+	var inspect map[string]map[string]map[string]string
+	require.NoError(t, env.RawClient.Call(&inspect, "txpool_inspect"))
+	require.Contains(t, inspect["queued"][fromAddress.Hex()]["1"], toAddress.Hex())
+
+	// the transactions waiting in the mempool can be queried by hash
+	tx, isPending, err := env.Client.TransactionByHash(context.Background(), tx1.Hash())
+	require.NoError(t, err)
+	require.True(t, isPending)
+	require.Equal(t, tx1.Hash(), tx.Hash())
+
+	// once the transaction with nonce 0 is processed, the one with nonce 1 follows
+	env.mustSendTransactionAndWait(newTx(0))
+	require.Eventually(t, func() bool {
+		return env.NonceAt(fromAddress) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, map[string]hexutil.Uint{"pending": 0, "queued": 1}, txPoolStatus())
+	require.EqualValues(t, 2, pendingNonce())
+}
+
+func TestRPCEVMIndex(t *testing.T) {
+	env := newSoloTestEnv(t, solo.InitChainOptions{
+		InitRequestParameters: dict.Dict{
+			root.ParamEVM(evm.FieldBlockKeepAmount): codec.EncodeInt32(2),
+		},
+		EVMIndex: true,
+	})
+	creator, creatorAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	contractABI, err := abi.JSON(strings.NewReader(evmtest.ERC20ContractABI))
+	require.NoError(t, err)
+	deployTx, deployReceipt, contractAddress := env.DeployEVMContract(creator, contractABI, evmtest.ERC20ContractBytecode, "TestCoin", "TEST")
+	require.Len(t, deployReceipt.Logs, 1)
+
+	// mint more blocks, so that the block of the deployment is pruned from the chain state
+	_, toAddress := solo.NewEthereumAccount()
+	for i := 0; i < 3; i++ {
+		tx, err := types.SignTx(
+			types.NewTransaction(env.NonceAt(creatorAddress), toAddress, big.NewInt(0), 100_000, evm.GasPrice, nil),
+			env.Signer(),
+			creator,
+		)
+		require.NoError(t, err)
+		env.mustSendTransactionAndWait(tx)
+	}
+	ret, err := env.soloChain.CallView(evm.Contract.Name, evm.FuncGetReceipt.Name, evm.FieldTransactionHash, deployTx.Hash().Bytes())
+	require.NoError(t, err)
+	require.False(t, ret.MustHas(evm.FieldResult))
+
+	// the node-side index still has it
+	receipt := env.MustTxReceipt(deployTx.Hash())
+	require.Equal(t, deployReceipt.BlockHash, receipt.BlockHash)
+	require.Equal(t, contractAddress, receipt.ContractAddress)
+	require.Len(t, receipt.Logs, 1)
+
+	require.Equal(t, deployTx.Hash(), env.TransactionByHash(deployTx.Hash()).Hash())
+
+	block := env.BlockByNumber(deployReceipt.BlockNumber)
+	require.Len(t, block.Transactions(), 1)
+	require.Equal(t, deployTx.Hash(), block.Transactions()[0].Hash())
+	require.EqualValues(t, deployReceipt.BlockNumber.Uint64(), env.BlockByHash(deployReceipt.BlockHash).NumberU64())
+
+	logs := env.getLogs(ethereum.FilterQuery{Addresses: []common.Address{contractAddress}})
+	require.Len(t, logs, 1)
+	require.Equal(t, deployTx.Hash(), logs[0].TxHash)
+	require.Equal(t, deployReceipt.BlockHash, logs[0].BlockHash)
+}
+
+func TestRPCTypedTransactions(t *testing.T) {
+	env := newSoloTestEnv(t)
+	from, fromAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	_, toAddress := solo.NewEthereumAccount()
+	chainID := big.NewInt(int64(env.ChainID))
+	accessList := types.AccessList{{Address: toAddress, StorageKeys: []common.Hash{{1}}}}
+
+	// EIP-1559 transactions are rejected until the London hard fork is activated
+	{
+		tx, err := types.SignNewTx(from, env.Signer(), &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     env.NonceAt(fromAddress),
+			To:        &toAddress,
+			Gas:       100_000,
+			GasFeeCap: big.NewInt(1000),
+			GasTipCap: big.NewInt(10),
+			Value:     big.NewInt(0),
+		})
+		require.NoError(t, err)
+		_, err = env.SendTransactionAndWait(tx)
+		require.ErrorContains(t, err, "London hard fork is not active")
+	}
+	env.activateLondon()
+
+	for _, txData := range []types.TxData{
+		&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      env.NonceAt(fromAddress),
+			To:         &toAddress,
+			Gas:        100_000,
+			GasPrice:   evm.GasPrice,
+			Value:      big.NewInt(0),
+			AccessList: accessList,
+		},
+		&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      env.NonceAt(fromAddress) + 1,
+			To:         &toAddress,
+			Gas:        100_000,
+			GasFeeCap:  big.NewInt(1000),
+			GasTipCap:  big.NewInt(10),
+			Value:      big.NewInt(0),
+			AccessList: accessList,
+		},
+	} {
+		tx, err := types.SignNewTx(from, env.Signer(), txData)
+		require.NoError(t, err)
+		receipt := env.mustSendTransactionAndWait(tx)
+		require.EqualValues(t, tx.Type(), receipt.Type)
+		require.EqualValues(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+		rpcTx := env.TransactionByBlockNumberAndIndex(receipt.BlockNumber, 0)
+		require.EqualValues(t, tx.Type(), rpcTx.Type)
+		require.EqualValues(t, accessList, *rpcTx.Accesses)
+		sentTx := env.TransactionByHash(tx.Hash())
+		require.EqualValues(t, tx.GasFeeCap(), sentTx.GasFeeCap())
+		require.EqualValues(t, tx.GasTipCap(), sentTx.GasTipCap())
+	}
+}
+
+func TestRPCSendDynamicFeeTransaction(t *testing.T) {
+	env := newSoloTestEnv(t)
+	ethKey, ethAddr := env.soloChain.NewEthereumAccountWithL2Funds()
+	env.accountManager.Add(ethKey)
+	_, to := solo.NewEthereumAccount()
+	env.activateLondon()
+
+	gas := hexutil.Uint64(100_000)
+	txHash := env.MustSendTransaction(&jsonrpc.SendTxArgs{
+		From:                 ethAddr,
+		To:                   &to,
+		Gas:                  &gas,
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(0)),
+	})
+	tx := env.TransactionByHash(txHash)
+	require.EqualValues(t, types.DynamicFeeTxType, tx.Type())
+}
+
+func TestRPCFeeHistory(t *testing.T) {
+	env := newSoloTestEnv(t)
+	creator, _ := env.soloChain.NewEthereumAccountWithL2Funds()
+	env.deployStorageContract(creator)
+
+	// with the default fee policy the price of 1 EVM gas is a fraction of a base token, rounded up
+	gasPrice, err := env.Client.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, gasPrice.Uint64())
+	tip, err := env.Client.SuggestGasTipCap(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 0, tip.Uint64())
+
+	latest := env.BlockNumber()
+	var feeHistory jsonrpc.RPCFeeHistory
+	err = env.RawClient.Call(&feeHistory, "eth_feeHistory", 10, "latest", []float64{25, 75})
+	require.NoError(t, err)
+	require.EqualValues(t, 0, feeHistory.OldestBlock.ToInt().Uint64())
+	require.Len(t, feeHistory.GasUsedRatio, int(latest+1))
+	require.Len(t, feeHistory.BaseFee, int(latest+2))
+	require.Len(t, feeHistory.Reward, int(latest+1))
+	require.Len(t, feeHistory.Reward[0], 2)
+	require.NotZero(t, feeHistory.GasUsedRatio[latest])
+
+	err = env.RawClient.Call(&feeHistory, "eth_feeHistory", 1, "latest", []float64{75, 25})
+	require.Error(t, err)
+}
+
+func TestRPCGasPriceFromFeePolicy(t *testing.T) {
+	env := newSoloTestEnv(t)
+	from, fromAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	_, toAddress := solo.NewEthereumAccount()
+	env.activateLondon()
+
+	// 1 base token pays for 1 unit of gas
+	feePolicy := env.soloChain.GetGasFeePolicy()
+	feePolicy.GasPerToken = 1
+	_, err := env.soloChain.PostRequestSync(
+		solo.NewCallParams(governance.Contract.Name, governance.FuncSetFeePolicy.Name,
+			governance.ParamFeePolicyBytes, feePolicy.Bytes(),
+		).WithGasBudget(100_000),
+		nil,
+	)
+	require.NoError(t, err)
+
+	gasPrice, err := env.Client.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, gasPrice.Uint64())
+
+	newTx := func(gasFeeCap *big.Int) *types.Transaction {
+		tx, err := types.SignNewTx(from, env.Signer(), &types.DynamicFeeTx{
+			ChainID:   big.NewInt(int64(env.ChainID)),
+			Nonce:     env.NonceAt(fromAddress),
+			To:        &toAddress,
+			Gas:       50_000,
+			GasFeeCap: gasFeeCap,
+			GasTipCap: big.NewInt(0),
+			Value:     big.NewInt(0),
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	// the max fee must cover the gas price derived from the fee policy
+	_, err = env.SendTransactionAndWait(newTx(big.NewInt(0)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max fee per gas too low")
+
+	receipt := env.mustSendTransactionAndWait(newTx(gasPrice))
+	require.EqualValues(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestRPCGasPriceDefaultPolicy(t *testing.T) {
+	env := newSoloTestEnv(t)
+	from, fromAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	_, toAddress := solo.NewEthereumAccount()
+
+	gasPrice, err := env.Client.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, gasPrice.Sign())
+
+	tx, err := types.SignTx(
+		types.NewTransaction(env.NonceAt(fromAddress), toAddress, big.NewInt(0), 100_000, gasPrice, nil),
+		env.Signer(),
+		from,
+	)
+	require.NoError(t, err)
+
+	agentID := isc.NewEthereumAddressAgentID(fromAddress)
+	balanceBefore := env.soloChain.L2BaseTokens(agentID)
+	receipt := env.mustSendTransactionAndWait(tx)
+	require.EqualValues(t, types.ReceiptStatusSuccessful, receipt.Status)
+	fee := balanceBefore - env.soloChain.L2BaseTokens(agentID)
+
+	// the fee charged by ISC is covered by gasPrice * gas
+	require.Positive(t, fee)
+	require.LessOrEqual(t, fee, gasPrice.Uint64()*receipt.GasUsed)
+}
+
+func TestRPCGetProof(t *testing.T) {
+	env := newSoloTestEnv(t)
+	creator, creatorAddress := env.soloChain.NewEthereumAccountWithL2Funds()
+	_, contractAddress, _ := env.deployStorageContract(creator)
+
+	getProof := func(addr common.Address, slots ...string) *evmutil.AccountProof {
+		var ret jsonrpc.RPCAccountProof
+		require.NoError(t, env.RawClient.Call(&ret, "eth_getProof", addr, slots, "latest"))
+		proof, err := ret.ToAccountProof()
+		require.NoError(t, err)
+		return proof
+	}
+	aliasOutput := env.soloChain.GetAnchorOutput().GetAliasOutput()
+	layout := evmimpl.NewStateLayout(nil)
+
+	// EOA with funds and nonce
+	proof := getProof(creatorAddress)
+	require.NoError(t, evmutil.VerifyAccountProof(aliasOutput, layout, proof))
+	require.EqualValues(t, 1, proof.Nonce)
+	require.EqualValues(t, env.soloChain.L2BaseTokens(isc.NewEthereumAddressAgentID(creatorAddress)), proof.Balance.Uint64())
+	require.Equal(t, crypto.Keccak256Hash(nil), proof.CodeHash)
+
+	// contract with code and storage; slot 0 holds uint32 n = 42
+	proof = getProof(contractAddress, "0x0", "0x1")
+	require.NoError(t, evmutil.VerifyAccountProof(aliasOutput, layout, proof))
+	require.Equal(t, crypto.Keccak256Hash(env.Code(contractAddress)), proof.CodeHash)
+	require.Len(t, proof.StorageProof, 2)
+	require.EqualValues(t, 42, proof.StorageProof[0].Value.Big().Uint64())
+	require.Equal(t, common.Hash{}, proof.StorageProof[1].Value)
+
+	// tampered values are rejected
+	proof.StorageProof[0].Value = common.BigToHash(big.NewInt(43))
+	require.Error(t, evmutil.VerifyAccountProof(aliasOutput, layout, proof))
+	proof = getProof(contractAddress)
+	proof.StorageProof = append(proof.StorageProof, &evmutil.StorageProof{Key: common.Hash{}, Proof: proof.CodeProof})
+	require.Error(t, evmutil.VerifyAccountProof(aliasOutput, layout, proof))
+
+	// only the latest state can be proven
+	var ret jsonrpc.RPCAccountProof
+	require.Error(t, env.RawClient.Call(&ret, "eth_getProof", contractAddress, []string{}, "0x1"))
+}
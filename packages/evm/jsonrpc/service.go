@@ -6,9 +6,11 @@
 package jsonrpc
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,7 +18,6 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/vm/core/errors"
@@ -52,6 +53,13 @@ func (e *EthService) resolveError(err error) error {
 }
 
 func (e *EthService) GetTransactionCount(address common.Address, blockNumberOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if blockNumber, ok := blockNumberOrHash.Number(); ok && blockNumber == rpc.PendingBlockNumber {
+		n, err := e.evmChain.PendingTransactionCount(address)
+		if err != nil {
+			return 0, e.resolveError(err)
+		}
+		return hexutil.Uint64(n), nil
+	}
 	n, err := e.evmChain.TransactionCount(address, blockNumberOrHash)
 	if err != nil {
 		return 0, e.resolveError(err)
@@ -95,6 +103,10 @@ func (e *EthService) GetTransactionByHash(hash common.Hash) (*RPCTransaction, er
 		return nil, e.resolveError(err)
 	}
 	if tx == nil {
+		// the transaction may be waiting in the mempool
+		if tx = e.evmChain.MempoolTransactionByHash(hash); tx != nil {
+			return newRPCTransaction(tx, common.Hash{}, 0, 0), nil
+		}
 		return nil, nil
 	}
 	return newRPCTransaction(tx, blockHash, blockNumber, index), err
@@ -167,7 +179,7 @@ func (e *EthService) GetTransactionReceipt(txHash common.Hash) (map[string]inter
 
 func (e *EthService) SendRawTransaction(txBytes hexutil.Bytes) (common.Hash, error) {
 	tx := new(types.Transaction)
-	if err := rlp.DecodeBytes(txBytes, tx); err != nil {
+	if err := tx.UnmarshalBinary(txBytes); err != nil {
 		return common.Hash{}, err
 	}
 	if err := e.evmChain.SendTransaction(tx); err != nil {
@@ -191,6 +203,40 @@ func (e *EthService) GetStorageAt(address common.Address, key common.Hash, block
 	return ret, e.resolveError(err)
 }
 
+// GetProof implements the eth_getProof method according to https://eips.ethereum.org/EIPS/eip-1186.
+// The proofs are anchored to the ISC state commitment, so only the latest state is supported.
+func (e *EthService) GetProof(address common.Address, storageKeys []string, blockNumberOrHash rpc.BlockNumberOrHash) (*RPCAccountProof, error) {
+	if blockNumber, ok := blockNumberOrHash.Number(); !ok || blockNumber != rpc.LatestBlockNumber {
+		return nil, xerrors.New("eth_getProof is only supported for the latest block")
+	}
+	keys := make([]common.Hash, len(storageKeys))
+	for i, key := range storageKeys {
+		var err error
+		if keys[i], err = decodeStorageKey(key); err != nil {
+			return nil, err
+		}
+	}
+	proof, err := e.evmChain.Proof(address, keys)
+	if err != nil {
+		return nil, e.resolveError(err)
+	}
+	return newRPCAccountProof(proof), nil
+}
+
+// decodeStorageKey accepts storage keys shorter than 32 bytes and with an
+// odd number of hex digits, such as "0x0"
+func decodeStorageKey(key string) (common.Hash, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(key, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) > common.HashLength {
+		return common.Hash{}, xerrors.Errorf("invalid storage key: %s", key)
+	}
+	return common.BytesToHash(b), nil
+}
+
 func (e *EthService) GetBlockTransactionCountByHash(blockHash common.Hash) (hexutil.Uint, error) {
 	ret, err := e.evmChain.BlockTransactionCountByHash(blockHash)
 	return hexutil.Uint(ret), e.resolveError(err)
@@ -221,8 +267,24 @@ func (e *EthService) Accounts() []common.Address {
 	return e.accounts.Addresses()
 }
 
-func (e *EthService) GasPrice() *hexutil.Big {
-	return (*hexutil.Big)(big.NewInt(0))
+func (e *EthService) GasPrice() (*hexutil.Big, error) {
+	ret, err := e.evmChain.GasPrice()
+	if err != nil {
+		return nil, e.resolveError(err)
+	}
+	return (*hexutil.Big)(ret), nil
+}
+
+func (e *EthService) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(0)) // ISC does not have priority fees
+}
+
+func (e *EthService) FeeHistory(blockCount rpc.DecimalOrHex, newestBlock rpc.BlockNumber, rewardPercentiles []float64) (*RPCFeeHistory, error) {
+	ret, err := e.evmChain.FeeHistory(uint64(blockCount), parseBlockNumber(newestBlock), rewardPercentiles)
+	if err != nil {
+		return nil, e.resolveError(err)
+	}
+	return newRPCFeeHistory(ret), nil
 }
 
 func (e *EthService) Mining() bool {
@@ -355,29 +417,76 @@ func (s *Web3Service) Sha3(input hexutil.Bytes) hexutil.Bytes {
 	return crypto.Keccak256(input)
 }
 
-type TxPoolService struct{}
+// TxPoolService shows the Ethereum transactions waiting in the mempool of the chain
+type TxPoolService struct {
+	evmChain *EVMChain
+}
 
-func NewTxPoolService() *TxPoolService {
-	return &TxPoolService{}
+func NewTxPoolService(evmChain *EVMChain) *TxPoolService {
+	return &TxPoolService{evmChain}
 }
 
-func (s *TxPoolService) Content() map[string]map[string]map[string]*RPCTransaction {
-	return map[string]map[string]map[string]*RPCTransaction{
-		"pending": make(map[string]map[string]*RPCTransaction),
-		"queued":  make(map[string]map[string]*RPCTransaction),
+func (s *TxPoolService) Content() (map[string]map[string]map[string]*RPCTransaction, error) {
+	pending, queued, err := s.evmChain.TxPool()
+	if err != nil {
+		return nil, err
+	}
+	content := func(txs map[common.Address]map[uint64]*types.Transaction) map[string]map[string]*RPCTransaction {
+		ret := make(map[string]map[string]*RPCTransaction)
+		for sender, byNonce := range txs {
+			ret[sender.Hex()] = make(map[string]*RPCTransaction)
+			for nonce, tx := range byNonce {
+				ret[sender.Hex()][strconv.FormatUint(nonce, 10)] = newRPCTransaction(tx, common.Hash{}, 0, 0)
+			}
+		}
+		return ret
 	}
+	return map[string]map[string]map[string]*RPCTransaction{
+		"pending": content(pending),
+		"queued":  content(queued),
+	}, nil
 }
 
-func (s *TxPoolService) Inspect() map[string]map[string]map[string]string {
-	return map[string]map[string]map[string]string{
-		"pending": make(map[string]map[string]string),
-		"queued":  make(map[string]map[string]string),
+func (s *TxPoolService) Inspect() (map[string]map[string]map[string]string, error) {
+	pending, queued, err := s.evmChain.TxPool()
+	if err != nil {
+		return nil, err
+	}
+	inspect := func(txs map[common.Address]map[uint64]*types.Transaction) map[string]map[string]string {
+		ret := make(map[string]map[string]string)
+		for sender, byNonce := range txs {
+			ret[sender.Hex()] = make(map[string]string)
+			for nonce, tx := range byNonce {
+				to := "contract creation"
+				if tx.To() != nil {
+					to = tx.To().Hex()
+				}
+				ret[sender.Hex()][strconv.FormatUint(nonce, 10)] = fmt.Sprintf("%s: %v wei + %v gas × %v wei",
+					to, tx.Value(), tx.Gas(), tx.GasPrice())
+			}
+		}
+		return ret
 	}
+	return map[string]map[string]map[string]string{
+		"pending": inspect(pending),
+		"queued":  inspect(queued),
+	}, nil
 }
 
-func (s *TxPoolService) Status() map[string]hexutil.Uint {
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(0),
-		"queued":  hexutil.Uint(0),
+func (s *TxPoolService) Status() (map[string]hexutil.Uint, error) {
+	pending, queued, err := s.evmChain.TxPool()
+	if err != nil {
+		return nil, err
 	}
+	count := func(txs map[common.Address]map[uint64]*types.Transaction) hexutil.Uint {
+		n := 0
+		for _, byNonce := range txs {
+			n += len(byNonce)
+		}
+		return hexutil.Uint(n)
+	}
+	return map[string]hexutil.Uint{
+		"pending": count(pending),
+		"queued":  count(queued),
+	}, nil
 }
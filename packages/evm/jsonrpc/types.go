@@ -16,25 +16,29 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/iotaledger/wasp/packages/evm/evmutil"
-	"github.com/iotaledger/wasp/packages/vm/core/evm"
 )
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        *common.Hash    `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
+	BlockHash        *common.Hash      `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex *hexutil.Uint64   `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	V                *hexutil.Big      `json:"v"`
+	R                *hexutil.Big      `json:"r"`
+	S                *hexutil.Big      `json:"s"`
+	Type             hexutil.Uint64    `json:"type"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
+	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	GasFeeCap        *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap        *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -120,7 +124,7 @@ func newRPCTransactionFromBlockIndex(b *types.Block, index uint64) *RPCTransacti
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber, index uint64) *RPCTransaction {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = evmutil.Signer(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
@@ -137,6 +141,16 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		V:        (*hexutil.Big)(v),
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
+		Type:     hexutil.Uint64(tx.Type()),
+	}
+	if tx.Type() != types.LegacyTxType {
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
 	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = &blockHash
@@ -169,6 +183,7 @@ func RPCMarshalReceipt(r *types.Receipt, tx *types.Transaction) map[string]inter
 		"logs":              RPCMarshalLogs(r),
 		"logsBloom":         r.Bloom,
 		"status":            hexutil.Uint64(r.Status),
+		"type":              hexutil.Uint(r.Type),
 	}
 }
 
@@ -195,12 +210,15 @@ func RPCMarshalLog(r *types.Receipt, logIndex uint) map[string]interface{} {
 }
 
 type RPCCallArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Data     *hexutil.Bytes  `json:"data"`
+	From                 common.Address    `json:"from"`
+	To                   *common.Address   `json:"to"`
+	Gas                  *hexutil.Uint64   `json:"gas"`
+	GasPrice             *hexutil.Big      `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big      `json:"value"`
+	Data                 *hexutil.Bytes    `json:"data"`
+	AccessList           *types.AccessList `json:"accessList"`
 }
 
 func (c *RPCCallArgs) parse() (ret ethereum.CallMsg) {
@@ -210,31 +228,68 @@ func (c *RPCCallArgs) parse() (ret ethereum.CallMsg) {
 		ret.Gas = uint64(*c.Gas)
 	}
 	ret.GasPrice = (*big.Int)(c.GasPrice)
+	ret.GasFeeCap = (*big.Int)(c.MaxFeePerGas)
+	ret.GasTipCap = (*big.Int)(c.MaxPriorityFeePerGas)
 	ret.Value = (*big.Int)(c.Value)
 	if c.Data != nil {
 		ret.Data = *c.Data
 	}
+	if c.AccessList != nil {
+		ret.AccessList = *c.AccessList
+	}
 	return
 }
 
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 type SendTxArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                *hexutil.Uint64 `json:"nonce"`
 	// We accept "data" and "input" for backwards-compatibility reasons. "input" is the
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// Introduced by AccessListTxType transaction.
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
 func (args *SendTxArgs) setDefaults(e *EthService) error {
-	if args.GasPrice == nil {
-		args.GasPrice = (*hexutil.Big)(evm.GasPrice)
+	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
+		return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	if args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil {
+		// EIP-1559 transaction: ISC has no priority fees, and the base fee
+		// is the gas price derived from the fee policy
+		if args.MaxPriorityFeePerGas == nil {
+			args.MaxPriorityFeePerGas = new(hexutil.Big)
+		}
+		if args.MaxFeePerGas == nil {
+			gasPrice, err := e.evmChain.GasPrice()
+			if err != nil {
+				return err
+			}
+			args.MaxFeePerGas = (*hexutil.Big)(new(big.Int).Add(gasPrice, (*big.Int)(args.MaxPriorityFeePerGas)))
+		}
+		if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+			return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+		}
+	} else if args.GasPrice == nil {
+		gasPrice, err := e.evmChain.GasPrice()
+		if err != nil {
+			return err
+		}
+		args.GasPrice = (*hexutil.Big)(gasPrice)
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(big.NewInt(int64(e.evmChain.chainID)))
 	}
 	if args.Value == nil {
 		args.Value = new(hexutil.Big)
@@ -274,11 +329,16 @@ func (args *SendTxArgs) setDefaults(e *EthService) error {
 			data = *input
 		}
 		callArgs := ethereum.CallMsg{
-			From:     args.From, // From shouldn't be nil
-			To:       args.To,
-			GasPrice: (*big.Int)(args.GasPrice),
-			Value:    (*big.Int)(args.Value),
-			Data:     data,
+			From:      args.From, // From shouldn't be nil
+			To:        args.To,
+			GasPrice:  (*big.Int)(args.GasPrice),
+			GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+			GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+			Value:     (*big.Int)(args.Value),
+			Data:      data,
+		}
+		if args.AccessList != nil {
+			callArgs.AccessList = *args.AccessList
 		}
 		estimated, err := e.evmChain.EstimateGas(callArgs)
 		if err != nil {
@@ -289,6 +349,8 @@ func (args *SendTxArgs) setDefaults(e *EthService) error {
 	return nil
 }
 
+// toTransaction converts the arguments to a transaction.
+// This assumes that setDefaults has been called.
 func (args *SendTxArgs) toTransaction() *types.Transaction {
 	var input []byte
 	if args.Input != nil {
@@ -296,10 +358,156 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Data != nil {
 		input = *args.Data
 	}
-	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	var data types.TxData
+	switch {
+	case args.MaxFeePerGas != nil:
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		data = &types.DynamicFeeTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: al,
+		}
+	case args.AccessList != nil:
+		data = &types.AccessListTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasPrice:   (*big.Int)(args.GasPrice),
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: *args.AccessList,
+		}
+	default:
+		data = &types.LegacyTx{
+			To:       args.To,
+			Nonce:    uint64(*args.Nonce),
+			Gas:      uint64(*args.Gas),
+			GasPrice: (*big.Int)(args.GasPrice),
+			Value:    (*big.Int)(args.Value),
+			Data:     input,
+		}
+	}
+	return types.NewTx(data)
+}
+
+// RPCFeeHistory is the RPC representation of the eth_feeHistory result
+type RPCFeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+func newRPCFeeHistory(h *FeeHistory) *RPCFeeHistory {
+	ret := &RPCFeeHistory{
+		OldestBlock:  (*hexutil.Big)(h.OldestBlock),
+		GasUsedRatio: h.GasUsedRatio,
+	}
+	for _, rewards := range h.Reward {
+		r := make([]*hexutil.Big, len(rewards))
+		for i, reward := range rewards {
+			r[i] = (*hexutil.Big)(reward)
+		}
+		ret.Reward = append(ret.Reward, r)
+	}
+	for _, baseFee := range h.BaseFee {
+		ret.BaseFee = append(ret.BaseFee, (*hexutil.Big)(baseFee))
+	}
+	return ret
+}
+
+// RPCAccountProof is the result of eth_getProof. Each element of AccountProof
+// (nonce, balance and code) and of the storage proofs is an ISC state proof,
+// anchored to the state with index StateIndex.
+type RPCAccountProof struct {
+	Address      common.Address    `json:"address"`
+	AccountProof []hexutil.Bytes   `json:"accountProof"`
+	Balance      *hexutil.Big      `json:"balance"`
+	CodeHash     common.Hash       `json:"codeHash"`
+	Nonce        hexutil.Uint64    `json:"nonce"`
+	StorageHash  common.Hash       `json:"storageHash"`
+	StorageProof []RPCStorageProof `json:"storageProof"`
+	StateIndex   hexutil.Uint64    `json:"stateIndex"`
+}
+
+type RPCStorageProof struct {
+	Key   common.Hash     `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+func newRPCAccountProof(p *evmutil.AccountProof) *RPCAccountProof {
+	ret := &RPCAccountProof{
+		Address: p.Address,
+		AccountProof: []hexutil.Bytes{
+			p.NonceProof.Bytes(),
+			p.BalanceProof.Bytes(),
+			p.CodeProof.Bytes(),
+		},
+		Balance:      (*hexutil.Big)(p.Balance),
+		CodeHash:     p.CodeHash,
+		Nonce:        hexutil.Uint64(p.Nonce),
+		StorageProof: make([]RPCStorageProof, len(p.StorageProof)),
+		StateIndex:   hexutil.Uint64(p.StateIndex),
+	}
+	for i, sp := range p.StorageProof {
+		ret.StorageProof[i] = RPCStorageProof{
+			Key:   sp.Key,
+			Value: (*hexutil.Big)(sp.Value.Big()),
+			Proof: []hexutil.Bytes{sp.Proof.Bytes()},
+		}
+	}
+	return ret
+}
+
+// ToAccountProof decodes the result of eth_getProof, so that it can be checked
+// with evmutil.VerifyAccountProof
+func (p *RPCAccountProof) ToAccountProof() (*evmutil.AccountProof, error) {
+	if len(p.AccountProof) != 3 {
+		return nil, fmt.Errorf("invalid account proof: expected 3 elements, got %d", len(p.AccountProof))
+	}
+	proofs := make([]*evmutil.StateProof, len(p.AccountProof))
+	for i, b := range p.AccountProof {
+		var err error
+		if proofs[i], err = evmutil.StateProofFromBytes(b); err != nil {
+			return nil, err
+		}
+	}
+	ret := &evmutil.AccountProof{
+		Address:      p.Address,
+		StateIndex:   uint32(p.StateIndex),
+		Balance:      (*big.Int)(p.Balance),
+		CodeHash:     p.CodeHash,
+		Nonce:        uint64(p.Nonce),
+		NonceProof:   proofs[0],
+		BalanceProof: proofs[1],
+		CodeProof:    proofs[2],
+	}
+	for _, sp := range p.StorageProof {
+		if len(sp.Proof) != 1 {
+			return nil, fmt.Errorf("invalid storage proof: expected 1 element, got %d", len(sp.Proof))
+		}
+		proof, err := evmutil.StateProofFromBytes(sp.Proof[0])
+		if err != nil {
+			return nil, err
+		}
+		ret.StorageProof = append(ret.StorageProof, &evmutil.StorageProof{
+			Key:   sp.Key,
+			Value: common.BigToHash((*big.Int)(sp.Value)),
+			Proof: proof,
+		})
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	return ret, nil
 }
 
 type RPCFilterQuery ethereum.FilterQuery
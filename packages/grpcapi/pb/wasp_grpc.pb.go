@@ -0,0 +1,438 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: wasp.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// WaspClient is the client API for Wasp service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WaspClient interface {
+	// GetChainInfo returns the governance information of the chain
+	GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*ChainInfo, error)
+	// CallView calls a view function of a contract
+	CallView(ctx context.Context, in *CallViewRequest, opts ...grpc.CallOption) (*CallViewResponse, error)
+	// StateGet returns the raw value of a key of the chain state
+	StateGet(ctx context.Context, in *StateGetRequest, opts ...grpc.CallOption) (*StateGetResponse, error)
+	// SubmitRequest validates an off-ledger request and sends it to the mempool
+	SubmitRequest(ctx context.Context, in *SubmitRequestRequest, opts ...grpc.CallOption) (*SubmitRequestResponse, error)
+	// GetRequestReceipt returns the receipt of a processed request
+	GetRequestReceipt(ctx context.Context, in *GetRequestReceiptRequest, opts ...grpc.CallOption) (*GetRequestReceiptResponse, error)
+	// WaitRequestProcessed waits until the request is processed, or the deadline
+	// of the call is exceeded
+	WaitRequestProcessed(ctx context.Context, in *GetRequestReceiptRequest, opts ...grpc.CallOption) (*GetRequestReceiptResponse, error)
+	// SubscribeBlocks streams the blocks of the chain, as they are produced. The
+	// stream fails with RESOURCE_EXHAUSTED, if the subscriber does not keep up;
+	// the blocks missed must then be queried after subscribing again
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (Wasp_SubscribeBlocksClient, error)
+	// SubscribeEvents streams the events emitted by the contracts of the chain,
+	// once their block is produced. The stream fails with RESOURCE_EXHAUSTED, if
+	// the subscriber does not keep up
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Wasp_SubscribeEventsClient, error)
+}
+
+type waspClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWaspClient(cc grpc.ClientConnInterface) WaspClient {
+	return &waspClient{cc}
+}
+
+func (c *waspClient) GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*ChainInfo, error) {
+	out := new(ChainInfo)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/GetChainInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) CallView(ctx context.Context, in *CallViewRequest, opts ...grpc.CallOption) (*CallViewResponse, error) {
+	out := new(CallViewResponse)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/CallView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) StateGet(ctx context.Context, in *StateGetRequest, opts ...grpc.CallOption) (*StateGetResponse, error) {
+	out := new(StateGetResponse)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/StateGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) SubmitRequest(ctx context.Context, in *SubmitRequestRequest, opts ...grpc.CallOption) (*SubmitRequestResponse, error) {
+	out := new(SubmitRequestResponse)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/SubmitRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) GetRequestReceipt(ctx context.Context, in *GetRequestReceiptRequest, opts ...grpc.CallOption) (*GetRequestReceiptResponse, error) {
+	out := new(GetRequestReceiptResponse)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/GetRequestReceipt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) WaitRequestProcessed(ctx context.Context, in *GetRequestReceiptRequest, opts ...grpc.CallOption) (*GetRequestReceiptResponse, error) {
+	out := new(GetRequestReceiptResponse)
+	err := c.cc.Invoke(ctx, "/wasp.Wasp/WaitRequestProcessed", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waspClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (Wasp_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Wasp_ServiceDesc.Streams[0], "/wasp.Wasp/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &waspSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Wasp_SubscribeBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type waspSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *waspSubscribeBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *waspClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Wasp_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Wasp_ServiceDesc.Streams[1], "/wasp.Wasp/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &waspSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Wasp_SubscribeEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type waspSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *waspSubscribeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WaspServer is the server API for Wasp service.
+// All implementations must embed UnimplementedWaspServer
+// for forward compatibility
+type WaspServer interface {
+	// GetChainInfo returns the governance information of the chain
+	GetChainInfo(context.Context, *GetChainInfoRequest) (*ChainInfo, error)
+	// CallView calls a view function of a contract
+	CallView(context.Context, *CallViewRequest) (*CallViewResponse, error)
+	// StateGet returns the raw value of a key of the chain state
+	StateGet(context.Context, *StateGetRequest) (*StateGetResponse, error)
+	// SubmitRequest validates an off-ledger request and sends it to the mempool
+	SubmitRequest(context.Context, *SubmitRequestRequest) (*SubmitRequestResponse, error)
+	// GetRequestReceipt returns the receipt of a processed request
+	GetRequestReceipt(context.Context, *GetRequestReceiptRequest) (*GetRequestReceiptResponse, error)
+	// WaitRequestProcessed waits until the request is processed, or the deadline
+	// of the call is exceeded
+	WaitRequestProcessed(context.Context, *GetRequestReceiptRequest) (*GetRequestReceiptResponse, error)
+	// SubscribeBlocks streams the blocks of the chain, as they are produced. The
+	// stream fails with RESOURCE_EXHAUSTED, if the subscriber does not keep up;
+	// the blocks missed must then be queried after subscribing again
+	SubscribeBlocks(*SubscribeBlocksRequest, Wasp_SubscribeBlocksServer) error
+	// SubscribeEvents streams the events emitted by the contracts of the chain,
+	// once their block is produced. The stream fails with RESOURCE_EXHAUSTED, if
+	// the subscriber does not keep up
+	SubscribeEvents(*SubscribeEventsRequest, Wasp_SubscribeEventsServer) error
+	mustEmbedUnimplementedWaspServer()
+}
+
+// UnimplementedWaspServer must be embedded to have forward compatible implementations.
+type UnimplementedWaspServer struct {
+}
+
+func (UnimplementedWaspServer) GetChainInfo(context.Context, *GetChainInfoRequest) (*ChainInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChainInfo not implemented")
+}
+func (UnimplementedWaspServer) CallView(context.Context, *CallViewRequest) (*CallViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CallView not implemented")
+}
+func (UnimplementedWaspServer) StateGet(context.Context, *StateGetRequest) (*StateGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateGet not implemented")
+}
+func (UnimplementedWaspServer) SubmitRequest(context.Context, *SubmitRequestRequest) (*SubmitRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitRequest not implemented")
+}
+func (UnimplementedWaspServer) GetRequestReceipt(context.Context, *GetRequestReceiptRequest) (*GetRequestReceiptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRequestReceipt not implemented")
+}
+func (UnimplementedWaspServer) WaitRequestProcessed(context.Context, *GetRequestReceiptRequest) (*GetRequestReceiptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitRequestProcessed not implemented")
+}
+func (UnimplementedWaspServer) SubscribeBlocks(*SubscribeBlocksRequest, Wasp_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (UnimplementedWaspServer) SubscribeEvents(*SubscribeEventsRequest, Wasp_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (UnimplementedWaspServer) mustEmbedUnimplementedWaspServer() {}
+
+// UnsafeWaspServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WaspServer will
+// result in compilation errors.
+type UnsafeWaspServer interface {
+	mustEmbedUnimplementedWaspServer()
+}
+
+func RegisterWaspServer(s grpc.ServiceRegistrar, srv WaspServer) {
+	s.RegisterService(&Wasp_ServiceDesc, srv)
+}
+
+func _Wasp_GetChainInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).GetChainInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/GetChainInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).GetChainInfo(ctx, req.(*GetChainInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_CallView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).CallView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/CallView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).CallView(ctx, req.(*CallViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_StateGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).StateGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/StateGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).StateGet(ctx, req.(*StateGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_SubmitRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).SubmitRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/SubmitRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).SubmitRequest(ctx, req.(*SubmitRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_GetRequestReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequestReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).GetRequestReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/GetRequestReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).GetRequestReceipt(ctx, req.(*GetRequestReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_WaitRequestProcessed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequestReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaspServer).WaitRequestProcessed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wasp.Wasp/WaitRequestProcessed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaspServer).WaitRequestProcessed(ctx, req.(*GetRequestReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasp_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WaspServer).SubscribeBlocks(m, &waspSubscribeBlocksServer{stream})
+}
+
+type Wasp_SubscribeBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type waspSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *waspSubscribeBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Wasp_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WaspServer).SubscribeEvents(m, &waspSubscribeEventsServer{stream})
+}
+
+type Wasp_SubscribeEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type waspSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *waspSubscribeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Wasp_ServiceDesc is the grpc.ServiceDesc for Wasp service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Wasp_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wasp.Wasp",
+	HandlerType: (*WaspServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetChainInfo",
+			Handler:    _Wasp_GetChainInfo_Handler,
+		},
+		{
+			MethodName: "CallView",
+			Handler:    _Wasp_CallView_Handler,
+		},
+		{
+			MethodName: "StateGet",
+			Handler:    _Wasp_StateGet_Handler,
+		},
+		{
+			MethodName: "SubmitRequest",
+			Handler:    _Wasp_SubmitRequest_Handler,
+		},
+		{
+			MethodName: "GetRequestReceipt",
+			Handler:    _Wasp_GetRequestReceipt_Handler,
+		},
+		{
+			MethodName: "WaitRequestProcessed",
+			Handler:    _Wasp_WaitRequestProcessed_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _Wasp_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Wasp_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wasp.proto",
+}
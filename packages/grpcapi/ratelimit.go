@@ -0,0 +1,92 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/grpcapi/pb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/webapi/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key with the API key of the client
+const APIKeyMetadata = "x-api-key"
+
+// the rate limit groups of the web API, by gRPC method
+var methodGroups = map[string]string{
+	"/wasp.Wasp/SubmitRequest": ratelimit.GroupRequest,
+	"/wasp.Wasp/CallView":      ratelimit.GroupCallView,
+	"/wasp.Wasp/StateGet":      ratelimit.GroupCallView,
+}
+
+// RateLimitOptions returns the server options, which apply the limits of the
+// web API to the gRPC calls. The API key is passed in the x-api-key metadata.
+// A stream spends its cost once, when it is opened.
+func RateLimitOptions(limiter *ratelimit.Limiter, log *logger.Logger) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := allow(ctx, limiter, log, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := allow(ss.Context(), limiter, log, info.FullMethod, nil); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+func allow(ctx context.Context, limiter *ratelimit.Limiter, log *logger.Logger, fullMethod string, req interface{}) error {
+	group, ok := methodGroups[fullMethod]
+	if !ok {
+		group = ratelimit.GroupDefault
+	}
+	cost := 1
+	if callView, ok := req.(*pb.CallViewRequest); ok {
+		cost = limiter.ViewCost(isc.Hname(callView.ContractHname), isc.Hname(callView.FunctionHname))
+	}
+
+	var apiKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(APIKeyMetadata); len(values) > 0 {
+			apiKey = values[0]
+		}
+	}
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	err := limiter.Allow(group, cost, apiKey, ip)
+	var limited *ratelimit.LimitedError
+	var tooHigh *ratelimit.CostTooHighError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ratelimit.ErrInvalidAPIKey):
+		return status.Error(codes.Unauthenticated, "invalid API key")
+	case errors.As(err, &limited):
+		return status.Error(codes.ResourceExhausted, limited.Error())
+	case errors.As(err, &tooHigh):
+		return status.Error(codes.ResourceExhausted, tooHigh.Error())
+	default:
+		log.Errorf("cannot load API key: %v", err)
+		return status.Error(codes.Internal, "cannot load API key")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package grpcapi implements the gRPC API of the node. It covers the public
+// part of the REST web API, and streams the blocks and the events of the
+// chains instead of the websocket.
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/chainutil"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/grpcapi/pb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WaitRequestProcessedDefaultTimeout is used, when the call has no deadline
+const WaitRequestProcessedDefaultTimeout = 30 * time.Second
+
+type checkRequestsFn func(ch chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error)
+
+type blockEventsFn func(ch chain.ChainCore, blockIndex uint32) ([]*pb.Event, error)
+
+type Server struct {
+	pb.UnimplementedWaspServer
+
+	getChain      chains.ChainProvider
+	checkRequests checkRequestsFn
+	blockEvents   blockEventsFn
+	nodePubKey    *cryptolib.PublicKey
+	log           *logger.Logger
+}
+
+var _ pb.WaspServer = &Server{}
+
+func New(getChain chains.ChainProvider, nodePubKey *cryptolib.PublicKey, log *logger.Logger) *Server {
+	return &Server{
+		getChain:      getChain,
+		checkRequests: chainutil.CheckOffLedgerRequests,
+		blockEvents:   getBlockEvents,
+		nodePubKey:    nodePubKey,
+		log:           log.Named("grpc"),
+	}
+}
+
+// Register registers the service in the gRPC server
+func (s *Server) Register(server *grpc.Server) {
+	pb.RegisterWaspServer(server, s)
+}
+
+func (s *Server) chain(chainIDStr string) (chain.Chain, error) {
+	chainID, err := isc.ChainIDFromString(chainIDStr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid chain ID %q: %v", chainIDStr, err)
+	}
+	ch := s.getChain(chainID)
+	if ch == nil {
+		return nil, status.Errorf(codes.NotFound, "chain not found: %s", chainID)
+	}
+	return ch, nil
+}
+
+func (s *Server) GetChainInfo(_ context.Context, req *pb.GetChainInfoRequest) (*pb.ChainInfo, error) {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := chainutil.CallView(ch, governance.Contract.Hname(), governance.ViewGetChainInfo.Hname(), nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get chain info: %v", err)
+	}
+	info, err := governance.GetChainInfo(ret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot decode chain info: %v", err)
+	}
+	var blockIndex uint32
+	err = optimism.RetryOnStateInvalidated(func() (err error) {
+		blockIndex, err = ch.GetStateReader().BlockIndex()
+		return err
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get block index: %v", err)
+	}
+	return &pb.ChainInfo{
+		ChainId:             info.ChainID.String(),
+		ChainOwnerId:        info.ChainOwnerID.String(),
+		Description:         info.Description,
+		MaxBlobSize:         info.MaxBlobSize,
+		MaxEventSize:        uint32(info.MaxEventSize),
+		MaxEventsPerRequest: uint32(info.MaxEventsPerReq),
+		BlockIndex:          blockIndex,
+	}, nil
+}
+
+func (s *Server) CallView(_ context.Context, req *pb.CallViewRequest) (*pb.CallViewResponse, error) {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	params := dict.New()
+	for k, v := range req.Params {
+		params.Set(kv.Key(k), v)
+	}
+	ret, err := chainutil.CallView(ch, isc.Hname(req.ContractHname), isc.Hname(req.FunctionHname), params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "view call failed: %v", err)
+	}
+	result := make(map[string][]byte, len(ret))
+	for k, v := range ret {
+		result[string(k)] = v
+	}
+	return &pb.CallViewResponse{Result: result}, nil
+}
+
+func (s *Server) StateGet(_ context.Context, req *pb.StateGetRequest) (*pb.StateGetResponse, error) {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	err = optimism.RetryOnStateInvalidated(func() (err error) {
+		value, err = ch.GetStateReader().KVStoreReader().Get(kv.Key(req.Key))
+		return err
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot read the state: %v", err)
+	}
+	return &pb.StateGetResponse{Value: value}, nil
+}
+
+// SubmitRequest runs the checks of the web API on the request, against the
+// latest state of the chain
+func (s *Server) SubmitRequest(_ context.Context, req *pb.SubmitRequestRequest) (*pb.SubmitRequestResponse, error) {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	rGeneric, err := isc.NewRequestFromMarshalUtil(marshalutil.New(req.Request))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot decode off-ledger request: %v", err)
+	}
+	offLedgerReq, ok := rGeneric.(isc.OffLedgerRequest)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "off-ledger request expected")
+	}
+	if err := offLedgerReq.VerifySignature(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not verify: %v", err)
+	}
+	if !offLedgerReq.ChainID().Equals(ch.ID()) {
+		return nil, status.Error(codes.InvalidArgument, "request is for a different chain")
+	}
+	rejected, err := s.checkRequests(ch, []isc.OffLedgerRequest{offLedgerReq})
+	if err != nil {
+		s.log.Errorf("SubmitRequest: cannot check the request: %v", err)
+		return nil, status.Error(codes.Internal, "cannot check the request")
+	}
+	if rejected[0] != nil {
+		return nil, status.Error(codes.FailedPrecondition, rejected[0].Error())
+	}
+	ch.EnqueueOffLedgerRequestMsg(&messages.OffLedgerRequestMsgIn{
+		OffLedgerRequestMsg: messages.OffLedgerRequestMsg{
+			ChainID: ch.ID(),
+			Req:     offLedgerReq,
+		},
+		SenderPubKey: s.nodePubKey,
+	})
+	return &pb.SubmitRequestResponse{RequestId: offLedgerReq.ID().String()}, nil
+}
+
+func (s *Server) GetRequestReceipt(_ context.Context, req *pb.GetRequestReceiptRequest) (*pb.GetRequestReceiptResponse, error) {
+	ch, reqID, err := s.chainAndRequestID(req)
+	if err != nil {
+		return nil, err
+	}
+	return getReceipt(ch, reqID)
+}
+
+func (s *Server) WaitRequestProcessed(ctx context.Context, req *pb.GetRequestReceiptRequest) (*pb.GetRequestReceiptResponse, error) {
+	ch, reqID, err := s.chainAndRequestID(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, WaitRequestProcessedDefaultTimeout)
+		defer cancel()
+	}
+
+	// subscribe before checking the receipt, not to miss the event
+	requestProcessed := make(chan struct{}, 1)
+	attachID := ch.AttachToRequestProcessed(func(rid isc.RequestID) {
+		if rid != reqID {
+			return
+		}
+		select {
+		case requestProcessed <- struct{}{}:
+		default:
+		}
+	})
+	defer ch.DetachFromRequestProcessed(attachID)
+
+	ret, err := getReceipt(ch, reqID)
+	if err != nil || ret.Found {
+		return ret, err
+	}
+	select {
+	case <-requestProcessed:
+		return getReceipt(ch, reqID)
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (s *Server) chainAndRequestID(req *pb.GetRequestReceiptRequest) (chain.Chain, isc.RequestID, error) {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return nil, isc.RequestID{}, err
+	}
+	reqID, err := isc.RequestIDFromString(req.RequestId)
+	if err != nil {
+		return nil, isc.RequestID{}, status.Errorf(codes.InvalidArgument, "invalid request ID %q: %v", req.RequestId, err)
+	}
+	return ch, reqID, nil
+}
+
+func getReceipt(ch chain.ChainRequests, reqID isc.RequestID) (*pb.GetRequestReceiptResponse, error) {
+	var receipt *isc.Receipt
+	err := optimism.RetryOnStateInvalidated(func() (err error) {
+		panicCatchErr := panicutil.CatchPanicReturnError(func() {
+			receipt, err = doGetReceipt(ch, reqID)
+		}, coreutil.ErrorStateInvalidated)
+		if err != nil {
+			return err
+		}
+		return panicCatchErr
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get the receipt: %v", err)
+	}
+	if receipt == nil {
+		return &pb.GetRequestReceiptResponse{}, nil
+	}
+	return &pb.GetRequestReceiptResponse{
+		Found: true,
+		Receipt: &pb.Receipt{
+			Request:       receipt.Request,
+			BlockIndex:    receipt.BlockIndex,
+			RequestIndex:  uint32(receipt.RequestIndex),
+			GasBudget:     receipt.GasBudget,
+			GasBurned:     receipt.GasBurned,
+			GasFeeCharged: receipt.GasFeeCharged,
+			Error:         receipt.ResolvedError,
+		},
+	}, nil
+}
+
+func doGetReceipt(ch chain.ChainRequests, reqID isc.RequestID) (*isc.Receipt, error) {
+	receipt, err := ch.GetRequestReceipt(reqID)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	resolvedError, err := ch.ResolveError(receipt.Error)
+	if err != nil {
+		return nil, err
+	}
+	return receipt.ToISCReceipt(resolvedError), nil
+}
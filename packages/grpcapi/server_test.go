@@ -0,0 +1,272 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/grpcapi/pb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
+	util "github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/webapi/ratelimit"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockedChain struct {
+	chain.Chain // the methods not used by the server panic
+	id          *isc.ChainID
+	enqueued    []*messages.OffLedgerRequestMsgIn
+}
+
+func (m *mockedChain) ID() *isc.ChainID {
+	return m.id
+}
+
+func (m *mockedChain) EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgIn) {
+	m.enqueued = append(m.enqueued, msg)
+}
+
+func (m *mockedChain) GetRequestReceipt(reqID isc.RequestID) (*blocklog.RequestReceipt, error) {
+	return &blocklog.RequestReceipt{
+		Request:       util.DummyOffledgerRequest(m.id),
+		GasBudget:     123,
+		GasBurned:     10,
+		GasFeeCharged: 100,
+		BlockIndex:    111,
+		RequestIndex:  222,
+	}, nil
+}
+
+func (m *mockedChain) ResolveError(e *isc.UnresolvedVMError) (*isc.VMError, error) {
+	return nil, nil
+}
+
+type testEnv struct {
+	t      *testing.T
+	chain  *mockedChain
+	server *Server
+	client pb.WaspClient
+}
+
+func newTestEnv(t *testing.T, opts ...grpc.ServerOption) *testEnv {
+	log := testlogger.NewLogger(t)
+	ch := &mockedChain{id: isc.RandomChainID()}
+	server := New(func(chainID *isc.ChainID) chain.Chain {
+		if chainID.Equals(ch.id) {
+			return ch
+		}
+		return nil
+	}, cryptolib.NewKeyPair().GetPublicKey(), log)
+	server.checkRequests = func(_ chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error) {
+		return make([]error, len(reqs)), nil
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer(opts...)
+	server.Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &testEnv{t: t, chain: ch, server: server, client: pb.NewWaspClient(conn)}
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	require.Error(t, err)
+	require.Equal(t, code, status.Code(err), err.Error())
+}
+
+func TestSubmitRequest(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	req := util.DummyOffledgerRequest(env.chain.id)
+
+	res, err := env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: env.chain.id.String(), Request: req.Bytes()})
+	require.NoError(t, err)
+	require.Equal(t, req.ID().String(), res.RequestId)
+	require.Len(t, env.chain.enqueued, 1)
+	require.Equal(t, req.ID(), env.chain.enqueued[0].Req.ID())
+
+	_, err = env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: "foo", Request: req.Bytes()})
+	requireCode(t, codes.InvalidArgument, err)
+	_, err = env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: isc.RandomChainID().String(), Request: req.Bytes()})
+	requireCode(t, codes.NotFound, err)
+	_, err = env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: env.chain.id.String(), Request: []byte{1, 2, 3}})
+	requireCode(t, codes.InvalidArgument, err)
+	other := util.DummyOffledgerRequest(isc.RandomChainID())
+	_, err = env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: env.chain.id.String(), Request: other.Bytes()})
+	requireCode(t, codes.InvalidArgument, err)
+
+	env.server.checkRequests = func(_ chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error) {
+		return []error{xerrors.New("request already processed")}, nil
+	}
+	_, err = env.client.SubmitRequest(ctx, &pb.SubmitRequestRequest{ChainId: env.chain.id.String(), Request: req.Bytes()})
+	requireCode(t, codes.FailedPrecondition, err)
+	require.Len(t, env.chain.enqueued, 1)
+}
+
+func TestGetRequestReceipt(t *testing.T) {
+	env := newTestEnv(t)
+	reqID := isc.NewRequestID(util.DummyOffledgerRequest(env.chain.id).ID().TransactionID, 0)
+
+	res, err := env.client.GetRequestReceipt(context.Background(), &pb.GetRequestReceiptRequest{
+		ChainId:   env.chain.id.String(),
+		RequestId: reqID.String(),
+	})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.EqualValues(t, 111, res.Receipt.BlockIndex)
+	require.EqualValues(t, 222, res.Receipt.RequestIndex)
+	require.EqualValues(t, 123, res.Receipt.GasBudget)
+	require.EqualValues(t, 10, res.Receipt.GasBurned)
+	require.EqualValues(t, 100, res.Receipt.GasFeeCharged)
+	require.Empty(t, res.Receipt.Error)
+	require.NotEmpty(t, res.Receipt.Request)
+
+	_, err = env.client.GetRequestReceipt(context.Background(), &pb.GetRequestReceiptRequest{
+		ChainId:   env.chain.id.String(),
+		RequestId: "foo",
+	})
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func publishState(chainID *isc.ChainID, blockIndex uint32) {
+	// see chain.PublishStateTransition
+	publisher.Publish("state", chainID.String(), fmt.Sprint(blockIndex), "1", "anchor")
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	env := newTestEnv(t)
+	reqID := util.DummyOffledgerRequest(env.chain.id).ID()
+	env.server.blockEvents = func(_ chain.ChainCore, blockIndex uint32) ([]*pb.Event, error) {
+		event, err := parseEvent(isc.Hn("some contract").String() + ": some event")
+		require.NoError(t, err)
+		event.BlockIndex = blockIndex
+		event.RequestId = reqID.String()
+		return []*pb.Event{event}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := env.client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{ChainId: env.chain.id.String()})
+	require.NoError(t, err)
+
+	// the subscription is attached asynchronously, publish until it is received
+	go func() {
+		for ctx.Err() == nil {
+			publishState(isc.RandomChainID(), 1)
+			publishState(env.chain.id, 5)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "some event", event.Message)
+	require.EqualValues(t, isc.Hn("some contract"), event.ContractHname)
+	require.EqualValues(t, 5, event.BlockIndex)
+	require.Equal(t, reqID.String(), event.RequestId)
+}
+
+func TestSubscribeEventsOverflow(t *testing.T) {
+	env := newTestEnv(t)
+	called := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	env.server.blockEvents = func(_ chain.ChainCore, blockIndex uint32) ([]*pb.Event, error) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+		<-unblock
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := env.client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{ChainId: env.chain.id.String()})
+	require.NoError(t, err)
+
+	// wait until the subscriber is busy with a block, then fill its buffer
+	go func() {
+		for ctx.Err() == nil {
+			select {
+			case <-called:
+				for i := 0; i <= subscriberBufferSize; i++ {
+					publishState(env.chain.id, uint32(i))
+				}
+				close(unblock)
+				return
+			default:
+				publishState(env.chain.id, 0)
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+	_, err = stream.Recv()
+	requireCode(t, codes.ResourceExhausted, err)
+}
+
+func TestParseEvent(t *testing.T) {
+	event, err := parseEvent("0000002a: some: event")
+	require.NoError(t, err)
+	require.EqualValues(t, 42, event.ContractHname)
+	require.Equal(t, "some: event", event.Message)
+
+	_, err = parseEvent("some event")
+	require.Error(t, err)
+}
+
+func TestRateLimit(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	nodeSigner, err := signer.NewLocal(keystore.NewDBKeyStore(store), log)
+	require.NoError(t, err)
+	reg := registry.NewRegistry(log, store, nodeSigner)
+	config := ratelimit.DefaultConfig()
+	config.Groups[ratelimit.GroupDefault] = ratelimit.Limit{Rate: 1, Burst: 2}
+	limiter := ratelimit.New(config, reg, metrics.DefaultWebAPIMetrics(), log)
+	env := newTestEnv(t, RateLimitOptions(limiter, log)...)
+
+	req := &pb.GetRequestReceiptRequest{
+		ChainId:   env.chain.id.String(),
+		RequestId: isc.NewRequestID(util.DummyOffledgerRequest(env.chain.id).ID().TransactionID, 0).String(),
+	}
+	// the call with an unknown key is charged to the client IP
+	ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, "unknown")
+	_, err = env.client.GetRequestReceipt(ctx, req)
+	requireCode(t, codes.Unauthenticated, err)
+
+	_, err = env.client.GetRequestReceipt(context.Background(), req)
+	require.NoError(t, err)
+	_, err = env.client.GetRequestReceipt(context.Background(), req)
+	requireCode(t, codes.ResourceExhausted, err)
+	_, err = env.client.GetRequestReceipt(ctx, req)
+	requireCode(t, codes.ResourceExhausted, err)
+}
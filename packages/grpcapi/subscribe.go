@@ -0,0 +1,206 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/chainutil"
+	"github.com/iotaledger/wasp/packages/grpcapi/pb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// subscriberBufferSize is the number of blocks buffered for a subscriber. The
+// subscription ends, when a slow subscriber does not keep up.
+const subscriberBufferSize = 100
+
+var errSubscriberOverflow = status.Error(codes.ResourceExhausted, "the subscriber does not keep up with the chain, subscribe again")
+
+type stateTransition struct {
+	blockIndex     uint32
+	anchorOutputID string
+}
+
+type stateSubscription struct {
+	transitions  chan *stateTransition
+	overflow     chan struct{}
+	overflowOnce sync.Once
+	closure      *events.Closure
+}
+
+// subscribeStateTransitions buffers the new states of the chain, until the
+// subscription is closed. The overflow channel is closed, once the buffer is
+// full.
+func subscribeStateTransitions(ch chain.ChainCore) *stateSubscription {
+	chainID := ch.ID().String()
+	sub := &stateSubscription{
+		transitions: make(chan *stateTransition, subscriberBufferSize),
+		overflow:    make(chan struct{}),
+	}
+	sub.closure = events.NewClosure(func(msgType string, parts []string) {
+		// see chain.PublishStateTransition
+		if msgType != "state" || len(parts) < 4 || parts[0] != chainID {
+			return
+		}
+		blockIndex, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return
+		}
+		select {
+		case sub.transitions <- &stateTransition{blockIndex: uint32(blockIndex), anchorOutputID: parts[3]}:
+		default:
+			sub.overflowOnce.Do(func() { close(sub.overflow) })
+		}
+	})
+	publisher.Event.Attach(sub.closure)
+	return sub
+}
+
+func (sub *stateSubscription) close() {
+	publisher.Event.Detach(sub.closure)
+}
+
+// SubscribeBlocks sends the block info of each new state of the chain
+func (s *Server) SubscribeBlocks(req *pb.SubscribeBlocksRequest, stream pb.Wasp_SubscribeBlocksServer) error {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return err
+	}
+	sub := subscribeStateTransitions(ch)
+	defer sub.close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.overflow:
+			return errSubscriberOverflow
+		case transition := <-sub.transitions:
+			block, err := getBlock(ch, transition)
+			if err != nil {
+				s.log.Warnf("SubscribeBlocks: cannot get block %d: %v", transition.blockIndex, err)
+				continue
+			}
+			if err := stream.Send(block); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func getBlock(ch chain.ChainCore, transition *stateTransition) (*pb.Block, error) {
+	ret, err := chainutil.CallView(ch, blocklog.Contract.Hname(), blocklog.ViewGetBlockInfo.Hname(), dict.Dict{
+		blocklog.ParamBlockIndex: codec.EncodeUint32(transition.blockIndex),
+	})
+	if err != nil {
+		return nil, err
+	}
+	info, err := blocklog.BlockInfoFromBytes(transition.blockIndex, ret.MustGet(blocklog.ParamBlockInfo))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Block{
+		BlockIndex:            info.BlockIndex,
+		Timestamp:             timestamppb.New(info.Timestamp),
+		TotalRequests:         uint32(info.TotalRequests),
+		NumSuccessfulRequests: uint32(info.NumSuccessfulRequests),
+		NumOffLedgerRequests:  uint32(info.NumOffLedgerRequests),
+		GasBurned:             info.GasBurned,
+		GasFeeCharged:         info.GasFeeCharged,
+		AnchorOutputId:        transition.anchorOutputID,
+	}, nil
+}
+
+// SubscribeEvents sends the events emitted by the contracts of the chain, once
+// their block is produced
+func (s *Server) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.Wasp_SubscribeEventsServer) error {
+	ch, err := s.chain(req.ChainId)
+	if err != nil {
+		return err
+	}
+	sub := subscribeStateTransitions(ch)
+	defer sub.close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.overflow:
+			return errSubscriberOverflow
+		case transition := <-sub.transitions:
+			blockEvents, err := s.blockEvents(ch, transition.blockIndex)
+			if err != nil {
+				s.log.Warnf("SubscribeEvents: cannot get the events of block %d: %v", transition.blockIndex, err)
+				continue
+			}
+			for _, event := range blockEvents {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// getBlockEvents reads the events emitted by the requests of the block
+func getBlockEvents(ch chain.ChainCore, blockIndex uint32) ([]*pb.Event, error) {
+	ret, err := chainutil.CallView(ch, blocklog.Contract.Hname(), blocklog.ViewGetRequestIDsForBlock.Hname(), dict.Dict{
+		blocklog.ParamBlockIndex: codec.EncodeUint32(blockIndex),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var blockEvents []*pb.Event
+	reqIDs := collections.NewArray16ReadOnly(ret, blocklog.ParamRequestID)
+	for i := uint16(0); i < reqIDs.MustLen(); i++ {
+		reqID, err := codec.DecodeRequestID(reqIDs.MustGetAt(i))
+		if err != nil {
+			return nil, err
+		}
+		res, err := chainutil.CallView(ch, blocklog.Contract.Hname(), blocklog.ViewGetEventsForRequest.Hname(), dict.Dict{
+			blocklog.ParamRequestID: codec.EncodeRequestID(reqID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		msgs := collections.NewArray16ReadOnly(res, blocklog.ParamEvent)
+		for j := uint16(0); j < msgs.MustLen(); j++ {
+			event, err := parseEvent(string(msgs.MustGetAt(j)))
+			if err != nil {
+				return nil, err
+			}
+			event.BlockIndex = blockIndex
+			event.RequestId = reqID.String()
+			blockEvents = append(blockEvents, event)
+		}
+	}
+	return blockEvents, nil
+}
+
+// parseEvent splits the event, as saved by blocklog.SaveEvent, into the
+// contract and the message
+func parseEvent(text string) (*pb.Event, error) {
+	hnameStr, msg, ok := strings.Cut(text, ": ")
+	if !ok {
+		return nil, xerrors.Errorf("invalid event %q", text)
+	}
+	hname, err := isc.HnameFromString(hnameStr)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Event{ContractHname: uint32(hname), Message: msg}, nil
+}
@@ -20,6 +20,7 @@ const (
 	AgentIDKindAddress
 	AgentIDKindContract
 	AgentIDKindEthereumAddress
+	AgentIDKindMultisig
 )
 
 // AgentID represents any entity that can hold assets on L2 and/or call contracts.
@@ -79,6 +80,8 @@ func AgentIDFromMarshalUtil(mu *marshalutil.MarshalUtil) (AgentID, error) {
 		return contractAgentIDFromMarshalUtil(mu)
 	case AgentIDKindEthereumAddress:
 		return ethAgentIDFromMarshalUtil(mu)
+	case AgentIDKindMultisig:
+		return multisigAgentIDFromMarshalUtil(mu)
 	}
 	return nil, fmt.Errorf("no handler for AgentID kind %d", kind)
 }
@@ -112,6 +115,9 @@ func NewAgentIDFromString(s string) (AgentID, error) {
 	if strings.HasPrefix(addrPart, string(parameters.L1().Protocol.Bech32HRP)) {
 		return addressAgentIDFromString(s)
 	}
+	if strings.HasPrefix(addrPart, multisigAgentIDPrefix) {
+		return multisigAgentIDFromString(addrPart)
+	}
 	if strings.HasPrefix(addrPart, "0x") {
 		return ethAgentIDFromString(s)
 	}
@@ -0,0 +1,69 @@
+package isc
+
+import (
+	"strings"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+)
+
+const multisigAgentIDPrefix = "multisig:"
+
+// MultisigAgentID is an AgentID of an L2 account controlled by M-of-N keys.
+// It is the hash of the policy the account was registered with, it doesn't change when the signers are rotated
+type MultisigAgentID struct {
+	id hashing.HashValue
+}
+
+var _ AgentID = &MultisigAgentID{}
+
+// NewMultisigAgentID returns the AgentID of the account registered with the policy
+func NewMultisigAgentID(policy *MultisigPolicy) *MultisigAgentID {
+	return &MultisigAgentID{id: policy.Hash()}
+}
+
+func multisigAgentIDFromMarshalUtil(mu *marshalutil.MarshalUtil) (AgentID, error) {
+	idBytes, err := mu.ReadBytes(hashing.HashSize)
+	if err != nil {
+		return nil, err
+	}
+	id, err := hashing.HashValueFromBytes(idBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &MultisigAgentID{id: id}, nil
+}
+
+func multisigAgentIDFromString(s string) (AgentID, error) {
+	id, err := hashing.HashValueFromHex(strings.TrimPrefix(s, multisigAgentIDPrefix))
+	if err != nil {
+		return nil, err
+	}
+	return &MultisigAgentID{id: id}, nil
+}
+
+func (a *MultisigAgentID) ID() hashing.HashValue {
+	return a.id
+}
+
+func (a *MultisigAgentID) Kind() AgentIDKind {
+	return AgentIDKindMultisig
+}
+
+func (a *MultisigAgentID) Bytes() []byte {
+	return append([]byte{byte(a.Kind())}, a.id[:]...)
+}
+
+func (a *MultisigAgentID) String() string {
+	return multisigAgentIDPrefix + a.id.Hex()
+}
+
+func (a *MultisigAgentID) Equals(other AgentID) bool {
+	if other == nil {
+		return false
+	}
+	if other.Kind() != a.Kind() {
+		return false
+	}
+	return other.(*MultisigAgentID).id == a.id
+}
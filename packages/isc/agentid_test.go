@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/iota.go/v3/tpkg"
+	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/stretchr/testify/require"
 )
@@ -95,6 +96,29 @@ func TestAgentID(t *testing.T) {
 			require.True(t, a.Equals(a2))
 		}
 
+		{
+			b := a.Bytes()
+			a2, err := AgentIDFromBytes(b)
+			require.NoError(t, err)
+			require.EqualValues(t, a, a2)
+			require.True(t, a.Equals(a2))
+		}
+	}
+	{
+		policy, err := NewMultisigPolicy(1, cryptolib.NewKeyPair().GetPublicKey())
+		require.NoError(t, err)
+		a := NewMultisigAgentID(policy)
+
+		{
+			s := a.String()
+			require.NotContains(t, s, "@")
+			require.Regexp(t, `^multisig:[0-9a-f]{64}$`, s)
+			a2, err := NewAgentIDFromString(s)
+			require.NoError(t, err)
+			require.EqualValues(t, a, a2)
+			require.True(t, a.Equals(a2))
+		}
+
 		{
 			b := a.Bytes()
 			a2, err := AgentIDFromBytes(b)
@@ -14,6 +14,7 @@ const (
 	CoreContractBlocklog        = "blocklog"
 	CoreContractErrors          = "errors"
 	CoreContractGovernance      = "governance"
+	CoreContractScheduler       = "scheduler"
 	CoreEPRotateStateController = "rotateStateController"
 )
 
@@ -26,6 +27,7 @@ var (
 	CoreContractBlocklogHname        = isc.Hn(CoreContractBlocklog)
 	CoreContractErrorsHname          = isc.Hn(CoreContractErrors)
 	CoreContractGovernanceHname      = isc.Hn(CoreContractGovernance)
+	CoreContractSchedulerHname       = isc.Hn(CoreContractScheduler)
 	CoreEPRotateStateControllerHname = isc.Hn(CoreEPRotateStateController)
 
 	hnames = map[string]isc.Hname{
@@ -37,6 +39,7 @@ var (
 		CoreContractBlocklog:   CoreContractBlocklogHname,
 		CoreContractGovernance: CoreContractGovernanceHname,
 		CoreContractErrors:     CoreContractErrorsHname,
+		CoreContractScheduler:  CoreContractSchedulerHname,
 	}
 )
 
@@ -0,0 +1,55 @@
+package coreutil
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+)
+
+// The list-style views of the core contracts return their elements one page at a time, in the order of
+// the keys of the elements. While there are more elements, the result contains the cursor of the next page,
+// which is passed to the view to get it
+const (
+	// ParamPageCursor the cursor of the page, absent for the first page
+	ParamPageCursor = "pc"
+	// ParamPageLimit the maximum number of elements of the page, uint32. Optional, default: MaxPageLimit
+	ParamPageLimit = "pl"
+	// ParamPageNextCursor the cursor of the next page in the result, absent if there are no more elements
+	ParamPageNextCursor = "pn"
+
+	MaxPageLimit = 1000
+)
+
+// GetPageParams returns the cursor and the limit of the page requested in the params of the view.
+// The cursor is nil for the first page
+func GetPageParams(ctx isc.SandboxView) ([]byte, uint32) {
+	cursor := ctx.Params().MustGetBytes(ParamPageCursor, nil)
+	limit := ctx.Params().MustGetUint32(ParamPageLimit, MaxPageLimit)
+	ctx.Requiref(limit > 0 && limit <= MaxPageLimit, "page limit must be between 1 and %d", MaxPageLimit)
+	return cursor, limit
+}
+
+// SetNextPageCursor sets the cursor of the next page in the result of the view, if there are more elements
+func SetNextPageCursor(ret dict.Dict, next []byte) {
+	if next != nil {
+		ret.Set(ParamPageNextCursor, next)
+	}
+}
+
+// PageParams returns the params of the view requesting the page after the cursor. A nil cursor requests
+// the first page, a zero limit requests MaxPageLimit elements
+func PageParams(params dict.Dict, cursor []byte, limit uint32) dict.Dict {
+	ret := params.Clone()
+	if cursor != nil {
+		ret.Set(ParamPageCursor, cursor)
+	}
+	if limit > 0 {
+		ret.Set(ParamPageLimit, codec.EncodeUint32(limit))
+	}
+	return ret
+}
+
+// NextPageCursor returns the cursor of the next page from the result of the view, nil if it was the last one
+func NextPageCursor(res dict.Dict) []byte {
+	return res.MustGet(ParamPageNextCursor)
+}
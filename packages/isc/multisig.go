@@ -0,0 +1,156 @@
+package isc
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"golang.org/x/xerrors"
+)
+
+// MaxMultisigSigners is the maximum number of signers of a multisig account
+const MaxMultisigSigners = 32
+
+// MultisigPolicy is the signer set of a multisig account: a request of the account must be
+// signed by at least Threshold of the Signers
+type MultisigPolicy struct {
+	Threshold uint16
+	Signers   []*cryptolib.PublicKey
+}
+
+func NewMultisigPolicy(threshold uint16, signers ...*cryptolib.PublicKey) (*MultisigPolicy, error) {
+	ret := &MultisigPolicy{
+		Threshold: threshold,
+		Signers:   signers,
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Validate checks if the threshold can be reached and the signers are not repeated
+func (p *MultisigPolicy) Validate() error {
+	if len(p.Signers) == 0 || len(p.Signers) > MaxMultisigSigners {
+		return xerrors.Errorf("wrong number of signers: %d", len(p.Signers))
+	}
+	if p.Threshold == 0 || int(p.Threshold) > len(p.Signers) {
+		return xerrors.Errorf("wrong threshold %d for %d signers", p.Threshold, len(p.Signers))
+	}
+	for i, s := range p.Signers {
+		if p.SignerIndex(s) != i {
+			return xerrors.Errorf("repeated signer %s", s)
+		}
+	}
+	return nil
+}
+
+// SignerIndex returns the index of the key in the signers, -1 if the key is not one of them
+func (p *MultisigPolicy) SignerIndex(publicKey *cryptolib.PublicKey) int {
+	for i, s := range p.Signers {
+		if s.Equals(publicKey) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *MultisigPolicy) Hash() hashing.HashValue {
+	return hashing.HashData(p.Bytes())
+}
+
+func (p *MultisigPolicy) Equals(other *MultisigPolicy) bool {
+	return other != nil && p.Hash() == other.Hash()
+}
+
+func (p *MultisigPolicy) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.WriteUint16(p.Threshold).
+		WriteUint8(uint8(len(p.Signers)))
+	for _, s := range p.Signers {
+		mu.WriteBytes(s.AsBytes())
+	}
+}
+
+func (p *MultisigPolicy) Bytes() []byte {
+	mu := marshalutil.New()
+	p.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+func MultisigPolicyFromMarshalUtil(mu *marshalutil.MarshalUtil) (*MultisigPolicy, error) {
+	ret := &MultisigPolicy{}
+	var err error
+	if ret.Threshold, err = mu.ReadUint16(); err != nil {
+		return nil, err
+	}
+	n, err := mu.ReadUint8()
+	if err != nil {
+		return nil, err
+	}
+	ret.Signers = make([]*cryptolib.PublicKey, n)
+	for i := range ret.Signers {
+		b, err := mu.ReadBytes(cryptolib.PublicKeySize)
+		if err != nil {
+			return nil, err
+		}
+		if ret.Signers[i], err = cryptolib.NewPublicKeyFromBytes(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func MultisigPolicyFromBytes(data []byte) (*MultisigPolicy, error) {
+	return MultisigPolicyFromMarshalUtil(marshalutil.New(data))
+}
+
+// VerifySignatures checks if the data is signed by enough distinct signers
+func (p *MultisigPolicy) VerifySignatures(data []byte, signatures []*MultisigSignature) error {
+	signed := make(map[uint8]bool)
+	for _, sig := range signatures {
+		if int(sig.SignerIndex) >= len(p.Signers) {
+			return xerrors.Errorf("wrong signer index %d", sig.SignerIndex)
+		}
+		if !p.Signers[sig.SignerIndex].Verify(data, sig.Signature) {
+			return xerrors.Errorf("invalid signature of the signer %d", sig.SignerIndex)
+		}
+		signed[sig.SignerIndex] = true
+	}
+	if len(signed) < int(p.Threshold) {
+		return xerrors.Errorf("signed by %d signers, %d required", len(signed), p.Threshold)
+	}
+	return nil
+}
+
+func (p *MultisigPolicy) String() string {
+	return fmt.Sprintf("%d of %v", p.Threshold, p.Signers)
+}
+
+// MultisigSignature is a signature by one of the signers of the multisig account
+type MultisigSignature struct {
+	SignerIndex uint8
+	Signature   []byte
+}
+
+func (s *MultisigSignature) writeToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.WriteUint8(s.SignerIndex).
+		WriteUint16(uint16(len(s.Signature))).
+		WriteBytes(s.Signature)
+}
+
+func (s *MultisigSignature) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if s.SignerIndex, err = mu.ReadUint8(); err != nil {
+		return err
+	}
+	n, err := mu.ReadUint16()
+	if err != nil {
+		return err
+	}
+	s.Signature, err = mu.ReadBytes(int(n))
+	return err
+}
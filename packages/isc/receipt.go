@@ -3,6 +3,7 @@ package isc
 import (
 	"fmt"
 
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
@@ -13,6 +14,8 @@ type Receipt struct {
 	GasBudget     uint64             `json:"gasBudget"`
 	GasBurned     uint64             `json:"gasBurned"`
 	GasFeeCharged uint64             `json:"gasFeeCharged"`
+	GasFeePayer   string             `json:"gasFeePayer"` // empty if no fee was charged
+	CallResults   []dict.Dict        `json:"callResults"` // results of the calls of the multi-call request
 	BlockIndex    uint32             `json:"blockIndex"`
 	RequestIndex  uint16             `json:"requestIndex"`
 	ResolvedError string             `json:"resolvedError"`
@@ -32,6 +35,9 @@ func (r Receipt) String() string {
 	ret += fmt.Sprintf("Err: %v\n", r.ResolvedError)
 	ret += fmt.Sprintf("Block/Request index: %d / %d\n", r.BlockIndex, r.RequestIndex)
 	ret += fmt.Sprintf("Gas budget / burned / fee charged: %d / %d /%d\n", r.GasBudget, r.GasBurned, r.GasFeeCharged)
+	if r.GasFeePayer != "" {
+		ret += fmt.Sprintf("Gas fee paid by: %s\n", r.GasFeePayer)
+	}
 	ret += fmt.Sprintf("Call data: %s\n", r.Request)
 	return ret
 }
@@ -61,6 +61,41 @@ type OffLedgerRequest interface {
 	VerifySignature() error
 }
 
+// SponsoredOffLedgerRequest is an off-ledger request, the gas fee of which can be paid by a sponsor
+// instead of the sender
+type SponsoredOffLedgerRequest interface {
+	OffLedgerRequest
+	WithSponsor(key *cryptolib.KeyPair, maxFee uint64) SponsoredOffLedgerRequest
+	Sponsor() (AgentID, uint64)
+}
+
+type UnsignedMultiCallRequest interface {
+	WithNonce(nonce uint64) UnsignedMultiCallRequest
+	WithGasBudget(gasBudget uint64) UnsignedMultiCallRequest
+	Sign(key *cryptolib.KeyPair) MultiCallRequest
+}
+
+// MultiCallRequest is an off-ledger request with an ordered list of calls, run atomically with one gas budget
+type MultiCallRequest interface {
+	OffLedgerRequest
+	Calls() []*Call
+}
+
+type UnsignedMultisigRequest interface {
+	WithNonce(nonce uint64) UnsignedMultisigRequest
+	WithGasBudget(gasBudget uint64) UnsignedMultisigRequest
+	WithAllowance(allowance *Allowance) UnsignedMultisigRequest
+	Sign(keys ...*cryptolib.KeyPair) (MultisigRequest, error)
+}
+
+// MultisigRequest is an off-ledger request sent by a multisig account. The signers sign it one by one,
+// it is valid once signed by the threshold of the signers
+type MultisigRequest interface {
+	OffLedgerRequest
+	Sign(keys ...*cryptolib.KeyPair) (MultisigRequest, error)
+	MultisigPolicy() *MultisigPolicy
+}
+
 type OnLedgerRequest interface {
 	Request
 	Output() iotago.Output
@@ -69,6 +104,13 @@ type OnLedgerRequest interface {
 	Features() Features
 }
 
+// ScheduledCallRequest is a call, registered in the 'scheduler' core contract
+// and run by the VM at the start of a block, once it is due.
+type ScheduledCallRequest interface {
+	Request
+	CallID() uint64
+}
+
 type ReturnAmountOptions interface {
 	ReturnTo() iotago.Address
 	Amount() uint64
@@ -35,6 +35,15 @@ func NewEVMOffLedgerRequest(chainID *ChainID, tx *types.Transaction) (OffLedgerR
 	}, nil
 }
 
+// EVMTransaction returns the Ethereum transaction of the request, if it is an EVM transaction request
+func EVMTransaction(req Request) (*types.Transaction, bool) {
+	r, ok := req.(*evmOffLedgerRequest)
+	if !ok {
+		return nil, false
+	}
+	return r.tx, true
+}
+
 func (r *evmOffLedgerRequest) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
 	var err error
 	if r.chainID, err = ChainIDFromMarshalUtil(mu); err != nil {
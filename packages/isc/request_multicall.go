@@ -0,0 +1,267 @@
+package isc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+)
+
+// MaxCallsInMultiCallRequest is the maximum number of calls in one multi-call request
+const MaxCallsInMultiCallRequest = 32
+
+// Call is one of the calls of the multi-call request
+type Call struct {
+	Target    CallTarget
+	Params    dict.Dict
+	Allowance *Allowance
+}
+
+func NewCall(contract, entryPoint Hname, params dict.Dict, allowance *Allowance) *Call {
+	if params == nil {
+		params = dict.New()
+	}
+	if allowance == nil {
+		allowance = NewEmptyAllowance()
+	}
+	return &Call{
+		Target:    NewCallTarget(contract, entryPoint),
+		Params:    params,
+		Allowance: allowance.Clone(),
+	}
+}
+
+func (c *Call) writeToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.Write(c.Target.Contract).
+		Write(c.Target.EntryPoint).
+		Write(c.Params)
+	c.Allowance.WriteToMarshalUtil(mu)
+}
+
+func (c *Call) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if err = c.Target.Contract.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if err = c.Target.EntryPoint.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if c.Params, err = dict.FromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if c.Allowance, err = AllowanceFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Call) String() string {
+	return fmt.Sprintf("%s::%s(%s)", c.Target.Contract, c.Target.EntryPoint, c.Params)
+}
+
+// offLedgerMultiCallRequest is an off-ledger request, which carries an ordered list of calls.
+// The VM runs all the calls in one go, with one gas budget. If any of the calls fails, all of them are reverted.
+type offLedgerMultiCallRequest struct {
+	chainID         *ChainID
+	calls           []*Call
+	signatureScheme *offLedgerSignatureScheme // nil if unsigned
+	nonce           uint64
+	gasBudget       uint64
+}
+
+var (
+	_ UnsignedMultiCallRequest = &offLedgerMultiCallRequest{}
+	_ MultiCallRequest         = &offLedgerMultiCallRequest{}
+)
+
+// NewOffLedgerMultiCallRequest creates the request with the calls, of which there must be
+// at least one and at most MaxCallsInMultiCallRequest
+func NewOffLedgerMultiCallRequest(chainID *ChainID, calls []*Call, nonce uint64) (UnsignedMultiCallRequest, error) {
+	if err := checkNumCalls(len(calls)); err != nil {
+		return nil, err
+	}
+	return &offLedgerMultiCallRequest{
+		chainID:   chainID,
+		calls:     calls,
+		nonce:     nonce,
+		gasBudget: gas.MaxGasPerCall,
+	}, nil
+}
+
+func checkNumCalls(numCalls int) error {
+	if numCalls == 0 || numCalls > MaxCallsInMultiCallRequest {
+		return fmt.Errorf("wrong number of calls in the multi-call request: %d, expected 1 to %d", numCalls, MaxCallsInMultiCallRequest)
+	}
+	return nil
+}
+
+func (r *offLedgerMultiCallRequest) WithNonce(nonce uint64) UnsignedMultiCallRequest {
+	r.nonce = nonce
+	return r
+}
+
+func (r *offLedgerMultiCallRequest) WithGasBudget(gasBudget uint64) UnsignedMultiCallRequest {
+	r.gasBudget = gasBudget
+	return r
+}
+
+// Sign signs the essence
+func (r *offLedgerMultiCallRequest) Sign(key *cryptolib.KeyPair) MultiCallRequest {
+	r.signatureScheme = &offLedgerSignatureScheme{
+		publicKey: key.GetPublicKey(),
+	}
+	r.signatureScheme.signature = key.GetPrivateKey().Sign(r.essenceBytes())
+	return r
+}
+
+func (r *offLedgerMultiCallRequest) essenceBytes() []byte {
+	mu := marshalutil.New()
+	r.writeEssenceToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+func (r *offLedgerMultiCallRequest) writeEssenceToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.
+		WriteByte(requestKindTagOffLedgerMultiCall).
+		Write(r.chainID).
+		WriteUint64(r.nonce).
+		WriteUint64(r.gasBudget).
+		WriteUint16(uint16(len(r.calls)))
+	for _, c := range r.calls {
+		c.writeToMarshalUtil(mu)
+	}
+	r.signatureScheme.writeEssence(mu)
+}
+
+func (r *offLedgerMultiCallRequest) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	r.writeEssenceToMarshalUtil(mu)
+	r.signatureScheme.writeSignature(mu)
+}
+
+func (r *offLedgerMultiCallRequest) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if r.chainID, err = ChainIDFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.nonce, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	if r.gasBudget, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	numCalls, err := mu.ReadUint16()
+	if err != nil {
+		return err
+	}
+	if err := checkNumCalls(int(numCalls)); err != nil {
+		return err
+	}
+	r.calls = make([]*Call, numCalls)
+	for i := range r.calls {
+		r.calls[i] = &Call{}
+		if err := r.calls[i].readFromMarshalUtil(mu); err != nil {
+			return err
+		}
+	}
+	r.signatureScheme = &offLedgerSignatureScheme{}
+	if err := r.signatureScheme.readEssence(mu); err != nil {
+		return err
+	}
+	return r.signatureScheme.readSignature(mu)
+}
+
+func (r *offLedgerMultiCallRequest) Bytes() []byte {
+	mu := marshalutil.New()
+	r.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+// VerifySignature verifies essence signature
+func (r *offLedgerMultiCallRequest) VerifySignature() error {
+	if !r.signatureScheme.publicKey.Verify(r.essenceBytes(), r.signatureScheme.signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// Calls returns the calls of the request, in the order they are run
+func (r *offLedgerMultiCallRequest) Calls() []*Call {
+	return r.calls
+}
+
+func (r *offLedgerMultiCallRequest) IsOffLedger() bool {
+	return true
+}
+
+func (r *offLedgerMultiCallRequest) ChainID() *ChainID {
+	return r.chainID
+}
+
+// Nonce incremental nonce used for replay protection
+func (r *offLedgerMultiCallRequest) Nonce() uint64 {
+	return r.nonce
+}
+
+// ID returns request id for this request. The request must be signed
+func (r *offLedgerMultiCallRequest) ID() RequestID {
+	return NewRequestID(iotago.TransactionID(hashing.HashData(r.Bytes())), 0)
+}
+
+// CallTarget is the target of the first call
+func (r *offLedgerMultiCallRequest) CallTarget() CallTarget {
+	return r.calls[0].Target
+}
+
+// Params are the parameters of the first call
+func (r *offLedgerMultiCallRequest) Params() dict.Dict {
+	return r.calls[0].Params
+}
+
+// Allowance is the total allowance of all the calls, i.e. what is debited from the sender's account at most
+func (r *offLedgerMultiCallRequest) Allowance() *Allowance {
+	ret := NewEmptyAllowance()
+	for _, c := range r.calls {
+		if !c.Allowance.IsEmpty() {
+			ret.Add(c.Allowance)
+		}
+	}
+	return ret
+}
+
+func (r *offLedgerMultiCallRequest) SenderAccount() AgentID {
+	return NewAgentID(r.signatureScheme.publicKey.AsEd25519Address())
+}
+
+func (r *offLedgerMultiCallRequest) TargetAddress() iotago.Address {
+	return r.chainID.AsAddress()
+}
+
+func (r *offLedgerMultiCallRequest) FungibleTokens() *FungibleTokens {
+	return nil
+}
+
+func (r *offLedgerMultiCallRequest) NFT() *NFT {
+	return nil
+}
+
+func (r *offLedgerMultiCallRequest) GasBudget() (gasBudget uint64, isEVM bool) {
+	return r.gasBudget, false
+}
+
+func (r *offLedgerMultiCallRequest) String() string {
+	calls := make([]string, len(r.calls))
+	for i, c := range r.calls {
+		calls[i] = c.String()
+	}
+	return fmt.Sprintf("offLedgerMultiCallRequest::{ ID: %s, sender: %s, calls: [%s], nonce: %d }",
+		r.ID().String(),
+		r.SenderAccount().String(),
+		strings.Join(calls, ", "),
+		r.nonce,
+	)
+}
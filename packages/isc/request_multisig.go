@@ -0,0 +1,239 @@
+package isc
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+)
+
+// offLedgerMultisigRequest is an off-ledger request sent by a multisig account. It carries the policy of
+// the account, the VM checks it is the current one. The signatures are checked against the carried policy
+type offLedgerMultisigRequest struct {
+	chainID    *ChainID
+	contract   Hname
+	entryPoint Hname
+	params     dict.Dict
+	nonce      uint64
+	allowance  *Allowance
+	gasBudget  uint64
+	sender     *MultisigAgentID
+	policy     *MultisigPolicy
+	signatures []*MultisigSignature
+}
+
+var (
+	_ UnsignedMultisigRequest = &offLedgerMultisigRequest{}
+	_ MultisigRequest         = &offLedgerMultisigRequest{}
+)
+
+func NewOffLedgerMultisigRequest(
+	chainID *ChainID,
+	sender *MultisigAgentID,
+	policy *MultisigPolicy,
+	contract, entryPoint Hname,
+	params dict.Dict,
+	nonce uint64,
+) UnsignedMultisigRequest {
+	return &offLedgerMultisigRequest{
+		chainID:    chainID,
+		contract:   contract,
+		entryPoint: entryPoint,
+		params:     params,
+		nonce:      nonce,
+		allowance:  NewEmptyAllowance(),
+		gasBudget:  gas.MaxGasPerCall,
+		sender:     sender,
+		policy:     policy,
+	}
+}
+
+func (r *offLedgerMultisigRequest) WithNonce(nonce uint64) UnsignedMultisigRequest {
+	r.nonce = nonce
+	return r
+}
+
+func (r *offLedgerMultisigRequest) WithGasBudget(gasBudget uint64) UnsignedMultisigRequest {
+	r.gasBudget = gasBudget
+	return r
+}
+
+func (r *offLedgerMultisigRequest) WithAllowance(allowance *Allowance) UnsignedMultisigRequest {
+	r.allowance = allowance.Clone()
+	return r
+}
+
+// Sign adds the signatures of the keys to the request. The keys must be signers of the policy
+func (r *offLedgerMultisigRequest) Sign(keys ...*cryptolib.KeyPair) (MultisigRequest, error) {
+	// all the keys are checked first, so that the request is not changed on error
+	indexes := make([]int, len(keys))
+	for j, key := range keys {
+		if indexes[j] = r.policy.SignerIndex(key.GetPublicKey()); indexes[j] < 0 {
+			return nil, fmt.Errorf("%s is not a signer of the multisig account", key.GetPublicKey())
+		}
+	}
+	essence := r.essenceBytes()
+	for j, key := range keys {
+		r.signatures = append(r.signatures, &MultisigSignature{
+			SignerIndex: uint8(indexes[j]),
+			Signature:   key.GetPrivateKey().Sign(essence),
+		})
+	}
+	return r, nil
+}
+
+func (r *offLedgerMultisigRequest) essenceBytes() []byte {
+	mu := marshalutil.New()
+	r.writeEssenceToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+func (r *offLedgerMultisigRequest) writeEssenceToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.WriteByte(requestKindTagOffLedgerMultisig).
+		Write(r.chainID).
+		Write(r.contract).
+		Write(r.entryPoint).
+		Write(r.params).
+		WriteUint64(r.nonce).
+		WriteUint64(r.gasBudget).
+		Write(r.sender.id)
+	r.policy.WriteToMarshalUtil(mu)
+	r.allowance.WriteToMarshalUtil(mu)
+}
+
+func (r *offLedgerMultisigRequest) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	r.writeEssenceToMarshalUtil(mu)
+	mu.WriteUint8(uint8(len(r.signatures)))
+	for _, sig := range r.signatures {
+		sig.writeToMarshalUtil(mu)
+	}
+}
+
+func (r *offLedgerMultisigRequest) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if r.chainID, err = ChainIDFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if err = r.contract.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if err = r.entryPoint.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.params, err = dict.FromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.nonce, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	if r.gasBudget, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	sender, err := multisigAgentIDFromMarshalUtil(mu)
+	if err != nil {
+		return err
+	}
+	r.sender = sender.(*MultisigAgentID)
+	if r.policy, err = MultisigPolicyFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.allowance, err = AllowanceFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	n, err := mu.ReadUint8()
+	if err != nil {
+		return err
+	}
+	r.signatures = make([]*MultisigSignature, n)
+	for i := range r.signatures {
+		r.signatures[i] = &MultisigSignature{}
+		if err := r.signatures[i].readFromMarshalUtil(mu); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *offLedgerMultisigRequest) Bytes() []byte {
+	mu := marshalutil.New()
+	r.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+// VerifySignature checks if the request is signed by the threshold of the signers of the carried policy.
+// Whether the policy is the current one of the account is checked by the VM
+func (r *offLedgerMultisigRequest) VerifySignature() error {
+	return r.policy.VerifySignatures(r.essenceBytes(), r.signatures)
+}
+
+func (r *offLedgerMultisigRequest) MultisigPolicy() *MultisigPolicy {
+	return r.policy
+}
+
+func (r *offLedgerMultisigRequest) IsOffLedger() bool {
+	return true
+}
+
+func (r *offLedgerMultisigRequest) ChainID() *ChainID {
+	return r.chainID
+}
+
+// Nonce incremental nonce used for replay protection
+func (r *offLedgerMultisigRequest) Nonce() uint64 {
+	return r.nonce
+}
+
+// ID returns request id for this request. The signatures are not part of the id, so the request
+// can't be processed twice with a different set of signatures
+func (r *offLedgerMultisigRequest) ID() RequestID {
+	return NewRequestID(iotago.TransactionID(hashing.HashData(r.essenceBytes())), 0)
+}
+
+func (r *offLedgerMultisigRequest) CallTarget() CallTarget {
+	return NewCallTarget(r.contract, r.entryPoint)
+}
+
+func (r *offLedgerMultisigRequest) Params() dict.Dict {
+	return r.params
+}
+
+func (r *offLedgerMultisigRequest) Allowance() *Allowance {
+	return r.allowance
+}
+
+func (r *offLedgerMultisigRequest) SenderAccount() AgentID {
+	return r.sender
+}
+
+func (r *offLedgerMultisigRequest) TargetAddress() iotago.Address {
+	return r.chainID.AsAddress()
+}
+
+func (r *offLedgerMultisigRequest) FungibleTokens() *FungibleTokens {
+	return nil
+}
+
+func (r *offLedgerMultisigRequest) NFT() *NFT {
+	return nil
+}
+
+func (r *offLedgerMultisigRequest) GasBudget() (gasBudget uint64, isEVM bool) {
+	return r.gasBudget, false
+}
+
+func (r *offLedgerMultisigRequest) String() string {
+	return fmt.Sprintf("offLedgerMultisigRequest::{ ID: %s, sender: %s, policy: %s, target: %s, entrypoint: %s, Params: %s, nonce: %d, signatures: %d }",
+		r.ID().String(),
+		r.sender.String(),
+		r.policy.String(),
+		r.contract.String(),
+		r.entryPoint.String(),
+		r.params.String(),
+		r.nonce,
+		len(r.signatures),
+	)
+}
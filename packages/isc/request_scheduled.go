@@ -0,0 +1,169 @@
+package isc
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+)
+
+// scheduledCallRequest is a call, registered in the 'scheduler' core contract.
+// It is not posted by anyone, the VM creates it from the scheduler state when
+// the call becomes due and runs it at the start of the block.
+type scheduledCallRequest struct {
+	chainID    *ChainID
+	callID     uint64
+	sender     AgentID
+	contract   Hname
+	entryPoint Hname
+	params     dict.Dict
+	gasBudget  uint64
+	prepaid    *FungibleTokens
+}
+
+var _ ScheduledCallRequest = &scheduledCallRequest{}
+
+func NewScheduledCallRequest(
+	chainID *ChainID,
+	callID uint64,
+	sender AgentID,
+	target CallTarget,
+	params dict.Dict,
+	gasBudget uint64,
+	prepaid *FungibleTokens,
+) ScheduledCallRequest {
+	if params == nil {
+		params = dict.New()
+	}
+	if prepaid == nil {
+		prepaid = NewEmptyAssets()
+	}
+	return &scheduledCallRequest{
+		chainID:    chainID,
+		callID:     callID,
+		sender:     sender,
+		contract:   target.Contract,
+		entryPoint: target.EntryPoint,
+		params:     params,
+		gasBudget:  gasBudget,
+		prepaid:    prepaid,
+	}
+}
+
+func (r *scheduledCallRequest) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if r.chainID, err = ChainIDFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.callID, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	if r.sender, err = AgentIDFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if err := r.contract.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if err := r.entryPoint.ReadFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.params, err = dict.FromMarshalUtil(mu); err != nil {
+		return err
+	}
+	if r.gasBudget, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	if r.prepaid, err = FungibleTokensFromMarshalUtil(mu); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *scheduledCallRequest) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.
+		WriteByte(requestKindTagScheduledCall).
+		Write(r.chainID).
+		WriteUint64(r.callID).
+		Write(r.sender).
+		Write(r.contract).
+		Write(r.entryPoint).
+		Write(r.params).
+		WriteUint64(r.gasBudget)
+	r.prepaid.WriteToMarshalUtil(mu)
+}
+
+// CallID is the ID of the call in the scheduler.
+func (r *scheduledCallRequest) CallID() uint64 {
+	return r.callID
+}
+
+func (r *scheduledCallRequest) Allowance() *Allowance {
+	return NewEmptyAllowance()
+}
+
+func (r *scheduledCallRequest) CallTarget() CallTarget {
+	return CallTarget{
+		Contract:   r.contract,
+		EntryPoint: r.entryPoint,
+	}
+}
+
+func (r *scheduledCallRequest) Params() dict.Dict {
+	return r.params
+}
+
+// FungibleTokens are the tokens, prepaid for the call when it was scheduled.
+// The VM moves them from the scheduler to the sender's account before the call.
+func (r *scheduledCallRequest) FungibleTokens() *FungibleTokens {
+	return r.prepaid
+}
+
+func (r *scheduledCallRequest) GasBudget() (gas uint64, isEVM bool) {
+	return r.gasBudget, false
+}
+
+// ID is derived from the chain and the call ID, so it is unique and known in advance.
+func (r *scheduledCallRequest) ID() RequestID {
+	mu := marshalutil.New().
+		WriteByte(requestKindTagScheduledCall).
+		Write(r.chainID).
+		WriteUint64(r.callID)
+	return NewRequestID(iotago.TransactionID(hashing.HashData(mu.Bytes())), 0)
+}
+
+func (r *scheduledCallRequest) NFT() *NFT {
+	return nil
+}
+
+// SenderAccount is the agent, which has scheduled the call.
+func (r *scheduledCallRequest) SenderAccount() AgentID {
+	return r.sender
+}
+
+func (r *scheduledCallRequest) TargetAddress() iotago.Address {
+	return r.chainID.AsAddress()
+}
+
+func (r *scheduledCallRequest) Bytes() []byte {
+	mu := marshalutil.New()
+	r.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+func (r *scheduledCallRequest) IsOffLedger() bool {
+	return false
+}
+
+func (r *scheduledCallRequest) String() string {
+	return fmt.Sprintf("scheduledCallRequest::{ ID: %s, callID: %d, sender: %s, target: %s, entrypoint: %s, Params: %s, gasBudget: %d }",
+		r.ID().String(),
+		r.callID,
+		r.sender.String(),
+		r.contract.String(),
+		r.entryPoint.String(),
+		r.params.String(),
+		r.gasBudget,
+	)
+}
@@ -31,6 +31,115 @@ func TestSerializeRequestData(t *testing.T) {
 		require.True(t, bytes.Equal(serialized, serialized2))
 	})
 
+	t.Run("off ledger sponsored", func(t *testing.T) {
+		unsponsored := NewOffLedgerRequest(RandomChainID(), 3, 14, dict.New(), 1337).WithGasBudget(100).Sign(cryptolib.NewKeyPair())
+		idUnsponsored := unsponsored.ID()
+		sponsorKey := cryptolib.NewKeyPair()
+		req = unsponsored.(SponsoredOffLedgerRequest).WithSponsor(sponsorKey, 5000)
+		require.EqualValues(t, idUnsponsored, req.ID())
+
+		serialized := req.Bytes()
+		req2, err := NewRequestFromMarshalUtil(marshalutil.New(serialized))
+		require.NoError(t, err)
+
+		reqBack := req2.(SponsoredOffLedgerRequest)
+		require.NoError(t, reqBack.VerifySignature())
+		require.EqualValues(t, req.ID(), reqBack.ID())
+		sponsor, maxFee := reqBack.Sponsor()
+		require.True(t, sponsor.Equals(NewAgentID(sponsorKey.Address())))
+		require.EqualValues(t, 5000, maxFee)
+		require.True(t, bytes.Equal(serialized, req2.Bytes()))
+
+		// the sponsor signed the max fee
+		reqBack.(*offLedgerRequestData).sponsor.maxFee = 6000
+		require.Error(t, reqBack.VerifySignature())
+	})
+
+	t.Run("off ledger multi-call", func(t *testing.T) {
+		calls := []*Call{
+			NewCall(3, 14, dict.Dict{"a": []byte{1}}, NewAllowanceBaseTokens(100)),
+			NewCall(4, 15, nil, nil),
+			NewCall(5, 16, nil, NewAllowanceBaseTokens(200)),
+		}
+		unsigned, err := NewOffLedgerMultiCallRequest(RandomChainID(), calls, 1337)
+		require.NoError(t, err)
+		req := unsigned.WithGasBudget(100).Sign(cryptolib.NewKeyPair())
+		require.EqualValues(t, 300, req.Allowance().Assets.BaseTokens)
+
+		serialized := req.Bytes()
+		req2, err := NewRequestFromMarshalUtil(marshalutil.New(serialized))
+		require.NoError(t, err)
+
+		reqBack := req2.(MultiCallRequest)
+		require.NoError(t, reqBack.VerifySignature())
+		require.EqualValues(t, req.ID(), reqBack.ID())
+		require.Len(t, reqBack.Calls(), 3)
+		require.EqualValues(t, 15, reqBack.Calls()[1].Target.EntryPoint)
+		require.True(t, bytes.Equal(serialized, req2.Bytes()))
+
+		// the calls are signed
+		reqBack.Calls()[2].Target.EntryPoint = 17
+		require.Error(t, reqBack.VerifySignature())
+	})
+
+	t.Run("off ledger multi-call number of calls", func(t *testing.T) {
+		_, err := NewOffLedgerMultiCallRequest(RandomChainID(), nil, 1337)
+		require.Error(t, err)
+		calls := make([]*Call, MaxCallsInMultiCallRequest+1)
+		for i := range calls {
+			calls[i] = NewCall(3, 14, nil, nil)
+		}
+		_, err = NewOffLedgerMultiCallRequest(RandomChainID(), calls, 1337)
+		require.Error(t, err)
+		_, err = NewOffLedgerMultiCallRequest(RandomChainID(), calls[:MaxCallsInMultiCallRequest], 1337)
+		require.NoError(t, err)
+
+		// the decoder rejects the requests built around the constructor
+		for _, calls := range [][]*Call{{}, calls} {
+			req := &offLedgerMultiCallRequest{chainID: RandomChainID(), calls: calls}
+			_, err = NewRequestFromBytes(req.Sign(cryptolib.NewKeyPair()).Bytes())
+			require.ErrorContains(t, err, "wrong number of calls")
+		}
+	})
+
+	t.Run("off ledger multisig", func(t *testing.T) {
+		k1, k2, k3 := cryptolib.NewKeyPair(), cryptolib.NewKeyPair(), cryptolib.NewKeyPair()
+		policy, err := NewMultisigPolicy(2, k1.GetPublicKey(), k2.GetPublicKey(), k3.GetPublicKey())
+		require.NoError(t, err)
+		unsigned := NewOffLedgerMultisigRequest(RandomChainID(), NewMultisigAgentID(policy), policy, 3, 14, dict.New(), 1337).
+			WithGasBudget(100)
+
+		// signed by the signers one by one
+		req, err := unsigned.Sign(k1)
+		require.NoError(t, err)
+		require.Error(t, req.VerifySignature())
+		req2, err := NewRequestFromMarshalUtil(marshalutil.New(req.Bytes()))
+		require.NoError(t, err)
+		req, err = req2.(MultisigRequest).Sign(k3)
+		require.NoError(t, err)
+		require.NoError(t, req.VerifySignature())
+		require.EqualValues(t, req2.ID(), req.ID())
+
+		serialized := req.Bytes()
+		req2, err = NewRequestFromMarshalUtil(marshalutil.New(serialized))
+		require.NoError(t, err)
+		reqBack := req2.(MultisigRequest)
+		require.NoError(t, reqBack.VerifySignature())
+		require.True(t, policy.Equals(reqBack.MultisigPolicy()))
+		require.True(t, NewMultisigAgentID(policy).Equals(reqBack.SenderAccount()))
+		require.True(t, bytes.Equal(serialized, reqBack.Bytes()))
+
+		// not a signer, the request is left unchanged
+		_, err = reqBack.Sign(k2, cryptolib.NewKeyPair())
+		require.ErrorContains(t, err, "not a signer")
+		require.True(t, bytes.Equal(serialized, reqBack.Bytes()))
+
+		_, err = NewMultisigPolicy(4, k1.GetPublicKey(), k2.GetPublicKey(), k3.GetPublicKey())
+		require.Error(t, err)
+		_, err = NewMultisigPolicy(1, k1.GetPublicKey(), k1.GetPublicKey())
+		require.Error(t, err)
+	})
+
 	t.Run("on ledger", func(t *testing.T) {
 		sender := tpkg.RandAliasAddress()
 		requestMetadata := &RequestMetadata{
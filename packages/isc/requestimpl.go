@@ -23,6 +23,10 @@ const (
 	requestKindTagOffLedgerISC
 	requestKindTagOffLedgerEVM
 	requestKindTagOffLedgerEVMEstimateGas
+	requestKindTagScheduledCall
+	requestKindTagOffLedgerISCSponsored
+	requestKindTagOffLedgerMultiCall
+	requestKindTagOffLedgerMultisig
 )
 
 func NewRequestFromBytes(data []byte) (Request, error) {
@@ -44,6 +48,14 @@ func NewRequestFromMarshalUtil(mu *marshalutil.MarshalUtil) (Request, error) {
 		r = &evmOffLedgerRequest{}
 	case requestKindTagOffLedgerEVMEstimateGas:
 		r = &evmOffLedgerEstimateGasRequest{}
+	case requestKindTagScheduledCall:
+		r = &scheduledCallRequest{}
+	case requestKindTagOffLedgerISCSponsored:
+		r = &offLedgerRequestData{sponsor: &offLedgerSponsor{}}
+	case requestKindTagOffLedgerMultiCall:
+		r = &offLedgerMultiCallRequest{}
+	case requestKindTagOffLedgerMultisig:
+		r = &offLedgerMultisigRequest{}
 	default:
 		panic(fmt.Sprintf("no handler for request kind %d", kind))
 	}
@@ -64,6 +76,7 @@ type offLedgerRequestData struct {
 	nonce           uint64
 	allowance       *Allowance
 	gasBudget       uint64
+	sponsor         *offLedgerSponsor // nil if not sponsored
 }
 
 type offLedgerSignatureScheme struct {
@@ -104,6 +117,31 @@ func (s *offLedgerSignatureScheme) readSignature(mu *marshalutil.MarshalUtil) er
 	return err
 }
 
+// offLedgerSponsor is the countersignature of the request by the account, which pays the gas fee
+// of the request instead of the sender, up to maxFee tokens
+type offLedgerSponsor struct {
+	maxFee          uint64
+	signatureScheme *offLedgerSignatureScheme
+}
+
+func (s *offLedgerSponsor) write(mu *marshalutil.MarshalUtil) {
+	mu.WriteUint64(s.maxFee)
+	s.signatureScheme.writeEssence(mu)
+	s.signatureScheme.writeSignature(mu)
+}
+
+func (s *offLedgerSponsor) read(mu *marshalutil.MarshalUtil) error {
+	var err error
+	if s.maxFee, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	s.signatureScheme = &offLedgerSignatureScheme{}
+	if err := s.signatureScheme.readEssence(mu); err != nil {
+		return err
+	}
+	return s.signatureScheme.readSignature(mu)
+}
+
 func NewOffLedgerRequest(chainID *ChainID, contract, entryPoint Hname, params dict.Dict, nonce uint64) UnsignedOffLedgerRequest {
 	return &offLedgerRequestData{
 		chainID:    chainID,
@@ -124,8 +162,9 @@ func (r *offLedgerRequestData) IsOffLedger() bool {
 }
 
 var (
-	_ UnsignedOffLedgerRequest = &offLedgerRequestData{}
-	_ OffLedgerRequest         = &offLedgerRequestData{}
+	_ UnsignedOffLedgerRequest  = &offLedgerRequestData{}
+	_ OffLedgerRequest          = &offLedgerRequestData{}
+	_ SponsoredOffLedgerRequest = &offLedgerRequestData{}
 )
 
 func (r *offLedgerRequestData) ChainID() *ChainID {
@@ -156,9 +195,18 @@ func (r *offLedgerRequestData) Bytes() []byte {
 	return mu.Bytes()
 }
 
+// WriteToMarshalUtil writes the request. The sponsored request is written with its own kind tag,
+// so the unsponsored requests are written the same way as before sponsoring was introduced
 func (r *offLedgerRequestData) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
-	r.writeEssenceToMarshalUtil(mu)
+	if r.sponsor == nil {
+		r.writeEssenceToMarshalUtil(mu)
+		r.signatureScheme.writeSignature(mu)
+		return
+	}
+	mu.WriteByte(requestKindTagOffLedgerISCSponsored)
+	r.writeEssenceDataToMarshalUtil(mu)
 	r.signatureScheme.writeSignature(mu)
+	r.sponsor.write(mu)
 }
 
 func (r *offLedgerRequestData) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
@@ -168,9 +216,22 @@ func (r *offLedgerRequestData) readFromMarshalUtil(mu *marshalutil.MarshalUtil)
 	if err := r.signatureScheme.readSignature(mu); err != nil {
 		return err
 	}
+	if r.sponsor != nil {
+		if err := r.sponsor.read(mu); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// unsponsoredBytes returns the request as signed by the sender, without the sponsor
+func (r *offLedgerRequestData) unsponsoredBytes() []byte {
+	mu := marshalutil.New()
+	r.writeEssenceToMarshalUtil(mu)
+	r.signatureScheme.writeSignature(mu)
+	return mu.Bytes()
+}
+
 func (r *offLedgerRequestData) essenceBytes() []byte {
 	mu := marshalutil.New()
 	r.writeEssenceToMarshalUtil(mu)
@@ -178,8 +239,12 @@ func (r *offLedgerRequestData) essenceBytes() []byte {
 }
 
 func (r *offLedgerRequestData) writeEssenceToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.WriteByte(requestKindTagOffLedgerISC)
+	r.writeEssenceDataToMarshalUtil(mu)
+}
+
+func (r *offLedgerRequestData) writeEssenceDataToMarshalUtil(mu *marshalutil.MarshalUtil) {
 	mu.
-		WriteByte(requestKindTagOffLedgerISC).
 		Write(r.chainID).
 		Write(r.contract).
 		Write(r.entryPoint).
@@ -231,6 +296,37 @@ func (r *offLedgerRequestData) Sign(key *cryptolib.KeyPair) OffLedgerRequest {
 	return r
 }
 
+// sponsorEssenceBytes returns the data signed by the sponsor: the essence signed by the sender,
+// the maximum fee and the public key of the sponsor
+func (r *offLedgerRequestData) sponsorEssenceBytes() []byte {
+	mu := marshalutil.New()
+	r.writeEssenceToMarshalUtil(mu)
+	mu.WriteUint64(r.sponsor.maxFee)
+	r.sponsor.signatureScheme.writeEssence(mu)
+	return mu.Bytes()
+}
+
+// WithSponsor countersigns the request, already signed by the sender. The sponsor pays the gas fee
+// of the request, up to maxFee tokens
+func (r *offLedgerRequestData) WithSponsor(key *cryptolib.KeyPair, maxFee uint64) SponsoredOffLedgerRequest {
+	r.sponsor = &offLedgerSponsor{
+		maxFee: maxFee,
+		signatureScheme: &offLedgerSignatureScheme{
+			publicKey: key.GetPublicKey(),
+		},
+	}
+	r.sponsor.signatureScheme.signature = key.GetPrivateKey().Sign(r.sponsorEssenceBytes())
+	return r
+}
+
+// Sponsor returns the account of the sponsor and the maximum fee it pays. Nil if the request is not sponsored
+func (r *offLedgerRequestData) Sponsor() (AgentID, uint64) {
+	if r.sponsor == nil {
+		return nil, 0
+	}
+	return NewAgentID(r.sponsor.signatureScheme.publicKey.AsEd25519Address()), r.sponsor.maxFee
+}
+
 // FungibleTokens is attached assets to the UTXO. Nil for off-ledger
 func (r *offLedgerRequestData) FungibleTokens() *FungibleTokens {
 	return nil
@@ -255,20 +351,24 @@ func (r *offLedgerRequestData) WithAllowance(allowance *Allowance) UnsignedOffLe
 	return r
 }
 
-// VerifySignature verifies essence signature
+// VerifySignature verifies essence signature and the signature of the sponsor, if any
 func (r *offLedgerRequestData) VerifySignature() error {
 	if !r.signatureScheme.publicKey.Verify(r.essenceBytes(), r.signatureScheme.signature) {
 		return fmt.Errorf("invalid signature")
 	}
+	if r.sponsor != nil && !r.sponsor.signatureScheme.publicKey.Verify(r.sponsorEssenceBytes(), r.sponsor.signatureScheme.signature) {
+		return fmt.Errorf("invalid signature of the sponsor")
+	}
 	return nil
 }
 
 // ID returns request id for this request
 // index part of request id is always 0 for off ledger requests
 // note that request needs to have been signed before this value is
-// considered valid
+// considered valid. The sponsor is not part of the id, so the request can't be
+// processed twice by replacing or removing the sponsor
 func (r *offLedgerRequestData) ID() (requestID RequestID) {
-	return NewRequestID(iotago.TransactionID(hashing.HashData(r.Bytes())), 0)
+	return NewRequestID(iotago.TransactionID(hashing.HashData(r.unsponsoredBytes())), 0)
 }
 
 // Nonce incremental nonce used for replay protection
@@ -310,13 +410,18 @@ func (r *offLedgerRequestData) GasBudget() (gasBudget uint64, isEVM bool) {
 }
 
 func (r *offLedgerRequestData) String() string {
-	return fmt.Sprintf("offLedgerRequestData::{ ID: %s, sender: %s, target: %s, entrypoint: %s, Params: %s, nonce: %d }",
+	sponsor := "none"
+	if agentID, maxFee := r.Sponsor(); agentID != nil {
+		sponsor = fmt.Sprintf("%s (max fee %d)", agentID.String(), maxFee)
+	}
+	return fmt.Sprintf("offLedgerRequestData::{ ID: %s, sender: %s, target: %s, entrypoint: %s, Params: %s, nonce: %d, sponsor: %s }",
 		r.ID().String(),
 		r.SenderAccount().String(),
 		r.contract.String(),
 		r.entryPoint.String(),
 		r.Params().String(),
 		r.nonce,
+		sponsor,
 	)
 }
 
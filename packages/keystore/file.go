@@ -0,0 +1,218 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	fileKeyStoreVersion = 1
+	fileKeyStoreKDF     = "scrypt"
+	scryptN             = 1 << 15
+	scryptR             = 8
+	scryptP             = 1
+	scryptKeyLen        = 32 // AES-256.
+	scryptSaltLen       = 32
+)
+
+// fileKeyStoreJSON is the on-disk representation of the file key store.
+// Only the KDF parameters are kept in plaintext.
+type fileKeyStoreJSON struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type fileKeyStore struct {
+	filename string
+	salt     []byte
+	aead     cipher.AEAD
+	secrets  map[string][]byte // Decrypted secrets, indexed by the hex-encoded keys.
+	lock     *sync.RWMutex
+}
+
+var _ KeyStore = &fileKeyStore{}
+
+// NewFileKeyStore opens a passphrase protected key store file, or creates a new one,
+// if the file does not exist yet. The file content is decrypted in memory only,
+// each update re-encrypts the whole file and replaces it atomically.
+func NewFileKeyStore(filename string, passphrase []byte) (KeyStore, error) {
+	if filename == "" {
+		return nil, xerrors.New("key store file name is not specified")
+	}
+	if len(passphrase) == 0 {
+		return nil, xerrors.New("key store passphrase is empty")
+	}
+	ks := &fileKeyStore{
+		filename: filename,
+		secrets:  make(map[string][]byte),
+		lock:     &sync.RWMutex{},
+	}
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		ks.salt = make([]byte, scryptSaltLen)
+		if _, err = rand.Read(ks.salt); err != nil {
+			return nil, err
+		}
+		if ks.aead, err = newFileKeyStoreAEAD(passphrase, ks.salt, scryptN, scryptR, scryptP); err != nil {
+			return nil, err
+		}
+		if err = ks.write(); err != nil {
+			return nil, err
+		}
+		return ks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = ks.read(data, passphrase); err != nil {
+		return nil, xerrors.Errorf("cannot open the key store %v: %w", filename, err)
+	}
+	return ks, nil
+}
+
+func newFileKeyStoreAEAD(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (ks *fileKeyStore) read(data, passphrase []byte) error {
+	var err error
+	var fileJSON fileKeyStoreJSON
+	if err = json.Unmarshal(data, &fileJSON); err != nil {
+		return err
+	}
+	if fileJSON.Version != fileKeyStoreVersion || fileJSON.KDF != fileKeyStoreKDF {
+		return xerrors.Errorf("unsupported key store format: version=%v, kdf=%v", fileJSON.Version, fileJSON.KDF)
+	}
+	ks.salt = fileJSON.Salt
+	if ks.aead, err = newFileKeyStoreAEAD(passphrase, fileJSON.Salt, fileJSON.N, fileJSON.R, fileJSON.P); err != nil {
+		return err
+	}
+	plaintext, err := ks.aead.Open(nil, fileJSON.Nonce, fileJSON.Ciphertext, nil)
+	if err != nil {
+		return xerrors.New("wrong passphrase or corrupted key store")
+	}
+	return json.Unmarshal(plaintext, &ks.secrets)
+}
+
+// write is called with the lock held or before the store is shared.
+func (ks *fileKeyStore) write() error {
+	plaintext, err := json.Marshal(ks.secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, ks.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.Marshal(&fileKeyStoreJSON{
+		Version:    fileKeyStoreVersion,
+		KDF:        fileKeyStoreKDF,
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       ks.salt,
+		Nonce:      nonce,
+		Ciphertext: ks.aead.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(ks.filename), filepath.Base(ks.filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name()) // Fails silently, if the file was renamed already.
+	if _, err = tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), ks.filename)
+}
+
+func (ks *fileKeyStore) Has(key []byte) (bool, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	_, ok := ks.secrets[hex.EncodeToString(key)]
+	return ok, nil
+}
+
+func (ks *fileKeyStore) Get(key []byte) ([]byte, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	value, ok := ks.secrets[hex.EncodeToString(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+func (ks *fileKeyStore) Set(key, value []byte) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	hexKey := hex.EncodeToString(key)
+	prev, hadPrev := ks.secrets[hexKey]
+	ks.secrets[hexKey] = append([]byte{}, value...)
+	if err := ks.write(); err != nil {
+		if hadPrev {
+			ks.secrets[hexKey] = prev
+		} else {
+			delete(ks.secrets, hexKey)
+		}
+		return err
+	}
+	return nil
+}
+
+func (ks *fileKeyStore) Delete(key []byte) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	hexKey := hex.EncodeToString(key)
+	prev, hadPrev := ks.secrets[hexKey]
+	if !hadPrev {
+		return nil
+	}
+	delete(ks.secrets, hexKey)
+	if err := ks.write(); err != nil {
+		ks.secrets[hexKey] = prev
+		return err
+	}
+	return nil
+}
+
+func (ks *fileKeyStore) Close() error {
+	return nil // All the updates are written immediately.
+}
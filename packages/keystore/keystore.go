@@ -0,0 +1,102 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package keystore provides a storage for the secret material of a node,
+// i.e. the node identity key and the distributed key shares. Several backends
+// are supported:
+//
+//   - db: the secrets are stored in plaintext in the registry database (legacy);
+//   - file: the secrets are kept in a file encrypted with a passphrase,
+//     they are only decrypted in memory.
+//
+// The key stores are used by the signer package, which can run in a separate
+// process to keep the secrets out of the node process.
+package keystore
+
+import (
+	"errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"golang.org/x/xerrors"
+)
+
+const (
+	TypeDB   = "db"
+	TypeFile = "file"
+)
+
+// PassphraseEnvVar is the environment variable to take the
+// passphrase of the file key store from, if it is not provided otherwise.
+const PassphraseEnvVar = "WASP_KEYSTORE_PASSPHRASE"
+
+var ErrKeyNotFound = errors.New("key not found in the key store")
+
+// KeyStore stands for a storage of secrets. The keys are opaque byte strings,
+// usually produced by the dbkeys package.
+type KeyStore interface {
+	Has(key []byte) (bool, error)
+	Get(key []byte) ([]byte, error) // Returns ErrKeyNotFound, if there is no such key.
+	Set(key, value []byte) error
+	Delete(key []byte) error
+	Close() error
+}
+
+// Config describes the key store to use.
+type Config struct {
+	Type       string // One of TypeDB, TypeFile.
+	File       string // For TypeFile.
+	Passphrase []byte // For TypeFile.
+}
+
+// New creates a key store according to the configuration.
+// The store is only used for the TypeDB backend.
+func New(config *Config, store kvstore.KVStore) (KeyStore, error) {
+	switch config.Type {
+	case TypeDB, "":
+		return NewDBKeyStore(store), nil
+	case TypeFile:
+		return NewFileKeyStore(config.File, config.Passphrase)
+	default:
+		return nil, xerrors.Errorf("unknown key store type: %v", config.Type)
+	}
+}
+
+// region dbKeyStore ///////////////////////////////////////////////////////////
+
+type dbKeyStore struct {
+	store kvstore.KVStore
+}
+
+var _ KeyStore = &dbKeyStore{}
+
+// NewDBKeyStore keeps the secrets in plaintext in the specified store.
+// It is kept for compatibility with the existing databases.
+func NewDBKeyStore(store kvstore.KVStore) KeyStore {
+	return &dbKeyStore{store: store}
+}
+
+func (ks *dbKeyStore) Has(key []byte) (bool, error) {
+	return ks.store.Has(key)
+}
+
+func (ks *dbKeyStore) Get(key []byte) ([]byte, error) {
+	value, err := ks.store.Get(key)
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	return value, err
+}
+
+func (ks *dbKeyStore) Set(key, value []byte) error {
+	return ks.store.Set(key, value)
+}
+
+func (ks *dbKeyStore) Delete(key []byte) error {
+	return ks.store.Delete(key)
+}
+
+func (ks *dbKeyStore) Close() error {
+	return nil // The store is owned by the caller.
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
@@ -0,0 +1,69 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyStore(t *testing.T, ks keystore.KeyStore) {
+	key := []byte{1, 2, 3}
+	secret := []byte("some secret value")
+
+	has, err := ks.Has(key)
+	require.NoError(t, err)
+	require.False(t, has)
+	_, err = ks.Get(key)
+	require.ErrorIs(t, err, keystore.ErrKeyNotFound)
+
+	require.NoError(t, ks.Set(key, secret))
+	has, err = ks.Has(key)
+	require.NoError(t, err)
+	require.True(t, has)
+	value, err := ks.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, secret, value)
+
+	require.NoError(t, ks.Delete(key))
+	has, err = ks.Has(key)
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestDBKeyStore(t *testing.T) {
+	testKeyStore(t, keystore.NewDBKeyStore(mapdb.NewMapDB()))
+}
+
+func TestFileKeyStore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "keystore.json")
+	passphrase := []byte("correct horse battery staple")
+	key := []byte{7}
+	secret := []byte("the node identity")
+
+	ks, err := keystore.NewFileKeyStore(filename, passphrase)
+	require.NoError(t, err)
+	testKeyStore(t, ks)
+	require.NoError(t, ks.Set(key, secret))
+	require.NoError(t, ks.Close())
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(data, secret))
+
+	_, err = keystore.NewFileKeyStore(filename, []byte("wrong passphrase"))
+	require.Error(t, err)
+
+	ks, err = keystore.NewFileKeyStore(filename, passphrase)
+	require.NoError(t, err)
+	value, err := ks.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, secret, value)
+}
@@ -0,0 +1,77 @@
+package buffered
+
+import (
+	"github.com/iotaledger/wasp/packages/kv"
+)
+
+// AccessLog records the keys accessed through a BufferedKVStoreAccess: the keys read, the prefixes
+// iterated over and the keys written. It is used to detect conflicts between runs of the VM on
+// copies of the same state
+type AccessLog struct {
+	reads    map[kv.Key]struct{}
+	prefixes map[kv.Key]struct{}
+	writes   map[kv.Key]struct{}
+}
+
+func NewAccessLog() *AccessLog {
+	return &AccessLog{
+		reads:    make(map[kv.Key]struct{}),
+		prefixes: make(map[kv.Key]struct{}),
+		writes:   make(map[kv.Key]struct{}),
+	}
+}
+
+func (l *AccessLog) read(key kv.Key) {
+	if l != nil {
+		l.reads[key] = struct{}{}
+	}
+}
+
+func (l *AccessLog) iterate(prefix kv.Key) {
+	if l != nil {
+		l.prefixes[prefix] = struct{}{}
+	}
+}
+
+func (l *AccessLog) write(key kv.Key) {
+	if l != nil {
+		l.writes[key] = struct{}{}
+	}
+}
+
+// Writes returns the keys written, in no particular order
+func (l *AccessLog) Writes() []kv.Key {
+	ret := make([]kv.Key, 0, len(l.writes))
+	for k := range l.writes {
+		ret = append(ret, k)
+	}
+	return ret
+}
+
+// ReadsAnyWrittenBy returns true if any of the keys read or iterated over according to the log
+// was written according to the other log. The keys in 'except' are not taken into account
+func (l *AccessLog) ReadsAnyWrittenBy(other *AccessLog, except ...kv.Key) bool {
+	for k := range other.writes {
+		if isExcepted(k, except) {
+			continue
+		}
+		if _, ok := l.reads[k]; ok {
+			return true
+		}
+		for prefix := range l.prefixes {
+			if k.HasPrefix(prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isExcepted(k kv.Key, except []kv.Key) bool {
+	for _, e := range except {
+		if k == e {
+			return true
+		}
+	}
+	return false
+}
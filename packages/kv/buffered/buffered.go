@@ -15,6 +15,7 @@ import (
 type BufferedKVStoreAccess struct {
 	r    kv.KVStoreReader
 	muts *Mutations
+	log  *AccessLog
 }
 
 func NewBufferedKVStoreAccess(r kv.KVStoreReader) *BufferedKVStoreAccess {
@@ -39,6 +40,12 @@ func (b *BufferedKVStoreAccess) ClearMutations() {
 	b.muts = NewMutations()
 }
 
+// SetAccessLog starts recording the accessed keys to the log. With nil, the recording is stopped.
+// The log is not inherited by copies
+func (b *BufferedKVStoreAccess) SetAccessLog(log *AccessLog) {
+	b.log = log
+}
+
 // DangerouslyDumpToDict returns a Dict with the whole contents of the
 // backing store + applied mutations.
 func (b *BufferedKVStoreAccess) DangerouslyDumpToDict() dict.Dict {
@@ -83,14 +90,17 @@ func (b *BufferedKVStoreAccess) flag(k kv.Key) string {
 }
 
 func (b *BufferedKVStoreAccess) Set(key kv.Key, value []byte) {
+	b.log.write(key)
 	b.muts.Set(key, value)
 }
 
 func (b *BufferedKVStoreAccess) Del(key kv.Key) {
+	b.log.write(key)
 	b.muts.Del(key)
 }
 
 func (b *BufferedKVStoreAccess) Get(key kv.Key) ([]byte, error) {
+	b.log.read(key)
 	v, ok := b.muts.Get(key)
 	if ok {
 		return v, nil
@@ -103,6 +113,7 @@ func (b *BufferedKVStoreAccess) MustGet(key kv.Key) []byte {
 }
 
 func (b *BufferedKVStoreAccess) Has(key kv.Key) (bool, error) {
+	b.log.read(key)
 	v, ok := b.muts.Get(key)
 	if ok {
 		return v != nil, nil
@@ -135,6 +146,7 @@ func (b *BufferedKVStoreAccess) MustIterate(prefix kv.Key, f func(key kv.Key, va
 }
 
 func (b *BufferedKVStoreAccess) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
+	b.log.iterate(prefix)
 	for k := range b.muts.Sets {
 		if !k.HasPrefix(prefix) {
 			continue
@@ -172,16 +184,21 @@ func (b *BufferedKVStoreAccess) MustIterateSorted(prefix kv.Key, f func(key kv.K
 }
 
 func (b *BufferedKVStoreAccess) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) bool) error {
+	return b.IterateKeysSortedFrom(prefix, "", f)
+}
+
+func (b *BufferedKVStoreAccess) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
+	b.log.iterate(prefix)
 	var keys []kv.Key
 
 	for k := range b.muts.Sets {
-		if !k.HasPrefix(prefix) {
+		if !k.HasPrefix(prefix) || k < from {
 			continue
 		}
 		keys = append(keys, k)
 	}
 
-	err := b.r.IterateKeysSorted(prefix, func(k kv.Key) bool {
+	err := b.r.IterateKeysSortedFrom(prefix, from, func(k kv.Key) bool {
 		if !b.muts.Contains(k) {
 			keys = append(keys, k)
 		}
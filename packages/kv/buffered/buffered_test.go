@@ -106,4 +106,39 @@ func TestIterateSorted(t *testing.T) {
 		return true
 	})
 	require.Equal(t, []kv.Key{"234", "245", "247", "248", "250", "259"}, seen)
+
+	seen = nil
+	kv.MustIterateKeysSortedFrom(b, "2", "246", func(k kv.Key) bool {
+		seen = append(seen, k)
+		return true
+	})
+	require.Equal(t, []kv.Key{"247", "248", "250", "259"}, seen)
+}
+
+func TestAccessLog(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("ab"), []byte("v1"))
+	_ = db.Set([]byte("cd"), []byte("v2"))
+
+	b1 := NewBufferedKVStoreAccess(kv.NewHiveKVStoreReader(db))
+	log1 := NewAccessLog()
+	b1.SetAccessLog(log1)
+	b1.MustGet("ab")
+	b1.MustIterateKeys("x", func(kv.Key) bool { return true })
+	b1.Set("ef", []byte("v3"))
+
+	b2 := b1.Copy()
+	log2 := NewAccessLog()
+	b2.SetAccessLog(log2)
+	b2.Set("cd", []byte("v4"))
+	b2.Del("xy")
+
+	require.ElementsMatch(t, []kv.Key{"cd", "xy"}, log2.Writes())
+	// "xy" is under the iterated prefix
+	require.True(t, log1.ReadsAnyWrittenBy(log2))
+	require.False(t, log1.ReadsAnyWrittenBy(log2, "xy"))
+	require.False(t, log2.ReadsAnyWrittenBy(log1))
+
+	b2.MustGet("ef")
+	require.True(t, log2.ReadsAnyWrittenBy(log1))
 }
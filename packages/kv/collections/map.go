@@ -1,6 +1,7 @@
 package collections
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/iotaledger/wasp/packages/kv"
@@ -213,3 +214,67 @@ func (m *ImmutableMap) MustIterateKeys(f func(elemKey []byte) bool) {
 		panic(err)
 	}
 }
+
+// IterateSorted iterates the elements in the order of their keys
+func (m *ImmutableMap) IterateSorted(f func(elemKey []byte, value []byte) bool) error {
+	prefix := kv.Key(MapElemKey(m.name, nil))
+	return m.kvr.IterateSorted(prefix, func(key kv.Key, value []byte) bool {
+		return f([]byte(key)[len(prefix):], value)
+	})
+}
+
+// IterateKeysSorted iterates the keys of the elements in their order
+func (m *ImmutableMap) IterateKeysSorted(f func(elemKey []byte) bool) error {
+	prefix := kv.Key(MapElemKey(m.name, nil))
+	return m.kvr.IterateKeysSorted(prefix, func(key kv.Key) bool {
+		return f([]byte(key)[len(prefix):])
+	})
+}
+
+func (m *ImmutableMap) MustIterateSorted(f func(elemKey []byte, value []byte) bool) {
+	err := m.IterateSorted(f)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (m *ImmutableMap) MustIterateKeysSorted(f func(elemKey []byte) bool) {
+	err := m.IterateKeysSorted(f)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// MustIteratePage calls f with at most limit elements in the order of their keys, starting after the cursor,
+// or with the first element if the cursor is nil. The elements for which filter returns false are skipped,
+// a nil filter accepts all elements. Only the values of the elements of the page are read, but the key
+// stores have no seek: the keys of the whole map are read and sorted for each page, so a page costs O(N log N)
+// in the size of the map, and reading the map page by page costs O(N^2/limit).
+// Returns the cursor of the next page, nil if there are no more elements
+func (m *ImmutableMap) MustIteratePage(cursor []byte, limit uint32, filter func(elemKey []byte) bool, f func(elemKey []byte, value []byte)) []byte {
+	if limit == 0 {
+		panic("MustIteratePage: the limit must be positive")
+	}
+	var page [][]byte
+	var next []byte
+	prefix := kv.Key(MapElemKey(m.name, nil))
+	kv.MustIterateKeysSortedFrom(m.kvr, prefix, kv.Key(MapElemKey(m.name, cursor)), func(key kv.Key) bool {
+		elemKey := []byte(key)[len(prefix):]
+		if cursor != nil && bytes.Equal(elemKey, cursor) {
+			return true
+		}
+		if filter != nil && !filter(elemKey) {
+			return true
+		}
+		if uint32(len(page)) == limit {
+			next = page[len(page)-1]
+			return false
+		}
+		page = append(page, elemKey)
+		return true
+	})
+	for _, elemKey := range page {
+		f(elemKey, m.MustGetAt(elemKey))
+	}
+	return next
+}
@@ -140,3 +140,35 @@ func TestMapConcurrentAccess(t *testing.T) {
 	require.EqualValues(t, m1.MustLen(), 0)
 	require.EqualValues(t, m2.MustLen(), 0)
 }
+
+func TestMapIteratePage(t *testing.T) {
+	vars := dict.New()
+	m := NewMap(vars, "testMap")
+	for _, k := range []string{"k5", "k1", "k4", "x2", "k3", "k2"} {
+		m.MustSetAt([]byte(k), []byte("v"+k))
+	}
+	onlyK := func(k []byte) bool { return k[0] == 'k' }
+
+	var keys []string
+	var cursor []byte
+	pages := 0
+	for {
+		cursor = m.MustIteratePage(cursor, 2, onlyK, func(k []byte, v []byte) {
+			require.EqualValues(t, "v"+string(k), string(v))
+			keys = append(keys, string(k))
+		})
+		pages++
+		if cursor == nil {
+			break
+		}
+	}
+	require.Equal(t, []string{"k1", "k2", "k3", "k4", "k5"}, keys)
+	require.Equal(t, 3, pages)
+
+	keys = nil
+	cursor = m.MustIteratePage([]byte("k4"), 10, nil, func(k []byte, v []byte) {
+		keys = append(keys, string(k))
+	})
+	require.Nil(t, cursor)
+	require.Equal(t, []string{"k5", "x2"}, keys)
+}
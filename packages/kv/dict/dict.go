@@ -167,9 +167,17 @@ func (d Dict) IterateSorted(prefix kv.Key, f func(key kv.Key, value []byte) bool
 }
 
 func (d Dict) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) bool) error {
-	for _, k := range d.KeysSorted() {
+	return d.IterateKeysSortedFrom(prefix, "", f)
+}
+
+func (d Dict) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
+	if from < prefix {
+		from = prefix
+	}
+	keys := d.KeysSorted()
+	for _, k := range keys[sort.Search(len(keys), func(i int) bool { return keys[i] >= from }):] {
 		if !k.HasPrefix(prefix) {
-			continue
+			break
 		}
 		if !f(k) {
 			break
@@ -120,6 +120,14 @@ func TestIterateSorted(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.Equal(t, []kv.Key{"k1", "k2", "k3", "k4", "k5"}, seen)
+
+	seen = nil
+	err = d.IterateKeysSortedFrom("k", "k3", func(k kv.Key) bool {
+		seen = append(seen, k)
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, []kv.Key{"k3", "k4", "k5"}, seen)
 }
 
 func TestMarshaling(t *testing.T) {
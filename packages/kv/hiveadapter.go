@@ -65,9 +65,17 @@ func (h *HiveKVStoreReader) IterateSorted(prefix Key, f func(key Key, value []by
 }
 
 func (h *HiveKVStoreReader) IterateKeysSorted(prefix Key, f func(key Key) bool) error {
+	return h.IterateKeysSortedFrom(prefix, "", f)
+}
+
+// IterateKeysSortedFrom reads all the keys with the prefix and sorts them, the hive.go key stores
+// iterate by prefix only and can't seek to from
+func (h *HiveKVStoreReader) IterateKeysSortedFrom(prefix, from Key, f func(key Key) bool) error {
 	var keys []Key
 	err := h.db.IterateKeys([]byte(prefix), func(k kvstore.Key) bool {
-		keys = append(keys, Key(k))
+		if Key(k) >= from {
+			keys = append(keys, Key(k))
+		}
 		return true
 	})
 	if err != nil {
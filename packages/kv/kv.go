@@ -51,6 +51,9 @@ type KVIterator interface {
 	IterateKeys(prefix Key, f func(key Key) bool) error
 	IterateSorted(prefix Key, f func(key Key, value []byte) bool) error
 	IterateKeysSorted(prefix Key, f func(key Key) bool) error
+	// IterateKeysSortedFrom iterates the keys with the prefix in their order, starting with the first key
+	// which is not less than from. The implementations read all the keys with the prefix, there is no seek
+	IterateKeysSortedFrom(prefix, from Key, f func(key Key) bool) error
 }
 
 type KVMustReader interface {
@@ -117,6 +120,13 @@ func MustIterateKeysSorted(kvs KVStoreReader, prefix Key, f func(key Key) bool)
 	}
 }
 
+func MustIterateKeysSortedFrom(kvs KVStoreReader, prefix, from Key, f func(key Key) bool) {
+	err := kvs.IterateKeysSortedFrom(prefix, from, f)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func Concat(fragments ...interface{}) []byte {
 	var buf bytes.Buffer
 	for _, v := range fragments {
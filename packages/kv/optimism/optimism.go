@@ -127,6 +127,19 @@ func (o *OptimisticKVStoreReader) IterateKeysSorted(prefix kv.Key, f func(key kv
 	return nil
 }
 
+func (o *OptimisticKVStoreReader) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
+	if !o.baseline.IsValid() {
+		return coreutil.ErrorStateInvalidated
+	}
+	if err := o.kvstore.IterateKeysSortedFrom(prefix, from, f); err != nil {
+		return err
+	}
+	if !o.baseline.IsValid() {
+		return coreutil.ErrorStateInvalidated
+	}
+	return nil
+}
+
 func (o *OptimisticKVStoreReader) MustGet(key kv.Key) []byte {
 	o.baseline.MustValidate()
 	defer o.baseline.MustValidate()
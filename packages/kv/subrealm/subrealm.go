@@ -54,6 +54,12 @@ func (s *subrealm) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) bool) err
 	})
 }
 
+func (s *subrealm) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
+	return s.kv.IterateKeysSortedFrom(s.prefix+prefix, s.prefix+from, func(key kv.Key) bool {
+		return f(key[len(s.prefix):])
+	})
+}
+
 func (s *subrealm) MustGet(key kv.Key) []byte {
 	return kv.MustGet(s, key)
 }
@@ -46,6 +46,12 @@ func (s *subrealmReadOnly) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) b
 	})
 }
 
+func (s *subrealmReadOnly) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
+	return s.kv.IterateKeysSortedFrom(s.prefix+prefix, s.prefix+from, func(key kv.Key) bool {
+		return f(key[len(s.prefix):])
+	})
+}
+
 func (s *subrealmReadOnly) MustGet(key kv.Key) []byte {
 	return kv.MustGet(s, key)
 }
@@ -33,6 +33,8 @@ type Metrics struct {
 	blockSizes              *prometheus.GaugeVec
 	lastSeenStateIndex      *prometheus.GaugeVec
 	lastSeenStateIndexVal   uint32
+	webAPIRequestCost       *prometheus.CounterVec
+	webAPIRateLimited       *prometheus.CounterVec
 	nodeconnMetrics         nodeconnmetrics.NodeConnectionMetrics
 }
 
@@ -155,6 +157,18 @@ func (m *Metrics) registerMetrics() {
 		Help: "Last seen state index",
 	}, []string{"chain"})
 	prometheus.MustRegister(m.lastSeenStateIndex)
+
+	m.webAPIRequestCost = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wasp_webapi_request_cost",
+		Help: "Cost units spent by the web API requests, per rate limit group",
+	}, []string{"group"})
+	prometheus.MustRegister(m.webAPIRequestCost)
+
+	m.webAPIRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wasp_webapi_rate_limited_counter",
+		Help: "Number of web API requests rejected by the rate limiter",
+	}, []string{"group", "client"})
+	prometheus.MustRegister(m.webAPIRateLimited)
 }
 
 func (m *Metrics) GetNodeConnectionMetrics() nodeconnmetrics.NodeConnectionMetrics {
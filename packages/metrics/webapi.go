@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type WebAPIMetrics interface {
+	CountRequestCost(group string, cost int)
+	CountRateLimited(group, client string)
+}
+
+type webAPIMetricsObj struct {
+	metrics *Metrics
+}
+
+var (
+	_ WebAPIMetrics = &webAPIMetricsObj{}
+	_ WebAPIMetrics = &defaultWebAPIMetrics{}
+)
+
+func (m *Metrics) NewWebAPIMetrics() WebAPIMetrics {
+	if m == nil {
+		return DefaultWebAPIMetrics()
+	}
+	return &webAPIMetricsObj{metrics: m}
+}
+
+// the counters are registered, when the metrics server starts, and the web API
+// may serve requests before that
+func (w *webAPIMetricsObj) CountRequestCost(group string, cost int) {
+	if w.metrics.webAPIRequestCost == nil {
+		return
+	}
+	w.metrics.webAPIRequestCost.With(prometheus.Labels{"group": group}).Add(float64(cost))
+}
+
+func (w *webAPIMetricsObj) CountRateLimited(group, client string) {
+	if w.metrics.webAPIRateLimited == nil {
+		return
+	}
+	w.metrics.webAPIRateLimited.With(prometheus.Labels{"group": group, "client": client}).Inc()
+}
+
+type defaultWebAPIMetrics struct{}
+
+func DefaultWebAPIMetrics() WebAPIMetrics {
+	return &defaultWebAPIMetrics{}
+}
+
+func (m *defaultWebAPIMetrics) CountRequestCost(_ string, _ int) {}
+
+func (m *defaultWebAPIMetrics) CountRateLimited(_, _ string) {}
@@ -32,6 +32,11 @@ const (
 	WebAPIAdminWhitelist         = "webapi.adminWhitelist"
 	WebAPIAdminWhitelistDisabled = "webapi.adminWhitelistDisabled"
 	WebAPIAuth                   = "webapi.auth"
+	WebAPIRateLimit              = "webapi.rateLimit"
+	WebAPIRateLimitEnabled       = "webapi.rateLimit.enabled"
+	WebAPIRateLimitTrustProxy    = "webapi.rateLimit.trustForwardedFor"
+	WebAPIGRPCEnabled            = "webapi.grpc.enabled"
+	WebAPIGRPCBindAddress        = "webapi.grpc.bindAddress"
 
 	DashboardBindAddress       = "dashboard.bindAddress"
 	DashboardExploreAddressURL = "dashboard.exploreAddressUrl"
@@ -51,6 +56,10 @@ const (
 	OffledgerBroadcastInterval   = "offledger.broadcastInterval"
 	OffledgerAPICacheTTL         = "offledger.apiCacheTTL"
 
+	EVMArchiveIndex = "evm.archiveIndex"
+
+	VMParallelWorkers = "vm.parallelWorkers"
+
 	ProfilingBindAddress   = "profiling.bindAddress"
 	ProfilingEnabled       = "profiling.enabled"
 	ProfilingWriteProfiles = "profiling.writeProfiles"
@@ -61,10 +70,16 @@ const (
 	WALEnabled   = "wal.enabled"
 	WALDirectory = "wal.directory"
 
+	StateManagerFastSyncThreshold = "statemgr.fastSyncThreshold"
+
 	RawBlocksEnabled = "debug.rawblocksEnabled"
 	RawBlocksDir     = "debug.rawblocksDirectory"
 	RegistryUseText  = "registry.useText"
 	RegistryFile     = "registry.file"
+
+	RegistryKeyStoreType   = "registry.keyStore.type"
+	RegistryKeyStoreFile   = "registry.keyStore.file"
+	RegistryKeyStoreSocket = "registry.keyStore.socket"
 )
 
 func Init() *configuration.Configuration {
@@ -87,6 +102,10 @@ func Init() *configuration.Configuration {
 	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm wndpoints")
 	flag.StringToString(WebAPIAuth, nil, "authentication scheme for web API")
 	flag.Bool(WebAPIAdminWhitelistDisabled, false, "Disables IP whitelisting and allows requests from _any_ IP")
+	flag.Bool(WebAPIRateLimitEnabled, false, "whether to limit the rate of the public web API requests per client IP and API key")
+	flag.Bool(WebAPIRateLimitTrustProxy, false, "take the client IP from the X-Forwarded-For header, when the node is behind a reverse proxy")
+	flag.Bool(WebAPIGRPCEnabled, false, "whether to serve the gRPC API")
+	flag.String(WebAPIGRPCBindAddress, "127.0.0.1:9091", "the bind address for the gRPC API")
 
 	flag.String(DashboardBindAddress, "127.0.0.1:7000", "the bind address for the node dashboard")
 	flag.String(DashboardExploreAddressURL, "", "URL to add as href to addresses in the dashboard [default: <nodeconn.address>:8081/explorer/address]")
@@ -105,6 +124,10 @@ func Init() *configuration.Configuration {
 	flag.Int(OffledgerBroadcastInterval, 5000, "time between re-broadcast of offledger requests (in ms)")
 	flag.Int(OffledgerAPICacheTTL, 5*60, "time to keep processed offledger requests in api cache (in seconds)")
 
+	flag.Int(VMParallelWorkers, 0, "number of workers running the requests of a block speculatively in parallel. With 0 or 1 the requests are run sequentially")
+
+	flag.Bool(EVMArchiveIndex, false, "whether to keep an index of all the EVM blocks outside the chain state, to serve the JSON-RPC queries of the blocks pruned by the evm contract")
+
 	flag.String(ProfilingBindAddress, "127.0.0.1:6060", "pprof http server address")
 	flag.Bool(ProfilingEnabled, false, "whether profiling is enabled")
 	flag.Bool(ProfilingWriteProfiles, false, "whether to write profiling profiles to disk on node shutdown (when enabled some metrics will be unavailable via pprof runtime endpoint)")
@@ -115,10 +138,15 @@ func Init() *configuration.Configuration {
 	flag.Bool(WALEnabled, true, "enabled wal")
 	flag.String(WALDirectory, "wal", "path to logs folder")
 
+	flag.Int(StateManagerFastSyncThreshold, 0, "download the state trie from the peers instead of applying the blocks, if a chain starts at least that many blocks behind its latest state; 0 disables fast sync")
+
 	flag.Bool(RawBlocksEnabled, false, "enable raw blocks to be written to disk on a separate dir")
 	flag.String(RawBlocksDir, "blocks", "path to the directory where the blocks should be written to")
 	flag.Bool(RegistryUseText, false, "enable text key/value store for registry db.")
 	flag.String(RegistryFile, "chain-registry.json", "registry filename. Ignored if registry.useText is false.")
+	flag.String(RegistryKeyStoreType, "db", "where to keep the node identity and DKShares: db, file or remote. The remote signer keeps them in a separate process, signing on behalf of the node.")
+	flag.String(RegistryKeyStoreFile, "keystore.json", "key store filename, the passphrase is taken from the WASP_KEYSTORE_PASSPHRASE environment variable. Used if registry.keyStore.type is file.")
+	flag.String(RegistryKeyStoreSocket, "keystore.sock", "unix socket of the remote signer, the token is taken from the WASP_KEYSTORE_TOKEN environment variable. Used if registry.keyStore.type is remote.")
 
 	return all
 }
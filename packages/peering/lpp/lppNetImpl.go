@@ -32,6 +32,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/peering/domain"
 	"github.com/iotaledger/wasp/packages/peering/group"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/util"
 	libp2p "github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/crypto"
@@ -55,17 +56,17 @@ const (
 
 // netImpl implements a peering.NetworkProvider interface.
 type netImpl struct {
-	myNetID     string                  // NetID of this node.
-	lppHost     host.Host               // The instance of the libp2p to use.
-	port        int                     // Port to use for peering.
-	ctx         context.Context         // Context for the libp2p
-	ctxCancel   context.CancelFunc      // A way to close the context.
-	peers       map[libp2ppeer.ID]*peer // By remotePeer.ID()
-	peersLock   *sync.RWMutex
-	recvEvents  *events.Event // Used to publish events to all attached clients.
-	nodeKeyPair *cryptolib.KeyPair
-	trusted     peering.TrustedNetworkManager
-	log         *logger.Logger
+	myNetID      string                  // NetID of this node.
+	lppHost      host.Host               // The instance of the libp2p to use.
+	port         int                     // Port to use for peering.
+	ctx          context.Context         // Context for the libp2p
+	ctxCancel    context.CancelFunc      // A way to close the context.
+	peers        map[libp2ppeer.ID]*peer // By remotePeer.ID()
+	peersLock    *sync.RWMutex
+	recvEvents   *events.Event // Used to publish events to all attached clients.
+	nodeIdentity signer.Identity
+	trusted      peering.TrustedNetworkManager
+	log          *logger.Logger
 }
 
 var (
@@ -78,13 +79,13 @@ var (
 func NewNetworkProvider(
 	myNetID string,
 	port int,
-	nodeKeyPair *cryptolib.KeyPair,
+	nodeIdentity signer.Identity,
 	trusted peering.TrustedNetworkManager,
 	log *logger.Logger,
 ) (peering.NetworkProvider, peering.TrustedNetworkManager, error) {
-	privKey, err := crypto.UnmarshalEd25519PrivateKey(nodeKeyPair.GetPrivateKey().AsBytes())
+	privKey, err := newSignerPrivKey(nodeIdentity)
 	if err != nil {
-		return nil, nil, xerrors.Errorf("unable to convert the private key: %w", err)
+		return nil, nil, err
 	}
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	lppHost, err := libp2p.New(
@@ -104,17 +105,17 @@ func NewNetworkProvider(
 		return nil, nil, xerrors.Errorf("failed to construct libp2p host: %w", err)
 	}
 	n := netImpl{
-		myNetID:     myNetID,
-		lppHost:     lppHost,
-		ctx:         ctx,
-		ctxCancel:   ctxCancel,
-		port:        port,
-		peers:       make(map[libp2ppeer.ID]*peer),
-		peersLock:   &sync.RWMutex{},
-		recvEvents:  nil, // Initialized bellow.
-		nodeKeyPair: nodeKeyPair,
-		trusted:     trusted,
-		log:         log,
+		myNetID:      myNetID,
+		lppHost:      lppHost,
+		ctx:          ctx,
+		ctxCancel:    ctxCancel,
+		port:         port,
+		peers:        make(map[libp2ppeer.ID]*peer),
+		peersLock:    &sync.RWMutex{},
+		recvEvents:   nil, // Initialized bellow.
+		nodeIdentity: nodeIdentity,
+		trusted:      trusted,
+		log:          log,
 	}
 	n.recvEvents = events.NewEvent(n.eventHandler)
 	//
@@ -396,7 +397,7 @@ func (n *netImpl) NetID() string {
 
 // PubKey implements peering.PeerSender for the Self() node.
 func (n *netImpl) PubKey() *cryptolib.PublicKey {
-	return n.nodeKeyPair.GetPublicKey()
+	return n.nodeIdentity.GetPublicKey()
 }
 
 // SendMsg implements peering.PeerSender for the Self() node.
@@ -472,7 +473,7 @@ func (n *netImpl) TrustedPeers() ([]*peering.TrustedPeer, error) {
 }
 
 func (n *netImpl) usePeer(remotePubKey *cryptolib.PublicKey) (peering.PeerSender, error) {
-	if remotePubKey.Equals(n.nodeKeyPair.GetPublicKey()) {
+	if remotePubKey.Equals(n.nodeIdentity.GetPublicKey()) {
 		return n, nil
 	}
 	n.peersLock.Lock()
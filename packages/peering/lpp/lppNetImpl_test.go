@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering/lpp"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/testutil/testpeers"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,11 +38,11 @@ func TestLPPPeeringImpl(t *testing.T) {
 			require.NoError(t, err)
 		}
 	}
-	nodes[0], _, err = lpp.NewNetworkProvider(netIDs[0], 9027, keys[0], tnms[0], log.Named("node0"))
+	nodes[0], _, err = lpp.NewNetworkProvider(netIDs[0], 9027, testpeers.NewSigner(t, keys[0]), tnms[0], log.Named("node0"))
 	require.NoError(t, err)
-	nodes[1], _, err = lpp.NewNetworkProvider(netIDs[1], 9028, keys[1], tnms[1], log.Named("node1"))
+	nodes[1], _, err = lpp.NewNetworkProvider(netIDs[1], 9028, testpeers.NewSigner(t, keys[1]), tnms[1], log.Named("node1"))
 	require.NoError(t, err)
-	nodes[2], _, err = lpp.NewNetworkProvider(netIDs[2], 9029, keys[2], tnms[2], log.Named("node2"))
+	nodes[2], _, err = lpp.NewNetworkProvider(netIDs[2], 9029, testpeers.NewSigner(t, keys[2]), tnms[2], log.Named("node2"))
 	require.NoError(t, err)
 	for i := range nodes {
 		go nodes[i].Run(context.Background())
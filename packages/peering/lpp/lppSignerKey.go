@@ -0,0 +1,61 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package lpp
+
+import (
+	"crypto/sha256"
+
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	pb "github.com/libp2p/go-libp2p/core/crypto/pb"
+	"golang.org/x/xerrors"
+)
+
+const signerKeyRawLabel = "wasp-lpp-raw-key"
+
+// signerPrivKey represents the node identity, held by the signer, as a libp2p private key.
+// The libp2p only signs with it (TLS certificates, peer records), except the QUIC transport
+// deriving its stateless reset key from the Raw bytes. Raw returns a hash of a signature
+// instead of the key, it is stable because the Ed25519 signatures are deterministic.
+type signerPrivKey struct {
+	identity signer.Identity
+	pubKey   crypto.PubKey
+	raw      []byte
+}
+
+var _ crypto.PrivKey = &signerPrivKey{}
+
+func newSignerPrivKey(identity signer.Identity) (*signerPrivKey, error) {
+	pubKey, err := crypto.UnmarshalEd25519PublicKey(identity.GetPublicKey().AsBytes())
+	if err != nil {
+		return nil, xerrors.Errorf("unable to convert the public key: %w", err)
+	}
+	signature, err := identity.Sign([]byte(signerKeyRawLabel))
+	if err != nil {
+		return nil, xerrors.Errorf("unable to sign with the node identity: %w", err)
+	}
+	raw := sha256.Sum256(signature)
+	return &signerPrivKey{identity: identity, pubKey: pubKey, raw: raw[:]}, nil
+}
+
+func (k *signerPrivKey) Equals(other crypto.Key) bool {
+	otherT, ok := other.(*signerPrivKey)
+	return ok && k.pubKey.Equals(otherT.pubKey)
+}
+
+func (k *signerPrivKey) Raw() ([]byte, error) {
+	return k.raw, nil
+}
+
+func (k *signerPrivKey) Type() pb.KeyType {
+	return pb.KeyType_Ed25519
+}
+
+func (k *signerPrivKey) Sign(data []byte) ([]byte, error) {
+	return k.identity.Sign(data)
+}
+
+func (k *signerPrivKey) GetPublic() crypto.PubKey {
+	return k.pubKey
+}
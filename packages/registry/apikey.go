@@ -0,0 +1,57 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// APIKey is a key, which the clients of the web API can present to get a quota
+// of their own, instead of sharing the rate limit of their IP address.
+// Rate is the number of cost units per second and Burst is the maximum number
+// of cost units which can be spent at once.
+type APIKey struct {
+	Key   string
+	Name  string
+	Rate  uint32
+	Burst uint32
+}
+
+func APIKeyFromBytes(buf []byte) (*APIKey, error) {
+	var err error
+	r := bytes.NewBuffer(buf)
+	k := APIKey{}
+	if k.Key, err = util.ReadString16(r); err != nil {
+		return nil, err
+	}
+	if k.Name, err = util.ReadString16(r); err != nil {
+		return nil, err
+	}
+	if err = util.ReadUint32(r, &k.Rate); err != nil {
+		return nil, err
+	}
+	if err = util.ReadUint32(r, &k.Burst); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (k *APIKey) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := util.WriteString16(&buf, k.Key); err != nil {
+		return nil, err
+	}
+	if err := util.WriteString16(&buf, k.Name); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint32(&buf, k.Rate); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint32(&buf, k.Burst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	nodeSigner, err := signer.NewLocal(keystore.NewDBKeyStore(store), log)
+	require.NoError(t, err)
+	reg := NewRegistry(log, store, nodeSigner)
+
+	keys, err := reg.APIKeys()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	k, err := reg.GetAPIKey("key1")
+	require.NoError(t, err)
+	require.Nil(t, k)
+
+	require.NoError(t, reg.SaveAPIKey(&APIKey{Key: "key1", Name: "first", Rate: 10, Burst: 20}))
+	require.NoError(t, reg.SaveAPIKey(&APIKey{Key: "key2", Name: "second", Rate: 1, Burst: 1}))
+	require.NoError(t, reg.SaveAPIKey(&APIKey{Key: "key2", Name: "second", Rate: 5, Burst: 5})) // Duplicate entry should be overwritten.
+
+	keys, err = reg.APIKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	k, err = reg.GetAPIKey("key2")
+	require.NoError(t, err)
+	require.Equal(t, &APIKey{Key: "key2", Name: "second", Rate: 5, Burst: 5}, k)
+
+	require.NoError(t, reg.DeleteAPIKey("key1"))
+	keys, err = reg.APIKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "key2", keys[0].Key)
+}
@@ -8,6 +8,7 @@ package registry
 import (
 	"encoding/binary"
 	"errors"
+	"sort"
 
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
@@ -18,6 +19,7 @@ import (
 const (
 	dbKeyForConsensusJournalLogIndex byte = iota
 	dbKeyForConsensusJournalLocalView
+	dbKeyForConsensusJournalDecisions
 )
 
 func (r *Impl) LoadConsensusJournal(id journal.ID) (journal.LogIndex, journal.LocalView, error) {
@@ -67,6 +69,74 @@ func (r *Impl) SaveConsensusJournalLocalView(id journal.ID, localView journal.Lo
 	return nil
 }
 
+func (r *Impl) SaveConsensusJournalDecision(id journal.ID, decision *journal.Decision) error {
+	decisionBytes, err := decision.AsBytes()
+	if err != nil {
+		return xerrors.Errorf("cannot serialize decision: %w", err)
+	}
+	return r.store.Set(dbKeyForConsensusJournalDecision(id, decision.LogIndex), decisionBytes)
+}
+
+// PruneConsensusJournalDecisions removes only the decision at before-1. The decisions
+// are pruned each time a new one is saved, so the older ones are already gone and
+// there is no need to scan the whole journal.
+func (r *Impl) PruneConsensusJournalDecisions(id journal.ID, before journal.LogIndex) error {
+	if before == 0 {
+		return nil
+	}
+	return r.store.Delete(dbKeyForConsensusJournalDecision(id, before-1))
+}
+
+// ConsensusJournals returns the log indexes of all the consensus journals in the registry store.
+// It reads the store directly, to inspect the registry of a stopped node.
+func ConsensusJournals(store kvstore.KVStore) (map[journal.ID]journal.LogIndex, error) {
+	prefix := dbKeyForConsensusJournal(journal.ID{}, dbKeyForConsensusJournalLogIndex)
+	prefix = prefix[:len(prefix)-len(journal.ID{})]
+	ret := make(map[journal.ID]journal.LogIndex)
+	err := store.Iterate(prefix, func(key kvstore.Key, value kvstore.Value) bool {
+		var id journal.ID
+		copy(id[:], key[len(prefix):])
+		ret[id] = journal.LogIndex(binary.BigEndian.Uint32(value))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConsensusJournalDecisions returns the decisions, recorded in the journal, ordered by the log index.
+// It reads the store directly, to inspect the registry of a stopped node.
+func ConsensusJournalDecisions(store kvstore.KVStore, id journal.ID) ([]*journal.Decision, error) {
+	var ret []*journal.Decision
+	var err error
+	err2 := store.Iterate(dbKeyForConsensusJournal(id, dbKeyForConsensusJournalDecisions), func(key kvstore.Key, value kvstore.Value) bool {
+		var decision *journal.Decision
+		if decision, err = journal.NewDecisionFromBytes(value); err != nil {
+			err = xerrors.Errorf("cannot deserialize decision %x: %w", key, err)
+			return false
+		}
+		ret = append(ret, decision)
+		return true
+	})
+	if err2 != nil {
+		return nil, err2
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].LogIndex < ret[j].LogIndex
+	})
+	return ret, nil
+}
+
+func dbKeyForConsensusJournalDecision(id journal.ID, logIndex journal.LogIndex) []byte {
+	liBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(liBytes, logIndex.AsUint32())
+	return append(dbKeyForConsensusJournal(id, dbKeyForConsensusJournalDecisions), liBytes...)
+}
+
 func dbKeyForConsensusJournal(id journal.ID, subKey byte) []byte {
 	return dbkeys.MakeKey(dbkeys.ObjectTypeConsensusJournal, []byte{subKey}, id[:])
 }
@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/tpkg"
+	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusJournalDecisions(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	nodeSigner, err := signer.NewLocal(keystore.NewDBKeyStore(store), log)
+	require.NoError(t, err)
+	reg := NewRegistry(log, store, nodeSigner)
+	chainID := isc.RandomChainID()
+	id, err := journal.MakeID(*chainID, tpkg.RandEd25519Address())
+	require.NoError(t, err)
+	require.NoError(t, reg.SaveConsensusJournalLogIndex(*id, 3))
+
+	kp := cryptolib.NewKeyPair()
+	ao := &iotago.AliasOutput{
+		Amount:     1000,
+		AliasID:    *chainID.AsAliasID(),
+		StateIndex: 7,
+		Conditions: iotago.UnlockConditions{
+			&iotago.StateControllerAddressUnlockCondition{Address: tpkg.RandEd25519Address()},
+			&iotago.GovernorAddressUnlockCondition{Address: tpkg.RandEd25519Address()},
+		},
+	}
+	for li := journal.LogIndex(0); li < 3; li++ {
+		req := isc.NewOffLedgerRequest(chainID, isc.Hn("contract"), isc.Hn("entryPoint"), nil, uint64(li)).Sign(kp)
+		require.NoError(t, reg.SaveConsensusJournalDecision(*id, &journal.Decision{
+			LogIndex:           li,
+			BaseAliasOutput:    isc.NewAliasOutputWithID(ao, tpkg.RandOutputID(0).UTXOInput()),
+			Proposals:          [][]byte{{1, 2, 3}, {4, 5}},
+			TimeAssumption:     time.Now(),
+			Entropy:            hashing.RandomHash(nil),
+			ValidatorFeeTarget: isc.NewAgentID(kp.Address()),
+			Requests:           []isc.Request{req},
+		}))
+	}
+
+	journals, err := ConsensusJournals(store)
+	require.NoError(t, err)
+	require.Equal(t, map[journal.ID]journal.LogIndex{*id: 3}, journals)
+
+	decisions, err := ConsensusJournalDecisions(store, *id)
+	require.NoError(t, err)
+	require.Len(t, decisions, 3)
+	for i, d := range decisions {
+		require.EqualValues(t, i, d.LogIndex)
+		require.EqualValues(t, 7, d.BaseAliasOutput.GetStateIndex())
+		require.Equal(t, [][]byte{{1, 2, 3}, {4, 5}}, d.Proposals)
+		require.Len(t, d.Requests, 1)
+		require.True(t, d.ValidatorFeeTarget.Equals(isc.NewAgentID(kp.Address())))
+	}
+
+	require.NoError(t, reg.PruneConsensusJournalDecisions(*id, 2))
+	decisions, err = ConsensusJournalDecisions(store, *id)
+	require.NoError(t, err)
+	require.Len(t, decisions, 2)
+	require.EqualValues(t, 0, decisions[0].LogIndex)
+	require.EqualValues(t, 2, decisions[1].LogIndex)
+
+	require.NoError(t, reg.PruneConsensusJournalDecisions(*id, 1))
+	decisions, err = ConsensusJournalDecisions(store, *id)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.EqualValues(t, 2, decisions[0].LogIndex)
+}
@@ -9,20 +9,21 @@ import (
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 )
 
 type Provider func() *Impl
 
 type NodeIdentityProvider interface {
-	GetNodeIdentity() *cryptolib.KeyPair
+	GetNodeIdentity() signer.Signer
 	GetNodePublicKey() *cryptolib.PublicKey
 }
 
 // DKShareRegistryProvider stands for a partial registry interface, needed for this package.
-// It should be implemented by registry.impl
+// It should be implemented by registry.impl. The key shares are stored by the signer,
+// only their public parts are provided here.
 type DKShareRegistryProvider interface {
-	SaveDKShare(dkShare tcrypto.DKShare) error
 	LoadDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error)
 }
 
@@ -36,3 +37,12 @@ type ChainRecordRegistryProvider interface {
 	ActivateChainRecord(chainID *isc.ChainID) (*ChainRecord, error)
 	DeactivateChainRecord(chainID *isc.ChainID) (*ChainRecord, error)
 }
+
+// APIKeyRegistryProvider stands for a partial registry interface, needed to
+// keep the API keys of the web API clients.
+type APIKeyRegistryProvider interface {
+	GetAPIKey(key string) (*APIKey, error)
+	SaveAPIKey(apiKey *APIKey) error
+	DeleteAPIKey(key string) error
+	APIKeys() ([]*APIKey, error)
+}
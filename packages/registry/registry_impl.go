@@ -17,8 +17,10 @@ import (
 	"github.com/iotaledger/wasp/packages/database/dbkeys"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 )
 
@@ -27,15 +29,16 @@ import (
 // Impl is just a placeholder to implement all interfaces needed by different components.
 // Each of the interfaces are implemented in the corresponding file in this package.
 type Impl struct {
-	log          *logger.Logger
-	store        kvstore.KVStore
-	nodeIdentity *cryptolib.KeyPair
+	log    *logger.Logger
+	store  kvstore.KVStore
+	signer signer.Signer // Node identity and DKShares are kept here.
 }
 
 var (
 	_ NodeIdentityProvider        = &Impl{}
 	_ DKShareRegistryProvider     = &Impl{}
 	_ ChainRecordRegistryProvider = &Impl{}
+	_ APIKeyRegistryProvider      = &Impl{}
 	_ journal.Registry            = &Impl{}
 )
 
@@ -52,33 +55,13 @@ func DefaultConfig() *Config {
 }
 
 // New creates new instance of the registry implementation.
-func NewRegistry(log *logger.Logger, store kvstore.KVStore) *Impl {
-	result := &Impl{
-		log:   log.Named("registry"),
-		store: store,
+// The secrets (node identity and DKShares) are kept by the signer.
+func NewRegistry(log *logger.Logger, store kvstore.KVStore, nodeSigner signer.Signer) *Impl {
+	return &Impl{
+		log:    log.Named("registry"),
+		store:  store,
+		signer: nodeSigner,
 	}
-	// Read or create node identity - private/public key pair
-	dbKey := dbKeyForNodeIdentity()
-	exists, _ := result.store.Has(dbKey)
-	if !exists {
-		result.nodeIdentity = cryptolib.NewKeyPair()
-		data := result.nodeIdentity.GetPrivateKey().AsBytes()
-		result.log.Infof("Node identity key pair generated. PublicKey: %s", result.nodeIdentity.GetPublicKey())
-		if err := result.store.Set(dbKey, data); err != nil {
-			result.log.Error("Generated node identity cannot be stored: %v", err)
-		}
-	}
-	data, err := result.store.Get(dbKey)
-	if err != nil {
-		result.log.Panicf("Cannot read node identity: %v", err)
-	}
-	privateKey, err := cryptolib.NewPrivateKeyFromBytes(data)
-	if err != nil {
-		result.log.Panicf("Cannot read create private key from read node identity: %v", err)
-	}
-	result.nodeIdentity = cryptolib.NewKeyPairFromPrivateKey(privateKey)
-
-	return result
 }
 
 // endregion ////////////////////////////////////////////////////////
@@ -158,37 +141,13 @@ func (r *Impl) SaveChainRecord(rec *ChainRecord) error {
 
 // region DKShareRegistryProvider ////////////////////////////////////////////////////
 
-// SaveDKShare implements dkg.DKShareRegistryProvider.
-func (r *Impl) SaveDKShare(dkShare tcrypto.DKShare) error {
-	var err error
-	var exists bool
-	dbKey := dbKeyForDKShare(dkShare.GetAddress())
-
-	r.log.Infof("Saving DKShare for address=%v as key %v", dkShare.GetAddress().String(), dbKey)
-
-	if exists, err = r.store.Has(dbKey); err != nil {
-		return err
-	}
-	if exists {
-		return fmt.Errorf("attempt to overwrite existing DK key share")
-	}
-	return r.store.Set(dbKey, dkShare.Bytes())
-}
-
 // LoadDKShare implements dkg.DKShareRegistryProvider.
 func (r *Impl) LoadDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error) {
-	data, err := r.store.Get(dbKeyForDKShare(sharedAddress))
-	if err != nil {
-		if errors.Is(err, kvstore.ErrKeyNotFound) {
-			return nil, ErrDKShareNotFound
-		}
-		return nil, err
+	dkShare, err := r.signer.LoadDKShare(sharedAddress)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		return nil, ErrDKShareNotFound
 	}
-	return tcrypto.DKShareFromBytes(data, tcrypto.DefaultEd25519Suite(), tcrypto.DefaultBLSSuite(), r.nodeIdentity.GetPrivateKey())
-}
-
-func dbKeyForDKShare(sharedAddress iotago.Address) []byte {
-	return dbkeys.MakeKey(dbkeys.ObjectTypeDistributedKeyData, isc.BytesFromAddress(sharedAddress))
+	return dkShare, err
 }
 
 // endregion //////////////////////////////////////////////////////////////
@@ -272,6 +231,55 @@ func dbKeyForTrustedPeer(tp *peering.TrustedPeer) ([]byte, error) {
 
 // endregion  //////////////////////////////////////////////////////////////////////
 
+// region APIKeyRegistryProvider ///////////////////////////////////////////////
+
+// GetAPIKey implements APIKeyRegistryProvider interface. It returns nil, if the key is not known.
+func (r *Impl) GetAPIKey(key string) (*APIKey, error) {
+	data, err := r.store.Get(dbKeyForAPIKey(key))
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return APIKeyFromBytes(data)
+}
+
+// SaveAPIKey implements APIKeyRegistryProvider interface.
+func (r *Impl) SaveAPIKey(apiKey *APIKey) error {
+	data, err := apiKey.Bytes()
+	if err != nil {
+		return err
+	}
+	return r.store.Set(dbKeyForAPIKey(apiKey.Key), data)
+}
+
+// DeleteAPIKey implements APIKeyRegistryProvider interface.
+func (r *Impl) DeleteAPIKey(key string) error {
+	return r.store.Delete(dbKeyForAPIKey(key))
+}
+
+// APIKeys implements APIKeyRegistryProvider interface.
+func (r *Impl) APIKeys() ([]*APIKey, error) {
+	ret := make([]*APIKey, 0)
+	err := r.store.Iterate([]byte{dbkeys.ObjectTypeAPIKey}, func(key kvstore.Key, value kvstore.Value) bool {
+		if k, recErr := APIKeyFromBytes(value); recErr == nil {
+			ret = append(ret, k)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func dbKeyForAPIKey(key string) []byte {
+	return dbkeys.MakeKey(dbkeys.ObjectTypeAPIKey, []byte(key))
+}
+
+// endregion  //////////////////////////////////////////////////////////////////////
+
 // region BlobCacheProvider ///////////////////////////////////////////////
 
 // TODO blob cache cleanup
@@ -327,8 +335,8 @@ func (r *Impl) HasBlob(h hashing.HashValue) (bool, error) {
 // region NodeIdentity //////////////////////////////////////////
 
 // GetNodeIdentity implements NodeIdentityProvider.
-func (r *Impl) GetNodeIdentity() *cryptolib.KeyPair {
-	return r.nodeIdentity
+func (r *Impl) GetNodeIdentity() signer.Signer {
+	return r.signer
 }
 
 // GetNodePublicKey implements NodeIdentityProvider.
@@ -336,8 +344,4 @@ func (r *Impl) GetNodePublicKey() *cryptolib.PublicKey {
 	return r.GetNodeIdentity().GetPublicKey()
 }
 
-func dbKeyForNodeIdentity() []byte {
-	return dbkeys.MakeKey(dbkeys.ObjectTypeNodeIdentity)
-}
-
 // endregion ///////////////////////////////////////////////////
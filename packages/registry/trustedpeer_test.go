@@ -5,6 +5,8 @@ import (
 
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/stretchr/testify/require"
 )
@@ -12,7 +14,10 @@ import (
 func TestTrustedPeer(t *testing.T) {
 	// var err error
 	log := testlogger.NewLogger(t)
-	reg := NewRegistry(log, mapdb.NewMapDB())
+	store := mapdb.NewMapDB()
+	nodeSigner, err := signer.NewLocal(keystore.NewDBKeyStore(store), log)
+	require.NoError(t, err)
+	reg := NewRegistry(log, store, nodeSigner)
 
 	tpList, err := reg.TrustedPeers()
 	require.Nil(t, err)
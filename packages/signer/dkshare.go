@@ -0,0 +1,62 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3/sign/dss"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"golang.org/x/xerrors"
+)
+
+var errNoPrivateShare = xerrors.New("the private key share is held by the signer")
+
+// dkShareRef is the public part of a key share, the operations
+// needing the private share are delegated to the signer, if possible.
+type dkShareRef struct {
+	tcrypto.DKShare
+	signer Signer
+}
+
+var _ tcrypto.DKShare = &dkShareRef{}
+
+func newDKShareRef(publicBytes []byte, signer Signer) (tcrypto.DKShare, error) {
+	dkShare, err := tcrypto.DKSharePublicFromBytes(publicBytes, tcrypto.DefaultEd25519Suite(), tcrypto.DefaultBLSSuite())
+	if err != nil {
+		return nil, err
+	}
+	return &dkShareRef{DKShare: dkShare, signer: signer}, nil
+}
+
+func (d *dkShareRef) Bytes() []byte {
+	return d.DKShare.PublicBytes()
+}
+
+func (d *dkShareRef) DSSSignShare(data []byte, nonce tcrypto.SecretShare) (*dss.PartialSig, error) {
+	return nil, errNoPrivateShare
+}
+
+func (d *dkShareRef) DSSRecoverMasterSignature(sigShares []*dss.PartialSig, data []byte, nonce tcrypto.SecretShare) ([]byte, error) {
+	return nil, errNoPrivateShare
+}
+
+func (d *dkShareRef) DSSSecretShare() tcrypto.SecretShare {
+	return nil // Use Signer.NewDSS instead.
+}
+
+func (d *dkShareRef) DSSReshareDeal(newN, newT uint16) (*tcrypto.ReshareDeal, error) {
+	return nil, errNoPrivateShare
+}
+
+func (d *dkShareRef) BLSSignShare(data []byte) (tbls.SigShare, error) {
+	return d.signer.BLSSignShare(d.GetAddress(), data)
+}
+
+func (d *dkShareRef) BLSSign(data []byte) ([]byte, error) {
+	return nil, errNoPrivateShare
+}
+
+func (d *dkShareRef) BLSReshareDeal(newN, newT uint16) (*tcrypto.ReshareDeal, error) {
+	return nil, errNoPrivateShare
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// The number of the DSS contexts remembered to prevent signing
+// a different message with the same context.
+const maxSignedContexts = 1000
+
+type localSigner struct {
+	keyStore keystore.KeyStore
+	identity *cryptolib.KeyPair
+	secKey   kyber.Scalar // Derived from the identity.
+	edSuite  suites.Suite
+	blsSuite tcrypto.Suite
+	signed   *signedContexts
+	log      *logger.Logger
+}
+
+var _ Signer = &localSigner{}
+
+// NewLocal creates a signer using the secrets in the key store. The
+// node identity is generated and stored, if the key store has none.
+func NewLocal(keyStore keystore.KeyStore, log *logger.Logger) (Signer, error) {
+	data, err := keyStore.Get(dbKeyForNodeIdentity())
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		identity := cryptolib.NewKeyPair()
+		if err := keyStore.Set(dbKeyForNodeIdentity(), identity.GetPrivateKey().AsBytes()); err != nil {
+			return nil, xerrors.Errorf("generated node identity cannot be stored: %w", err)
+		}
+		log.Infof("Node identity key pair generated. PublicKey: %s", identity.GetPublicKey())
+		return newLocal(keyStore, identity, log)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read node identity: %w", err)
+	}
+	privateKey, err := cryptolib.NewPrivateKeyFromBytes(data)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create private key from the node identity: %w", err)
+	}
+	return newLocal(keyStore, cryptolib.NewKeyPairFromPrivateKey(privateKey), log)
+}
+
+// NewLocalWithIdentity creates a signer with the specified identity, storing it in the
+// key store, if the key store has none. It is mainly used in the tests.
+func NewLocalWithIdentity(keyStore keystore.KeyStore, identity *cryptolib.KeyPair, log *logger.Logger) (Signer, error) {
+	data, err := keyStore.Get(dbKeyForNodeIdentity())
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		if err := keyStore.Set(dbKeyForNodeIdentity(), identity.GetPrivateKey().AsBytes()); err != nil {
+			return nil, err
+		}
+		return newLocal(keyStore, identity, log)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(data, identity.GetPrivateKey().AsBytes()) {
+		return nil, xerrors.New("the key store has another node identity")
+	}
+	return newLocal(keyStore, identity, log)
+}
+
+func newLocal(keyStore keystore.KeyStore, identity *cryptolib.KeyPair, log *logger.Logger) (*localSigner, error) {
+	kyberEdDSSA := eddsa.EdDSA{}
+	if err := kyberEdDSSA.UnmarshalBinary(identity.GetPrivateKey().AsBytes()); err != nil {
+		return nil, err
+	}
+	return &localSigner{
+		keyStore: keyStore,
+		identity: identity,
+		secKey:   kyberEdDSSA.Secret,
+		edSuite:  tcrypto.DefaultEd25519Suite(),
+		blsSuite: tcrypto.DefaultBLSSuite(),
+		signed:   newSignedContexts(maxSignedContexts),
+		log:      log.Named("signer"),
+	}, nil
+}
+
+// ReadDKShare reads the complete key share (with the private parts) from the key store.
+// Only the signer and the tools working with the key store offline should use it.
+func ReadDKShare(keyStore keystore.KeyStore, sharedAddress iotago.Address, nodePrivKey *cryptolib.PrivateKey) (tcrypto.DKShare, error) {
+	data, err := keyStore.Get(dbKeyForDKShare(sharedAddress))
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.DKShareFromBytes(data, tcrypto.DefaultEd25519Suite(), tcrypto.DefaultBLSSuite(), nodePrivKey)
+}
+
+// StoreDKShare stores the complete key share in the key store.
+// It is used by the tests to provide the pre-generated key shares.
+func StoreDKShare(keyStore keystore.KeyStore, dkShare tcrypto.DKShare) error {
+	return keyStore.Set(dbKeyForDKShare(dkShare.GetAddress()), dkShare.Bytes())
+}
+
+func dbKeyForNodeIdentity() []byte {
+	return dbkeys.MakeKey(dbkeys.ObjectTypeNodeIdentity)
+}
+
+func dbKeyForDKShare(sharedAddress iotago.Address) []byte {
+	return dbkeys.MakeKey(dbkeys.ObjectTypeDistributedKeyData, isc.BytesFromAddress(sharedAddress))
+}
+
+func (s *localSigner) GetPublicKey() *cryptolib.PublicKey {
+	return s.identity.GetPublicKey()
+}
+
+func (s *localSigner) Sign(data []byte) ([]byte, error) {
+	return s.identity.GetPrivateKey().Sign(data), nil
+}
+
+func (s *localSigner) LoadDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error) {
+	dkShare, err := s.readDKShare(sharedAddress)
+	if err != nil {
+		return nil, err
+	}
+	return newDKShareRef(dkShare.PublicBytes(), s)
+}
+
+func (s *localSigner) BLSSignShare(sharedAddress iotago.Address, data []byte) (tbls.SigShare, error) {
+	dkShare, err := s.readDKShare(sharedAddress)
+	if err != nil {
+		return nil, err
+	}
+	return dkShare.BLSSignShare(data)
+}
+
+func (s *localSigner) NewDKG(peerPubs []*cryptolib.PublicKey, threshold uint16) (DKG, error) {
+	return newLocalDKG(s, peerPubs, threshold)
+}
+
+func (s *localSigner) NewReshare(params *ReshareParams) (Reshare, error) {
+	return newLocalReshare(s, params)
+}
+
+func (s *localSigner) NewDSS(sharedAddress iotago.Address, key string, index int) (DSS, error) {
+	return newLocalDSS(s, sharedAddress, key, index)
+}
+
+func (s *localSigner) Close() error {
+	return s.keyStore.Close()
+}
+
+func (s *localSigner) readDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error) {
+	return ReadDKShare(s.keyStore, sharedAddress, s.identity.GetPrivateKey())
+}
+
+func (s *localSigner) storeDKShare(dkShare tcrypto.DKShare, replace bool) error {
+	dbKey := dbKeyForDKShare(dkShare.GetAddress())
+	if !replace {
+		exists, err := s.keyStore.Has(dbKey)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return xerrors.New("attempt to overwrite existing DK key share")
+		}
+	}
+	s.log.Infof("Storing DKShare for address=%v", dkShare.GetAddress().String())
+	return s.keyStore.Set(dbKey, dkShare.Bytes())
+}
+
+// region signedContexts ///////////////////////////////////////////////////////
+
+// signedContexts remembers the messages signed with the DSS contexts. Signing two
+// different messages in the same context is refused, because the consensus only
+// produces a single transaction for each log index. The contexts are not persisted,
+// the consensus itself takes care of that over the restarts.
+type signedContexts struct {
+	lock   *sync.Mutex
+	hashes map[string]hashing.HashValue
+	order  []string // For the cleanup.
+	limit  int
+}
+
+func newSignedContexts(limit int) *signedContexts {
+	return &signedContexts{
+		lock:   &sync.Mutex{},
+		hashes: map[string]hashing.HashValue{},
+		order:  []string{},
+		limit:  limit,
+	}
+}
+
+func (sc *signedContexts) check(context string, message []byte) error {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	hash := hashing.HashData(message)
+	if signed, ok := sc.hashes[context]; ok {
+		if signed != hash {
+			return xerrors.New("another message was already signed in this context")
+		}
+		return nil
+	}
+	sc.hashes[context] = hash
+	sc.order = append(sc.order, context)
+	if len(sc.order) > sc.limit {
+		delete(sc.hashes, sc.order[0])
+		sc.order = sc.order[1:]
+	}
+	return nil
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
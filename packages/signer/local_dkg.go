@@ -0,0 +1,274 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	"go.dedis.ch/kyber/v3/util/key"
+	"golang.org/x/xerrors"
+)
+
+// localSession is the part common to the DKG and the resharing.
+type localSession struct {
+	signer  *localSigner
+	dkShare tcrypto.DKShare // The new key share, once it is produced.
+	replace bool            // The resharing replaces the existing share.
+	lock    *sync.Mutex
+}
+
+func (ls *localSession) BLSSign(data []byte) ([]byte, error) {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+	if ls.dkShare == nil {
+		return nil, xerrors.New("the key share is not produced yet")
+	}
+	return ls.dkShare.BLSSign(data)
+}
+
+func (ls *localSession) Commit(edPublicShares, blsPublicShares []kyber.Point) error {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+	if ls.dkShare == nil {
+		return xerrors.New("there is no dkShare to commit")
+	}
+	ls.dkShare.SetPublicShares(edPublicShares, blsPublicShares)
+	return ls.signer.storeDKShare(ls.dkShare, ls.replace)
+}
+
+func (ls *localSession) Close() error {
+	return nil
+}
+
+// region localDKG /////////////////////////////////////////////////////////////
+
+type localDKG struct {
+	localSession
+	peerPubs  []*cryptolib.PublicKey
+	threshold uint16
+	impl      map[KeySet]*rabin_dkg.DistKeyGenerator // Nil, if N=1.
+}
+
+var _ DKG = &localDKG{}
+
+func newLocalDKG(s *localSigner, peerPubs []*cryptolib.PublicKey, threshold uint16) (*localDKG, error) {
+	d := &localDKG{
+		localSession: localSession{signer: s, lock: &sync.Mutex{}},
+		peerPubs:     peerPubs,
+		threshold:    threshold,
+	}
+	if len(peerPubs) < 2 {
+		// We use real DKG only if N >= 2. Otherwise we just generate key pair, and that's all.
+		return d, nil
+	}
+	kyberPeerPubs := make([]kyber.Point, len(peerPubs))
+	for i := range kyberPeerPubs {
+		kyberPeerPubs[i] = s.edSuite.Point()
+		if err := kyberPeerPubs[i].UnmarshalBinary(peerPubs[i].AsBytes()); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	d.impl = make(map[KeySet]*rabin_dkg.DistKeyGenerator)
+	if d.impl[KeySetEd25519], err = rabin_dkg.NewDistKeyGenerator(s.edSuite, s.edSuite, s.secKey, kyberPeerPubs, int(threshold)); err != nil {
+		return nil, xerrors.Errorf("failed to instantiate DistKeyGenerator: %w", err)
+	}
+	if d.impl[KeySetBLS], err = rabin_dkg.NewDistKeyGenerator(s.blsSuite, s.edSuite, s.secKey, kyberPeerPubs, int(threshold)); err != nil {
+		return nil, xerrors.Errorf("failed to instantiate DistKeyGenerator: %w", err)
+	}
+	return d, nil
+}
+
+// generator is called with the lock held.
+func (d *localDKG) generator(ks KeySet) (*rabin_dkg.DistKeyGenerator, error) {
+	if d.impl == nil {
+		return nil, xerrors.New("unexpected step for n=1")
+	}
+	impl, ok := d.impl[ks]
+	if !ok {
+		return nil, xerrors.Errorf("unknown key set: %v", ks)
+	}
+	return impl, nil
+}
+
+func (d *localDKG) Deals(ks KeySet) (map[int]*rabin_dkg.Deal, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.Deals()
+}
+
+func (d *localDKG) ProcessDeal(ks KeySet, deal *rabin_dkg.Deal) (*rabin_dkg.Response, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.ProcessDeal(deal)
+}
+
+func (d *localDKG) ProcessResponse(ks KeySet, response *rabin_dkg.Response) (*rabin_dkg.Justification, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.ProcessResponse(response)
+}
+
+func (d *localDKG) ProcessJustification(ks KeySet, justification *rabin_dkg.Justification) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return err
+	}
+	return impl.ProcessJustification(justification)
+}
+
+func (d *localDKG) QUAL(ks KeySet) ([]int, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	impl.SetTimeout()
+	if !impl.Certified() {
+		return nil, xerrors.New("node not certified")
+	}
+	return impl.QUAL(), nil
+}
+
+func (d *localDKG) SecretCommits(ks KeySet) (*rabin_dkg.SecretCommits, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.SecretCommits()
+}
+
+func (d *localDKG) ProcessSecretCommits(ks KeySet, secretCommits *rabin_dkg.SecretCommits) (*rabin_dkg.ComplaintCommits, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.ProcessSecretCommits(secretCommits)
+}
+
+func (d *localDKG) ProcessComplaintCommits(ks KeySet, complaintCommits *rabin_dkg.ComplaintCommits) (*rabin_dkg.ReconstructCommits, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return nil, err
+	}
+	return impl.ProcessComplaintCommits(complaintCommits)
+}
+
+func (d *localDKG) ProcessReconstructCommits(ks KeySet, reconstructCommits *rabin_dkg.ReconstructCommits) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	impl, err := d.generator(ks)
+	if err != nil {
+		return err
+	}
+	return impl.ProcessReconstructCommits(reconstructCommits)
+}
+
+func (d *localDKG) DKShare() (tcrypto.DKShare, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.dkShare == nil {
+		var err error
+		if d.impl == nil {
+			d.dkShare, err = d.makeSingleDKShare()
+		} else {
+			d.dkShare, err = d.makeDKShare()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newDKShareRef(d.dkShare.PublicBytes(), d.signer)
+}
+
+// makeSingleDKShare is the case for N=1, just use simple key pairs.
+func (d *localDKG) makeSingleDKShare() (tcrypto.DKShare, error) {
+	s := d.signer
+	keyPairE := key.NewKeyPair(s.edSuite)
+	keyPairB := key.NewKeyPair(s.blsSuite)
+	return tcrypto.NewDKShare(
+		0,                              // Index
+		1,                              // N
+		1,                              // T
+		s.identity.GetPrivateKey(),     // NodePrivKey
+		d.peerPubs,                     // NodePubKeys
+		s.edSuite,                      // Ed25519: Suite
+		keyPairE.Public,                // Ed25519: SharedPublic
+		[]kyber.Point{keyPairE.Public}, // Ed25519: PublicCommits
+		[]kyber.Point{keyPairE.Public}, // Ed25519: PublicShares
+		keyPairE.Private,               // Ed25519: PrivateShare
+		s.blsSuite,                     // BLS: Suite
+		keyPairB.Public,                // BLS: SharedPublic
+		make([]kyber.Point, 0),         // BLS: PublicCommits
+		[]kyber.Point{keyPairB.Public}, // BLS: PublicShares
+		keyPairB.Private,               // BLS: PrivateShare
+	)
+}
+
+// makeDKShare retrieves the generated key shares, only the own public share is known here.
+func (d *localDKG) makeDKShare() (tcrypto.DKShare, error) {
+	s := d.signer
+	var err error
+	if !d.impl[KeySetEd25519].Finished() || !d.impl[KeySetBLS].Finished() {
+		return nil, xerrors.New("DKG procedure is not finished")
+	}
+	var distKeyShareDSS *rabin_dkg.DistKeyShare
+	var distKeyShareBLS *rabin_dkg.DistKeyShare
+	if distKeyShareDSS, err = d.impl[KeySetEd25519].DistKeyShare(); err != nil {
+		return nil, err
+	}
+	if distKeyShareBLS, err = d.impl[KeySetBLS].DistKeyShare(); err != nil {
+		return nil, err
+	}
+	groupSize := uint16(len(d.peerPubs))
+	ownIndex := uint16(distKeyShareDSS.PriShare().I)
+	publicSharesDSS := make([]kyber.Point, groupSize)
+	publicSharesDSS[ownIndex] = s.edSuite.Point().Mul(distKeyShareDSS.PriShare().V, nil)
+	publicSharesBLS := make([]kyber.Point, groupSize)
+	publicSharesBLS[ownIndex] = s.blsSuite.Point().Mul(distKeyShareBLS.PriShare().V, nil)
+	return tcrypto.NewDKShare(
+		ownIndex,                     // Index
+		groupSize,                    // N
+		d.threshold,                  // T
+		s.identity.GetPrivateKey(),   // NodePrivKey
+		d.peerPubs,                   // NodePubKeys
+		s.edSuite,                    // Ed25519: Suite
+		distKeyShareDSS.Public(),     // Ed25519: SharedPublic
+		distKeyShareDSS.Commits,      // Ed25519: PublicCommits
+		publicSharesDSS,              // Ed25519: PublicShares
+		distKeyShareDSS.PriShare().V, // Ed25519: PrivateShare
+		s.blsSuite,                   // BLS: Suite
+		distKeyShareBLS.Public(),     // BLS: SharedPublic
+		distKeyShareBLS.Commits,      // BLS: PublicCommits
+		publicSharesBLS,              // BLS: PublicShares
+		distKeyShareBLS.PriShare().V, // BLS: PrivateShare
+	)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
@@ -0,0 +1,219 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"sync"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/chain/dss"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// localDSS hosts a DSS protocol instance. The messages of the instance are wrapped
+// into envelopes signed by the node identity, and the received envelopes are checked
+// against the public keys of the committee, so the node relaying them cannot forge
+// the messages of the other nodes, nor reuse them in another instance.
+type localDSS struct {
+	signer   *localSigner
+	context  []byte // Identifies the instance in the envelopes.
+	me       gpa.NodeID
+	peerPubs map[gpa.NodeID]*cryptolib.PublicKey
+	inst     dss.DSS
+	asGPA    gpa.GPA
+	lock     *sync.Mutex
+}
+
+var _ DSS = &localDSS{}
+
+func newLocalDSS(s *localSigner, sharedAddress iotago.Address, key string, index int) (*localDSS, error) {
+	dkShare, err := s.readDKShare(sharedAddress)
+	if err != nil {
+		return nil, err
+	}
+	context, err := dssContext(sharedAddress, key, index)
+	if err != nil {
+		return nil, err
+	}
+	nodePKs := dkShare.GetNodePubKeys()
+	nodeIDs := make([]gpa.NodeID, len(nodePKs))
+	peerPubs := make(map[gpa.NodeID]*cryptolib.PublicKey, len(nodePKs))
+	kyberNodePKs := make(map[gpa.NodeID]kyber.Point, len(nodePKs))
+	for i := range nodePKs {
+		nodeIDs[i] = gpa.NodeID(nodePKs[i].String())
+		peerPubs[nodeIDs[i]] = nodePKs[i]
+		kyberNodePKs[nodeIDs[i]] = s.edSuite.Point()
+		if err := kyberNodePKs[nodeIDs[i]].UnmarshalBinary(nodePKs[i].AsBytes()); err != nil {
+			return nil, err
+		}
+	}
+	me := gpa.NodeID(s.GetPublicKey().String())
+	if _, ok := peerPubs[me]; !ok {
+		return nil, xerrors.Errorf("this node is not in the committee of %v", sharedAddress)
+	}
+	n := len(nodeIDs)
+	f := n - int(dkShare.GetT())
+	s.log.Debugf("Constructing DSS instance, CommitteeAddress=%v, n=%v, f=%v, nodeIDs=%v", dkShare.DSSSharedPublic(), n, f, nodeIDs)
+	inst := dss.New(
+		s.edSuite,                // suite
+		nodeIDs,                  // nodeIDs
+		kyberNodePKs,             // nodePKs
+		f,                        // f
+		me,                       // me
+		s.secKey,                 // mySK
+		dkShare.DSSSecretShare(), // longTermSecretShare
+		s.log,
+	)
+	return &localDSS{
+		signer:   s,
+		context:  context,
+		me:       me,
+		peerPubs: peerPubs,
+		inst:     inst,
+		asGPA:    inst.AsGPA(),
+		lock:     &sync.Mutex{},
+	}, nil
+}
+
+func dssContext(sharedAddress iotago.Address, key string, index int) ([]byte, error) {
+	w := &bytes.Buffer{}
+	if err := util.WriteBytes16(w, isc.BytesFromAddress(sharedAddress)); err != nil {
+		return nil, err
+	}
+	if err := util.WriteString16(w, key); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint32(w, uint32(index)); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func (d *localDSS) Input() (*DSSResult, error) {
+	return d.call(func() gpa.OutMessages {
+		return d.asGPA.Input(nil)
+	})
+}
+
+func (d *localDSS) Message(sender gpa.NodeID, data []byte) (*DSSResult, error) {
+	senderPub, ok := d.peerPubs[sender]
+	if !ok {
+		return nil, xerrors.Errorf("unknown sender: %v", sender)
+	}
+	payload, err := d.openEnvelope(senderPub, data)
+	if err != nil {
+		return nil, err
+	}
+	return d.call(func() gpa.OutMessages {
+		msg, err := d.asGPA.UnmarshalMessage(payload)
+		if err != nil {
+			d.signer.log.Warnf("Cannot parse DSS message: %v", err)
+			return nil
+		}
+		msg.SetSender(sender)
+		return d.asGPA.Message(msg)
+	})
+}
+
+func (d *localDSS) Decided(decidedIndexProposals map[gpa.NodeID][]int, messageToSign []byte) (*DSSResult, error) {
+	if err := d.signer.signed.check(string(d.context), messageToSign); err != nil {
+		return nil, err
+	}
+	return d.call(func() gpa.OutMessages {
+		return d.asGPA.Message(d.inst.NewMsgDecided(decidedIndexProposals, messageToSign))
+	})
+}
+
+func (d *localDSS) Close() error {
+	return nil
+}
+
+// call runs the instance, the malformed messages can make it panic.
+func (d *localDSS) call(f func() gpa.OutMessages) (result *DSSResult, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = xerrors.Errorf("DSS instance failed: %v", r)
+		}
+	}()
+	msgs := f()
+	result = &DSSResult{Messages: []*DSSMessage{}}
+	if msgs != nil {
+		if err := msgs.Iterate(func(msg gpa.Message) error {
+			data, err := d.sealEnvelope(msg)
+			if err != nil {
+				return err
+			}
+			result.Messages = append(result.Messages, &DSSMessage{Recipient: msg.Recipient(), Data: data})
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if output := d.asGPA.Output(); output != nil {
+		result.Output = output.(*dss.Output)
+	}
+	return result, nil
+}
+
+func (d *localDSS) envelopeData(recipient gpa.NodeID, payload []byte) ([]byte, error) {
+	w := &bytes.Buffer{}
+	if err := util.WriteBytes16(w, d.context); err != nil {
+		return nil, err
+	}
+	if err := util.WriteString16(w, string(recipient)); err != nil {
+		return nil, err
+	}
+	if err := util.WriteBytes32(w, payload); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func (d *localDSS) sealEnvelope(msg gpa.Message) ([]byte, error) {
+	payload, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := d.envelopeData(msg.Recipient(), payload)
+	if err != nil {
+		return nil, err
+	}
+	w := &bytes.Buffer{}
+	if err := util.WriteBytes32(w, payload); err != nil {
+		return nil, err
+	}
+	if err := util.WriteBytes16(w, d.signer.identity.GetPrivateKey().Sign(signedData)); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func (d *localDSS) openEnvelope(senderPub *cryptolib.PublicKey, data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	payload, err := util.ReadBytes32(r)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := util.ReadBytes16(r)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := d.envelopeData(d.me, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !senderPub.Verify(signedData, signature) {
+		return nil, xerrors.Errorf("invalid signature of a DSS message from %v", senderPub)
+	}
+	return payload, nil
+}
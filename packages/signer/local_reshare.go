@@ -0,0 +1,267 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+	"go.dedis.ch/kyber/v3/share"
+	"golang.org/x/xerrors"
+)
+
+type localReshare struct {
+	localSession
+	params     *ReshareParams
+	oldDKShare tcrypto.DKShare // Current share of this node, nil, if not in the old group.
+	oldIndex   *uint16         // Index of this node in the old group, if any.
+	newIndex   *uint16         // Index of this node in the new group, if any.
+	commits    map[KeySet]map[uint16][]kyber.Point
+	subShares  map[KeySet]map[uint16]*share.PriShare
+}
+
+var _ Reshare = &localReshare{}
+
+func newLocalReshare(s *localSigner, params *ReshareParams) (*localReshare, error) {
+	r := &localReshare{
+		localSession: localSession{signer: s, replace: true, lock: &sync.Mutex{}},
+		params:       params,
+		commits: map[KeySet]map[uint16][]kyber.Point{
+			KeySetEd25519: make(map[uint16][]kyber.Point),
+			KeySetBLS:     make(map[uint16][]kyber.Point),
+		},
+		subShares: map[KeySet]map[uint16]*share.PriShare{
+			KeySetEd25519: make(map[uint16]*share.PriShare),
+			KeySetBLS:     make(map[uint16]*share.PriShare),
+		},
+	}
+	if oldIndex, ok := pubKeyIndex(params.OldPeerPubs, s.GetPublicKey()); ok {
+		var err error
+		if r.oldDKShare, err = s.readDKShare(params.SharedAddress); err != nil {
+			return nil, xerrors.Errorf("cannot load the key share to reshare: %w", err)
+		}
+		if err = r.checkOldDKShare(oldIndex); err != nil {
+			return nil, err
+		}
+		r.oldIndex = &oldIndex
+	}
+	if newIndex, ok := pubKeyIndex(params.NewPeerPubs, s.GetPublicKey()); ok {
+		r.newIndex = &newIndex
+	}
+	return r, nil
+}
+
+// checkOldDKShare ensures the initiator has provided the same information on the
+// current key as the one stored on this node.
+func (r *localReshare) checkOldDKShare(oldIndex uint16) error {
+	dks := r.oldDKShare
+	params := r.params
+	if dks.GetIndex() == nil || *dks.GetIndex() != oldIndex {
+		return xerrors.Errorf("node index mismatch for the key %v", params.SharedAddress)
+	}
+	if dks.GetT() != params.OldThreshold || int(dks.GetN()) != len(params.OldPeerPubs) {
+		return xerrors.Errorf("threshold or group size mismatch for the key %v", params.SharedAddress)
+	}
+	for i, nodePubKey := range dks.GetNodePubKeys() {
+		if !nodePubKey.Equals(params.OldPeerPubs[i]) {
+			return xerrors.Errorf("node public key mismatch for the key %v", params.SharedAddress)
+		}
+	}
+	if !pointsEqual(dks.DSSPublicShares(), params.EdOldPubShares) || !pointsEqual(dks.BLSPublicShares(), params.BLSOldPubShares) {
+		return xerrors.Errorf("public shares mismatch for the key %v", params.SharedAddress)
+	}
+	return nil
+}
+
+func (r *localReshare) oldPubShares(ks KeySet) []kyber.Point {
+	if ks == KeySetBLS {
+		return r.params.BLSOldPubShares
+	}
+	return r.params.EdOldPubShares
+}
+
+func (r *localReshare) Deal(ks KeySet) ([]kyber.Point, [][]byte, error) {
+	if r.oldDKShare == nil {
+		return nil, nil, nil // Not a dealer.
+	}
+	newN := uint16(len(r.params.NewPeerPubs))
+	var deal *tcrypto.ReshareDeal
+	var err error
+	switch ks {
+	case KeySetEd25519:
+		deal, err = r.oldDKShare.DSSReshareDeal(newN, r.params.NewThreshold)
+	case KeySetBLS:
+		deal, err = r.oldDKShare.BLSReshareDeal(newN, r.params.NewThreshold)
+	default:
+		return nil, nil, xerrors.Errorf("unknown key set: %v", ks)
+	}
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to make a resharing deal: %w", err)
+	}
+	encryptedSubShares := make([][]byte, newN)
+	for i := range encryptedSubShares {
+		if r.newIndex != nil && uint16(i) == *r.newIndex {
+			// Our own deal is accepted without sending it over the network.
+			if err = r.acceptDeal(ks, *r.oldIndex, deal.Commits, deal.SubShares[i]); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if encryptedSubShares[i], err = r.encryptSubShare(r.params.NewPeerPubs[i], deal.SubShares[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return deal.Commits, encryptedSubShares, nil
+}
+
+func (r *localReshare) AcceptDeal(ks KeySet, dealerIdx uint16, commits []kyber.Point, encryptedSubShare []byte) error {
+	if r.newIndex == nil {
+		return xerrors.New("this node is not in the new group")
+	}
+	subShare, err := r.decryptSubShare(ks, encryptedSubShare)
+	if err != nil {
+		return xerrors.Errorf("failed to decrypt a resharing deal: %w", err)
+	}
+	return r.acceptDeal(ks, dealerIdx, commits, subShare)
+}
+
+// acceptDeal verifies a deal and stores it for the recovery of the new share.
+func (r *localReshare) acceptDeal(ks KeySet, dealerIdx uint16, commits []kyber.Point, subShare *share.PriShare) error {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return err
+	}
+	oldPubShares := r.oldPubShares(ks)
+	if int(dealerIdx) >= len(oldPubShares) {
+		return xerrors.Errorf("have no public share for the dealer %v", dealerIdx)
+	}
+	if subShare.I != int(*r.newIndex) {
+		return xerrors.Errorf("sub-share has index %v instead of %v", subShare.I, *r.newIndex)
+	}
+	if err := tcrypto.VerifyReshareSubShare(suite, oldPubShares[dealerIdx], r.params.NewThreshold, commits, subShare); err != nil {
+		return err
+	}
+	r.lock.Lock()
+	r.commits[ks][dealerIdx] = commits
+	r.subShares[ks][dealerIdx] = subShare
+	r.lock.Unlock()
+	return nil
+}
+
+// The sub-shares are encrypted with the receiver's node key, so only the receiver can use them.
+func (r *localReshare) encryptSubShare(receiverPub *cryptolib.PublicKey, subShare *share.PriShare) ([]byte, error) {
+	edSuite := r.signer.edSuite
+	receiverPoint := edSuite.Point()
+	if err := receiverPoint.UnmarshalBinary(receiverPub.AsBytes()); err != nil {
+		return nil, err
+	}
+	subShareBytes, err := subShare.V.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return ecies.Encrypt(edSuite, receiverPoint, subShareBytes, edSuite.Hash)
+}
+
+func (r *localReshare) decryptSubShare(ks KeySet, encrypted []byte) (*share.PriShare, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, err
+	}
+	edSuite := r.signer.edSuite
+	subShareBytes, err := ecies.Decrypt(edSuite, r.signer.secKey, encrypted, edSuite.Hash)
+	if err != nil {
+		return nil, err
+	}
+	subShare := share.PriShare{I: int(*r.newIndex), V: suite.Scalar()}
+	if err := subShare.V.UnmarshalBinary(subShareBytes); err != nil {
+		return nil, err
+	}
+	return &subShare, nil
+}
+
+func (r *localReshare) Recover(dealers []uint16) (tcrypto.DKShare, error) {
+	if r.newIndex == nil {
+		return nil, xerrors.New("this node is not in the new group")
+	}
+	s := r.signer
+	params := r.params
+	oldN := uint16(len(params.OldPeerPubs))
+	newN := uint16(len(params.NewPeerPubs))
+	priShares := make(map[KeySet]*share.PriShare)
+	commits := make(map[KeySet][]kyber.Point)
+	for _, ks := range []KeySet{KeySetEd25519, KeySetBLS} {
+		suite, err := keySetSuite(ks)
+		if err != nil {
+			return nil, err
+		}
+		qualCommits := make(map[uint16][]kyber.Point)
+		qualSubShares := make(map[uint16]*share.PriShare)
+		r.lock.Lock()
+		for _, dealerIdx := range dealers {
+			qualCommits[dealerIdx] = r.commits[ks][dealerIdx]
+			qualSubShares[dealerIdx] = r.subShares[ks][dealerIdx]
+			if qualCommits[dealerIdx] == nil || qualSubShares[dealerIdx] == nil {
+				r.lock.Unlock()
+				return nil, xerrors.Errorf("have no accepted deal from the dealer %v", dealerIdx)
+			}
+		}
+		r.lock.Unlock()
+		if priShares[ks], commits[ks], err = tcrypto.RecoverReshared(suite, oldN, params.OldThreshold, qualCommits, qualSubShares); err != nil {
+			return nil, err
+		}
+	}
+	publicSharesDSS := tcrypto.ResharedPublicShares(s.edSuite, commits[KeySetEd25519], newN)
+	publicSharesBLS := tcrypto.ResharedPublicShares(s.blsSuite, commits[KeySetBLS], newN)
+	dkShare, err := tcrypto.NewDKShare(
+		*r.newIndex,                // Index
+		newN,                       // N
+		params.NewThreshold,        // T
+		s.identity.GetPrivateKey(), // NodePrivKey
+		params.NewPeerPubs,         // NodePubKeys
+		s.edSuite,                  // Ed25519: Suite
+		commits[KeySetEd25519][0],  // Ed25519: SharedPublic
+		commits[KeySetEd25519],     // Ed25519: PublicCommits
+		publicSharesDSS,            // Ed25519: PublicShares
+		priShares[KeySetEd25519].V, // Ed25519: PrivateShare
+		s.blsSuite,                 // BLS: Suite
+		commits[KeySetBLS][0],      // BLS: SharedPublic
+		commits[KeySetBLS],         // BLS: PublicCommits
+		publicSharesBLS,            // BLS: PublicShares
+		priShares[KeySetBLS].V,     // BLS: PrivateShare
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !dkShare.GetAddress().Equal(params.SharedAddress) {
+		return nil, xerrors.Errorf("reshared key has address %v instead of %v", dkShare.GetAddress(), params.SharedAddress)
+	}
+	r.lock.Lock()
+	r.dkShare = dkShare
+	r.lock.Unlock()
+	return newDKShareRef(dkShare.PublicBytes(), s)
+}
+
+func pubKeyIndex(pubKeys []*cryptolib.PublicKey, pubKey *cryptolib.PublicKey) (uint16, bool) {
+	for i := range pubKeys {
+		if pubKeys[i].Equals(pubKey) {
+			return uint16(i), true
+		}
+	}
+	return 0, false
+}
+
+func pointsEqual(a, b []kyber.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package signer
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+const peerCredentialsSupported = true
+
+// checkPeerCredentials checks, if the process on the other end of the unix
+// socket runs as one of the allowed users.
+func checkPeerCredentials(conn net.Conn, allowedUIDs []uint32) error {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return xerrors.New("not a unix socket connection")
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return err
+	}
+	if credErr != nil {
+		return xerrors.Errorf("cannot get the peer credentials: %w", credErr)
+	}
+	for _, uid := range allowedUIDs {
+		if cred.Uid == uid {
+			return nil
+		}
+	}
+	return xerrors.Errorf("user %v (pid %v) is not allowed", cred.Uid, cred.Pid)
+}
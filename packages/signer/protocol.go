@@ -0,0 +1,355 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+// The remote signer talks to a signer served by another process over a local
+// (unix) socket. Each request and response is sent as a single frame prefixed
+// by its length. A request consists of an operation code, a session ID and the
+// operation specific payload; a response consists of a status code and a payload.
+//
+// The sessions are the DKG, resharing and DSS instances created over the same
+// connection, they are closed when the connection is closed.
+//
+// A client must run as one of the allowed unix users (checked with the peer
+// credentials of the socket, where the platform has them), and present the token
+// of the server in its first frame, if the server has one. The server answers the
+// token frame with a status before serving any request.
+
+import (
+	"encoding/binary"
+	"io"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/chain/dss"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+const (
+	remoteOpPublicKey byte = iota
+	remoteOpSign
+	remoteOpLoadDKShare
+	remoteOpBLSSignShare
+	remoteOpNewDKG
+	remoteOpNewReshare
+	remoteOpNewDSS
+	remoteOpSessionBLSSign
+	remoteOpSessionCommit
+	remoteOpSessionClose
+	remoteOpDKGDeals
+	remoteOpDKGProcessDeal
+	remoteOpDKGProcessResponse
+	remoteOpDKGProcessJustification
+	remoteOpDKGQUAL
+	remoteOpDKGSecretCommits
+	remoteOpDKGProcessSecretCommits
+	remoteOpDKGProcessComplaintCommits
+	remoteOpDKGProcessReconstructCommits
+	remoteOpDKGDKShare
+	remoteOpReshareDeal
+	remoteOpReshareAcceptDeal
+	remoteOpReshareRecover
+	remoteOpDSSInput
+	remoteOpDSSMessage
+	remoteOpDSSDecided
+)
+
+const (
+	remoteStatusOK byte = iota
+	remoteStatusNotFound
+	remoteStatusError
+)
+
+const remoteMaxFrameSize = 16 * 1024 * 1024
+
+func keySetSuite(ks KeySet) (suites.Suite, error) {
+	switch ks {
+	case KeySetEd25519:
+		return tcrypto.DefaultEd25519Suite(), nil
+	case KeySetBLS:
+		return tcrypto.DefaultBLSSuite(), nil
+	default:
+		return nil, xerrors.Errorf("unknown key set: %v", ks)
+	}
+}
+
+func writeRemoteFrame(w io.Writer, frame []byte) error {
+	buf := make([]byte, 4+len(frame))
+	binary.BigEndian.PutUint32(buf, uint32(len(frame)))
+	copy(buf[4:], frame)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readRemoteFrame(r io.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	frameLen := binary.BigEndian.Uint32(lenBuf[:])
+	if frameLen > remoteMaxFrameSize {
+		return nil, xerrors.Errorf("frame too large: %v", frameLen)
+	}
+	frame := make([]byte, frameLen)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func writeAddress(w io.Writer, address iotago.Address) error {
+	return util.WriteBytes16(w, isc.BytesFromAddress(address))
+}
+
+func readAddress(r io.Reader) (iotago.Address, error) {
+	addressBytes, err := util.ReadBytes16(r)
+	if err != nil {
+		return nil, err
+	}
+	address, _, err := isc.AddressFromBytes(addressBytes)
+	return address, err
+}
+
+func writePubKeys(w io.Writer, pubKeys []*cryptolib.PublicKey) error {
+	if err := util.WriteUint16(w, uint16(len(pubKeys))); err != nil {
+		return err
+	}
+	for i := range pubKeys {
+		if err := util.WriteBytes16(w, pubKeys[i].AsBytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPubKeys(r io.Reader) ([]*cryptolib.PublicKey, error) {
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, err
+	}
+	pubKeys := make([]*cryptolib.PublicKey, count)
+	for i := range pubKeys {
+		pubKeyBytes, err := util.ReadBytes16(r)
+		if err != nil {
+			return nil, err
+		}
+		if pubKeys[i], err = cryptolib.NewPublicKeyFromBytes(pubKeyBytes); err != nil {
+			return nil, err
+		}
+	}
+	return pubKeys, nil
+}
+
+func writeInts(w io.Writer, ints []int) error {
+	if err := util.WriteUint16(w, uint16(len(ints))); err != nil {
+		return err
+	}
+	for _, i := range ints {
+		if err := util.WriteUint32(w, uint32(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readInts(r io.Reader) ([]int, error) {
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, err
+	}
+	ints := make([]int, count)
+	for i := range ints {
+		var val uint32
+		if err := util.ReadUint32(r, &val); err != nil {
+			return nil, err
+		}
+		ints[i] = int(val)
+	}
+	return ints, nil
+}
+
+func writeReshareParams(w io.Writer, params *ReshareParams) error {
+	if err := writeAddress(w, params.SharedAddress); err != nil {
+		return err
+	}
+	if err := writePubKeys(w, params.OldPeerPubs); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, params.OldThreshold); err != nil {
+		return err
+	}
+	if err := tcrypto.WritePoints(w, params.EdOldPubShares); err != nil {
+		return err
+	}
+	if err := tcrypto.WritePoints(w, params.BLSOldPubShares); err != nil {
+		return err
+	}
+	if err := writePubKeys(w, params.NewPeerPubs); err != nil {
+		return err
+	}
+	return util.WriteUint16(w, params.NewThreshold)
+}
+
+func readReshareParams(r io.Reader) (*ReshareParams, error) {
+	var err error
+	params := &ReshareParams{}
+	if params.SharedAddress, err = readAddress(r); err != nil {
+		return nil, err
+	}
+	if params.OldPeerPubs, err = readPubKeys(r); err != nil {
+		return nil, err
+	}
+	if err = util.ReadUint16(r, &params.OldThreshold); err != nil {
+		return nil, err
+	}
+	if params.EdOldPubShares, err = tcrypto.ReadPoints(r, tcrypto.DefaultEd25519Suite()); err != nil {
+		return nil, err
+	}
+	if params.BLSOldPubShares, err = tcrypto.ReadPoints(r, tcrypto.DefaultBLSSuite()); err != nil {
+		return nil, err
+	}
+	if params.NewPeerPubs, err = readPubKeys(r); err != nil {
+		return nil, err
+	}
+	if err = util.ReadUint16(r, &params.NewThreshold); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func writeDecidedIndexProposals(w io.Writer, decidedIndexProposals map[gpa.NodeID][]int) error {
+	if err := util.WriteUint16(w, uint16(len(decidedIndexProposals))); err != nil {
+		return err
+	}
+	for nodeID, indexes := range decidedIndexProposals {
+		if err := util.WriteString16(w, string(nodeID)); err != nil {
+			return err
+		}
+		if err := writeInts(w, indexes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDecidedIndexProposals(r io.Reader) (map[gpa.NodeID][]int, error) {
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, err
+	}
+	decidedIndexProposals := make(map[gpa.NodeID][]int, count)
+	for i := uint16(0); i < count; i++ {
+		nodeID, err := util.ReadString16(r)
+		if err != nil {
+			return nil, err
+		}
+		if decidedIndexProposals[gpa.NodeID(nodeID)], err = readInts(r); err != nil {
+			return nil, err
+		}
+	}
+	return decidedIndexProposals, nil
+}
+
+func writeDSSResult(w io.Writer, result *DSSResult) error {
+	if err := util.WriteUint16(w, uint16(len(result.Messages))); err != nil {
+		return err
+	}
+	for _, msg := range result.Messages {
+		if err := util.WriteString16(w, string(msg.Recipient)); err != nil {
+			return err
+		}
+		if err := util.WriteBytes32(w, msg.Data); err != nil {
+			return err
+		}
+	}
+	if err := util.WriteBoolByte(w, result.Output != nil); err != nil {
+		return err
+	}
+	if result.Output == nil {
+		return nil
+	}
+	if err := util.WriteBoolByte(w, result.Output.ProposedIndexes != nil); err != nil {
+		return err
+	}
+	if result.Output.ProposedIndexes != nil {
+		if err := writeInts(w, result.Output.ProposedIndexes); err != nil {
+			return err
+		}
+	}
+	if err := util.WriteBoolByte(w, result.Output.Signature != nil); err != nil {
+		return err
+	}
+	if result.Output.Signature != nil {
+		return util.WriteBytes16(w, result.Output.Signature)
+	}
+	return nil
+}
+
+func readDSSResult(r io.Reader) (*DSSResult, error) {
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, err
+	}
+	result := &DSSResult{Messages: make([]*DSSMessage, count)}
+	for i := range result.Messages {
+		recipient, err := util.ReadString16(r)
+		if err != nil {
+			return nil, err
+		}
+		data, err := util.ReadBytes32(r)
+		if err != nil {
+			return nil, err
+		}
+		result.Messages[i] = &DSSMessage{Recipient: gpa.NodeID(recipient), Data: data}
+	}
+	var present bool
+	if err := util.ReadBoolByte(r, &present); err != nil || !present {
+		return result, err
+	}
+	var err error
+	result.Output = &dss.Output{}
+	if err = util.ReadBoolByte(r, &present); err != nil {
+		return nil, err
+	}
+	if present {
+		if result.Output.ProposedIndexes, err = readInts(r); err != nil {
+			return nil, err
+		}
+	}
+	if err = util.ReadBoolByte(r, &present); err != nil {
+		return nil, err
+	}
+	if present {
+		if result.Output.Signature, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// writeNilablePoints is for the commitments of the resharing deals,
+// they are nil for the nodes not dealing.
+func writeNilablePoints(w io.Writer, points []kyber.Point) error {
+	if err := util.WriteBoolByte(w, points != nil); err != nil {
+		return err
+	}
+	if points == nil {
+		return nil
+	}
+	return tcrypto.WritePoints(w, points)
+}
+
+func readNilablePoints(r io.Reader, suite kyber.Group) ([]kyber.Point, error) {
+	var present bool
+	if err := util.ReadBoolByte(r, &present); err != nil || !present {
+		return nil, err
+	}
+	return tcrypto.ReadPoints(r, suite)
+}
@@ -0,0 +1,545 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"golang.org/x/xerrors"
+)
+
+type remoteSigner struct {
+	conn   net.Conn
+	lock   *sync.Mutex // Requests are served one at a time.
+	pubKey *cryptolib.PublicKey
+}
+
+var _ Signer = &remoteSigner{}
+
+// NewRemote connects to a signer served over the specified unix socket,
+// authenticating with the token, if the server requires one.
+func NewRemote(socket string, token []byte) (Signer, error) {
+	if socket == "" {
+		return nil, xerrors.New("signer socket is not specified")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot connect to the signer at %v: %w", socket, err)
+	}
+	if err := authenticateRemote(conn, token); err != nil {
+		conn.Close()
+		return nil, xerrors.Errorf("cannot authenticate to the signer at %v: %w", socket, err)
+	}
+	rs := &remoteSigner{conn: conn, lock: &sync.Mutex{}}
+	resp, err := rs.call(remoteOpPublicKey, 0, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if rs.pubKey, err = cryptolib.NewPublicKeyFromBytes(resp); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return rs, nil
+}
+
+func authenticateRemote(conn net.Conn, token []byte) error {
+	if err := writeRemoteFrame(conn, token); err != nil {
+		return err
+	}
+	resp, err := readRemoteFrame(conn)
+	if err != nil {
+		return err
+	}
+	if len(resp) != 1 || resp[0] != remoteStatusOK {
+		return xerrors.New("access denied")
+	}
+	return nil
+}
+
+// call sends a request and waits for the response. The payload
+// is written by the function passed, if any.
+func (rs *remoteSigner) call(op byte, session uint32, payload func(w io.Writer) error) ([]byte, error) {
+	var req bytes.Buffer
+	if err := util.WriteByte(&req, op); err != nil {
+		return nil, err
+	}
+	if err := util.WriteUint32(&req, session); err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		if err := payload(&req); err != nil {
+			return nil, err
+		}
+	}
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	if err := writeRemoteFrame(rs.conn, req.Bytes()); err != nil {
+		return nil, err
+	}
+	resp, err := readRemoteFrame(rs.conn)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(resp)
+	status, err := util.ReadByte(r)
+	if err != nil {
+		return nil, err
+	}
+	respPayload, err := util.ReadBytes32(r)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case remoteStatusOK:
+		return respPayload, nil
+	case remoteStatusNotFound:
+		return nil, keystore.ErrKeyNotFound
+	case remoteStatusError:
+		return nil, xerrors.Errorf("remote signer: %s", respPayload)
+	default:
+		return nil, xerrors.Errorf("remote signer: unexpected status %v", status)
+	}
+}
+
+func (rs *remoteSigner) GetPublicKey() *cryptolib.PublicKey {
+	return rs.pubKey
+}
+
+func (rs *remoteSigner) Sign(data []byte) ([]byte, error) {
+	return rs.call(remoteOpSign, 0, func(w io.Writer) error {
+		return util.WriteBytes32(w, data)
+	})
+}
+
+func (rs *remoteSigner) LoadDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error) {
+	resp, err := rs.call(remoteOpLoadDKShare, 0, func(w io.Writer) error {
+		return writeAddress(w, sharedAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newDKShareRef(resp, rs)
+}
+
+func (rs *remoteSigner) BLSSignShare(sharedAddress iotago.Address, data []byte) (tbls.SigShare, error) {
+	return rs.call(remoteOpBLSSignShare, 0, func(w io.Writer) error {
+		if err := writeAddress(w, sharedAddress); err != nil {
+			return err
+		}
+		return util.WriteBytes32(w, data)
+	})
+}
+
+func (rs *remoteSigner) NewDKG(peerPubs []*cryptolib.PublicKey, threshold uint16) (DKG, error) {
+	session, err := rs.newSession(remoteOpNewDKG, func(w io.Writer) error {
+		if err := writePubKeys(w, peerPubs); err != nil {
+			return err
+		}
+		return util.WriteUint16(w, threshold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteDKG{session}, nil
+}
+
+func (rs *remoteSigner) NewReshare(params *ReshareParams) (Reshare, error) {
+	session, err := rs.newSession(remoteOpNewReshare, func(w io.Writer) error {
+		return writeReshareParams(w, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteReshare{session}, nil
+}
+
+func (rs *remoteSigner) NewDSS(sharedAddress iotago.Address, key string, index int) (DSS, error) {
+	session, err := rs.newSession(remoteOpNewDSS, func(w io.Writer) error {
+		if err := writeAddress(w, sharedAddress); err != nil {
+			return err
+		}
+		if err := util.WriteString16(w, key); err != nil {
+			return err
+		}
+		return util.WriteUint32(w, uint32(index))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteDSS{session}, nil
+}
+
+func (rs *remoteSigner) Close() error {
+	return rs.conn.Close()
+}
+
+func (rs *remoteSigner) newSession(op byte, payload func(w io.Writer) error) (*remoteSession, error) {
+	resp, err := rs.call(op, 0, payload)
+	if err != nil {
+		return nil, err
+	}
+	var id uint32
+	if err := util.ReadUint32(bytes.NewReader(resp), &id); err != nil {
+		return nil, err
+	}
+	return &remoteSession{signer: rs, id: id}, nil
+}
+
+// region remoteSession ////////////////////////////////////////////////////////
+
+type remoteSession struct {
+	signer *remoteSigner
+	id     uint32
+}
+
+func (rss *remoteSession) call(op byte, payload func(w io.Writer) error) (*bytes.Reader, error) {
+	resp, err := rss.signer.call(op, rss.id, payload)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(resp), nil
+}
+
+func (rss *remoteSession) BLSSign(data []byte) ([]byte, error) {
+	return rss.signer.call(remoteOpSessionBLSSign, rss.id, func(w io.Writer) error {
+		return util.WriteBytes32(w, data)
+	})
+}
+
+func (rss *remoteSession) Commit(edPublicShares, blsPublicShares []kyber.Point) error {
+	_, err := rss.call(remoteOpSessionCommit, func(w io.Writer) error {
+		if err := tcrypto.WritePoints(w, edPublicShares); err != nil {
+			return err
+		}
+		return tcrypto.WritePoints(w, blsPublicShares)
+	})
+	return err
+}
+
+func (rss *remoteSession) Close() error {
+	_, err := rss.call(remoteOpSessionClose, nil)
+	return err
+}
+
+func writeKeySet(ks KeySet) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		return util.WriteByte(w, byte(ks))
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
+
+// region remoteDKG ////////////////////////////////////////////////////////////
+
+type remoteDKG struct {
+	*remoteSession
+}
+
+var _ DKG = &remoteDKG{}
+
+func (d *remoteDKG) Deals(ks KeySet) (map[int]*rabin_dkg.Deal, error) {
+	r, err := d.call(remoteOpDKGDeals, writeKeySet(ks))
+	if err != nil {
+		return nil, err
+	}
+	return readDeals(r)
+}
+
+func (d *remoteDKG) ProcessDeal(ks KeySet, deal *rabin_dkg.Deal) (*rabin_dkg.Response, error) {
+	r, err := d.call(remoteOpDKGProcessDeal, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinDeal(w, deal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	responses, err := tcrypto.ReadRabinResponses(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != 1 {
+		return nil, xerrors.Errorf("expected a single response, got %v", len(responses))
+	}
+	return responses[0], nil
+}
+
+func (d *remoteDKG) ProcessResponse(ks KeySet, response *rabin_dkg.Response) (*rabin_dkg.Justification, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.call(remoteOpDKGProcessResponse, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinResponses(w, []*rabin_dkg.Response{response})
+	})
+	if err != nil {
+		return nil, err
+	}
+	justifications, err := tcrypto.ReadRabinJustifications(r, suite)
+	if err != nil || len(justifications) == 0 {
+		return nil, err
+	}
+	return justifications[0], nil
+}
+
+func (d *remoteDKG) ProcessJustification(ks KeySet, justification *rabin_dkg.Justification) error {
+	_, err := d.call(remoteOpDKGProcessJustification, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinJustifications(w, []*rabin_dkg.Justification{justification})
+	})
+	return err
+}
+
+func (d *remoteDKG) QUAL(ks KeySet) ([]int, error) {
+	r, err := d.call(remoteOpDKGQUAL, writeKeySet(ks))
+	if err != nil {
+		return nil, err
+	}
+	return readInts(r)
+}
+
+func (d *remoteDKG) SecretCommits(ks KeySet) (*rabin_dkg.SecretCommits, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.call(remoteOpDKGSecretCommits, writeKeySet(ks))
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.ReadRabinSecretCommits(r, suite)
+}
+
+func (d *remoteDKG) ProcessSecretCommits(ks KeySet, secretCommits *rabin_dkg.SecretCommits) (*rabin_dkg.ComplaintCommits, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.call(remoteOpDKGProcessSecretCommits, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinSecretCommits(w, secretCommits)
+	})
+	if err != nil {
+		return nil, err
+	}
+	complaintCommits, err := tcrypto.ReadRabinComplaintCommits(r, suite)
+	if err != nil || len(complaintCommits) == 0 {
+		return nil, err
+	}
+	return complaintCommits[0], nil
+}
+
+func (d *remoteDKG) ProcessComplaintCommits(ks KeySet, complaintCommits *rabin_dkg.ComplaintCommits) (*rabin_dkg.ReconstructCommits, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.call(remoteOpDKGProcessComplaintCommits, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinComplaintCommits(w, []*rabin_dkg.ComplaintCommits{complaintCommits})
+	})
+	if err != nil {
+		return nil, err
+	}
+	reconstructCommits, err := tcrypto.ReadRabinReconstructCommits(r, suite)
+	if err != nil || len(reconstructCommits) == 0 {
+		return nil, err
+	}
+	return reconstructCommits[0], nil
+}
+
+func (d *remoteDKG) ProcessReconstructCommits(ks KeySet, reconstructCommits *rabin_dkg.ReconstructCommits) error {
+	_, err := d.call(remoteOpDKGProcessReconstructCommits, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinReconstructCommits(w, []*rabin_dkg.ReconstructCommits{reconstructCommits})
+	})
+	return err
+}
+
+func (d *remoteDKG) DKShare() (tcrypto.DKShare, error) {
+	resp, err := d.signer.call(remoteOpDKGDKShare, d.id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newDKShareRef(resp, d.signer)
+}
+
+func writeDeals(w io.Writer, deals map[int]*rabin_dkg.Deal) error {
+	if err := util.WriteUint16(w, uint16(len(deals))); err != nil {
+		return err
+	}
+	for i, deal := range deals {
+		if err := util.WriteUint16(w, uint16(i)); err != nil {
+			return err
+		}
+		if err := tcrypto.WriteRabinDeal(w, deal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDeals(r io.Reader) (map[int]*rabin_dkg.Deal, error) {
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, err
+	}
+	deals := make(map[int]*rabin_dkg.Deal, count)
+	for i := uint16(0); i < count; i++ {
+		var index uint16
+		if err := util.ReadUint16(r, &index); err != nil {
+			return nil, err
+		}
+		deal, err := tcrypto.ReadRabinDeal(r, tcrypto.DefaultEd25519Suite())
+		if err != nil {
+			return nil, err
+		}
+		deals[int(index)] = deal
+	}
+	return deals, nil
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
+
+// region remoteReshare ////////////////////////////////////////////////////////
+
+type remoteReshare struct {
+	*remoteSession
+}
+
+var _ Reshare = &remoteReshare{}
+
+func (rr *remoteReshare) Deal(ks KeySet) ([]kyber.Point, [][]byte, error) {
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := rr.call(remoteOpReshareDeal, writeKeySet(ks))
+	if err != nil {
+		return nil, nil, err
+	}
+	commits, err := readNilablePoints(r, suite)
+	if err != nil || commits == nil {
+		return nil, nil, err
+	}
+	var count uint16
+	if err := util.ReadUint16(r, &count); err != nil {
+		return nil, nil, err
+	}
+	encryptedSubShares := make([][]byte, count)
+	for i := range encryptedSubShares {
+		if encryptedSubShares[i], err = util.ReadBytes16(r); err != nil {
+			return nil, nil, err
+		}
+		if len(encryptedSubShares[i]) == 0 {
+			encryptedSubShares[i] = nil // The own sub-share.
+		}
+	}
+	return commits, encryptedSubShares, nil
+}
+
+func (rr *remoteReshare) AcceptDeal(ks KeySet, dealerIdx uint16, commits []kyber.Point, encryptedSubShare []byte) error {
+	_, err := rr.call(remoteOpReshareAcceptDeal, func(w io.Writer) error {
+		if err := util.WriteByte(w, byte(ks)); err != nil {
+			return err
+		}
+		if err := util.WriteUint16(w, dealerIdx); err != nil {
+			return err
+		}
+		if err := tcrypto.WritePoints(w, commits); err != nil {
+			return err
+		}
+		return util.WriteBytes16(w, encryptedSubShare)
+	})
+	return err
+}
+
+func (rr *remoteReshare) Recover(dealers []uint16) (tcrypto.DKShare, error) {
+	resp, err := rr.signer.call(remoteOpReshareRecover, rr.id, func(w io.Writer) error {
+		if err := util.WriteUint16(w, uint16(len(dealers))); err != nil {
+			return err
+		}
+		for _, dealer := range dealers {
+			if err := util.WriteUint16(w, dealer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newDKShareRef(resp, rr.signer)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
+
+// region remoteDSS ////////////////////////////////////////////////////////////
+
+type remoteDSS struct {
+	*remoteSession
+}
+
+var _ DSS = &remoteDSS{}
+
+func (d *remoteDSS) Input() (*DSSResult, error) {
+	r, err := d.call(remoteOpDSSInput, nil)
+	if err != nil {
+		return nil, err
+	}
+	return readDSSResult(r)
+}
+
+func (d *remoteDSS) Message(sender gpa.NodeID, data []byte) (*DSSResult, error) {
+	r, err := d.call(remoteOpDSSMessage, func(w io.Writer) error {
+		if err := util.WriteString16(w, string(sender)); err != nil {
+			return err
+		}
+		return util.WriteBytes32(w, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readDSSResult(r)
+}
+
+func (d *remoteDSS) Decided(decidedIndexProposals map[gpa.NodeID][]int, messageToSign []byte) (*DSSResult, error) {
+	r, err := d.call(remoteOpDSSDecided, func(w io.Writer) error {
+		if err := writeDecidedIndexProposals(w, decidedIndexProposals); err != nil {
+			return err
+		}
+		return util.WriteBytes32(w, messageToSign)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readDSSResult(r)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////
@@ -0,0 +1,533 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net"
+	"os"
+
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	"golang.org/x/xerrors"
+)
+
+// ServeConfig restricts the clients of a served signer.
+type ServeConfig struct {
+	// The clients must present this token, if it is set.
+	Token []byte
+	// The unix users allowed to connect, the owner of the serving process, if empty.
+	AllowedUIDs []uint32
+	// The resharing deals encrypt the sub-shares of the served key shares for the
+	// new group of nodes chosen by the client. A compromised client could choose
+	// the nodes it controls, so the deals are only made, if this is set.
+	AllowReshare bool
+	// Called with the reason, when a client is rejected.
+	OnReject func(reason error)
+}
+
+// Serve exposes the signer to the remote signer clients connecting via
+// the unix socket listener. It returns when the listener is closed. On the
+// platforms, where the peer credentials of a socket are not available, the
+// token is mandatory.
+func Serve(listener net.Listener, signer Signer, config *ServeConfig) error {
+	if !peerCredentialsSupported && len(config.Token) == 0 {
+		return xerrors.New("the clients cannot be identified on this platform, a token is required")
+	}
+	allowedUIDs := config.AllowedUIDs
+	if len(allowedUIDs) == 0 {
+		allowedUIDs = []uint32{uint32(os.Getuid())}
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			if err := checkRemoteClient(conn, config.Token, allowedUIDs); err != nil {
+				if config.OnReject != nil {
+					config.OnReject(err)
+				}
+				conn.Close()
+				return
+			}
+			sc := &servedConn{
+				signer:       signer,
+				allowReshare: config.AllowReshare,
+				sessions:     map[uint32]Session{},
+				dsss:         map[uint32]DSS{},
+			}
+			sc.serve(conn)
+		}()
+	}
+}
+
+// checkRemoteClient checks the credentials of the peer process and its token,
+// and answers the token frame.
+func checkRemoteClient(conn net.Conn, token []byte, allowedUIDs []uint32) error {
+	err := checkPeerCredentials(conn, allowedUIDs)
+	if err == nil {
+		var clientToken []byte
+		if clientToken, err = readRemoteFrame(conn); err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(clientToken, token) != 1 {
+			err = xerrors.New("invalid token")
+		}
+	}
+	if err != nil {
+		_ = writeRemoteFrame(conn, []byte{remoteStatusError})
+		return err
+	}
+	return writeRemoteFrame(conn, []byte{remoteStatusOK})
+}
+
+// servedConn keeps the sessions opened by a client.
+type servedConn struct {
+	signer       Signer
+	allowReshare bool
+	sessions     map[uint32]Session // DKG and resharing.
+	dsss         map[uint32]DSS
+	lastID       uint32
+}
+
+func (sc *servedConn) serve(conn net.Conn) {
+	defer conn.Close()
+	defer sc.closeSessions()
+	for {
+		req, err := readRemoteFrame(conn)
+		if err != nil {
+			return
+		}
+		status, payload := sc.serveRequest(req)
+		var resp bytes.Buffer
+		if err := util.WriteByte(&resp, status); err != nil {
+			return
+		}
+		if err := util.WriteBytes32(&resp, payload); err != nil {
+			return
+		}
+		if err := writeRemoteFrame(conn, resp.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (sc *servedConn) closeSessions() {
+	for _, s := range sc.sessions {
+		_ = s.Close()
+	}
+	for _, d := range sc.dsss {
+		_ = d.Close()
+	}
+}
+
+func (sc *servedConn) serveRequest(req []byte) (byte, []byte) {
+	r := bytes.NewReader(req)
+	op, err := util.ReadByte(r)
+	if err != nil {
+		return remoteStatusError, []byte(err.Error())
+	}
+	var sessionID uint32
+	if err := util.ReadUint32(r, &sessionID); err != nil {
+		return remoteStatusError, []byte(err.Error())
+	}
+	w := &bytes.Buffer{}
+	err = sc.handle(op, sessionID, r, w)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		return remoteStatusNotFound, nil
+	}
+	if err != nil {
+		return remoteStatusError, []byte(err.Error())
+	}
+	return remoteStatusOK, w.Bytes()
+}
+
+func (sc *servedConn) handle(op byte, sessionID uint32, r io.Reader, w *bytes.Buffer) error {
+	switch op {
+	case remoteOpPublicKey:
+		_, err := w.Write(sc.signer.GetPublicKey().AsBytes())
+		return err
+	case remoteOpSign:
+		data, err := util.ReadBytes32(r)
+		if err != nil {
+			return err
+		}
+		signature, err := sc.signer.Sign(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(signature)
+		return err
+	case remoteOpLoadDKShare:
+		sharedAddress, err := readAddress(r)
+		if err != nil {
+			return err
+		}
+		dkShare, err := sc.signer.LoadDKShare(sharedAddress)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(dkShare.PublicBytes())
+		return err
+	case remoteOpBLSSignShare:
+		sharedAddress, err := readAddress(r)
+		if err != nil {
+			return err
+		}
+		data, err := util.ReadBytes32(r)
+		if err != nil {
+			return err
+		}
+		sigShare, err := sc.signer.BLSSignShare(sharedAddress, data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(sigShare)
+		return err
+	case remoteOpNewDKG, remoteOpNewReshare, remoteOpNewDSS:
+		return sc.handleNew(op, r, w)
+	case remoteOpSessionBLSSign, remoteOpSessionCommit, remoteOpSessionClose:
+		return sc.handleSession(op, sessionID, r, w)
+	case remoteOpDSSInput, remoteOpDSSMessage, remoteOpDSSDecided:
+		return sc.handleDSS(op, sessionID, r, w)
+	case remoteOpReshareDeal, remoteOpReshareAcceptDeal, remoteOpReshareRecover:
+		return sc.handleReshare(op, sessionID, r, w)
+	default:
+		return sc.handleDKG(op, sessionID, r, w)
+	}
+}
+
+func (sc *servedConn) handleNew(op byte, r io.Reader, w *bytes.Buffer) error {
+	sc.lastID++
+	id := sc.lastID
+	switch op {
+	case remoteOpNewDKG:
+		peerPubs, err := readPubKeys(r)
+		if err != nil {
+			return err
+		}
+		var threshold uint16
+		if err := util.ReadUint16(r, &threshold); err != nil {
+			return err
+		}
+		dkg, err := sc.signer.NewDKG(peerPubs, threshold)
+		if err != nil {
+			return err
+		}
+		sc.sessions[id] = dkg
+	case remoteOpNewReshare:
+		params, err := readReshareParams(r)
+		if err != nil {
+			return err
+		}
+		reshare, err := sc.signer.NewReshare(params)
+		if err != nil {
+			return err
+		}
+		sc.sessions[id] = reshare
+	case remoteOpNewDSS:
+		sharedAddress, err := readAddress(r)
+		if err != nil {
+			return err
+		}
+		key, err := util.ReadString16(r)
+		if err != nil {
+			return err
+		}
+		var index uint32
+		if err := util.ReadUint32(r, &index); err != nil {
+			return err
+		}
+		d, err := sc.signer.NewDSS(sharedAddress, key, int(index))
+		if err != nil {
+			return err
+		}
+		sc.dsss[id] = d
+	}
+	return util.WriteUint32(w, id)
+}
+
+func (sc *servedConn) handleSession(op byte, sessionID uint32, r io.Reader, w *bytes.Buffer) error {
+	if op == remoteOpSessionClose {
+		if s, ok := sc.sessions[sessionID]; ok {
+			delete(sc.sessions, sessionID)
+			return s.Close()
+		}
+		if d, ok := sc.dsss[sessionID]; ok {
+			delete(sc.dsss, sessionID)
+			return d.Close()
+		}
+		return nil
+	}
+	s, ok := sc.sessions[sessionID]
+	if !ok {
+		return xerrors.Errorf("unknown session: %v", sessionID)
+	}
+	switch op {
+	case remoteOpSessionBLSSign:
+		data, err := util.ReadBytes32(r)
+		if err != nil {
+			return err
+		}
+		signature, err := s.BLSSign(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(signature)
+		return err
+	default: // remoteOpSessionCommit
+		edPublicShares, err := tcrypto.ReadPoints(r, tcrypto.DefaultEd25519Suite())
+		if err != nil {
+			return err
+		}
+		blsPublicShares, err := tcrypto.ReadPoints(r, tcrypto.DefaultBLSSuite())
+		if err != nil {
+			return err
+		}
+		return s.Commit(edPublicShares, blsPublicShares)
+	}
+}
+
+//nolint:gocyclo
+func (sc *servedConn) handleDKG(op byte, sessionID uint32, r io.Reader, w *bytes.Buffer) error {
+	dkg, ok := sc.sessions[sessionID].(DKG)
+	if !ok {
+		return xerrors.Errorf("unknown DKG session: %v", sessionID)
+	}
+	if op == remoteOpDKGDKShare {
+		dkShare, err := dkg.DKShare()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(dkShare.PublicBytes())
+		return err
+	}
+	ksByte, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	ks := KeySet(ksByte)
+	suite, err := keySetSuite(ks)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case remoteOpDKGDeals:
+		deals, err := dkg.Deals(ks)
+		if err != nil {
+			return err
+		}
+		return writeDeals(w, deals)
+	case remoteOpDKGProcessDeal:
+		deal, err := tcrypto.ReadRabinDeal(r, tcrypto.DefaultEd25519Suite())
+		if err != nil {
+			return err
+		}
+		response, err := dkg.ProcessDeal(ks, deal)
+		if err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinResponses(w, []*rabin_dkg.Response{response})
+	case remoteOpDKGProcessResponse:
+		responses, err := tcrypto.ReadRabinResponses(r)
+		if err != nil {
+			return err
+		}
+		justifications := []*rabin_dkg.Justification{}
+		for _, response := range responses {
+			justification, err := dkg.ProcessResponse(ks, response)
+			if err != nil {
+				return err
+			}
+			if justification != nil {
+				justifications = append(justifications, justification)
+			}
+		}
+		return tcrypto.WriteRabinJustifications(w, justifications)
+	case remoteOpDKGProcessJustification:
+		justifications, err := tcrypto.ReadRabinJustifications(r, suite)
+		if err != nil {
+			return err
+		}
+		for _, justification := range justifications {
+			if err := dkg.ProcessJustification(ks, justification); err != nil {
+				return err
+			}
+		}
+		return nil
+	case remoteOpDKGQUAL:
+		qual, err := dkg.QUAL(ks)
+		if err != nil {
+			return err
+		}
+		return writeInts(w, qual)
+	case remoteOpDKGSecretCommits:
+		secretCommits, err := dkg.SecretCommits(ks)
+		if err != nil {
+			return err
+		}
+		return tcrypto.WriteRabinSecretCommits(w, secretCommits)
+	case remoteOpDKGProcessSecretCommits:
+		secretCommits, err := tcrypto.ReadRabinSecretCommits(r, suite)
+		if err != nil {
+			return err
+		}
+		complaintCommits, err := dkg.ProcessSecretCommits(ks, secretCommits)
+		if err != nil {
+			return err
+		}
+		if complaintCommits == nil {
+			return tcrypto.WriteRabinComplaintCommits(w, []*rabin_dkg.ComplaintCommits{})
+		}
+		return tcrypto.WriteRabinComplaintCommits(w, []*rabin_dkg.ComplaintCommits{complaintCommits})
+	case remoteOpDKGProcessComplaintCommits:
+		complaintCommits, err := tcrypto.ReadRabinComplaintCommits(r, suite)
+		if err != nil {
+			return err
+		}
+		reconstructCommits := []*rabin_dkg.ReconstructCommits{}
+		for _, cc := range complaintCommits {
+			rc, err := dkg.ProcessComplaintCommits(ks, cc)
+			if err != nil {
+				return err
+			}
+			if rc != nil {
+				reconstructCommits = append(reconstructCommits, rc)
+			}
+		}
+		return tcrypto.WriteRabinReconstructCommits(w, reconstructCommits)
+	case remoteOpDKGProcessReconstructCommits:
+		reconstructCommits, err := tcrypto.ReadRabinReconstructCommits(r, suite)
+		if err != nil {
+			return err
+		}
+		for _, rc := range reconstructCommits {
+			if err := dkg.ProcessReconstructCommits(ks, rc); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return xerrors.Errorf("unknown operation: %v", op)
+	}
+}
+
+func (sc *servedConn) handleReshare(op byte, sessionID uint32, r io.Reader, w *bytes.Buffer) error {
+	reshare, ok := sc.sessions[sessionID].(Reshare)
+	if !ok {
+		return xerrors.Errorf("unknown resharing session: %v", sessionID)
+	}
+	switch op {
+	case remoteOpReshareDeal:
+		if !sc.allowReshare {
+			return xerrors.New("resharing of the keys is not allowed by the signer")
+		}
+		ksByte, err := util.ReadByte(r)
+		if err != nil {
+			return err
+		}
+		commits, encryptedSubShares, err := reshare.Deal(KeySet(ksByte))
+		if err != nil {
+			return err
+		}
+		if err := writeNilablePoints(w, commits); err != nil || commits == nil {
+			return err
+		}
+		if err := util.WriteUint16(w, uint16(len(encryptedSubShares))); err != nil {
+			return err
+		}
+		for i := range encryptedSubShares {
+			if err := util.WriteBytes16(w, encryptedSubShares[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case remoteOpReshareAcceptDeal:
+		ksByte, err := util.ReadByte(r)
+		if err != nil {
+			return err
+		}
+		suite, err := keySetSuite(KeySet(ksByte))
+		if err != nil {
+			return err
+		}
+		var dealerIdx uint16
+		if err := util.ReadUint16(r, &dealerIdx); err != nil {
+			return err
+		}
+		commits, err := tcrypto.ReadPoints(r, suite)
+		if err != nil {
+			return err
+		}
+		encryptedSubShare, err := util.ReadBytes16(r)
+		if err != nil {
+			return err
+		}
+		return reshare.AcceptDeal(KeySet(ksByte), dealerIdx, commits, encryptedSubShare)
+	default: // remoteOpReshareRecover
+		var count uint16
+		if err := util.ReadUint16(r, &count); err != nil {
+			return err
+		}
+		dealers := make([]uint16, count)
+		for i := range dealers {
+			if err := util.ReadUint16(r, &dealers[i]); err != nil {
+				return err
+			}
+		}
+		dkShare, err := reshare.Recover(dealers)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(dkShare.PublicBytes())
+		return err
+	}
+}
+
+func (sc *servedConn) handleDSS(op byte, sessionID uint32, r io.Reader, w *bytes.Buffer) error {
+	d, ok := sc.dsss[sessionID]
+	if !ok {
+		return xerrors.Errorf("unknown DSS session: %v", sessionID)
+	}
+	var result *DSSResult
+	var err error
+	switch op {
+	case remoteOpDSSInput:
+		result, err = d.Input()
+	case remoteOpDSSMessage:
+		var sender string
+		var data []byte
+		if sender, err = util.ReadString16(r); err != nil {
+			return err
+		}
+		if data, err = util.ReadBytes32(r); err != nil {
+			return err
+		}
+		result, err = d.Message(gpa.NodeID(sender), data)
+	default: // remoteOpDSSDecided
+		var decidedIndexProposals map[gpa.NodeID][]int
+		var messageToSign []byte
+		if decidedIndexProposals, err = readDecidedIndexProposals(r); err != nil {
+			return err
+		}
+		if messageToSign, err = util.ReadBytes32(r); err != nil {
+			return err
+		}
+		result, err = d.Decided(decidedIndexProposals, messageToSign)
+	}
+	if err != nil {
+		return err
+	}
+	return writeDSSResult(w, result)
+}
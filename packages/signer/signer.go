@@ -0,0 +1,191 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signer keeps the secret keys of a node and signs with them. The
+// secrets are the node identity key and the distributed key shares of the
+// committees, they are kept in a keystore.KeyStore and never leave the signer.
+// The node only gets the public parts of the keys, and asks the signer for the
+// signatures:
+//
+//   - the identity signatures (peering, node ownership certificates, etc.);
+//   - the BLS signature shares (randomness of the consensus);
+//   - the DSS partial signatures (the L1 transactions), the whole DSS protocol
+//     instance is hosted by the signer, the node only relays its messages;
+//   - the distributed key generation and resharing, producing the new shares.
+//
+// The signer can run in the node process (NewLocal), or in a separate process
+// serving it over a unix socket (Serve), in which case the node connects to it
+// with NewRemote. A compromised node can then still ask the signer to sign,
+// but it cannot obtain the keys.
+package signer
+
+import (
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/chain/dss"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/gpa"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"golang.org/x/xerrors"
+)
+
+// TypeRemote selects the remote signer in the Config, the other types
+// are the types of the key store used by the local signer.
+const TypeRemote = "remote"
+
+// TokenEnvVar is the environment variable to take the token authenticating
+// the node to the remote signer from.
+const TokenEnvVar = "WASP_KEYSTORE_TOKEN"
+
+// KeySet selects one of the keys generated by a DKG, the numbering
+// is the same as in the dkg package.
+type KeySet byte
+
+const (
+	KeySetEd25519 KeySet = iota // For the L1 signatures (DSS).
+	KeySetBLS                   // For the randomness (BLS).
+)
+
+// Identity is the part of the Signer authenticating the node.
+type Identity interface {
+	GetPublicKey() *cryptolib.PublicKey
+	Sign(data []byte) ([]byte, error)
+}
+
+// Signer holds the node identity and the distributed key shares.
+type Signer interface {
+	Identity
+	//
+	// LoadDKShare returns the public part of the stored key share. The BLS signature
+	// shares made with it are delegated to the signer, the DSS signatures are made
+	// with NewDSS. Returns keystore.ErrKeyNotFound, if there is no such share.
+	LoadDKShare(sharedAddress iotago.Address) (tcrypto.DKShare, error)
+	BLSSignShare(sharedAddress iotago.Address, data []byte) (tbls.SigShare, error)
+	//
+	// NewDKG starts the generation of a new distributed key.
+	NewDKG(peerPubs []*cryptolib.PublicKey, threshold uint16) (DKG, error)
+	// NewReshare starts the resharing of an existing distributed key.
+	NewReshare(params *ReshareParams) (Reshare, error)
+	// NewDSS starts a DSS protocol instance signing with the share of the key.
+	NewDSS(sharedAddress iotago.Address, key string, index int) (DSS, error)
+	Close() error
+}
+
+// Session is the common part of the procedures producing a new key share.
+// The share is stored by the signer, when it is committed.
+type Session interface {
+	// BLSSign signs the data with the new BLS key share (non-threshold variant),
+	// the nodes use it to prove they hold the share.
+	BLSSign(data []byte) ([]byte, error)
+	// Commit sets the public shares of all the nodes and stores the new key share.
+	Commit(edPublicShares, blsPublicShares []kyber.Point) error
+	Close() error
+}
+
+// DKG runs the Rabin DKG of the key sets, the methods follow the
+// rabin_dkg.DistKeyGenerator. Only DKShare is used, if there is a single node.
+type DKG interface {
+	Session
+	Deals(ks KeySet) (map[int]*rabin_dkg.Deal, error)
+	ProcessDeal(ks KeySet, deal *rabin_dkg.Deal) (*rabin_dkg.Response, error)
+	ProcessResponse(ks KeySet, response *rabin_dkg.Response) (*rabin_dkg.Justification, error)
+	ProcessJustification(ks KeySet, justification *rabin_dkg.Justification) error
+	// QUAL closes the deal phase and returns the qualified nodes. It fails, if this node is not certified.
+	QUAL(ks KeySet) ([]int, error)
+	SecretCommits(ks KeySet) (*rabin_dkg.SecretCommits, error)
+	ProcessSecretCommits(ks KeySet, secretCommits *rabin_dkg.SecretCommits) (*rabin_dkg.ComplaintCommits, error)
+	ProcessComplaintCommits(ks KeySet, complaintCommits *rabin_dkg.ComplaintCommits) (*rabin_dkg.ReconstructCommits, error)
+	ProcessReconstructCommits(ks KeySet, reconstructCommits *rabin_dkg.ReconstructCommits) error
+	// DKShare completes the procedure and returns the public part of the new key share,
+	// only the public share of this node is known at this point.
+	DKShare() (tcrypto.DKShare, error)
+}
+
+// ReshareParams describe the key to reshare and the new group of nodes.
+type ReshareParams struct {
+	SharedAddress   iotago.Address
+	OldPeerPubs     []*cryptolib.PublicKey
+	OldThreshold    uint16
+	EdOldPubShares  []kyber.Point
+	BLSOldPubShares []kyber.Point
+	NewPeerPubs     []*cryptolib.PublicKey
+	NewThreshold    uint16
+}
+
+// Reshare moves the key share of this node to a new group of nodes (as a dealer),
+// and recovers the new key share of this node (as a new member).
+type Reshare interface {
+	Session
+	// Deal produces the deal of this node, if it is in the old group. The sub-shares are
+	// encrypted for the new members, the own sub-share is accepted without encryption.
+	Deal(ks KeySet) (commits []kyber.Point, encryptedSubShares [][]byte, err error)
+	AcceptDeal(ks KeySet, dealerIdx uint16, commits []kyber.Point, encryptedSubShare []byte) error
+	// Recover recovers the new key share from the accepted deals of the dealers.
+	Recover(dealers []uint16) (tcrypto.DKShare, error)
+}
+
+// DSS is a DSS protocol instance hosted by the signer. The node relays the
+// messages of the instance to its peers. The messages are authenticated by
+// the signers, so the node cannot inject messages of the other nodes.
+type DSS interface {
+	Input() (*DSSResult, error)
+	Message(sender gpa.NodeID, data []byte) (*DSSResult, error)
+	Decided(decidedIndexProposals map[gpa.NodeID][]int, messageToSign []byte) (*DSSResult, error)
+	Close() error
+}
+
+// DSSResult contains the messages to send and the output of the instance, if any.
+type DSSResult struct {
+	Messages []*DSSMessage
+	Output   *dss.Output
+}
+
+type DSSMessage struct {
+	Recipient gpa.NodeID
+	Data      []byte
+}
+
+// Config describes the signer to use.
+type Config struct {
+	Type       string // TypeRemote or one of the key store types.
+	File       string // For keystore.TypeFile.
+	Passphrase []byte // For keystore.TypeFile.
+	Socket     string // For TypeRemote.
+	Token      []byte // For TypeRemote, if the served signer requires it.
+}
+
+// New creates a signer according to the configuration. The store is the
+// registry database, it is used by the keystore.TypeDB key store.
+func New(config *Config, store kvstore.KVStore, log *logger.Logger) (Signer, error) {
+	if config.Type == TypeRemote {
+		return NewRemote(config.Socket, config.Token)
+	}
+	keyStore, err := keystore.New(&keystore.Config{
+		Type:       config.Type,
+		File:       config.File,
+		Passphrase: config.Passphrase,
+	}, store)
+	if err != nil {
+		return nil, err
+	}
+	if config.Type == keystore.TypeFile {
+		// The identity would be generated anew otherwise.
+		inDB, err := store.Has(dbKeyForNodeIdentity())
+		if err != nil {
+			return nil, err
+		}
+		inKeyStore, err := keyStore.Has(dbKeyForNodeIdentity())
+		if err != nil {
+			return nil, err
+		}
+		if inDB && !inKeyStore {
+			return nil, xerrors.New("node identity is stored in the database, but not in the key store, migrate the secrets to the key store first")
+		}
+	}
+	return NewLocal(keyStore, log)
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/dkg"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/testutil/testpeers"
+	"github.com/stretchr/testify/require"
+)
+
+func testIdentity(t *testing.T, s signer.Signer) {
+	data := []byte("some data to sign")
+	signature, err := s.Sign(data)
+	require.NoError(t, err)
+	require.True(t, s.GetPublicKey().Verify(data, signature))
+
+	_, err = s.LoadDKShare(cryptolib.NewKeyPair().Address())
+	require.ErrorIs(t, err, keystore.ErrKeyNotFound)
+}
+
+func TestLocalSigner(t *testing.T) {
+	identity := cryptolib.NewKeyPair()
+	s := testpeers.NewSigner(t, identity)
+	require.True(t, identity.GetPublicKey().Equals(s.GetPublicKey()))
+	testIdentity(t, s)
+}
+
+func serveTestSigner(t *testing.T, s signer.Signer, config *signer.ServeConfig) string {
+	socket := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- signer.Serve(listener, s, config) }()
+	t.Cleanup(func() {
+		require.NoError(t, listener.Close())
+		require.NoError(t, <-served)
+	})
+	return socket
+}
+
+func TestRemoteSigner(t *testing.T) {
+	identity := cryptolib.NewKeyPair()
+	socket := serveTestSigner(t, testpeers.NewSigner(t, identity), &signer.ServeConfig{Token: []byte("token")})
+
+	s, err := signer.NewRemote(socket, []byte("token"))
+	require.NoError(t, err)
+	require.True(t, identity.GetPublicKey().Equals(s.GetPublicKey()))
+	testIdentity(t, s)
+	require.NoError(t, s.Close())
+}
+
+func TestRemoteSignerAuth(t *testing.T) {
+	rejected := make(chan error, 2)
+	socket := serveTestSigner(t, testpeers.NewSigner(t, cryptolib.NewKeyPair()), &signer.ServeConfig{
+		Token:       []byte("token"),
+		AllowedUIDs: []uint32{uint32(os.Getuid()) + 1},
+		OnReject:    func(reason error) { rejected <- reason },
+	})
+	_, err := signer.NewRemote(socket, []byte("token"))
+	require.Error(t, err)
+	require.Error(t, <-rejected)
+
+	socket = serveTestSigner(t, testpeers.NewSigner(t, cryptolib.NewKeyPair()), &signer.ServeConfig{
+		Token:    []byte("token"),
+		OnReject: func(reason error) { rejected <- reason },
+	})
+	_, err = signer.NewRemote(socket, []byte("wrong token"))
+	require.Error(t, err)
+	require.Error(t, <-rejected)
+	_, err = signer.NewRemote(socket, nil)
+	require.Error(t, err)
+	require.Error(t, <-rejected)
+}
+
+// TestRemoteDKG checks, if the nodes can generate a key with the remote signers,
+// sign with its shares, and cannot reshare it, unless the signers allow that.
+func TestRemoteDKG(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+	var peerCount uint16 = 4
+	var threshold uint16 = 3
+	peerNetIDs, peerIdentities := testpeers.SetupKeys(peerCount)
+	networkProviders, networkCloser := testpeers.SetupNet(peerNetIDs, peerIdentities, testutil.NewPeeringNetReliable(log), log)
+	defer networkCloser.Close()
+	signers := make([]signer.Signer, peerCount)
+	dkgNodes := make([]*dkg.Node, peerCount)
+	for i := range peerIdentities {
+		socket := serveTestSigner(t, testpeers.NewSigner(t, peerIdentities[i]), &signer.ServeConfig{})
+		var err error
+		signers[i], err = signer.NewRemote(socket, nil)
+		require.NoError(t, err)
+		defer signers[i].Close()
+		dkgNodes[i], err = dkg.NewNode(signers[i], networkProviders[i], log.Named(peerNetIDs[i]))
+		require.NoError(t, err)
+	}
+	dkShare, err := dkgNodes[0].GenerateDistributedKey(testpeers.PublicKeys(peerIdentities), threshold, 1*time.Second, 2*time.Second, 100*time.Second)
+	require.NoError(t, err)
+
+	dataToSign := []byte("some data to sign")
+	blsPartSigs := make([][]byte, peerCount)
+	var aggrDks tcrypto.DKShare
+	for i := range signers {
+		dks, err := signers[i].LoadDKShare(dkShare.GetAddress())
+		require.NoError(t, err)
+		if i == 0 {
+			aggrDks = dks
+		}
+		blsPartSigs[i], err = dks.BLSSignShare(dataToSign)
+		require.NoError(t, err)
+	}
+	blsAggrSig, err := aggrDks.BLSRecoverMasterSignature(blsPartSigs, dataToSign)
+	require.NoError(t, err)
+	require.NoError(t, aggrDks.BLSVerifyMasterSignature(dataToSign, blsAggrSig.Signature[:]))
+
+	_, err = dkgNodes[0].ReshareDistributedKey(dkShare.GetAddress(), testpeers.PublicKeys(peerIdentities), threshold, 1*time.Second, 2*time.Second, 10*time.Second)
+	require.Error(t, err)
+}
+
+func TestNewFileKeyStoreIdentity(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	config := &signer.Config{
+		Type:       keystore.TypeFile,
+		File:       filepath.Join(t.TempDir(), "keystore.json"),
+		Passphrase: []byte("passphrase"),
+	}
+	store := mapdb.NewMapDB()
+
+	s, err := signer.New(config, store, log)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	empty := true
+	require.NoError(t, store.IterateKeys(nil, func(_ []byte) bool {
+		empty = false
+		return false
+	}))
+	require.True(t, empty, "the identity must not be stored in the database")
+
+	reopened, err := signer.New(config, store, log)
+	require.NoError(t, err)
+	require.True(t, s.GetPublicKey().Equals(reopened.GetPublicKey()))
+}
+
+func TestNewIdentityNotMigrated(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	_, err := signer.New(&signer.Config{Type: keystore.TypeDB}, store, log)
+	require.NoError(t, err)
+
+	_, err = signer.New(&signer.Config{
+		Type:       keystore.TypeFile,
+		File:       filepath.Join(t.TempDir(), "keystore.json"),
+		Passphrase: []byte("passphrase"),
+	}, store, log)
+	require.Error(t, err)
+}
@@ -254,10 +254,14 @@ func (ch *Chain) GetInfo() (*isc.ChainID, isc.AgentID, map[isc.Hname]*root.Contr
 	chainOwnerID, err := codec.DecodeAgentID(res.MustGet(governance.VarChainOwnerID))
 	require.NoError(ch.Env.T, err)
 
-	res, err = ch.CallView(root.Contract.Name, root.ViewGetContractRecords.Name)
-	require.NoError(ch.Env.T, err)
-
-	contracts, err := root.DecodeContractRegistry(collections.NewMapReadOnly(res, root.StateVarContractRegistry))
+	contracts := make(map[isc.Hname]*root.ContractRecord)
+	err = ch.CallViewAllPages(root.Contract.Name, root.ViewGetContractRecords.Name, nil, func(page dict.Dict) {
+		recs, err := root.DecodeContractRegistry(collections.NewMapReadOnly(page, root.StateVarContractRegistry))
+		require.NoError(ch.Env.T, err)
+		for hname, rec := range recs {
+			contracts[hname] = rec
+		}
+	})
 	require.NoError(ch.Env.T, err)
 	return chainID, chainOwnerID, contracts
 }
@@ -294,15 +298,15 @@ func eventsFromViewResult(t TestContext, viewResult dict.Dict) []string {
 
 // GetEventsForContract calls the view in the  'blocklog' core smart contract to retrieve events for a given smart contract.
 func (ch *Chain) GetEventsForContract(name string) ([]string, error) {
-	viewResult, err := ch.CallView(
-		blocklog.Contract.Name, blocklog.ViewGetEventsForContract.Name,
-		blocklog.ParamContractHname, isc.Hn(name),
-	)
+	ret := make([]string, 0)
+	params := dict.Dict{blocklog.ParamContractHname: isc.Hn(name).Bytes()}
+	err := ch.CallViewAllPages(blocklog.Contract.Name, blocklog.ViewGetEventsForContract.Name, params, func(page dict.Dict) {
+		ret = append(ret, eventsFromViewResult(ch.Env.T, page)...)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return eventsFromViewResult(ch.Env.T, viewResult), nil
+	return ret, nil
 }
 
 // GetEventsForRequest calls the view in the  'blocklog' core smart contract to retrieve events for a given request.
@@ -536,6 +540,41 @@ func (ch *Chain) GetAllowedStateControllerAddresses() []iotago.Address {
 	return ret
 }
 
+// AddGasSponsor allows the account to sponsor the gas fees of the off-ledger requests
+func (ch *Chain) AddGasSponsor(agentID isc.AgentID, keyPair *cryptolib.KeyPair) error {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncAddGasSponsor.Name,
+		governance.ParamGasSponsor, agentID,
+	).WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// RemoveGasSponsor removes the account from the list of the allowed gas sponsors
+func (ch *Chain) RemoveGasSponsor(agentID isc.AgentID, keyPair *cryptolib.KeyPair) error {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncRemoveGasSponsor.Name,
+		governance.ParamGasSponsor, agentID,
+	).WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// GetGasSponsors returns the accounts allowed to sponsor the gas fees
+func (ch *Chain) GetGasSponsors() []isc.AgentID {
+	res, err := ch.CallView(coreutil.CoreContractGovernance, governance.ViewGetGasSponsors.Name)
+	require.NoError(ch.Env.T, err)
+	if len(res) == 0 {
+		return nil
+	}
+	ret := make([]isc.AgentID, 0)
+	arr := collections.NewArray16ReadOnly(res, governance.ParamGasSponsors)
+	for i := uint16(0); i < arr.MustLen(); i++ {
+		a, err := codec.DecodeAgentID(arr.MustGetAt(i))
+		require.NoError(ch.Env.T, err)
+		ret = append(ret, a)
+	}
+	return ret
+}
+
 // RotateStateController rotates the chain to the new controller address.
 // We assume self-governed chain here.
 // Mostly use for the testing of committee rotation logic, otherwise not much needed for smart contract testing
@@ -7,7 +7,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/evm/evmtypes"
+	"github.com/iotaledger/wasp/packages/evm/evmutil"
 	"github.com/iotaledger/wasp/packages/evm/jsonrpc"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/codec"
@@ -40,6 +42,22 @@ func (b *jsonRPCSoloBackend) ISCCallView(scName, funName string, args dict.Dict)
 	return b.Chain.CallView(scName, funName, args)
 }
 
+func (b *jsonRPCSoloBackend) ISCMempoolRequests() []isc.Request {
+	return b.Chain.mempool.Requests()
+}
+
+func (b *jsonRPCSoloBackend) ISCStateProofs(keys [][]byte) (uint32, []*evmutil.StateProof, error) {
+	blockIndex, values, proofs, err := b.Chain.GetStateProofs(keys)
+	if err != nil {
+		return 0, nil, err
+	}
+	return blockIndex, evmutil.NewStateProofs(keys, values, proofs), nil
+}
+
+func (b *jsonRPCSoloBackend) EVMIndex() *evmindex.Index {
+	return b.Chain.evmIndex
+}
+
 func (b *jsonRPCSoloBackend) BaseToken() *parameters.BaseToken {
 	return b.baseToken
 }
@@ -21,15 +21,13 @@ import (
 
 // L2Accounts returns all accounts on the chain with non-zero balances
 func (ch *Chain) L2Accounts() []isc.AgentID {
-	d, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccounts.Name)
-	require.NoError(ch.Env.T, err)
-	keys := d.KeysSorted()
-	ret := make([]isc.AgentID, 0, len(keys)-1)
-	for _, key := range keys {
-		aid, err := codec.DecodeAgentID([]byte(key))
+	ret := make([]isc.AgentID, 0)
+	err := ch.CallViewAllPages(accounts.Contract.Name, accounts.ViewAccounts.Name, nil, func(page dict.Dict) {
+		accs, err := accounts.DecodeAccounts(page)
 		require.NoError(ch.Env.T, err)
-		ret = append(ret, aid)
-	}
+		ret = append(ret, accs...)
+	})
+	require.NoError(ch.Env.T, err)
 	return ret
 }
 
@@ -80,15 +78,17 @@ func (ch *Chain) L2BaseTokens(agentID isc.AgentID) uint64 {
 
 func (ch *Chain) L2NFTs(agentID isc.AgentID) []iotago.NFTID {
 	ret := make([]iotago.NFTID, 0)
-	res, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccountNFTs.Name, accounts.ParamAgentID, agentID)
+	params := dict.Dict{accounts.ParamAgentID: codec.EncodeAgentID(agentID)}
+	err := ch.CallViewAllPages(accounts.Contract.Name, accounts.ViewAccountNFTs.Name, params, func(page dict.Dict) {
+		nftIDs := collections.NewArray16ReadOnly(page, accounts.ParamNFTIDs)
+		nftLen := nftIDs.MustLen()
+		for i := uint16(0); i < nftLen; i++ {
+			nftID := iotago.NFTID{}
+			copy(nftID[:], nftIDs.MustGetAt(i))
+			ret = append(ret, nftID)
+		}
+	})
 	require.NoError(ch.Env.T, err)
-	nftIDs := collections.NewArray16ReadOnly(res, accounts.ParamNFTIDs)
-	nftLen := nftIDs.MustLen()
-	for i := uint16(0); i < nftLen; i++ {
-		nftID := iotago.NFTID{}
-		copy(nftID[:], nftIDs.MustGetAt(i))
-		ret = append(ret, nftID)
-	}
 	return ret
 }
 
@@ -249,6 +249,112 @@ func (ch *Chain) DestroyFoundry(sn uint32, user *cryptolib.KeyPair) error {
 	return err
 }
 
+// GrantFoundry grants the ownership of the foundry controlled by the user to the grantee
+func (ch *Chain) GrantFoundry(sn uint32, grantee isc.AgentID, user *cryptolib.KeyPair) error {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncGrantFoundry.Name,
+		accounts.ParamFoundrySN, sn,
+		accounts.ParamAgentID, grantee,
+	).WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, user)
+	return err
+}
+
+// ClaimFoundry moves the ownership of the foundry granted to the user to the user
+func (ch *Chain) ClaimFoundry(sn uint32, user *cryptolib.KeyPair) error {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncClaimFoundry.Name,
+		accounts.ParamFoundrySN, sn,
+	).WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, user)
+	return err
+}
+
+// DelegateFoundry gives the delegate the right to mint and burn the tokens of the foundry controlled by the user.
+// A nil cap means unlimited
+func (ch *Chain) DelegateFoundry(sn uint32, delegate isc.AgentID, mintCap, burnCap *big.Int, user *cryptolib.KeyPair) error {
+	par := dict.Dict{
+		accounts.ParamFoundrySN: codec.EncodeUint32(sn),
+		accounts.ParamAgentID:   codec.EncodeAgentID(delegate),
+	}
+	if mintCap != nil {
+		par.Set(accounts.ParamFoundryMintCap, codec.EncodeBigIntAbs(mintCap))
+	}
+	if burnCap != nil {
+		par.Set(accounts.ParamFoundryBurnCap, codec.EncodeBigIntAbs(burnCap))
+	}
+	req := NewCallParamsFromDict(accounts.Contract.Name, accounts.FuncDelegateFoundry.Name, par).
+		WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, user)
+	return err
+}
+
+// RevokeFoundryDelegation revokes the delegation of the foundry controlled by the user
+func (ch *Chain) RevokeFoundryDelegation(sn uint32, delegate isc.AgentID, user *cryptolib.KeyPair) error {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncRevokeFoundryDelegation.Name,
+		accounts.ParamFoundrySN, sn,
+		accounts.ParamAgentID, delegate,
+	).WithMaxAffordableGasBudget()
+	_, err := ch.PostRequestSync(req, user)
+	return err
+}
+
+// MintNFTOnL2 mints an NFT on L2 to the account of the user. If the collection is not nil, the collection NFT,
+// owned by the user, is the issuer of the new NFT
+func (ch *Chain) MintNFTOnL2(immutableMetadata []byte, collectionID *iotago.NFTID, user *cryptolib.KeyPair) (iotago.NFTID, error) {
+	par := dict.Dict{accounts.ParamNFTImmutableData: immutableMetadata}
+	if collectionID != nil {
+		par.Set(accounts.ParamNFTCollectionID, collectionID[:])
+	}
+	req := NewCallParamsFromDict(accounts.Contract.Name, accounts.FuncMintNFT.Name, par).
+		WithMaxAffordableGasBudget()
+	if user == nil {
+		user = ch.OriginatorPrivateKey
+	}
+	ret := iotago.NFTID{}
+	res, err := ch.PostRequestSync(req, user)
+	if err != nil {
+		return ret, err
+	}
+	copy(ret[:], res.MustGet(accounts.ParamNFTID))
+	return ret, nil
+}
+
+// GetAccountFoundries returns the serial numbers of the foundries owned by the account
+func (ch *Chain) GetAccountFoundries(agentID isc.AgentID) []uint32 {
+	ret := make([]uint32, 0)
+	params := dict.Dict{accounts.ParamAgentID: codec.EncodeAgentID(agentID)}
+	err := ch.CallViewAllPages(accounts.Contract.Name, accounts.ViewAccountFoundries.Name, params, func(page dict.Dict) {
+		collections.NewMapReadOnly(page, accounts.ParamFoundrySNs).MustIterateKeys(func(sn []byte) bool {
+			ret = append(ret, codec.MustDecodeUint32(sn))
+			return true
+		})
+	})
+	require.NoError(ch.Env.T, err)
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+// GetFoundryDelegations returns the grantee of the pending grant of the foundry, if any, and its delegations
+func (ch *Chain) GetFoundryDelegations(sn uint32) (isc.AgentID, map[string]*accounts.FoundryDelegation) {
+	res, err := ch.CallView(accounts.Contract.Name, accounts.ViewFoundryDelegations.Name,
+		accounts.ParamFoundrySN, sn,
+	)
+	require.NoError(ch.Env.T, err)
+	var grantee isc.AgentID
+	if res.MustHas(accounts.ParamFoundryGrantee) {
+		grantee, err = codec.DecodeAgentID(res.MustGet(accounts.ParamFoundryGrantee))
+		require.NoError(ch.Env.T, err)
+	}
+	delegations := make(map[string]*accounts.FoundryDelegation)
+	collections.NewMapReadOnly(res, accounts.ParamFoundryDelegations).MustIterate(func(k, v []byte) bool {
+		agentID, err := isc.AgentIDFromBytes(k)
+		require.NoError(ch.Env.T, err)
+		delegations[agentID.String()], err = accounts.FoundryDelegationFromBytes(v)
+		require.NoError(ch.Env.T, err)
+		return true
+	})
+	return grantee, delegations
+}
+
 func (ch *Chain) MintTokens(foundry, amount interface{}, user *cryptolib.KeyPair) error {
 	req := NewCallParams(accounts.Contract.Name, accounts.FuncFoundryModifySupply.Name,
 		accounts.ParamFoundrySN, toFoundrySN(foundry),
@@ -384,3 +490,82 @@ func (ch *Chain) SendFromL2ToL2AccountNativeTokens(id iotago.NativeTokenID, targ
 	transfer.Assets.AddNativeTokens(id, amount)
 	return ch.SendFromL2ToL2Account(transfer, target, user)
 }
+
+// RegisterMultisigAccount registers the multisig account controlled by the policy. Returns the AgentID of the account
+func (ch *Chain) RegisterMultisigAccount(policy *isc.MultisigPolicy, user *cryptolib.KeyPair) (*isc.MultisigAgentID, error) {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncRegisterMultisigAccount.Name,
+		accounts.ParamMultisigPolicy, policy.Bytes(),
+	).WithMaxAffordableGasBudget()
+	res, err := ch.PostRequestOffLedger(req, user)
+	if err != nil {
+		return nil, err
+	}
+	agentID, err := codec.DecodeAgentID(res.MustGet(accounts.ParamAgentID))
+	require.NoError(ch.Env.T, err)
+	return agentID.(*isc.MultisigAgentID), nil
+}
+
+// GetMultisigPolicy returns the current policy of the multisig account, nil if the account is not registered
+func (ch *Chain) GetMultisigPolicy(agentID *isc.MultisigAgentID) *isc.MultisigPolicy {
+	res, err := ch.CallView(accounts.Contract.Name, accounts.ViewGetMultisigPolicy.Name, accounts.ParamAgentID, agentID)
+	require.NoError(ch.Env.T, err)
+	if !res.MustHas(accounts.ParamMultisigPolicy) {
+		return nil
+	}
+	policy, err := isc.MultisigPolicyFromBytes(res.MustGet(accounts.ParamMultisigPolicy))
+	require.NoError(ch.Env.T, err)
+	return policy
+}
+
+// SetAccountHistoryEnabled turns the recording of the history of the L2 accounts on or off.
+// Must be sent by the chain owner, nil means the originator
+func (ch *Chain) SetAccountHistoryEnabled(enabled bool, user *cryptolib.KeyPair) error {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncSetAccountHistoryEnabled.Name,
+		accounts.ParamHistoryEnabled, enabled,
+	).WithMaxAffordableGasBudget()
+	if user == nil {
+		user = ch.OriginatorPrivateKey
+	}
+	_, err := ch.PostRequestOffLedger(req, user)
+	return err
+}
+
+// SetAccountHistoryRetention sets the number of the latest entries kept in the history of each L2 account.
+// Must be sent by the chain owner, nil means the originator
+func (ch *Chain) SetAccountHistoryRetention(retention uint32, user *cryptolib.KeyPair) error {
+	req := NewCallParams(accounts.Contract.Name, accounts.FuncSetAccountHistoryRetention.Name,
+		accounts.ParamHistoryRetention, retention,
+	).WithMaxAffordableGasBudget()
+	if user == nil {
+		user = ch.OriginatorPrivateKey
+	}
+	_, err := ch.PostRequestOffLedger(req, user)
+	return err
+}
+
+// GetAccountHistoryFirst returns the index of the oldest entry kept in the history of the L2 account
+func (ch *Chain) GetAccountHistoryFirst(agentID isc.AgentID) uint32 {
+	res, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccountHistory.Name,
+		accounts.ParamAgentID, agentID,
+		accounts.ParamHistoryLimit, uint32(0),
+	)
+	require.NoError(ch.Env.T, err)
+	return codec.MustDecodeUint32(res.MustGet(accounts.ParamHistoryFirst))
+}
+
+// GetAccountHistory returns a page of the history of the L2 account and the total number of its entries
+func (ch *Chain) GetAccountHistory(agentID isc.AgentID, offset, limit uint32) ([]*accounts.HistoryEntry, uint32) {
+	res, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccountHistory.Name,
+		accounts.ParamAgentID, agentID,
+		accounts.ParamHistoryOffset, offset,
+		accounts.ParamHistoryLimit, limit,
+	)
+	require.NoError(ch.Env.T, err)
+	arr := collections.NewArray32ReadOnly(res, accounts.ParamHistoryEntries)
+	ret := make([]*accounts.HistoryEntry, arr.MustLen())
+	for i := range ret {
+		ret[i], err = accounts.HistoryEntryFromBytes(arr.MustGetAt(uint32(i)))
+		require.NoError(ch.Env.T, err)
+	}
+	return ret, codec.MustDecodeUint32(res.MustGet(accounts.ParamHistoryTotal))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -39,6 +40,9 @@ type CallParams struct {
 	nonce      uint64 // ignored for on-ledger
 	params     dict.Dict
 	sender     iotago.Address
+	// sponsor of the off-ledger request and the max gas fee it pays. Ignored for on-ledger
+	sponsor       *cryptolib.KeyPair
+	sponsorMaxFee uint64
 }
 
 // NewCallParams creates structure which wraps in one object call parameters, used in PostRequestSync and callViewFull
@@ -184,12 +188,47 @@ func (r *CallParams) WithSender(sender iotago.Address) *CallParams {
 	return r
 }
 
+// WithSponsor makes the off-ledger request sponsored: the sponsor pays the gas fee, up to maxFee
+func (r *CallParams) WithSponsor(sponsor *cryptolib.KeyPair, maxFee uint64) *CallParams {
+	r.sponsor = sponsor
+	r.sponsorMaxFee = maxFee
+	return r
+}
+
 // NewRequestOffLedger creates off-ledger request from parameters
 func (r *CallParams) NewRequestOffLedger(chainID *isc.ChainID, keyPair *cryptolib.KeyPair) isc.OffLedgerRequest {
 	ret := isc.NewOffLedgerRequest(chainID, r.target, r.entryPoint, r.params, r.nonce).
 		WithGasBudget(r.gasBudget).
-		WithAllowance(r.allowance)
-	return ret.Sign(keyPair)
+		WithAllowance(r.allowance).
+		Sign(keyPair)
+	if r.sponsor != nil {
+		return ret.(isc.SponsoredOffLedgerRequest).WithSponsor(r.sponsor, r.sponsorMaxFee)
+	}
+	return ret
+}
+
+// NewMultisigRequest creates an off-ledger request of the multisig account from parameters, signed by the keys
+func (r *CallParams) NewMultisigRequest(chainID *isc.ChainID, account *isc.MultisigAgentID, policy *isc.MultisigPolicy, keys ...*cryptolib.KeyPair) (isc.MultisigRequest, error) {
+	return isc.NewOffLedgerMultisigRequest(chainID, account, policy, r.target, r.entryPoint, r.params, r.nonce).
+		WithGasBudget(r.gasBudget).
+		WithAllowance(r.allowance).
+		Sign(keys...)
+}
+
+// NewMultiCallRequest creates the off-ledger multi-call request with a call for each of the parameters.
+// The gas budget and the nonce of the request are taken from the first call
+func NewMultiCallRequest(chainID *isc.ChainID, calls []*CallParams, keyPair *cryptolib.KeyPair) (isc.MultiCallRequest, error) {
+	iscCalls := make([]*isc.Call, len(calls))
+	for i, c := range calls {
+		iscCalls[i] = isc.NewCall(c.target, c.entryPoint, c.params, c.allowance)
+	}
+	req, err := isc.NewOffLedgerMultiCallRequest(chainID, iscCalls, 0)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithNonce(calls[0].nonce).
+		WithGasBudget(calls[0].gasBudget).
+		Sign(keyPair), nil
 }
 
 func (ch *Chain) mustStardustVM() {
@@ -344,6 +383,37 @@ func (ch *Chain) PostRequestOffLedger(req *CallParams, keyPair *cryptolib.KeyPai
 	return ch.RunOffLedgerRequest(r)
 }
 
+// PostMultisigRequest runs the off-ledger request sent by the multisig account with the given policy, signed by the keys
+func (ch *Chain) PostMultisigRequest(req *CallParams, account *isc.MultisigAgentID, policy *isc.MultisigPolicy, keys ...*cryptolib.KeyPair) (dict.Dict, error) {
+	r, err := req.NewMultisigRequest(ch.ChainID, account, policy, keys...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.VerifySignature(); err != nil {
+		return nil, err
+	}
+	return ch.RunOffLedgerRequest(r)
+}
+
+// PostMultiCallRequest runs the calls atomically, in one off-ledger multi-call request.
+// Returns the results of the calls run, which is all of them unless the request failed
+func (ch *Chain) PostMultiCallRequest(calls []*CallParams, keyPair *cryptolib.KeyPair) ([]dict.Dict, error) {
+	defer ch.logRequestLastBlock()
+	if keyPair == nil {
+		keyPair = ch.OriginatorPrivateKey
+	}
+	r, err := NewMultiCallRequest(ch.ChainID, calls, keyPair)
+	if err != nil {
+		return nil, err
+	}
+	results := ch.RunRequestsSync([]isc.Request{r}, "multi-call")
+	if len(results) == 0 {
+		return nil, xerrors.New("request was skipped")
+	}
+	res := results[0]
+	return res.Receipt.CallResults, ch.ResolveVMError(res.Receipt.Error).AsGoError()
+}
+
 func (ch *Chain) PostRequestSyncTx(req *CallParams, keyPair *cryptolib.KeyPair) (*iotago.Transaction, dict.Dict, error) {
 	tx, receipt, res, err := ch.PostRequestSyncExt(req, keyPair)
 	if err != nil {
@@ -498,6 +568,22 @@ func (ch *Chain) CallViewByHname(hContract, hFunction isc.Hname, params ...inter
 	return vmctx.CallViewExternal(hContract, hFunction, p)
 }
 
+// CallViewAllPages calls the list-style view page by page, until the last page. The result of each page is
+// passed to f. See coreutil.ParamPageCursor
+func (ch *Chain) CallViewAllPages(scName, funName string, params dict.Dict, f func(page dict.Dict)) error {
+	var cursor []byte
+	for {
+		res, err := ch.CallView(scName, funName, coreutil.PageParams(params, cursor, 0))
+		if err != nil {
+			return err
+		}
+		f(res)
+		if cursor = coreutil.NextPageCursor(res); cursor == nil {
+			return nil
+		}
+	}
+}
+
 // GetMerkleProofRaw returns Merkle proof of the key in the state
 func (ch *Chain) GetMerkleProofRaw(key []byte) *trie_blake2b.Proof {
 	ch.Log().Debugf("GetMerkleProof")
@@ -512,6 +598,18 @@ func (ch *Chain) GetMerkleProofRaw(key []byte) *trie_blake2b.Proof {
 	return ret
 }
 
+// GetStateProofs returns the index of the latest state, and the values and Merkle proofs of the keys in it
+func (ch *Chain) GetStateProofs(keys [][]byte) (uint32, [][]byte, []*trie_blake2b.Proof, error) {
+	ch.Log().Debugf("GetStateProofs")
+
+	ch.runVMMutex.Lock()
+	defer ch.runVMMutex.Unlock()
+
+	vmctx := viewcontext.New(ch)
+	ch.StateReader.SetBaseline()
+	return vmctx.GetStateProofs(keys)
+}
+
 // GetBlockProof returns Merkle proof of the key in the state
 func (ch *Chain) GetBlockProof(blockIndex uint32) (*blocklog.BlockInfo, *trie_blake2b.Proof, error) {
 	ch.Log().Debugf("GetBlockProof")
@@ -162,6 +162,15 @@ func (ch *Chain) settleStateTransition(stateTx *iotago.Transaction, reqids []isc
 		ch.State.BlockIndex(), len(reqids), len(stateTx.Essence.Outputs))
 	ch.Log().Debugf("Batch processed: %s", batchShortStr(reqids))
 
+	if ch.evmIndex != nil {
+		ch.StateReader.SetBaseline()
+		gap, err := ch.evmIndex.Update(ch.StateReader.KVStoreReader())
+		require.NoError(ch.Env.T, err)
+		if gap != nil {
+			ch.Log().Warnf("EVM blocks %d-%d were pruned before they were indexed", gap.From, gap.To)
+		}
+	}
+
 	ch.mempool.RemoveRequests(reqids...)
 
 	go ch.Env.EnqueueRequests(stateTx)
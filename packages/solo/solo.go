@@ -17,7 +17,9 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
 	"github.com/iotaledger/wasp/packages/database/dbmanager"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -107,6 +109,8 @@ type Chain struct {
 	mempool mempool.Mempool
 	// used for non-standard VMs
 	bypassStardustVM bool
+	// archive of the EVM blocks, if enabled
+	evmIndex *evmindex.Index
 }
 
 var _ chain.ChainCore = &Chain{}
@@ -127,6 +131,8 @@ type InitChainOptions struct {
 	// flag forces bypassing any StardustVM ledger-dependent calls, such as init or blocklog
 	// To be used with provided non-standard VMRunner
 	BypassStardustVM bool
+	// flag enables the node-side archive of the EVM blocks, see evmindex
+	EVMIndex bool
 }
 
 func defaultInitOptions() *InitOptions {
@@ -221,6 +227,7 @@ func (env *Solo) NewChainExt(chainOriginator *cryptolib.KeyPair, initBaseTokens
 	vmRunner := runvm.NewVMRunner()
 	var initRequestParams []dict.Dict
 	bypassStardustVM := false
+	evmIndexEnabled := false
 
 	if len(initOptions) > 0 {
 		if initOptions[0].VMRunner != nil {
@@ -230,6 +237,7 @@ func (env *Solo) NewChainExt(chainOriginator *cryptolib.KeyPair, initBaseTokens
 			initRequestParams = []dict.Dict{initOptions[0].InitRequestParameters}
 		}
 		bypassStardustVM = initOptions[0].BypassStardustVM
+		evmIndexEnabled = initOptions[0].EVMIndex
 	}
 
 	stateControllerKey := env.NewKeyPairFromIndex(-1) // leaving positive indices to user
@@ -298,6 +306,11 @@ func (env *Solo) NewChainExt(chainOriginator *cryptolib.KeyPair, initBaseTokens
 	}
 	ret.mempool = mempool.New(chainID.AsAddress(), ret.StateReader, chainlog, metrics.DefaultChainMetrics())
 	require.NoError(env.T, err)
+	if evmIndexEnabled {
+		evmIndexStore, err := store.WithRealm(append(store.Realm(), dbkeys.ObjectTypeEVMIndex))
+		require.NoError(env.T, err)
+		ret.evmIndex = evmindex.New(evmIndexStore)
+	}
 
 	// creating origin transaction with the origin of the Alias chain
 	outs, ids := env.utxoDB.GetUnspentOutputs(originatorAddr)
@@ -0,0 +1,340 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// TrieNode is a node of the state trie as it is stored in the DB, together with
+// the value committed by its terminal. The nodes are downloaded from the peers
+// to sync the state without applying all the blocks from origin (fast sync).
+type TrieNode struct {
+	Key   []byte // unpacked key of the node
+	Bytes []byte // serialized node
+	Value []byte // value of the terminal, nil if the node has no terminal
+}
+
+// TrieNodeRef is the unpacked key of a node and the commitment to the node,
+// as it is known by its parent
+type TrieNodeRef struct {
+	Key        []byte
+	Commitment trie.VCommitment
+}
+
+// terminalValue is a trie.KVReader, which provides the value of the terminal
+// to decode a single node, regardless of the key
+type terminalValue []byte
+
+func (v terminalValue) Get([]byte) []byte {
+	return v
+}
+
+func (v terminalValue) Has([]byte) bool {
+	return v != nil
+}
+
+func (n *TrieNode) nodeData() (*trie.NodeData, error) {
+	return trie.NodeDataFromBytes(model, n.Bytes, n.Key, model.PathArity(), terminalValue(n.Value))
+}
+
+func (n *TrieNode) valueKey(nodeData *trie.NodeData) ([]byte, error) {
+	return trie.PackUnpackedBytes(trie.Concat(n.Key, nodeData.PathFragment), model.PathArity())
+}
+
+// Verify checks, that the node and the value of its terminal are committed by
+// the commitment. It returns the references to the children of the node.
+func (n *TrieNode) Verify(commitment trie.VCommitment) ([]*TrieNodeRef, error) {
+	nodeData, err := n.nodeData()
+	if err != nil {
+		return nil, xerrors.Errorf("invalid trie node %x: %w", n.Key, err)
+	}
+	if !model.EqualCommitments(model.CalcNodeCommitment(nodeData), commitment) {
+		return nil, xerrors.Errorf("trie node %x is not committed by %s", n.Key, commitment)
+	}
+	if nodeData.Terminal == nil {
+		if n.Value != nil {
+			return nil, xerrors.Errorf("trie node %x has no terminal, but a value is provided", n.Key)
+		}
+	} else if n.Value == nil || !model.EqualCommitments(model.CommitToData(n.Value), nodeData.Terminal) {
+		return nil, xerrors.Errorf("value of trie node %x is not committed by its terminal", n.Key)
+	}
+	children := make([]*TrieNodeRef, 0, len(nodeData.ChildCommitments))
+	for i := 0; i < model.PathArity().NumChildren(); i++ {
+		if c, ok := nodeData.ChildCommitments[byte(i)]; ok {
+			children = append(children, &TrieNodeRef{
+				Key:        trie.Concat(n.Key, nodeData.PathFragment, byte(i)),
+				Commitment: c,
+			})
+		}
+	}
+	return children, nil
+}
+
+func (n *TrieNode) Write(w io.Writer) error {
+	if err := util.WriteBytes32(w, n.Key); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, n.Bytes); err != nil {
+		return err
+	}
+	if err := util.WriteBoolByte(w, n.Value != nil); err != nil {
+		return err
+	}
+	if n.Value != nil {
+		return util.WriteBytes32(w, n.Value)
+	}
+	return nil
+}
+
+func (n *TrieNode) Read(r io.Reader) error {
+	var err error
+	if n.Key, err = util.ReadBytes32(r); err != nil {
+		return err
+	}
+	if n.Bytes, err = util.ReadBytes32(r); err != nil {
+		return err
+	}
+	var hasValue bool
+	if err = util.ReadBoolByte(r, &hasValue); err != nil {
+		return err
+	}
+	n.Value = nil
+	if hasValue {
+		if n.Value, err = util.ReadBytes32(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadTrieNode loads the node with the unpacked key from the DB, together with
+// the value of its terminal. It returns nil if the node is not in the DB.
+func LoadTrieNode(store kvstore.KVStore, key []byte) (*TrieNode, error) {
+	encodedKey, err := trie.EncodeUnpackedBytes(key, model.PathArity())
+	if err != nil {
+		return nil, err
+	}
+	data := trieKVStore(store).Get(encodedKey)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	nodeData, err := trie.NodeDataFromBytes(model, data, key, model.PathArity(), valueKVStore(store))
+	if err != nil {
+		return nil, fmt.Errorf("LoadTrieNode: %w", err)
+	}
+	ret := &TrieNode{Key: key, Bytes: data}
+	if nodeData.Terminal != nil {
+		valueKey, err := ret.valueKey(nodeData)
+		if err != nil {
+			return nil, err
+		}
+		if ret.Value = valueKVStore(store).Get(valueKey); ret.Value == nil {
+			return nil, fmt.Errorf("LoadTrieNode: value of the terminal of node %x not found", key)
+		}
+	}
+	return ret, nil
+}
+
+// SaveTrieNodes stores the nodes and the values of their terminals in the DB
+// in one transaction. The nodes are expected to be verified.
+func SaveTrieNodes(store kvstore.KVStore, nodes []*TrieNode) error {
+	batch, err := store.Batched()
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		encodedKey, err := trie.EncodeUnpackedBytes(n.Key, model.PathArity())
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeTrie, encodedKey), n.Bytes); err != nil {
+			return err
+		}
+		if n.Value == nil {
+			continue
+		}
+		nodeData, err := n.nodeData()
+		if err != nil {
+			return err
+		}
+		valueKey, err := n.valueKey(nodeData)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeState, valueKey), n.Value); err != nil {
+			return err
+		}
+	}
+	return batch.Commit()
+}
+
+// SetFastSyncInProgress marks the state in the DB as incomplete, while it is
+// being downloaded by fast sync
+func SetFastSyncInProgress(store kvstore.KVStore, inProgress bool) error {
+	key := dbkeys.MakeKey(dbkeys.ObjectTypeFastSync)
+	var err error
+	if inProgress {
+		err = store.Set(key, []byte{1})
+	} else {
+		err = store.Delete(key)
+	}
+	if err != nil {
+		return err
+	}
+	return store.Flush()
+}
+
+// IsFastSyncInProgress returns true, if the state in the DB has been left
+// incomplete by an interrupted fast sync
+func IsFastSyncInProgress(store kvstore.KVStore) (bool, error) {
+	return store.Has(dbkeys.MakeKey(dbkeys.ObjectTypeFastSync))
+}
+
+// DeleteState deletes the trie and the values of the state from the DB
+func DeleteState(store kvstore.KVStore) error {
+	if err := store.DeletePrefix([]byte{dbkeys.ObjectTypeTrie}); err != nil {
+		return err
+	}
+	if err := store.DeletePrefix([]byte{dbkeys.ObjectTypeState}); err != nil {
+		return err
+	}
+	return store.Flush()
+}
+
+// CompleteFastSync is called, when all the nodes of the state with the
+// commitment have been downloaded. It deletes the nodes and the values left from
+// the previous state, which are not committed by the downloaded trie, removes
+// the fast sync mark and loads the downloaded state.
+func CompleteFastSync(store kvstore.KVStore, chainID *isc.ChainID, commitment trie.VCommitment) (VirtualStateAccess, error) {
+	vs := NewVirtualState(store)
+	if !EqualCommitments(trie.RootCommitment(vs.trie), commitment) {
+		return nil, fmt.Errorf("CompleteFastSync: expected state commitment %s, got %s", commitment, trie.RootCommitment(vs.trie))
+	}
+	if err := pruneTrie(store); err != nil {
+		return nil, err
+	}
+	if stale := vs.ReconcileTrie(); len(stale) > 0 {
+		batch, err := store.Batched()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range stale {
+			if err := batch.Delete(dbkeys.MakeKey(dbkeys.ObjectTypeState, []byte(key))); err != nil {
+				return nil, err
+			}
+		}
+		if err := batch.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	if err := SetFastSyncInProgress(store, false); err != nil {
+		return nil, err
+	}
+	ret, exists, err := LoadSolidState(store, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("CompleteFastSync: state not found")
+	}
+	return ret, nil
+}
+
+// max number of unreachable nodes deleted in one batch by pruneTrie
+const pruneTrieBatchSize = 10000
+
+// pruneTrie deletes the nodes, which are not reachable from the root. The trie
+// accesses the nodes by their keys, so a node left from the previous state
+// would otherwise be taken as a part of the current one. The keys are checked
+// while iterating, so that they are not all kept in memory.
+func pruneTrie(store kvstore.KVStore) error {
+	batch, err := store.Batched()
+	if err != nil {
+		return err
+	}
+	deleted := 0
+	var iterErr error
+	err = store.IterateKeys([]byte{dbkeys.ObjectTypeTrie}, func(key kvstore.Key) bool {
+		unpackedKey, err := trie.DecodeToUnpackedBytes(key[1:], model.PathArity())
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		reachable, err := isTrieNodeReachable(store, unpackedKey)
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		if reachable {
+			return true
+		}
+		if iterErr = batch.Delete(append([]byte(nil), key...)); iterErr != nil {
+			return false
+		}
+		if deleted++; deleted%pruneTrieBatchSize == 0 {
+			// the nodes deleted so far are not reachable anyway, so they can be committed apart
+			if iterErr = batch.Commit(); iterErr != nil {
+				return false
+			}
+			if batch, iterErr = store.Batched(); iterErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if iterErr != nil {
+		if batch != nil {
+			batch.Cancel()
+		}
+		return iterErr
+	}
+	return batch.Commit()
+}
+
+// isTrieNodeReachable checks, that every node on the path from the root to the
+// key is in the DB and has a child in the direction of the key
+func isTrieNodeReachable(store kvstore.KVStore, key []byte) (bool, error) {
+	var nodeKey []byte
+	for {
+		encodedKey, err := trie.EncodeUnpackedBytes(nodeKey, model.PathArity())
+		if err != nil {
+			return false, err
+		}
+		data := trieKVStore(store).Get(encodedKey)
+		if len(data) == 0 {
+			return false, nil
+		}
+		if len(nodeKey) == len(key) {
+			return true, nil
+		}
+		// only the structure of the node is needed, so the values of the terminals are not read
+		nodeData, err := trie.NodeDataFromBytes(model, data, nodeKey, model.PathArity(), terminalValue([]byte{}))
+		if err != nil {
+			return false, err
+		}
+		rest := key[len(nodeKey):]
+		if len(rest) <= len(nodeData.PathFragment) || !bytes.HasPrefix(rest, nodeData.PathFragment) {
+			return false, nil
+		}
+		childIndex := rest[len(nodeData.PathFragment)]
+		if _, ok := nodeData.ChildCommitments[childIndex]; !ok {
+			return false, nil
+		}
+		nodeKey = trie.Concat(nodeKey, nodeData.PathFragment, childIndex)
+	}
+}
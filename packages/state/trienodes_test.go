@@ -0,0 +1,116 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/trie.go/trie"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/testutil/testmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrieNodesSync(t *testing.T) {
+	chainID := testmisc.RandChainID()
+	srcStore := mapdb.NewMapDB()
+	src, err := CreateOriginState(srcStore, chainID)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		src.KVStore().Set(kv.Key(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+	}
+	// values longer than the size optimization threshold are committed by hash
+	src.KVStore().Set("long", bytes.Repeat([]byte{1}, 100))
+	require.NoError(t, src.Save())
+	root := trie.RootCommitment(src.TrieNodeStore())
+
+	// the destination has the origin state and a stale value, not present in the source
+	dstStore := mapdb.NewMapDB()
+	dst, err := CreateOriginState(dstStore, chainID)
+	require.NoError(t, err)
+	dst.KVStore().Set("stale", []byte("stale"))
+	require.NoError(t, dst.Save())
+	require.NoError(t, SetFastSyncInProgress(dstStore, true))
+
+	queue := []*TrieNodeRef{{Key: nil, Commitment: root}}
+	nodes := 0
+	for len(queue) > 0 {
+		ref := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		node, err := LoadTrieNode(srcStore, ref.Key)
+		require.NoError(t, err)
+		require.NotNil(t, node)
+
+		// serialization round trip, as the node is sent to the peer
+		var buf bytes.Buffer
+		require.NoError(t, node.Write(&buf))
+		received := &TrieNode{}
+		require.NoError(t, received.Read(&buf))
+		require.True(t, bytes.Equal(node.Key, received.Key))
+		require.EqualValues(t, node.Bytes, received.Bytes)
+		require.EqualValues(t, node.Value, received.Value)
+
+		children, err := received.Verify(ref.Commitment)
+		require.NoError(t, err)
+		require.NoError(t, SaveTrieNodes(dstStore, []*TrieNode{received}))
+		queue = append(queue, children...)
+		nodes++
+	}
+	require.Greater(t, nodes, 100)
+
+	inProgress, err := IsFastSyncInProgress(dstStore)
+	require.NoError(t, err)
+	require.True(t, inProgress)
+
+	synced, err := CompleteFastSync(dstStore, chainID, root)
+	require.NoError(t, err)
+	require.True(t, EqualCommitments(root, trie.RootCommitment(synced.TrieNodeStore())))
+	require.EqualValues(t, []byte("value42"), synced.KVStoreReader().MustGet("key42"))
+	require.EqualValues(t, bytes.Repeat([]byte{1}, 100), synced.KVStoreReader().MustGet("long"))
+	require.Nil(t, synced.KVStoreReader().MustGet("stale"))
+
+	inProgress, err = IsFastSyncInProgress(dstStore)
+	require.NoError(t, err)
+	require.False(t, inProgress)
+}
+
+func TestTrieNodeVerify(t *testing.T) {
+	store := mapdb.NewMapDB()
+	vs, err := CreateOriginState(store, testmisc.RandChainID())
+	require.NoError(t, err)
+	vs.KVStore().Set("a", []byte("a"))
+	vs.KVStore().Set("b", bytes.Repeat([]byte{2}, 100))
+	require.NoError(t, vs.Save())
+	root := trie.RootCommitment(vs.TrieNodeStore())
+
+	node, err := LoadTrieNode(store, nil)
+	require.NoError(t, err)
+	children, err := node.Verify(root)
+	require.NoError(t, err)
+	require.NotEmpty(t, children)
+
+	// wrong value of the terminal
+	tampered := &TrieNode{Key: node.Key, Bytes: node.Bytes, Value: []byte("wrong")}
+	_, err = tampered.Verify(root)
+	require.Error(t, err)
+
+	// node not committed by the commitment
+	_, err = node.Verify(children[0].Commitment)
+	require.Error(t, err)
+
+	// terminal committed by hash
+	long, err := LoadTrieNode(store, trie.UnpackBytes([]byte("b"), trie.PathArity16))
+	require.NoError(t, err)
+	if long == nil {
+		t.Skip("the key 'b' is not stored in its own node")
+	}
+	tampered = &TrieNode{Key: long.Key, Bytes: long.Bytes, Value: bytes.Repeat([]byte{3}, 100)}
+	_, err = tampered.Verify(trie.RootCommitment(vs.TrieNodeStore()))
+	require.Error(t, err)
+
+	missing, err := LoadTrieNode(store, []byte{15, 15, 15})
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
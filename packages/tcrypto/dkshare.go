@@ -159,6 +159,17 @@ func DKShareFromBytes(buf []byte, edSuite suites.Suite, blsSuite Suite, nodePriv
 	return &s, nil
 }
 
+// DKSharePublicFromBytes reads a DKShare written by PublicBytes.
+// The share has no private parts, the operations needing them cannot be used.
+func DKSharePublicFromBytes(buf []byte, edSuite suites.Suite, blsSuite Suite) (DKShare, error) {
+	r := bytes.NewReader(buf)
+	s := dkShareImpl{edSuite: edSuite, blsSuite: blsSuite}
+	if err := s.read(r, false); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 // Bytes returns byte representation of the share.
 func (s *dkShareImpl) Bytes() []byte {
 	var buf bytes.Buffer
@@ -168,8 +179,21 @@ func (s *dkShareImpl) Bytes() []byte {
 	return buf.Bytes()
 }
 
+// PublicBytes returns byte representation of the share without the private key shares.
+func (s *dkShareImpl) PublicBytes() []byte {
+	var buf bytes.Buffer
+	if err := s.write(&buf, false); err != nil {
+		panic(xerrors.Errorf("DKShare.PublicBytes: %w", err))
+	}
+	return buf.Bytes()
+}
+
 // Write returns byte representation of this struct.
 func (s *dkShareImpl) Write(w io.Writer) error {
+	return s.write(w, true)
+}
+
+func (s *dkShareImpl) write(w io.Writer, withPrivate bool) error {
 	var err error
 	//
 	// Common attributes.
@@ -214,17 +238,14 @@ func (s *dkShareImpl) Write(w io.Writer) error {
 			return err
 		}
 	}
-	if err := util.WriteUint16(w, uint16(len(s.edPublicShares))); err != nil {
+	if err := writePublicShares(w, s.edPublicShares, !withPrivate); err != nil {
 		return err
 	}
-	for i := 0; i < len(s.edPublicShares); i++ {
-		if err := util.WriteMarshaled(w, s.edPublicShares[i]); err != nil {
+	if withPrivate {
+		if err := util.WriteMarshaled(w, s.edPrivateShare); err != nil {
 			return err
 		}
 	}
-	if err := util.WriteMarshaled(w, s.edPrivateShare); err != nil {
-		return err
-	}
 	//
 	// BLS part of the key shares.
 	if err := util.WriteMarshaled(w, s.blsSharedPublic); err != nil {
@@ -238,21 +259,22 @@ func (s *dkShareImpl) Write(w io.Writer) error {
 			return err
 		}
 	}
-	if err := util.WriteUint16(w, uint16(len(s.blsPublicShares))); err != nil {
+	if err := writePublicShares(w, s.blsPublicShares, !withPrivate); err != nil {
 		return err
 	}
-	for i := 0; i < len(s.blsPublicShares); i++ {
-		if err := util.WriteMarshaled(w, s.blsPublicShares[i]); err != nil {
+	if withPrivate {
+		if err := util.WriteMarshaled(w, s.blsPrivateShare); err != nil {
 			return err
 		}
 	}
-	if err := util.WriteMarshaled(w, s.blsPrivateShare); err != nil {
-		return err
-	}
 	return nil
 }
 
 func (s *dkShareImpl) Read(r io.Reader) error {
+	return s.read(r, true)
+}
+
+func (s *dkShareImpl) read(r io.Reader, withPrivate bool) error {
 	var err error
 	var arrLen uint16
 	//
@@ -302,19 +324,19 @@ func (s *dkShareImpl) Read(r io.Reader) error {
 	}
 	//
 	// DSS / Ed25519 shares.
-	if err := s.readDSSAttrs(r); err != nil {
+	if err := s.readDSSAttrs(r, withPrivate); err != nil {
 		return err
 	}
 	//
 	// BLS Shares.
-	if err := s.readBLSAttrs(r); err != nil {
+	if err := s.readBLSAttrs(r, withPrivate); err != nil {
 		return err
 	}
 	return nil
 }
 
 // Read function was split just to make the linter happy.
-func (s *dkShareImpl) readDSSAttrs(r io.Reader) error {
+func (s *dkShareImpl) readDSSAttrs(r io.Reader, withPrivate bool) error {
 	var arrLen uint16
 	s.edSharedPublic = s.edSuite.Point()
 	if err := util.ReadMarshaled(r, s.edSharedPublic); err != nil {
@@ -334,18 +356,15 @@ func (s *dkShareImpl) readDSSAttrs(r io.Reader) error {
 	}
 	//
 	// Ed25519 shares: PublicShares
-	if err := util.ReadUint16(r, &arrLen); err != nil {
+	var err error
+	if s.edPublicShares, err = readPublicShares(r, s.edSuite, !withPrivate); err != nil {
 		return err
 	}
-	s.edPublicShares = make([]kyber.Point, arrLen)
-	for i := uint16(0); i < arrLen; i++ {
-		s.edPublicShares[i] = s.edSuite.Point()
-		if err := util.ReadMarshaled(r, s.edPublicShares[i]); err != nil {
-			return err
-		}
-	}
 	//
 	// Ed25519 shares: Private share.
+	if !withPrivate {
+		return nil
+	}
 	s.edPrivateShare = s.edSuite.Scalar()
 	if err := util.ReadMarshaled(r, s.edPrivateShare); err != nil {
 		return err
@@ -354,7 +373,7 @@ func (s *dkShareImpl) readDSSAttrs(r io.Reader) error {
 }
 
 // Read function was split just to make the linter happy.
-func (s *dkShareImpl) readBLSAttrs(r io.Reader) error {
+func (s *dkShareImpl) readBLSAttrs(r io.Reader, withPrivate bool) error {
 	var arrLen uint16
 	s.blsSharedPublic = s.blsSuite.G2().Point()
 	if err := util.ReadMarshaled(r, s.blsSharedPublic); err != nil {
@@ -374,18 +393,15 @@ func (s *dkShareImpl) readBLSAttrs(r io.Reader) error {
 	}
 	//
 	// BLS shares: PublicShares
-	if err := util.ReadUint16(r, &arrLen); err != nil {
+	var err error
+	if s.blsPublicShares, err = readPublicShares(r, s.blsSuite.G2(), !withPrivate); err != nil {
 		return err
 	}
-	s.blsPublicShares = make([]kyber.Point, arrLen)
-	for i := uint16(0); i < arrLen; i++ {
-		s.blsPublicShares[i] = s.blsSuite.G2().Point()
-		if err := util.ReadMarshaled(r, s.blsPublicShares[i]); err != nil {
-			return err
-		}
-	}
 	//
 	// BLS shares: Private share.
+	if !withPrivate {
+		return nil
+	}
 	s.blsPrivateShare = s.blsSuite.G2().Scalar()
 	if err := util.ReadMarshaled(r, s.blsPrivateShare); err != nil {
 		return err
@@ -393,6 +409,53 @@ func (s *dkShareImpl) readBLSAttrs(r io.Reader) error {
 	return nil
 }
 
+// The public shares of the other nodes are only known at the end of the DKG, the
+// public representation of a share can be produced before that, so it marks the
+// missing public shares.
+func writePublicShares(w io.Writer, publicShares []kyber.Point, markMissing bool) error {
+	if err := util.WriteUint16(w, uint16(len(publicShares))); err != nil {
+		return err
+	}
+	for i := range publicShares {
+		if markMissing {
+			if err := util.WriteBoolByte(w, publicShares[i] != nil); err != nil {
+				return err
+			}
+			if publicShares[i] == nil {
+				continue
+			}
+		}
+		if err := util.WriteMarshaled(w, publicShares[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPublicShares(r io.Reader, group kyber.Group, markMissing bool) ([]kyber.Point, error) {
+	var arrLen uint16
+	if err := util.ReadUint16(r, &arrLen); err != nil {
+		return nil, err
+	}
+	publicShares := make([]kyber.Point, arrLen)
+	for i := range publicShares {
+		if markMissing {
+			var present bool
+			if err := util.ReadBoolByte(r, &present); err != nil {
+				return nil, err
+			}
+			if !present {
+				continue
+			}
+		}
+		publicShares[i] = group.Point()
+		if err := util.ReadMarshaled(r, publicShares[i]); err != nil {
+			return nil, err
+		}
+	}
+	return publicShares, nil
+}
+
 func (s *dkShareImpl) GetAddress() iotago.Address {
 	return s.address
 }
@@ -521,6 +584,11 @@ func (s *dkShareImpl) DSSSecretShare() SecretShare {
 	}
 }
 
+// DSSReshareDeal produces a deal for moving the own DSS key share to a new group of nodes.
+func (s *dkShareImpl) DSSReshareDeal(newN, newT uint16) (*ReshareDeal, error) {
+	return newReshareDeal(s.edSuite, s.edPrivateShare, newN, newT, s.edSuite.RandomStream())
+}
+
 func (s *dkShareImpl) makeSigner(data []byte, nonce SecretShare) (*dss.DSS, error) {
 	priKeyDKS := s.DSSSecretShare()
 	nodePrivKey := eddsa.EdDSA{}
@@ -605,6 +673,11 @@ func (s *dkShareImpl) BLSVerify(signer kyber.Point, data, signature []byte) erro
 	return bdn.Verify(s.blsSuite, signer, data, signature)
 }
 
+// BLSReshareDeal produces a deal for moving the own BLS key share to a new group of nodes.
+func (s *dkShareImpl) BLSReshareDeal(newN, newT uint16) (*ReshareDeal, error) {
+	return newReshareDeal(s.blsSuite, s.blsPrivateShare, newN, newT, s.blsSuite.RandomStream())
+}
+
 ///////////////////////// Test support functions.
 
 func (s *dkShareImpl) AssignNodePubKeys(nodePubKeys []*cryptolib.PublicKey) {
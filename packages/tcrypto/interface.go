@@ -22,6 +22,7 @@ type SecretShare interface {
 // a node as a result of the DKG procedure.
 type DKShare interface {
 	Bytes() []byte
+	PublicBytes() []byte // Without the private key shares.
 	GetAddress() iotago.Address
 	GetIndex() *uint16
 	GetN() uint16
@@ -38,6 +39,7 @@ type DKShare interface {
 	DSSRecoverMasterSignature(sigShares []*dss.PartialSig, data []byte, nonce SecretShare) ([]byte, error)
 	DSSVerifyMasterSignature(data, signature []byte) error
 	DSSSecretShare() SecretShare
+	DSSReshareDeal(newN, newT uint16) (*ReshareDeal, error)
 	//
 	// BLS based crypto (for randomness only.)
 	BLSSharedPublic() kyber.Point
@@ -48,6 +50,7 @@ type DKShare interface {
 	BLSVerifyMasterSignature(data, signature []byte) error
 	BLSSign(data []byte) ([]byte, error)                        // Non-threshold variant.
 	BLSVerify(signer kyber.Point, data, signature []byte) error // Non-threshold variant.
+	BLSReshareDeal(newN, newT uint16) (*ReshareDeal, error)
 	//
 	// For tests only.
 	AssignNodePubKeys(nodePubKeys []*cryptolib.PublicKey)
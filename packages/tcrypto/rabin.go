@@ -0,0 +1,505 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+//
+// This file contains the codecs for the messages of the Rabin DKG protocol.
+// They are used by the DKG nodes to exchange the messages, as well as to pass
+// them to the signer holding the secrets of a node.
+//
+
+import (
+	"io"
+
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	rabin_vss "go.dedis.ch/kyber/v3/share/vss/rabin"
+)
+
+// WriteRabinDeal writes a deal. The deal is encrypted for its receiver.
+//
+//nolint:gocritic
+func WriteRabinDeal(w io.Writer, d *rabin_dkg.Deal) error {
+	var err error
+	if err = util.WriteUint32(w, d.Index); err != nil {
+		return err
+	}
+	if err = util.WriteMarshaled(w, d.Deal.DHKey); err != nil {
+		return err
+	}
+	if err = util.WriteBytes16(w, d.Deal.Signature); err != nil {
+		return err
+	}
+	if err = util.WriteBytes16(w, d.Deal.Nonce); err != nil {
+		return err
+	}
+	return util.WriteBytes16(w, d.Deal.Cipher)
+}
+
+// ReadRabinDeal reads a deal written by WriteRabinDeal.
+// The suite is the one of the node keys (Ed25519).
+//
+//nolint:gocritic
+func ReadRabinDeal(r io.Reader, edSuite kyber.Group) (*rabin_dkg.Deal, error) {
+	var err error
+	d := &rabin_dkg.Deal{
+		Deal: &rabin_vss.EncryptedDeal{
+			DHKey: edSuite.Point(),
+		},
+	}
+	if err = util.ReadUint32(r, &d.Index); err != nil {
+		return nil, err
+	}
+	if err = util.ReadMarshaled(r, d.Deal.DHKey); err != nil {
+		return nil, err
+	}
+	if d.Deal.Signature, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	if d.Deal.Nonce, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	if d.Deal.Cipher, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// WriteRabinResponses writes a list of responses.
+//
+//nolint:gocritic
+func WriteRabinResponses(w io.Writer, responses []*rabin_dkg.Response) error {
+	var err error
+	if err = util.WriteUint32(w, uint32(len(responses))); err != nil {
+		return err
+	}
+	for _, r := range responses {
+		if err = util.WriteUint32(w, r.Index); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, r.Response.SessionID); err != nil {
+			return err
+		}
+		if err = util.WriteUint32(w, r.Response.Index); err != nil {
+			return err
+		}
+		if err = util.WriteBoolByte(w, r.Response.Approved); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, r.Response.Signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRabinResponses reads a list of responses written by WriteRabinResponses.
+//
+//nolint:gocritic
+func ReadRabinResponses(r io.Reader) ([]*rabin_dkg.Response, error) {
+	var err error
+	var listLen uint32
+	if err = util.ReadUint32(r, &listLen); err != nil {
+		return nil, err
+	}
+	responses := make([]*rabin_dkg.Response, int(listLen))
+	for i := range responses {
+		response := rabin_dkg.Response{
+			Response: &rabin_vss.Response{},
+		}
+		responses[i] = &response
+		if err = util.ReadUint32(r, &response.Index); err != nil {
+			return nil, err
+		}
+		if response.Response.SessionID, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+		if err = util.ReadUint32(r, &response.Response.Index); err != nil {
+			return nil, err
+		}
+		if err = util.ReadBoolByte(r, &response.Response.Approved); err != nil {
+			return nil, err
+		}
+		if response.Response.Signature, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// WriteRabinJustifications writes a list of justifications.
+//
+//nolint:gocritic
+func WriteRabinJustifications(w io.Writer, justifications []*rabin_dkg.Justification) error {
+	var err error
+	if err = util.WriteUint32(w, uint32(len(justifications))); err != nil {
+		return err
+	}
+	for _, j := range justifications {
+		if err = util.WriteUint32(w, j.Index); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, j.Justification.SessionID); err != nil {
+			return err
+		}
+		if err = util.WriteUint32(w, j.Justification.Index); err != nil {
+			return err
+		}
+		if err = writeVssDeal(w, j.Justification.Deal); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, j.Justification.Signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRabinJustifications reads a list of justifications written by WriteRabinJustifications.
+// The suite is the one of the key set being generated.
+//
+//nolint:gocritic
+func ReadRabinJustifications(r io.Reader, suite kyber.Group) ([]*rabin_dkg.Justification, error) {
+	var err error
+	var jLen uint32
+	if err = util.ReadUint32(r, &jLen); err != nil {
+		return nil, err
+	}
+	justifications := make([]*rabin_dkg.Justification, int(jLen))
+	for i := range justifications {
+		j := rabin_dkg.Justification{
+			Justification: &rabin_vss.Justification{},
+		}
+		justifications[i] = &j
+		if err = util.ReadUint32(r, &j.Index); err != nil {
+			return nil, err
+		}
+		if j.Justification.SessionID, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+		if err = util.ReadUint32(r, &j.Justification.Index); err != nil {
+			return nil, err
+		}
+		if j.Justification.Deal, err = readVssDeal(r, suite); err != nil {
+			return nil, err
+		}
+		if j.Justification.Signature, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return justifications, nil
+}
+
+// WriteRabinSecretCommits writes the secret commits, they can be nil.
+//
+//nolint:gocritic
+func WriteRabinSecretCommits(w io.Writer, sc *rabin_dkg.SecretCommits) error {
+	var err error
+	if err = util.WriteBoolByte(w, sc == nil); err != nil {
+		return err
+	}
+	if sc == nil {
+		return nil
+	}
+	if err = util.WriteUint32(w, sc.Index); err != nil {
+		return err
+	}
+	if err = util.WriteUint32(w, uint32(len(sc.Commitments))); err != nil {
+		return err
+	}
+	for i := range sc.Commitments {
+		if err = util.WriteMarshaled(w, sc.Commitments[i]); err != nil {
+			return err
+		}
+	}
+	if err = util.WriteBytes16(w, sc.SessionID); err != nil {
+		return err
+	}
+	return util.WriteBytes16(w, sc.Signature)
+}
+
+// ReadRabinSecretCommits reads the secret commits written by WriteRabinSecretCommits.
+// The suite is the one of the key set being generated.
+//
+//nolint:gocritic
+func ReadRabinSecretCommits(r io.Reader, suite kyber.Group) (*rabin_dkg.SecretCommits, error) {
+	var err error
+	var isNil bool
+	if err = util.ReadBoolByte(r, &isNil); err != nil {
+		return nil, err
+	}
+	if isNil {
+		return nil, nil
+	}
+	sc := &rabin_dkg.SecretCommits{}
+	if err = util.ReadUint32(r, &sc.Index); err != nil {
+		return nil, err
+	}
+	var cLen uint32
+	if err = util.ReadUint32(r, &cLen); err != nil {
+		return nil, err
+	}
+	sc.Commitments = make([]kyber.Point, cLen)
+	for i := range sc.Commitments {
+		sc.Commitments[i] = suite.Point()
+		if err = util.ReadMarshaled(r, sc.Commitments[i]); err != nil {
+			return nil, err
+		}
+	}
+	if sc.SessionID, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	if sc.Signature, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// WriteRabinComplaintCommits writes a list of complaint commits.
+//
+//nolint:gocritic
+func WriteRabinComplaintCommits(w io.Writer, complaintCommits []*rabin_dkg.ComplaintCommits) error {
+	var err error
+	if err = util.WriteUint32(w, uint32(len(complaintCommits))); err != nil {
+		return err
+	}
+	for i := range complaintCommits {
+		if err = util.WriteUint32(w, complaintCommits[i].Index); err != nil {
+			return err
+		}
+		if err = util.WriteUint32(w, complaintCommits[i].DealerIndex); err != nil {
+			return err
+		}
+		if err = writeVssDeal(w, complaintCommits[i].Deal); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, complaintCommits[i].Signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRabinComplaintCommits reads a list of complaint commits written by WriteRabinComplaintCommits.
+// The suite is the one of the key set being generated.
+//
+//nolint:gocritic
+func ReadRabinComplaintCommits(r io.Reader, suite kyber.Group) ([]*rabin_dkg.ComplaintCommits, error) {
+	var err error
+	var ccLen uint32
+	if err = util.ReadUint32(r, &ccLen); err != nil {
+		return nil, err
+	}
+	complaintCommits := make([]*rabin_dkg.ComplaintCommits, ccLen)
+	for i := range complaintCommits {
+		complaintCommits[i] = &rabin_dkg.ComplaintCommits{}
+		if err = util.ReadUint32(r, &complaintCommits[i].Index); err != nil {
+			return nil, err
+		}
+		if err = util.ReadUint32(r, &complaintCommits[i].DealerIndex); err != nil {
+			return nil, err
+		}
+		if complaintCommits[i].Deal, err = readVssDeal(r, suite); err != nil {
+			return nil, err
+		}
+		if complaintCommits[i].Signature, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return complaintCommits, nil
+}
+
+// WriteRabinReconstructCommits writes a list of reconstruct commits.
+//
+//nolint:gocritic
+func WriteRabinReconstructCommits(w io.Writer, reconstructCommits []*rabin_dkg.ReconstructCommits) error {
+	var err error
+	if err = util.WriteUint32(w, uint32(len(reconstructCommits))); err != nil {
+		return err
+	}
+	for i := range reconstructCommits {
+		if err = util.WriteBytes16(w, reconstructCommits[i].SessionID); err != nil {
+			return err
+		}
+		if err = util.WriteUint32(w, reconstructCommits[i].Index); err != nil {
+			return err
+		}
+		if err = util.WriteUint32(w, reconstructCommits[i].DealerIndex); err != nil {
+			return err
+		}
+		if err = writePriShare(w, reconstructCommits[i].Share); err != nil {
+			return err
+		}
+		if err = util.WriteBytes16(w, reconstructCommits[i].Signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRabinReconstructCommits reads a list of reconstruct commits written by WriteRabinReconstructCommits.
+// The suite is the one of the key set being generated.
+//
+//nolint:gocritic
+func ReadRabinReconstructCommits(r io.Reader, suite kyber.Group) ([]*rabin_dkg.ReconstructCommits, error) {
+	var err error
+	var rcLen uint32
+	if err = util.ReadUint32(r, &rcLen); err != nil {
+		return nil, err
+	}
+	reconstructCommits := make([]*rabin_dkg.ReconstructCommits, rcLen)
+	for i := range reconstructCommits {
+		reconstructCommits[i] = &rabin_dkg.ReconstructCommits{}
+		if reconstructCommits[i].SessionID, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+		if err = util.ReadUint32(r, &reconstructCommits[i].Index); err != nil {
+			return nil, err
+		}
+		if err = util.ReadUint32(r, &reconstructCommits[i].DealerIndex); err != nil {
+			return nil, err
+		}
+		if reconstructCommits[i].Share, err = readPriShare(r, suite); err != nil {
+			return nil, err
+		}
+		if reconstructCommits[i].Signature, err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return reconstructCommits, nil
+}
+
+// WritePoints writes a list of points.
+func WritePoints(w io.Writer, points []kyber.Point) error {
+	if err := util.WriteUint16(w, uint16(len(points))); err != nil {
+		return err
+	}
+	for i := range points {
+		if err := util.WriteMarshaled(w, points[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadPoints reads a list of points written by WritePoints.
+func ReadPoints(r io.Reader, suite kyber.Group) ([]kyber.Point, error) {
+	var arrLen uint16
+	if err := util.ReadUint16(r, &arrLen); err != nil {
+		return nil, err
+	}
+	points := make([]kyber.Point, arrLen)
+	for i := range points {
+		points[i] = suite.Point()
+		if err := util.ReadMarshaled(r, points[i]); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+//	type PriShare struct {
+//		I int          // Index of the private share
+//		V kyber.Scalar // Value of the private share
+//	}
+//
+//nolint:gocritic
+func writePriShare(w io.Writer, val *share.PriShare) error {
+	var err error
+	if err = util.WriteBoolByte(w, val == nil); err != nil {
+		return err
+	}
+	if val == nil {
+		return nil
+	}
+	if err = util.WriteUint32(w, uint32(val.I)); err != nil {
+		return err
+	}
+	return util.WriteMarshaled(w, val.V)
+}
+
+func readPriShare(r io.Reader, suite kyber.Group) (*share.PriShare, error) {
+	var err error
+	var valNil bool
+	if err = util.ReadBoolByte(r, &valNil); err != nil {
+		return nil, err
+	}
+	if valNil {
+		return nil, nil
+	}
+	var i uint32
+	if err = util.ReadUint32(r, &i); err != nil {
+		return nil, err
+	}
+	val := &share.PriShare{I: int(i), V: suite.Scalar()}
+	if err = util.ReadMarshaled(r, val.V); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+//	type rabin_vvs.Deal struct {
+//		SessionID []byte			// Unique session identifier for this protocol run
+//		SecShare *share.PriShare	// Private share generated by the dealer
+//		RndShare *share.PriShare	// Random share generated by the dealer
+//		T uint32					// Threshold used for this secret sharing run
+//		Commitments []kyber.Point	// Commitments are the coefficients used to verify the shares against
+//	}
+//
+//nolint:gocritic
+func writeVssDeal(w io.Writer, d *rabin_vss.Deal) error {
+	var err error
+	if err = util.WriteBytes16(w, d.SessionID); err != nil {
+		return err
+	}
+	if err = writePriShare(w, d.SecShare); err != nil {
+		return err
+	}
+	if err = writePriShare(w, d.RndShare); err != nil {
+		return err
+	}
+	if err = util.WriteUint32(w, d.T); err != nil {
+		return err
+	}
+	if err = util.WriteUint32(w, uint32(len(d.Commitments))); err != nil {
+		return err
+	}
+	for i := range d.Commitments {
+		if err = util.WriteMarshaled(w, d.Commitments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readVssDeal(r io.Reader, suite kyber.Group) (*rabin_vss.Deal, error) {
+	var err error
+	d := rabin_vss.Deal{}
+	if d.SessionID, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	if d.SecShare, err = readPriShare(r, suite); err != nil {
+		return nil, err
+	}
+	if d.RndShare, err = readPriShare(r, suite); err != nil {
+		return nil, err
+	}
+	if err = util.ReadUint32(r, &d.T); err != nil {
+		return nil, err
+	}
+	var commitmentCount uint32
+	if err = util.ReadUint32(r, &commitmentCount); err != nil {
+		return nil, err
+	}
+	d.Commitments = make([]kyber.Point, int(commitmentCount))
+	for i := range d.Commitments {
+		d.Commitments[i] = suite.Point()
+		if err = util.ReadMarshaled(r, d.Commitments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+// Proactive resharing of an existing distributed key.
+//
+// Each member of the current group (a dealer) shares its private key share
+// using a new random polynomial of degree newT-1, whose free coefficient is
+// the dealer's private share. The coefficients of the polynomial are committed
+// publicly (Feldman VSS), so the new members can check each sub-share against
+// the commitments, and the commitments against the public share of the dealer.
+// A new member then interpolates the sub-shares received from any oldT dealers
+// at zero to obtain its new private share. The shared public key (and thus the
+// shared address) remains the same, while all the private shares change.
+//
+// See "Verifiable Secret Redistribution for Threshold Signing Schemes" by
+// T. Wong, C. Wang and J. Wing, CMU-CS-02-114.
+
+import (
+	"crypto/cipher"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"golang.org/x/xerrors"
+)
+
+// ReshareDeal is produced by a member of the current group to move
+// its key share to a new group of nodes.
+type ReshareDeal struct {
+	Commits   []kyber.Point     // Public commitments of the dealer's polynomial, Commits[0] is the dealer's public share.
+	SubShares []*share.PriShare // A sub-share for each member of the new group, indexed by the new node index.
+}
+
+func newReshareDeal(group kyber.Group, secret kyber.Scalar, newN, newT uint16, rand cipher.Stream) (*ReshareDeal, error) {
+	if newN < 1 || newT < 1 || newT > newN {
+		return nil, xerrors.Errorf("invalid resharing parameters: N=%v, T=%v", newN, newT)
+	}
+	if secret == nil {
+		return nil, xerrors.New("there is no private share to reshare")
+	}
+	priPoly := share.NewPriPoly(group, int(newT), secret, rand)
+	_, commits := priPoly.Commit(nil).Info()
+	return &ReshareDeal{
+		Commits:   commits,
+		SubShares: priPoly.Shares(int(newN)),
+	}, nil
+}
+
+// VerifyReshareSubShare checks, if a sub-share received from a dealer is consistent with the
+// commitments published by it, and that the dealer has shared its actual private key share.
+// The later is checked by comparing the free coefficient commitment with the known public
+// share of the dealer.
+func VerifyReshareSubShare(group kyber.Group, dealerPublicShare kyber.Point, newT uint16, commits []kyber.Point, subShare *share.PriShare) error {
+	if len(commits) != int(newT) {
+		return xerrors.Errorf("expected %v commitments, received %v", newT, len(commits))
+	}
+	if !commits[0].Equal(dealerPublicShare) {
+		return xerrors.New("dealer has not shared its public share")
+	}
+	if subShare == nil || subShare.V == nil {
+		return xerrors.New("sub-share is missing")
+	}
+	if !share.NewPubPoly(group, nil, commits).Check(subShare) {
+		return xerrors.Errorf("sub-share %v does not match the commitments", subShare.I)
+	}
+	return nil
+}
+
+// RecoverReshared combines the sub-shares received from exactly oldT dealers into
+// the new private share of a node, and the commitments of the dealers into the
+// public commitments of the new shared polynomial. The maps are indexed by the
+// dealer indexes in the old group. The free coefficient of the resulting commitments
+// is the shared public key, which must be the same as before the resharing.
+func RecoverReshared(
+	group kyber.Group,
+	oldN, oldT uint16,
+	dealerCommits map[uint16][]kyber.Point,
+	dealerSubShares map[uint16]*share.PriShare,
+) (*share.PriShare, []kyber.Point, error) {
+	if len(dealerCommits) != int(oldT) || len(dealerSubShares) != int(oldT) {
+		return nil, nil, xerrors.Errorf("expected deals from exactly %v dealers, have %v commits and %v sub-shares", oldT, len(dealerCommits), len(dealerSubShares))
+	}
+	var newIndex int
+	var newT int
+	priShares := make([]*share.PriShare, 0, oldT)
+	for dealerIdx, subShare := range dealerSubShares {
+		commits, ok := dealerCommits[dealerIdx]
+		if !ok {
+			return nil, nil, xerrors.Errorf("have no commitments from the dealer %v", dealerIdx)
+		}
+		if len(priShares) == 0 {
+			newIndex = subShare.I
+			newT = len(commits)
+		} else if newIndex != subShare.I || newT != len(commits) {
+			return nil, nil, xerrors.Errorf("inconsistent deal from the dealer %v", dealerIdx)
+		}
+		priShares = append(priShares, &share.PriShare{I: int(dealerIdx), V: subShare.V})
+	}
+	priShareValue, err := share.RecoverSecret(group, priShares, int(oldT), int(oldN))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot recover the private share: %w", err)
+	}
+	commits := make([]kyber.Point, newT)
+	for k := range commits {
+		pubShares := make([]*share.PubShare, 0, oldT)
+		for dealerIdx := range dealerCommits {
+			pubShares = append(pubShares, &share.PubShare{I: int(dealerIdx), V: dealerCommits[dealerIdx][k]})
+		}
+		if commits[k], err = share.RecoverCommit(group, pubShares, int(oldT), int(oldN)); err != nil {
+			return nil, nil, xerrors.Errorf("cannot recover the public commitments: %w", err)
+		}
+	}
+	return &share.PriShare{I: newIndex, V: priShareValue}, commits, nil
+}
+
+// ResharedPublicShares derives the public shares of all the n members of the
+// new group from the public commitments produced by RecoverReshared.
+func ResharedPublicShares(group kyber.Group, commits []kyber.Point, n uint16) []kyber.Point {
+	pubShares := share.NewPubPoly(group, nil, commits).Shares(int(n))
+	result := make([]kyber.Point, n)
+	for i := range pubShares {
+		result[i] = pubShares[i].V
+	}
+	return result
+}
+
+// RecoverMissingPublicShares derives the public shares of the members, which have
+// not reported them, by interpolating at least t of the known ones. The members of
+// the new group, which could not recover their private shares, are left out of the
+// resharing, but their public shares are still needed to verify the signatures. The
+// interpolated polynomial must have the given shared public key as its free term.
+func RecoverMissingPublicShares(group kyber.Group, known map[int]kyber.Point, sharedPublic kyber.Point, t, n uint16) ([]kyber.Point, error) {
+	pubShares := make([]*share.PubShare, 0, len(known))
+	for i, v := range known {
+		pubShares = append(pubShares, &share.PubShare{I: i, V: v})
+	}
+	pubPoly, err := share.RecoverPubPoly(group, pubShares, int(t), int(n))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot interpolate the public shares: %w", err)
+	}
+	if !pubPoly.Commit().Equal(sharedPublic) {
+		return nil, xerrors.New("public shares do not match the shared public key")
+	}
+	result := make([]kyber.Point, n)
+	for i := range result {
+		if v, ok := known[i]; ok {
+			result[i] = v
+		} else {
+			result[i] = pubPoly.Eval(i).V
+		}
+	}
+	return result, nil
+}
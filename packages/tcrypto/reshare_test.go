@@ -0,0 +1,39 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/suites"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestRecoverMissingPublicShares(t *testing.T) {
+	edSuite, err := suites.Find("Ed25519")
+	require.NoError(t, err)
+	var n, th uint16 = 7, 5
+	secret := edSuite.Scalar().Pick(random.New())
+	pubPoly := share.NewPriPoly(edSuite, int(th), secret, random.New()).Commit(nil)
+	all := pubPoly.Shares(int(n))
+
+	known := map[int]kyber.Point{}
+	for _, i := range []int{0, 2, 3, 5, 6} {
+		known[i] = all[i].V
+	}
+	recovered, err := RecoverMissingPublicShares(edSuite, known, pubPoly.Commit(), th, n)
+	require.NoError(t, err)
+	for i := range all {
+		require.True(t, all[i].V.Equal(recovered[i]), "share %v", i)
+	}
+
+	_, err = RecoverMissingPublicShares(edSuite, known, edSuite.Point().Pick(random.New()), th, n)
+	require.Error(t, err)
+	delete(known, 0)
+	_, err = RecoverMissingPublicShares(edSuite, known, pubPoly.Commit(), th, n)
+	require.Error(t, err)
+}
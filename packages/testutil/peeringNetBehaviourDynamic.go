@@ -92,6 +92,18 @@ func (pndT *PeeringNetDynamic) WithPeerDisconnected(id *string, peerPubKey *cryp
 	return pndT
 }
 
+// WithPartition isolates the specified peers from the rest of the network.
+// The peers in the partition can still communicate with each other.
+func (pndT *PeeringNetDynamic) WithPartition(id *string, peerPubKeys []*cryptolib.PublicKey) *PeeringNetDynamic {
+	pndT.addHandlerEntry(peeringNetDynamicHandlerEntry{
+		id,
+		&peeringNetDynamicHandlerPartition{
+			peerPubKeys: peerPubKeys,
+		},
+	})
+	return pndT
+}
+
 func (pndT *PeeringNetDynamic) addHandlerEntry(handler peeringNetDynamicHandlerEntry) {
 	pndT.mutex.Lock()
 	defer pndT.mutex.Unlock()
@@ -248,3 +260,30 @@ func (pdT *peeringNetDynamicHandlerPeerDisconnected) handleSendMessage(
 	}
 	callHandlersAndSendFun(nextHandlers)
 }
+
+type peeringNetDynamicHandlerPartition struct {
+	peerPubKeys []*cryptolib.PublicKey
+}
+
+func (pT *peeringNetDynamicHandlerPartition) handleSendMessage(
+	msg *peeringMsg,
+	dstPubKey *cryptolib.PublicKey,
+	nextHandlers []peeringNetDynamicHandlerEntry,
+	callHandlersAndSendFun func(nextHandlers []peeringNetDynamicHandlerEntry),
+	log *logger.Logger,
+) {
+	if pT.contains(msg.from) != pT.contains(dstPubKey) {
+		log.Debugf("Network dropped message %v -%v-> %v, because of the partition", msg.from.String(), msg.msg.MsgType, dstPubKey.String())
+		return
+	}
+	callHandlersAndSendFun(nextHandlers)
+}
+
+func (pT *peeringNetDynamicHandlerPartition) contains(pubKey *cryptolib.PublicKey) bool {
+	for _, peerPubKey := range pT.peerPubKeys {
+		if peerPubKey.Equals(pubKey) {
+			return true
+		}
+	}
+	return false
+}
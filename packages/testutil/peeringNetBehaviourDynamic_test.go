@@ -252,6 +252,43 @@ func TestPeeringNetDynamicPeerDisconnected(t *testing.T) {
 	behavior.Close()
 }
 
+func TestPeeringNetDynamicPartition(t *testing.T) {
+	inChI := make(chan *peeringMsg)
+	outChI := make(chan *peeringMsg, 1000)
+	inChO := make(chan *peeringMsg)
+	outChO := make(chan *peeringMsg, 1000)
+	stopCh := make(chan bool)
+	durationsI := make([]time.Duration, 0)
+	durationsO := make([]time.Duration, 0)
+	go testRecvLoop(outChI, &durationsI, stopCh)
+	go testRecvLoop(outChO, &durationsO, stopCh)
+	srcPeerIdentity := cryptolib.NewKeyPair()
+	inPeerIdentity := cryptolib.NewKeyPair()
+	outPeerIdentity := cryptolib.NewKeyPair()
+	srcNode := peeringNode{netID: "src", identity: srcPeerIdentity}
+	outNode := peeringNode{netID: "out", identity: outPeerIdentity}
+	//
+	// Run the test.
+	partition := []*cryptolib.PublicKey{srcPeerIdentity.GetPublicKey(), inPeerIdentity.GetPublicKey()}
+	behavior := NewPeeringNetDynamic(testlogger.WithLevel(testlogger.NewLogger(t), logger.LevelError, false)).WithPartition(nil, partition)
+	behavior.AddLink(inChI, outChI, inPeerIdentity.GetPublicKey())
+	behavior.AddLink(inChO, outChO, outPeerIdentity.GetPublicKey())
+	for i := 0; i < 100; i++ {
+		sendMessage(&srcNode, inChI) // Will be received - both peers are in the partition
+		sendMessage(&srcNode, inChO) // Won't be received - destination is outside the partition
+		sendMessage(&outNode, inChI) // Won't be received - source is outside the partition
+		sendMessage(&outNode, inChO) // Will be received - both peers are outside the partition
+	}
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 100, len(durationsI))
+	require.Equal(t, 100, len(durationsO))
+
+	// Stop the test.
+	stopCh <- true
+	stopCh <- true
+	behavior.Close()
+}
+
 func testRecvLoop(outCh chan *peeringMsg, durations *[]time.Duration, stopCh chan bool) {
 	for {
 		select {
@@ -6,6 +6,7 @@ package testutil
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/iotaledger/hive.go/logger"
@@ -60,15 +61,6 @@ func (p *PeeringNetwork) NetworkProviders() []peering.NetworkProvider {
 	return cp
 }
 
-func (p *PeeringNetwork) nodeByPubKey(nodePubKey *cryptolib.PublicKey) *peeringNode {
-	for i := range p.nodes {
-		if p.nodes[i].identity.GetPublicKey().Equals(nodePubKey) {
-			return p.nodes[i]
-		}
-	}
-	return nil
-}
-
 // Close implements the io.Closer interface.
 func (p *PeeringNetwork) Close() error {
 	for _, n := range p.nodes {
@@ -89,6 +81,7 @@ type peeringNode struct {
 	sendCh   chan *peeringMsg
 	recvCh   chan *peeringMsg
 	recvCbs  []*peeringCb
+	recvLock sync.RWMutex // Guards the recvCbs.
 	network  *PeeringNetwork
 	log      *logger.Logger
 }
@@ -127,7 +120,10 @@ func newPeeringNode(netID string, identity *cryptolib.KeyPair, network *PeeringN
 func (n *peeringNode) recvLoop() {
 	for pm := range n.recvCh {
 		msgPeeringID := pm.msg.PeeringID.String()
-		for _, cb := range n.recvCbs {
+		n.recvLock.RLock()
+		recvCbs := n.recvCbs
+		n.recvLock.RUnlock()
+		for _, cb := range recvCbs {
 			if cb.peeringID.String() == msgPeeringID && cb.receiver == pm.msg.MsgReceiver {
 				cb.callback(&peering.PeerMessageIn{
 					PeerMessageData: pm.msg,
@@ -189,11 +185,11 @@ func (p *peeringNetworkProvider) Self() peering.PeerSender {
 func (p *peeringNetworkProvider) PeerGroup(peeringID peering.PeeringID, peerPubKeys []*cryptolib.PublicKey) (peering.GroupProvider, error) {
 	peers := make([]peering.PeerSender, len(peerPubKeys))
 	for i := range peerPubKeys {
-		n := p.network.nodeByPubKey(peerPubKeys[i])
-		if n == nil {
+		peer, err := p.PeerByPubKey(peerPubKeys[i])
+		if err != nil {
 			return nil, errors.New("unknown node location")
 		}
-		peers[i] = p.senders[i]
+		peers[i] = peer
 	}
 	return group.NewPeeringGroupProvider(p, peeringID, peers, p.log)
 }
@@ -202,11 +198,11 @@ func (p *peeringNetworkProvider) PeerGroup(peeringID peering.PeeringID, peerPubK
 func (p *peeringNetworkProvider) PeerDomain(peeringID peering.PeeringID, peerPubKeys []*cryptolib.PublicKey) (peering.PeerDomainProvider, error) {
 	peers := make([]peering.PeerSender, len(peerPubKeys))
 	for i := range peerPubKeys {
-		n := p.network.nodeByPubKey(peerPubKeys[i])
-		if n == nil {
+		peer, err := p.PeerByPubKey(peerPubKeys[i])
+		if err != nil {
 			return nil, errors.New("unknown node pub key")
 		}
-		peers[i] = p.senders[i]
+		peers[i] = peer
 	}
 	return domain.NewPeerDomain(p, peeringID, peers, p.log), nil
 }
@@ -217,18 +213,29 @@ func (p *peeringNetworkProvider) Attach(
 	receiver byte,
 	callback func(recv *peering.PeerMessageIn),
 ) interface{} {
-	p.self.recvCbs = append(p.self.recvCbs, &peeringCb{
+	cb := &peeringCb{
 		callback:  callback,
 		destNP:    p,
 		peeringID: peeringID,
 		receiver:  receiver,
-	})
-	return nil // We don't care on the attachIDs for now.
+	}
+	p.self.recvLock.Lock()
+	defer p.self.recvLock.Unlock()
+	p.self.recvCbs = append(p.self.recvCbs, cb)
+	return cb
 }
 
 // Detach implements peering.NetworkProvider.
 func (p *peeringNetworkProvider) Detach(attachID interface{}) {
-	// Detach is not important in tests.
+	p.self.recvLock.Lock()
+	defer p.self.recvLock.Unlock()
+	recvCbs := make([]*peeringCb, 0, len(p.self.recvCbs))
+	for _, cb := range p.self.recvCbs {
+		if cb != attachID {
+			recvCbs = append(recvCbs, cb)
+		}
+	}
+	p.self.recvCbs = recvCbs // A new slice, as the receive loop can iterate over the old one.
 }
 
 func (p *peeringNetworkProvider) SendMsgByPubKey(peerPubKey *cryptolib.PublicKey, msg *peering.PeerMessageData) {
@@ -131,6 +131,12 @@ func (m *MockedChainCore) EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequ
 	m.onOffLedgerRequest(msg)
 }
 
+func (m *MockedChainCore) EnqueueOffLedgerRequestMsgs(msgs []*messages.OffLedgerRequestMsgIn) {
+	for _, msg := range msgs {
+		m.onOffLedgerRequest(msg)
+	}
+}
+
 func (m *MockedChainCore) EnqueueMissingRequestIDsMsg(msg *messages.MissingRequestIDsMsgIn) {
 	m.onMissingRequestIDs(msg)
 }
@@ -11,6 +11,7 @@ import (
 type mockedConsensusJournalRegistryImpl struct {
 	li map[journal.ID]journal.LogIndex
 	lv map[journal.ID][]byte
+	d  map[journal.ID]map[journal.LogIndex][]byte
 }
 
 var _ journal.Registry = &mockedConsensusJournalRegistryImpl{}
@@ -19,6 +20,7 @@ func NewMockedConsensusJournalRegistry() journal.Registry {
 	return &mockedConsensusJournalRegistryImpl{
 		li: map[journal.ID]journal.LogIndex{},
 		lv: map[journal.ID][]byte{},
+		d:  map[journal.ID]map[journal.LogIndex][]byte{},
 	}
 }
 
@@ -33,6 +35,25 @@ func (m *mockedConsensusJournalRegistryImpl) SaveConsensusJournalLocalView(id jo
 	return err
 }
 
+func (m *mockedConsensusJournalRegistryImpl) SaveConsensusJournalDecision(id journal.ID, decision *journal.Decision) error {
+	decisionBytes, err := decision.AsBytes()
+	if err != nil {
+		return err
+	}
+	if _, ok := m.d[id]; !ok {
+		m.d[id] = map[journal.LogIndex][]byte{}
+	}
+	m.d[id][decision.LogIndex] = decisionBytes
+	return nil
+}
+
+func (m *mockedConsensusJournalRegistryImpl) PruneConsensusJournalDecisions(id journal.ID, before journal.LogIndex) error {
+	if before > 0 {
+		delete(m.d[id], before-1)
+	}
+	return nil
+}
+
 func (m *mockedConsensusJournalRegistryImpl) LoadConsensusJournal(id journal.ID) (journal.LogIndex, journal.LocalView, error) {
 	lvBytes, found := m.lv[id]
 	if !found {
@@ -0,0 +1,232 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package testcluster runs a committee of Wasp nodes in-process, without Solo and
+// without external binaries. The nodes have independent databases, communicate
+// over the in-memory peering network and use a local stand-in of the L1 network.
+// Faults (node crashes, network partitions, message delays and losses) can be
+// injected into a running cluster to test the chain and its contracts in the
+// byzantine scenarios.
+package testcluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/dkg"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/testutil/testpeers"
+	"github.com/iotaledger/wasp/packages/transaction"
+	"github.com/iotaledger/wasp/packages/vm/core/coreprocessors"
+	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+const initTimeout = 60 * time.Second
+
+// Config of the cluster to start.
+type Config struct {
+	N               int                // Number of the nodes in the committee.
+	T               int                // Threshold (quorum) of the committee.
+	ProcessorConfig *processors.Config // The core contracts are used, if nil.
+	InitParams      dict.Dict          // Parameters of the chain init request, optional.
+	Log             *logger.Logger     // A test logger is used, if nil.
+}
+
+// Cluster is a committee of nodes, running a single chain.
+type Cluster struct {
+	Nodes            []*Node
+	L1               *L1
+	ChainID          *isc.ChainID
+	CommitteeAddress iotago.Address
+	Originator       *cryptolib.KeyPair // Deployed the chain, it is the governor of the chain.
+	netBehavior      *testutil.PeeringNetDynamic
+	peeringNetwork   *testutil.PeeringNetwork
+	faultIndex       int
+	t                *testing.T
+	log              *logger.Logger
+}
+
+// New starts a cluster with a new chain, deployed on a new committee.
+// It returns after the chain is initialized.
+func New(t *testing.T, config *Config) *Cluster {
+	log := config.Log
+	if log == nil {
+		log = testlogger.NewLogger(t)
+	}
+	procConfig := config.ProcessorConfig
+	if procConfig == nil {
+		procConfig = coreprocessors.Config()
+	}
+	c := &Cluster{
+		Nodes:       make([]*Node, config.N),
+		L1:          newL1(log),
+		Originator:  cryptolib.NewKeyPair(),
+		netBehavior: testutil.NewPeeringNetDynamic(log),
+		t:           t,
+		log:         log,
+	}
+	t.Cleanup(c.Close)
+	//
+	// Nodes and the peering network between them.
+	peerNetIDs := make([]string, config.N)
+	peerIdentities := make([]*cryptolib.KeyPair, config.N)
+	for i := range c.Nodes {
+		c.Nodes[i] = newNode(i, procConfig, c.L1, log.Named(fmt.Sprintf("node%02d", i)))
+		peerNetIDs[i] = fmt.Sprintf("node%02d:4000", i)
+		peerIdentities[i] = c.Nodes[i].Identity()
+	}
+	c.peeringNetwork = testutil.NewPeeringNetwork(
+		peerNetIDs, peerIdentities, 10000, c.netBehavior,
+		testlogger.WithLevel(log, logger.LevelWarn, false),
+	)
+	for i, netProvider := range c.peeringNetwork.NetworkProviders() {
+		c.Nodes[i].netProvider = netProvider
+	}
+	//
+	// The committee keys are generated by the nodes themselves.
+	dkgNodes := make([]*dkg.Node, config.N)
+	for i, node := range c.Nodes {
+		dkgNode, err := dkg.NewNode(node.Registry.GetNodeIdentity(), node.netProvider, node.log)
+		require.NoError(t, err)
+		dkgNodes[i] = dkgNode
+	}
+	dkShare, err := dkgNodes[0].GenerateDistributedKey(
+		testpeers.PublicKeys(peerIdentities),
+		uint16(config.T),
+		100*time.Second,
+		200*time.Second,
+		300*time.Second,
+	)
+	require.NoError(t, err)
+	c.CommitteeAddress = dkShare.GetAddress()
+	//
+	// Deploy the chain and start it on all the nodes.
+	var initReqID isc.RequestID
+	c.ChainID, initReqID = c.deployChain(config.InitParams)
+	for _, node := range c.Nodes {
+		node.chainID = c.ChainID
+		require.NoError(t, node.start())
+	}
+	require.NoError(t, c.AwaitRequestProcessed(initReqID, initTimeout))
+	return c
+}
+
+func (c *Cluster) deployChain(initParams dict.Dict) (*isc.ChainID, isc.RequestID) {
+	originatorAddr := c.Originator.GetPublicKey().AsEd25519Address()
+	require.NoError(c.t, c.L1.GetFundsFromFaucet(originatorAddr))
+	outs, outIDs := c.L1.UtxoDB().GetUnspentOutputs(originatorAddr)
+	originTx, chainID, err := transaction.NewChainOriginTransaction(
+		c.Originator,
+		c.CommitteeAddress,
+		originatorAddr,
+		0,
+		outs,
+		outIDs,
+	)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.L1.AddToLedger(originTx))
+	outs, outIDs = c.L1.UtxoDB().GetUnspentOutputs(originatorAddr)
+	var initParamsList []dict.Dict
+	if initParams != nil {
+		initParamsList = append(initParamsList, initParams)
+	}
+	initTx, err := transaction.NewRootInitRequestTransaction(
+		c.Originator,
+		chainID,
+		"testcluster chain",
+		outs,
+		outIDs,
+		initParamsList...,
+	)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.L1.AddToLedger(initTx))
+	initTxID, err := initTx.ID()
+	require.NoError(c.t, err)
+	c.log.Infof("Chain %v deployed on the committee %v", chainID, c.CommitteeAddress)
+	return chainID, isc.NewRequestID(initTxID, 0)
+}
+
+// Close stops all the nodes and the networks. It is called on the test cleanup.
+func (c *Cluster) Close() {
+	for _, node := range c.Nodes {
+		if node != nil {
+			node.stop()
+		}
+	}
+	if c.peeringNetwork != nil {
+		if err := c.peeringNetwork.Close(); err != nil {
+			c.log.Warnf("Failed to close the peering network: %v", err)
+		}
+		c.peeringNetwork = nil
+	}
+	if c.L1 != nil {
+		c.L1.Close()
+		c.L1 = nil
+	}
+}
+
+// PostRequest posts an on-ledger request to the chain. The sender pays for
+// the storage deposit and the attached tokens from its L1 address.
+func (c *Cluster) PostRequest(sender *cryptolib.KeyPair, metadata *isc.SendMetadata, tokens *isc.FungibleTokens) (isc.RequestID, error) {
+	senderAddr := sender.GetPublicKey().AsEd25519Address()
+	outs, outIDs := c.L1.UtxoDB().GetUnspentOutputs(senderAddr)
+	tx, err := transaction.NewRequestTransaction(transaction.NewRequestTransactionParams{
+		SenderKeyPair:    sender,
+		SenderAddress:    senderAddr,
+		UnspentOutputs:   outs,
+		UnspentOutputIDs: outIDs,
+		Request: &isc.RequestParameters{
+			TargetAddress:                 c.ChainID.AsAddress(),
+			FungibleTokens:                tokens,
+			AdjustToMinimumStorageDeposit: true,
+			Metadata:                      metadata,
+		},
+	})
+	if err != nil {
+		return isc.RequestID{}, xerrors.Errorf("cannot create a request transaction: %w", err)
+	}
+	if err := c.L1.AddToLedger(tx); err != nil {
+		return isc.RequestID{}, xerrors.Errorf("cannot add the request transaction to L1: %w", err)
+	}
+	txID, err := tx.ID()
+	if err != nil {
+		return isc.RequestID{}, err
+	}
+	return isc.NewRequestID(txID, 0), nil
+}
+
+// PostOffLedgerRequest sends an off-ledger request to the first running node.
+func (c *Cluster) PostOffLedgerRequest(req isc.OffLedgerRequest) error {
+	for _, node := range c.Nodes {
+		if node.IsRunning() {
+			return node.PostOffLedgerRequest(req)
+		}
+	}
+	return xerrors.New("there are no running nodes in the cluster")
+}
+
+// AwaitRequestProcessed waits until the request is processed by all the running nodes.
+func (c *Cluster) AwaitRequestProcessed(reqID isc.RequestID, timeout time.Duration) error {
+	for _, node := range c.Nodes {
+		if !node.IsRunning() {
+			continue
+		}
+		receipt, err := node.AwaitRequestProcessed(reqID, timeout)
+		if err != nil {
+			return err
+		}
+		if receipt.Error != nil {
+			return xerrors.Errorf("request %v failed: %v", reqID, receipt.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/testutil/testcluster"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/stretchr/testify/require"
+)
+
+const requestTimeout = 60 * time.Second
+
+func TestClusterFaults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the in-process cluster test in the short mode")
+	}
+	log := testlogger.WithLevel(testlogger.NewLogger(t), logger.LevelInfo, false)
+	defer log.Sync()
+	c := testcluster.New(t, &testcluster.Config{N: 4, T: 3, Log: log})
+
+	user := cryptolib.NewKeyPair()
+	require.NoError(t, c.L1.GetFundsFromFaucet(user.Address()))
+	deposit := func() isc.RequestID {
+		reqID, err := c.PostRequest(user, &isc.SendMetadata{
+			TargetContract: accounts.Contract.Hname(),
+			EntryPoint:     accounts.FuncDeposit.Hname(),
+			GasBudget:      gas.MaxGasPerCall,
+		}, isc.NewFungibleBaseTokens(1*isc.Million))
+		require.NoError(t, err)
+		return reqID
+	}
+	require.NoError(t, c.AwaitRequestProcessed(deposit(), requestTimeout))
+
+	// The committee tolerates a single faulty node.
+	c.KillNode(3)
+	require.NoError(t, c.AwaitRequestProcessed(deposit(), requestTimeout))
+
+	// The restarted node catches up with the chain.
+	c.RestartNode(3)
+	require.NoError(t, c.AwaitRequestProcessed(deposit(), requestTimeout))
+
+	// The requests are processed after the partition is healed.
+	partition := c.Partition(0, 1)
+	reqID := deposit()
+	_, err := c.Nodes[2].AwaitRequestProcessed(reqID, 5*time.Second)
+	require.Error(t, err)
+	c.RemoveFault(partition)
+	require.NoError(t, c.AwaitRequestProcessed(reqID, requestTimeout))
+
+	// And with the messages delayed.
+	c.DelayMessages(10*time.Millisecond, 50*time.Millisecond)
+	require.NoError(t, c.AwaitRequestProcessed(deposit(), requestTimeout))
+
+	// All the deposits are on the restarted node as well.
+	res, err := c.Nodes[3].CallView(accounts.Contract.Hname(), accounts.ViewBalanceBaseToken.Hname(), dict.Dict{
+		accounts.ParamAgentID: codec.EncodeAgentID(isc.NewAgentID(user.Address())),
+	})
+	require.NoError(t, err)
+	balance, err := codec.DecodeUint64(res.MustGet(accounts.ParamBalance))
+	require.NoError(t, err)
+	require.Greater(t, balance, 4*isc.Million)
+}
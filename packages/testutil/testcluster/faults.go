@@ -0,0 +1,77 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/stretchr/testify/require"
+)
+
+// KillNode stops the node and disconnects it from the network.
+// Its registry and the chain state are kept for the restart.
+func (c *Cluster) KillNode(index int) {
+	node := c.Nodes[index]
+	if !node.IsRunning() {
+		return
+	}
+	c.netBehavior.WithPeerDisconnected(killFaultID(index), node.Identity().GetPublicKey())
+	node.stop()
+	c.log.Infof("Node %v killed", index)
+}
+
+// RestartNode connects the killed node back to the network and starts it again.
+func (c *Cluster) RestartNode(index int) {
+	c.netBehavior.RemoveHandler(*killFaultID(index))
+	require.NoError(c.t, c.Nodes[index].start())
+	c.log.Infof("Node %v restarted", index)
+}
+
+// Partition isolates the specified nodes from the rest of the cluster.
+// The returned fault ID can be used to heal the partition with RemoveFault.
+func (c *Cluster) Partition(indexes ...int) string {
+	pubKeys := make([]*cryptolib.PublicKey, len(indexes))
+	for i, index := range indexes {
+		pubKeys[i] = c.Nodes[index].Identity().GetPublicKey()
+	}
+	id := c.nextFaultID("partition")
+	c.netBehavior.WithPartition(&id, pubKeys)
+	c.log.Infof("Nodes %v partitioned from the cluster, fault %v", indexes, id)
+	return id
+}
+
+// DelayMessages delays each message in the network by a random duration in the specified range.
+func (c *Cluster) DelayMessages(delayFrom, delayTill time.Duration) string {
+	id := c.nextFaultID("delay")
+	c.netBehavior.WithDelayingChannel(&id, delayFrom, delayTill)
+	c.log.Infof("Messages delayed by %v-%v, fault %v", delayFrom, delayTill, id)
+	return id
+}
+
+// LoseMessages drops the messages in the network. Only the specified
+// percentage of the messages is delivered.
+func (c *Cluster) LoseMessages(deliveryProbability int) string {
+	id := c.nextFaultID("lose")
+	c.netBehavior.WithLosingChannel(&id, deliveryProbability)
+	c.log.Infof("Messages delivered with %v%% probability, fault %v", deliveryProbability, id)
+	return id
+}
+
+// RemoveFault stops the network fault, injected by Partition, DelayMessages or LoseMessages.
+func (c *Cluster) RemoveFault(id string) {
+	require.True(c.t, c.netBehavior.RemoveHandler(id), "fault %v not found", id)
+	c.log.Infof("Fault %v removed", id)
+}
+
+func (c *Cluster) nextFaultID(kind string) string {
+	c.faultIndex++
+	return fmt.Sprintf("%s-%d", kind, c.faultIndex)
+}
+
+func killFaultID(index int) *string {
+	id := fmt.Sprintf("kill-%d", index)
+	return &id
+}
@@ -0,0 +1,365 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/util/pipe"
+	"github.com/iotaledger/wasp/packages/utxodb"
+	"golang.org/x/xerrors"
+)
+
+const milestonePeriod = 100 * time.Millisecond
+
+// L1 is a local stand-in for the L1 network, shared by all the nodes of the cluster.
+// Transactions are validated and confirmed synchronously by the underlying UTXODB
+// ledger, and the new outputs are pushed to the nodes, as the real node connection does.
+type L1 struct {
+	utxoDB     *utxodb.UtxoDB
+	conns      map[*l1NodeConn]bool
+	milestones *events.Event
+	stopCh     chan bool
+	mutex      sync.RWMutex
+	log        *logger.Logger
+}
+
+func newL1(log *logger.Logger) *L1 {
+	l1 := &L1{
+		utxoDB: utxodb.New(utxodb.DefaultInitParams().WithInitialTime(time.Now())),
+		conns:  map[*l1NodeConn]bool{},
+		milestones: events.NewEvent(func(handler interface{}, params ...interface{}) {
+			handler.(chain.NodeConnectionMilestonesHandlerFun)(params[0].(*nodeclient.MilestoneInfo))
+		}),
+		stopCh: make(chan bool),
+		log:    log.Named("l1"),
+	}
+	go l1.milestonesLoop()
+	return l1
+}
+
+// UtxoDB gives a direct access to the underlying ledger. Transactions
+// added to it directly are not pushed to the nodes, use AddToLedger for that.
+func (l1 *L1) UtxoDB() *utxodb.UtxoDB {
+	return l1.utxoDB
+}
+
+// GetFundsFromFaucet sends base tokens from the genesis address to the target address.
+func (l1 *L1) GetFundsFromFaucet(target iotago.Address, amount ...uint64) error {
+	_, err := l1.utxoDB.GetFundsFromFaucet(target, amount...)
+	return err
+}
+
+// AddToLedger confirms the transaction and notifies the nodes about the outputs it creates.
+func (l1 *L1) AddToLedger(tx *iotago.Transaction) error {
+	l1.mutex.Lock()
+	defer l1.mutex.Unlock()
+	txID, err := tx.ID()
+	if err != nil {
+		return xerrors.Errorf("cannot calculate transaction ID: %w", err)
+	}
+	if err := l1.utxoDB.AddToLedger(tx); err != nil {
+		return err
+	}
+	l1.log.Debugf("Transaction %v confirmed", isc.TxID(txID))
+	for i, output := range tx.Essence.Outputs {
+		outputID := iotago.OutputIDFromTransactionIDAndIndex(txID, uint16(i))
+		for conn := range l1.conns {
+			conn.handleOutput(outputID, output)
+		}
+	}
+	for conn := range l1.conns {
+		conn.handleInclusionState(txID, "included")
+	}
+	return nil
+}
+
+// NewNodeConnection creates a connection of a single node to this L1.
+func (l1 *L1) NewNodeConnection() chain.NodeConnection {
+	conn := &l1NodeConn{
+		l1:      l1,
+		chains:  map[isc.ChainID]*l1Chain{},
+		metrics: nodeconnmetrics.NewEmptyNodeConnectionMetrics(),
+	}
+	l1.mutex.Lock()
+	defer l1.mutex.Unlock()
+	l1.conns[conn] = true
+	return conn
+}
+
+// Close stops generating the milestones.
+func (l1 *L1) Close() {
+	close(l1.stopCh)
+}
+
+func (l1 *L1) milestonesLoop() {
+	index := uint32(0)
+	for {
+		select {
+		case <-l1.stopCh:
+			return
+		case now := <-time.After(milestonePeriod):
+			l1.milestones.Trigger(&nodeclient.MilestoneInfo{
+				Index:     index,
+				Timestamp: uint32(now.Unix()),
+			})
+			index++
+		}
+	}
+}
+
+// inclusionState returns the inclusion state of the transaction, as
+// the consensus expects it. Conflicting transactions are not detected.
+func (l1 *L1) inclusionState(txID iotago.TransactionID) string {
+	if _, ok := l1.utxoDB.GetTransaction(txID); ok {
+		return "included"
+	}
+	return "noTransaction"
+}
+
+// l1NodeConn implements chain.NodeConnection on top of the L1 stand-in.
+type l1NodeConn struct {
+	l1      *L1
+	chains  map[isc.ChainID]*l1Chain
+	metrics nodeconnmetrics.NodeConnectionMetrics
+}
+
+var _ chain.NodeConnection = &l1NodeConn{}
+
+// l1Chain delivers the events of a single chain to a node in order and
+// without blocking the L1, as the handlers are blocking until consumed.
+type l1Chain struct {
+	chainID            isc.ChainID
+	stateOutputHandler func(iotago.OutputID, iotago.Output)
+	outputHandler      func(iotago.OutputID, iotago.Output)
+	inclusionStates    *events.Event
+	eventPipe          pipe.Pipe
+}
+
+func newL1Chain(chainID *isc.ChainID, stateOutputHandler, outputHandler func(iotago.OutputID, iotago.Output)) *l1Chain {
+	ch := &l1Chain{
+		chainID:            *chainID,
+		stateOutputHandler: stateOutputHandler,
+		outputHandler:      outputHandler,
+		inclusionStates: events.NewEvent(func(handler interface{}, params ...interface{}) {
+			handler.(chain.NodeConnectionInclusionStateHandlerFun)(params[0].(iotago.TransactionID), params[1].(string))
+		}),
+		eventPipe: pipe.NewDefaultInfinitePipe(),
+	}
+	go func() {
+		for event := range ch.eventPipe.Out() {
+			event.(func())()
+		}
+	}()
+	return ch
+}
+
+func (ch *l1Chain) enqueue(event func()) {
+	ch.eventPipe.In() <- event
+}
+
+// RegisterChain implements chain.NodeConnection.
+// The current state output and the unspent outputs of the chain are sent to the node right away.
+func (nc *l1NodeConn) RegisterChain(chainID *isc.ChainID, stateOutputHandler, outputHandler func(iotago.OutputID, iotago.Output)) {
+	nc.l1.mutex.Lock()
+	defer nc.l1.mutex.Unlock()
+	nc.metrics.SetRegistered(chainID)
+	ch := newL1Chain(chainID, stateOutputHandler, outputHandler)
+	nc.chains[*chainID] = ch
+	for outputID, output := range nc.l1.utxoDB.GetAliasOutputs(chainID.AsAddress()) {
+		outputID, output := outputID, output
+		ch.enqueue(func() { ch.stateOutputHandler(outputID, output) })
+	}
+	outputs, _ := nc.l1.utxoDB.GetUnspentOutputs(chainID.AsAddress())
+	for outputID, output := range outputs {
+		if _, ok := output.(*iotago.AliasOutput); ok || !shouldBeProcessed(output) {
+			continue
+		}
+		outputID, output := outputID, output
+		ch.enqueue(func() { ch.outputHandler(outputID, output) })
+	}
+}
+
+// UnregisterChain implements chain.NodeConnection.
+func (nc *l1NodeConn) UnregisterChain(chainID *isc.ChainID) {
+	nc.l1.mutex.Lock()
+	defer nc.l1.mutex.Unlock()
+	nc.metrics.SetUnregistered(chainID)
+	if ch, ok := nc.chains[*chainID]; ok {
+		ch.eventPipe.Close()
+		delete(nc.chains, *chainID)
+	}
+}
+
+// PublishStateTransaction implements chain.NodeConnection.
+// The same transaction is usually published by several nodes of the committee.
+func (nc *l1NodeConn) PublishStateTransaction(chainID *isc.ChainID, stateIndex uint32, tx *iotago.Transaction) error {
+	err := nc.l1.AddToLedger(tx)
+	if err == nil {
+		return nil
+	}
+	txID, idErr := tx.ID()
+	if idErr == nil && nc.l1.inclusionState(txID) == "included" {
+		return nil
+	}
+	return xerrors.Errorf("cannot publish state transaction for state %v: %w", stateIndex, err)
+}
+
+// PublishGovernanceTransaction implements chain.NodeConnection.
+func (nc *l1NodeConn) PublishGovernanceTransaction(chainID *isc.ChainID, tx *iotago.Transaction) error {
+	err := nc.l1.AddToLedger(tx)
+	if err == nil {
+		return nil
+	}
+	txID, idErr := tx.ID()
+	if idErr == nil && nc.l1.inclusionState(txID) == "included" {
+		return nil
+	}
+	return xerrors.Errorf("cannot publish governance transaction: %w", err)
+}
+
+// PullLatestOutput implements chain.NodeConnection.
+func (nc *l1NodeConn) PullLatestOutput(chainID *isc.ChainID) {
+	nc.l1.mutex.RLock()
+	defer nc.l1.mutex.RUnlock()
+	ch, ok := nc.chains[*chainID]
+	if !ok {
+		return
+	}
+	for outputID, output := range nc.l1.utxoDB.GetAliasOutputs(chainID.AsAddress()) {
+		outputID, output := outputID, output
+		ch.enqueue(func() { ch.stateOutputHandler(outputID, output) })
+	}
+}
+
+// PullTxInclusionState implements chain.NodeConnection.
+func (nc *l1NodeConn) PullTxInclusionState(chainID *isc.ChainID, txID iotago.TransactionID) {
+	nc.l1.mutex.RLock()
+	defer nc.l1.mutex.RUnlock()
+	ch, ok := nc.chains[*chainID]
+	if !ok {
+		return
+	}
+	state := nc.l1.inclusionState(txID)
+	ch.enqueue(func() { ch.inclusionStates.Trigger(txID, state) })
+}
+
+// PullStateOutputByID implements chain.NodeConnection.
+func (nc *l1NodeConn) PullStateOutputByID(chainID *isc.ChainID, id *iotago.UTXOInput) {
+	nc.l1.mutex.RLock()
+	defer nc.l1.mutex.RUnlock()
+	ch, ok := nc.chains[*chainID]
+	if !ok {
+		return
+	}
+	outputID := id.ID()
+	output := nc.l1.utxoDB.GetOutput(outputID)
+	if output == nil {
+		nc.l1.log.Warnf("PullStateOutputByID: output %v not found", isc.OID(id))
+		return
+	}
+	ch.enqueue(func() { ch.stateOutputHandler(outputID, output) })
+}
+
+// AttachTxInclusionStateEvents implements chain.NodeConnection.
+func (nc *l1NodeConn) AttachTxInclusionStateEvents(chainID *isc.ChainID, handler chain.NodeConnectionInclusionStateHandlerFun) (*events.Closure, error) {
+	nc.l1.mutex.RLock()
+	defer nc.l1.mutex.RUnlock()
+	ch, ok := nc.chains[*chainID]
+	if !ok {
+		return nil, xerrors.Errorf("chain %v is not registered", chainID)
+	}
+	closure := events.NewClosure(handler)
+	ch.inclusionStates.Attach(closure)
+	return closure, nil
+}
+
+// DetachTxInclusionStateEvents implements chain.NodeConnection.
+func (nc *l1NodeConn) DetachTxInclusionStateEvents(chainID *isc.ChainID, closure *events.Closure) error {
+	nc.l1.mutex.RLock()
+	defer nc.l1.mutex.RUnlock()
+	ch, ok := nc.chains[*chainID]
+	if !ok {
+		return xerrors.Errorf("chain %v is not registered", chainID)
+	}
+	ch.inclusionStates.Detach(closure)
+	return nil
+}
+
+// AttachMilestones implements chain.NodeConnection.
+func (nc *l1NodeConn) AttachMilestones(handler chain.NodeConnectionMilestonesHandlerFun) *events.Closure {
+	closure := events.NewClosure(handler)
+	nc.l1.milestones.Attach(closure)
+	return closure
+}
+
+// DetachMilestones implements chain.NodeConnection.
+func (nc *l1NodeConn) DetachMilestones(attachID *events.Closure) {
+	nc.l1.milestones.Detach(attachID)
+}
+
+// SetMetrics implements chain.NodeConnection.
+func (nc *l1NodeConn) SetMetrics(metrics nodeconnmetrics.NodeConnectionMetrics) {
+	nc.metrics = metrics
+}
+
+// GetMetrics implements chain.NodeConnection.
+func (nc *l1NodeConn) GetMetrics() nodeconnmetrics.NodeConnectionMetrics {
+	return nc.metrics
+}
+
+// Close implements chain.NodeConnection.
+func (nc *l1NodeConn) Close() {
+	nc.l1.mutex.Lock()
+	defer nc.l1.mutex.Unlock()
+	for chainID, ch := range nc.chains {
+		ch.eventPipe.Close()
+		delete(nc.chains, chainID)
+	}
+	delete(nc.l1.conns, nc)
+}
+
+// handleOutput is called with the L1 lock held.
+func (nc *l1NodeConn) handleOutput(outputID iotago.OutputID, output iotago.Output) {
+	// notify chains about state updates
+	if aliasOutput, ok := output.(*iotago.AliasOutput); ok {
+		chainID := isc.ChainIDFromAliasID(util.AliasIDFromAliasOutput(aliasOutput, outputID))
+		if ch, ok := nc.chains[chainID]; ok {
+			ch.enqueue(func() { ch.stateOutputHandler(outputID, output) })
+		}
+	}
+	// notify chains about new UTXOS owned by them
+	unlockAddr := output.UnlockConditionSet().Address()
+	if unlockAddr == nil || !shouldBeProcessed(output) {
+		return
+	}
+	if unlockAliasAddr, ok := unlockAddr.Address.(*iotago.AliasAddress); ok {
+		chainID := isc.ChainIDFromAliasID(unlockAliasAddr.AliasID())
+		if ch, ok := nc.chains[chainID]; ok {
+			ch.enqueue(func() { ch.outputHandler(outputID, output) })
+		}
+	}
+}
+
+// handleInclusionState is called with the L1 lock held.
+func (nc *l1NodeConn) handleInclusionState(txID iotago.TransactionID, state string) {
+	for _, ch := range nc.chains {
+		ch := ch
+		ch.enqueue(func() { ch.inclusionStates.Trigger(txID, state) })
+	}
+}
+
+// only outputs without SDRC should be processed.
+func shouldBeProcessed(out iotago.Output) bool {
+	return !out.UnlockConditionSet().HasStorageDepositReturnCondition()
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/chainimpl"
+	"github.com/iotaledger/wasp/packages/chain/chainutil"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
+	"github.com/iotaledger/wasp/packages/wal"
+	"golang.org/x/xerrors"
+)
+
+const awaitPollPeriod = 100 * time.Millisecond
+
+// Node is a single Wasp node of the cluster, running the chain in-process.
+// The registry and the chain state survive the restarts of the node.
+type Node struct {
+	Index       int
+	Registry    *registry.Impl
+	identity    *cryptolib.KeyPair
+	registryDB  kvstore.KVStore
+	netProvider peering.NetworkProvider
+	l1          *L1
+	chainDB     kvstore.KVStore
+	procConfig  *processors.Config
+	chainID     *isc.ChainID
+	theChain    chain.Chain
+	mutex       sync.RWMutex
+	log         *logger.Logger
+}
+
+func newNode(index int, procConfig *processors.Config, l1 *L1, log *logger.Logger) *Node {
+	registryDB := mapdb.NewMapDB()
+	identity := cryptolib.NewKeyPair()
+	// The fake peering network needs the key pairs, thus the signer is created with a known identity.
+	nodeSigner, err := signer.NewLocalWithIdentity(keystore.NewDBKeyStore(registryDB), identity, log)
+	if err != nil {
+		panic(xerrors.Errorf("cannot create the signer of node %v: %w", index, err))
+	}
+	return &Node{
+		Index:      index,
+		Registry:   registry.NewRegistry(log, registryDB, nodeSigner),
+		identity:   identity,
+		registryDB: registryDB,
+		l1:         l1,
+		chainDB:    mapdb.NewMapDB(),
+		procConfig: procConfig,
+		log:        log,
+	}
+}
+
+// Identity returns the key pair, identifying the node in the peering network.
+func (n *Node) Identity() *cryptolib.KeyPair {
+	return n.identity
+}
+
+// RegistryDB returns the store of the node registry, as it is found in the database of a node.
+func (n *Node) RegistryDB() kvstore.KVStore {
+	return n.registryDB
+}
+
+// ChainDB returns the store of the chain state and blocks.
+func (n *Node) ChainDB() kvstore.KVStore {
+	return n.chainDB
+}
+
+// Chain returns the chain, run by the node, or nil, if the node is not running.
+func (n *Node) Chain() chain.Chain {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.theChain
+}
+
+// IsRunning returns true, if the node is not killed.
+func (n *Node) IsRunning() bool {
+	return n.Chain() != nil
+}
+
+func (n *Node) start() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.theChain != nil {
+		return xerrors.Errorf("node %v is already running", n.Index)
+	}
+	theChain := chainimpl.NewChain(
+		n.chainID,
+		n.log,
+		n.l1.NewNodeConnection(),
+		n.chainDB,
+		n.netProvider,
+		n.Registry,
+		n.Registry,
+		n.procConfig,
+		2,
+		1*time.Second,
+		true,
+		metrics.DefaultChainMetrics(),
+		n.Registry,
+		wal.NewDefault(),
+		false,
+		runvm.NewVMRunner(),
+	)
+	if theChain == nil {
+		return xerrors.Errorf("failed to start the chain on node %v", n.Index)
+	}
+	n.theChain = theChain
+	return nil
+}
+
+func (n *Node) stop() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.theChain == nil {
+		return
+	}
+	n.theChain.Dismiss("node stopped")
+	n.theChain = nil
+}
+
+// PostOffLedgerRequest sends the signed off-ledger request to this node, as the web API does.
+func (n *Node) PostOffLedgerRequest(req isc.OffLedgerRequest) error {
+	theChain := n.Chain()
+	if theChain == nil {
+		return xerrors.Errorf("node %v is not running", n.Index)
+	}
+	theChain.EnqueueOffLedgerRequestMsg(&messages.OffLedgerRequestMsgIn{
+		OffLedgerRequestMsg: messages.OffLedgerRequestMsg{
+			ChainID: theChain.ID(),
+			Req:     req,
+		},
+		SenderPubKey: n.Identity().GetPublicKey(),
+	})
+	return nil
+}
+
+// CallView calls the view entry point on the state, known to this node.
+func (n *Node) CallView(contract, entryPoint isc.Hname, params dict.Dict) (dict.Dict, error) {
+	theChain := n.Chain()
+	if theChain == nil {
+		return nil, xerrors.Errorf("node %v is not running", n.Index)
+	}
+	return chainutil.CallView(theChain, contract, entryPoint, params)
+}
+
+// AwaitRequestProcessed waits until the request is processed in the state, known to this node.
+func (n *Node) AwaitRequestProcessed(reqID isc.RequestID, timeout time.Duration) (*blocklog.RequestReceipt, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var err error
+		if theChain := n.Chain(); theChain != nil {
+			var receipt *blocklog.RequestReceipt
+			receipt, err = theChain.GetRequestReceipt(reqID)
+			if err == nil && receipt != nil {
+				return receipt, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			if err != nil { // The state can be invalidated while reading, thus errors are retried until the timeout.
+				return nil, xerrors.Errorf("cannot get receipt of request %v on node %v: %w", reqID, n.Index, err)
+			}
+			return nil, xerrors.Errorf("timeout while waiting for request %v to be processed on node %v", reqID, n.Index)
+		}
+		time.Sleep(awaitPollPeriod)
+	}
+}
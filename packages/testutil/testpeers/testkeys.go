@@ -9,12 +9,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/dkg"
+	"github.com/iotaledger/wasp/packages/keystore"
 	"github.com/iotaledger/wasp/packages/peering"
-	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
@@ -46,17 +48,17 @@ func SetupDkg(
 	peerIdentities []*cryptolib.KeyPair,
 	suite tcrypto.Suite,
 	log *logger.Logger,
-) (iotago.Address, []registry.DKShareRegistryProvider) {
+) (iotago.Address, []signer.Signer) {
 	timeout := 300 * time.Second
 	networkProviders, networkCloser := SetupNet(peerNetIDs, peerIdentities, testutil.NewPeeringNetReliable(log), log)
 	//
 	// Initialize the DKG subsystem in each node.
 	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
-	registries := make([]registry.DKShareRegistryProvider, len(peerNetIDs))
+	signers := make([]signer.Signer, len(peerNetIDs))
 	for i := range peerNetIDs {
-		registries[i] = testutil.NewDkgRegistryProvider(peerIdentities[i].GetPrivateKey())
+		signers[i] = NewSigner(t, peerIdentities[i])
 		dkgNode, err := dkg.NewNode(
-			peerIdentities[i], networkProviders[i], registries[i],
+			signers[i], networkProviders[i],
 			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelError, false),
 		)
 		require.NoError(t, err)
@@ -75,14 +77,14 @@ func SetupDkg(
 	require.NotNil(t, dkShare.GetAddress())
 	require.NotNil(t, dkShare.GetSharedPublic())
 	require.NoError(t, networkCloser.Close())
-	return dkShare.GetAddress(), registries
+	return dkShare.GetAddress(), signers
 }
 
 func SetupDkgPregenerated(
 	t *testing.T,
 	threshold uint16,
 	identities []*cryptolib.KeyPair,
-) (iotago.Address, []registry.DKShareRegistryProvider) {
+) (iotago.Address, []signer.Signer) {
 	var err error
 	serializedDks := pregeneratedDksRead(uint16(len(identities)), threshold)
 	nodePubKeys := make([]*cryptolib.PublicKey, len(identities))
@@ -90,7 +92,7 @@ func SetupDkgPregenerated(
 		nodePubKeys[i] = identities[i].GetPublicKey()
 	}
 	dks := make([]tcrypto.DKShare, len(serializedDks))
-	registries := make([]registry.DKShareRegistryProvider, len(identities))
+	signers := make([]signer.Signer, len(identities))
 	for i := range dks {
 		dks[i], err = tcrypto.DKShareFromBytes(serializedDks[i], tcrypto.DefaultEd25519Suite(), tcrypto.DefaultBLSSuite(), identities[i].GetPrivateKey())
 		require.Nil(t, err)
@@ -98,12 +100,25 @@ func SetupDkgPregenerated(
 			dks[i].AssignCommonData(dks[0])
 		}
 		dks[i].AssignNodePubKeys(nodePubKeys)
-		registries[i] = testutil.NewDkgRegistryProvider(identities[i].GetPrivateKey())
-		require.Nil(t, registries[i].SaveDKShare(dks[i]))
+		keyStore := keystore.NewDBKeyStore(mapdb.NewMapDB())
+		require.Nil(t, signer.StoreDKShare(keyStore, dks[i]))
+		signers[i] = NewSignerWithKeyStore(t, keyStore, identities[i])
 	}
 	require.Equal(t, dks[0].GetN(), uint16(len(identities)), "dks was pregenerated for different node count (N=%v)", dks[0].GetN())
 	require.Equal(t, dks[0].GetT(), threshold, "dks was pregenerated for different threshold (T=%v)", dks[0].GetT())
-	return dks[0].GetAddress(), registries
+	return dks[0].GetAddress(), signers
+}
+
+// NewSigner creates a local signer for the node identity, keeping the secrets in memory.
+func NewSigner(t *testing.T, identity *cryptolib.KeyPair) signer.Signer {
+	return NewSignerWithKeyStore(t, keystore.NewDBKeyStore(mapdb.NewMapDB()), identity)
+}
+
+// NewSignerWithKeyStore creates a local signer for the node identity using the specified key store.
+func NewSignerWithKeyStore(t *testing.T, keyStore keystore.KeyStore, identity *cryptolib.KeyPair) signer.Signer {
+	s, err := signer.NewLocalWithIdentity(keyStore, identity, testlogger.NewSilentLogger(t.Name(), true))
+	require.NoError(t, err)
+	return s
 }
 
 func SetupNet(
@@ -34,6 +34,16 @@ type NewTransferTransactionParams struct {
 
 // NewTransferTransaction creates a basic output transaction that sends L1 Token to another L1 address
 func NewTransferTransaction(params NewTransferTransactionParams) (*iotago.Transaction, error) {
+	essence, err := NewTransferTransactionEssence(params)
+	if err != nil {
+		return nil, err
+	}
+	return SignTxEssence(essence, params.SenderKeyPair)
+}
+
+// NewTransferTransactionEssence creates the unsigned essence of the transfer transaction.
+// The SenderKeyPair of the params is not used, so the essence can be signed elsewhere
+func NewTransferTransactionEssence(params NewTransferTransactionParams) (*iotago.TransactionEssence, error) {
 	output := MakeBasicOutput(
 		params.TargetAddress,
 		params.SenderAddress,
@@ -77,7 +87,7 @@ func NewTransferTransaction(params NewTransferTransactionParams) (*iotago.Transa
 
 	inputsCommitment := inputIDs.OrderedSet(params.UnspentOutputs).MustCommitment()
 
-	return CreateAndSignTx(inputIDs, inputsCommitment, outputs, params.SenderKeyPair, parameters.L1().Protocol.NetworkID())
+	return MakeTxEssence(inputIDs, inputsCommitment, outputs, parameters.L1().Protocol.NetworkID()), nil
 }
 
 // NewRequestTransaction creates a transaction including one or more requests to a chain.
@@ -264,14 +264,27 @@ func MakeAnchorTransaction(essence *iotago.TransactionEssence, sig iotago.Signat
 }
 
 func CreateAndSignTx(inputs iotago.OutputIDs, inputsCommitment []byte, outputs iotago.Outputs, wallet *cryptolib.KeyPair, networkID uint64) (*iotago.Transaction, error) {
+	if len(inputsCommitment) != iotago.InputsCommitmentLength {
+		return nil, iotago.ErrInvalidInputsCommitment
+	}
+	return SignTxEssence(MakeTxEssence(inputs, inputsCommitment, outputs, networkID), wallet)
+}
+
+// MakeTxEssence creates the essence of a transaction, to be signed with SignTxEssence
+func MakeTxEssence(inputs iotago.OutputIDs, inputsCommitment []byte, outputs iotago.Outputs, networkID uint64) *iotago.TransactionEssence {
 	essence := &iotago.TransactionEssence{
 		NetworkID: networkID,
 		Inputs:    inputs.UTXOInputs(),
 		Outputs:   outputs,
 	}
+	copy(essence.InputsCommitment[:], inputsCommitment)
+	return essence
+}
 
+// SignTxEssence signs the essence, all the inputs of which must be unlocked by the wallet
+func SignTxEssence(essence *iotago.TransactionEssence, wallet *cryptolib.KeyPair) (*iotago.Transaction, error) {
 	sigs, err := essence.Sign(
-		inputsCommitment,
+		essence.InputsCommitment[:],
 		wallet.GetPrivateKey().AddressKeysForEd25519Address(wallet.Address()),
 	)
 	if err != nil {
@@ -280,7 +293,7 @@ func CreateAndSignTx(inputs iotago.OutputIDs, inputsCommitment []byte, outputs i
 
 	return &iotago.Transaction{
 		Essence: essence,
-		Unlocks: MakeSignatureAndReferenceUnlocks(len(inputs), sigs[0]),
+		Unlocks: MakeSignatureAndReferenceUnlocks(len(essence.Inputs), sigs[0]),
 	}, nil
 }
 
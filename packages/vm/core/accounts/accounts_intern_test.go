@@ -48,7 +48,7 @@ func TestCreditDebit1(t *testing.T) {
 
 	agentID1 := knownAgentID(1, 2)
 	transfer := isc.NewFungibleTokens(42, nil).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp1")
 
 	require.NotNil(t, total)
@@ -56,7 +56,7 @@ func TestCreditDebit1(t *testing.T) {
 	require.True(t, total.Equals(transfer))
 
 	transfer.BaseTokens = 1
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp2")
 
 	expected := isc.NewFungibleTokens(43, nil).AddNativeTokens(dummyAssetID, big.NewInt(4))
@@ -67,7 +67,7 @@ func TestCreditDebit1(t *testing.T) {
 	require.Zero(t, userAssets.Tokens.MustSet()[dummyAssetID].Amount.Cmp(big.NewInt(4)))
 	checkLedgerT(t, state, "cp2")
 
-	DebitFromAccount(state, agentID1, expected)
+	DebitFromAccount(state, agentID1, expected, HistoryReasonWithdrawal)
 	total = checkLedgerT(t, state, "cp3")
 	expected = isc.NewEmptyAssets()
 	require.True(t, expected.Equals(total))
@@ -80,7 +80,7 @@ func TestCreditDebit2(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewFungibleTokens(42, nil).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp1")
 
 	expected := transfer
@@ -88,7 +88,7 @@ func TestCreditDebit2(t *testing.T) {
 	require.True(t, expected.Equals(total))
 
 	transfer = isc.NewEmptyAssets().AddNativeTokens(dummyAssetID, big.NewInt(2))
-	DebitFromAccount(state, agentID1, transfer)
+	DebitFromAccount(state, agentID1, transfer, HistoryReasonWithdrawal)
 	total = checkLedgerT(t, state, "cp2")
 	require.EqualValues(t, 0, len(total.Tokens))
 	expected = isc.NewFungibleTokens(42, nil)
@@ -107,7 +107,7 @@ func TestCreditDebit3(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewFungibleTokens(42, nil).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp1")
 
 	expected := transfer
@@ -117,7 +117,7 @@ func TestCreditDebit3(t *testing.T) {
 	transfer = isc.NewEmptyAssets().AddNativeTokens(dummyAssetID, big.NewInt(100))
 	require.Panics(t,
 		func() {
-			DebitFromAccount(state, agentID1, transfer)
+			DebitFromAccount(state, agentID1, transfer, HistoryReasonWithdrawal)
 		},
 	)
 	total = checkLedgerT(t, state, "cp2")
@@ -134,7 +134,7 @@ func TestCreditDebit4(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewFungibleBaseTokens(42).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp1")
 
 	expected := transfer
@@ -176,7 +176,7 @@ func TestCreditDebit5(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewFungibleBaseTokens(42).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	total = checkLedgerT(t, state, "cp1")
 
 	expected := transfer
@@ -215,7 +215,7 @@ func TestCreditDebit6(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewFungibleBaseTokens(42).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	checkLedgerT(t, state, "cp1")
 
 	agentID2 := isc.NewRandomAgentID()
@@ -243,13 +243,13 @@ func TestCreditDebit7(t *testing.T) {
 
 	agentID1 := isc.NewRandomAgentID()
 	transfer := isc.NewEmptyAssets().AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	checkLedgerT(t, state, "cp1")
 
 	debitTransfer := isc.NewFungibleTokens(1, nil)
 	// debit must fail
 	require.Panics(t, func() {
-		DebitFromAccount(state, agentID1, debitTransfer)
+		DebitFromAccount(state, agentID1, debitTransfer, HistoryReasonWithdrawal)
 	})
 
 	total = checkLedgerT(t, state, "cp1")
@@ -262,7 +262,7 @@ func TestMoveAll(t *testing.T) {
 	agentID2 := isc.NewRandomAgentID()
 
 	transfer := isc.NewFungibleBaseTokens(42).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	require.EqualValues(t, 1, getAccountsMapR(state).MustLen())
 	accs := getAccountsIntern(state)
 	require.EqualValues(t, 1, len(accs))
@@ -282,14 +282,14 @@ func TestDebitAll(t *testing.T) {
 	agentID1 := isc.NewRandomAgentID()
 
 	transfer := isc.NewFungibleTokens(42, nil).AddNativeTokens(dummyAssetID, big.NewInt(2))
-	CreditToAccount(state, agentID1, transfer)
+	CreditToAccount(state, agentID1, transfer, HistoryReasonDeposit)
 	require.EqualValues(t, 1, getAccountsMapR(state).MustLen())
 	accs := getAccountsIntern(state)
 	require.EqualValues(t, 1, len(accs))
 	_, ok := accs[kv.Key(agentID1.Bytes())]
 	require.True(t, ok)
 
-	DebitFromAccount(state, agentID1, transfer)
+	DebitFromAccount(state, agentID1, transfer, HistoryReasonWithdrawal)
 	require.EqualValues(t, 0, getAccountsMapR(state).MustLen())
 	accs = getAccountsIntern(state)
 	require.EqualValues(t, 0, len(accs))
@@ -314,7 +314,7 @@ func TestTransferNFTs(t *testing.T) {
 		Issuer:   tpkg.RandEd25519Address(),
 		Metadata: []byte("foobar"),
 	}
-	CreditNFTToAccount(state, agentID1, NFT1)
+	CreditNFTToAccount(state, agentID1, NFT1, HistoryReasonDeposit)
 	// nft is credited
 	user1NFTs := getAccountNFTs(getAccountR(state, agentID1))
 	require.Len(t, user1NFTs, 1)
@@ -343,7 +343,7 @@ func TestTransferNFTs(t *testing.T) {
 	require.Equal(t, user2NFTs[0], NFT1.ID)
 
 	// remove the NFT from the chain
-	DebitNFTFromAccount(state, agentID2, NFT1.ID)
+	DebitNFTFromAccount(state, agentID2, NFT1.ID, HistoryReasonWithdrawal)
 	require.Panics(t, func() {
 		GetNFTData(state, NFT1.ID)
 	})
@@ -383,14 +383,51 @@ func TestCreditDebitNFT1(t *testing.T) {
 		Issuer:   tpkg.RandEd25519Address(),
 		Metadata: []byte("foobar"),
 	}
-	CreditNFTToAccount(state, agentID1, &nft)
+	CreditNFTToAccount(state, agentID1, &nft, HistoryReasonDeposit)
 
 	accNFTs := GetAccountNFTs(state, agentID1)
 	require.Len(t, accNFTs, 1)
 	require.Equal(t, accNFTs[0], nft.ID)
 
-	DebitNFTFromAccount(state, agentID1, nft.ID)
+	DebitNFTFromAccount(state, agentID1, nft.ID, HistoryReasonWithdrawal)
 
 	accNFTs = GetAccountNFTs(state, agentID1)
 	require.Len(t, accNFTs, 0)
 }
+
+func TestAccountHistoryRetention(t *testing.T) {
+	state := dict.New()
+	setAccountHistoryEnabled(state, true)
+	setAccountHistoryRetention(state, 3)
+
+	agentID1 := knownAgentID(1, 2)
+	for i := 0; i < 5; i++ {
+		CreditToAccount(state, agentID1, isc.NewFungibleBaseTokens(uint64(i+1)), HistoryReasonMint)
+		SaveAccountHistory(state, 1, uint16(i), isc.RequestID{})
+	}
+	entries, first, total := GetAccountHistory(state, agentID1, 0, MaxAccountHistoryPageSize)
+	require.EqualValues(t, 2, first)
+	require.EqualValues(t, 5, total)
+	require.Len(t, entries, 3)
+	for i, e := range entries {
+		require.EqualValues(t, i+2, e.RequestIndex)
+		require.EqualValues(t, i+3, e.Delta.Int64())
+		require.EqualValues(t, HistoryReasonMint, e.Reason)
+	}
+	entries, _, _ = GetAccountHistory(state, agentID1, 4, MaxAccountHistoryPageSize)
+	require.Len(t, entries, 1)
+	require.EqualValues(t, 4, entries[0].RequestIndex)
+
+	// the entries recorded outside of the requests are not attributed to the next request
+	DebitFromAccount(state, agentID1, isc.NewFungibleBaseTokens(1), HistoryReasonBurn)
+	SaveBlockAccountHistory(state, 2)
+	require.Zero(t, getPendingHistory(state).MustLen())
+	entries, first, total = GetAccountHistory(state, agentID1, 5, MaxAccountHistoryPageSize)
+	require.EqualValues(t, 3, first)
+	require.EqualValues(t, 6, total)
+	require.Len(t, entries, 1)
+	require.EqualValues(t, 2, entries[0].BlockIndex)
+	require.EqualValues(t, HistoryRequestIndexBlock, entries[0].RequestIndex)
+	require.EqualValues(t, HistoryReasonBurn, entries[0].Reason)
+	require.EqualValues(t, -1, entries[0].Delta.Int64())
+}
@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"fmt"
 	"math"
 	"math/big"
 
@@ -15,22 +16,35 @@ import (
 
 var Processor = Contract.Processor(initialize,
 	// funcs
+	FuncClaimFoundry.WithHandler(claimFoundry),
+	FuncDelegateFoundry.WithHandler(delegateFoundry),
 	FuncDeposit.WithHandler(deposit),
 	FuncFoundryCreateNew.WithHandler(foundryCreateNew),
 	FuncFoundryDestroy.WithHandler(foundryDestroy),
 	FuncFoundryModifySupply.WithHandler(foundryModifySupply),
+	FuncGrantFoundry.WithHandler(grantFoundry),
 	FuncHarvest.WithHandler(harvest),
+	FuncMintNFT.WithHandler(mintNFT),
+	FuncRegisterMultisigAccount.WithHandler(registerMultisigAccount),
+	FuncRevokeFoundryDelegation.WithHandler(revokeFoundryDelegation),
+	FuncRotateMultisigPolicy.WithHandler(rotateMultisigPolicy),
+	FuncSetAccountHistoryEnabled.WithHandler(setAccountHistoryEnabledFunc),
+	FuncSetAccountHistoryRetention.WithHandler(setAccountHistoryRetentionFunc),
 	FuncTransferAllowanceTo.WithHandler(transferAllowanceTo),
 	FuncWithdraw.WithHandler(withdraw),
 
 	// views
+	ViewAccountFoundries.WithHandler(viewAccountFoundries),
+	ViewAccountHistory.WithHandler(viewAccountHistory),
 	ViewAccountNFTs.WithHandler(viewAccountNFTs),
 	ViewAccounts.WithHandler(viewAccounts),
 	ViewBalance.WithHandler(viewBalance),
 	ViewBalanceBaseToken.WithHandler(viewBalanceBaseToken),
 	ViewBalanceNativeToken.WithHandler(viewBalanceNativeToken),
+	ViewFoundryDelegations.WithHandler(viewFoundryDelegations),
 	ViewFoundryOutput.WithHandler(viewFoundryOutput),
 	ViewGetAccountNonce.WithHandler(viewGetAccountNonce),
+	ViewGetMultisigPolicy.WithHandler(viewGetMultisigPolicy),
 	ViewGetNativeTokenIDRegistry.WithHandler(viewGetNativeTokenIDRegistry),
 	ViewNFTData.WithHandler(viewNFTData),
 	ViewTotalAssets.WithHandler(viewTotalAssets),
@@ -51,7 +65,7 @@ func initialize(ctx isc.Sandbox) dict.Dict {
 
 	// initial load with base tokens from origin anchor output exceeding minimum storage deposit assumption
 	initialLoadBaseTokens := isc.NewFungibleTokens(baseTokensOnAnchor-storageDepositAssumptions.AnchorOutput, nil)
-	CreditToAccount(ctx.State(), ctx.ChainID().CommonAccount(), initialLoadBaseTokens)
+	CreditToAccount(ctx.State(), ctx.ChainID().CommonAccount(), initialLoadBaseTokens, HistoryReasonDeposit)
 	return nil
 }
 
@@ -97,6 +111,11 @@ func withdraw(ctx isc.Sandbox) dict.Dict {
 	ctx.Requiref(!ctx.AllowanceAvailable().IsEmpty(), "Allowance can't be empty in 'accounts.withdraw'")
 
 	callerAddress, ok := isc.AddressFromAgentID(ctx.Caller())
+	if _, isMultisig := ctx.Caller().(*isc.MultisigAgentID); isMultisig {
+		// multisig accounts have no L1 address, the funds are sent to the given one
+		callerAddress = ctx.Params().MustGetAddress(ParamWithdrawalTarget)
+		ok = true
+	}
 	ctx.Requiref(ok, "caller must have L1 address")
 
 	callerContract, _ := ctx.Caller().(*isc.ContractAgentID)
@@ -160,6 +179,165 @@ func withdraw(ctx isc.Sandbox) dict.Dict {
 	return nil
 }
 
+// registerMultisigAccount registers the multisig account with its initial policy. The AgentID of the account
+// is the hash of the initial policy, it doesn't change when the policy is rotated
+// Params:
+// - ParamMultisigPolicy: isc.MultisigPolicy
+// Returns:
+// - ParamAgentID: the AgentID of the account
+func registerMultisigAccount(ctx isc.Sandbox) dict.Dict {
+	policy, err := isc.MultisigPolicyFromBytes(ctx.Params().MustGetBytes(ParamMultisigPolicy))
+	ctx.RequireNoError(err)
+	agentID := isc.NewMultisigAgentID(policy)
+	ctx.Requiref(GetMultisigPolicy(ctx.State(), agentID) == nil, "multisig account %s is already registered", agentID)
+	setMultisigPolicy(ctx.State(), agentID, policy)
+	ctx.Event(fmt.Sprintf("[multisig] registered %s: %s", agentID, policy))
+	return dict.Dict{ParamAgentID: codec.EncodeAgentID(agentID)}
+}
+
+// rotateMultisigPolicy replaces the signer set and the threshold of the multisig account.
+// Must be sent by the multisig account, i.e. signed by the threshold of its current signers
+// Params:
+// - ParamMultisigPolicy: isc.MultisigPolicy
+func rotateMultisigPolicy(ctx isc.Sandbox) dict.Dict {
+	agentID, ok := ctx.Caller().(*isc.MultisigAgentID)
+	ctx.Requiref(ok, "caller must be a multisig account")
+	ctx.Requiref(GetMultisigPolicy(ctx.State(), agentID) != nil, "multisig account %s is not registered", agentID)
+	policy, err := isc.MultisigPolicyFromBytes(ctx.Params().MustGetBytes(ParamMultisigPolicy))
+	ctx.RequireNoError(err)
+	setMultisigPolicy(ctx.State(), agentID, policy)
+	ctx.Event(fmt.Sprintf("[multisig] rotated %s: %s", agentID, policy))
+	return nil
+}
+
+// mintNFT mints an NFT on L2. The issuer of the NFT is the collection NFT, owned by the caller. Without a collection,
+// the issuer is the chain, and only the chain owner can mint the NFT.
+// The NFT is minted on L1 only when it leaves the chain, so no storage deposit is needed until then
+// Params:
+// - ParamNFTImmutableData: []byte, the immutable metadata of the NFT
+// - ParamAgentID: the owner of the new NFT. Optional, default: the caller
+// - ParamNFTCollectionID: NFTID of the collection NFT, owned by the caller. Optional for the chain owner
+// Returns:
+// - ParamNFTID: NFTID of the new NFT
+func mintNFT(ctx isc.Sandbox) dict.Dict {
+	metadata := ctx.Params().MustGetBytes(ParamNFTImmutableData)
+	ctx.Requiref(len(metadata) > 0 && len(metadata) <= iotago.MaxMetadataLength,
+		"immutable metadata must be between 1 and %d bytes", iotago.MaxMetadataLength)
+	target := ctx.Params().MustGetAgentID(ParamAgentID, ctx.Caller())
+
+	issuer := ctx.ChainID().AsAddress()
+	if !ctx.Params().MustHas(ParamNFTCollectionID) {
+		ctx.Requiref(ctx.ChainOwnerID().Equals(ctx.Caller()), "only the chain owner can mint an NFT without a collection")
+	} else {
+		collectionIDBytes := ctx.Params().MustGetBytes(ParamNFTCollectionID)
+		if len(collectionIDBytes) != iotago.NFTIDLength {
+			panic(ErrInvalidNFTID)
+		}
+		collectionID := iotago.NFTID{}
+		copy(collectionID[:], collectionIDBytes)
+		ctx.Requiref(hasNFT(ctx.State(), ctx.Caller(), collectionID), "collection NFT %s is not owned by the caller", collectionID)
+		// the issuer must be unlocked when the NFT is minted on L1
+		ctx.Requiref(!IsL2NativeNFT(ctx.State(), collectionID), "collection NFT %s must exist on L1", collectionID)
+		issuer = collectionID.ToAddress()
+	}
+
+	nftID := mintL2NativeNFT(ctx.State(), ctx.ChainID(), target, issuer, metadata)
+	return dict.Dict{ParamNFTID: nftID[:]}
+}
+
+// grantFoundry grants the ownership of the foundry, controlled by the caller, to another agent.
+// The ownership moves when the grantee claims it. Granting the foundry to the caller cancels the pending grant
+// Params:
+// - ParamFoundrySN: serial number of the foundry
+// - ParamAgentID: the grantee
+func grantFoundry(ctx isc.Sandbox) dict.Dict {
+	sn := ctx.Params().MustGetUint32(ParamFoundrySN)
+	grantee := ctx.Params().MustGetAgentID(ParamAgentID)
+	ctx.Requiref(HasFoundry(ctx.State(), ctx.Caller(), sn), "foundry #%d is not controlled by the caller", sn)
+	if grantee.Equals(ctx.Caller()) {
+		setFoundryGrant(ctx.State(), sn, nil, nil)
+		ctx.Event(fmt.Sprintf("[foundry] grant of #%d cancelled", sn))
+		return nil
+	}
+	setFoundryGrant(ctx.State(), sn, ctx.Caller(), grantee)
+	ctx.Event(fmt.Sprintf("[foundry] #%d granted to %s", sn, grantee))
+	return nil
+}
+
+// claimFoundry moves the ownership of the foundry, granted to the caller, to the caller.
+// The delegations of the foundry are revoked
+// Params:
+// - ParamFoundrySN: serial number of the foundry
+func claimFoundry(ctx isc.Sandbox) dict.Dict {
+	sn := ctx.Params().MustGetUint32(ParamFoundrySN)
+	owner, grantee := GetFoundryGrant(ctx.State(), sn)
+	ctx.Requiref(grantee != nil && grantee.Equals(ctx.Caller()), "foundry #%d is not granted to the caller", sn)
+
+	MoveFoundryBetweenAccounts(ctx.State(), owner, ctx.Caller(), sn)
+	clearFoundryControl(ctx.State(), sn)
+	ctx.Event(fmt.Sprintf("[foundry] #%d claimed by %s from %s", sn, ctx.Caller(), owner))
+	return nil
+}
+
+// delegateFoundry gives another agent the right to mint and burn the tokens of the foundry, controlled by the caller.
+// It replaces the previous delegation to the same agent, if any
+// Params:
+// - ParamFoundrySN: serial number of the foundry
+// - ParamAgentID: the delegate
+// - ParamFoundryMintCap: big.Int, the maximum total amount the delegate can mint. Optional, default: unlimited
+// - ParamFoundryBurnCap: big.Int, the maximum total amount the delegate can burn. Optional, default: unlimited
+func delegateFoundry(ctx isc.Sandbox) dict.Dict {
+	sn := ctx.Params().MustGetUint32(ParamFoundrySN)
+	delegate := ctx.Params().MustGetAgentID(ParamAgentID)
+	mintCap := ctx.Params().MustGetBigInt(ParamFoundryMintCap, nil)
+	burnCap := ctx.Params().MustGetBigInt(ParamFoundryBurnCap, nil)
+	ctx.Requiref(HasFoundry(ctx.State(), ctx.Caller(), sn), "foundry #%d is not controlled by the caller", sn)
+	ctx.Requiref(!delegate.Equals(ctx.Caller()), "can't delegate the foundry to its owner")
+	ctx.Requiref(mintCap == nil || mintCap.Cmp(util.MaxUint256) <= 0, "mint cap exceeds the maximum supply of a native token")
+	ctx.Requiref(burnCap == nil || burnCap.Cmp(util.MaxUint256) <= 0, "burn cap exceeds the maximum supply of a native token")
+
+	delegation := NewFoundryDelegation(mintCap, burnCap)
+	setFoundryDelegation(ctx.State(), sn, delegate, delegation)
+	ctx.Event(fmt.Sprintf("[foundry] #%d delegated to %s: %s", sn, delegate, delegation))
+	return nil
+}
+
+// revokeFoundryDelegation revokes the delegation of the foundry, controlled by the caller, to the agent
+// Params:
+// - ParamFoundrySN: serial number of the foundry
+// - ParamAgentID: the delegate
+func revokeFoundryDelegation(ctx isc.Sandbox) dict.Dict {
+	sn := ctx.Params().MustGetUint32(ParamFoundrySN)
+	delegate := ctx.Params().MustGetAgentID(ParamAgentID)
+	ctx.Requiref(HasFoundry(ctx.State(), ctx.Caller(), sn), "foundry #%d is not controlled by the caller", sn)
+	ctx.Requiref(GetFoundryDelegation(ctx.State(), sn, delegate) != nil, "foundry #%d is not delegated to %s", sn, delegate)
+	setFoundryDelegation(ctx.State(), sn, delegate, nil)
+	ctx.Event(fmt.Sprintf("[foundry] delegation of #%d to %s revoked", sn, delegate))
+	return nil
+}
+
+// setAccountHistoryEnabledFunc turns the recording of the history of the accounts on or off.
+// The entries recorded so far are kept when it is turned off
+// Params:
+// - ParamHistoryEnabled: bool
+func setAccountHistoryEnabledFunc(ctx isc.Sandbox) dict.Dict {
+	ctx.RequireCallerIsChainOwner()
+	setAccountHistoryEnabled(ctx.State(), ctx.Params().MustGetBool(ParamHistoryEnabled))
+	return nil
+}
+
+// setAccountHistoryRetentionFunc sets the number of the latest entries kept in the history of each account.
+// The older entries of an account are pruned when a new entry is recorded for it
+// Params:
+// - ParamHistoryRetention: uint32, more than 0
+func setAccountHistoryRetentionFunc(ctx isc.Sandbox) dict.Dict {
+	ctx.RequireCallerIsChainOwner()
+	retention := ctx.Params().MustGetUint32(ParamHistoryRetention)
+	ctx.Requiref(retention > 0, "retention must be more than 0")
+	setAccountHistoryRetention(ctx.State(), retention)
+	return nil
+}
+
 // harvest moves all the L2 balances of chain commmon account to chain owner's account
 // Params:
 //
@@ -229,14 +407,16 @@ func foundryDestroy(ctx isc.Sandbox) dict.Dict {
 
 	deleteFoundryFromAccount(getAccountFoundries(ctx.State(), ctx.Caller()), sn)
 	DeleteFoundryOutput(ctx.State(), sn)
+	clearFoundryControl(ctx.State(), sn)
 	// the storage deposit goes to the caller's account
 	CreditToAccount(ctx.State(), ctx.Caller(), &isc.FungibleTokens{
 		BaseTokens: storageDepositReleased,
-	})
+	}, HistoryReasonStorageDeposit)
 	return nil
 }
 
 // foundryModifySupply inflates (mints) or shrinks supply of token by the foundry, controlled by the caller
+// or delegated to the caller within the caps of the delegation
 // Params:
 // - ParamFoundrySN serial number of the foundry
 // - ParamSupplyDeltaAbs absolute delta of the supply as big.Int
@@ -249,8 +429,13 @@ func foundryModifySupply(ctx isc.Sandbox) dict.Dict {
 		return nil
 	}
 	destroy := ctx.Params().MustGetBool(ParamDestroyTokens, false)
-	// check if foundry is controlled by the caller
-	ctx.Requiref(HasFoundry(ctx.State(), ctx.Caller(), sn), "foundry #%d is not controlled by the caller", sn)
+	// check if foundry is controlled by the caller or delegated to it
+	if !HasFoundry(ctx.State(), ctx.Caller(), sn) {
+		delegation := GetFoundryDelegation(ctx.State(), sn, ctx.Caller())
+		ctx.Requiref(delegation != nil, "foundry #%d is not controlled by the caller", sn)
+		ctx.RequireNoError(delegation.use(delta, destroy))
+		setFoundryDelegation(ctx.State(), sn, ctx.Caller(), delegation)
+	}
 
 	out, _, _ := GetFoundryOutput(ctx.State(), sn, ctx.ChainID())
 	tokenID, err := out.NativeTokenID()
@@ -269,10 +454,10 @@ func foundryModifySupply(ctx isc.Sandbox) dict.Dict {
 				},
 			}),
 		))
-		DebitFromAccount(ctx.State(), ctx.AccountID(), deltaAssets)
+		DebitFromAccount(ctx.State(), ctx.AccountID(), deltaAssets, HistoryReasonBurn)
 		storageDepositAdjustment = ctx.Privileged().ModifyFoundrySupply(sn, delta.Neg(delta))
 	} else {
-		CreditToAccount(ctx.State(), ctx.Caller(), deltaAssets)
+		CreditToAccount(ctx.State(), ctx.Caller(), deltaAssets, HistoryReasonMint)
 		storageDepositAdjustment = ctx.Privileged().ModifyFoundrySupply(sn, delta)
 	}
 
@@ -283,7 +468,7 @@ func foundryModifySupply(ctx isc.Sandbox) dict.Dict {
 		debitBaseTokensFromAllowance(ctx, uint64(-storageDepositAdjustment))
 	case storageDepositAdjustment > 0:
 		// storage deposit is returned to the caller account
-		CreditToAccount(ctx.State(), ctx.Caller(), isc.NewFungibleBaseTokens(uint64(storageDepositAdjustment)))
+		CreditToAccount(ctx.State(), ctx.Caller(), isc.NewFungibleBaseTokens(uint64(storageDepositAdjustment)), HistoryReasonStorageDeposit)
 	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
@@ -49,9 +50,20 @@ func viewTotalAssets(ctx isc.SandboxView) dict.Dict {
 	return getAccountBalanceDict(getTotalL2AssetsAccountR(ctx.StateR()))
 }
 
-// viewAccounts returns list of all accounts as keys of the ImmutableCodec
+// viewAccounts returns a page of the list of all accounts
+// Params:
+// - coreutil.ParamPageCursor, coreutil.ParamPageLimit
+// Returns:
+// - ParamAllAccounts: map of AgentID => 0xff
 func viewAccounts(ctx isc.SandboxView) dict.Dict {
-	return getAccountsIntern(ctx.StateR())
+	cursor, limit := coreutil.GetPageParams(ctx)
+	ret := dict.New()
+	accs := collections.NewMap(ret, ParamAllAccounts)
+	next := getAccountsMapR(ctx.StateR()).MustIteratePage(cursor, limit, nil, func(agentID []byte, _ []byte) {
+		accs.MustSetAt(agentID, []byte{0xff})
+	})
+	coreutil.SetNextPageCursor(ret, next)
+	return ret
 }
 
 // nonces are only sent with off-ledger requests
@@ -88,17 +100,64 @@ func viewFoundryOutput(ctx isc.SandboxView) dict.Dict {
 	return ret
 }
 
-// viewAccountNFTs returns the NFTIDs of NFTs owned by an account
+// viewAccountNFTs returns a page of the NFTIDs of NFTs owned by an account
+// Params:
+// - ParamAgentID
+// - coreutil.ParamPageCursor, coreutil.ParamPageLimit
 func viewAccountNFTs(ctx isc.SandboxView) dict.Dict {
 	ctx.Log().Debugf("accounts.viewAccountNFTs")
 	aid := ctx.Params().MustGetAgentID(ParamAgentID)
-	nftIDs := getAccountNFTs(getAccountR(ctx.StateR(), aid))
+	cursor, limit := coreutil.GetPageParams(ctx)
 
 	ret := dict.New()
 	arr := collections.NewArray16(ret, ParamNFTIDs)
-	for _, nftID := range nftIDs {
-		arr.MustPush(nftID[:])
+	isNFT := func(idBytes []byte) bool {
+		// native tokens and base tokens are in the same map
+		return len(idBytes) == iotago.NFTIDLength
+	}
+	next := getAccountR(ctx.StateR(), aid).MustIteratePage(cursor, limit, isNFT, func(nftID []byte, _ []byte) {
+		arr.MustPush(nftID)
+	})
+	coreutil.SetNextPageCursor(ret, next)
+	return ret
+}
+
+// viewAccountFoundries returns a page of the serial numbers of the foundries owned by the account
+// Params:
+// - ParamAgentID
+// Returns:
+// - ParamFoundrySNs: map of uint32 serial number => 0xff
+func viewAccountFoundries(ctx isc.SandboxView) dict.Dict {
+	aid := ctx.Params().MustGetAgentID(ParamAgentID)
+	cursor, limit := coreutil.GetPageParams(ctx)
+
+	ret := dict.New()
+	sns := collections.NewMap(ret, ParamFoundrySNs)
+	next := getAccountFoundriesR(ctx.StateR(), aid).MustIteratePage(cursor, limit, nil, func(sn []byte, _ []byte) {
+		sns.MustSetAt(sn, []byte{0xff})
+	})
+	coreutil.SetNextPageCursor(ret, next)
+	return ret
+}
+
+// viewFoundryDelegations returns the control of the foundry other than its owner
+// Params:
+// - ParamFoundrySN
+// Returns:
+// - ParamFoundryGrantee: AgentID the ownership of the foundry is granted to, absent if there is no pending grant
+// - ParamFoundryDelegations: map of AgentID => FoundryDelegation
+func viewFoundryDelegations(ctx isc.SandboxView) dict.Dict {
+	sn := ctx.Params().MustGetUint32(ParamFoundrySN)
+
+	ret := dict.New()
+	if _, grantee := GetFoundryGrant(ctx.StateR(), sn); grantee != nil {
+		ret.Set(ParamFoundryGrantee, grantee.Bytes())
 	}
+	delegations := collections.NewMap(ret, ParamFoundryDelegations)
+	getFoundryDelegationsR(ctx.StateR(), sn).MustIterate(func(agentID []byte, delegation []byte) bool {
+		delegations.MustSetAt(agentID, delegation)
+		return true
+	})
 	return ret
 }
 
@@ -116,3 +175,46 @@ func viewNFTData(ctx isc.SandboxView) dict.Dict {
 		ParamNFTData: data.Bytes(),
 	}
 }
+
+// viewGetMultisigPolicy returns the current policy of the multisig account
+// Params:
+// - ParamAgentID: the AgentID of the multisig account
+// Returns:
+// - ParamMultisigPolicy: isc.MultisigPolicy, absent if the account is not registered
+func viewGetMultisigPolicy(ctx isc.SandboxView) dict.Dict {
+	agentID, ok := ctx.Params().MustGetAgentID(ParamAgentID).(*isc.MultisigAgentID)
+	ctx.Requiref(ok, "not a multisig account")
+	policy := GetMultisigPolicy(ctx.StateR(), agentID)
+	if policy == nil {
+		return nil
+	}
+	return dict.Dict{ParamMultisigPolicy: policy.Bytes()}
+}
+
+// viewAccountHistory returns a page of the history of the account, oldest entries first
+// Params:
+// - ParamAgentID
+// - ParamHistoryOffset: uint32, index of the first entry. Optional, default: 0
+// - ParamHistoryLimit: uint32, at most MaxAccountHistoryPageSize. Optional, default: MaxAccountHistoryPageSize
+// Returns:
+// - ParamHistoryEntries: Array32 of HistoryEntry
+// - ParamHistoryFirst: uint32, index of the oldest entry kept, the older ones were pruned
+// - ParamHistoryTotal: uint32, the number of entries recorded for the account
+// - ParamHistoryRetention: uint32, the number of the latest entries kept for each account
+func viewAccountHistory(ctx isc.SandboxView) dict.Dict {
+	agentID := ctx.Params().MustGetAgentID(ParamAgentID)
+	offset := ctx.Params().MustGetUint32(ParamHistoryOffset, 0)
+	limit := ctx.Params().MustGetUint32(ParamHistoryLimit, MaxAccountHistoryPageSize)
+	ctx.Requiref(limit <= MaxAccountHistoryPageSize, "limit must not exceed %d", MaxAccountHistoryPageSize)
+	entries, first, total := GetAccountHistory(ctx.StateR(), agentID, offset, limit)
+
+	ret := dict.New()
+	arr := collections.NewArray32(ret, ParamHistoryEntries)
+	for _, entry := range entries {
+		arr.MustPush(entry.Bytes())
+	}
+	ret.Set(ParamHistoryFirst, codec.EncodeUint32(first))
+	ret.Set(ParamHistoryTotal, codec.EncodeUint32(total))
+	ret.Set(ParamHistoryRetention, codec.EncodeUint32(GetAccountHistoryRetention(ctx.StateR())))
+	return ret
+}
@@ -19,6 +19,10 @@ var (
 	ViewFoundryOutput            = coreutil.ViewFunc("foundryOutput")
 	ViewAccountNFTs              = coreutil.ViewFunc("accountNFTs")
 	ViewNFTData                  = coreutil.ViewFunc("nftData")
+	ViewGetMultisigPolicy        = coreutil.ViewFunc("getMultisigPolicy")
+	ViewAccountHistory           = coreutil.ViewFunc("accountHistory")
+	ViewAccountFoundries         = coreutil.ViewFunc("accountFoundries")
+	ViewFoundryDelegations       = coreutil.ViewFunc("foundryDelegations")
 
 	// Funcs
 	FuncDeposit             = coreutil.Func("deposit")
@@ -28,8 +32,18 @@ var (
 	FuncFoundryCreateNew    = coreutil.Func("foundryCreateNew")
 	FuncFoundryDestroy      = coreutil.Func("foundryDestroy")
 	FuncFoundryModifySupply = coreutil.Func("foundryModifySupply")
-	// TODO implement grant/claim protocol of moving ownership of the foundry
-	//  Including ownership of the foundry by the common account/chain owner
+	FuncMintNFT             = coreutil.Func("mintNFT")
+	// multisig accounts
+	FuncRegisterMultisigAccount = coreutil.Func("registerMultisigAccount")
+	FuncRotateMultisigPolicy    = coreutil.Func("rotateMultisigPolicy")
+	// history of the accounts
+	FuncSetAccountHistoryEnabled   = coreutil.Func("setAccountHistoryEnabled")
+	FuncSetAccountHistoryRetention = coreutil.Func("setAccountHistoryRetention")
+	// control of the foundries
+	FuncGrantFoundry            = coreutil.Func("grantFoundry")
+	FuncClaimFoundry            = coreutil.Func("claimFoundry")
+	FuncDelegateFoundry         = coreutil.Func("delegateFoundry")
+	FuncRevokeFoundryDelegation = coreutil.Func("revokeFoundryDelegation")
 )
 
 const (
@@ -56,6 +70,26 @@ const (
 	prefixNFTData
 	//
 	stateVarMinimumStorageDepositAssumptionsBin
+	// prefixMultisigPolicies a map of multisig accounts -> current policy
+	prefixMultisigPolicies
+	// stateVarAccountHistoryEnabled true if the movements of the accounts are recorded
+	stateVarAccountHistoryEnabled
+	// prefixAccountHistory prefix for the array of history entries of a particular account
+	prefixAccountHistory
+	// prefixAccountHistoryPending array of the entries recorded by the current request
+	prefixAccountHistoryPending
+	// prefixFoundryGrants a map of foundries -> agent the ownership of the foundry is granted to
+	prefixFoundryGrants
+	// prefixFoundryDelegations prefix for the map of delegates -> delegation of a particular foundry
+	prefixFoundryDelegations
+	// prefixL2NativeNFTs a map of the NFTs minted on L2 which don't exist on L1 yet
+	prefixL2NativeNFTs
+	// stateVarL2NativeNFTCounter the number of NFTs minted on L2 so far
+	stateVarL2NativeNFTCounter
+	// stateVarAccountHistoryRetention the number of the latest history entries kept for each account
+	stateVarAccountHistoryRetention
+	// prefixAccountHistoryFirst prefix for the index of the oldest history entry kept for a particular account
+	prefixAccountHistoryFirst
 
 	ParamAgentID                      = "a"
 	ParamAccountNonce                 = "n"
@@ -72,6 +106,23 @@ const (
 	ParamNFTData                      = "e"
 	ParamBalance                      = "B"
 	ParamNativeTokenID                = "N"
+	ParamMultisigPolicy               = "m"
+	ParamWithdrawalTarget             = "w"
+	ParamHistoryEnabled               = "x"
+	ParamHistoryOffset                = "o"
+	ParamHistoryLimit                 = "l"
+	ParamHistoryEntries               = "h"
+	ParamHistoryTotal                 = "T"
+	ParamHistoryFirst                 = "F"
+	ParamAllAccounts                  = "A"
+	ParamHistoryRetention             = "R"
+	ParamFoundryMintCap               = "M"
+	ParamFoundryBurnCap               = "D"
+	ParamFoundryGrantee               = "g"
+	ParamFoundryDelegations           = "r"
+	ParamFoundrySNs                   = "S"
+	ParamNFTImmutableData             = "I"
+	ParamNFTCollectionID              = "C"
 )
 
 var ErrStorageDepositAssumptionsWrong = xerrors.New("'storage deposit assumptions' parameter not specified or wrong")
@@ -153,8 +153,8 @@ func loadAccountMutations(account *collections.Map, assets *isc.FungibleTokens)
 	return fromBaseTokens, addBaseTokens, tokenMutations
 }
 
-// CreditToAccount brings new funds to the on chain ledger
-func CreditToAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.FungibleTokens) {
+// CreditToAccount brings new funds to the on chain ledger. The reason is recorded in the history of the account
+func CreditToAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.FungibleTokens, reason HistoryReason) {
 	if assets == nil || (assets.BaseTokens == 0 && len(assets.Tokens) == 0) {
 		return
 	}
@@ -166,6 +166,9 @@ func CreditToAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.Fungible
 	creditToAccount(account, assets)
 	creditToAccount(getTotalL2AssetsAccount(state), assets)
 	touchAccount(state, account)
+	if IsAccountHistoryEnabled(state) {
+		recordFungibleTokens(state, agentID, nil, assets, 1, reason)
+	}
 }
 
 // creditToAccount adds assets to the internal account map
@@ -190,8 +193,9 @@ func creditToAccount(account *collections.Map, assets *isc.FungibleTokens) {
 	}
 }
 
-// DebitFromAccount takes out assets balance the on chain ledger. If not enough it panics
-func DebitFromAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.FungibleTokens) {
+// DebitFromAccount takes out assets balance the on chain ledger. If not enough it panics.
+// The reason is recorded in the history of the account
+func DebitFromAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.FungibleTokens, reason HistoryReason) {
 	if assets.IsEmpty() {
 		return
 	}
@@ -207,6 +211,9 @@ func DebitFromAccount(state kv.KVStore, agentID isc.AgentID, assets *isc.Fungibl
 		panic("debitFromAccount: inconsistent ledger state")
 	}
 	touchAccount(state, account)
+	if IsAccountHistoryEnabled(state) {
+		recordFungibleTokens(state, agentID, nil, assets, -1, reason)
+	}
 }
 
 // debitFromAccount debits assets from the internal accounts map
@@ -286,6 +293,10 @@ func hasEnoughForAllowance(account *collections.ImmutableMap, allowance *isc.All
 
 // MoveBetweenAccounts moves assets between on-chain accounts. Returns if it was a success (= enough funds in the source)
 func MoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentID, fungibleTokens *isc.FungibleTokens, nfts []iotago.NFTID) bool {
+	return moveBetweenAccounts(state, fromAgentID, toAgentID, fungibleTokens, nfts, HistoryReasonTransfer)
+}
+
+func moveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentID, fungibleTokens *isc.FungibleTokens, nfts []iotago.NFTID, reason HistoryReason) bool {
 	checkLedger(state, "MoveBetweenAccounts.IN")
 	defer checkLedger(state, "MoveBetweenAccounts.OUT")
 
@@ -313,11 +324,26 @@ func MoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentID, f
 		creditNFTToAccount(state, toAccount, nft, toAgentID)
 	}
 
+	if IsAccountHistoryEnabled(state) {
+		recordFungibleTokens(state, fromAgentID, toAgentID, fungibleTokens, -1, reason)
+		recordNFTs(state, fromAgentID, toAgentID, nfts, -1, reason)
+		recordFungibleTokens(state, toAgentID, fromAgentID, fungibleTokens, 1, reason)
+		recordNFTs(state, toAgentID, fromAgentID, nfts, 1, reason)
+	}
 	return true
 }
 
 func MustMoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentID, fungibleTokens *isc.FungibleTokens, nfts []iotago.NFTID) {
-	if !MoveBetweenAccounts(state, fromAgentID, toAgentID, fungibleTokens, nfts) {
+	mustMoveBetweenAccounts(state, fromAgentID, toAgentID, fungibleTokens, nfts, HistoryReasonTransfer)
+}
+
+// MustMoveGasFee moves the charged gas fee from the account of the payer to the target account
+func MustMoveGasFee(state kv.KVStore, payer, target isc.AgentID, fee *isc.FungibleTokens) {
+	mustMoveBetweenAccounts(state, payer, target, fee, nil, HistoryReasonFee)
+}
+
+func mustMoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentID, fungibleTokens *isc.FungibleTokens, nfts []iotago.NFTID, reason HistoryReason) {
+	if !moveBetweenAccounts(state, fromAgentID, toAgentID, fungibleTokens, nfts, reason) {
 		panic(xerrors.Errorf(" agentID: %s. %v. fungibleTokens: %s, nfts: %s", fromAgentID, ErrNotEnoughFunds, fungibleTokens, nfts))
 	}
 }
@@ -325,9 +351,9 @@ func MustMoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID isc.AgentI
 func AdjustAccountBaseTokens(state kv.KVStore, account isc.AgentID, adjustment int64) {
 	switch {
 	case adjustment > 0:
-		CreditToAccount(state, account, isc.NewFungibleTokens(uint64(adjustment), nil))
+		CreditToAccount(state, account, isc.NewFungibleTokens(uint64(adjustment), nil), HistoryReasonStorageDeposit)
 	case adjustment < 0:
-		DebitFromAccount(state, account, isc.NewFungibleTokens(uint64(-adjustment), nil))
+		DebitFromAccount(state, account, isc.NewFungibleTokens(uint64(-adjustment), nil), HistoryReasonStorageDeposit)
 	}
 }
 
@@ -343,6 +369,16 @@ func getBaseTokensBalance(account *collections.ImmutableMap) uint64 {
 	return 0
 }
 
+// BaseTokensBalanceKey returns the state key of the base tokens balance of the account
+func BaseTokensBalanceKey(agentID isc.AgentID) kv.Key {
+	return kv.Key(collections.MapElemKey(string(kv.Concat(prefixAccount, agentID.Bytes())), nil))
+}
+
+// NativeTokenBalanceKey returns the state key of the native token balance of the account
+func NativeTokenBalanceKey(agentID isc.AgentID, tokenID *iotago.NativeTokenID) kv.Key {
+	return kv.Key(collections.MapElemKey(string(kv.Concat(prefixAccount, agentID.Bytes())), tokenID[:]))
+}
+
 // GetNativeTokenBalance returns balance or nil if it does not exist
 func GetNativeTokenBalance(state kv.KVStoreReader, agentID isc.AgentID, tokenID *iotago.NativeTokenID) *big.Int {
 	return getNativeTokenBalance(getAccountR(state, agentID), tokenID)
@@ -360,6 +396,24 @@ func getNativeTokenBalance(account *collections.ImmutableMap, tokenID *iotago.Na
 	return ret
 }
 
+// DecodeAccounts decodes a page of the result of the accounts view
+func DecodeAccounts(page dict.Dict) ([]isc.AgentID, error) {
+	ret := make([]isc.AgentID, 0)
+	var err error
+	collections.NewMapReadOnly(page, ParamAllAccounts).MustIterateKeysSorted(func(elemKey []byte) bool {
+		var agentID isc.AgentID
+		if agentID, err = codec.DecodeAgentID(elemKey); err != nil {
+			return false
+		}
+		ret = append(ret, agentID)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
 func getAccountsIntern(state kv.KVStoreReader) dict.Dict {
 	ret := dict.New()
 	getAccountsMapR(state).MustIterate(func(agentID []byte, val []byte) bool {
@@ -846,5 +900,5 @@ func debitBaseTokensFromAllowance(ctx isc.Sandbox, amount uint64) {
 	storageDepositAssets := isc.NewFungibleBaseTokens(amount)
 	transfer := isc.NewAllowanceFungibleTokens(storageDepositAssets)
 	ctx.TransferAllowedFunds(commonAccount, transfer)
-	DebitFromAccount(ctx.State(), commonAccount, storageDepositAssets)
+	DebitFromAccount(ctx.State(), commonAccount, storageDepositAssets, HistoryReasonStorageDeposit)
 }
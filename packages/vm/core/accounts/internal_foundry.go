@@ -0,0 +1,191 @@
+package accounts
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// FoundryDelegation is the right of an agent other than the owner of the foundry to mint and burn its tokens
+type FoundryDelegation struct {
+	// MintCap and BurnCap are the maximum total amounts the delegate can mint and burn, nil if unlimited
+	MintCap *big.Int
+	BurnCap *big.Int
+	// Minted and Burned are the total amounts minted and burned by the delegate so far
+	Minted *big.Int
+	Burned *big.Int
+}
+
+func NewFoundryDelegation(mintCap, burnCap *big.Int) *FoundryDelegation {
+	return &FoundryDelegation{
+		MintCap: mintCap,
+		BurnCap: burnCap,
+		Minted:  big.NewInt(0),
+		Burned:  big.NewInt(0),
+	}
+}
+
+func (d *FoundryDelegation) Bytes() []byte {
+	mu := marshalutil.New()
+	writeOptionalBigInt(mu, d.MintCap)
+	writeOptionalBigInt(mu, d.BurnCap)
+	writeOptionalBigInt(mu, d.Minted)
+	writeOptionalBigInt(mu, d.Burned)
+	return mu.Bytes()
+}
+
+func FoundryDelegationFromBytes(data []byte) (*FoundryDelegation, error) {
+	mu := marshalutil.New(data)
+	ret := &FoundryDelegation{}
+	var err error
+	if ret.MintCap, err = readOptionalBigInt(mu); err != nil {
+		return nil, err
+	}
+	if ret.BurnCap, err = readOptionalBigInt(mu); err != nil {
+		return nil, err
+	}
+	if ret.Minted, err = readOptionalBigInt(mu); err != nil {
+		return nil, err
+	}
+	if ret.Burned, err = readOptionalBigInt(mu); err != nil {
+		return nil, err
+	}
+	if ret.Minted == nil || ret.Burned == nil {
+		return nil, xerrors.New("wrong foundry delegation: missing totals")
+	}
+	return ret, nil
+}
+
+// writeOptionalBigInt writes the value with a 1 byte length prefix, the caps and the totals of
+// a delegation never exceed the maximum supply of a native token (32 bytes)
+func writeOptionalBigInt(mu *marshalutil.MarshalUtil, v *big.Int) {
+	mu.WriteBool(v != nil)
+	if v != nil {
+		if v.Cmp(util.MaxUint256) > 0 {
+			panic(fmt.Sprintf("foundry delegation: value %s exceeds the maximum supply of a native token", v))
+		}
+		b := v.Bytes()
+		mu.WriteUint8(uint8(len(b))).WriteBytes(b)
+	}
+}
+
+func readOptionalBigInt(mu *marshalutil.MarshalUtil) (*big.Int, error) {
+	present, err := mu.ReadBool()
+	if err != nil || !present {
+		return nil, err
+	}
+	n, err := mu.ReadUint8()
+	if err != nil {
+		return nil, err
+	}
+	b, err := mu.ReadBytes(int(n))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// use accounts for the delta of the supply modified by the delegate. It fails if the cap would be exceeded
+func (d *FoundryDelegation) use(delta *big.Int, destroy bool) error {
+	total, limit, what := d.Minted, d.MintCap, "mint"
+	if destroy {
+		total, limit, what = d.Burned, d.BurnCap, "burn"
+	}
+	newTotal := new(big.Int).Add(total, delta)
+	if limit != nil && newTotal.Cmp(limit) > 0 {
+		return xerrors.Errorf("%s cap of the delegation exceeded: %s + %s > %s", what, total, delta, limit)
+	}
+	total.Set(newTotal)
+	return nil
+}
+
+func (d *FoundryDelegation) String() string {
+	capStr := func(c *big.Int) string {
+		if c == nil {
+			return "unlimited"
+		}
+		return c.String()
+	}
+	return fmt.Sprintf("minted %s of %s, burned %s of %s", d.Minted, capStr(d.MintCap), d.Burned, capStr(d.BurnCap))
+}
+
+// getFoundryGrants is a map of foundries -> the owner and the grantee of the pending grant of the foundry
+func getFoundryGrants(state kv.KVStore) *collections.Map {
+	return collections.NewMap(state, prefixFoundryGrants)
+}
+
+func getFoundryGrantsR(state kv.KVStoreReader) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, prefixFoundryGrants)
+}
+
+func getFoundryDelegations(state kv.KVStore, sn uint32) *collections.Map {
+	return collections.NewMap(state, string(kv.Concat(prefixFoundryDelegations, util.Uint32To4Bytes(sn))))
+}
+
+func getFoundryDelegationsR(state kv.KVStoreReader, sn uint32) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, string(kv.Concat(prefixFoundryDelegations, util.Uint32To4Bytes(sn))))
+}
+
+// GetFoundryGrant returns the owner of the foundry which granted its ownership and the grantee,
+// both nil if there is no pending grant
+func GetFoundryGrant(state kv.KVStoreReader, sn uint32) (isc.AgentID, isc.AgentID) {
+	data := getFoundryGrantsR(state).MustGetAt(util.Uint32To4Bytes(sn))
+	if data == nil {
+		return nil, nil
+	}
+	mu := marshalutil.New(data)
+	owner, err := isc.AgentIDFromMarshalUtil(mu)
+	if err != nil {
+		panic(fmt.Sprintf("GetFoundryGrant: inconsistency - wrong grant of foundry #%d: %v", sn, err))
+	}
+	grantee, err := isc.AgentIDFromMarshalUtil(mu)
+	if err != nil {
+		panic(fmt.Sprintf("GetFoundryGrant: inconsistency - wrong grant of foundry #%d: %v", sn, err))
+	}
+	return owner, grantee
+}
+
+func setFoundryGrant(state kv.KVStore, sn uint32, owner, grantee isc.AgentID) {
+	if grantee == nil {
+		getFoundryGrants(state).MustDelAt(util.Uint32To4Bytes(sn))
+		return
+	}
+	mu := marshalutil.New().
+		WriteBytes(owner.Bytes()).
+		WriteBytes(grantee.Bytes())
+	getFoundryGrants(state).MustSetAt(util.Uint32To4Bytes(sn), mu.Bytes())
+}
+
+// GetFoundryDelegation returns the delegation of the foundry to the agent, nil if there is none
+func GetFoundryDelegation(state kv.KVStoreReader, sn uint32, agentID isc.AgentID) *FoundryDelegation {
+	data := getFoundryDelegationsR(state, sn).MustGetAt(agentID.Bytes())
+	if data == nil {
+		return nil
+	}
+	ret, err := FoundryDelegationFromBytes(data)
+	if err != nil {
+		panic(fmt.Sprintf("GetFoundryDelegation: inconsistency - wrong delegation of foundry #%d to %s: %v", sn, agentID, err))
+	}
+	return ret
+}
+
+func setFoundryDelegation(state kv.KVStore, sn uint32, agentID isc.AgentID, d *FoundryDelegation) {
+	if d == nil {
+		getFoundryDelegations(state, sn).MustDelAt(agentID.Bytes())
+		return
+	}
+	getFoundryDelegations(state, sn).MustSetAt(agentID.Bytes(), d.Bytes())
+}
+
+// clearFoundryControl removes the pending grant and all the delegations of the foundry.
+// It is called when the foundry changes its owner or is destroyed
+func clearFoundryControl(state kv.KVStore, sn uint32) {
+	setFoundryGrant(state, sn, nil, nil)
+	getFoundryDelegations(state, sn).Erase()
+}
@@ -0,0 +1,390 @@
+package accounts
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MaxAccountHistoryPageSize is the maximum number of history entries returned by one call of the view
+	MaxAccountHistoryPageSize = 100
+	// DefaultAccountHistoryRetention is the number of the latest entries kept for each account, unless set by the chain owner
+	DefaultAccountHistoryRetention = 1000
+	// HistoryRequestIndexBlock is the request index of the entries recorded outside of the requests,
+	// by the contracts subscribed to the block context. Their request ID is empty
+	HistoryRequestIndexBlock = math.MaxUint16
+)
+
+// HistoryReason is the reason of a movement of assets in or out of an L2 account
+type HistoryReason byte
+
+const (
+	// HistoryReasonDeposit assets came to the chain from L1
+	HistoryReasonDeposit = HistoryReason(iota)
+	// HistoryReasonWithdrawal assets left the chain to L1
+	HistoryReasonWithdrawal
+	// HistoryReasonTransfer assets moved between two L2 accounts
+	HistoryReasonTransfer
+	// HistoryReasonFee gas fee moved from the payer to the validator or the chain owner
+	HistoryReasonFee
+	// HistoryReasonMint native tokens minted by the foundry or NFTs minted on L2
+	HistoryReasonMint
+	// HistoryReasonBurn native tokens destroyed by the foundry
+	HistoryReasonBurn
+	// HistoryReasonStorageDeposit base tokens taken for or released from a storage deposit on L1
+	HistoryReasonStorageDeposit
+)
+
+func (r HistoryReason) String() string {
+	switch r {
+	case HistoryReasonDeposit:
+		return "deposit"
+	case HistoryReasonWithdrawal:
+		return "withdrawal"
+	case HistoryReasonTransfer:
+		return "transfer"
+	case HistoryReasonFee:
+		return "fee"
+	case HistoryReasonMint:
+		return "mint"
+	case HistoryReasonBurn:
+		return "burn"
+	case HistoryReasonStorageDeposit:
+		return "storageDeposit"
+	}
+	return fmt.Sprintf("unknown(%d)", byte(r))
+}
+
+// HistoryEntry is one movement of a single asset in or out of an L2 account
+type HistoryEntry struct {
+	BlockIndex   uint32
+	RequestIndex uint16
+	RequestID    isc.RequestID
+	// Counterparty is the other account of a transfer or a fee, nil if the assets came from or went to L1
+	Counterparty isc.AgentID
+	// NativeTokenID and NFTID are both nil if the entry is about base tokens
+	NativeTokenID *iotago.NativeTokenID
+	NFTID         *iotago.NFTID
+	// Delta is positive when the assets came in. It is 1 or -1 for NFTs
+	Delta  *big.Int
+	Reason HistoryReason
+}
+
+const (
+	historyAssetBaseTokens = byte(iota)
+	historyAssetNativeToken
+	historyAssetNFT
+)
+
+func (e *HistoryEntry) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
+	mu.WriteUint32(e.BlockIndex).
+		WriteUint16(e.RequestIndex).
+		Write(e.RequestID).
+		WriteByte(byte(e.Reason)).
+		WriteBool(e.Counterparty != nil)
+	if e.Counterparty != nil {
+		mu.WriteBytes(e.Counterparty.Bytes())
+	}
+	switch {
+	case e.NativeTokenID != nil:
+		mu.WriteByte(historyAssetNativeToken).WriteBytes(e.NativeTokenID[:])
+	case e.NFTID != nil:
+		mu.WriteByte(historyAssetNFT).WriteBytes(e.NFTID[:])
+	default:
+		mu.WriteByte(historyAssetBaseTokens)
+	}
+	abs := new(big.Int).Abs(e.Delta).Bytes()
+	mu.WriteBool(e.Delta.Sign() < 0).
+		WriteUint8(uint8(len(abs))).
+		WriteBytes(abs)
+}
+
+func (e *HistoryEntry) Bytes() []byte {
+	mu := marshalutil.New()
+	e.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+func HistoryEntryFromMarshalUtil(mu *marshalutil.MarshalUtil) (*HistoryEntry, error) {
+	ret := &HistoryEntry{}
+	var err error
+	if ret.BlockIndex, err = mu.ReadUint32(); err != nil {
+		return nil, err
+	}
+	if ret.RequestIndex, err = mu.ReadUint16(); err != nil {
+		return nil, err
+	}
+	if ret.RequestID, err = isc.RequestIDFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	reason, err := mu.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	ret.Reason = HistoryReason(reason)
+	hasCounterparty, err := mu.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	if hasCounterparty {
+		if ret.Counterparty, err = isc.AgentIDFromMarshalUtil(mu); err != nil {
+			return nil, err
+		}
+	}
+	assetKind, err := mu.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch assetKind {
+	case historyAssetBaseTokens:
+	case historyAssetNativeToken:
+		b, err := mu.ReadBytes(iotago.NativeTokenIDLength)
+		if err != nil {
+			return nil, err
+		}
+		ret.NativeTokenID = new(iotago.NativeTokenID)
+		copy(ret.NativeTokenID[:], b)
+	case historyAssetNFT:
+		b, err := mu.ReadBytes(iotago.NFTIDLength)
+		if err != nil {
+			return nil, err
+		}
+		ret.NFTID = new(iotago.NFTID)
+		copy(ret.NFTID[:], b)
+	default:
+		return nil, xerrors.Errorf("wrong asset kind of the history entry: %d", assetKind)
+	}
+	neg, err := mu.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	n, err := mu.ReadUint8()
+	if err != nil {
+		return nil, err
+	}
+	abs, err := mu.ReadBytes(int(n))
+	if err != nil {
+		return nil, err
+	}
+	ret.Delta = new(big.Int).SetBytes(abs)
+	if neg {
+		ret.Delta.Neg(ret.Delta)
+	}
+	return ret, nil
+}
+
+func HistoryEntryFromBytes(data []byte) (*HistoryEntry, error) {
+	return HistoryEntryFromMarshalUtil(marshalutil.New(data))
+}
+
+func (e *HistoryEntry) String() string {
+	asset := "base tokens"
+	switch {
+	case e.NativeTokenID != nil:
+		asset = e.NativeTokenID.String()
+	case e.NFTID != nil:
+		asset = "NFT " + e.NFTID.String()
+	}
+	counterparty := "L1"
+	if e.Counterparty != nil {
+		counterparty = e.Counterparty.String()
+	}
+	request := "request " + e.RequestID.String()
+	if e.RequestIndex == HistoryRequestIndexBlock {
+		request = "block context"
+	}
+	return fmt.Sprintf("block %d, %s: %s %s %s, counterparty: %s",
+		e.BlockIndex, request, e.Reason, e.Delta, asset, counterparty)
+}
+
+// IsAccountHistoryEnabled returns true if the movements of the L2 accounts are recorded
+func IsAccountHistoryEnabled(state kv.KVStoreReader) bool {
+	return codec.MustDecodeBool(state.MustGet(kv.Key(stateVarAccountHistoryEnabled)), false)
+}
+
+func setAccountHistoryEnabled(state kv.KVStore, enabled bool) {
+	if enabled {
+		state.Set(kv.Key(stateVarAccountHistoryEnabled), codec.EncodeBool(true))
+	} else {
+		state.Del(kv.Key(stateVarAccountHistoryEnabled))
+	}
+}
+
+// getPendingHistory returns the entries recorded by the current request. Each element is the AgentID
+// of the account followed by the entry. The block and the request are filled in by SaveAccountHistory
+func getPendingHistory(state kv.KVStore) *collections.Array32 {
+	return collections.NewArray32(state, prefixAccountHistoryPending)
+}
+
+// GetAccountHistoryRetention returns the number of the latest entries kept in the history of each account
+func GetAccountHistoryRetention(state kv.KVStoreReader) uint32 {
+	return codec.MustDecodeUint32(state.MustGet(kv.Key(stateVarAccountHistoryRetention)), DefaultAccountHistoryRetention)
+}
+
+func setAccountHistoryRetention(state kv.KVStore, retention uint32) {
+	state.Set(kv.Key(stateVarAccountHistoryRetention), codec.EncodeUint32(retention))
+}
+
+func accountHistoryName(agentID isc.AgentID) string {
+	return string(kv.Concat(prefixAccountHistory, agentID.Bytes()))
+}
+
+func accountHistoryFirstKey(agentID isc.AgentID) kv.Key {
+	return kv.Key(kv.Concat(prefixAccountHistoryFirst, agentID.Bytes()))
+}
+
+// getAccountHistoryFirst returns the index of the oldest entry kept in the history of the account.
+// The entries before it were pruned
+func getAccountHistoryFirst(state kv.KVStoreReader, agentID isc.AgentID) uint32 {
+	return codec.MustDecodeUint32(state.MustGet(accountHistoryFirstKey(agentID)), 0)
+}
+
+func getAccountHistory(state kv.KVStore, agentID isc.AgentID) *collections.Array32 {
+	return collections.NewArray32(state, accountHistoryName(agentID))
+}
+
+func getAccountHistoryR(state kv.KVStoreReader, agentID isc.AgentID) *collections.ImmutableArray32 {
+	return collections.NewArray32ReadOnly(state, accountHistoryName(agentID))
+}
+
+// recordFungibleTokens records the movement of the fungible tokens in (sign > 0) or out (sign < 0) of the account.
+// The callers check if the history is enabled
+func recordFungibleTokens(state kv.KVStore, agentID, counterparty isc.AgentID, assets *isc.FungibleTokens, sign int, reason HistoryReason) {
+	if assets == nil {
+		return
+	}
+	if assets.BaseTokens > 0 {
+		recordHistory(state, agentID, &HistoryEntry{
+			Counterparty: counterparty,
+			Delta:        signed(new(big.Int).SetUint64(assets.BaseTokens), sign),
+			Reason:       reason,
+		})
+	}
+	for _, nt := range assets.Tokens {
+		if nt.Amount == nil || nt.Amount.Sign() == 0 {
+			continue
+		}
+		id := nt.ID
+		recordHistory(state, agentID, &HistoryEntry{
+			Counterparty:  counterparty,
+			NativeTokenID: &id,
+			Delta:         signed(new(big.Int).Set(nt.Amount), sign),
+			Reason:        reason,
+		})
+	}
+}
+
+// recordNFTs records the movement of the NFTs in (sign > 0) or out (sign < 0) of the account
+func recordNFTs(state kv.KVStore, agentID, counterparty isc.AgentID, nftIDs []iotago.NFTID, sign int, reason HistoryReason) {
+	for i := range nftIDs {
+		id := nftIDs[i]
+		recordHistory(state, agentID, &HistoryEntry{
+			Counterparty: counterparty,
+			NFTID:        &id,
+			Delta:        signed(big.NewInt(1), sign),
+			Reason:       reason,
+		})
+	}
+}
+
+func signed(v *big.Int, sign int) *big.Int {
+	if sign < 0 {
+		return v.Neg(v)
+	}
+	return v
+}
+
+func recordHistory(state kv.KVStore, agentID isc.AgentID, entry *HistoryEntry) {
+	mu := marshalutil.New()
+	mu.WriteBytes(agentID.Bytes())
+	entry.WriteToMarshalUtil(mu)
+	getPendingHistory(state).MustPush(mu.Bytes())
+}
+
+// SaveAccountHistory moves the entries recorded by the request to the history of the accounts, pruning
+// the oldest entries of the accounts beyond the retention. It is called by the VM at the end of each request
+func SaveAccountHistory(state kv.KVStore, blockIndex uint32, requestIndex uint16, requestID isc.RequestID) {
+	pending := getPendingHistory(state)
+	n := pending.MustLen()
+	if n == 0 {
+		return
+	}
+	retention := GetAccountHistoryRetention(state)
+	for i := uint32(0); i < n; i++ {
+		mu := marshalutil.New(pending.MustGetAt(i))
+		agentID, err := isc.AgentIDFromMarshalUtil(mu)
+		if err != nil {
+			panic(fmt.Sprintf("SaveAccountHistory: inconsistency - wrong pending entry: %v", err))
+		}
+		entry, err := HistoryEntryFromMarshalUtil(mu)
+		if err != nil {
+			panic(fmt.Sprintf("SaveAccountHistory: inconsistency - wrong pending entry: %v", err))
+		}
+		entry.BlockIndex = blockIndex
+		entry.RequestIndex = requestIndex
+		entry.RequestID = requestID
+		history := getAccountHistory(state, agentID)
+		history.MustPush(entry.Bytes())
+		pruneAccountHistory(state, agentID, history.MustLen(), retention)
+	}
+	pending.MustErase()
+}
+
+// SaveBlockAccountHistory moves the entries recorded outside of the requests, by the contracts subscribed
+// to the block context, to the history of the accounts. It is called by the VM when it opens and closes the
+// block contexts, so that they are not attributed to a request
+func SaveBlockAccountHistory(state kv.KVStore, blockIndex uint32) {
+	SaveAccountHistory(state, blockIndex, HistoryRequestIndexBlock, isc.RequestID{})
+}
+
+// pruneAccountHistory deletes the oldest entries of the account, keeping at most retention entries
+func pruneAccountHistory(state kv.KVStore, agentID isc.AgentID, total, retention uint32) {
+	if total <= retention {
+		return
+	}
+	first := getAccountHistoryFirst(state, agentID)
+	if total-first <= retention {
+		return
+	}
+	name := accountHistoryName(agentID)
+	for ; total-first > retention; first++ {
+		state.Del(collections.Array32ElemKey(name, first))
+	}
+	state.Set(accountHistoryFirstKey(agentID), codec.EncodeUint32(first))
+}
+
+// GetAccountHistory returns at most limit entries of the history of the account, starting with the
+// entry at the offset, oldest first. The offsets before the oldest entry kept start with the oldest one.
+// It also returns the index of the oldest entry kept and the total number of entries recorded for the account
+func GetAccountHistory(state kv.KVStoreReader, agentID isc.AgentID, offset, limit uint32) ([]*HistoryEntry, uint32, uint32) {
+	history := getAccountHistoryR(state, agentID)
+	total := history.MustLen()
+	first := getAccountHistoryFirst(state, agentID)
+	if offset < first {
+		offset = first
+	}
+	if offset >= total {
+		return nil, first, total
+	}
+	if limit > total-offset {
+		limit = total - offset
+	}
+	ret := make([]*HistoryEntry, limit)
+	for i := range ret {
+		entry, err := HistoryEntryFromBytes(history.MustGetAt(offset + uint32(i)))
+		if err != nil {
+			panic(fmt.Sprintf("GetAccountHistory: inconsistency - wrong entry of %s: %v", agentID, err))
+		}
+		ret[i] = entry
+	}
+	return ret, first, total
+}
@@ -0,0 +1,49 @@
+package accounts
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"golang.org/x/xerrors"
+)
+
+func getMultisigPolicies(state kv.KVStore) *collections.Map {
+	return collections.NewMap(state, prefixMultisigPolicies)
+}
+
+func getMultisigPoliciesR(state kv.KVStoreReader) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, prefixMultisigPolicies)
+}
+
+// GetMultisigPolicy returns the current policy of the multisig account, nil if the account is not registered
+func GetMultisigPolicy(state kv.KVStoreReader, agentID *isc.MultisigAgentID) *isc.MultisigPolicy {
+	id := agentID.ID()
+	data := getMultisigPoliciesR(state).MustGetAt(id[:])
+	if data == nil {
+		return nil
+	}
+	policy, err := isc.MultisigPolicyFromBytes(data)
+	if err != nil {
+		panic(fmt.Sprintf("GetMultisigPolicy: inconsistency - wrong policy of %s: %v", agentID, err))
+	}
+	return policy
+}
+
+func setMultisigPolicy(state kv.KVStore, agentID *isc.MultisigAgentID, policy *isc.MultisigPolicy) {
+	id := agentID.ID()
+	getMultisigPolicies(state).MustSetAt(id[:], policy.Bytes())
+}
+
+// CheckMultisigPolicy checks if the policy is the current one of the registered multisig account
+func CheckMultisigPolicy(state kv.KVStoreReader, agentID *isc.MultisigAgentID, policy *isc.MultisigPolicy) error {
+	current := GetMultisigPolicy(state, agentID)
+	if current == nil {
+		return xerrors.Errorf("multisig account %s is not registered", agentID)
+	}
+	if !current.Equals(policy) {
+		return xerrors.Errorf("policy is not the current one of the multisig account %s", agentID)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
@@ -11,8 +12,8 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// CreditNFTToAccount credits an NFT to the on chain ledger
-func CreditNFTToAccount(state kv.KVStore, agentID isc.AgentID, nft *isc.NFT) {
+// CreditNFTToAccount credits an NFT, which came to the chain or was minted on L2, to the on chain ledger
+func CreditNFTToAccount(state kv.KVStore, agentID isc.AgentID, nft *isc.NFT, reason HistoryReason) {
 	if nft == nil {
 		return
 	}
@@ -24,6 +25,9 @@ func CreditNFTToAccount(state kv.KVStore, agentID isc.AgentID, nft *isc.NFT) {
 	saveNFTData(state, nft)
 	creditNFTToAccount(state, account, nft.ID, agentID)
 	touchAccount(state, account)
+	if IsAccountHistoryEnabled(state) {
+		recordNFTs(state, agentID, nil, []iotago.NFTID{nft.ID}, 1, reason)
+	}
 }
 
 func saveNFTData(state kv.KVStore, nft *isc.NFT) {
@@ -40,6 +44,8 @@ func deleteNFTData(state kv.KVStore, id iotago.NFTID) {
 		panic("deleteNFTData: inconsistency - NFT data doesn't exists")
 	}
 	nftMap.MustDelAt(id[:])
+	// the NFT leaves the chain, if it was minted on L2 it now exists on L1
+	getL2NativeNFTs(state).MustDelAt(id[:])
 }
 
 func GetNFTData(state kv.KVStoreReader, id iotago.NFTID) isc.NFT {
@@ -73,7 +79,7 @@ func creditNFTToAccount(state kv.KVStore, account *collections.Map, id iotago.NF
 
 // DebitNFTFromAccount removes an NFT from an account. if that account doesn't own the nft, it panics
 // this will also delete the NFT data, as the NFT will be leaving the chain
-func DebitNFTFromAccount(state kv.KVStore, agentID isc.AgentID, id iotago.NFTID) {
+func DebitNFTFromAccount(state kv.KVStore, agentID isc.AgentID, id iotago.NFTID, reason HistoryReason) {
 	if id.Empty() {
 		return
 	}
@@ -88,6 +94,9 @@ func DebitNFTFromAccount(state kv.KVStore, agentID isc.AgentID, id iotago.NFTID)
 
 	deleteNFTData(state, id)
 	touchAccount(state, account)
+	if IsAccountHistoryEnabled(state) {
+		recordNFTs(state, agentID, nil, []iotago.NFTID{id}, -1, reason)
+	}
 }
 
 // DebitNFTFromAccount removes an NFT from the internal map of an account
@@ -99,3 +108,45 @@ func debitNFTFromAccount(account *collections.Map, id iotago.NFTID) bool {
 	err = account.DelAt(id[:])
 	return err == nil
 }
+
+func getL2NativeNFTs(state kv.KVStore) *collections.Map {
+	return collections.NewMap(state, prefixL2NativeNFTs)
+}
+
+func getL2NativeNFTsR(state kv.KVStoreReader) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, prefixL2NativeNFTs)
+}
+
+// IsL2NativeNFT returns true if the NFT was minted on L2 and doesn't exist on L1 yet.
+// Such an NFT is minted on L1 when it leaves the chain
+func IsL2NativeNFT(state kv.KVStoreReader, id iotago.NFTID) bool {
+	return getL2NativeNFTsR(state).MustHasAt(id[:])
+}
+
+// newL2NativeNFTID returns a unique ID for the next NFT minted on L2. It can't collide with the IDs of L1 NFTs,
+// which are hashes of output IDs
+func newL2NativeNFTID(state kv.KVStore, chainID *isc.ChainID) iotago.NFTID {
+	counter := codec.MustDecodeUint64(state.MustGet(kv.Key(stateVarL2NativeNFTCounter)), 0)
+	state.Set(kv.Key(stateVarL2NativeNFTCounter), codec.EncodeUint64(counter+1))
+	var ret iotago.NFTID
+	h := hashing.HashData([]byte("L2 native NFT"), chainID.Bytes(), codec.EncodeUint64(counter))
+	copy(ret[:], h[:])
+	return ret
+}
+
+// mintL2NativeNFT creates a new NFT on L2 and credits it to the account
+func mintL2NativeNFT(state kv.KVStore, chainID *isc.ChainID, agentID isc.AgentID, issuer iotago.Address, metadata []byte) iotago.NFTID {
+	nft := &isc.NFT{
+		ID:       newL2NativeNFTID(state, chainID),
+		Issuer:   issuer,
+		Metadata: metadata,
+	}
+	getL2NativeNFTs(state).MustSetAt(nft.ID[:], []byte{0xff})
+	CreditNFTToAccount(state, agentID, nft, HistoryReasonMint)
+	return nft.ID
+}
+
+// hasNFT checks if the account owns the NFT
+func hasNFT(state kv.KVStoreReader, agentID isc.AgentID, id iotago.NFTID) bool {
+	return getAccountR(state, agentID).MustHasAt(id[:])
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 )
@@ -101,11 +103,13 @@ func getBlobField(ctx isc.SandboxView) dict.Dict {
 
 func listBlobs(ctx isc.SandboxView) dict.Dict {
 	ctx.Log().Debugf("blob.listBlobs.begin")
+	cursor, limit := coreutil.GetPageParams(ctx)
 	ret := dict.New()
-	GetDirectoryR(ctx.StateR()).MustIterate(func(hash []byte, totalSize []byte) bool {
-		ret.Set(kv.Key(hash), totalSize)
-		return true
+	sizes := collections.NewMap(ret, ParamBlobSizes)
+	next := GetDirectoryR(ctx.StateR()).MustIteratePage(cursor, limit, nil, func(hash []byte, totalSize []byte) {
+		sizes.MustSetAt(hash, totalSize)
 	})
+	coreutil.SetNextPageCursor(ret, next)
 	return ret
 }
 
@@ -13,6 +13,8 @@ const (
 	ParamHash  = "hash"
 	ParamField = "field"
 	ParamBytes = "bytes"
+	// ParamBlobSizes the map of blob hash => total size in the result of the listBlobs view
+	ParamBlobSizes = "sizes"
 
 	// variable names of standard blob's field
 	// user-defined field must be different
@@ -105,18 +105,24 @@ func DecodeSizesMap(sizes dict.Dict) (map[string]uint32, error) {
 	return ret, nil
 }
 
-func DecodeDirectory(blobs dict.Dict) (map[hashing.HashValue]uint32, error) {
+// DecodeDirectory decodes a page of the result of the listBlobs view
+func DecodeDirectory(page dict.Dict) (map[hashing.HashValue]uint32, error) {
 	ret := make(map[hashing.HashValue]uint32)
-	for hash, size := range blobs {
-		v, err := DecodeSize(size)
-		if err != nil {
-			return nil, err
+	var err error
+	collections.NewMapReadOnly(page, ParamBlobSizes).MustIterate(func(hash []byte, size []byte) bool {
+		var v uint32
+		if v, err = DecodeSize(size); err != nil {
+			return false
 		}
-		h, err := codec.DecodeHashValue([]byte(hash))
-		if err != nil {
-			return nil, err
+		var h hashing.HashValue
+		if h, err = codec.DecodeHashValue(hash); err != nil {
+			return false
 		}
 		ret[h] = v
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 	return ret, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,3 +22,48 @@ func TestSerdeRequestReceipt(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, forward, back.Bytes())
 }
+
+func TestSerdeRequestReceiptOptionalFields(t *testing.T) {
+	keyPair := cryptolib.NewKeyPair()
+	req := isc.NewOffLedgerRequest(isc.RandomChainID(), isc.Hn("0"), isc.Hn("0"), nil, 0).Sign(keyPair)
+	rec := &RequestReceipt{
+		Request:       req,
+		GasBudget:     100,
+		GasBurned:     10,
+		GasFeeCharged: 1,
+	}
+	// a receipt without the optional fields is encoded as before they were introduced
+	plain := rec.Bytes()
+	back, err := RequestReceiptFromBytes(plain)
+	require.NoError(t, err)
+	require.Nil(t, back.GasFeePayer)
+	require.Nil(t, back.CallResults)
+
+	rec.GasFeePayer = req.SenderAccount()
+	withSender := rec.Bytes()
+	require.Equal(t, plain, withSender[:len(plain)])
+	require.Len(t, withSender, len(plain)+1)
+	back, err = RequestReceiptFromBytes(withSender)
+	require.NoError(t, err)
+	require.True(t, req.SenderAccount().Equals(back.GasFeePayer))
+
+	sponsor := isc.NewAgentID(cryptolib.NewKeyPair().Address())
+	rec.GasFeePayer = sponsor
+	rec.CallResults = []dict.Dict{{"a": []byte{1}}, {}}
+	rec.Error = &isc.UnresolvedVMError{ErrorCode: isc.VMErrorCode{ContractID: isc.Hn("c"), ID: 1}}
+	forward := rec.Bytes()
+	back, err = RequestReceiptFromBytes(forward)
+	require.NoError(t, err)
+	require.True(t, sponsor.Equals(back.GasFeePayer))
+	require.Equal(t, rec.CallResults, back.CallResults)
+	require.Equal(t, rec.Error.Bytes(), back.Error.Bytes())
+	require.Equal(t, forward, back.Bytes())
+
+	rec.GasFeePayer = nil
+	rec.CallResults = nil
+	rec.Error = nil
+	_, err = RequestReceiptFromBytes(append(rec.Bytes(), 0x80))
+	require.Error(t, err)
+	_, err = RequestReceiptFromBytes(append(rec.Bytes(), receiptFlagGasFeePayerSender|receiptFlagGasFeePayerSponsor))
+	require.Error(t, err)
+}
@@ -34,6 +34,17 @@ func (k EventLookupKey) Bytes() []byte {
 	return k[:]
 }
 
+// Before returns true if the event of k was emitted before the event of other
+func (k EventLookupKey) Before(other EventLookupKey) bool {
+	if k.BlockIndex() != other.BlockIndex() {
+		return k.BlockIndex() < other.BlockIndex()
+	}
+	if k.RequestIndex() != other.RequestIndex() {
+		return k.RequestIndex() < other.RequestIndex()
+	}
+	return k.RequestEventIndex() < other.RequestEventIndex()
+}
+
 func (k *EventLookupKey) Write(w io.Writer) error {
 	_, err := w.Write(k[:])
 	return err
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -189,7 +190,8 @@ func viewGetEventsForContract(ctx isc.SandboxView) dict.Dict {
 	contract := ctx.Params().MustGetHname(ParamContractHname)
 	fromBlock := ctx.Params().MustGetUint32(ParamFromBlock, 0)
 	toBlock := ctx.Params().MustGetUint32(ParamToBlock, math.MaxUint32)
-	events, err := getSmartContractEventsInternal(ctx.StateR(), contract, fromBlock, toBlock)
+	cursor, limit := coreutil.GetPageParams(ctx)
+	events, next, err := getSmartContractEventsInternal(ctx.StateR(), contract, fromBlock, toBlock, cursor, limit)
 	ctx.RequireNoError(err)
 
 	ret := dict.New()
@@ -197,5 +199,6 @@ func viewGetEventsForContract(ctx isc.SandboxView) dict.Dict {
 	for _, event := range events {
 		arr.MustPush([]byte(event))
 	}
+	coreutil.SetNextPageCursor(ret, next)
 	return ret
 }
@@ -207,35 +207,52 @@ func getRequestEventsInternal(partition kv.KVStoreReader, reqID *isc.RequestID)
 	}
 }
 
-func getSmartContractEventsInternal(partition kv.KVStoreReader, contract isc.Hname, fromBlock, toBlock uint32) ([]string, error) {
+// getSmartContractEventsInternal returns at most limit events of the contract emitted in the block range, after
+// the event with the cursor as the lookup key, or from the first one if the cursor is nil. It also returns the
+// cursor of the next page, nil if there are no more events
+func getSmartContractEventsInternal(partition kv.KVStoreReader, contract isc.Hname, fromBlock, toBlock uint32, cursor []byte, limit uint32) ([]string, []byte, error) {
 	scLut := collections.NewMapReadOnly(partition, prefixSmartContractEventsLookup)
 	ret := []string{}
 	entries, err := scLut.GetAt(contract.Bytes())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	var cursorKey *EventLookupKey
+	if cursor != nil {
+		if cursorKey, err = EventLookupKeyFromBytes(bytes.NewReader(cursor)); err != nil {
+			return nil, nil, xerrors.Errorf("getSmartContractEventsIntern wrong cursor. %v", err)
+		}
 	}
 	events := collections.NewMapReadOnly(partition, prefixRequestEvents)
 	keysBuf := bytes.NewBuffer(entries)
+	var lastKey *EventLookupKey
 	for {
 		key, err := EventLookupKeyFromBytes(keysBuf)
 		if err != nil && !errors.Is(err, io.EOF) {
-			return nil, xerrors.Errorf("getSmartContractEventsIntern unable to parse key. %v", err)
+			return nil, nil, xerrors.Errorf("getSmartContractEventsIntern unable to parse key. %v", err)
 		}
 		if key == nil { // no more events
-			return ret, nil
+			return ret, nil, nil
 		}
 		keyBlockIndex := key.BlockIndex()
 		if keyBlockIndex < fromBlock {
 			continue
 		}
 		if keyBlockIndex > toBlock {
-			return ret, nil
+			return ret, nil, nil
+		}
+		if cursorKey != nil && !cursorKey.Before(*key) {
+			continue
+		}
+		if uint32(len(ret)) == limit {
+			return ret, lastKey.Bytes(), nil
 		}
 		event, err := events.GetAt(key.Bytes())
 		if err != nil {
-			return nil, xerrors.Errorf("getSmartContractEventsIntern unable to get event by key. %v", err)
+			return nil, nil, xerrors.Errorf("getSmartContractEventsIntern unable to get event by key. %v", err)
 		}
 		ret = append(ret, string(event))
+		lastKey = key
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/gas"
 )
@@ -22,6 +23,11 @@ type RequestReceipt struct {
 	GasBudget     uint64                 `json:"gasBudget"`
 	GasBurned     uint64                 `json:"gasBurned"`
 	GasFeeCharged uint64                 `json:"gasFeeCharged"`
+	// the account the gas fee was charged from: the sender or the sponsor of the request. Nil if no fee was charged
+	GasFeePayer isc.AgentID `json:"gasFeePayer"`
+	// results of the calls of the multi-call request, in the order of the calls. If the request failed,
+	// the results of the calls run before the failing one. Nil for other requests
+	CallResults []dict.Dict `json:"callResults"`
 	// not persistent
 	BlockIndex   uint32       `json:"blockIndex"`
 	RequestIndex uint16       `json:"requestIndex"`
@@ -32,6 +38,8 @@ func RequestReceiptFromBytes(data []byte) (*RequestReceipt, error) {
 	return RequestReceiptFromMarshalUtil(marshalutil.New(data))
 }
 
+// RequestReceiptFromMarshalUtil reads the receipt. The optional fields are at the
+// end of the encoding, so the receipt must be the last thing in the buffer
 func RequestReceiptFromMarshalUtil(mu *marshalutil.MarshalUtil) (*RequestReceipt, error) {
 	ret := &RequestReceipt{}
 
@@ -50,16 +58,19 @@ func RequestReceiptFromMarshalUtil(mu *marshalutil.MarshalUtil) (*RequestReceipt
 		return nil, err
 	}
 
-	if isError, err := mu.ReadBool(); err != nil {
+	isError, err := mu.ReadBool()
+	if err != nil {
 		return nil, err
-	} else if !isError {
-		return ret, nil
+	}
+	if isError {
+		if ret.Error, err = isc.UnresolvedVMErrorFromMarshalUtil(mu); err != nil {
+			return nil, err
+		}
 	}
 
-	if ret.Error, err = isc.UnresolvedVMErrorFromMarshalUtil(mu); err != nil {
+	if err := ret.readOptionalFields(mu); err != nil {
 		return nil, err
 	}
-
 	return ret, nil
 }
 
@@ -79,9 +90,86 @@ func (r *RequestReceipt) Bytes() []byte {
 		mu.WriteBytes(r.Error.Bytes())
 	}
 
+	r.writeOptionalFields(mu)
+
 	return mu.Bytes()
 }
 
+// the flags of the optional fields, which follow the mandatory ones. The receipts
+// without the optional fields are encoded without the flags byte, the same as the
+// receipts written before the fields were introduced
+const (
+	receiptFlagGasFeePayerSender byte = 1 << iota
+	receiptFlagGasFeePayerSponsor
+	receiptFlagCallResults
+
+	receiptFlagsAll = receiptFlagGasFeePayerSender | receiptFlagGasFeePayerSponsor | receiptFlagCallResults
+)
+
+// writeOptionalFields writes the flags byte and the fields it announces. The gas
+// fee payer is written only if it is not the sender of the request
+func (r *RequestReceipt) writeOptionalFields(mu *marshalutil.MarshalUtil) {
+	var flags byte
+	switch {
+	case r.GasFeePayer == nil:
+	case r.GasFeePayer.Equals(r.Request.SenderAccount()):
+		flags |= receiptFlagGasFeePayerSender
+	default:
+		flags |= receiptFlagGasFeePayerSponsor
+	}
+	if r.CallResults != nil {
+		flags |= receiptFlagCallResults
+	}
+	if flags == 0 {
+		return
+	}
+	mu.WriteByte(flags)
+	if flags&receiptFlagGasFeePayerSponsor != 0 {
+		mu.WriteBytes(r.GasFeePayer.Bytes())
+	}
+	if flags&receiptFlagCallResults != 0 {
+		mu.WriteUint16(uint16(len(r.CallResults)))
+		for _, res := range r.CallResults {
+			mu.Write(res)
+		}
+	}
+}
+
+func (r *RequestReceipt) readOptionalFields(mu *marshalutil.MarshalUtil) error {
+	done, err := mu.DoneReading()
+	if err != nil || done {
+		return err
+	}
+	flags, err := mu.ReadByte()
+	if err != nil {
+		return err
+	}
+	if flags&^receiptFlagsAll != 0 || flags&receiptFlagGasFeePayerSender != 0 && flags&receiptFlagGasFeePayerSponsor != 0 {
+		return fmt.Errorf("invalid receipt flags %#x", flags)
+	}
+	if flags&receiptFlagGasFeePayerSender != 0 {
+		r.GasFeePayer = r.Request.SenderAccount()
+	}
+	if flags&receiptFlagGasFeePayerSponsor != 0 {
+		if r.GasFeePayer, err = isc.AgentIDFromMarshalUtil(mu); err != nil {
+			return err
+		}
+	}
+	if flags&receiptFlagCallResults != 0 {
+		n, err := mu.ReadUint16()
+		if err != nil {
+			return err
+		}
+		r.CallResults = make([]dict.Dict, n)
+		for i := range r.CallResults {
+			if r.CallResults[i], err = dict.FromMarshalUtil(mu); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (r *RequestReceipt) WithBlockData(blockIndex uint32, requestIndex uint16) *RequestReceipt {
 	r.BlockIndex = blockIndex
 	r.RequestIndex = requestIndex
@@ -93,6 +181,9 @@ func (r *RequestReceipt) String() string {
 	ret += fmt.Sprintf("Err: %v\n", r.Error)
 	ret += fmt.Sprintf("Block/Request index: %d / %d\n", r.BlockIndex, r.RequestIndex)
 	ret += fmt.Sprintf("Gas budget / burned / fee charged: %d / %d /%d\n", r.GasBudget, r.GasBurned, r.GasFeeCharged)
+	if r.GasFeePayer != nil {
+		ret += fmt.Sprintf("Gas fee paid by: %s\n", r.GasFeePayer)
+	}
 	ret += fmt.Sprintf("Call data: %s\n", r.Request)
 	return ret
 }
@@ -117,12 +208,18 @@ func (r *RequestReceipt) LookupKey() RequestLookupKey {
 }
 
 func (r *RequestReceipt) ToISCReceipt(resolvedError *isc.VMError) *isc.Receipt {
+	var gasFeePayer string
+	if r.GasFeePayer != nil {
+		gasFeePayer = r.GasFeePayer.String()
+	}
 	return &isc.Receipt{
 		Request:       r.Request.Bytes(),
 		Error:         r.Error,
 		GasBudget:     r.GasBudget,
 		GasBurned:     r.GasBurned,
 		GasFeeCharged: r.GasFeeCharged,
+		GasFeePayer:   gasFeePayer,
+		CallResults:   r.CallResults,
 		BlockIndex:    r.BlockIndex,
 		RequestIndex:  r.RequestIndex,
 		ResolvedError: resolvedError.Error(),
@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 )
 
 var All = map[isc.Hname]*coreutil.ContractInfo{
@@ -22,6 +23,7 @@ var All = map[isc.Hname]*coreutil.ContractInfo{
 	blocklog.Contract.Hname():   blocklog.Contract,
 	governance.Contract.Hname(): governance.Contract,
 	evm.Contract.Hname():        evm.Contract,
+	scheduler.Contract.Hname():  scheduler.Contract,
 }
 
 func AllSortedByName() []*coreutil.ContractInfo {
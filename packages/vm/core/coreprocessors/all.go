@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/governance/governanceimpl"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/core/root/rootimpl"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 )
 
@@ -25,6 +26,7 @@ var All = map[hashing.HashValue]isc.VMProcessor{
 	blocklog.Contract.ProgramHash:   blocklog.Processor,
 	governance.Contract.ProgramHash: governanceimpl.Processor,
 	evm.Contract.ProgramHash:        evmimpl.Processor,
+	scheduler.Contract.ProgramHash:  scheduler.Processor,
 }
 
 func init() {
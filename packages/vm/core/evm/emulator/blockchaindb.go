@@ -27,6 +27,8 @@ const (
 	keyGasLimit = "g"
 	// Amount of blocks to keep in DB. Older blocks will be pruned every time a transaction is added
 	keyKeepAmount = "k"
+	// Number of the block activating the London hard fork, absent if it is not scheduled
+	keyLondonBlock = "l"
 
 	// blocks:
 
@@ -100,6 +102,28 @@ func (bc *BlockchainDB) keepAmount() int32 {
 	return gas
 }
 
+func (bc *BlockchainDB) SetLondonBlock(blockNumber uint64) {
+	bc.kv.Set(keyLondonBlock, codec.EncodeUint64(blockNumber))
+}
+
+// GetLondonBlock returns the number of the block activating the London hard fork, nil if it is not scheduled
+func (bc *BlockchainDB) GetLondonBlock() *big.Int {
+	b := bc.kv.MustGet(keyLondonBlock)
+	if b == nil {
+		return nil
+	}
+	blockNumber, err := codec.DecodeUint64(b)
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetUint64(blockNumber)
+}
+
+func (bc *BlockchainDB) isLondon(blockNumber uint64) bool {
+	londonBlock := bc.GetLondonBlock()
+	return londonBlock != nil && londonBlock.Uint64() <= blockNumber
+}
+
 func (bc *BlockchainDB) setPendingTimestamp(timestamp uint64) {
 	bc.kv.Set(keyPendingTimestamp, codec.EncodeUint64(timestamp))
 }
@@ -161,12 +185,17 @@ func (bc *BlockchainDB) GetPendingBlockNumber() uint64 {
 }
 
 func (bc *BlockchainDB) GetPendingHeader() *types.Header {
-	return &types.Header{
+	header := &types.Header{
 		Difficulty: &big.Int{},
 		Number:     new(big.Int).SetUint64(bc.GetPendingBlockNumber()),
 		GasLimit:   bc.GetGasLimit(),
 		Time:       bc.getPendingTimestamp(),
 	}
+	if bc.isLondon(header.Number.Uint64()) {
+		// the fees are charged by ISC, so the base fee is zero
+		header.BaseFee = new(big.Int)
+	}
+	return header
 }
 
 func (bc *BlockchainDB) GetLatestPendingReceipt() *types.Receipt {
@@ -452,7 +481,12 @@ func (bc *BlockchainDB) GetHeaderByBlockNumber(blockNumber uint64) *types.Header
 	if blockNumber > bc.GetNumber() {
 		return nil
 	}
-	return bc.headerFromGob(bc.getHeaderGobByBlockNumber(blockNumber), blockNumber)
+	g := bc.getHeaderGobByBlockNumber(blockNumber)
+	if g == nil {
+		// pruned
+		return nil
+	}
+	return bc.headerFromGob(g, blockNumber)
 }
 
 func (bc *BlockchainDB) getHeaderGobByBlockNumber(blockNumber uint64) *headerGob {
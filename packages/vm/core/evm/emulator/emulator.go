@@ -17,6 +17,8 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/iotaledger/wasp/packages/evm/evmutil"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"golang.org/x/xerrors"
 )
@@ -29,7 +31,7 @@ type EVMEmulator struct {
 	getBalance  BalanceFunc
 }
 
-func makeConfig(chainID int) *params.ChainConfig {
+func makeConfig(chainID int, londonBlock *big.Int) *params.ChainConfig {
 	return &params.ChainConfig{
 		ChainID:             big.NewInt(int64(chainID)),
 		HomesteadBlock:      big.NewInt(0),
@@ -43,6 +45,7 @@ func makeConfig(chainID int) *params.ChainConfig {
 		IstanbulBlock:       big.NewInt(0),
 		MuirGlacierBlock:    big.NewInt(0),
 		BerlinBlock:         big.NewInt(0),
+		LondonBlock:         londonBlock,
 		Ethash:              &params.EthashConfig{},
 	}
 }
@@ -56,10 +59,39 @@ func newStateDB(store kv.KVStore, getBalance BalanceFunc) *StateDB {
 	return NewStateDB(subrealm.New(store, keyStateDB), getBalance)
 }
 
+// GetNonce returns the nonce of the Ethereum account, reading it directly from the emulator state
+func GetNonce(store kv.KVStoreReader, addr common.Address) uint64 {
+	n, err := codec.DecodeUint64(subrealm.NewReadOnly(store, keyStateDB).MustGet(accountNonceKey(addr)), 0)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// NonceKey returns the key of the account nonce, relative to the emulator state
+func NonceKey(addr common.Address) kv.Key {
+	return keyStateDB + accountNonceKey(addr)
+}
+
+// CodeKey returns the key of the account code, relative to the emulator state
+func CodeKey(addr common.Address) kv.Key {
+	return keyStateDB + accountCodeKey(addr)
+}
+
+// StorageKey returns the key of an account storage slot, relative to the emulator state
+func StorageKey(addr common.Address, slot common.Hash) kv.Key {
+	return keyStateDB + accountStateKey(addr, slot)
+}
+
 func newBlockchainDB(store kv.KVStore) *BlockchainDB {
 	return NewBlockchainDB(subrealm.New(store, keyBlockchainDB))
 }
 
+// GetBlockchainDB returns read access to the BlockchainDB of the emulator state
+func GetBlockchainDB(store kv.KVStoreReader) *BlockchainDB {
+	return newBlockchainDB(buffered.NewBufferedKVStoreAccess(store))
+}
+
 // Init initializes the EVM state with the provided genesis allocation parameters
 func Init(store kv.KVStore, chainID uint16, blockKeepAmount int32, gasLimit, timestamp uint64, alloc core.GenesisAlloc, getBalance BalanceFunc) {
 	bdb := newBlockchainDB(store)
@@ -92,9 +124,9 @@ func NewEVMEmulator(store kv.KVStore, timestamp uint64, magicContract vm.ISCCont
 
 	return &EVMEmulator{
 		timestamp:   timestamp,
-		chainConfig: makeConfig(int(bdb.GetChainID())),
+		chainConfig: makeConfig(int(bdb.GetChainID()), bdb.GetLondonBlock()),
 		kv:          store,
-		vmConfig:    vm.Config{ISCContract: magicContract},
+		vmConfig:    vm.Config{ISCContract: magicContract, NoBaseFee: true},
 		getBalance:  getBalance,
 	}
 }
@@ -168,6 +200,9 @@ func (e *EVMEmulator) CallContract(call ethereum.CallMsg, gasBurnEnable func(boo
 }
 
 func (e *EVMEmulator) applyMessage(msg core.Message, statedb vm.StateDB, header *types.Header, gasBurnEnable func(bool)) (*core.ExecutionResult, error) {
+	// gas fees are charged by ISC according to the governance fee policy,
+	// so the EVM itself does not charge anything
+	msg = feelessMsg{msg}
 	blockContext := core.NewEVMBlockContext(header, e.ChainContext(), nil)
 	txContext := core.NewEVMTxContext(msg)
 	vmEnv := vm.NewEVM(blockContext, txContext, statedb, e.chainConfig, e.vmConfig)
@@ -194,7 +229,14 @@ func (e *EVMEmulator) SendTransaction(tx *types.Transaction, gasBurnEnable func(
 		return nil, nil, xerrors.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
 	}
 
-	msg, err := tx.AsMessage(types.MakeSigner(e.chainConfig, pendingHeader.Number), pendingHeader.BaseFee)
+	if tx.Type() == types.DynamicFeeTxType && !e.chainConfig.IsLondon(pendingHeader.Number) {
+		return nil, nil, xerrors.Errorf("invalid transaction: %w", types.ErrTxTypeNotSupported)
+	}
+	if tx.GasFeeCap().Cmp(tx.GasTipCap()) < 0 {
+		return nil, nil, xerrors.Errorf("invalid transaction: %w", core.ErrTipAboveFeeCap)
+	}
+
+	msg, err := tx.AsMessage(e.Signer(), pendingHeader.BaseFee)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -247,7 +289,7 @@ func (e *EVMEmulator) MintBlock() {
 // returning all the results in one batch.
 func (e *EVMEmulator) FilterLogs(query *ethereum.FilterQuery) []*types.Log {
 	receipts := e.getReceiptsInFilterRange(query)
-	return e.filterLogs(query, receipts)
+	return FilterReceiptLogs(query, receipts)
 }
 
 func (e *EVMEmulator) getReceiptsInFilterRange(query *ethereum.FilterQuery) []*types.Receipt {
@@ -283,7 +325,13 @@ func (e *EVMEmulator) getReceiptsInFilterRange(query *ethereum.FilterQuery) []*t
 	return receipts
 }
 
-func (e *EVMEmulator) filterLogs(query *ethereum.FilterQuery, receipts []*types.Receipt) []*types.Log {
+// BloomMatches reports whether the bloom filter may contain logs matching the query
+func BloomMatches(bloom types.Bloom, query *ethereum.FilterQuery) bool {
+	return bloomFilter(bloom, query.Addresses, query.Topics)
+}
+
+// FilterReceiptLogs returns the logs of the receipts matching the addresses and topics of the query
+func FilterReceiptLogs(query *ethereum.FilterQuery, receipts []*types.Receipt) []*types.Log {
 	var logs []*types.Log
 	for _, r := range receipts {
 		if !bloomFilter(r.Bloom, query.Addresses, query.Topics) {
@@ -381,6 +429,15 @@ func (m callMsg) Value() *big.Int              { return m.CallMsg.Value }
 func (m callMsg) Data() []byte                 { return m.CallMsg.Data }
 func (m callMsg) AccessList() types.AccessList { return m.CallMsg.AccessList }
 
+// feelessMsg wraps a core.Message, overriding the fee fields with zero
+type feelessMsg struct {
+	core.Message
+}
+
+func (m feelessMsg) GasPrice() *big.Int  { return new(big.Int) }
+func (m feelessMsg) GasFeeCap() *big.Int { return new(big.Int) }
+func (m feelessMsg) GasTipCap() *big.Int { return new(big.Int) }
+
 type chainContext struct {
 	engine consensus.Engine
 }
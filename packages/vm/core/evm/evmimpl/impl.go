@@ -23,6 +23,8 @@ import (
 var Processor = evm.Contract.Processor(initialize,
 	evm.FuncSetGasRatio.WithHandler(setGasRatio),
 	evm.FuncGetGasRatio.WithHandler(getGasRatio),
+	evm.FuncSetLondonBlock.WithHandler(setLondonBlock),
+	evm.FuncGetLondonBlock.WithHandler(getLondonBlock),
 	evm.FuncSendTransaction.WithHandler(applyTransaction),
 	evm.FuncGetBalance.WithHandler(getBalance),
 	evm.FuncCallContract.WithHandler(callContract),
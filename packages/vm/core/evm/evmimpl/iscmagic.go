@@ -17,7 +17,9 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/evm/iscmagic"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext/vmexceptions"
 )
@@ -254,6 +256,34 @@ func (c *magicContract) doRun(evm *vm.EVM, caller vm.ContractRef, input []byte,
 		moveAssetsToCommonAccount(c.ctx, caller, req.FungibleTokens, []iotago.NFTID{nftID})
 		c.ctx.SendAsNFT(req, nftID)
 
+	case "mintNFT":
+		var params struct {
+			Metadata     []byte
+			CollectionID iscmagic.NFTID
+		}
+		err := method.Inputs.Copy(&params, args)
+		c.ctx.RequireNoError(err)
+		callerAgentID := isc.NewEthereumAddressAgentID(caller.Address())
+		mintParams := dict.Dict{
+			accounts.ParamNFTImmutableData: params.Metadata,
+			accounts.ParamAgentID:          codec.EncodeAgentID(callerAgentID),
+		}
+		collectionID := params.CollectionID.Unwrap()
+		hasCollection := !collectionID.Empty()
+		// the collection NFT must be owned by the evm contract, the caller of the accounts contract, while minting
+		evmAgentID := isc.NewContractAgentID(c.ctx.ChainID(), c.ctx.Contract())
+		if hasCollection {
+			c.ctx.Privileged().MustMoveBetweenAccounts(callerAgentID, evmAgentID, nil, []iotago.NFTID{collectionID})
+			mintParams[accounts.ParamNFTCollectionID] = collectionID[:]
+		}
+		mintRet := c.ctx.Call(accounts.Contract.Hname(), accounts.FuncMintNFT.Hname(), mintParams, nil)
+		if hasCollection {
+			c.ctx.Privileged().MustMoveBetweenAccounts(evmAgentID, callerAgentID, nil, []iotago.NFTID{collectionID})
+		}
+		var nftID iotago.NFTID
+		copy(nftID[:], mintRet.MustGet(accounts.ParamNFTID))
+		outs = []interface{}{iscmagic.WrapNFTID(nftID)}
+
 	case "call":
 		var callArgs struct {
 			ContractHname uint32
@@ -0,0 +1,71 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evmimpl
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/evm/evmutil"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/emulator"
+)
+
+// stateLayout implements evmutil.StateLayout for the evm core contract
+type stateLayout struct {
+	gasFeeTokenID *iotago.NativeTokenID
+}
+
+var _ evmutil.StateLayout = &stateLayout{}
+
+// NewStateLayout returns the layout of the Ethereum accounts in the ISC
+// state. The balance of an account is the L2 balance of the token used to pay
+// for gas (the base token if gasFeeTokenID is nil).
+func NewStateLayout(gasFeeTokenID *iotago.NativeTokenID) evmutil.StateLayout {
+	return &stateLayout{gasFeeTokenID: gasFeeTokenID}
+}
+
+func evmStateKey(key kv.Key) []byte {
+	return evm.Contract.FullKey([]byte(keyEVMState + key))
+}
+
+func (l *stateLayout) NonceKey(addr common.Address) []byte {
+	return evmStateKey(emulator.NonceKey(addr))
+}
+
+func (l *stateLayout) CodeKey(addr common.Address) []byte {
+	return evmStateKey(emulator.CodeKey(addr))
+}
+
+func (l *stateLayout) StorageKey(addr common.Address, slot common.Hash) []byte {
+	return evmStateKey(emulator.StorageKey(addr, slot))
+}
+
+func (l *stateLayout) BalanceKey(addr common.Address) []byte {
+	agentID := isc.NewEthereumAddressAgentID(addr)
+	if l.gasFeeTokenID != nil {
+		return accounts.Contract.FullKey([]byte(accounts.NativeTokenBalanceKey(agentID, l.gasFeeTokenID)))
+	}
+	return accounts.Contract.FullKey([]byte(accounts.BaseTokensBalanceKey(agentID)))
+}
+
+func (l *stateLayout) DecodeNonce(b []byte) (uint64, error) {
+	return codec.DecodeUint64(b)
+}
+
+func (l *stateLayout) DecodeBalance(b []byte) (*big.Int, error) {
+	if l.gasFeeTokenID != nil {
+		return new(big.Int).SetBytes(b), nil
+	}
+	n, err := codec.DecodeUint64(b)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(n), nil
+}
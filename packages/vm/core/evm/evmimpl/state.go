@@ -4,6 +4,7 @@
 package evmimpl
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/wasp/packages/evm/evmtypes"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv"
@@ -12,6 +13,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/emulator"
 )
 
 const (
@@ -49,3 +51,36 @@ func getGasRatio(ctx isc.SandboxView) dict.Dict {
 func GetGasRatio(state kv.KVStoreReader) util.Ratio32 {
 	return codec.MustDecodeRatio32(state.MustGet(keyGasRatio), evmtypes.DefaultGasRatio)
 }
+
+// setLondonBlock schedules the activation of the London hard fork at the EVM block given in FieldBlockNumber,
+// which must come after the pending block. Once activated, the hard fork can't be rescheduled
+func setLondonBlock(ctx isc.Sandbox) dict.Dict {
+	ctx.RequireCallerIsChainOwner()
+	blockNumber := ctx.Params().MustGetUint64(evm.FieldBlockNumber)
+	bc := createEmulator(ctx).BlockchainDB()
+	pending := bc.GetPendingBlockNumber()
+	if londonBlock := bc.GetLondonBlock(); londonBlock != nil {
+		ctx.Requiref(londonBlock.Uint64() > pending, "the London hard fork is already active since block %d", londonBlock)
+	}
+	ctx.Requiref(blockNumber > pending, "the London hard fork must be activated after the pending block %d", pending)
+	bc.SetLondonBlock(blockNumber)
+	return nil
+}
+
+func getLondonBlock(ctx isc.SandboxView) dict.Dict {
+	londonBlock := createEmulatorR(ctx).BlockchainDB().GetLondonBlock()
+	if londonBlock == nil {
+		return nil
+	}
+	return result(codec.EncodeUint64(londonBlock.Uint64()))
+}
+
+// GetNonce returns the nonce of the Ethereum account, given the state of the evm contract
+func GetNonce(state kv.KVStoreReader, addr common.Address) uint64 {
+	return emulator.GetNonce(subrealm.NewReadOnly(state, keyEVMState), addr)
+}
+
+// GetBlockchainDB returns read access to the EVM blocks, given the state of the evm contract
+func GetBlockchainDB(state kv.KVStoreReader) *emulator.BlockchainDB {
+	return emulator.GetBlockchainDB(subrealm.NewReadOnly(state, keyEVMState))
+}
@@ -27,8 +27,10 @@ const (
 	FuncGetChainID                          = "getChainID"
 
 	// evm SC management
-	FuncSetGasRatio = "setGasRatio"
-	FuncGetGasRatio = "getGasRatio"
+	FuncSetGasRatio    = "setGasRatio"
+	FuncGetGasRatio    = "getGasRatio"
+	FuncSetLondonBlock = "setLondonBlock"
+	FuncGetLondonBlock = "getLondonBlock"
 
 	// block context
 	FuncOpenBlockContext  = "openBlockContext"
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/tpkg"
 	"github.com/iotaledger/wasp/contracts/native/inccounter"
 	"github.com/iotaledger/wasp/packages/evm/evmtest"
@@ -136,6 +137,35 @@ func TestGasRatio(t *testing.T) {
 	require.Greater(t, res.iscReceipt.GasFeeCharged, initialGasFee)
 }
 
+func TestLondonBlock(t *testing.T) {
+	env := initEVM(t)
+	require.Nil(t, env.getLondonBlock())
+
+	// only the owner can schedule the hard fork
+	newUserWallet, _ := env.solo.NewKeyPairWithFunds()
+	err := env.setLondonBlock(env.getBlockNumber()+10, iscCallOptions{wallet: newUserWallet})
+	require.True(t, isc.VMErrorIs(err, vm.ErrUnauthorized))
+	require.Nil(t, env.getLondonBlock())
+
+	// the hard fork can't be activated in the past
+	err = env.setLondonBlock(env.getBlockNumber(), iscCallOptions{wallet: env.soloChain.OriginatorPrivateKey})
+	require.ErrorContains(t, err, "must be activated after the pending block")
+	require.Nil(t, env.getLondonBlock())
+
+	// the scheduled block can be changed until the hard fork is active
+	err = env.setLondonBlock(env.getBlockNumber()+10, iscCallOptions{wallet: env.soloChain.OriginatorPrivateKey})
+	require.NoError(t, err)
+	londonBlock := env.getBlockNumber() + 2
+	err = env.setLondonBlock(londonBlock, iscCallOptions{wallet: env.soloChain.OriginatorPrivateKey})
+	require.NoError(t, err)
+	require.EqualValues(t, londonBlock, *env.getLondonBlock())
+
+	// once active, the hard fork can't be rescheduled
+	err = env.setLondonBlock(env.getBlockNumber()+10, iscCallOptions{wallet: env.soloChain.OriginatorPrivateKey})
+	require.ErrorContains(t, err, "already active")
+	require.EqualValues(t, londonBlock, *env.getLondonBlock())
+}
+
 // tests that the gas limits are correctly enforced based on the base tokens sent
 func TestGasLimit(t *testing.T) {
 	env := initEVM(t)
@@ -305,6 +335,53 @@ func TestISCNFTData(t *testing.T) {
 	require.EqualValues(t, metadata, ret.MustUnwrap().Metadata)
 }
 
+func TestISCMintNFT(t *testing.T) {
+	env := initEVM(t)
+	ethKey, ethAddr := env.soloChain.NewEthereumAccountWithL2Funds()
+	ethAgentID := isc.NewEthereumAddressAgentID(ethAddr)
+	getNFTData := func(id iotago.NFTID) *isc.NFT {
+		ret := new(iscmagic.ISCNFT)
+		env.MagicContract(ethKey).callView("getNFTData", []interface{}{iscmagic.WrapNFTID(id)}, &ret)
+		return ret.MustUnwrap()
+	}
+
+	// only the chain owner can mint an NFT without a collection
+	_, err := env.MagicContract(ethKey).callFn(nil, "mintNFT", []byte("foo"), iscmagic.NFTID{})
+	require.Error(t, err)
+	require.Empty(t, env.soloChain.L2NFTs(ethAgentID))
+
+	// mint an NFT of a collection owned by the caller
+	issuerWallet, issuerAddress := env.solo.NewKeyPairWithFunds()
+	collection, _, err := env.solo.MintNFTL1(issuerWallet, issuerAddress, []byte("collection"))
+	require.NoError(t, err)
+	_, err = env.soloChain.PostRequestSync(
+		solo.NewCallParams(accounts.Contract.Name, accounts.FuncTransferAllowanceTo.Name,
+			accounts.ParamAgentID, ethAgentID,
+		).
+			AddBaseTokens(100000).
+			WithNFT(collection).
+			WithAllowance(isc.NewAllowance(0, nil, []iotago.NFTID{collection.ID})).
+			WithMaxAffordableGasBudget().
+			WithSender(collection.ID.ToAddress()),
+		issuerWallet,
+	)
+	require.NoError(t, err)
+
+	_, err = env.MagicContract(ethKey).callFn(nil, "mintNFT", []byte("bar"), iscmagic.WrapNFTID(collection.ID))
+	require.NoError(t, err)
+	nfts := env.soloChain.L2NFTs(ethAgentID)
+	require.Len(t, nfts, 2)
+	found := false
+	for _, id := range nfts {
+		nft := getNFTData(id)
+		if string(nft.Metadata) == "bar" {
+			require.True(t, collection.ID.ToAddress().Equal(nft.Issuer))
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
 func TestISCTriggerEvent(t *testing.T) {
 	env := initEVM(t)
 	ethKey, _ := env.soloChain.NewEthereumAccountWithL2Funds()
@@ -200,6 +200,22 @@ func (e *soloChainEnv) setGasRatio(newGasRatio util.Ratio32, opts ...iscCallOpti
 	return err
 }
 
+func (e *soloChainEnv) getLondonBlock() *uint64 {
+	ret, err := e.callView(evm.FuncGetLondonBlock.Name)
+	require.NoError(e.t, err)
+	if !ret.MustHas(evm.FieldResult) {
+		return nil
+	}
+	n, err := codec.DecodeUint64(ret.MustGet(evm.FieldResult))
+	require.NoError(e.t, err)
+	return &n
+}
+
+func (e *soloChainEnv) setLondonBlock(blockNumber uint64, opts ...iscCallOptions) error {
+	_, err := e.postRequest(opts, evm.FuncSetLondonBlock.Name, evm.FieldBlockNumber, blockNumber)
+	return err
+}
+
 func (e *soloChainEnv) getBalance(addr common.Address) *big.Int {
 	bal, err := e.evmChain.Balance(addr, latestBlock)
 	require.NoError(e.t, err)
@@ -34,8 +34,10 @@ var (
 	FuncGetChainID                          = coreutil.ViewFunc(evmnames.FuncGetChainID)
 
 	// evm SC management
-	FuncSetGasRatio = coreutil.Func(evmnames.FuncSetGasRatio)
-	FuncGetGasRatio = coreutil.ViewFunc(evmnames.FuncGetGasRatio)
+	FuncSetGasRatio    = coreutil.Func(evmnames.FuncSetGasRatio)
+	FuncGetGasRatio    = coreutil.ViewFunc(evmnames.FuncGetGasRatio)
+	FuncSetLondonBlock = coreutil.Func(evmnames.FuncSetLondonBlock)
+	FuncGetLondonBlock = coreutil.ViewFunc(evmnames.FuncGetLondonBlock)
 
 	// block context
 	FuncOpenBlockContext  = coreutil.Func(evmnames.FuncOpenBlockContext)
@@ -46,6 +46,13 @@ interface ISC {
     // The specified `allowance` must not be greater than `fungibleTokens`.
     function sendAsNFT(L1Address memory targetAddress, ISCFungibleTokens memory fungibleTokens, NFTID id, bool adjustMinimumStorageDeposit, ISCSendMetadata memory metadata, ISCSendOptions memory sendOptions) external;
 
+    // Mint an NFT on L2 with the given immutable metadata and credit it to the caller's L2 account.
+    // If `collectionID` is not zero, the NFT belongs to the collection: the collection
+    // NFT, owned by the caller, is the issuer of the new NFT. Otherwise the chain is the issuer,
+    // and only the chain owner can mint the NFT.
+    // The NFT is minted on L1, with a new NFTID, only when it is sent out of the chain.
+    function mintNFT(bytes memory metadata, NFTID collectionID) external returns (NFTID);
+
     // Register a custom ISC error message
     //
     // Usage example:
@@ -21,10 +21,15 @@ import (
 type NodeOwnershipCertificate []byte
 
 func NewNodeOwnershipCertificate(nodeKeyPair *cryptolib.KeyPair, ownerAddress iotago.Address) NodeOwnershipCertificate {
+	return nodeKeyPair.GetPrivateKey().Sign(NodeOwnershipCertificateData(nodeKeyPair.GetPublicKey(), ownerAddress))
+}
+
+// NodeOwnershipCertificateData returns the data signed by the node in the certificate.
+func NodeOwnershipCertificateData(nodePubKey *cryptolib.PublicKey, ownerAddress iotago.Address) []byte {
 	certData := bytes.Buffer{}
-	certData.Write(nodeKeyPair.GetPublicKey().AsBytes())
+	certData.Write(nodePubKey.AsBytes())
 	certData.Write(isc.BytesFromAddress(ownerAddress))
-	return nodeKeyPair.GetPrivateKey().Sign(certData.Bytes())
+	return certData.Bytes()
 }
 
 func NewNodeOwnershipCertificateFromBytes(data []byte) NodeOwnershipCertificate {
@@ -32,10 +37,7 @@ func NewNodeOwnershipCertificateFromBytes(data []byte) NodeOwnershipCertificate
 }
 
 func (c NodeOwnershipCertificate) Verify(nodePubKey *cryptolib.PublicKey, ownerAddress iotago.Address) bool {
-	certData := bytes.Buffer{}
-	certData.Write(nodePubKey.AsBytes())
-	certData.Write(isc.BytesFromAddress(ownerAddress))
-	return nodePubKey.Verify(certData.Bytes(), c.Bytes())
+	return nodePubKey.Verify(NodeOwnershipCertificateData(nodePubKey, ownerAddress), c.Bytes())
 }
 
 func (c NodeOwnershipCertificate) Bytes() []byte {
@@ -0,0 +1,39 @@
+package governanceimpl
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// Gas sponsors are the accounts allowed to pay the gas fees of the off-ledger requests signed by other accounts.
+// The sponsorship of a request by an account which is not allowed is ignored, the gas fee is charged to the sender
+
+func addGasSponsor(ctx isc.Sandbox) dict.Dict {
+	ctx.RequireCallerIsChainOwner()
+	sponsor := ctx.Params().MustGetAgentID(governance.ParamGasSponsor)
+	collections.NewMap(ctx.State(), governance.VarGasSponsors).MustSetAt(sponsor.Bytes(), []byte{0xFF})
+	return nil
+}
+
+func removeGasSponsor(ctx isc.Sandbox) dict.Dict {
+	ctx.RequireCallerIsChainOwner()
+	sponsor := ctx.Params().MustGetAgentID(governance.ParamGasSponsor)
+	collections.NewMap(ctx.State(), governance.VarGasSponsors).MustDelAt(sponsor.Bytes())
+	return nil
+}
+
+func getGasSponsors(ctx isc.SandboxView) dict.Dict {
+	sponsors := collections.NewMapReadOnly(ctx.StateR(), governance.VarGasSponsors)
+	if sponsors.MustLen() == 0 {
+		return nil
+	}
+	ret := dict.New()
+	retArr := collections.NewArray16(ret, governance.ParamGasSponsors)
+	sponsors.MustIterateKeys(func(elemKey []byte) bool {
+		retArr.MustPush(elemKey)
+		return true
+	})
+	return ret
+}
@@ -42,6 +42,11 @@ var Processor = governance.Contract.Processor(initialize,
 	governance.FuncStartMaintenance.WithHandler(setMaintenanceOn),
 	governance.FuncStopMaintenance.WithHandler(setMaintenanceOff),
 	governance.ViewGetMaintenanceStatus.WithHandler(getMaintenanceStatus),
+
+	// gas sponsors
+	governance.FuncAddGasSponsor.WithHandler(addGasSponsor),
+	governance.FuncRemoveGasSponsor.WithHandler(removeGasSponsor),
+	governance.ViewGetGasSponsors.WithHandler(getGasSponsors),
 )
 
 func initialize(ctx isc.Sandbox) dict.Dict {
@@ -51,6 +51,11 @@ var (
 	FuncStartMaintenance     = coreutil.Func("startMaintenance")
 	FuncStopMaintenance      = coreutil.Func("stopMaintenance")
 	ViewGetMaintenanceStatus = coreutil.ViewFunc("getMaintenanceStatus")
+
+	// gas sponsors
+	FuncAddGasSponsor    = coreutil.Func("addGasSponsor")
+	FuncRemoveGasSponsor = coreutil.Func("removeGasSponsor")
+	ViewGetGasSponsors   = coreutil.ViewFunc("getGasSponsors")
 )
 
 // state variables
@@ -79,6 +84,9 @@ const (
 
 	// maintenance
 	VarMaintenanceStatus = "m"
+
+	// gas sponsors
+	VarGasSponsors = "gs"
 )
 
 // params
@@ -111,4 +119,8 @@ const (
 
 	// access nodes: changeAccessNodes
 	ParamChangeAccessNodesActions = "n"
+
+	// gas sponsors
+	ParamGasSponsor  = "gs"
+	ParamGasSponsors = "gl"
 )
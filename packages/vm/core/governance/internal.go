@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
 	"github.com/iotaledger/wasp/packages/vm/gas"
 )
@@ -80,3 +81,8 @@ func GetGasFeePolicy(state kv.KVStoreReader) (*gas.GasFeePolicy, error) {
 func MustGetGasFeePolicy(state kv.KVStoreReader) *gas.GasFeePolicy {
 	return gas.MustGasFeePolicyFromBytes(state.MustGet(VarGasFeePolicyBytes))
 }
+
+// IsGasSponsorAllowed returns true if the chain owner allows the account to sponsor the gas fees of the requests
+func IsGasSponsorAllowed(state kv.KVStoreReader, agentID isc.AgentID) bool {
+	return collections.NewMapReadOnly(state, VarGasSponsors).MustHasAt(agentID.Bytes())
+}
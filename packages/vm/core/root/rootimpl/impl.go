@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
@@ -23,6 +24,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 )
 
 var Processor = root.Contract.Processor(initialize,
@@ -98,6 +100,9 @@ func initialize(ctx isc.Sandbox) dict.Dict {
 	ctx.RequireNoError(err)
 	storeAndInitCoreContract(ctx, evm.Contract, evmParams)
 
+	// store 'scheduler' into the registry and run init
+	storeAndInitCoreContract(ctx, scheduler.Contract, nil)
+
 	state.Set(root.StateVarDeployPermissionsEnabled, codec.EncodeBool(true))
 	state.Set(root.StateVarStateInitialized, []byte{0xFF})
 	// storing hname as a terminal value of the contract's state root.
@@ -203,14 +208,14 @@ func findContract(ctx isc.SandboxView) dict.Dict {
 
 func getContractRecords(ctx isc.SandboxView) dict.Dict {
 	src := root.GetContractRegistryR(ctx.StateR())
+	cursor, limit := coreutil.GetPageParams(ctx)
 
 	ret := dict.New()
 	dst := collections.NewMap(ret, root.StateVarContractRegistry)
-	src.MustIterate(func(elemKey []byte, value []byte) bool {
+	next := src.MustIteratePage(cursor, limit, nil, func(elemKey []byte, value []byte) {
 		dst.MustSetAt(elemKey, value)
-		return true
 	})
-
+	coreutil.SetNextPageCursor(ret, next)
 	return ret
 }
 
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+)
+
+var Processor = Contract.Processor(initialize,
+	FuncScheduleCall.WithHandler(scheduleCall),
+	FuncCancelCall.WithHandler(cancelCall),
+	ViewGetScheduledCall.WithHandler(getScheduledCall),
+	ViewGetScheduledCalls.WithHandler(getScheduledCalls),
+)
+
+func initialize(ctx isc.Sandbox) dict.Dict {
+	// storing hname as a terminal value of the contract's state root.
+	// This way we will be able to retrieve commitment to the contract's state
+	ctx.State().Set("", ctx.Contract().Bytes())
+	return nil
+}
+
+// scheduleCall registers a call to be run by the VM at the start of a future block.
+// The call is made on behalf of the caller, so a contract can schedule a call to itself.
+// The allowance is taken as the prepayment for the gas of the call. It must cover the fee
+// for the gas budget under the current fee policy. What is left after the call, remains
+// in the caller's account.
+// Input:
+// - ParamTargetContract Hname
+// - ParamTargetEntryPoint Hname
+// - ParamCallParams dict.Dict, encoded. Optional
+// - ParamBlockIndex uint32: the call is due at this block. Either it or ParamTimestamp must be set
+// - ParamTimestamp time.Time: the call is due at the first block with a timestamp not before it
+// - ParamGasBudget uint64
+// Output:
+// - ParamCallID uint64
+func scheduleCall(ctx isc.Sandbox) dict.Dict {
+	ctx.Log().Debugf("scheduler.scheduleCall.begin")
+	params := ctx.Params()
+	call := &ScheduledCall{
+		Registrant: ctx.Caller(),
+		Target: isc.CallTarget{
+			Contract:   params.MustGetHname(ParamTargetContract),
+			EntryPoint: params.MustGetHname(ParamTargetEntryPoint),
+		},
+		AtBlockIndex: params.MustGetUint32(ParamBlockIndex, 0),
+		AtTime:       params.MustGetTime(ParamTimestamp, time.Time{}),
+		GasBudget:    params.MustGetUint64(ParamGasBudget),
+	}
+	var err error
+	call.Params, err = codec.DecodeDict(params.MustGetBytes(ParamCallParams, codec.EncodeDict(dict.New())))
+	ctx.RequireNoError(err, "scheduler.scheduleCall: wrong call params")
+
+	ctx.Requiref((call.AtBlockIndex != 0) != !call.AtTime.IsZero(),
+		"scheduler.scheduleCall: exactly one of the block index or the timestamp must be specified")
+	if call.AtBlockIndex != 0 {
+		ctx.Requiref(call.AtBlockIndex > ctx.StateAnchor().StateIndex+1,
+			"scheduler.scheduleCall: block index %d is not in the future", call.AtBlockIndex)
+	} else {
+		ctx.Requiref(call.AtTime.After(ctx.Timestamp()),
+			"scheduler.scheduleCall: timestamp %v is not in the future", call.AtTime)
+	}
+	ctx.Requiref(call.GasBudget > 0 && call.GasBudget <= gas.MaxGasPerCall,
+		"scheduler.scheduleCall: gas budget must be in the range 1..%d", gas.MaxGasPerCall)
+	ctx.Requiref(scheduledCallsMapR(ctx.StateR()).MustLen() < MaxPendingCalls,
+		"scheduler.scheduleCall: too many pending calls")
+
+	allowance := ctx.AllowanceAvailable()
+	ctx.Requiref(len(allowance.NFTs) == 0, "scheduler.scheduleCall: NFTs can't be prepaid")
+	call.Prepaid = allowance.Assets
+	requireEnoughForGas(ctx, call)
+
+	// the allowance goes to the common account first, as for all core contracts,
+	// then it is set aside in the escrow account until the call is run
+	ctx.TransferAllowedFunds(ctx.AccountID())
+	ctx.Privileged().MustMoveBetweenAccounts(ctx.AccountID(), EscrowAccount(ctx.ChainID()), call.Prepaid, nil)
+
+	state := ctx.State()
+	call.ID = nextCallID(state)
+	saveScheduledCall(state, call)
+
+	ctx.Event(fmt.Sprintf("[scheduler] call %d to %s::%s scheduled by %s",
+		call.ID, call.Target.Contract, call.Target.EntryPoint, call.Registrant))
+	return dict.Dict{ParamCallID: codec.EncodeUint64(call.ID)}
+}
+
+// cancelCall removes the pending call and returns the prepaid tokens to the registrant.
+// Only the registrant of the call can cancel it.
+// Input:
+// - ParamCallID uint64
+func cancelCall(ctx isc.Sandbox) dict.Dict {
+	ctx.Log().Debugf("scheduler.cancelCall.begin")
+	callID := ctx.Params().MustGetUint64(ParamCallID)
+	state := ctx.State()
+	call, err := GetScheduledCall(state, callID)
+	ctx.RequireNoError(err)
+	ctx.Requiref(call != nil, "scheduler.cancelCall: call %d not found", callID)
+	ctx.RequireCaller(call.Registrant)
+
+	ctx.RequireNoError(DeleteScheduledCall(state, callID))
+	ctx.Privileged().MustMoveBetweenAccounts(EscrowAccount(ctx.ChainID()), call.Registrant, call.Prepaid, nil)
+
+	ctx.Event(fmt.Sprintf("[scheduler] call %d cancelled", callID))
+	return nil
+}
+
+// getScheduledCall returns the pending call
+// Input:
+// - ParamCallID uint64
+// Output:
+// - ParamScheduledCall: ScheduledCall, encoded
+func getScheduledCall(ctx isc.SandboxView) dict.Dict {
+	callID := ctx.Params().MustGetUint64(ParamCallID)
+	call, err := GetScheduledCall(ctx.StateR(), callID)
+	ctx.RequireNoError(err)
+	ctx.Requiref(call != nil, "scheduler.getScheduledCall: call %d not found", callID)
+	return dict.Dict{ParamScheduledCall: call.Bytes()}
+}
+
+// getScheduledCalls returns all the pending calls, ordered by ID
+// Output:
+// - ParamScheduledCalls: array16 of encoded ScheduledCall
+func getScheduledCalls(ctx isc.SandboxView) dict.Dict {
+	calls, err := GetScheduledCalls(ctx.StateR())
+	ctx.RequireNoError(err)
+	ret := dict.New()
+	arr := collections.NewArray16(ret, ParamScheduledCalls)
+	for _, call := range calls {
+		arr.MustPush(call.Bytes())
+	}
+	return ret
+}
+
+// requireEnoughForGas checks if the prepaid tokens cover the fee for the gas budget of the call
+func requireEnoughForGas(ctx isc.Sandbox, call *ScheduledCall) {
+	res := ctx.CallView(governance.Contract.Hname(), governance.ViewGetFeePolicy.Hname(), nil)
+	feePolicy, err := gas.FeePolicyFromBytes(res.MustGet(governance.ParamFeePolicyBytes))
+	ctx.RequireNoError(err)
+	toOwner, toValidator := feePolicy.FeeFromGas(call.GasBudget, math.MaxUint64)
+	fee := toOwner + toValidator
+
+	var prepaid uint64
+	if feePolicy.GasFeeTokenID == nil {
+		prepaid = call.Prepaid.BaseTokens
+	} else if amount := isc.FindNativeTokenBalance(call.Prepaid.Tokens, feePolicy.GasFeeTokenID); amount != nil {
+		if amount.IsUint64() {
+			prepaid = amount.Uint64()
+		} else {
+			prepaid = math.MaxUint64
+		}
+	}
+	ctx.Requiref(prepaid >= fee,
+		"scheduler.scheduleCall: prepaid %d tokens are not enough for the gas budget, %d are needed", prepaid, fee)
+}
@@ -0,0 +1,57 @@
+// Package scheduler implements the scheduler core contract. Contracts and users
+// register calls in it to be run later, at a given block index or timestamp. The
+// VM runs the due calls at the start of a block, before the requests of the batch,
+// so timed logic does not need to post timelocked requests through L1.
+package scheduler
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+)
+
+var Contract = coreutil.NewContract(coreutil.CoreContractScheduler, "Scheduler Contract")
+
+const (
+	// MaxPendingCalls is the maximum number of calls, waiting in the scheduler
+	MaxPendingCalls = 1000
+	// MaxCallsPerBlock is the maximum number of due calls the VM runs at the start of a block.
+	// The rest are run in the following blocks
+	MaxCallsPerBlock = 16
+)
+
+var (
+	FuncScheduleCall      = coreutil.Func("scheduleCall")
+	FuncCancelCall        = coreutil.Func("cancelCall")
+	ViewGetScheduledCall  = coreutil.ViewFunc("getScheduledCall")
+	ViewGetScheduledCalls = coreutil.ViewFunc("getScheduledCalls")
+)
+
+const (
+	// parameters
+	ParamTargetContract   = "c"
+	ParamTargetEntryPoint = "e"
+	ParamCallParams       = "p"
+	ParamBlockIndex       = "n"
+	ParamTimestamp        = "t"
+	ParamGasBudget        = "g"
+	ParamCallID           = "i"
+	ParamScheduledCall    = "s"
+	ParamScheduledCalls   = "a"
+)
+
+const (
+	// state variables
+	varCallIDCounter  = "c"
+	varScheduledCalls = "s"
+	// indexes of the pending calls by the block index and by the timestamp, when they are due.
+	// The keys are sorted by the due point, see dueKey
+	varDueByBlockIndex = "b"
+	varDueByTimestamp  = "t"
+)
+
+// EscrowAccount is the L2 account where the scheduler keeps the tokens, prepaid for the
+// pending calls. It is not the common account (where the funds of the core contracts
+// normally go), so the prepaid tokens can't be harvested by the chain owner.
+func EscrowAccount(chainID *isc.ChainID) isc.AgentID {
+	return isc.NewContractAgentID(chainID, Contract.Hname())
+}
@@ -0,0 +1,216 @@
+package scheduler
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"golang.org/x/xerrors"
+)
+
+// ScheduledCall is a call, waiting in the scheduler to become due.
+// It is due at the block with AtBlockIndex, or at the first block with a timestamp
+// not before AtTime, whichever of the two is set.
+type ScheduledCall struct {
+	ID           uint64
+	Registrant   isc.AgentID // The agent, which has scheduled the call. The call is made on its behalf.
+	Target       isc.CallTarget
+	Params       dict.Dict
+	AtBlockIndex uint32
+	AtTime       time.Time
+	GasBudget    uint64
+	Prepaid      *isc.FungibleTokens // Kept in the EscrowAccount until the call is run or cancelled.
+}
+
+func ScheduledCallFromBytes(data []byte) (*ScheduledCall, error) {
+	return ScheduledCallFromMarshalUtil(marshalutil.New(data))
+}
+
+func ScheduledCallFromMarshalUtil(mu *marshalutil.MarshalUtil) (*ScheduledCall, error) {
+	ret := &ScheduledCall{}
+	var err error
+	if ret.ID, err = mu.ReadUint64(); err != nil {
+		return nil, err
+	}
+	if ret.Registrant, err = isc.AgentIDFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Target.Contract, err = isc.HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Target.EntryPoint, err = isc.HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Params, err = dict.FromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.AtBlockIndex, err = mu.ReadUint32(); err != nil {
+		return nil, err
+	}
+	if ret.AtTime, err = mu.ReadTime(); err != nil {
+		return nil, err
+	}
+	if ret.GasBudget, err = mu.ReadUint64(); err != nil {
+		return nil, err
+	}
+	if ret.Prepaid, err = isc.FungibleTokensFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c *ScheduledCall) Bytes() []byte {
+	mu := marshalutil.New().
+		WriteUint64(c.ID).
+		Write(c.Registrant).
+		Write(c.Target.Contract).
+		Write(c.Target.EntryPoint).
+		Write(c.Params).
+		WriteUint32(c.AtBlockIndex).
+		WriteTime(c.AtTime).
+		WriteUint64(c.GasBudget)
+	c.Prepaid.WriteToMarshalUtil(mu)
+	return mu.Bytes()
+}
+
+// IsDue returns true, if the call must be run in the block with the specified index and timestamp
+func (c *ScheduledCall) IsDue(blockIndex uint32, timestamp time.Time) bool {
+	if c.AtBlockIndex != 0 {
+		return blockIndex >= c.AtBlockIndex
+	}
+	return !timestamp.Before(c.AtTime)
+}
+
+// Request returns the call as a request, to be run by the VM
+func (c *ScheduledCall) Request(chainID *isc.ChainID) isc.ScheduledCallRequest {
+	return isc.NewScheduledCallRequest(chainID, c.ID, c.Registrant, c.Target, c.Params, c.GasBudget, c.Prepaid)
+}
+
+func scheduledCallsMap(state kv.KVStore) *collections.Map {
+	return collections.NewMap(state, varScheduledCalls)
+}
+
+func scheduledCallsMapR(state kv.KVStoreReader) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, varScheduledCalls)
+}
+
+func nextCallID(state kv.KVStore) uint64 {
+	id := codec.MustDecodeUint64(state.MustGet(varCallIDCounter), 0) + 1
+	state.Set(varCallIDCounter, codec.EncodeUint64(id))
+	return id
+}
+
+func saveScheduledCall(state kv.KVStore, call *ScheduledCall) {
+	scheduledCallsMap(state).MustSetAt(codec.EncodeUint64(call.ID), call.Bytes())
+	state.Set(call.dueKey(), []byte{})
+}
+
+// dueKey is the key of the call in the due index. It consists of the index prefix,
+// the block index or the timestamp in nanoseconds, and the call ID, all big endian,
+// so the sorted keys are ordered by the due point.
+func (c *ScheduledCall) dueKey() kv.Key {
+	var key []byte
+	if c.AtBlockIndex != 0 {
+		key = make([]byte, 4+8)
+		binary.BigEndian.PutUint32(key, c.AtBlockIndex)
+		binary.BigEndian.PutUint64(key[4:], c.ID)
+		return kv.Key(varDueByBlockIndex) + kv.Key(key)
+	}
+	key = make([]byte, 8+8)
+	binary.BigEndian.PutUint64(key, uint64(c.AtTime.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], c.ID)
+	return kv.Key(varDueByTimestamp) + kv.Key(key)
+}
+
+// DeleteScheduledCall removes the call from the scheduler. The prepaid tokens are left
+// in the escrow account, they must be moved out by the caller.
+func DeleteScheduledCall(state kv.KVStore, callID uint64) error {
+	call, err := GetScheduledCall(state, callID)
+	if err != nil || call == nil {
+		return err
+	}
+	state.Del(call.dueKey())
+	scheduledCallsMap(state).MustDelAt(codec.EncodeUint64(callID))
+	return nil
+}
+
+// GetScheduledCall returns the pending call or nil, if there is no such call
+func GetScheduledCall(state kv.KVStoreReader, callID uint64) (*ScheduledCall, error) {
+	data := scheduledCallsMapR(state).MustGetAt(codec.EncodeUint64(callID))
+	if data == nil {
+		return nil, nil
+	}
+	return ScheduledCallFromBytes(data)
+}
+
+// GetScheduledCalls returns all the pending calls, ordered by ID
+func GetScheduledCalls(state kv.KVStoreReader) ([]*ScheduledCall, error) {
+	var ret []*ScheduledCall
+	var err error
+	scheduledCallsMapR(state).MustIterate(func(_ []byte, value []byte) bool {
+		var call *ScheduledCall
+		if call, err = ScheduledCallFromBytes(value); err != nil {
+			return false
+		}
+		ret = append(ret, call)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// DueCalls returns the calls to be run at the start of the block with the specified index and timestamp.
+// At most MaxCallsPerBlock of them are returned, ordered by ID. The ones due at the earliest block or
+// time are preferred, so the calls postponed from the previous blocks are run first. Only the due calls
+// are decoded, the pending ones are found by the due indexes.
+func DueCalls(state kv.KVStoreReader, blockIndex uint32, timestamp time.Time) ([]*ScheduledCall, error) {
+	dueIDs := make([]uint64, 0, MaxCallsPerBlock)
+	dueIDs = appendDue(state, varDueByBlockIndex, 4, func(dueAt []byte) bool {
+		return binary.BigEndian.Uint32(dueAt) <= blockIndex
+	}, dueIDs)
+	dueIDs = appendDue(state, varDueByTimestamp, 8, func(dueAt []byte) bool {
+		return !time.Unix(0, int64(binary.BigEndian.Uint64(dueAt))).After(timestamp)
+	}, dueIDs)
+	sort.Slice(dueIDs, func(i, j int) bool { return dueIDs[i] < dueIDs[j] })
+	if len(dueIDs) > MaxCallsPerBlock {
+		dueIDs = dueIDs[:MaxCallsPerBlock]
+	}
+	ret := make([]*ScheduledCall, len(dueIDs))
+	for i, id := range dueIDs {
+		call, err := GetScheduledCall(state, id)
+		if err != nil {
+			return nil, err
+		}
+		if call == nil {
+			return nil, xerrors.Errorf("inconsistent due index: call %d not found", id)
+		}
+		ret[i] = call
+	}
+	return ret, nil
+}
+
+// appendDue appends the IDs of at most MaxCallsPerBlock earliest due calls from the index
+func appendDue(state kv.KVStoreReader, index string, dueAtLen int, isDue func(dueAt []byte) bool, ids []uint64) []uint64 {
+	count := 0
+	state.MustIterateKeysSorted(kv.Key(index), func(key kv.Key) bool {
+		k := []byte(key[len(index):])
+		if len(k) != dueAtLen+8 || !isDue(k[:dueAtLen]) {
+			return false
+		}
+		ids = append(ids, binary.BigEndian.Uint64(k[dueAtLen:]))
+		count++
+		return count < MaxCallsPerBlock
+	})
+	return ids
+}
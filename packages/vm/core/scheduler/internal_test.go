@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCall(state dict.Dict, atBlockIndex uint32, atTime time.Time) *ScheduledCall {
+	call := &ScheduledCall{
+		ID:           nextCallID(state),
+		Registrant:   isc.NewRandomAgentID(),
+		Target:       isc.NewCallTarget(isc.Hn("contract"), isc.Hn("func")),
+		Params:       dict.Dict{"a": []byte{1}},
+		AtBlockIndex: atBlockIndex,
+		AtTime:       atTime,
+		GasBudget:    1000,
+		Prepaid:      isc.NewFungibleBaseTokens(100),
+	}
+	saveScheduledCall(state, call)
+	return call
+}
+
+func dueIDs(t *testing.T, state dict.Dict, blockIndex uint32, timestamp time.Time) []uint64 {
+	calls, err := DueCalls(state, blockIndex, timestamp)
+	require.NoError(t, err)
+	ret := make([]uint64, len(calls))
+	for i, call := range calls {
+		ret[i] = call.ID
+	}
+	return ret
+}
+
+func TestScheduledCallBytes(t *testing.T) {
+	call := newTestCall(dict.New(), 0, time.Unix(1000, 0))
+	call2, err := ScheduledCallFromBytes(call.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, call.Bytes(), call2.Bytes())
+	require.True(t, call.AtTime.Equal(call2.AtTime))
+}
+
+func TestDueCalls(t *testing.T) {
+	state := dict.New()
+	now := time.Unix(1000, 0)
+	c1 := newTestCall(state, 10, time.Time{})
+	c2 := newTestCall(state, 0, now.Add(time.Minute))
+	c3 := newTestCall(state, 5, time.Time{})
+	c4 := newTestCall(state, 0, now)
+
+	require.Empty(t, dueIDs(t, state, 4, now.Add(-time.Second)))
+	require.Equal(t, []uint64{c3.ID, c4.ID}, dueIDs(t, state, 5, now))
+	require.Equal(t, []uint64{c1.ID, c2.ID, c3.ID, c4.ID}, dueIDs(t, state, 100, now.Add(time.Hour)))
+
+	require.NoError(t, DeleteScheduledCall(state, c3.ID))
+	require.NoError(t, DeleteScheduledCall(state, c3.ID)) // no such call anymore
+	require.Equal(t, []uint64{c4.ID}, dueIDs(t, state, 5, now))
+	calls, err := GetScheduledCalls(state)
+	require.NoError(t, err)
+	require.Len(t, calls, 3)
+}
+
+func TestDueCallsLimit(t *testing.T) {
+	state := dict.New()
+	// the calls due at the later blocks have the lower IDs
+	for i := 0; i < 2*MaxCallsPerBlock; i++ {
+		newTestCall(state, uint32(100-i), time.Time{})
+	}
+	due := dueIDs(t, state, 100, time.Unix(0, 0))
+	require.Len(t, due, MaxCallsPerBlock)
+	// the calls due the longest are preferred
+	for i, id := range due {
+		require.EqualValues(t, MaxCallsPerBlock+i+1, id)
+	}
+}
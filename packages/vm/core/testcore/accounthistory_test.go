@@ -0,0 +1,123 @@
+package testcore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHistory(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	userKey, userAddr := env.NewKeyPairWithFunds()
+	user := isc.NewAgentID(userAddr)
+	ch.MustDepositBaseTokensToL2(10*isc.Million, userKey)
+
+	// the history is disabled by default
+	_, total := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+	require.Zero(t, total)
+
+	require.Error(t, ch.SetAccountHistoryEnabled(true, userKey))
+	require.NoError(t, ch.SetAccountHistoryEnabled(true, nil))
+
+	t.Run("deposit", func(t *testing.T) {
+		balance := ch.L2BaseTokens(user)
+		ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+		receipt := ch.LastReceipt()
+
+		entries, total := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		require.EqualValues(t, len(entries), total)
+		require.EqualValues(t, accounts.HistoryReasonDeposit, entries[0].Reason)
+		require.Nil(t, entries[0].Counterparty)
+		require.EqualValues(t, isc.Million, entries[0].Delta.Uint64())
+
+		sum, fee := big.NewInt(0), big.NewInt(0)
+		for _, e := range entries {
+			require.EqualValues(t, receipt.BlockIndex, e.BlockIndex)
+			require.EqualValues(t, receipt.RequestIndex, e.RequestIndex)
+			require.EqualValues(t, receipt.DeserializedRequest().ID(), e.RequestID)
+			require.Nil(t, e.NativeTokenID)
+			require.Nil(t, e.NFTID)
+			sum.Add(sum, e.Delta)
+			if e.Reason == accounts.HistoryReasonFee {
+				require.NotNil(t, e.Counterparty)
+				fee.Sub(fee, e.Delta)
+			}
+		}
+		require.EqualValues(t, receipt.GasFeeCharged, fee.Uint64())
+		require.EqualValues(t, ch.L2BaseTokens(user)-balance, sum.Int64())
+	})
+
+	_, targetAddr := env.NewKeyPair()
+	target := isc.NewAgentID(targetAddr)
+
+	t.Run("transfer", func(t *testing.T) {
+		_, err := ch.PostRequestOffLedger(solo.NewCallParams(accounts.Contract.Name, accounts.FuncTransferAllowanceTo.Name,
+			accounts.ParamAgentID, target,
+			accounts.ParamForceOpenAccount, true,
+		).WithAllowance(isc.NewAllowanceBaseTokens(1000)).WithMaxAffordableGasBudget(), userKey)
+		require.NoError(t, err)
+
+		entries, total := ch.GetAccountHistory(target, 0, accounts.MaxAccountHistoryPageSize)
+		require.EqualValues(t, 1, total)
+		require.EqualValues(t, accounts.HistoryReasonTransfer, entries[0].Reason)
+		require.True(t, user.Equals(entries[0].Counterparty))
+		require.EqualValues(t, 1000, entries[0].Delta.Int64())
+
+		entries, _ = ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		found := false
+		for _, e := range entries {
+			if e.Reason == accounts.HistoryReasonTransfer && target.Equals(e.Counterparty) {
+				require.EqualValues(t, -1000, e.Delta.Int64())
+				found = true
+			}
+		}
+		require.True(t, found)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		all, total := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		require.Greater(t, total, uint32(3))
+		page, pageTotal := ch.GetAccountHistory(user, 1, 2)
+		require.Equal(t, total, pageTotal)
+		require.Len(t, page, 2)
+		require.Equal(t, all[1].Bytes(), page[0].Bytes())
+		require.Equal(t, all[2].Bytes(), page[1].Bytes())
+
+		page, _ = ch.GetAccountHistory(user, total, 10)
+		require.Empty(t, page)
+
+		_, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccountHistory.Name,
+			accounts.ParamAgentID, user,
+			accounts.ParamHistoryLimit, uint32(accounts.MaxAccountHistoryPageSize+1),
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		require.Error(t, ch.SetAccountHistoryRetention(2, userKey))
+		require.Error(t, ch.SetAccountHistoryRetention(0, nil))
+		require.NoError(t, ch.SetAccountHistoryRetention(2, nil))
+
+		ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+		entries, total := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		first := ch.GetAccountHistoryFirst(user)
+		require.EqualValues(t, total-2, first)
+		require.Len(t, entries, 2)
+		all, _ := ch.GetAccountHistory(user, first, accounts.MaxAccountHistoryPageSize)
+		require.Equal(t, entries, all)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		require.NoError(t, ch.SetAccountHistoryEnabled(false, nil))
+		_, total := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+		_, after := ch.GetAccountHistory(user, 0, accounts.MaxAccountHistoryPageSize)
+		require.Equal(t, total, after)
+	})
+}
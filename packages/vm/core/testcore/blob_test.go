@@ -6,8 +6,6 @@ import (
 
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
-	"github.com/iotaledger/wasp/packages/kv"
-	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/testutil/testmisc"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
@@ -66,12 +64,11 @@ func TestUploadBlob(t *testing.T) {
 		}
 		ret, err := ch.CallView(blob.Contract.Name, blob.ViewListBlobs.Name)
 		require.NoError(t, err)
-		require.EqualValues(t, howMany, len(ret))
+		sizes, err := blob.DecodeDirectory(ret)
+		require.NoError(t, err)
+		require.EqualValues(t, howMany, len(sizes))
 		for _, h := range hashes {
-			sizeBin := ret.MustGet(kv.Key(h[:]))
-			size, err := codec.DecodeUint32(sizeBin)
-			require.NoError(t, err)
-			require.EqualValues(t, len("dummy data #1"), int(size))
+			require.EqualValues(t, len("dummy data #1"), int(sizes[h]))
 
 			ret, err := ch.CallView(blob.Contract.Name, blob.ViewGetBlobField.Name,
 				blob.ParamHash, h,
@@ -80,7 +77,7 @@ func TestUploadBlob(t *testing.T) {
 			require.NoError(t, err)
 			require.EqualValues(t, 1, len(ret))
 			data := ret.MustGet(blob.ParamBytes)
-			require.EqualValues(t, size, len(data))
+			require.EqualValues(t, sizes[h], len(data))
 		}
 	})
 }
@@ -137,7 +134,9 @@ func TestUploadWasm(t *testing.T) {
 
 		ret, err := ch.CallView(blob.Contract.Name, blob.ViewListBlobs.Name)
 		require.NoError(t, err)
-		require.EqualValues(t, 1, len(ret))
+		sizes, err := blob.DecodeDirectory(ret)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, len(sizes))
 	})
 }
 
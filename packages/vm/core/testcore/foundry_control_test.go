@@ -0,0 +1,109 @@
+package testcore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoundryControl(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	ownerKey, ownerAddr := env.NewKeyPairWithFunds()
+	owner := isc.NewAgentID(ownerAddr)
+	ch.MustDepositBaseTokensToL2(10*isc.Million, ownerKey)
+	delegateKey, delegateAddr := env.NewKeyPairWithFunds()
+	delegate := isc.NewAgentID(delegateAddr)
+	ch.MustDepositBaseTokensToL2(10*isc.Million, delegateKey)
+	newOwnerKey, newOwnerAddr := env.NewKeyPairWithFunds()
+	newOwner := isc.NewAgentID(newOwnerAddr)
+	ch.MustDepositBaseTokensToL2(10*isc.Million, newOwnerKey)
+
+	sn, tokenID, err := ch.NewFoundryParams(1000).WithUser(ownerKey).CreateFoundry()
+	require.NoError(t, err)
+	require.Equal(t, []uint32{sn}, ch.GetAccountFoundries(owner))
+	require.NoError(t, ch.MintTokens(sn, 10, ownerKey))
+
+	t.Run("delegate", func(t *testing.T) {
+		require.Error(t, ch.MintTokens(sn, 10, delegateKey))
+		require.Error(t, ch.DelegateFoundry(sn, delegate, nil, nil, delegateKey))
+
+		require.NoError(t, ch.DelegateFoundry(sn, delegate, big.NewInt(50), big.NewInt(5), ownerKey))
+		require.NoError(t, ch.MintTokens(sn, 30, delegateKey))
+		ch.AssertL2NativeTokens(delegate, &tokenID, 30)
+		require.Error(t, ch.MintTokens(sn, 30, delegateKey))
+		require.NoError(t, ch.MintTokens(sn, 20, delegateKey))
+
+		require.NoError(t, ch.DestroyTokensOnL2(tokenID, 5, delegateKey))
+		require.Error(t, ch.DestroyTokensOnL2(tokenID, 1, delegateKey))
+		ch.AssertL2NativeTokens(delegate, &tokenID, 45)
+
+		_, delegations := ch.GetFoundryDelegations(sn)
+		require.Len(t, delegations, 1)
+		d := delegations[delegate.String()]
+		require.EqualValues(t, 50, d.Minted.Int64())
+		require.EqualValues(t, 5, d.Burned.Int64())
+
+		// the owner is not limited by the delegation
+		require.NoError(t, ch.MintTokens(sn, 100, ownerKey))
+	})
+
+	t.Run("cap bounds", func(t *testing.T) {
+		tooBig := new(big.Int).Lsh(big.NewInt(1), 256)
+		require.ErrorContains(t, ch.DelegateFoundry(sn, delegate, tooBig, nil, ownerKey), "mint cap exceeds")
+		require.ErrorContains(t, ch.DelegateFoundry(sn, delegate, nil, new(big.Int).Lsh(big.NewInt(1), 2048), ownerKey), "burn cap exceeds")
+
+		maxCap := new(big.Int).Sub(tooBig, big.NewInt(1))
+		require.NoError(t, ch.DelegateFoundry(sn, delegate, maxCap, maxCap, ownerKey))
+		_, delegations := ch.GetFoundryDelegations(sn)
+		require.Zero(t, maxCap.Cmp(delegations[delegate.String()].MintCap))
+		require.Zero(t, maxCap.Cmp(delegations[delegate.String()].BurnCap))
+		require.NoError(t, ch.RevokeFoundryDelegation(sn, delegate, ownerKey))
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		require.NoError(t, ch.DelegateFoundry(sn, delegate, nil, nil, ownerKey))
+		require.NoError(t, ch.MintTokens(sn, 100, delegateKey))
+		require.NoError(t, ch.RevokeFoundryDelegation(sn, delegate, ownerKey))
+		require.Error(t, ch.MintTokens(sn, 1, delegateKey))
+		require.Error(t, ch.RevokeFoundryDelegation(sn, delegate, ownerKey))
+	})
+
+	t.Run("grant and claim", func(t *testing.T) {
+		require.NoError(t, ch.DelegateFoundry(sn, delegate, nil, nil, ownerKey))
+		require.Error(t, ch.GrantFoundry(sn, newOwner, newOwnerKey))
+
+		require.NoError(t, ch.GrantFoundry(sn, newOwner, ownerKey))
+		grantee, _ := ch.GetFoundryDelegations(sn)
+		require.True(t, newOwner.Equals(grantee))
+
+		// only the grantee can claim the foundry
+		require.ErrorContains(t, ch.ClaimFoundry(sn, delegateKey), "not granted to the caller")
+		// the owner keeps control until the foundry is claimed
+		require.NoError(t, ch.MintTokens(sn, 1, ownerKey))
+
+		require.NoError(t, ch.ClaimFoundry(sn, newOwnerKey))
+		require.Empty(t, ch.GetAccountFoundries(owner))
+		require.Equal(t, []uint32{sn}, ch.GetAccountFoundries(newOwner))
+
+		grantee, delegations := ch.GetFoundryDelegations(sn)
+		require.Nil(t, grantee)
+		require.Empty(t, delegations)
+		require.Error(t, ch.MintTokens(sn, 1, ownerKey))
+		require.Error(t, ch.MintTokens(sn, 1, delegateKey))
+		require.NoError(t, ch.MintTokens(sn, 1, newOwnerKey))
+		require.Error(t, ch.ClaimFoundry(sn, newOwnerKey))
+	})
+
+	t.Run("cancel grant", func(t *testing.T) {
+		require.NoError(t, ch.GrantFoundry(sn, owner, newOwnerKey))
+		require.NoError(t, ch.GrantFoundry(sn, newOwner, newOwnerKey))
+		grantee, _ := ch.GetFoundryDelegations(sn)
+		require.Nil(t, grantee)
+		require.Error(t, ch.ClaimFoundry(sn, ownerKey))
+	})
+}
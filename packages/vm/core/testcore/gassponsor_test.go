@@ -0,0 +1,96 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/stretchr/testify/require"
+)
+
+func lastReceipt(ch *solo.Chain) *blocklog.RequestReceipt {
+	receipts := ch.GetRequestReceiptsForBlock()
+	return receipts[len(receipts)-1]
+}
+
+func TestGasSponsorsList(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	require.Empty(t, ch.GetGasSponsors())
+
+	_, addr1 := env.NewKeyPair()
+	_, addr2 := env.NewKeyPair()
+	sponsor1 := isc.NewAgentID(addr1)
+	sponsor2 := isc.NewAgentID(addr2)
+	require.NoError(t, ch.AddGasSponsor(sponsor1, nil))
+	require.NoError(t, ch.AddGasSponsor(sponsor2, nil))
+	require.Len(t, ch.GetGasSponsors(), 2)
+
+	require.NoError(t, ch.RemoveGasSponsor(sponsor1, nil))
+	sponsors := ch.GetGasSponsors()
+	require.Len(t, sponsors, 1)
+	require.True(t, sponsor2.Equals(sponsors[0]))
+
+	// only the chain owner controls the list
+	notOwner, _ := env.NewKeyPairWithFunds()
+	require.Error(t, ch.AddGasSponsor(sponsor1, notOwner))
+	require.Len(t, ch.GetGasSponsors(), 1)
+}
+
+func TestGasSponsorship(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	sponsorKey, _ := env.NewKeyPairWithFunds()
+	ch.MustDepositBaseTokensToL2(10*isc.Million, sponsorKey)
+	sponsor := isc.NewAgentID(sponsorKey.Address())
+
+	// the user has no funds on L2
+	userKey, userAddr := env.NewKeyPairWithFunds()
+	user := isc.NewAgentID(userAddr)
+
+	newRequest := func(maxFee uint64) *solo.CallParams {
+		return solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name).
+			WithMaxAffordableGasBudget().
+			WithSponsor(sponsorKey, maxFee)
+	}
+
+	t.Run("sponsor not allowed", func(t *testing.T) {
+		_, err := ch.PostRequestOffLedger(newRequest(isc.Million), userKey)
+		require.Error(t, err)
+		receipt := lastReceipt(ch)
+		require.True(t, user.Equals(receipt.GasFeePayer))
+		require.Zero(t, receipt.GasFeeCharged)
+	})
+
+	require.NoError(t, ch.AddGasSponsor(sponsor, nil))
+
+	t.Run("sponsor pays", func(t *testing.T) {
+		sponsorBalance := ch.L2BaseTokens(sponsor)
+		_, err := ch.PostRequestOffLedger(newRequest(isc.Million), userKey)
+		require.NoError(t, err)
+		receipt := lastReceipt(ch)
+		require.True(t, sponsor.Equals(receipt.GasFeePayer))
+		require.NotZero(t, receipt.GasFeeCharged)
+		require.EqualValues(t, sponsorBalance-receipt.GasFeeCharged, ch.L2BaseTokens(sponsor))
+		require.Zero(t, ch.L2BaseTokens(user))
+
+		// the receipt records the payer
+		back, err := blocklog.RequestReceiptFromBytes(receipt.Bytes())
+		require.NoError(t, err)
+		require.True(t, sponsor.Equals(back.GasFeePayer))
+	})
+
+	t.Run("capped by the max fee", func(t *testing.T) {
+		sponsorBalance := ch.L2BaseTokens(sponsor)
+		_, err := ch.PostRequestOffLedger(newRequest(1), userKey)
+		require.Error(t, err)
+		receipt := lastReceipt(ch)
+		require.True(t, sponsor.Equals(receipt.GasFeePayer))
+		require.LessOrEqual(t, receipt.GasFeeCharged, uint64(1))
+		require.EqualValues(t, sponsorBalance-receipt.GasFeeCharged, ch.L2BaseTokens(sponsor))
+	})
+}
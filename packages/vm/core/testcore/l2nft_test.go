@@ -0,0 +1,132 @@
+package testcore
+
+import (
+	"testing"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil/testmisc"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func findL1NFTByMetadata(env *solo.Solo, addr iotago.Address, metadata []byte) *iotago.NFTOutput {
+	for _, out := range env.L1NFTs(addr) {
+		if meta := out.ImmutableFeatureSet().MetadataFeature(); meta != nil && string(meta.Data) == string(metadata) {
+			return out
+		}
+	}
+	return nil
+}
+
+func TestMintNFTOnL2(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	userKey, userAddr := env.NewKeyPairWithFunds()
+	user := isc.NewAgentID(userAddr)
+	ch.MustDepositBaseTokensToL2(10*isc.Million, userKey)
+
+	t.Run("chain issuer", func(t *testing.T) {
+		_, err := ch.MintNFTOnL2([]byte{}, nil, nil)
+		require.Error(t, err)
+
+		// only the chain owner can mint an NFT issued by the chain
+		_, err = ch.MintNFTOnL2([]byte("not the owner"), nil, userKey)
+		require.Error(t, err)
+
+		owner := ch.OriginatorAgentID
+		ch.MustDepositBaseTokensToL2(10*isc.Million, nil)
+		metadata := []byte("L2 NFT")
+		nftID, err := ch.MintNFTOnL2(metadata, nil, nil)
+		require.NoError(t, err)
+		require.True(t, ch.HasL2NFT(owner, &nftID))
+		checkChainNFTData(t, ch, &isc.NFT{ID: nftID, Issuer: ch.ChainID.AsAddress(), Metadata: metadata}, owner)
+
+		_, err = ch.PostRequestSync(
+			solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name).
+				WithAllowance(isc.NewAllowance(isc.Million, nil, []iotago.NFTID{nftID})).
+				WithMaxAffordableGasBudget(),
+			nil,
+		)
+		require.NoError(t, err)
+		require.False(t, ch.HasL2NFT(owner, &nftID))
+		_, err = ch.CallView(accounts.Contract.Name, accounts.ViewNFTData.Name, dict.Dict{
+			accounts.ParamNFTID: nftID[:],
+		})
+		require.Error(t, err)
+
+		// the NFT is minted on L1 with a new ID
+		out := findL1NFTByMetadata(env, ch.OriginatorAddress, metadata)
+		require.NotNil(t, out)
+		require.True(t, out.ImmutableFeatureSet().IssuerFeature().Address.Equal(ch.ChainID.AsAddress()))
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		issuerKey, _ := env.NewKeyPairWithFunds()
+		collection, _, err := env.MintNFTL1(issuerKey, userAddr, []byte("collection"))
+		require.NoError(t, err)
+
+		// the collection must be owned by the caller on L2
+		_, err = ch.MintNFTOnL2([]byte("item"), &collection.ID, userKey)
+		require.Error(t, err)
+
+		_, err = ch.PostRequestSync(
+			solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name).
+				WithNFT(collection).
+				AddBaseTokens(isc.Million).
+				WithMaxAffordableGasBudget(),
+			userKey)
+		require.NoError(t, err)
+
+		metadata := []byte("collection item")
+		nftID, err := ch.MintNFTOnL2(metadata, &collection.ID, userKey)
+		require.NoError(t, err)
+		checkChainNFTData(t, ch, &isc.NFT{ID: nftID, Issuer: collection.ID.ToAddress(), Metadata: metadata}, user)
+
+		// an NFT minted on L2 can't be the collection
+		_, err = ch.MintNFTOnL2([]byte("nested"), &nftID, userKey)
+		require.Error(t, err)
+
+		_, err = ch.PostRequestSync(
+			solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name).
+				WithAllowance(isc.NewAllowance(isc.Million, nil, []iotago.NFTID{nftID})).
+				WithMaxAffordableGasBudget(),
+			userKey,
+		)
+		require.NoError(t, err)
+		out := findL1NFTByMetadata(env, userAddr, metadata)
+		require.NotNil(t, out)
+		require.True(t, out.ImmutableFeatureSet().IssuerFeature().Address.Equal(collection.ID.ToAddress()))
+
+		// the collection NFT stays on the chain
+		require.True(t, ch.HasL2NFT(user, &collection.ID))
+		checkChainNFTData(t, ch, collection, user)
+
+		// once the collection NFT has left the chain, the NFTs of the collection can't be minted on L1
+		metadata = []byte("orphan item")
+		orphanID, err := ch.MintNFTOnL2(metadata, &collection.ID, userKey)
+		require.NoError(t, err)
+		_, err = ch.PostRequestSync(
+			solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name).
+				WithAllowance(isc.NewAllowance(isc.Million, nil, []iotago.NFTID{collection.ID})).
+				WithMaxAffordableGasBudget(),
+			userKey,
+		)
+		require.NoError(t, err)
+		require.False(t, ch.HasL2NFT(user, &collection.ID))
+
+		_, err = ch.PostRequestSync(
+			solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name).
+				WithAllowance(isc.NewAllowance(isc.Million, nil, []iotago.NFTID{orphanID})).
+				WithMaxAffordableGasBudget(),
+			userKey,
+		)
+		testmisc.RequireErrorToBe(t, err, vm.ErrIssuerNFTNotOnChain)
+		require.True(t, ch.HasL2NFT(user, &orphanID))
+		require.Nil(t, findL1NFTByMetadata(env, userAddr, metadata))
+	})
+}
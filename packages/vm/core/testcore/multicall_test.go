@@ -0,0 +1,125 @@
+package testcore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiCall(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	userKey, userAddr := env.NewKeyPairWithFunds()
+	ch.MustDepositBaseTokensToL2(10*isc.Million, userKey)
+	user := isc.NewAgentID(userAddr)
+
+	_, addr1 := env.NewKeyPair()
+	_, addr2 := env.NewKeyPair()
+	target1 := isc.NewAgentID(addr1)
+	target2 := isc.NewAgentID(addr2)
+
+	transfer := func(target isc.AgentID, amount uint64) *solo.CallParams {
+		return solo.NewCallParams(accounts.Contract.Name, accounts.FuncTransferAllowanceTo.Name,
+			accounts.ParamAgentID, target,
+			accounts.ParamForceOpenAccount, true,
+		).WithAllowance(isc.NewAllowanceBaseTokens(amount))
+	}
+	storeBlob := func(data string) *solo.CallParams {
+		return solo.NewCallParams(blob.Contract.Name, blob.FuncStoreBlob.Name, "data", data)
+	}
+
+	t.Run("all calls succeed", func(t *testing.T) {
+		userBalance := ch.L2BaseTokens(user)
+		results, err := ch.PostMultiCallRequest([]*solo.CallParams{
+			transfer(target1, 1000).WithMaxAffordableGasBudget(),
+			storeBlob("one"),
+			transfer(target2, 2000),
+		}, userKey)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Empty(t, results[0])
+		blobHash, err := codec.DecodeHashValue(results[1].MustGet(blob.ParamHash))
+		require.NoError(t, err)
+		_, ok := ch.GetBlobInfo(blobHash)
+		require.True(t, ok)
+
+		require.EqualValues(t, 1000, ch.L2BaseTokens(target1))
+		require.EqualValues(t, 2000, ch.L2BaseTokens(target2))
+		receipt := lastReceipt(ch)
+		require.EqualValues(t, userBalance-3000-receipt.GasFeeCharged, ch.L2BaseTokens(user))
+
+		// the receipt records the results of the calls
+		back, err := blocklog.RequestReceiptFromBytes(receipt.Bytes())
+		require.NoError(t, err)
+		require.Len(t, back.CallResults, 3)
+		require.Equal(t, results[1].Bytes(), back.CallResults[1].Bytes())
+	})
+
+	t.Run("failing call reverts all calls", func(t *testing.T) {
+		userBalance := ch.L2BaseTokens(user)
+		results, err := ch.PostMultiCallRequest([]*solo.CallParams{
+			transfer(target1, 1000).WithMaxAffordableGasBudget(),
+			storeBlob("two"),
+			solo.NewCallParams(accounts.Contract.Name, "nonexistent"),
+			transfer(target2, 2000),
+		}, userKey)
+		require.Error(t, err)
+		// the results of the calls before the failing one are kept
+		require.Len(t, results, 2)
+		_, ok := ch.GetBlobInfo(blob.MustGetBlobHash(dict.Dict{"data": []byte("two")}))
+		require.False(t, ok)
+
+		require.EqualValues(t, 1000, ch.L2BaseTokens(target1))
+		require.EqualValues(t, 2000, ch.L2BaseTokens(target2))
+		// the gas fee is charged once, for all the calls
+		receipt := lastReceipt(ch)
+		require.NotZero(t, receipt.GasFeeCharged)
+		require.EqualValues(t, userBalance-receipt.GasFeeCharged, ch.L2BaseTokens(user))
+	})
+
+	t.Run("gas budget is shared", func(t *testing.T) {
+		// big enough to burn more than the minimum gas per request
+		data := strings.Repeat("x", 10_000)
+		_, err := ch.PostRequestOffLedger(storeBlob(data+"three").WithMaxAffordableGasBudget(), userKey)
+		require.NoError(t, err)
+		gasOneCall := lastReceipt(ch).GasBurned
+
+		// the budget of one call is not enough for two
+		_, err = ch.PostMultiCallRequest([]*solo.CallParams{
+			storeBlob(data + "four").WithGasBudget(gasOneCall),
+			storeBlob(data + "five"),
+		}, userKey)
+		require.ErrorContains(t, err, "gas budget exceeded")
+		_, ok := ch.GetBlobInfo(blob.MustGetBlobHash(dict.Dict{"data": []byte(data + "four")}))
+		require.False(t, ok)
+	})
+
+	t.Run("no calls", func(t *testing.T) {
+		_, err := ch.PostMultiCallRequest(nil, userKey)
+		require.ErrorContains(t, err, "wrong number of calls")
+	})
+
+	t.Run("special calls are skipped", func(t *testing.T) {
+		_, err := ch.PostMultiCallRequest([]*solo.CallParams{
+			transfer(target1, 1000).WithMaxAffordableGasBudget(),
+			solo.NewCallParams(governance.Contract.Name, governance.FuncRotateStateController.Name),
+		}, userKey)
+		require.ErrorContains(t, err, "skipped")
+
+		_, err = ch.PostMultiCallRequest([]*solo.CallParams{
+			transfer(target1, 1000).WithMaxAffordableGasBudget(),
+			solo.NewCallParams(blob.Contract.Name, "init"),
+		}, userKey)
+		require.ErrorContains(t, err, "skipped")
+	})
+}
@@ -0,0 +1,95 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisigAccount(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true})
+	ch := env.NewChain()
+
+	userKey, _ := env.NewKeyPairWithFunds()
+	ch.MustDepositBaseTokensToL2(20*isc.Million, userKey)
+
+	k1, k2, k3 := cryptolib.NewKeyPair(), cryptolib.NewKeyPair(), cryptolib.NewKeyPair()
+	policy, err := isc.NewMultisigPolicy(2, k1.GetPublicKey(), k2.GetPublicKey(), k3.GetPublicKey())
+	require.NoError(t, err)
+
+	multisig, err := ch.RegisterMultisigAccount(policy, userKey)
+	require.NoError(t, err)
+	require.True(t, multisig.Equals(isc.NewMultisigAgentID(policy)))
+	require.True(t, policy.Equals(ch.GetMultisigPolicy(multisig)))
+
+	_, err = ch.RegisterMultisigAccount(policy, userKey)
+	require.ErrorContains(t, err, "already registered")
+
+	require.NoError(t, ch.TransferAllowanceTo(isc.NewFungibleBaseTokens(10*isc.Million), multisig, true, userKey))
+
+	_, targetAddr := env.NewKeyPair()
+	target := isc.NewAgentID(targetAddr)
+	transfer := func(amount uint64) *solo.CallParams {
+		return solo.NewCallParams(accounts.Contract.Name, accounts.FuncTransferAllowanceTo.Name,
+			accounts.ParamAgentID, target,
+			accounts.ParamForceOpenAccount, true,
+		).WithAllowance(isc.NewAllowanceBaseTokens(amount)).WithMaxAffordableGasBudget()
+	}
+
+	t.Run("below threshold", func(t *testing.T) {
+		_, err := ch.PostMultisigRequest(transfer(1000), multisig, policy, k1)
+		require.ErrorContains(t, err, "signed by 1 signers, 2 required")
+		// the same signer twice doesn't count
+		_, err = ch.PostMultisigRequest(transfer(1000), multisig, policy, k1, k1)
+		require.Error(t, err)
+		require.Zero(t, ch.L2BaseTokens(target))
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		balance := ch.L2BaseTokens(multisig)
+		_, err := ch.PostMultisigRequest(transfer(1000).WithNonce(1), multisig, policy, k1, k3)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, ch.L2BaseTokens(target))
+		require.EqualValues(t, balance-1000-ch.LastReceipt().GasFeeCharged, ch.L2BaseTokens(multisig))
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		_, l1Addr := env.NewKeyPair()
+		req := solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name,
+			accounts.ParamWithdrawalTarget, l1Addr,
+		).WithAllowance(isc.NewAllowanceBaseTokens(isc.Million)).WithMaxAffordableGasBudget().WithNonce(2)
+		_, err := ch.PostMultisigRequest(req, multisig, policy, k2, k3)
+		require.NoError(t, err)
+		require.EqualValues(t, isc.Million, env.L1BaseTokens(l1Addr))
+	})
+
+	k4 := cryptolib.NewKeyPair()
+	newPolicy, err := isc.NewMultisigPolicy(1, k4.GetPublicKey())
+	require.NoError(t, err)
+
+	t.Run("rotate", func(t *testing.T) {
+		// only the multisig account can rotate its policy
+		_, err := ch.PostRequestOffLedger(solo.NewCallParams(accounts.Contract.Name, accounts.FuncRotateMultisigPolicy.Name,
+			accounts.ParamMultisigPolicy, newPolicy.Bytes(),
+		).WithMaxAffordableGasBudget(), userKey)
+		require.ErrorContains(t, err, "caller must be a multisig account")
+
+		req := solo.NewCallParams(accounts.Contract.Name, accounts.FuncRotateMultisigPolicy.Name,
+			accounts.ParamMultisigPolicy, newPolicy.Bytes(),
+		).WithMaxAffordableGasBudget().WithNonce(3)
+		_, err = ch.PostMultisigRequest(req, multisig, policy, k1, k2)
+		require.NoError(t, err)
+		require.True(t, newPolicy.Equals(ch.GetMultisigPolicy(multisig)))
+
+		// the old signers no longer control the account
+		_, err = ch.PostMultisigRequest(transfer(1000).WithNonce(4), multisig, policy, k1, k2)
+		require.ErrorContains(t, err, "skipped")
+		_, err = ch.PostMultisigRequest(transfer(1000).WithNonce(5), multisig, newPolicy, k4)
+		require.NoError(t, err)
+		require.EqualValues(t, 2000, ch.L2BaseTokens(target))
+	})
+}
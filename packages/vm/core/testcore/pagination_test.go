@@ -0,0 +1,72 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/corecontracts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagination(t *testing.T) {
+	env := solo.New(t)
+	ch := env.NewChain()
+
+	for i := 0; i < 5; i++ {
+		userKey, _ := env.NewKeyPairWithFunds()
+		ch.MustDepositBaseTokensToL2(isc.Million, userKey)
+	}
+
+	t.Run("accounts", func(t *testing.T) {
+		all := ch.L2Accounts()
+		require.Greater(t, len(all), 5)
+
+		var paged []isc.AgentID
+		var cursor []byte
+		pages := 0
+		for {
+			res, err := ch.CallView(accounts.Contract.Name, accounts.ViewAccounts.Name, coreutil.PageParams(nil, cursor, 2))
+			require.NoError(t, err)
+			pages++
+			accs, err := accounts.DecodeAccounts(res)
+			require.NoError(t, err)
+			paged = append(paged, accs...)
+			if cursor = coreutil.NextPageCursor(res); cursor == nil {
+				break
+			}
+			require.Len(t, accs, 2)
+		}
+		require.Equal(t, (len(all)+1)/2, pages)
+		require.ElementsMatch(t, all, paged)
+	})
+
+	t.Run("contract records", func(t *testing.T) {
+		res, err := ch.CallView(root.Contract.Name, root.ViewGetContractRecords.Name, coreutil.PageParams(nil, nil, 1))
+		require.NoError(t, err)
+		recs, err := root.DecodeContractRegistry(collections.NewMapReadOnly(res, root.StateVarContractRegistry))
+		require.NoError(t, err)
+		require.Len(t, recs, 1)
+		require.NotNil(t, coreutil.NextPageCursor(res))
+
+		_, _, contracts := ch.GetInfo()
+		require.Len(t, contracts, len(corecontracts.All))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		_, err := ch.CallView(root.Contract.Name, root.ViewGetContractRecords.Name, dict.Dict{
+			coreutil.ParamPageLimit: codec.EncodeUint32(0),
+		})
+		require.Error(t, err)
+		_, err = ch.CallView(root.Contract.Name, root.ViewGetContractRecords.Name, dict.Dict{
+			coreutil.ParamPageLimit: codec.EncodeUint32(coreutil.MaxPageLimit + 1),
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,196 @@
+package testcore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tickerGasBudget = 100_000
+	tickerPrepaid   = 10_000
+
+	varTicks = "t"
+)
+
+var (
+	tickerContract = coreutil.NewContract("ticker", "schedules calls to itself")
+
+	funcScheduleTick = coreutil.Func("scheduleTick")
+	funcTick         = coreutil.Func("tick")
+	viewGetTicks     = coreutil.ViewFunc("getTicks")
+
+	tickerContractProcessor = tickerContract.Processor(nil,
+		// scheduleTick schedules a call to 'tick' in 2 blocks, prepaid by the allowance of the caller
+		funcScheduleTick.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			ctx.TransferAllowedFunds(ctx.AccountID())
+			return ctx.Call(scheduler.Contract.Hname(), scheduler.FuncScheduleCall.Hname(), dict.Dict{
+				scheduler.ParamTargetContract:   codec.EncodeHname(ctx.Contract()),
+				scheduler.ParamTargetEntryPoint: codec.EncodeHname(funcTick.Hname()),
+				scheduler.ParamBlockIndex:       codec.EncodeUint32(ctx.StateAnchor().StateIndex + 3),
+				scheduler.ParamGasBudget:        codec.EncodeUint64(tickerGasBudget),
+			}, isc.NewAllowanceBaseTokens(tickerPrepaid))
+		}),
+		// tick can only be called by the contract itself, i.e. from the scheduler
+		funcTick.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			ctx.RequireCaller(ctx.AccountID())
+			ticks := codec.MustDecodeUint32(ctx.State().MustGet(varTicks), 0)
+			ctx.State().Set(varTicks, codec.EncodeUint32(ticks+1))
+			return nil
+		}),
+		viewGetTicks.WithHandler(func(ctx isc.SandboxView) dict.Dict {
+			return dict.Dict{varTicks: ctx.StateR().MustGet(varTicks)}
+		}),
+	)
+)
+
+func getTicks(t *testing.T, ch *solo.Chain) uint32 {
+	res, err := ch.CallView(tickerContract.Name, viewGetTicks.Name)
+	require.NoError(t, err)
+	return codec.MustDecodeUint32(res.MustGet(varTicks), 0)
+}
+
+func getScheduledCalls(t *testing.T, ch *solo.Chain) []*scheduler.ScheduledCall {
+	res, err := ch.CallView(scheduler.Contract.Name, scheduler.ViewGetScheduledCalls.Name)
+	require.NoError(t, err)
+	arr := collections.NewArray16ReadOnly(res, scheduler.ParamScheduledCalls)
+	ret := make([]*scheduler.ScheduledCall, arr.MustLen())
+	for i := range ret {
+		ret[i], err = scheduler.ScheduledCallFromBytes(arr.MustGetAt(uint16(i)))
+		require.NoError(t, err)
+	}
+	return ret
+}
+
+func TestSchedulerSelfCall(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true}).
+		WithNativeContract(tickerContractProcessor)
+	ch := env.NewChain()
+	require.NoError(t, ch.DeployContract(nil, tickerContract.Name, tickerContract.ProgramHash))
+	ch.MustDepositBaseTokensToL2(10_000_000, nil)
+
+	_, err := ch.PostRequestSync(
+		solo.NewCallParams(tickerContract.Name, funcScheduleTick.Name).
+			WithAllowance(isc.NewAllowanceBaseTokens(tickerPrepaid)).
+			WithMaxAffordableGasBudget(),
+		nil,
+	)
+	require.NoError(t, err)
+	dueBlockIndex := ch.GetLatestBlockInfo().BlockIndex + 2
+
+	calls := getScheduledCalls(t, ch)
+	require.Len(t, calls, 1)
+	require.EqualValues(t, dueBlockIndex, calls[0].AtBlockIndex)
+	require.True(t, calls[0].Registrant.Equals(isc.NewContractAgentID(ch.ChainID, tickerContract.Hname())))
+	ch.AssertL2BaseTokens(scheduler.EscrowAccount(ch.ChainID), tickerPrepaid)
+
+	// the call is not due in the next block
+	ch.MustDepositBaseTokensToL2(1_000_000, nil)
+	require.Zero(t, getTicks(t, ch))
+
+	// the call is run at the start of the block it is due at, before the request
+	ch.MustDepositBaseTokensToL2(1_000_000, nil)
+	require.EqualValues(t, 1, getTicks(t, ch))
+	require.Empty(t, getScheduledCalls(t, ch))
+	ch.AssertL2BaseTokens(scheduler.EscrowAccount(ch.ChainID), 0)
+
+	receipts := ch.GetRequestReceiptsForBlock(dueBlockIndex)
+	require.Len(t, receipts, 2)
+	require.Nil(t, receipts[0].Error)
+	scheduledReq, ok := receipts[0].Request.(isc.ScheduledCallRequest)
+	require.True(t, ok)
+	require.EqualValues(t, calls[0].ID, scheduledReq.CallID())
+	require.True(t, ch.IsRequestProcessed(scheduledReq.ID()))
+
+	// the gas is paid from the prepaid tokens, the rest goes to the account of the contract
+	require.EqualValues(t,
+		tickerPrepaid-receipts[0].GasFeeCharged,
+		ch.L2BaseTokens(isc.NewContractAgentID(ch.ChainID, tickerContract.Hname())),
+	)
+
+	// it is run only once
+	ch.MustDepositBaseTokensToL2(1_000_000, nil)
+	require.EqualValues(t, 1, getTicks(t, ch))
+	ch.CheckChain()
+}
+
+func TestSchedulerTimestampAndCancel(t *testing.T) {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true}).
+		WithNativeContract(tickerContractProcessor)
+	ch := env.NewChain()
+	require.NoError(t, ch.DeployContract(nil, tickerContract.Name, tickerContract.ProgramHash))
+
+	user, userAddr := env.NewKeyPairWithFunds()
+	userAgentID := isc.NewAgentID(userAddr)
+	ch.MustDepositBaseTokensToL2(10_000_000, user)
+
+	scheduleCall := func(at time.Time, prepaid uint64) (uint64, error) {
+		res, err := ch.PostRequestSync(
+			solo.NewCallParams(scheduler.Contract.Name, scheduler.FuncScheduleCall.Name,
+				scheduler.ParamTargetContract, tickerContract.Hname(),
+				scheduler.ParamTargetEntryPoint, funcTick.Hname(),
+				scheduler.ParamTimestamp, at,
+				scheduler.ParamGasBudget, uint64(tickerGasBudget),
+			).WithAllowance(isc.NewAllowanceBaseTokens(prepaid)).WithMaxAffordableGasBudget(),
+			user,
+		)
+		if err != nil {
+			return 0, err
+		}
+		return codec.MustDecodeUint64(res.MustGet(scheduler.ParamCallID)), nil
+	}
+
+	// the prepaid tokens must cover the gas budget
+	_, err := scheduleCall(env.GlobalTime().Add(time.Hour), 1)
+	require.ErrorContains(t, err, "not enough for the gas budget")
+
+	// the call must be in the future
+	_, err = scheduleCall(env.GlobalTime().Add(-time.Hour), tickerPrepaid)
+	require.ErrorContains(t, err, "not in the future")
+
+	callID1, err := scheduleCall(env.GlobalTime().Add(time.Hour), tickerPrepaid)
+	require.NoError(t, err)
+	callID2, err := scheduleCall(env.GlobalTime().Add(2*time.Hour), tickerPrepaid)
+	require.NoError(t, err)
+	require.Len(t, getScheduledCalls(t, ch), 2)
+	ch.AssertL2BaseTokens(scheduler.EscrowAccount(ch.ChainID), 2*tickerPrepaid)
+
+	// only the registrant can cancel the call
+	_, err = ch.PostRequestSync(
+		solo.NewCallParams(scheduler.Contract.Name, scheduler.FuncCancelCall.Name, scheduler.ParamCallID, callID2).
+			WithMaxAffordableGasBudget(),
+		nil,
+	)
+	require.Error(t, err)
+
+	balanceBefore := ch.L2BaseTokens(userAgentID)
+	_, err = ch.PostRequestOffLedger(
+		solo.NewCallParams(scheduler.Contract.Name, scheduler.FuncCancelCall.Name, scheduler.ParamCallID, callID2).
+			WithMaxAffordableGasBudget(),
+		user,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, balanceBefore+tickerPrepaid-ch.LastReceipt().GasFeeCharged, ch.L2BaseTokens(userAgentID))
+	calls := getScheduledCalls(t, ch)
+	require.Len(t, calls, 1)
+	require.EqualValues(t, callID1, calls[0].ID)
+
+	// the call is made on behalf of the user, 'tick' rejects it
+	env.AdvanceClockBy(time.Hour)
+	ch.MustDepositBaseTokensToL2(1_000_000, nil)
+	require.Empty(t, getScheduledCalls(t, ch))
+	require.Zero(t, getTicks(t, ch))
+	receipts := ch.GetRequestReceiptsForBlock()
+	require.Len(t, receipts, 2)
+	require.NotNil(t, receipts[0].Error)
+	ch.AssertL2BaseTokens(scheduler.EscrowAccount(ch.ChainID), 0)
+	ch.CheckChain()
+}
@@ -32,4 +32,5 @@ var (
 	ErrSenderUnknown                         = coreerrors.Register("sender unknown").Create()
 	ErrGasBudgetDetail                       = coreerrors.Register("%v: burned = %d (budget = %d)")
 	ErrUnauthorized                          = coreerrors.Register("unauthorized access").Create()
+	ErrIssuerNFTNotOnChain                   = coreerrors.Register("the collection NFT %v, the issuer of the NFT, is not owned by the chain")
 )
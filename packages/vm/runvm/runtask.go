@@ -8,13 +8,16 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
 )
 
-type VMRunner struct{}
+type VMRunner struct {
+	// number of workers running the requests speculatively. 0 means the requests are run sequentially
+	workers int
+}
 
 func (r VMRunner) Run(task *vm.VMTask) error {
 	// top exception catcher for all panics
 	// The VM session will be abandoned peacefully
 	err := panicutil.CatchPanic(func() {
-		runTask(task)
+		runTask(task, r.workers)
 	})
 	if err != nil {
 		task.Log.Warnf("GENERAL VM EXCEPTION: the task (ACS id %d) has been abandoned due to: %s", task.ACSSessionID, err.Error())
@@ -26,41 +29,91 @@ func NewVMRunner() vm.VMRunner {
 	return VMRunner{}
 }
 
+// NewParallelVMRunner returns the runner, which runs the requests of the batch speculatively, on
+// the given number of workers in parallel. The outcomes are committed in the batch order, the requests
+// which conflict with the ones before them are run again. The resulting block is the same as the one
+// produced by the sequential runner
+func NewParallelVMRunner(workers int) vm.VMRunner {
+	return VMRunner{workers: workers}
+}
+
 // runTask runs batch of requests on VM
-func runTask(task *vm.VMTask) {
+func runTask(task *vm.VMTask, workers int) {
 	vmctx := vmcontext.CreateVMContext(task)
 
 	var numOffLedger, numSuccess uint16
 	reqIndexInTheBlock := 0
 
-	if task.AnchorOutput.StateIndex > 0 {
-		vmctx.OpenBlockContexts()
+	// countResult counts the result of the request run in the block
+	countResult := func(req isc.Request, result *vm.RequestResult) {
+		reqIndexInTheBlock++
+		if req.IsOffLedger() {
+			numOffLedger++
+		}
+
+		if result.Receipt.Error == nil {
+			numSuccess++
+		} else {
+			task.Log.Debugf("runTask, ERROR running request: %s, error: %v", req.ID().String(), result.Receipt.Error)
+		}
 	}
 
-	// main loop over the batch of requests
-	for _, req := range task.Requests {
+	// runRequest runs the request and returns its result, or nil if the request was skipped
+	runRequest := func(req isc.Request) *vm.RequestResult {
 		result, skipReason := vmctx.RunTheRequest(req, uint16(reqIndexInTheBlock))
 		if skipReason != nil {
 			// some requests are just ignored (deterministically)
 			task.Log.Infof("request skipped (ignored) by the VM: %s, reason: %v",
 				req.ID().String(), skipReason)
-			continue
+			task.SkippedRequests = append(task.SkippedRequests, &vm.SkippedRequest{Request: req, Reason: skipReason})
+			return nil
 		}
-		task.Results = append(task.Results, result)
-		reqIndexInTheBlock++
-		if req.IsOffLedger() {
-			numOffLedger++
+		countResult(req, result)
+		return result
+	}
+
+	if task.AnchorOutput.StateIndex > 0 {
+		vmctx.OpenBlockContexts()
+
+		// the due calls from the scheduler are run at the start of the block.
+		// They are not run when estimating gas, the result must reflect the request only
+		if !task.EstimateGasMode {
+			for _, req := range vmctx.ScheduledCallsDue() {
+				if result := runRequest(req); result != nil {
+					task.ScheduledResults = append(task.ScheduledResults, result)
+					vmctx.AssertConsistentGasTotals()
+				}
+			}
 		}
+	}
 
-		if result.Receipt.Error == nil {
-			numSuccess++
-		} else {
-			task.Log.Debugf("runTask, ERROR running request: %s, error: %v", req.ID().String(), result.Receipt.Error)
+	var speculations []*vmcontext.Speculation
+	if workers > 1 && task.AnchorOutput.StateIndex > 0 && !task.EstimateGasMode {
+		speculations = speculate(vmctx, task.Requests, uint16(reqIndexInTheBlock), workers)
+	}
+	numCommitted := 0
+
+	// main loop over the batch of requests
+	for i, req := range task.Requests {
+		if speculations != nil && speculations[i] != nil {
+			if result, ok := vmctx.CommitSpeculation(speculations[i], uint16(reqIndexInTheBlock)); ok {
+				countResult(req, result)
+				task.Results = append(task.Results, result)
+				vmctx.AssertConsistentGasTotals()
+				numCommitted++
+				continue
+			}
 		}
-		vmctx.AssertConsistentGasTotals()
+		if result := runRequest(req); result != nil {
+			task.Results = append(task.Results, result)
+			vmctx.AssertConsistentGasTotals()
+		}
+	}
+	if speculations != nil {
+		task.Log.Debugf("runTask, committed %d speculative runs out of %d requests", numCommitted, len(task.Requests))
 	}
 
-	numProcessed := uint16(len(task.Results))
+	numProcessed := uint16(len(task.ScheduledResults) + len(task.Results))
 
 	task.Log.Debugf("runTask, ran %d requests. success: %d, offledger: %d",
 		numProcessed, numSuccess, numOffLedger)
@@ -0,0 +1,60 @@
+package runvm
+
+import (
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+)
+
+// speculate runs the requests of the batch speculatively, on the given number of workers. Each request
+// is run as if all the requests before it were run without being skipped, starting at the request index.
+// Returns the speculations by the position of the request in the batch, nil for the requests not run
+func speculate(vmctx *vmcontext.VMContext, reqs []isc.Request, requestIndex uint16, workers int) []*vmcontext.Speculation {
+	ret := make([]*vmcontext.Speculation, len(reqs))
+	// the forks are made one by one, the context can't be accessed concurrently
+	for i, req := range reqs {
+		if canSpeculate(req) {
+			ret[i] = vmctx.Speculate(req, requestIndex+uint16(i), i)
+		}
+	}
+
+	jobs := make(chan *vmcontext.Speculation)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				s.Run()
+			}
+		}()
+	}
+	for _, s := range ret {
+		if s != nil {
+			jobs <- s
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return ret
+}
+
+// canSpeculate returns true if the request can be run on a fork of the context. On-ledger requests
+// consume outputs of the anchor transaction, EVM requests need the block context of the evm contract.
+// Both are shared by the whole block
+func canSpeculate(req isc.Request) bool {
+	if !req.IsOffLedger() {
+		return false
+	}
+	if multi, ok := req.(isc.MultiCallRequest); ok {
+		for _, c := range multi.Calls() {
+			if c.Target.Contract == evm.Contract.Hname() {
+				return false
+			}
+		}
+		return true
+	}
+	return req.CallTarget().Contract != evm.Contract.Hname()
+}
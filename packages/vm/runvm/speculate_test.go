@@ -0,0 +1,219 @@
+package runvm_test
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testGasBudget = 1_000_000
+
+	paramValue  = "v"
+	paramTarget = "t"
+
+	varCounter = "c"
+)
+
+var (
+	testContract = coreutil.NewContract("speculate", "test contract for the parallel runner")
+
+	funcSetValue   = coreutil.Func("setValue")
+	funcIncCounter = coreutil.Func("incCounter")
+	funcEmitEvent  = coreutil.Func("emitEvent")
+	funcTransfer   = coreutil.Func("transfer")
+	funcFail       = coreutil.Func("fail")
+
+	testContractProcessor = testContract.Processor(nil,
+		// setValue stores the value, the timestamp and the entropy under the key of the caller
+		funcSetValue.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			entropy := ctx.GetEntropy()
+			value := append(ctx.Params().MustGetBytes(paramValue), codec.EncodeTime(ctx.Timestamp())...)
+			ctx.State().Set(kv.Key(ctx.Caller().Bytes()), append(value, entropy[:]...))
+			return nil
+		}),
+		funcIncCounter.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			counter := codec.MustDecodeUint64(ctx.State().MustGet(varCounter), 0)
+			ctx.State().Set(varCounter, codec.EncodeUint64(counter+1))
+			return dict.Dict{varCounter: codec.EncodeUint64(counter + 1)}
+		}),
+		funcEmitEvent.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			ctx.Event(string(ctx.Params().MustGetBytes(paramValue)))
+			return nil
+		}),
+		// transfer moves the allowance to the target account
+		funcTransfer.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			ctx.TransferAllowedFunds(ctx.Params().MustGetAgentID(paramTarget))
+			return nil
+		}),
+		funcFail.WithHandler(func(ctx isc.Sandbox) dict.Dict {
+			panic("failed on purpose")
+		}),
+	)
+)
+
+// comparingRunner runs each task both sequentially and with the parallel runner,
+// and requires the outcomes to be the same
+type comparingRunner struct {
+	t        *testing.T
+	parallel vm.VMRunner
+}
+
+func (r *comparingRunner) Run(task *vm.VMTask) error {
+	sequential := *task
+	sequential.VirtualStateAccess = task.VirtualStateAccess.Copy()
+	require.NoError(r.t, runvm.NewVMRunner().Run(&sequential))
+	require.NoError(r.t, r.parallel.Run(task))
+
+	require.True(r.t, state.EqualCommitments(
+		state.RootCommitment(sequential.VirtualStateAccess.TrieNodeStore()),
+		state.RootCommitment(task.VirtualStateAccess.TrieNodeStore()),
+	))
+	require.Equal(r.t, sequential.RotationAddress, task.RotationAddress)
+	if sequential.ResultTransactionEssence != nil {
+		expected, err := sequential.ResultTransactionEssence.Serialize(serializer.DeSeriModeNoValidation, nil)
+		require.NoError(r.t, err)
+		actual, err := task.ResultTransactionEssence.Serialize(serializer.DeSeriModeNoValidation, nil)
+		require.NoError(r.t, err)
+		require.Equal(r.t, expected, actual)
+		require.Equal(r.t, sequential.ResultInputsCommitment, task.ResultInputsCommitment)
+	}
+	requireSameResults(r.t, sequential.ScheduledResults, task.ScheduledResults)
+	requireSameResults(r.t, sequential.Results, task.Results)
+	return nil
+}
+
+func requireSameResults(t *testing.T, expected, actual []*vm.RequestResult) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.Equal(t, expected[i].Receipt.Bytes(), actual[i].Receipt.Bytes())
+		require.Equal(t, expected[i].Return.Bytes(), actual[i].Return.Bytes())
+	}
+}
+
+type testEnv struct {
+	t     *testing.T
+	ch    *solo.Chain
+	users []*cryptolib.KeyPair
+	nonce uint64
+}
+
+func newTestEnv(t *testing.T, numUsers int) *testEnv {
+	env := solo.New(t, &solo.InitOptions{AutoAdjustStorageDeposit: true}).
+		WithNativeContract(testContractProcessor)
+	ch, _, _ := env.NewChainExt(nil, 0, "chain1", solo.InitChainOptions{
+		VMRunner: &comparingRunner{t: t, parallel: runvm.NewParallelVMRunner(4)},
+	})
+	require.NoError(t, ch.DeployContract(nil, testContract.Name, testContract.ProgramHash))
+
+	ret := &testEnv{t: t, ch: ch}
+	for i := 0; i < numUsers; i++ {
+		user, _ := env.NewKeyPairWithFunds()
+		ch.MustDepositBaseTokensToL2(10_000_000, user)
+		ret.users = append(ret.users, user)
+	}
+	return ret
+}
+
+func (e *testEnv) request(user *cryptolib.KeyPair, contract, entryPoint isc.Hname, params dict.Dict, allowance *isc.Allowance) isc.Request {
+	e.nonce++
+	req := isc.NewOffLedgerRequest(e.ch.ChainID, contract, entryPoint, params, e.nonce).
+		WithGasBudget(testGasBudget)
+	if allowance != nil {
+		req = req.WithAllowance(allowance)
+	}
+	return req.Sign(user)
+}
+
+func (e *testEnv) call(user *cryptolib.KeyPair, f coreutil.EntryPointInfo, params dict.Dict, allowance ...*isc.Allowance) isc.Request {
+	var a *isc.Allowance
+	if len(allowance) > 0 {
+		a = allowance[0]
+	}
+	return e.request(user, testContract.Hname(), f.Hname(), params, a)
+}
+
+func (e *testEnv) run(reqs ...isc.Request) []*vm.RequestResult {
+	results := e.ch.RunOffLedgerRequests(reqs)
+	e.ch.CheckChain()
+	return results
+}
+
+func TestParallelIndependentRequests(t *testing.T) {
+	e := newTestEnv(t, 10)
+	var reqs []isc.Request
+	for i, user := range e.users {
+		reqs = append(reqs, e.call(user, funcSetValue, dict.Dict{paramValue: []byte{byte(i)}}))
+	}
+	results := e.run(reqs...)
+	require.Len(t, results, len(reqs))
+	for _, res := range results {
+		require.Nil(t, res.Receipt.Error)
+	}
+}
+
+func TestParallelConflictingRequests(t *testing.T) {
+	e := newTestEnv(t, 5)
+	var reqs []isc.Request
+	for i := 0; i < 3; i++ {
+		for _, user := range e.users {
+			reqs = append(reqs, e.call(user, funcIncCounter, nil))
+		}
+	}
+	results := e.run(reqs...)
+	require.Len(t, results, len(reqs))
+	for i, res := range results {
+		require.Nil(t, res.Receipt.Error)
+		require.EqualValues(t, i+1, codec.MustDecodeUint64(res.Return.MustGet(varCounter)))
+	}
+}
+
+func TestParallelMixedRequests(t *testing.T) {
+	e := newTestEnv(t, 6)
+	u := e.users
+	target := isc.NewAgentID(u[1].Address())
+
+	// a request with a nonce too old for u[5] is skipped
+	e.nonce = 2 * vmcontext.OffLedgerNonceStrictOrderTolerance
+	e.run(e.call(u[5], funcSetValue, dict.Dict{paramValue: []byte{1}}))
+	oldNonce := isc.NewOffLedgerRequest(e.ch.ChainID, testContract.Hname(), funcSetValue.Hname(), nil, 1).
+		WithGasBudget(testGasBudget).Sign(u[5])
+
+	reqs := []isc.Request{
+		e.call(u[0], funcSetValue, dict.Dict{paramValue: []byte{2}}),
+		e.call(u[1], funcIncCounter, nil),
+		// moves funds: reads the whole ledger
+		e.call(u[2], funcTransfer, dict.Dict{paramTarget: target.Bytes()}, isc.NewAllowanceBaseTokens(1000)),
+		e.call(u[3], funcEmitEvent, dict.Dict{paramValue: []byte("one")}),
+		e.call(u[4], funcEmitEvent, dict.Dict{paramValue: []byte("two")}),
+		e.call(u[5], funcFail, nil),
+		// sends an output: can't be run speculatively
+		e.request(u[3], accounts.Contract.Hname(), accounts.FuncWithdraw.Hname(), nil, isc.NewAllowanceBaseTokens(1_000_000)),
+		e.call(u[5], funcSetValue, dict.Dict{paramValue: []byte{3}}),
+		// skipped: the requests after it move up in the block
+		oldNonce,
+		e.call(u[1], funcIncCounter, nil),
+		e.call(u[2], funcSetValue, dict.Dict{paramValue: []byte{4}}),
+		e.call(u[4], funcTransfer, dict.Dict{paramTarget: target.Bytes()}, isc.NewAllowanceBaseTokens(1000)),
+	}
+	results := e.run(reqs...)
+	require.Len(t, results, len(reqs)-1)
+	require.NotNil(t, results[5].Receipt.Error)
+
+	events, err := e.ch.GetEventsForContract(testContract.Name)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -220,6 +221,28 @@ func (ctx *ViewContext) GetMerkleProof(key []byte) (ret *trie_blake2b.Proof, err
 	return ret, err
 }
 
+// GetStateProofs returns the index of the current state, and the value and
+// Merkle proof of each of the keys. A nil value means the key is absent.
+func (ctx *ViewContext) GetStateProofs(keys [][]byte) (blockIndex uint32, values [][]byte, proofs []*trie_blake2b.Proof, err error) {
+	err = panicutil.CatchAllButDBError(func() {
+		var e error
+		if blockIndex, e = ctx.stateReader.BlockIndex(); e != nil {
+			panic(e)
+		}
+		values = make([][]byte, len(keys))
+		proofs = make([]*trie_blake2b.Proof, len(keys))
+		for i, key := range keys {
+			values[i] = ctx.stateReader.KVStoreReader().MustGet(kv.Key(key))
+			proofs[i] = state.GetMerkleProof(key, ctx.stateReader.TrieNodeStore())
+		}
+		// the proofs must all belong to the same state
+		if bi, e := ctx.stateReader.BlockIndex(); e != nil || bi != blockIndex {
+			panic(coreutil.ErrorStateInvalidated)
+		}
+	}, ctx.log, "GetStateProofs: ")
+	return blockIndex, values, proofs, err
+}
+
 // GetBlockProof returns:
 // - blockInfo record in serialized form
 // - proof that the blockInfo is stored under the respective key.
@@ -20,31 +20,37 @@ import (
 
 // creditToAccount deposits transfer from request to chain account of of the called contract
 // It adds new tokens to the chain ledger. It is used when new tokens arrive with a request
-func (vmctx *VMContext) creditToAccount(agentID isc.AgentID, ftokens *isc.FungibleTokens) {
+func (vmctx *VMContext) creditToAccount(agentID isc.AgentID, ftokens *isc.FungibleTokens, reason accounts.HistoryReason) {
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
-		accounts.CreditToAccount(s, agentID, ftokens)
+		accounts.CreditToAccount(s, agentID, ftokens, reason)
 	})
 }
 
-func (vmctx *VMContext) creditNFTToAccount(agentID isc.AgentID, nft *isc.NFT) {
+func (vmctx *VMContext) creditNFTToAccount(agentID isc.AgentID, nft *isc.NFT, reason accounts.HistoryReason) {
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
-		accounts.CreditNFTToAccount(s, agentID, nft)
+		accounts.CreditNFTToAccount(s, agentID, nft, reason)
 	})
 }
 
 // debitFromAccount subtracts tokens from account if it is enough of it.
 // should be called only when posting request
-func (vmctx *VMContext) debitFromAccount(agentID isc.AgentID, transfer *isc.FungibleTokens) {
+func (vmctx *VMContext) debitFromAccount(agentID isc.AgentID, transfer *isc.FungibleTokens, reason accounts.HistoryReason) {
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
-		accounts.DebitFromAccount(s, agentID, transfer)
+		accounts.DebitFromAccount(s, agentID, transfer, reason)
 	})
 }
 
 // debitNFTFromAccount removes a NFT from account.
 // should be called only when posting request
-func (vmctx *VMContext) debitNFTFromAccount(agentID isc.AgentID, nftID iotago.NFTID) {
+func (vmctx *VMContext) debitNFTFromAccount(agentID isc.AgentID, nftID iotago.NFTID, reason accounts.HistoryReason) {
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
-		accounts.DebitNFTFromAccount(s, agentID, nftID)
+		accounts.DebitNFTFromAccount(s, agentID, nftID, reason)
+	})
+}
+
+func (vmctx *VMContext) mustMoveGasFee(payer, target isc.AgentID, fee *isc.FungibleTokens) {
+	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
+		accounts.MustMoveGasFee(s, payer, target, fee)
 	})
 }
 
@@ -134,19 +140,19 @@ func (vmctx *VMContext) GetNFTData(nftID iotago.NFTID) (ret isc.NFT) {
 	return ret
 }
 
-func (vmctx *VMContext) GetSenderTokenBalanceForFees() uint64 {
-	sender := vmctx.req.SenderAccount()
-	if sender == nil {
+// getTokenBalanceForFees returns the balance of the account in the tokens used to pay the gas fee
+func (vmctx *VMContext) getTokenBalanceForFees(agentID isc.AgentID) uint64 {
+	if agentID == nil {
 		return 0
 	}
 	if vmctx.chainInfo.GasFeePolicy.GasFeeTokenID == nil {
 		// base tokens are used as gas tokens
-		return vmctx.GetBaseTokensBalance(sender)
+		return vmctx.GetBaseTokensBalance(agentID)
 	}
 	// native tokens are used for gas fee
 	tokenID := vmctx.chainInfo.GasFeePolicy.GasFeeTokenID
 	// to pay for gas chain is configured to use some native token, not base tokens
-	tokensAvailableBig := vmctx.GetNativeTokenBalance(sender, tokenID)
+	tokensAvailableBig := vmctx.GetNativeTokenBalance(agentID, tokenID)
 	if tokensAvailableBig.IsUint64() {
 		return tokensAvailableBig.Uint64()
 	}
@@ -168,6 +174,8 @@ func (vmctx *VMContext) writeReceiptToBlockLog(errProvided error) *blocklog.Requ
 		GasBudget:     vmctx.gasBudgetAdjusted,
 		GasBurned:     vmctx.gasBurned,
 		GasFeeCharged: vmctx.gasFeeCharged,
+		GasFeePayer:   vmctx.gasFeePayer,
+		CallResults:   vmctx.callResults,
 	}
 
 	if errProvided != nil {
@@ -185,6 +193,31 @@ func (vmctx *VMContext) writeReceiptToBlockLog(errProvided error) *blocklog.Requ
 	if vmctx.task.EnableGasBurnLogging {
 		vmctx.gasBurnLog = gas.NewGasBurnLog()
 	}
+	if vmctx.speculation != nil {
+		// the receipt is saved when the speculative run is committed. Otherwise, all speculative runs
+		// would update the same indices of the blocklog, and conflict with each other
+		return receipt
+	}
+	vmctx.saveAccountHistory()
+	vmctx.saveReceipt(receipt)
+	return receipt
+}
+
+// saveAccountHistory stamps the movements of the accounts recorded by the request with the block and the request
+func (vmctx *VMContext) saveAccountHistory() {
+	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
+		accounts.SaveAccountHistory(s, vmctx.virtualState.BlockIndex(), vmctx.requestIndex, vmctx.req.ID())
+	})
+}
+
+// saveBlockAccountHistory stamps the movements of the accounts recorded by the block contexts with the block
+func (vmctx *VMContext) saveBlockAccountHistory() {
+	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
+		accounts.SaveBlockAccountHistory(s, vmctx.virtualState.BlockIndex())
+	})
+}
+
+func (vmctx *VMContext) saveReceipt(receipt *blocklog.RequestReceipt) {
 	var err error
 	vmctx.callCore(blocklog.Contract, func(s kv.KVStore) {
 		err = blocklog.SaveRequestReceipt(vmctx.State(), receipt, vmctx.requestLookupKey())
@@ -192,7 +225,6 @@ func (vmctx *VMContext) writeReceiptToBlockLog(errProvided error) *blocklog.Requ
 	if err != nil {
 		panic(err)
 	}
-	return receipt
 }
 
 func (vmctx *VMContext) MustSaveEvent(contract isc.Hname, msg string) {
@@ -0,0 +1,53 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"golang.org/x/xerrors"
+)
+
+// callTargets returns the targets of all the calls of the request
+func callTargets(req isc.Request) []isc.CallTarget {
+	multi, ok := req.(isc.MultiCallRequest)
+	if !ok {
+		return []isc.CallTarget{req.CallTarget()}
+	}
+	ret := make([]isc.CallTarget, len(multi.Calls()))
+	for i, c := range multi.Calls() {
+		ret[i] = c.Target
+	}
+	return ret
+}
+
+// checkReasonToSkipMultiCall checks the calls of the multi-call request.
+// The calls which are treated specially by the VM can't be a part of it
+func checkReasonToSkipMultiCall(req isc.MultiCallRequest) error {
+	if len(req.Calls()) == 0 || len(req.Calls()) > isc.MaxCallsInMultiCallRequest {
+		return xerrors.Errorf("wrong number of calls in the multi-call request: %d", len(req.Calls()))
+	}
+	for _, c := range req.Calls() {
+		if c.Target.EntryPoint == isc.EntryPointInit {
+			return xerrors.New("multi-call request can't call 'init'")
+		}
+		if c.Target.Contract == coreutil.CoreContractGovernanceHname && c.Target.EntryPoint == coreutil.CoreEPRotateStateControllerHname {
+			return xerrors.New("multi-call request can't rotate the state controller")
+		}
+	}
+	return nil
+}
+
+// callMulti runs the calls of the multi-call request one after another. The result of each call is
+// recorded for the receipt. A panic in any of the calls reverts all of them, the results of the calls
+// run before the failing one are kept in the receipt. Returns the result of the last call
+func (vmctx *VMContext) callMulti(calls []*isc.Call) (ret dict.Dict) {
+	vmctx.callResults = make([]dict.Dict, 0, len(calls))
+	for _, c := range calls {
+		ret = vmctx.callProgram(c.Target.Contract, c.Target.EntryPoint, c.Params, c.Allowance)
+		if ret == nil {
+			ret = dict.New()
+		}
+		vmctx.callResults = append(vmctx.callResults, ret)
+	}
+	return ret
+}
@@ -25,6 +25,7 @@ func (vmctx *VMContext) mustBeCalledFromContract(contract *coreutil.ContractInfo
 }
 
 func (vmctx *VMContext) TryLoadContract(programHash hashing.HashValue) error {
+	vmctx.mustNotBeSpeculative()
 	vmctx.mustBeCalledFromContract(root.Contract)
 	vmtype, programBinary, err := execution.GetProgramBinary(vmctx, programHash)
 	if err != nil {
@@ -34,16 +35,19 @@ func (vmctx *VMContext) TryLoadContract(programHash hashing.HashValue) error {
 }
 
 func (vmctx *VMContext) CreateNewFoundry(scheme iotago.TokenScheme, metadata []byte) (uint32, uint64) {
+	vmctx.mustNotBeSpeculative()
 	vmctx.mustBeCalledFromContract(accounts.Contract)
 	return vmctx.txbuilder.CreateNewFoundry(scheme, metadata)
 }
 
 func (vmctx *VMContext) DestroyFoundry(sn uint32) uint64 {
+	vmctx.mustNotBeSpeculative()
 	vmctx.mustBeCalledFromContract(accounts.Contract)
 	return vmctx.txbuilder.DestroyFoundry(sn)
 }
 
 func (vmctx *VMContext) ModifyFoundrySupply(sn uint32, delta *big.Int) int64 {
+	vmctx.mustNotBeSpeculative()
 	vmctx.mustBeCalledFromContract(accounts.Contract)
 	out, _, _ := accounts.GetFoundryOutput(vmctx.State(), sn, vmctx.ChainID())
 	tokenID, err := out.NativeTokenID()
@@ -61,9 +65,11 @@ func (vmctx *VMContext) SubscribeBlockContext(openFunc, closeFunc isc.Hname) {
 }
 
 func (vmctx *VMContext) SetBlockContext(bctx interface{}) {
+	vmctx.mustNotBeSpeculative()
 	vmctx.blockContext[vmctx.CurrentContractHname()] = bctx
 }
 
 func (vmctx *VMContext) BlockContext() interface{} {
+	vmctx.mustNotBeSpeculative()
 	return vmctx.blockContext[vmctx.CurrentContractHname()]
 }
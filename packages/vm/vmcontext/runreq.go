@@ -20,10 +20,12 @@ import (
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/util/panicutil"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/errors/coreerrors"
 	"github.com/iotaledger/wasp/packages/vm/core/evm"
 	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/gas"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext/vmexceptions"
@@ -40,9 +42,11 @@ func (vmctx *VMContext) RunTheRequest(req isc.Request, requestIndex uint16) (res
 	vmctx.requestEventIndex = 0
 	vmctx.entropy = hashing.HashData(vmctx.entropy[:])
 	vmctx.callStack = vmctx.callStack[:0]
+	vmctx.callResults = nil
 	vmctx.gasBudgetAdjusted = 0
 	vmctx.gasBurned = 0
 	vmctx.gasFeeCharged = 0
+	vmctx.gasFeePayer = nil
 	vmctx.GasBurnEnable(false)
 
 	vmctx.currentStateUpdate = state.NewStateUpdate(vmctx.virtualState.Timestamp().Add(1 * time.Nanosecond))
@@ -93,6 +97,11 @@ func (vmctx *VMContext) creditAssetsToChain() {
 		// off ledger request does not bring any deposit
 		return
 	}
+	if req, ok := vmctx.req.(isc.ScheduledCallRequest); ok {
+		// scheduled call does not consume any output, the prepaid tokens are already on the chain
+		vmctx.takeScheduledCall(req)
+		return
+	}
 	// Consume the output. Adjustment in L2 is needed because of the storage deposit in the internal UTXOs
 	storageDepositAdjustment := vmctx.txbuilder.Consume(vmctx.req.(isc.OnLedgerRequest))
 	if storageDepositAdjustment > 0 {
@@ -105,8 +114,8 @@ func (vmctx *VMContext) creditAssetsToChain() {
 	if account == nil {
 		account = vmctx.ChainID().CommonAccount()
 	}
-	vmctx.creditToAccount(account, vmctx.req.FungibleTokens())
-	vmctx.creditNFTToAccount(account, vmctx.req.NFT())
+	vmctx.creditToAccount(account, vmctx.req.FungibleTokens(), accounts.HistoryReasonDeposit)
+	vmctx.creditNFTToAccount(account, vmctx.req.NFT(), accounts.HistoryReasonDeposit)
 
 	// adjust the sender's account with the storage deposit consumed or returned by internal UTXOs
 	// if base tokens in the sender's account is not enough for the storage deposit of newly created TNT outputs
@@ -134,6 +143,7 @@ func (vmctx *VMContext) prepareGasBudget() {
 	if vmctx.isInitChainRequest() {
 		return
 	}
+	vmctx.resolveGasFeePayer()
 	vmctx.calculateAffordableGasBudget()
 	vmctx.gasSetBudget(vmctx.gasBudgetAdjusted)
 	vmctx.GasBurnEnable(true)
@@ -216,6 +226,9 @@ func (vmctx *VMContext) callFromRequest() dict.Dict {
 		// if sender unknown, follow panic path
 		panic(vm.ErrSenderUnknown)
 	}
+	if req, ok := vmctx.req.(isc.MultiCallRequest); ok {
+		return vmctx.callMulti(req.Calls())
+	}
 	// TODO check if the comment below holds true
 	// calling only non view entry points. Calling the view will trigger error and fallback
 	contract := vmctx.req.CallTarget().Contract
@@ -269,9 +282,44 @@ func (vmctx *VMContext) calculateAffordableGasBudget() {
 	vmctx.gasBudgetAdjusted = util.MinUint64(affordable, gas.MaxGasPerCall)
 }
 
+// resolveGasFeePayer determines the account the gas fee is charged from. It is the sponsor of the request,
+// if the request is sponsored and the sponsor is allowed by the chain owner. Otherwise, it is the sender
+func (vmctx *VMContext) resolveGasFeePayer() {
+	vmctx.gasFeePayer = vmctx.req.SenderAccount()
+	vmctx.gasFeePayerMaxFee = math.MaxUint64
+
+	req, ok := vmctx.req.(isc.SponsoredOffLedgerRequest)
+	if !ok {
+		return
+	}
+	sponsor, maxFee := req.Sponsor()
+	if sponsor == nil || sponsor.Equals(vmctx.gasFeePayer) {
+		return
+	}
+	var allowed bool
+	vmctx.callCore(governance.Contract, func(s kv.KVStore) {
+		allowed = governance.IsGasSponsorAllowed(s, sponsor)
+	})
+	if !allowed {
+		vmctx.Debugf("resolveGasFeePayer: %s is not allowed to sponsor gas, the sender pays", sponsor)
+		return
+	}
+	vmctx.gasFeePayer = sponsor
+	vmctx.gasFeePayerMaxFee = maxFee
+}
+
+// isSponsored returns true if the gas fee of the current request is paid by the sponsor
+func (vmctx *VMContext) isSponsored() bool {
+	return vmctx.gasFeePayer != nil && !vmctx.gasFeePayer.Equals(vmctx.req.SenderAccount())
+}
+
 // calcGuaranteedFeeTokens return hiw maximum tokens (base tokens or native) can be guaranteed for the fee,
 // taking into account allowance (which must be 'reserved')
 func (vmctx *VMContext) calcGuaranteedFeeTokens() uint64 {
+	if vmctx.isSponsored() {
+		// the allowance is taken from the sender, the sponsor pays up to the max fee it signed
+		return util.MinUint64(vmctx.getTokenBalanceForFees(vmctx.gasFeePayer), vmctx.gasFeePayerMaxFee)
+	}
 	var tokensGuaranteed uint64
 
 	if vmctx.chainInfo.GasFeePolicy.GasFeeTokenID == nil {
@@ -311,7 +359,7 @@ func (vmctx *VMContext) calcGuaranteedFeeTokens() uint64 {
 	return tokensGuaranteed
 }
 
-// chargeGasFee takes burned tokens from the sender's account
+// chargeGasFee takes burned tokens from the account of the gas fee payer, i.e. the sender or the sponsor
 // It should always be enough because gas budget is set affordable
 func (vmctx *VMContext) chargeGasFee() {
 	// ensure at least the minimum amount of gas is charged
@@ -336,7 +384,10 @@ func (vmctx *VMContext) chargeGasFee() {
 	availableToPayFee := vmctx.gasMaxTokensToSpendForGasFee
 	if !vmctx.task.EstimateGasMode && !vmctx.chainInfo.GasFeePolicy.IsEnoughForMinimumFee(availableToPayFee) {
 		// user didn't specify enough base tokens to cover the minimum request fee, charge whatever is present in the user's account
-		availableToPayFee = vmctx.GetSenderTokenBalanceForFees()
+		availableToPayFee = vmctx.getTokenBalanceForFees(vmctx.gasFeePayer)
+		if vmctx.isSponsored() {
+			availableToPayFee = util.MinUint64(availableToPayFee, vmctx.gasFeePayerMaxFee)
+		}
 	}
 
 	// total fees to charge
@@ -364,10 +415,21 @@ func (vmctx *VMContext) chargeGasFee() {
 		transferToValidator.BaseTokens = sendToValidator
 		transferToOwner.BaseTokens = sendToOwner
 	}
-	sender := vmctx.req.SenderAccount()
+	if vmctx.speculation != nil {
+		// the fee is moved when the speculative run is committed. Otherwise, all speculative runs
+		// would read and write the same fee accounts, and conflict with each other
+		vmctx.speculation.feePayer = vmctx.gasFeePayer
+		vmctx.speculation.feeToValidator = transferToValidator
+		vmctx.speculation.feeToOwner = transferToOwner
+		return
+	}
+	vmctx.moveGasFee(vmctx.gasFeePayer, transferToValidator, transferToOwner)
+}
 
-	vmctx.mustMoveBetweenAccounts(sender, vmctx.task.ValidatorFeeTarget, transferToValidator, nil)
-	vmctx.mustMoveBetweenAccounts(sender, vmctx.ChainID().CommonAccount(), transferToOwner, nil)
+// moveGasFee moves the charged gas fee from the payer's account to the validator and the chain owner
+func (vmctx *VMContext) moveGasFee(payer isc.AgentID, toValidator, toOwner *isc.FungibleTokens) {
+	vmctx.mustMoveGasFee(payer, vmctx.task.ValidatorFeeTarget, toValidator)
+	vmctx.mustMoveGasFee(payer, vmctx.ChainID().CommonAccount(), toOwner)
 }
 
 func (vmctx *VMContext) GetContractRecord(contractHname isc.Hname) (ret *root.ContractRecord) {
@@ -407,6 +469,10 @@ func (vmctx *VMContext) isInitChainRequest() bool {
 
 // mustCheckTransactionSize panics with ErrMaxTransactionSizeExceeded if the estimated transaction size exceeds the limit
 func (vmctx *VMContext) mustCheckTransactionSize() {
+	if vmctx.speculation != nil {
+		// the speculative run does not add anything to the transaction
+		return
+	}
 	essence, _ := vmctx.txbuilder.BuildTransactionEssence(state.L1CommitmentNil)
 	tx := transaction.MakeAnchorTransaction(essence, &iotago.Ed25519Signature{})
 	if tx.Size() > parameters.L1().MaxPayloadSize {
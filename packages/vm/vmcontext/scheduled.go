@@ -0,0 +1,60 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"golang.org/x/xerrors"
+)
+
+// ScheduledCallsDue returns the calls from the scheduler core contract, which are due in the current block.
+// They are run at the start of the block, before the requests of the batch
+func (vmctx *VMContext) ScheduledCallsDue() []isc.Request {
+	vmctx.currentStateUpdate = state.NewStateUpdate()
+	defer func() { vmctx.currentStateUpdate = nil }()
+
+	var calls []*scheduler.ScheduledCall
+	var err error
+	vmctx.callCore(scheduler.Contract, func(s kv.KVStore) {
+		calls, err = scheduler.DueCalls(s, vmctx.virtualState.BlockIndex(), vmctx.task.TimeAssumption)
+	})
+	if err != nil {
+		panic(xerrors.Errorf("ScheduledCallsDue: %w", err))
+	}
+	ret := make([]isc.Request, len(calls))
+	for i, call := range calls {
+		ret[i] = call.Request(vmctx.ChainID())
+	}
+	return ret
+}
+
+// checkReasonToSkipScheduledCall checks if the call is still pending in the scheduler
+func (vmctx *VMContext) checkReasonToSkipScheduledCall() error {
+	callID := vmctx.req.(isc.ScheduledCallRequest).CallID()
+	var call *scheduler.ScheduledCall
+	var err error
+	vmctx.callCore(scheduler.Contract, func(s kv.KVStore) {
+		call, err = scheduler.GetScheduledCall(s, callID)
+	})
+	if err != nil {
+		return err
+	}
+	if call == nil {
+		return xerrors.Errorf("scheduled call %d is not pending", callID)
+	}
+	return nil
+}
+
+// takeScheduledCall removes the call from the scheduler and moves the prepaid tokens
+// from the escrow to the account of the sender, which pays for the gas of the call
+func (vmctx *VMContext) takeScheduledCall(req isc.ScheduledCallRequest) {
+	var err error
+	vmctx.callCore(scheduler.Contract, func(s kv.KVStore) {
+		err = scheduler.DeleteScheduledCall(s, req.CallID())
+	})
+	if err != nil {
+		panic(xerrors.Errorf("takeScheduledCall: %w", err))
+	}
+	vmctx.mustMoveBetweenAccounts(scheduler.EscrowAccount(vmctx.ChainID()), req.SenderAccount(), req.FungibleTokens(), nil)
+}
@@ -17,6 +17,13 @@ func (vmctx *VMContext) getNFTData(nftID iotago.NFTID) *isc.NFT {
 	return &nft
 }
 
+func (vmctx *VMContext) isL2NativeNFT(nftID iotago.NFTID) (ret bool) {
+	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
+		ret = accounts.IsL2NativeNFT(s, nftID)
+	})
+	return ret
+}
+
 func (vmctx *VMContext) SendAsNFT(par isc.RequestParameters, nftID iotago.NFTID) {
 	nft := vmctx.getNFTData(nftID)
 	out := transaction.NFTOutputFromPostData(
@@ -25,7 +32,19 @@ func (vmctx *VMContext) SendAsNFT(par isc.RequestParameters, nftID iotago.NFTID)
 		par,
 		nft,
 	)
-	vmctx.debitNFTFromAccount(vmctx.AccountID(), nftID)
+	if vmctx.isL2NativeNFT(nftID) {
+		// the NFT was minted on L2, the output mints it on L1 with a new NFTID
+		vmctx.mustNotBeSpeculative()
+		out.NFTID = iotago.NFTID{}
+		if issuer, ok := nft.Issuer.(*iotago.NFTAddress); ok {
+			// the collection NFT must be unlocked by the transaction
+			if err := vmctx.txbuilder.IncludeNFT(issuer.NFTID()); err != nil {
+				vmctx.Debugf("SendAsNFT: %v", err)
+				panic(vm.ErrIssuerNFTNotOnChain.Create(issuer.NFTID().String()))
+			}
+		}
+	}
+	vmctx.debitNFTFromAccount(vmctx.AccountID(), nftID, accounts.HistoryReasonWithdrawal)
 	vmctx.sendOutput(out)
 }
 
@@ -41,6 +60,7 @@ func (vmctx *VMContext) Send(par isc.RequestParameters) {
 }
 
 func (vmctx *VMContext) sendOutput(o iotago.Output) {
+	vmctx.mustNotBeSpeculative()
 	if vmctx.NumPostedOutputs >= MaxPostedOutputsInOneRequest {
 		panic(vm.ErrExceededPostedOutputLimit)
 	}
@@ -57,6 +77,6 @@ func (vmctx *VMContext) sendOutput(o iotago.Output) {
 	vmctx.adjustL2BaseTokensIfNeeded(baseTokenAdjustmentL2, vmctx.AccountID())
 	// debit the assets from the on-chain account
 	// It panics with accounts.ErrNotEnoughFunds if sender's account balances are exceeded
-	vmctx.debitFromAccount(vmctx.AccountID(), assets)
+	vmctx.debitFromAccount(vmctx.AccountID(), assets, accounts.HistoryReasonWithdrawal)
 	vmctx.assertConsistentL2WithL1TxBuilder("sandbox.Send: end")
 }
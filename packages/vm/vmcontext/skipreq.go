@@ -9,6 +9,8 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/evm"
+	"github.com/iotaledger/wasp/packages/vm/core/evm/evmimpl"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext/vmexceptions"
 	"golang.org/x/xerrors"
@@ -36,11 +38,23 @@ func (vmctx *VMContext) earlyCheckReasonToSkip() error {
 		}
 	}
 
-	if vmctx.task.MaintenanceModeEnabled &&
-		vmctx.req.CallTarget().Contract != governance.Contract.Hname() {
-		return fmt.Errorf("skipped due to maintenance mode")
+	if req, ok := vmctx.req.(isc.MultiCallRequest); ok {
+		if err := checkReasonToSkipMultiCall(req); err != nil {
+			return err
+		}
+	}
+
+	if vmctx.task.MaintenanceModeEnabled {
+		for _, target := range callTargets(vmctx.req) {
+			if target.Contract != governance.Contract.Hname() {
+				return fmt.Errorf("skipped due to maintenance mode")
+			}
+		}
 	}
 
+	if _, ok := vmctx.req.(isc.ScheduledCallRequest); ok {
+		return vmctx.checkReasonToSkipScheduledCall()
+	}
 	if vmctx.req.IsOffLedger() {
 		return vmctx.checkReasonToSkipOffLedger()
 	}
@@ -79,15 +93,44 @@ func (vmctx *VMContext) checkReasonToSkipOffLedger() error {
 	}
 
 	var maxAssumed uint64
+	var policyErr error
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
 		// this is a replay protection measure for off-ledger requests assuming in the batch order of requests is random.
 		// It is checking if nonce is not too old. See replay-off-ledger.md
 		maxAssumed = accounts.GetMaxAssumedNonce(vmctx.State(), vmctx.req.SenderAccount())
+		// the signatures of the multisig request are checked against the policy it carries
+		if req, ok := vmctx.req.(isc.MultisigRequest); ok {
+			policyErr = accounts.CheckMultisigPolicy(vmctx.State(), req.SenderAccount().(*isc.MultisigAgentID), req.MultisigPolicy())
+		}
 	})
+	if policyErr != nil {
+		return policyErr
+	}
+	if err := vmctx.checkReasonToSkipEVMTransaction(); err != nil {
+		return err
+	}
 
 	return CheckNonce(vmctx.req.(isc.OffLedgerRequest), maxAssumed)
 }
 
+// checkReasonToSkipEVMTransaction skips the Ethereum transactions sent ahead of their turn. They are kept in the
+// mempool until the transactions with the previous nonces of the sender are processed, as the order of the
+// requests in the batch is random
+func (vmctx *VMContext) checkReasonToSkipEVMTransaction() error {
+	tx, ok := isc.EVMTransaction(vmctx.req)
+	if !ok {
+		return nil
+	}
+	var nonce uint64
+	vmctx.callCore(evm.Contract, func(s kv.KVStore) {
+		nonce = evmimpl.GetNonce(s, vmctx.req.SenderAccount().(*isc.EthereumAddressAgentID).EthAddress())
+	})
+	if tx.Nonce() > nonce {
+		return fmt.Errorf("nonce %d is ahead of the account nonce %d", tx.Nonce(), nonce)
+	}
+	return nil
+}
+
 // checkReasonToSkipOnLedger check reasons to skip UTXO request
 func (vmctx *VMContext) checkReasonToSkipOnLedger() error {
 	if err := vmctx.checkInternalOutput(); err != nil {
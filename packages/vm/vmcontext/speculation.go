@@ -0,0 +1,157 @@
+package vmcontext
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext/vmexceptions"
+)
+
+// Speculation is a run of the request on a fork of the VMContext, i.e. on a copy of the state.
+// The keys accessed by the run are recorded. The outcome of the run can be committed to the
+// VMContext, if none of the keys read by the run has been written in the meantime.
+type Speculation struct {
+	fork         *VMContext
+	req          isc.Request
+	requestIndex uint16
+	timestamp    time.Time // of the state the request is assumed to be run on
+	log          *buffered.AccessLog
+	aborted      bool // the request needs the resources shared by the whole block
+
+	result *vm.RequestResult
+	update state.Update
+	// the gas fee, moved from the payer's account when the run is committed
+	feePayer       isc.AgentID
+	feeToValidator *isc.FungibleTokens
+	feeToOwner     *isc.FungibleTokens
+}
+
+// Speculate forks the context for a speculative run of the request. The request is assumed to be
+// run at the index in the block, after 'ahead' more requests were run, none of them skipped.
+// The VMContext must not be used until all the forked speculations are run. The runs themselves
+// can be done concurrently.
+func (vmctx *VMContext) Speculate(req isc.Request, requestIndex uint16, ahead int) *Speculation {
+	if vmctx.writes == nil {
+		// from now on, the writes are recorded to validate the speculative runs
+		vmctx.writes = buffered.NewAccessLog()
+		vmctx.virtualState.KVStore().SetAccessLog(vmctx.writes)
+	}
+	entropy := vmctx.entropy
+	for i := 0; i < ahead; i++ {
+		entropy = hashing.HashData(entropy[:])
+	}
+	fork := &VMContext{
+		task:                      vmctx.task,
+		chainOwnerID:              vmctx.chainOwnerID,
+		virtualState:              state.WrapMustOptimisticVirtualStateAccess(vmctx.virtualState.Copy(), vmctx.task.SolidStateBaseline),
+		finalStateTimestamp:       vmctx.finalStateTimestamp,
+		blockContext:              make(map[isc.Hname]interface{}),
+		storageDepositAssumptions: vmctx.storageDepositAssumptions,
+		txbuilder:                 vmctx.txbuilder.Clone(),
+		entropy:                   entropy,
+		callStack:                 make([]*callContext, 0),
+	}
+	if vmctx.task.EnableGasBurnLogging {
+		fork.gasBurnLog = gas.NewGasBurnLog()
+	}
+	ret := &Speculation{
+		fork:         fork,
+		req:          req,
+		requestIndex: requestIndex,
+		timestamp:    vmctx.virtualState.Timestamp().Add(time.Duration(ahead) * time.Nanosecond),
+		log:          buffered.NewAccessLog(),
+	}
+	fork.speculation = ret
+	fork.virtualState.ApplyStateUpdate(state.NewStateUpdate(ret.timestamp))
+	fork.virtualState.KVStore().SetAccessLog(ret.log)
+	return ret
+}
+
+// Run runs the request on the fork. If the request is skipped or the run fails in any other way
+// than the request itself failing, the speculation is not valid and can't be committed
+func (s *Speculation) Run() {
+	err := panicutil.CatchPanic(func() {
+		var skipReason error
+		s.result, skipReason = s.fork.RunTheRequest(s.req, s.requestIndex)
+		if skipReason != nil {
+			s.result = nil
+		}
+	})
+	s.fork.virtualState.KVStore().SetAccessLog(nil)
+	if err != nil || s.aborted {
+		s.result = nil
+	}
+	if s.result == nil {
+		return
+	}
+	// the state update of the request consists of the keys written by the run
+	muts := s.fork.virtualState.KVStore().Mutations()
+	s.update = state.NewStateUpdate()
+	for _, k := range s.log.Writes() {
+		if v, ok := muts.Sets[k]; ok {
+			s.update.Mutations().Set(k, v)
+		} else {
+			s.update.Mutations().Del(k)
+		}
+	}
+}
+
+// CommitSpeculation commits the outcome of the speculative run of the request, if it is the same as
+// the outcome of running the request now would be: the request is at the assumed index in the block,
+// and none of the keys read by the run has been written since the fork. Otherwise, false is returned
+// and the request must be run again with RunTheRequest
+func (vmctx *VMContext) CommitSpeculation(s *Speculation, requestIndex uint16) (*vm.RequestResult, bool) {
+	fork := s.fork
+	if s.result == nil ||
+		s.requestIndex != requestIndex ||
+		!s.timestamp.Equal(vmctx.virtualState.Timestamp()) ||
+		fork.entropy != hashing.HashData(vmctx.entropy[:]) ||
+		// the run must not hit the gas limit of the block, which it could not see
+		vmctx.gasBurnedTotal+2*fork.gasBurnedTotal > gas.MaxGasPerBlock ||
+		// the timestamp is written by each request. It is checked above
+		s.log.ReadsAnyWrittenBy(vmctx.writes, kv.Key(coreutil.StatePrefixTimestamp)) {
+		return nil, false
+	}
+
+	vmctx.req = s.req
+	defer func() { vmctx.req = nil }()
+	vmctx.requestIndex = requestIndex
+	vmctx.currentStateUpdate = s.update
+	defer func() { vmctx.currentStateUpdate = nil }()
+
+	vmctx.entropy = fork.entropy
+	vmctx.chainInfo = fork.chainInfo
+	vmctx.chainOwnerID = fork.chainOwnerID
+	vmctx.gasBurnedTotal += fork.gasBurnedTotal
+	vmctx.gasFeeChargedTotal += fork.gasFeeChargedTotal
+	vmctx.GasBurnEnable(false)
+	if s.feeToValidator != nil {
+		vmctx.moveGasFee(s.feePayer, s.feeToValidator, s.feeToOwner)
+	}
+	// NOTE: when the history of the accounts is enabled, all runs record to the same pending entries,
+	// so the speculative runs mostly conflict and the requests are run again
+	vmctx.saveAccountHistory()
+	vmctx.saveReceipt(s.result.Receipt)
+	vmctx.virtualState.ApplyStateUpdate(vmctx.currentStateUpdate)
+	vmctx.assertConsistentL2WithL1TxBuilder("end CommitSpeculation")
+	return s.result, true
+}
+
+// mustNotBeSpeculative aborts the speculative run, when the request needs the resources shared by the
+// whole block: the anchor transaction and the block contexts
+func (vmctx *VMContext) mustNotBeSpeculative() {
+	if vmctx.speculation == nil {
+		return
+	}
+	// the panic may be caught by the VM plugin, the flag makes sure the run is not committed
+	vmctx.speculation.aborted = true
+	panic(vmexceptions.ErrSpeculationAborted)
+}
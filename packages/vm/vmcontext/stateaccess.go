@@ -87,15 +87,19 @@ func (s chainStateWrapper) IterateSorted(prefix kv.Key, f func(kv.Key, []byte) b
 }
 
 func (s chainStateWrapper) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) bool) error {
+	return s.IterateKeysSortedFrom(prefix, "", f)
+}
+
+func (s chainStateWrapper) IterateKeysSortedFrom(prefix, from kv.Key, f func(key kv.Key) bool) error {
 	s.vmctx.task.SolidStateBaseline.MustValidate()
 
 	var keys []kv.Key
 	for k := range s.vmctx.currentStateUpdate.Mutations().Sets {
-		if k.HasPrefix(prefix) {
+		if k.HasPrefix(prefix) && k >= from {
 			keys = append(keys, k)
 		}
 	}
-	err := s.vmctx.virtualState.KVStore().IterateKeysSorted(prefix, func(k kv.Key) bool {
+	err := s.vmctx.virtualState.KVStore().IterateKeysSortedFrom(prefix, from, func(k kv.Key) bool {
 		if !s.vmctx.currentStateUpdate.Mutations().Contains(k) {
 			keys = append(keys, k)
 		}
@@ -10,6 +10,8 @@ import (
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/transaction"
 	"github.com/iotaledger/wasp/packages/vm"
@@ -40,6 +42,10 @@ type VMContext struct {
 	txsnapshot                *vmtxbuilder.AnchorTransactionBuilder
 	gasBurnedTotal            uint64
 	gasFeeChargedTotal        uint64
+	// writes since the first speculative run has been forked, nil before
+	writes *buffered.AccessLog
+	// not nil if the context is a fork for a speculative run of the request
+	speculation *Speculation
 
 	// ---- request context
 	chainInfo          *governance.ChainInfo
@@ -50,6 +56,8 @@ type VMContext struct {
 	currentStateUpdate state.Update
 	entropy            hashing.HashValue
 	callStack          []*callContext
+	// results of the calls of the multi-call request, nil for other requests
+	callResults []dict.Dict
 	// --- gas related
 	// max tokens cane be charged for gas fee
 	gasMaxTokensToSpendForGasFee uint64
@@ -61,6 +69,10 @@ type VMContext struct {
 	gasBurned uint64
 	// tokens charged
 	gasFeeCharged uint64
+	// the account the gas fee is charged from: the sender or the sponsor of the request
+	gasFeePayer isc.AgentID
+	// max tokens the sponsor pays for the gas fee
+	gasFeePayerMaxFee uint64
 	// burn history. If disabled, it is nil
 	gasBurnLog *gas.BurnLog
 }
@@ -259,6 +271,7 @@ func (vmctx *VMContext) OpenBlockContexts() {
 	for _, sub := range subs {
 		vmctx.callProgram(sub.Contract, sub.OpenFunc, nil, nil)
 	}
+	vmctx.saveBlockAccountHistory()
 
 	vmctx.virtualState.ApplyStateUpdate(vmctx.currentStateUpdate)
 }
@@ -275,6 +288,7 @@ func (vmctx *VMContext) closeBlockContexts() {
 	for i := len(subs) - 1; i >= 0; i-- {
 		vmctx.callProgram(subs[i].Contract, subs[i].CloseFunc, nil, nil)
 	}
+	vmctx.saveBlockAccountHistory()
 }
 
 // saveInternalUTXOs relies on the order of the outputs in the anchor tx. If that order changes, this will be broken.
@@ -328,6 +342,10 @@ func (vmctx *VMContext) assertConsistentL2WithL1TxBuilder(checkpoint string) {
 	if vmctx.task.AnchorOutput.StateIndex == 0 && vmctx.isInitChainRequest() {
 		return
 	}
+	if vmctx.speculation != nil {
+		// the speculative run does not touch the tx builder. Consistency is checked when the run is committed
+		return
+	}
 	var totalL2Assets *isc.FungibleTokens
 	vmctx.callCore(accounts.Contract, func(s kv.KVStore) {
 		totalL2Assets = accounts.GetTotalL2Assets(s)
@@ -338,6 +356,10 @@ func (vmctx *VMContext) assertConsistentL2WithL1TxBuilder(checkpoint string) {
 func (vmctx *VMContext) AssertConsistentGasTotals() {
 	var sumGasBurned, sumGasFeeCharged uint64
 
+	for _, r := range vmctx.task.ScheduledResults {
+		sumGasBurned += r.Receipt.GasBurned
+		sumGasFeeCharged += r.Receipt.GasFeeCharged
+	}
 	for _, r := range vmctx.task.Results {
 		sumGasBurned += r.Receipt.GasBurned
 		sumGasFeeCharged += r.Receipt.GasFeeCharged
@@ -21,6 +21,10 @@ var (
 	ErrMaxTransactionSizeExceeded              = &skipRequestException{"exceeded maximum size of the transaction"}
 )
 
+// ErrSpeculationAborted aborts the speculative run of the request, when the request needs the resources
+// shared by the whole block. The request is then run again, in the batch order
+var ErrSpeculationAborted = &skipRequestException{"speculative run of the request aborted"}
+
 var AllProtocolLimits = []error{
 	ErrInputLimitExceeded,
 	ErrOutputLimitExceeded,
@@ -6,6 +6,7 @@ import (
 
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext/vmexceptions"
+	"golang.org/x/xerrors"
 )
 
 type nftIncluded struct {
@@ -65,6 +66,11 @@ func (txb *AnchorTransactionBuilder) NFTOutputsToBeUpdated() (toBeAdded, toBeRem
 	toBeRemoved = make([]*iotago.NFTOutput, 0, len(txb.nftsIncluded))
 	txb.inputs()
 	for _, nft := range txb.nftsSorted() {
+		if nft.in != nil && !nft.sentOutside {
+			// to update if input is not nil and it stays on the chain (it was included as the issuer of a new NFT)
+			toBeAdded = append(toBeAdded, nft.out)
+			continue
+		}
 		if nft.in != nil {
 			// to remove if input is not nil (nft exists in accounting), and its sent to outside the chain
 			toBeRemoved = append(toBeRemoved, nft.out)
@@ -123,6 +129,11 @@ func (txb *AnchorTransactionBuilder) sendNFT(o *iotago.NFTOutput) int64 {
 		panic(vmexceptions.ErrOutputLimitExceeded)
 	}
 
+	if o.NFTID.Empty() {
+		// NFT minted on L2 is minted on L1 by the output. It has no input and no storage deposit on the chain
+		return 0
+	}
+
 	if txb.nftsIncluded[o.NFTID] != nil {
 		// NFT comes in and out in the same block
 		txb.nftsIncluded[o.NFTID].sentOutside = true
@@ -147,3 +158,30 @@ func (txb *AnchorTransactionBuilder) sendNFT(o *iotago.NFTOutput) int64 {
 	txb.addDeltaBaseTokensToTotal(txb.storageDepositAssumption.NFTOutput)
 	return int64(txb.storageDepositAssumption.NFTOutput)
 }
+
+// IncludeNFT makes the NFT owned by the chain an input of the transaction and keeps it on the chain.
+// It is needed to unlock the NFT when it is the issuer of an NFT minted by the transaction.
+// Returns an error if the NFT is not owned by the chain on L1
+func (txb *AnchorTransactionBuilder) IncludeNFT(id iotago.NFTID) error {
+	if txb.nftsIncluded[id] != nil {
+		// the NFT is already an input of the transaction
+		return nil
+	}
+	if txb.InputsAreFull() {
+		panic(vmexceptions.ErrInputLimitExceeded)
+	}
+	if txb.outputsAreFull() {
+		panic(vmexceptions.ErrOutputLimitExceeded)
+	}
+	in, input := txb.loadNFTOutput(id)
+	if in == nil {
+		return xerrors.Errorf("IncludeNFT: NFT %s is not owned by the chain on L1", id)
+	}
+	txb.nftsIncluded[id] = &nftIncluded{
+		ID:    id,
+		input: input,
+		in:    in,
+		out:   cloneInternalNFTOutputOrNil(in),
+	}
+	return nil
+}
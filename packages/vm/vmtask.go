@@ -55,6 +55,11 @@ type VMTask struct {
 	ResultInputsCommitment []byte
 	// Results contains one result for each non-skipped request
 	Results []*RequestResult
+	// SkippedRequests contains the requests skipped by the VM, with the reasons
+	SkippedRequests []*SkippedRequest
+	// ScheduledResults contains one result for each call from the scheduler core contract,
+	// run at the start of the block, before the requests
+	ScheduledResults []*RequestResult
 	// If maintenance mode is enabled, only requests to the governance contract will be executed
 	MaintenanceModeEnabled bool
 }
@@ -68,6 +73,11 @@ type RequestResult struct {
 	Receipt *blocklog.RequestReceipt
 }
 
+type SkippedRequest struct {
+	Request isc.Request
+	Reason  error
+}
+
 func (task *VMTask) GetProcessedRequestIDs() []isc.RequestID {
 	ret := make([]isc.RequestID, len(task.Results))
 	for i, res := range task.Results {
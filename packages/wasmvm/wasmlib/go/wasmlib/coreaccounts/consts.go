@@ -18,10 +18,12 @@ const (
 
 const (
 	ParamAgentID                = "a"
+	ParamCursor                 = "pc"
 	ParamDestroyTokens          = "y"
 	ParamForceMinimumBaseTokens = "f"
 	ParamForceOpenAccount       = "c"
 	ParamFoundrySN              = "s"
+	ParamLimit                  = "pl"
 	ParamNftID                  = "z"
 	ParamSupplyDeltaAbs         = "d"
 	ParamTokenScheme            = "t"
@@ -29,12 +31,13 @@ const (
 
 const (
 	ResultAccountNonce     = "n"
-	ResultAllAccounts      = "this"
+	ResultAllAccounts      = "A"
 	ResultAssets           = "this"
 	ResultBalances         = "this"
 	ResultFoundryOutputBin = "b"
 	ResultFoundrySN        = "s"
 	ResultMapping          = "this"
+	ResultNextCursor       = "pn"
 	ResultNftData          = "e"
 	ResultNftIDs           = "i"
 )
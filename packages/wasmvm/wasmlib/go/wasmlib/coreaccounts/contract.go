@@ -51,6 +51,7 @@ type AccountNFTsCall struct {
 
 type AccountsCall struct {
 	Func    *wasmlib.ScView
+	Params  MutableAccountsParams
 	Results ImmutableAccountsResults
 }
 
@@ -140,6 +141,7 @@ func (sc Funcs) AccountNFTs(ctx wasmlib.ScViewCallContext) *AccountNFTsCall {
 
 func (sc Funcs) Accounts(ctx wasmlib.ScViewCallContext) *AccountsCall {
 	f := &AccountsCall{Func: wasmlib.NewScView(ctx, HScName, HViewAccounts)}
+	f.Params.proxy = wasmlib.NewCallParamsProxy(f.Func)
 	wasmlib.NewCallResultsProxy(f.Func, &f.Results.proxy)
 	return f
 }
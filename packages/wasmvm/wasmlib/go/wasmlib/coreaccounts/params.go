@@ -122,6 +122,16 @@ func (s ImmutableAccountNFTsParams) AgentID() wasmtypes.ScImmutableAgentID {
 	return wasmtypes.NewScImmutableAgentID(s.proxy.Root(ParamAgentID))
 }
 
+// cursor of the page, absent for the first page
+func (s ImmutableAccountNFTsParams) Cursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s ImmutableAccountNFTsParams) Limit() wasmtypes.ScImmutableUint32 {
+	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamLimit))
+}
+
 type MutableAccountNFTsParams struct {
 	proxy wasmtypes.Proxy
 }
@@ -130,6 +140,44 @@ func (s MutableAccountNFTsParams) AgentID() wasmtypes.ScMutableAgentID {
 	return wasmtypes.NewScMutableAgentID(s.proxy.Root(ParamAgentID))
 }
 
+// cursor of the page, absent for the first page
+func (s MutableAccountNFTsParams) Cursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s MutableAccountNFTsParams) Limit() wasmtypes.ScMutableUint32 {
+	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamLimit))
+}
+
+type ImmutableAccountsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s ImmutableAccountsParams) Cursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s ImmutableAccountsParams) Limit() wasmtypes.ScImmutableUint32 {
+	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamLimit))
+}
+
+type MutableAccountsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s MutableAccountsParams) Cursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s MutableAccountsParams) Limit() wasmtypes.ScMutableUint32 {
+	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamLimit))
+}
+
 type ImmutableBalanceParams struct {
 	proxy wasmtypes.Proxy
 }
@@ -42,6 +42,11 @@ type ImmutableAccountNFTsResults struct {
 	proxy wasmtypes.Proxy
 }
 
+// cursor of the next page, absent if there are no more elements
+func (s ImmutableAccountNFTsResults) NextCursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ResultNextCursor))
+}
+
 func (s ImmutableAccountNFTsResults) NftIDs() ArrayOfImmutableNftID {
 	return ArrayOfImmutableNftID{proxy: s.proxy.Root(ResultNftIDs)}
 }
@@ -70,6 +75,11 @@ type MutableAccountNFTsResults struct {
 	proxy wasmtypes.Proxy
 }
 
+// cursor of the next page, absent if there are no more elements
+func (s MutableAccountNFTsResults) NextCursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ResultNextCursor))
+}
+
 func (s MutableAccountNFTsResults) NftIDs() ArrayOfMutableNftID {
 	return ArrayOfMutableNftID{proxy: s.proxy.Root(ResultNftIDs)}
 }
@@ -87,7 +97,12 @@ type ImmutableAccountsResults struct {
 }
 
 func (s ImmutableAccountsResults) AllAccounts() MapAgentIDToImmutableBool {
-	return MapAgentIDToImmutableBool{proxy: s.proxy}
+	return MapAgentIDToImmutableBool{proxy: s.proxy.Root(ResultAllAccounts)}
+}
+
+// cursor of the next page, absent if there are no more elements
+func (s ImmutableAccountsResults) NextCursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ResultNextCursor))
 }
 
 type MapAgentIDToMutableBool struct {
@@ -107,7 +122,12 @@ type MutableAccountsResults struct {
 }
 
 func (s MutableAccountsResults) AllAccounts() MapAgentIDToMutableBool {
-	return MapAgentIDToMutableBool{proxy: s.proxy}
+	return MapAgentIDToMutableBool{proxy: s.proxy.Root(ResultAllAccounts)}
+}
+
+// cursor of the next page, absent if there are no more elements
+func (s MutableAccountsResults) NextCursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ResultNextCursor))
 }
 
 type MapTokenIDToImmutableBigInt struct {
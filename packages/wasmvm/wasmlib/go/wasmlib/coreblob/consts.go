@@ -18,17 +18,21 @@ const (
 
 const (
 	ParamBlobs       = "this"
+	ParamCursor      = "pc"
 	ParamDescription = "d"
 	ParamField       = "field"
 	ParamHash        = "hash"
+	ParamLimit       = "pl"
 	ParamProgBinary  = "p"
 	ParamVmType      = "v"
 )
 
 const (
-	ResultBlobSizes = "this"
-	ResultBytes     = "bytes"
-	ResultHash      = "hash"
+	ResultBlobSetSizes = "sizes"
+	ResultBlobSizes    = "this"
+	ResultBytes        = "bytes"
+	ResultHash         = "hash"
+	ResultNextCursor   = "pn"
 )
 
 const (
@@ -29,6 +29,7 @@ type GetBlobInfoCall struct {
 
 type ListBlobsCall struct {
 	Func    *wasmlib.ScView
+	Params  MutableListBlobsParams
 	Results ImmutableListBlobsResults
 }
 
@@ -59,6 +60,7 @@ func (sc Funcs) GetBlobInfo(ctx wasmlib.ScViewCallContext) *GetBlobInfoCall {
 
 func (sc Funcs) ListBlobs(ctx wasmlib.ScViewCallContext) *ListBlobsCall {
 	f := &ListBlobsCall{Func: wasmlib.NewScView(ctx, HScName, HViewListBlobs)}
+	f.Params.proxy = wasmlib.NewCallParamsProxy(f.Func)
 	wasmlib.NewCallResultsProxy(f.Func, &f.Results.proxy)
 	return f
 }
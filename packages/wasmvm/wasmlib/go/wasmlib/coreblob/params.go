@@ -123,3 +123,31 @@ type MutableGetBlobInfoParams struct {
 func (s MutableGetBlobInfoParams) Hash() wasmtypes.ScMutableHash {
 	return wasmtypes.NewScMutableHash(s.proxy.Root(ParamHash))
 }
+
+type ImmutableListBlobsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s ImmutableListBlobsParams) Cursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s ImmutableListBlobsParams) Limit() wasmtypes.ScImmutableUint32 {
+	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamLimit))
+}
+
+type MutableListBlobsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s MutableListBlobsParams) Cursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s MutableListBlobsParams) Limit() wasmtypes.ScMutableUint32 {
+	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamLimit))
+}
@@ -97,8 +97,13 @@ type ImmutableListBlobsResults struct {
 }
 
 // total size for each blob set
-func (s ImmutableListBlobsResults) BlobSizes() MapHashToImmutableInt32 {
-	return MapHashToImmutableInt32{proxy: s.proxy}
+func (s ImmutableListBlobsResults) BlobSetSizes() MapHashToImmutableInt32 {
+	return MapHashToImmutableInt32{proxy: s.proxy.Root(ResultBlobSetSizes)}
+}
+
+// cursor of the next page, absent if there are no more elements
+func (s ImmutableListBlobsResults) NextCursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ResultNextCursor))
 }
 
 type MapHashToMutableInt32 struct {
@@ -118,6 +123,11 @@ type MutableListBlobsResults struct {
 }
 
 // total size for each blob set
-func (s MutableListBlobsResults) BlobSizes() MapHashToMutableInt32 {
-	return MapHashToMutableInt32{proxy: s.proxy}
+func (s MutableListBlobsResults) BlobSetSizes() MapHashToMutableInt32 {
+	return MapHashToMutableInt32{proxy: s.proxy.Root(ResultBlobSetSizes)}
+}
+
+// cursor of the next page, absent if there are no more elements
+func (s MutableListBlobsResults) NextCursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ResultNextCursor))
 }
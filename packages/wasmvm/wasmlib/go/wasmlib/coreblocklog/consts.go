@@ -19,7 +19,9 @@ const (
 const (
 	ParamBlockIndex    = "n"
 	ParamContractHname = "h"
+	ParamCursor        = "pc"
 	ParamFromBlock     = "f"
+	ParamLimit         = "pl"
 	ParamRequestID     = "u"
 	ParamToBlock       = "t"
 )
@@ -29,6 +31,7 @@ const (
 	ResultBlockInfo              = "i"
 	ResultEvent                  = "e"
 	ResultGoverningAddress       = "g"
+	ResultNextCursor             = "pn"
 	ResultRequestID              = "u"
 	ResultRequestIndex           = "r"
 	ResultRequestProcessed       = "p"
@@ -50,10 +50,20 @@ func (s ImmutableGetEventsForContractParams) ContractHname() wasmtypes.ScImmutab
 	return wasmtypes.NewScImmutableHname(s.proxy.Root(ParamContractHname))
 }
 
+// cursor of the page, absent for the first page
+func (s ImmutableGetEventsForContractParams) Cursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ParamCursor))
+}
+
 func (s ImmutableGetEventsForContractParams) FromBlock() wasmtypes.ScImmutableUint32 {
 	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamFromBlock))
 }
 
+// maximum number of elements of the page, default 1000
+func (s ImmutableGetEventsForContractParams) Limit() wasmtypes.ScImmutableUint32 {
+	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamLimit))
+}
+
 func (s ImmutableGetEventsForContractParams) ToBlock() wasmtypes.ScImmutableUint32 {
 	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamToBlock))
 }
@@ -66,10 +76,20 @@ func (s MutableGetEventsForContractParams) ContractHname() wasmtypes.ScMutableHn
 	return wasmtypes.NewScMutableHname(s.proxy.Root(ParamContractHname))
 }
 
+// cursor of the page, absent for the first page
+func (s MutableGetEventsForContractParams) Cursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ParamCursor))
+}
+
 func (s MutableGetEventsForContractParams) FromBlock() wasmtypes.ScMutableUint32 {
 	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamFromBlock))
 }
 
+// maximum number of elements of the page, default 1000
+func (s MutableGetEventsForContractParams) Limit() wasmtypes.ScMutableUint32 {
+	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamLimit))
+}
+
 func (s MutableGetEventsForContractParams) ToBlock() wasmtypes.ScMutableUint32 {
 	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamToBlock))
 }
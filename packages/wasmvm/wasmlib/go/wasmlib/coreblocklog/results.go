@@ -127,6 +127,11 @@ func (s ImmutableGetEventsForContractResults) Event() ArrayOfImmutableBytes {
 	return ArrayOfImmutableBytes{proxy: s.proxy.Root(ResultEvent)}
 }
 
+// cursor of the next page, absent if there are no more elements
+func (s ImmutableGetEventsForContractResults) NextCursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ResultNextCursor))
+}
+
 type MutableGetEventsForContractResults struct {
 	proxy wasmtypes.Proxy
 }
@@ -136,6 +141,11 @@ func (s MutableGetEventsForContractResults) Event() ArrayOfMutableBytes {
 	return ArrayOfMutableBytes{proxy: s.proxy.Root(ResultEvent)}
 }
 
+// cursor of the next page, absent if there are no more elements
+func (s MutableGetEventsForContractResults) NextCursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ResultNextCursor))
+}
+
 type ImmutableGetEventsForRequestResults struct {
 	proxy wasmtypes.Proxy
 }
@@ -18,10 +18,12 @@ const (
 
 const (
 	ParamCloseFunc                = "bcc"
+	ParamCursor                   = "pc"
 	ParamDeployPermissionsEnabled = "de"
 	ParamDeployer                 = "dp"
 	ParamDescription              = "ds"
 	ParamHname                    = "hn"
+	ParamLimit                    = "pl"
 	ParamName                     = "nm"
 	ParamOpenFunc                 = "bco"
 	ParamProgramHash              = "ph"
@@ -31,6 +33,7 @@ const (
 	ResultContractFound    = "cf"
 	ResultContractRecData  = "dt"
 	ResultContractRegistry = "r"
+	ResultNextCursor       = "pn"
 )
 
 const (
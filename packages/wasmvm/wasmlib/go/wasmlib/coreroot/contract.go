@@ -42,6 +42,7 @@ type FindContractCall struct {
 
 type GetContractRecordsCall struct {
 	Func    *wasmlib.ScView
+	Params  MutableGetContractRecordsParams
 	Results ImmutableGetContractRecordsResults
 }
 
@@ -88,6 +89,7 @@ func (sc Funcs) FindContract(ctx wasmlib.ScViewCallContext) *FindContractCall {
 
 func (sc Funcs) GetContractRecords(ctx wasmlib.ScViewCallContext) *GetContractRecordsCall {
 	f := &GetContractRecordsCall{Func: wasmlib.NewScView(ctx, HScName, HViewGetContractRecords)}
+	f.Params.proxy = wasmlib.NewCallParamsProxy(f.Func)
 	wasmlib.NewCallResultsProxy(f.Func, &f.Results.proxy)
 	return f
 }
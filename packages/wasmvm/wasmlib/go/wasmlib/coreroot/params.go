@@ -133,3 +133,31 @@ type MutableFindContractParams struct {
 func (s MutableFindContractParams) Hname() wasmtypes.ScMutableHname {
 	return wasmtypes.NewScMutableHname(s.proxy.Root(ParamHname))
 }
+
+type ImmutableGetContractRecordsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s ImmutableGetContractRecordsParams) Cursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s ImmutableGetContractRecordsParams) Limit() wasmtypes.ScImmutableUint32 {
+	return wasmtypes.NewScImmutableUint32(s.proxy.Root(ParamLimit))
+}
+
+type MutableGetContractRecordsParams struct {
+	proxy wasmtypes.Proxy
+}
+
+// cursor of the page, absent for the first page
+func (s MutableGetContractRecordsParams) Cursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ParamCursor))
+}
+
+// maximum number of elements of the page, default 1000
+func (s MutableGetContractRecordsParams) Limit() wasmtypes.ScMutableUint32 {
+	return wasmtypes.NewScMutableUint32(s.proxy.Root(ParamLimit))
+}
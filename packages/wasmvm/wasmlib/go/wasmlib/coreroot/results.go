@@ -55,6 +55,11 @@ func (s ImmutableGetContractRecordsResults) ContractRegistry() MapHnameToImmutab
 	return MapHnameToImmutableBytes{proxy: s.proxy.Root(ResultContractRegistry)}
 }
 
+// cursor of the next page, absent if there are no more elements
+func (s ImmutableGetContractRecordsResults) NextCursor() wasmtypes.ScImmutableBytes {
+	return wasmtypes.NewScImmutableBytes(s.proxy.Root(ResultNextCursor))
+}
+
 type MapHnameToMutableBytes struct {
 	proxy wasmtypes.Proxy
 }
@@ -75,3 +80,8 @@ type MutableGetContractRecordsResults struct {
 func (s MutableGetContractRecordsResults) ContractRegistry() MapHnameToMutableBytes {
 	return MapHnameToMutableBytes{proxy: s.proxy.Root(ResultContractRegistry)}
 }
+
+// cursor of the next page, absent if there are no more elements
+func (s MutableGetContractRecordsResults) NextCursor() wasmtypes.ScMutableBytes {
+	return wasmtypes.NewScMutableBytes(s.proxy.Root(ResultNextCursor))
+}
@@ -14,15 +14,20 @@ const (
 	ScAgentIDAddress  byte = 1
 	ScAgentIDContract byte = 2
 	ScAgentIDEthereum byte = 3
+	ScAgentIDMultisig byte = 4
 )
 
 type ScAgentID struct {
-	kind    byte
-	address ScAddress
-	hname   ScHname
+	kind     byte
+	address  ScAddress
+	hname    ScHname
+	multisig ScHash
 }
 
-const nilAgentIDString = "-"
+const (
+	nilAgentIDString      = "-"
+	multisigAgentIDPrefix = "multisig:"
+)
 
 func NewScAgentID(address ScAddress, hname ScHname) ScAgentID {
 	return ScAgentID{kind: ScAgentIDContract, address: address, hname: hname}
@@ -39,6 +44,12 @@ func NewScAgentIDFromAddress(address ScAddress) ScAgentID {
 	}
 }
 
+// NewScAgentIDMultisig returns the agent id of the multisig account with the id,
+// which is the hash of the policy the account was registered with
+func NewScAgentIDMultisig(id ScHash) ScAgentID {
+	return ScAgentID{kind: ScAgentIDMultisig, multisig: id}
+}
+
 func (o ScAgentID) Address() ScAddress {
 	return o.address
 }
@@ -59,6 +70,15 @@ func (o ScAgentID) IsContract() bool {
 	return o.kind == ScAgentIDContract
 }
 
+func (o ScAgentID) IsMultisig() bool {
+	return o.kind == ScAgentIDMultisig
+}
+
+// MultisigID returns the id of the multisig account, the zero hash for the other kinds
+func (o ScAgentID) MultisigID() ScHash {
+	return o.multisig
+}
+
 func (o ScAgentID) String() string {
 	return AgentIDToString(o)
 }
@@ -96,6 +116,11 @@ func AgentIDFromBytes(buf []byte) (a ScAgentID) {
 			panic("invalid AgentID length: eth agentID")
 		}
 		a.address = AddressFromBytes(buf)
+	case ScAgentIDMultisig:
+		if len(buf) != ScHashLength {
+			panic("invalid AgentID length: multisig agentID")
+		}
+		a.multisig = HashFromBytes(buf)
 	case ScAgentIDNil:
 		break
 	default:
@@ -114,6 +139,8 @@ func AgentIDToBytes(value ScAgentID) []byte {
 		return append(buf, HnameToBytes(value.hname)...)
 	case ScAgentIDEthereum:
 		return append(buf, AddressToBytes(value.address)...)
+	case ScAgentIDMultisig:
+		return append(buf, HashToBytes(value.multisig)...)
 	case ScAgentIDNil:
 		return buf
 	default:
@@ -125,6 +152,9 @@ func AgentIDFromString(value string) ScAgentID {
 	if value == nilAgentIDString {
 		return ScAgentID{}
 	}
+	if strings.HasPrefix(value, multisigAgentIDPrefix) {
+		return NewScAgentIDMultisig(HashFromString(value[len(multisigAgentIDPrefix):]))
+	}
 
 	parts := strings.Split(value, "@")
 	switch len(parts) {
@@ -145,6 +175,8 @@ func AgentIDToString(value ScAgentID) string {
 		return HnameToString(value.Hname()) + "@" + AddressToString(value.Address())
 	case ScAgentIDEthereum:
 		return AddressToString(value.Address())
+	case ScAgentIDMultisig:
+		return multisigAgentIDPrefix + HashToString(value.multisig)
 	case ScAgentIDNil:
 		// isc.NilAgentID.String() returns "-" which means NilAgentID is "-"
 		return nilAgentIDString
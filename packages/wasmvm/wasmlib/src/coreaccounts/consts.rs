@@ -14,21 +14,24 @@ pub const SC_DESCRIPTION : &str = "Chain account ledger contract";
 pub const HSC_NAME       : ScHname = ScHname(0x3c4b5e02);
 
 pub(crate) const PARAM_AGENT_ID                  : &str = "a";
+pub(crate) const PARAM_CURSOR                    : &str = "pc";
 pub(crate) const PARAM_DESTROY_TOKENS            : &str = "y";
 pub(crate) const PARAM_FORCE_MINIMUM_BASE_TOKENS : &str = "f";
 pub(crate) const PARAM_FORCE_OPEN_ACCOUNT        : &str = "c";
 pub(crate) const PARAM_FOUNDRY_SN                : &str = "s";
+pub(crate) const PARAM_LIMIT                     : &str = "pl";
 pub(crate) const PARAM_NFT_ID                    : &str = "z";
 pub(crate) const PARAM_SUPPLY_DELTA_ABS          : &str = "d";
 pub(crate) const PARAM_TOKEN_SCHEME              : &str = "t";
 
 pub(crate) const RESULT_ACCOUNT_NONCE      : &str = "n";
-pub(crate) const RESULT_ALL_ACCOUNTS       : &str = "this";
+pub(crate) const RESULT_ALL_ACCOUNTS       : &str = "A";
 pub(crate) const RESULT_ASSETS             : &str = "this";
 pub(crate) const RESULT_BALANCES           : &str = "this";
 pub(crate) const RESULT_FOUNDRY_OUTPUT_BIN : &str = "b";
 pub(crate) const RESULT_FOUNDRY_SN         : &str = "s";
 pub(crate) const RESULT_MAPPING            : &str = "this";
+pub(crate) const RESULT_NEXT_CURSOR        : &str = "pn";
 pub(crate) const RESULT_NFT_DATA           : &str = "e";
 pub(crate) const RESULT_NFT_I_DS           : &str = "i";
 
@@ -52,6 +52,7 @@ pub struct AccountNFTsCall {
 
 pub struct AccountsCall {
 	pub func: ScView,
+	pub params: MutableAccountsParams,
 	pub results: ImmutableAccountsResults,
 }
 
@@ -166,8 +167,10 @@ impl ScFuncs {
     pub fn accounts(_ctx: &dyn ScViewCallContext) -> AccountsCall {
         let mut f = AccountsCall {
             func: ScView::new(HSC_NAME, HVIEW_ACCOUNTS),
+            params: MutableAccountsParams { proxy: Proxy::nil() },
             results: ImmutableAccountsResults { proxy: Proxy::nil() },
         };
+        ScView::link_params(&mut f.params.proxy, &f.func);
         ScView::link_results(&mut f.results.proxy, &f.func);
         f
     }
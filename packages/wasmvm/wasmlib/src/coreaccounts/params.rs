@@ -154,6 +154,16 @@ impl ImmutableAccountNFTsParams {
     pub fn agent_id(&self) -> ScImmutableAgentID {
 		ScImmutableAgentID::new(self.proxy.root(PARAM_AGENT_ID))
 	}
+
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScImmutableUint32 {
+		ScImmutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
 }
 
 #[derive(Clone)]
@@ -165,6 +175,50 @@ impl MutableAccountNFTsParams {
     pub fn agent_id(&self) -> ScMutableAgentID {
 		ScMutableAgentID::new(self.proxy.root(PARAM_AGENT_ID))
 	}
+
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScMutableUint32 {
+		ScMutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
+
+#[derive(Clone)]
+pub struct ImmutableAccountsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl ImmutableAccountsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScImmutableUint32 {
+		ScImmutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
+
+#[derive(Clone)]
+pub struct MutableAccountsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl MutableAccountsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScMutableUint32 {
+		ScMutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
 }
 
 #[derive(Clone)]
@@ -54,6 +54,11 @@ pub struct ImmutableAccountNFTsResults {
 }
 
 impl ImmutableAccountNFTsResults {
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
+
     pub fn nft_i_ds(&self) -> ArrayOfImmutableNftID {
 		ArrayOfImmutableNftID { proxy: self.proxy.root(RESULT_NFT_I_DS) }
 	}
@@ -88,6 +93,11 @@ pub struct MutableAccountNFTsResults {
 }
 
 impl MutableAccountNFTsResults {
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
+
     pub fn nft_i_ds(&self) -> ArrayOfMutableNftID {
 		ArrayOfMutableNftID { proxy: self.proxy.root(RESULT_NFT_I_DS) }
 	}
@@ -111,7 +121,12 @@ pub struct ImmutableAccountsResults {
 
 impl ImmutableAccountsResults {
     pub fn all_accounts(&self) -> MapAgentIDToImmutableBool {
-		MapAgentIDToImmutableBool { proxy: self.proxy.clone() }
+		MapAgentIDToImmutableBool { proxy: self.proxy.root(RESULT_ALL_ACCOUNTS) }
+	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
 	}
 }
 
@@ -137,7 +152,12 @@ pub struct MutableAccountsResults {
 
 impl MutableAccountsResults {
     pub fn all_accounts(&self) -> MapAgentIDToMutableBool {
-		MapAgentIDToMutableBool { proxy: self.proxy.clone() }
+		MapAgentIDToMutableBool { proxy: self.proxy.root(RESULT_ALL_ACCOUNTS) }
+	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
 	}
 }
 
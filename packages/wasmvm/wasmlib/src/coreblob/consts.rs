@@ -14,15 +14,19 @@ pub const SC_DESCRIPTION : &str = "Blob Contract";
 pub const HSC_NAME       : ScHname = ScHname(0xfd91bc63);
 
 pub(crate) const PARAM_BLOBS       : &str = "this";
+pub(crate) const PARAM_CURSOR      : &str = "pc";
 pub(crate) const PARAM_DESCRIPTION : &str = "d";
 pub(crate) const PARAM_FIELD       : &str = "field";
 pub(crate) const PARAM_HASH        : &str = "hash";
+pub(crate) const PARAM_LIMIT       : &str = "pl";
 pub(crate) const PARAM_PROG_BINARY : &str = "p";
 pub(crate) const PARAM_VM_TYPE     : &str = "v";
 
-pub(crate) const RESULT_BLOB_SIZES : &str = "this";
-pub(crate) const RESULT_BYTES      : &str = "bytes";
-pub(crate) const RESULT_HASH       : &str = "hash";
+pub(crate) const RESULT_BLOB_SET_SIZES : &str = "sizes";
+pub(crate) const RESULT_BLOB_SIZES     : &str = "this";
+pub(crate) const RESULT_BYTES          : &str = "bytes";
+pub(crate) const RESULT_HASH           : &str = "hash";
+pub(crate) const RESULT_NEXT_CURSOR    : &str = "pn";
 
 pub(crate) const FUNC_STORE_BLOB     : &str = "storeBlob";
 pub(crate) const VIEW_GET_BLOB_FIELD : &str = "getBlobField";
@@ -30,6 +30,7 @@ pub struct GetBlobInfoCall {
 
 pub struct ListBlobsCall {
 	pub func: ScView,
+	pub params: MutableListBlobsParams,
 	pub results: ImmutableListBlobsResults,
 }
 
@@ -73,8 +74,10 @@ impl ScFuncs {
     pub fn list_blobs(_ctx: &dyn ScViewCallContext) -> ListBlobsCall {
         let mut f = ListBlobsCall {
             func: ScView::new(HSC_NAME, HVIEW_LIST_BLOBS),
+            params: MutableListBlobsParams { proxy: Proxy::nil() },
             results: ImmutableListBlobsResults { proxy: Proxy::nil() },
         };
+        ScView::link_params(&mut f.params.proxy, &f.func);
         ScView::link_results(&mut f.results.proxy, &f.func);
         f
     }
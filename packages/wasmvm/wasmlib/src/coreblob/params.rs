@@ -148,3 +148,37 @@ impl MutableGetBlobInfoParams {
 		ScMutableHash::new(self.proxy.root(PARAM_HASH))
 	}
 }
+
+#[derive(Clone)]
+pub struct ImmutableListBlobsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl ImmutableListBlobsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScImmutableUint32 {
+		ScImmutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
+
+#[derive(Clone)]
+pub struct MutableListBlobsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl MutableListBlobsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScMutableUint32 {
+		ScMutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
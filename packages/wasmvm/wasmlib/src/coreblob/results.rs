@@ -127,8 +127,13 @@ pub struct ImmutableListBlobsResults {
 
 impl ImmutableListBlobsResults {
     // total size for each blob set
-    pub fn blob_sizes(&self) -> MapHashToImmutableInt32 {
-		MapHashToImmutableInt32 { proxy: self.proxy.clone() }
+    pub fn blob_set_sizes(&self) -> MapHashToImmutableInt32 {
+		MapHashToImmutableInt32 { proxy: self.proxy.root(RESULT_BLOB_SET_SIZES) }
+	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
 	}
 }
 
@@ -154,7 +159,12 @@ pub struct MutableListBlobsResults {
 
 impl MutableListBlobsResults {
     // total size for each blob set
-    pub fn blob_sizes(&self) -> MapHashToMutableInt32 {
-		MapHashToMutableInt32 { proxy: self.proxy.clone() }
+    pub fn blob_set_sizes(&self) -> MapHashToMutableInt32 {
+		MapHashToMutableInt32 { proxy: self.proxy.root(RESULT_BLOB_SET_SIZES) }
+	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
 	}
 }
@@ -15,7 +15,9 @@ pub const HSC_NAME       : ScHname = ScHname(0xf538ef2b);
 
 pub(crate) const PARAM_BLOCK_INDEX    : &str = "n";
 pub(crate) const PARAM_CONTRACT_HNAME : &str = "h";
+pub(crate) const PARAM_CURSOR         : &str = "pc";
 pub(crate) const PARAM_FROM_BLOCK     : &str = "f";
+pub(crate) const PARAM_LIMIT          : &str = "pl";
 pub(crate) const PARAM_REQUEST_ID     : &str = "u";
 pub(crate) const PARAM_TO_BLOCK       : &str = "t";
 
@@ -23,6 +25,7 @@ pub(crate) const RESULT_BLOCK_INDEX              : &str = "n";
 pub(crate) const RESULT_BLOCK_INFO               : &str = "i";
 pub(crate) const RESULT_EVENT                    : &str = "e";
 pub(crate) const RESULT_GOVERNING_ADDRESS        : &str = "g";
+pub(crate) const RESULT_NEXT_CURSOR              : &str = "pn";
 pub(crate) const RESULT_REQUEST_ID               : &str = "u";
 pub(crate) const RESULT_REQUEST_INDEX            : &str = "r";
 pub(crate) const RESULT_REQUEST_PROCESSED        : &str = "p";
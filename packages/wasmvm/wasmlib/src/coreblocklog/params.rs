@@ -65,10 +65,20 @@ impl ImmutableGetEventsForContractParams {
 		ScImmutableHname::new(self.proxy.root(PARAM_CONTRACT_HNAME))
 	}
 
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
     pub fn from_block(&self) -> ScImmutableUint32 {
 		ScImmutableUint32::new(self.proxy.root(PARAM_FROM_BLOCK))
 	}
 
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScImmutableUint32 {
+		ScImmutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+
     pub fn to_block(&self) -> ScImmutableUint32 {
 		ScImmutableUint32::new(self.proxy.root(PARAM_TO_BLOCK))
 	}
@@ -84,10 +94,20 @@ impl MutableGetEventsForContractParams {
 		ScMutableHname::new(self.proxy.root(PARAM_CONTRACT_HNAME))
 	}
 
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
     pub fn from_block(&self) -> ScMutableUint32 {
 		ScMutableUint32::new(self.proxy.root(PARAM_FROM_BLOCK))
 	}
 
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScMutableUint32 {
+		ScMutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+
     pub fn to_block(&self) -> ScMutableUint32 {
 		ScMutableUint32::new(self.proxy.root(PARAM_TO_BLOCK))
 	}
@@ -153,6 +153,11 @@ impl ImmutableGetEventsForContractResults {
     pub fn event(&self) -> ArrayOfImmutableBytes {
 		ArrayOfImmutableBytes { proxy: self.proxy.root(RESULT_EVENT) }
 	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
 }
 
 #[derive(Clone)]
@@ -165,6 +170,11 @@ impl MutableGetEventsForContractResults {
     pub fn event(&self) -> ArrayOfMutableBytes {
 		ArrayOfMutableBytes { proxy: self.proxy.root(RESULT_EVENT) }
 	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
 }
 
 #[derive(Clone)]
@@ -14,10 +14,12 @@ pub const SC_DESCRIPTION : &str = "Root Contract";
 pub const HSC_NAME       : ScHname = ScHname(0xcebf5908);
 
 pub(crate) const PARAM_CLOSE_FUNC                 : &str = "bcc";
+pub(crate) const PARAM_CURSOR                     : &str = "pc";
 pub(crate) const PARAM_DEPLOY_PERMISSIONS_ENABLED : &str = "de";
 pub(crate) const PARAM_DEPLOYER                   : &str = "dp";
 pub(crate) const PARAM_DESCRIPTION                : &str = "ds";
 pub(crate) const PARAM_HNAME                      : &str = "hn";
+pub(crate) const PARAM_LIMIT                      : &str = "pl";
 pub(crate) const PARAM_NAME                       : &str = "nm";
 pub(crate) const PARAM_OPEN_FUNC                  : &str = "bco";
 pub(crate) const PARAM_PROGRAM_HASH               : &str = "ph";
@@ -25,6 +27,7 @@ pub(crate) const PARAM_PROGRAM_HASH               : &str = "ph";
 pub(crate) const RESULT_CONTRACT_FOUND    : &str = "cf";
 pub(crate) const RESULT_CONTRACT_REC_DATA : &str = "dt";
 pub(crate) const RESULT_CONTRACT_REGISTRY : &str = "r";
+pub(crate) const RESULT_NEXT_CURSOR       : &str = "pn";
 
 pub(crate) const FUNC_DEPLOY_CONTRACT            : &str = "deployContract";
 pub(crate) const FUNC_GRANT_DEPLOY_PERMISSION    : &str = "grantDeployPermission";
@@ -43,6 +43,7 @@ pub struct FindContractCall {
 
 pub struct GetContractRecordsCall {
 	pub func: ScView,
+	pub params: MutableGetContractRecordsParams,
 	pub results: ImmutableGetContractRecordsResults,
 }
 
@@ -109,8 +110,10 @@ impl ScFuncs {
     pub fn get_contract_records(_ctx: &dyn ScViewCallContext) -> GetContractRecordsCall {
         let mut f = GetContractRecordsCall {
             func: ScView::new(HSC_NAME, HVIEW_GET_CONTRACT_RECORDS),
+            params: MutableGetContractRecordsParams { proxy: Proxy::nil() },
             results: ImmutableGetContractRecordsResults { proxy: Proxy::nil() },
         };
+        ScView::link_params(&mut f.params.proxy, &f.func);
         ScView::link_results(&mut f.results.proxy, &f.func);
         f
     }
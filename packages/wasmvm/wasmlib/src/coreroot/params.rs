@@ -170,3 +170,37 @@ impl MutableFindContractParams {
 		ScMutableHname::new(self.proxy.root(PARAM_HNAME))
 	}
 }
+
+#[derive(Clone)]
+pub struct ImmutableGetContractRecordsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl ImmutableGetContractRecordsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScImmutableUint32 {
+		ScImmutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
+
+#[derive(Clone)]
+pub struct MutableGetContractRecordsParams {
+	pub(crate) proxy: Proxy,
+}
+
+impl MutableGetContractRecordsParams {
+    // cursor of the page, absent for the first page
+    pub fn cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(PARAM_CURSOR))
+	}
+
+    // maximum number of elements of the page, default 1000
+    pub fn limit(&self) -> ScMutableUint32 {
+		ScMutableUint32::new(self.proxy.root(PARAM_LIMIT))
+	}
+}
@@ -66,6 +66,11 @@ impl ImmutableGetContractRecordsResults {
     pub fn contract_registry(&self) -> MapHnameToImmutableBytes {
 		MapHnameToImmutableBytes { proxy: self.proxy.root(RESULT_CONTRACT_REGISTRY) }
 	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScImmutableBytes {
+		ScImmutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
 }
 
 #[derive(Clone)]
@@ -93,4 +98,9 @@ impl MutableGetContractRecordsResults {
     pub fn contract_registry(&self) -> MapHnameToMutableBytes {
 		MapHnameToMutableBytes { proxy: self.proxy.root(RESULT_CONTRACT_REGISTRY) }
 	}
+
+    // cursor of the next page, absent if there are no more elements
+    pub fn next_cursor(&self) -> ScMutableBytes {
+		ScMutableBytes::new(self.proxy.root(RESULT_NEXT_CURSOR))
+	}
 }
@@ -11,13 +11,16 @@ pub const SC_AGENT_ID_NIL: u8 = 0;
 pub const SC_AGENT_ID_ADDRESS: u8 = 1;
 pub const SC_AGENT_ID_CONTRACT: u8 = 2;
 pub const SC_AGENT_ID_ETHEREUM: u8 = 3;
+pub const SC_AGENT_ID_MULTISIG: u8 = 4;
 const NIL_AGENT_ID_STRING: &str = "-";
+const MULTISIG_AGENT_ID_PREFIX: &str = "multisig:";
 
 #[derive(PartialEq, Clone)]
 pub struct ScAgentID {
     kind: u8,
     address: ScAddress,
     hname: ScHname,
+    multisig: ScHash,
 }
 
 impl ScAgentID {
@@ -26,6 +29,18 @@ impl ScAgentID {
             kind: SC_AGENT_ID_CONTRACT,
             address: address.clone(),
             hname: hname,
+            multisig: hash_from_bytes(&[]),
+        }
+    }
+
+    // agent id of the multisig account with the id,
+    // which is the hash of the policy the account was registered with
+    pub fn from_multisig(id: &ScHash) -> ScAgentID {
+        ScAgentID {
+            kind: SC_AGENT_ID_MULTISIG,
+            address: address_from_bytes(&[]),
+            hname: ScHname(0),
+            multisig: id.clone(),
         }
     }
 
@@ -47,6 +62,7 @@ impl ScAgentID {
             kind: kind,
             address: address.clone(),
             hname: ScHname(0),
+            multisig: hash_from_bytes(&[]),
         }
     }
 
@@ -66,6 +82,15 @@ impl ScAgentID {
         self.kind == SC_AGENT_ID_CONTRACT
     }
 
+    pub fn is_multisig(&self) -> bool {
+        self.kind == SC_AGENT_ID_MULTISIG
+    }
+
+    // id of the multisig account, the zero hash for the other kinds
+    pub fn multisig_id(&self) -> ScHash {
+        self.multisig.clone()
+    }
+
     pub fn to_bytes(&self) -> Vec<u8> {
         agent_id_to_bytes(self)
     }
@@ -91,6 +116,7 @@ pub fn agent_id_from_bytes(buf: &[u8]) -> ScAgentID {
             kind: SC_AGENT_ID_NIL,
             address: address_from_bytes(buf),
             hname: ScHname(0),
+            multisig: hash_from_bytes(&[]),
         };
     }
     match buf[0] {
@@ -117,6 +143,13 @@ pub fn agent_id_from_bytes(buf: &[u8]) -> ScAgentID {
             }
             return ScAgentID::from_address(&address_from_bytes(&buf));
         }
+        SC_AGENT_ID_MULTISIG => {
+            let buf: &[u8] = &buf[1..];
+            if buf.len() != SC_HASH_LENGTH {
+                panic("invalid AgentID length: multisig agentID");
+            }
+            return ScAgentID::from_multisig(&hash_from_bytes(&buf));
+        }
         SC_AGENT_ID_NIL => {}
         _ => panic("AgentIDFromBytes: invalid AgentID type"),
     }
@@ -124,6 +157,7 @@ pub fn agent_id_from_bytes(buf: &[u8]) -> ScAgentID {
         kind: SC_AGENT_ID_NIL,
         address: address_from_bytes(&[]),
         hname: ScHname(0),
+        multisig: hash_from_bytes(&[]),
     }
 }
 
@@ -141,6 +175,9 @@ pub fn agent_id_to_bytes(value: &ScAgentID) -> Vec<u8> {
         SC_AGENT_ID_ETHEREUM => {
             buf.extend_from_slice(&address_to_bytes(&value.address));
         }
+        SC_AGENT_ID_MULTISIG => {
+            buf.extend_from_slice(&hash_to_bytes(&value.multisig));
+        }
         SC_AGENT_ID_NIL => (),
         _ => panic("AgentIDToBytes: invalid AgentID type"),
     }
@@ -151,6 +188,9 @@ pub fn agent_id_from_string(value: &str) -> ScAgentID {
     if value.eq(NIL_AGENT_ID_STRING) {
         return agent_id_from_bytes(&[]);
     }
+    if value.starts_with(MULTISIG_AGENT_ID_PREFIX) {
+        return ScAgentID::from_multisig(&hash_from_string(&value[MULTISIG_AGENT_ID_PREFIX.len()..]));
+    }
 
     let parts: Vec<&str> = value.split("@").collect();
     match parts.len() {
@@ -179,6 +219,9 @@ pub fn agent_id_to_string(value: &ScAgentID) -> String {
         SC_AGENT_ID_ETHEREUM => {
             return value.address().to_string();
         }
+        SC_AGENT_ID_MULTISIG => {
+            return MULTISIG_AGENT_ID_PREFIX.to_string() + &value.multisig_id().to_string();
+        }
         SC_AGENT_ID_NIL => {
             return NIL_AGENT_ID_STRING.to_string();
         }
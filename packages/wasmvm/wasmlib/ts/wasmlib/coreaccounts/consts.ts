@@ -12,21 +12,24 @@ export const ScDescription = "Chain account ledger contract";
 export const HScName       = new wasmtypes.ScHname(0x3c4b5e02);
 
 export const ParamAgentID                = "a";
+export const ParamCursor                 = "pc";
 export const ParamDestroyTokens          = "y";
 export const ParamForceMinimumBaseTokens = "f";
 export const ParamForceOpenAccount       = "c";
 export const ParamFoundrySN              = "s";
+export const ParamLimit                  = "pl";
 export const ParamNftID                  = "z";
 export const ParamSupplyDeltaAbs         = "d";
 export const ParamTokenScheme            = "t";
 
 export const ResultAccountNonce     = "n";
-export const ResultAllAccounts      = "this";
+export const ResultAllAccounts      = "A";
 export const ResultAssets           = "this";
 export const ResultBalances         = "this";
 export const ResultFoundryOutputBin = "b";
 export const ResultFoundrySN        = "s";
 export const ResultMapping          = "this";
+export const ResultNextCursor       = "pn";
 export const ResultNftData          = "e";
 export const ResultNftIDs           = "i";
 
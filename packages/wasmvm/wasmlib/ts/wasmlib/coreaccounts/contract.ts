@@ -74,6 +74,7 @@ export class AccountNFTsCall {
 
 export class AccountsCall {
 	func: wasmlib.ScView;
+	params: sc.MutableAccountsParams = new sc.MutableAccountsParams(wasmlib.ScView.nilProxy);
 	results: sc.ImmutableAccountsResults = new sc.ImmutableAccountsResults(wasmlib.ScView.nilProxy);
 	public constructor(ctx: wasmlib.ScViewCallContext) {
 		this.func = new wasmlib.ScView(ctx, sc.HScName, sc.HViewAccounts);
@@ -181,6 +182,7 @@ export class ScFuncs {
 
 	static accounts(ctx: wasmlib.ScViewCallContext): AccountsCall {
 		const f = new AccountsCall(ctx);
+		f.params = new sc.MutableAccountsParams(wasmlib.newCallParamsProxy(f.func));
 		f.results = new sc.ImmutableAccountsResults(wasmlib.newCallResultsProxy(f.func));
 		return f;
 	}
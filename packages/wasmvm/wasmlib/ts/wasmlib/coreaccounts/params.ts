@@ -96,12 +96,56 @@ export class ImmutableAccountNFTsParams extends wasmtypes.ScProxy {
 	agentID(): wasmtypes.ScImmutableAgentID {
 		return new wasmtypes.ScImmutableAgentID(this.proxy.root(sc.ParamAgentID));
 	}
+
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScImmutableUint32 {
+		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamLimit));
+	}
 }
 
 export class MutableAccountNFTsParams extends wasmtypes.ScProxy {
 	agentID(): wasmtypes.ScMutableAgentID {
 		return new wasmtypes.ScMutableAgentID(this.proxy.root(sc.ParamAgentID));
 	}
+
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScMutableUint32 {
+		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+}
+
+export class ImmutableAccountsParams extends wasmtypes.ScProxy {
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScImmutableUint32 {
+		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+}
+
+export class MutableAccountsParams extends wasmtypes.ScProxy {
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScMutableUint32 {
+		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamLimit));
+	}
 }
 
 export class ImmutableBalanceParams extends wasmtypes.ScProxy {
@@ -32,6 +32,11 @@ export class ArrayOfImmutableNftID extends wasmtypes.ScProxy {
 }
 
 export class ImmutableAccountNFTsResults extends wasmtypes.ScProxy {
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
+
 	nftIDs(): sc.ArrayOfImmutableNftID {
 		return new sc.ArrayOfImmutableNftID(this.proxy.root(sc.ResultNftIDs));
 	}
@@ -57,6 +62,11 @@ export class ArrayOfMutableNftID extends wasmtypes.ScProxy {
 }
 
 export class MutableAccountNFTsResults extends wasmtypes.ScProxy {
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
+
 	nftIDs(): sc.ArrayOfMutableNftID {
 		return new sc.ArrayOfMutableNftID(this.proxy.root(sc.ResultNftIDs));
 	}
@@ -71,7 +81,12 @@ export class MapAgentIDToImmutableBool extends wasmtypes.ScProxy {
 
 export class ImmutableAccountsResults extends wasmtypes.ScProxy {
 	allAccounts(): sc.MapAgentIDToImmutableBool {
-		return new sc.MapAgentIDToImmutableBool(this.proxy);
+		return new sc.MapAgentIDToImmutableBool(this.proxy.root(sc.ResultAllAccounts));
+	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ResultNextCursor));
 	}
 }
 
@@ -88,7 +103,12 @@ export class MapAgentIDToMutableBool extends wasmtypes.ScProxy {
 
 export class MutableAccountsResults extends wasmtypes.ScProxy {
 	allAccounts(): sc.MapAgentIDToMutableBool {
-		return new sc.MapAgentIDToMutableBool(this.proxy);
+		return new sc.MapAgentIDToMutableBool(this.proxy.root(sc.ResultAllAccounts));
+	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ResultNextCursor));
 	}
 }
 
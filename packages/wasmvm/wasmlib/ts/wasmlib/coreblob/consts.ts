@@ -12,15 +12,19 @@ export const ScDescription = "Blob Contract";
 export const HScName       = new wasmtypes.ScHname(0xfd91bc63);
 
 export const ParamBlobs       = "this";
+export const ParamCursor      = "pc";
 export const ParamDescription = "d";
 export const ParamField       = "field";
 export const ParamHash        = "hash";
+export const ParamLimit       = "pl";
 export const ParamProgBinary  = "p";
 export const ParamVmType      = "v";
 
-export const ResultBlobSizes = "this";
-export const ResultBytes     = "bytes";
-export const ResultHash      = "hash";
+export const ResultBlobSetSizes = "sizes";
+export const ResultBlobSizes    = "this";
+export const ResultBytes        = "bytes";
+export const ResultHash         = "hash";
+export const ResultNextCursor   = "pn";
 
 export const FuncStoreBlob    = "storeBlob";
 export const ViewGetBlobField = "getBlobField";
@@ -37,6 +37,7 @@ export class GetBlobInfoCall {
 
 export class ListBlobsCall {
 	func: wasmlib.ScView;
+	params: sc.MutableListBlobsParams = new sc.MutableListBlobsParams(wasmlib.ScView.nilProxy);
 	results: sc.ImmutableListBlobsResults = new sc.ImmutableListBlobsResults(wasmlib.ScView.nilProxy);
 	public constructor(ctx: wasmlib.ScViewCallContext) {
 		this.func = new wasmlib.ScView(ctx, sc.HScName, sc.HViewListBlobs);
@@ -67,6 +68,7 @@ export class ScFuncs {
 
 	static listBlobs(ctx: wasmlib.ScViewCallContext): ListBlobsCall {
 		const f = new ListBlobsCall(ctx);
+		f.params = new sc.MutableListBlobsParams(wasmlib.newCallParamsProxy(f.func));
 		f.results = new sc.ImmutableListBlobsResults(wasmlib.newCallResultsProxy(f.func));
 		return f;
 	}
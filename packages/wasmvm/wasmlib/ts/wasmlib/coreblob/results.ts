@@ -77,8 +77,13 @@ export class MapHashToImmutableInt32 extends wasmtypes.ScProxy {
 
 export class ImmutableListBlobsResults extends wasmtypes.ScProxy {
 	// total size for each blob set
-	blobSizes(): sc.MapHashToImmutableInt32 {
-		return new sc.MapHashToImmutableInt32(this.proxy);
+	blobSetSizes(): sc.MapHashToImmutableInt32 {
+		return new sc.MapHashToImmutableInt32(this.proxy.root(sc.ResultBlobSetSizes));
+	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ResultNextCursor));
 	}
 }
 
@@ -95,7 +100,12 @@ export class MapHashToMutableInt32 extends wasmtypes.ScProxy {
 
 export class MutableListBlobsResults extends wasmtypes.ScProxy {
 	// total size for each blob set
-	blobSizes(): sc.MapHashToMutableInt32 {
-		return new sc.MapHashToMutableInt32(this.proxy);
+	blobSetSizes(): sc.MapHashToMutableInt32 {
+		return new sc.MapHashToMutableInt32(this.proxy.root(sc.ResultBlobSetSizes));
+	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ResultNextCursor));
 	}
 }
@@ -13,7 +13,9 @@ export const HScName       = new wasmtypes.ScHname(0xf538ef2b);
 
 export const ParamBlockIndex    = "n";
 export const ParamContractHname = "h";
+export const ParamCursor        = "pc";
 export const ParamFromBlock     = "f";
+export const ParamLimit         = "pl";
 export const ParamRequestID     = "u";
 export const ParamToBlock       = "t";
 
@@ -21,6 +23,7 @@ export const ResultBlockIndex             = "n";
 export const ResultBlockInfo              = "i";
 export const ResultEvent                  = "e";
 export const ResultGoverningAddress       = "g";
+export const ResultNextCursor             = "pn";
 export const ResultRequestID              = "u";
 export const ResultRequestIndex           = "r";
 export const ResultRequestProcessed       = "p";
@@ -37,10 +37,20 @@ export class ImmutableGetEventsForContractParams extends wasmtypes.ScProxy {
 		return new wasmtypes.ScImmutableHname(this.proxy.root(sc.ParamContractHname));
 	}
 
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
 	fromBlock(): wasmtypes.ScImmutableUint32 {
 		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamFromBlock));
 	}
 
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScImmutableUint32 {
+		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+
 	toBlock(): wasmtypes.ScImmutableUint32 {
 		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamToBlock));
 	}
@@ -51,10 +61,20 @@ export class MutableGetEventsForContractParams extends wasmtypes.ScProxy {
 		return new wasmtypes.ScMutableHname(this.proxy.root(sc.ParamContractHname));
 	}
 
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
 	fromBlock(): wasmtypes.ScMutableUint32 {
 		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamFromBlock));
 	}
 
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScMutableUint32 {
+		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+
 	toBlock(): wasmtypes.ScMutableUint32 {
 		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamToBlock));
 	}
@@ -107,6 +107,11 @@ export class ImmutableGetEventsForContractResults extends wasmtypes.ScProxy {
 	event(): sc.ArrayOfImmutableBytes {
 		return new sc.ArrayOfImmutableBytes(this.proxy.root(sc.ResultEvent));
 	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
 }
 
 export class MutableGetEventsForContractResults extends wasmtypes.ScProxy {
@@ -114,6 +119,11 @@ export class MutableGetEventsForContractResults extends wasmtypes.ScProxy {
 	event(): sc.ArrayOfMutableBytes {
 		return new sc.ArrayOfMutableBytes(this.proxy.root(sc.ResultEvent));
 	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
 }
 
 export class ImmutableGetEventsForRequestResults extends wasmtypes.ScProxy {
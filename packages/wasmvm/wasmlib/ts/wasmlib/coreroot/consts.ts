@@ -12,10 +12,12 @@ export const ScDescription = "Root Contract";
 export const HScName       = new wasmtypes.ScHname(0xcebf5908);
 
 export const ParamCloseFunc                = "bcc";
+export const ParamCursor                   = "pc";
 export const ParamDeployPermissionsEnabled = "de";
 export const ParamDeployer                 = "dp";
 export const ParamDescription              = "ds";
 export const ParamHname                    = "hn";
+export const ParamLimit                    = "pl";
 export const ParamName                     = "nm";
 export const ParamOpenFunc                 = "bco";
 export const ParamProgramHash              = "ph";
@@ -23,6 +25,7 @@ export const ParamProgramHash              = "ph";
 export const ResultContractFound    = "cf";
 export const ResultContractRecData  = "dt";
 export const ResultContractRegistry = "r";
+export const ResultNextCursor       = "pn";
 
 export const FuncDeployContract           = "deployContract";
 export const FuncGrantDeployPermission    = "grantDeployPermission";
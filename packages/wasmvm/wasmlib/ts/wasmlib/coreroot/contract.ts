@@ -59,6 +59,7 @@ export class FindContractCall {
 
 export class GetContractRecordsCall {
 	func: wasmlib.ScView;
+	params: sc.MutableGetContractRecordsParams = new sc.MutableGetContractRecordsParams(wasmlib.ScView.nilProxy);
 	results: sc.ImmutableGetContractRecordsResults = new sc.ImmutableGetContractRecordsResults(wasmlib.ScView.nilProxy);
 	public constructor(ctx: wasmlib.ScViewCallContext) {
 		this.func = new wasmlib.ScView(ctx, sc.HScName, sc.HViewGetContractRecords);
@@ -105,6 +106,7 @@ export class ScFuncs {
 
 	static getContractRecords(ctx: wasmlib.ScViewCallContext): GetContractRecordsCall {
 		const f = new GetContractRecordsCall(ctx);
+		f.params = new sc.MutableGetContractRecordsParams(wasmlib.newCallParamsProxy(f.func));
 		f.results = new sc.ImmutableGetContractRecordsResults(wasmlib.newCallResultsProxy(f.func));
 		return f;
 	}
@@ -107,3 +107,27 @@ export class MutableFindContractParams extends wasmtypes.ScProxy {
 		return new wasmtypes.ScMutableHname(this.proxy.root(sc.ParamHname));
 	}
 }
+
+export class ImmutableGetContractRecordsParams extends wasmtypes.ScProxy {
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScImmutableUint32 {
+		return new wasmtypes.ScImmutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+}
+
+export class MutableGetContractRecordsParams extends wasmtypes.ScProxy {
+	// cursor of the page, absent for the first page
+	cursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ParamCursor));
+	}
+
+	// maximum number of elements of the page, default 1000
+	limit(): wasmtypes.ScMutableUint32 {
+		return new wasmtypes.ScMutableUint32(this.proxy.root(sc.ParamLimit));
+	}
+}
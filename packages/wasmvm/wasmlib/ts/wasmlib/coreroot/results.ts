@@ -44,6 +44,11 @@ export class ImmutableGetContractRecordsResults extends wasmtypes.ScProxy {
 	contractRegistry(): sc.MapHnameToImmutableBytes {
 		return new sc.MapHnameToImmutableBytes(this.proxy.root(sc.ResultContractRegistry));
 	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScImmutableBytes {
+		return new wasmtypes.ScImmutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
 }
 
 export class MapHnameToMutableBytes extends wasmtypes.ScProxy {
@@ -62,4 +67,9 @@ export class MutableGetContractRecordsResults extends wasmtypes.ScProxy {
 	contractRegistry(): sc.MapHnameToMutableBytes {
 		return new sc.MapHnameToMutableBytes(this.proxy.root(sc.ResultContractRegistry));
 	}
+
+	// cursor of the next page, absent if there are no more elements
+	nextCursor(): wasmtypes.ScMutableBytes {
+		return new wasmtypes.ScMutableBytes(this.proxy.root(sc.ResultNextCursor));
+	}
 }
@@ -10,12 +10,15 @@ export const ScAgentIDNil: u8 = 0;
 export const ScAgentIDAddress: u8 = 1;
 export const ScAgentIDContract: u8 = 2;
 export const ScAgentIDEthereum: u8 = 3;
+export const ScAgentIDMultisig: u8 = 4;
 const nilAgentIDString: string = "-";
+const multisigAgentIDPrefix: string = "multisig:";
 
 export class ScAgentID {
     kind: u8;
     _address: wasmtypes.ScAddress;
     _hname: wasmtypes.ScHname;
+    _multisig: wasmtypes.ScHash = new wasmtypes.ScHash();
 
     constructor(address: wasmtypes.ScAddress, hname: wasmtypes.ScHname) {
         this.kind = ScAgentIDContract;
@@ -41,9 +44,20 @@ export class ScAgentID {
         return agentID;
     }
 
+    // agent id of the multisig account with the id,
+    // which is the hash of the policy the account was registered with
+    public static fromMultisig(id: wasmtypes.ScHash): ScAgentID {
+        const agentID = new ScAgentID(wasmtypes.addressFromBytes([]), new wasmtypes.ScHname(0));
+        agentID.kind = ScAgentIDMultisig;
+        agentID._multisig = id;
+        return agentID;
+    }
+
     public equals(other: ScAgentID): bool {
-        return this._address.equals(other._address) &&
-            this._hname.equals(other._hname);
+        return this.kind == other.kind &&
+            this._address.equals(other._address) &&
+            this._hname.equals(other._hname) &&
+            this._multisig.equals(other._multisig);
     }
 
     public address(): wasmtypes.ScAddress {
@@ -62,6 +76,15 @@ export class ScAgentID {
         return this.kind == ScAgentIDContract;
     }
 
+    public isMultisig(): bool {
+        return this.kind == ScAgentIDMultisig;
+    }
+
+    // id of the multisig account, the zero hash for the other kinds
+    public multisigID(): wasmtypes.ScHash {
+        return this._multisig;
+    }
+
     // convert to byte array representation
     public toBytes(): u8[] {
         return agentIDToBytes(this)
@@ -112,6 +135,12 @@ export function agentIDFromBytes(buf: u8[]): ScAgentID {
                 panic("invalid AgentID length: Eth agentID");
             }
             return ScAgentID.fromAddress(wasmtypes.addressFromBytes(buf));
+        case ScAgentIDMultisig:
+            buf = buf.slice(1)
+            if (buf.length != wasmtypes.ScHashLength) {
+                panic("invalid AgentID length: multisig agentID");
+            }
+            return ScAgentID.fromMultisig(wasmtypes.hashFromBytes(buf));
         case ScAgentIDNil:
             break;
         default: {
@@ -134,6 +163,8 @@ export function agentIDToBytes(value: ScAgentID): u8[] {
         }
         case ScAgentIDEthereum:
             return buf.concat(wasmtypes.addressToBytes(value._address))
+        case ScAgentIDMultisig:
+            return buf.concat(wasmtypes.hashToBytes(value._multisig))
         case ScAgentIDNil:
             return buf;
         default: {
@@ -148,6 +179,9 @@ export function agentIDFromString(value: string): ScAgentID {
     if (value == nilAgentIDString) {
         return agentIDFromBytes([]);
     }
+    if (value.startsWith(multisigAgentIDPrefix)) {
+        return ScAgentID.fromMultisig(wasmtypes.hashFromString(value.slice(multisigAgentIDPrefix.length)));
+    }
 
     const parts = value.split("@");
     switch (parts.length) {
@@ -170,6 +204,8 @@ export function agentIDToString(value: ScAgentID): string {
         }
         case ScAgentIDEthereum:
             return wasmtypes.addressToString(value.address())
+        case ScAgentIDMultisig:
+            return multisigAgentIDPrefix + wasmtypes.hashToString(value._multisig)
         case ScAgentIDNil:
             return nilAgentIDString;
         default: {
@@ -0,0 +1,124 @@
+package accounthistory
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iotaledger/wasp/packages/chain/chainutil"
+	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+type accountHistoryService struct {
+	chains chains.Provider
+}
+
+func AddEndpoints(server echoswagger.ApiRouter, allChains chains.Provider) {
+	s := &accountHistoryService{allChains}
+
+	server.GET(routes.AccountHistory(":chainID", ":agentID"), s.handleAccountHistory).
+		SetSummary("Get a page of the history of the movements of an L2 account, if it is enabled on the chain").
+		AddParamPath("", "chainID", "ChainID (bech32)").
+		AddParamPath("", "agentID", "AgentID of the account").
+		AddParamQuery(uint32(0), "offset", "Index of the first entry, default 0", false).
+		AddParamQuery(uint32(accounts.MaxAccountHistoryPageSize), "limit",
+			fmt.Sprintf("Maximum number of entries, at most %d", accounts.MaxAccountHistoryPageSize), false).
+		AddResponse(http.StatusOK, "History of the account", model.AccountHistoryResponse{}, nil)
+}
+
+func (s *accountHistoryService) handleAccountHistory(c echo.Context) error {
+	chainID, err := isc.ChainIDFromString(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	agentID, err := isc.NewAgentIDFromString(c.Param("agentID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid agent ID: %+v", c.Param("agentID")))
+	}
+	offset, err := parseUint32QueryParam(c, "offset", 0)
+	if err != nil {
+		return err
+	}
+	limit, err := parseUint32QueryParam(c, "limit", accounts.MaxAccountHistoryPageSize)
+	if err != nil {
+		return err
+	}
+	if limit > accounts.MaxAccountHistoryPageSize {
+		return httperrors.BadRequest(fmt.Sprintf("limit must not exceed %d", accounts.MaxAccountHistoryPageSize))
+	}
+
+	theChain := s.chains().Get(chainID)
+	if theChain == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
+	}
+	ret, err := chainutil.CallView(theChain, accounts.Contract.Hname(), accounts.ViewAccountHistory.Hname(), dict.Dict{
+		accounts.ParamAgentID:       codec.EncodeAgentID(agentID),
+		accounts.ParamHistoryOffset: codec.EncodeUint32(offset),
+		accounts.ParamHistoryLimit:  codec.EncodeUint32(limit),
+	})
+	if err != nil {
+		return httperrors.ServerError(fmt.Sprintf("View call failed: %v", err))
+	}
+
+	res := &model.AccountHistoryResponse{}
+	if res.First, err = codec.DecodeUint32(ret.MustGet(accounts.ParamHistoryFirst)); err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	if res.Total, err = codec.DecodeUint32(ret.MustGet(accounts.ParamHistoryTotal)); err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	if res.Retention, err = codec.DecodeUint32(ret.MustGet(accounts.ParamHistoryRetention)); err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	arr := collections.NewArray32ReadOnly(ret, accounts.ParamHistoryEntries)
+	for i := uint32(0); i < arr.MustLen(); i++ {
+		entry, err := accounts.HistoryEntryFromBytes(arr.MustGetAt(i))
+		if err != nil {
+			return httperrors.ServerError(err.Error())
+		}
+		res.Entries = append(res.Entries, historyEntryToModel(entry))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func parseUint32QueryParam(c echo.Context, name string, def uint32) (uint32, error) {
+	s := c.QueryParam(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, httperrors.BadRequest(fmt.Sprintf("Invalid %s: %+v", name, s))
+	}
+	return uint32(v), nil
+}
+
+func historyEntryToModel(e *accounts.HistoryEntry) *model.AccountHistoryEntry {
+	ret := &model.AccountHistoryEntry{
+		BlockIndex:   e.BlockIndex,
+		RequestIndex: e.RequestIndex,
+		RequestID:    model.NewRequestID(e.RequestID),
+		Delta:        e.Delta.String(),
+		Reason:       e.Reason.String(),
+	}
+	if e.Counterparty != nil {
+		ret.Counterparty = e.Counterparty.String()
+	}
+	if e.NativeTokenID != nil {
+		ret.NativeTokenID = e.NativeTokenID.ToHex()
+	}
+	if e.NFTID != nil {
+		ret.NFTID = e.NFTID.ToHex()
+	}
+	return ret
+}
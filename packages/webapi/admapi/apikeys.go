@@ -0,0 +1,102 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/ratelimit"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addAPIKeyEndpoints(adm echoswagger.ApiGroup, limiter *ratelimit.Limiter) {
+	addCtx := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("limiter", limiter)
+			return next(c)
+		}
+	}
+	example := &model.APIKey{
+		Key:   "5d9e0d8d4c3b0b9e57f3a2a4d1f9c0b8d6a1e2f3c4b5a697",
+		Name:  "some-dapp",
+		Rate:  100,
+		Burst: 200,
+	}
+
+	adm.GET(routes.AdmAPIKeys(), handleAPIKeyList, addCtx).
+		AddResponse(http.StatusOK, "A list of API keys.", []*model.APIKey{example}, nil).
+		SetSummary("Get a list of the API keys of the web API clients.")
+
+	adm.GET(routes.AdmAPIKey(":key"), handleAPIKeyGet, addCtx).
+		AddParamPath(example.Key, "key", "The API key.").
+		AddResponse(http.StatusOK, "API key info.", example, nil).
+		SetSummary("Get the quota of the API key.")
+
+	adm.POST(routes.AdmAPIKeys(), handleAPIKeyPost, addCtx).
+		AddParamBody(example, "APIKey", "The API key and its quota. The key is generated, if empty.", true).
+		AddResponse(http.StatusOK, "API key info.", example, nil).
+		SetSummary("Create or update an API key.")
+
+	adm.DELETE(routes.AdmAPIKey(":key"), handleAPIKeyDelete, addCtx).
+		AddParamPath(example.Key, "key", "The API key.").
+		SetSummary("Delete the API key.")
+}
+
+func handleAPIKeyList(c echo.Context) error {
+	limiter := c.Get("limiter").(*ratelimit.Limiter)
+	keys, err := limiter.APIKeys()
+	if err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	response := make([]*model.APIKey, len(keys))
+	for i := range keys {
+		response[i] = model.NewAPIKey(keys[i])
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func handleAPIKeyGet(c echo.Context) error {
+	limiter := c.Get("limiter").(*ratelimit.Limiter)
+	key, err := limiter.GetAPIKey(c.Param("key"))
+	if err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	if key == nil {
+		return httperrors.NotFound(fmt.Sprintf("API key not found: %s", c.Param("key")))
+	}
+	return c.JSON(http.StatusOK, model.NewAPIKey(key))
+}
+
+func handleAPIKeyPost(c echo.Context) error {
+	limiter := c.Get("limiter").(*ratelimit.Limiter)
+	var req model.APIKey
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body.")
+	}
+	key, err := limiter.SaveAPIKey(req.Record())
+	if err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	return c.JSON(http.StatusOK, model.NewAPIKey(key))
+}
+
+func handleAPIKeyDelete(c echo.Context) error {
+	limiter := c.Get("limiter").(*ratelimit.Limiter)
+	key, err := limiter.GetAPIKey(c.Param("key"))
+	if err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	if key == nil {
+		return httperrors.NotFound(fmt.Sprintf("API key not found: %s", c.Param("key")))
+	}
+	if err := limiter.DeleteAPIKey(key.Key); err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	return c.NoContent(http.StatusOK)
+}
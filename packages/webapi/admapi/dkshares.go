@@ -51,6 +51,12 @@ func addDKSharesEndpoints(adm echoswagger.ApiGroup, registryProvider registry.Pr
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
 		SetSummary("Generate a new distributed key")
 
+	adm.POST(routes.DKSharesReshare(":sharedAddress"), s.handleDKSharesReshare).
+		AddParamPath("", "sharedAddress", "Address of the DK share (bech32)").
+		AddParamBody(model.DKSharesReshareRequest(requestExample), "DKSharesReshareRequest", "Request parameters", true).
+		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
+		SetSummary("Move an existing distributed key to a new set of nodes, keeping its address")
+
 	adm.GET(routes.DKSharesGet(":sharedAddress"), s.handleDKSharesGet).
 		AddParamPath("", "sharedAddress", "Address of the DK share (base58)").
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
@@ -74,16 +80,9 @@ func (s *dkSharesService) handleDKSharesPost(c echo.Context) error {
 		return httperrors.BadRequest("PeerPubKeys are mandatory")
 	}
 
-	var peerPubKeys []*cryptolib.PublicKey
-	if req.PeerPubKeys != nil {
-		peerPubKeys = make([]*cryptolib.PublicKey, len(req.PeerPubKeys))
-		for i := range req.PeerPubKeys {
-			peerPubKey, err := cryptolib.NewPublicKeyFromBase58String(req.PeerPubKeys[i])
-			if err != nil {
-				return httperrors.BadRequest(fmt.Sprintf("Invalid PeerPubKeys[%v]=%v", i, req.PeerPubKeys[i]))
-			}
-			peerPubKeys[i] = peerPubKey
-		}
+	peerPubKeys, err := parsePeerPubKeys(req.PeerPubKeys)
+	if err != nil {
+		return err
 	}
 
 	dkShare, err := s.dkgNode().GenerateDistributedKey(
@@ -107,6 +106,59 @@ func (s *dkSharesService) handleDKSharesPost(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+func (s *dkSharesService) handleDKSharesReshare(c echo.Context) error {
+	var req model.DKSharesReshareRequest
+	var err error
+
+	var sharedAddress iotago.Address
+	if _, sharedAddress, err = iotago.ParseBech32(c.Param("sharedAddress")); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid sharedAddress: %v", err))
+	}
+	if err = c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body.")
+	}
+	if len(req.PeerPubKeys) < 1 {
+		return httperrors.BadRequest("PeerPubKeys are mandatory")
+	}
+	peerPubKeys, err := parsePeerPubKeys(req.PeerPubKeys)
+	if err != nil {
+		return err
+	}
+
+	dkShare, err := s.dkgNode().ReshareDistributedKey(
+		sharedAddress,
+		peerPubKeys,
+		req.Threshold,
+		1*time.Second,
+		3*time.Second,
+		time.Duration(req.TimeoutMS)*time.Millisecond,
+	)
+	if err != nil {
+		if _, ok := err.(dkg.InvalidParamsError); ok {
+			return httperrors.BadRequest(err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	var response *model.DKSharesInfo
+	if response, err = makeDKSharesInfo(dkShare); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func parsePeerPubKeys(encoded []string) ([]*cryptolib.PublicKey, error) {
+	peerPubKeys := make([]*cryptolib.PublicKey, len(encoded))
+	for i := range encoded {
+		peerPubKey, err := cryptolib.NewPublicKeyFromBase58String(encoded[i])
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid PeerPubKeys[%v]=%v", i, encoded[i]))
+		}
+		peerPubKeys[i] = peerPubKey
+	}
+	return peerPubKeys, nil
+}
+
 func (s *dkSharesService) handleDKSharesGet(c echo.Context) error {
 	var err error
 	var dkShare tcrypto.DKShare
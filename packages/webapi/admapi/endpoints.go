@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/wal"
+	"github.com/iotaledger/wasp/packages/webapi/ratelimit"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
@@ -33,6 +34,7 @@ func AddEndpoints(
 	shutdown ShutdownFunc,
 	metrics *metricspkg.Metrics,
 	w *wal.WAL,
+	limiter *ratelimit.Limiter,
 ) {
 	initLogger()
 
@@ -55,4 +57,5 @@ func AddEndpoints(
 	})
 	addDKSharesEndpoints(adm, registryProvider, nodeProvider)
 	addPeeringEndpoints(adm, network, tnm)
+	addAPIKeyEndpoints(adm, limiter)
 }
@@ -77,7 +77,13 @@ func handleAdmNodeOwnerCertificate(c echo.Context) error {
 
 	//
 	// Create the certificate. It consists of signature only. The data is not included.
-	cert := governance.NewNodeOwnershipCertificate(nodeIdentity, reqOwnerAddress)
+	// The signature is made by the signer, the node does not hold the identity key.
+	certData := governance.NodeOwnershipCertificateData(nodeIdentity.GetPublicKey(), reqOwnerAddress)
+	signature, err := nodeIdentity.Sign(certData)
+	if err != nil {
+		return httperrors.ServerError("Failed to sign the certificate")
+	}
+	cert := governance.NewNodeOwnershipCertificateFromBytes(signature)
 	resp := model.NodeOwnerCertificateResponse{
 		Certificate: model.NewBytes(cert.Bytes()),
 	}
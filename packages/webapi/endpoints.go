@@ -10,23 +10,29 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/chainutil"
 	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/dkg"
+	"github.com/iotaledger/wasp/packages/grpcapi"
 	metricspkg "github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/wal"
+	"github.com/iotaledger/wasp/packages/webapi/accounthistory"
 	"github.com/iotaledger/wasp/packages/webapi/admapi"
 	"github.com/iotaledger/wasp/packages/webapi/evm"
 	"github.com/iotaledger/wasp/packages/webapi/info"
+	"github.com/iotaledger/wasp/packages/webapi/ratelimit"
 	"github.com/iotaledger/wasp/packages/webapi/reqstatus"
 	"github.com/iotaledger/wasp/packages/webapi/request"
 	"github.com/iotaledger/wasp/packages/webapi/state"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
+	"google.golang.org/grpc"
 )
 
 var log *logger.Logger
 
+// Init adds the endpoints to the web API server. It returns the gRPC server
+// with the same public API, or nil if the gRPC API is disabled.
 func Init(
 	server echoswagger.ApiRoot,
 	network peering.NetworkProvider,
@@ -37,25 +43,34 @@ func Init(
 	shutdown admapi.ShutdownFunc,
 	metrics *metricspkg.Metrics,
 	w *wal.WAL,
-) {
+) *grpc.Server {
 	log = logger.NewLogger("WebAPI")
 
 	server.SetRequestContentType(echo.MIMEApplicationJSON)
 	server.SetResponseContentType(echo.MIMEApplicationJSON)
 
+	rateLimit := rateLimitConfig()
+	limiter := ratelimit.New(rateLimit, registryProvider(), metrics.NewWebAPIMetrics(), log)
+
 	pub := server.Group("public", "").SetDescription("Public endpoints")
+	if rateLimit.Enabled {
+		pub.EchoGroup().Use(limiter.Middleware())
+	}
 	addWebSocketEndpoint(pub, log)
 
 	info.AddEndpoints(pub, network)
 	reqstatus.AddEndpoints(pub, chainsProvider.ChainProvider())
 	state.AddEndpoints(pub, chainsProvider)
+	accounthistory.AddEndpoints(pub, chainsProvider)
 	evm.AddEndpoints(pub, chainsProvider, network.Self().PubKey)
 	request.AddEndpoints(
 		pub,
 		chainsProvider.ChainProvider(),
 		chainutil.GetAccountBalance,
+		chainutil.GasFeePayer,
 		chainutil.HasRequestBeenProcessed,
 		chainutil.CheckNonce,
+		chainutil.CheckOffLedgerRequests,
 		network.Self().PubKey(),
 		time.Duration(parameters.GetInt(parameters.OffledgerAPICacheTTL))*time.Second,
 		log,
@@ -73,6 +88,28 @@ func Init(
 		shutdown,
 		metrics,
 		w,
+		limiter,
 	)
 	log.Infof("added web api endpoints")
+
+	if !parameters.GetBool(parameters.WebAPIGRPCEnabled) {
+		return nil
+	}
+	var opts []grpc.ServerOption
+	if rateLimit.Enabled {
+		opts = grpcapi.RateLimitOptions(limiter, log)
+	}
+	grpcServer := grpc.NewServer(opts...)
+	grpcapi.New(chainsProvider.ChainProvider(), network.Self().PubKey(), log).Register(grpcServer)
+	log.Infof("added gRPC api")
+	return grpcServer
+}
+
+func rateLimitConfig() *ratelimit.Config {
+	config := ratelimit.DefaultConfig()
+	if err := parameters.GetStruct(parameters.WebAPIRateLimit, config); err != nil {
+		log.Warnf("invalid rate limit configuration, using the defaults: %v", err)
+		return ratelimit.DefaultConfig()
+	}
+	return config
 }
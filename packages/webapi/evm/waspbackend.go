@@ -15,6 +15,8 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/chainutil"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
+	"github.com/iotaledger/wasp/packages/evm/evmutil"
 	"github.com/iotaledger/wasp/packages/evm/jsonrpc"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/codec"
@@ -113,6 +115,22 @@ func (b *jsonRPCWaspBackend) ISCCallView(scName, funName string, args dict.Dict)
 	return chainutil.CallView(b.chain, isc.Hn(scName), isc.Hn(funName), args)
 }
 
+func (b *jsonRPCWaspBackend) ISCMempoolRequests() []isc.Request {
+	return b.chain.GetMempoolRequests()
+}
+
+func (b *jsonRPCWaspBackend) ISCStateProofs(keys [][]byte) (uint32, []*evmutil.StateProof, error) {
+	blockIndex, values, proofs, err := chainutil.GetStateProofs(b.chain, keys)
+	if err != nil {
+		return 0, nil, err
+	}
+	return blockIndex, evmutil.NewStateProofs(keys, values, proofs), nil
+}
+
+func (b *jsonRPCWaspBackend) EVMIndex() *evmindex.Index {
+	return b.chain.GetEVMIndex()
+}
+
 func (b *jsonRPCWaspBackend) BaseToken() *parameters.BaseToken {
 	return b.baseToken
 }
@@ -32,6 +32,18 @@ func Timeout(message string) *HTTPError {
 	return &HTTPError{Code: http.StatusRequestTimeout, Message: message}
 }
 
+func Unauthorized(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusUnauthorized, Message: message}
+}
+
+func RequestEntityTooLarge(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusRequestEntityTooLarge, Message: message}
+}
+
+func TooManyRequests(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusTooManyRequests, Message: message}
+}
+
 func ServerError(message string) *HTTPError {
 	return &HTTPError{Code: http.StatusInternalServerError, Message: message}
 }
@@ -0,0 +1,19 @@
+package model
+
+type AccountHistoryEntry struct {
+	BlockIndex    uint32    `swagger:"desc(Index of the block)"`
+	RequestIndex  uint16    `swagger:"desc(Index of the request in the block)"`
+	RequestID     RequestID `swagger:"desc(ID of the request)"`
+	Counterparty  string    `swagger:"desc(AgentID of the other account, empty if the assets came from or went to L1)"`
+	NativeTokenID string    `swagger:"desc(ID of the native token (hex), empty if the entry is about base tokens or an NFT)"`
+	NFTID         string    `swagger:"desc(ID of the NFT (hex), empty if the entry is about fungible tokens)"`
+	Delta         string    `swagger:"desc(Signed amount (decimal), positive if the assets came in)"`
+	Reason        string    `swagger:"desc(deposit, withdrawal, transfer, fee, mint, burn or storageDeposit)"`
+}
+
+type AccountHistoryResponse struct {
+	Entries   []*AccountHistoryEntry `swagger:"desc(Entries of the page, oldest first)"`
+	First     uint32                 `swagger:"desc(Index of the oldest entry kept, the older ones were pruned)"`
+	Total     uint32                 `swagger:"desc(Total number of entries recorded for the account)"`
+	Retention uint32                 `swagger:"desc(Number of the latest entries kept for each account)"`
+}
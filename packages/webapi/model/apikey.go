@@ -0,0 +1,34 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/registry"
+)
+
+// APIKey is a key of a web API client, with its rate limit quota.
+type APIKey struct {
+	Key   string `json:"key" swagger:"desc(The API key, passed in the X-API-Key header. Generated by the node, if empty.)"`
+	Name  string `json:"name" swagger:"desc(Name of the client.)"`
+	Rate  uint32 `json:"rate" swagger:"desc(Cost units per second. The default quota is used, if 0.)"`
+	Burst uint32 `json:"burst" swagger:"desc(Maximum cost units spent at once.)"`
+}
+
+func NewAPIKey(k *registry.APIKey) *APIKey {
+	return &APIKey{
+		Key:   k.Key,
+		Name:  k.Name,
+		Rate:  k.Rate,
+		Burst: k.Burst,
+	}
+}
+
+func (k *APIKey) Record() *registry.APIKey {
+	return &registry.APIKey{
+		Key:   k.Key,
+		Name:  k.Name,
+		Rate:  k.Rate,
+		Burst: k.Burst,
+	}
+}
@@ -10,6 +10,14 @@ type DKSharesPostRequest struct {
 	TimeoutMS   uint32   `json:"timeoutMS" swagger:"desc(Timeout in milliseconds.)"`
 }
 
+// DKSharesReshareRequest is a POST request for moving an existing DKShare to a new
+// set of nodes and/or threshold, keeping its address.
+type DKSharesReshareRequest struct {
+	PeerPubKeys []string `json:"peerPubKeys" swagger:"desc(Base64 encoded public keys of the new holders of the DKS.)"`
+	Threshold   uint16   `json:"threshold" swagger:"desc(Should be =< len(PeerPubKeys))"`
+	TimeoutMS   uint32   `json:"timeoutMS" swagger:"desc(Timeout in milliseconds.)"`
+}
+
 // DKSharesInfo stands for the DKShare representation, returned by the GET and POST methods.
 type DKSharesInfo struct {
 	Address      string   `json:"address" swagger:"desc(New generated shared address.)"`
@@ -1,5 +1,35 @@
 package model
 
+import (
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/isc"
+)
+
 type OffLedgerRequestBody struct {
 	Request Bytes `swagger:"desc(Offledger Request (base64))"`
 }
+
+type OffLedgerRequestBatchBody struct {
+	Requests []Bytes `swagger:"desc(Offledger Requests (base64))"`
+}
+
+type OffLedgerRequestResult struct {
+	RequestID *RequestID `swagger:"desc(Request ID, null if the request could not be decoded)"`
+	Accepted  bool       `swagger:"desc(Whether the request was accepted by the node)"`
+	Error     string     `swagger:"desc(Why the request was rejected)"`
+}
+
+type OffLedgerRequestBatchResponse struct {
+	Results []*OffLedgerRequestResult `swagger:"desc(Result of each request, in the order of the batch)"`
+}
+
+// OffLedgerRequestBatchBytes is the binary format of a batch of off-ledger
+// requests: the number of requests followed by the requests
+func OffLedgerRequestBatchBytes(reqs []isc.OffLedgerRequest) []byte {
+	mu := marshalutil.New()
+	mu.WriteUint16(uint16(len(reqs)))
+	for _, req := range reqs {
+		mu.WriteBytes(req.Bytes())
+	}
+	return mu.Bytes()
+}
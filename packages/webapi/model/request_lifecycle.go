@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
+)
+
+type RequestLifecycleEvent struct {
+	Stage      string    `json:"stage" swagger:"desc(Stage entered by the request)"`
+	Time       time.Time `json:"time" swagger:"desc(Time of the event)"`
+	BlockIndex uint32    `json:"blockIndex" swagger:"desc(Block of the request, in the processed and confirmed stages)"`
+	Reason     string    `json:"reason,omitempty" swagger:"desc(Why the request was skipped, or returned to the mempool)"`
+}
+
+type RequestLifecycleResponse struct {
+	RequestID  RequestID                `json:"requestId" swagger:"desc(Request ID)"`
+	Stage      string                   `json:"stage" swagger:"desc(Current stage of the request: unknown, mempool, proposed, skipped, processed or confirmed)"`
+	BlockIndex uint32                   `json:"blockIndex" swagger:"desc(Block of the request, once it is processed)"`
+	History    []*RequestLifecycleEvent `json:"history" swagger:"desc(Recent stages of the request, the oldest first)"`
+}
+
+func NewRequestLifecycleResponse(status *lifecycle.Status) *RequestLifecycleResponse {
+	ret := &RequestLifecycleResponse{
+		RequestID: NewRequestID(status.RequestID),
+		Stage:     string(status.Stage),
+		History:   make([]*RequestLifecycleEvent, len(status.History)),
+	}
+	for i, ev := range status.History {
+		ret.History[i] = &RequestLifecycleEvent{
+			Stage:      string(ev.Stage),
+			Time:       ev.Time,
+			BlockIndex: ev.BlockIndex,
+			Reason:     ev.Reason,
+		}
+		if ev.Stage == lifecycle.StageProcessed || ev.Stage == lifecycle.StageConfirmed {
+			ret.BlockIndex = ev.BlockIndex
+		}
+	}
+	return ret
+}
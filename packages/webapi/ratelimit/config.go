@@ -0,0 +1,84 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+)
+
+// route groups, which are rate limited separately
+const (
+	GroupDefault  = "default"
+	GroupRequest  = "request"
+	GroupCallView = "callview"
+	GroupEVM      = "evm"
+)
+
+// Limit is a token bucket: Rate cost units per second are added to the
+// bucket, up to Burst units. A Rate of 0 disables the limit.
+type Limit struct {
+	Rate  int `koanf:"rate"`
+	Burst int `koanf:"burst"`
+}
+
+type Config struct {
+	Enabled bool `koanf:"enabled"`
+	// take the client IP from the X-Forwarded-For and X-Real-IP headers, when
+	// the node is behind a reverse proxy
+	TrustForwardedFor bool `koanf:"trustForwardedFor"`
+	// limits per client IP, by route group
+	Groups map[string]Limit `koanf:"groups"`
+	// limit of the API keys created without an explicit quota
+	APIKeyDefault Limit `koanf:"apiKeyDefault"`
+	// cost of the view calls, by contract name and function name; 1 by default
+	ViewCosts map[string]map[string]int `koanf:"viewCosts"`
+	// cost of the EVM JSON-RPC methods, by method name; 1 by default
+	JSONRPCCosts map[string]int `koanf:"jsonrpcCosts"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Groups: map[string]Limit{
+			GroupDefault:  {Rate: 20, Burst: 40},
+			GroupRequest:  {Rate: 5, Burst: 10},
+			GroupCallView: {Rate: 20, Burst: 40},
+			GroupEVM:      {Rate: 20, Burst: 40},
+		},
+		APIKeyDefault: Limit{Rate: 100, Burst: 200},
+		ViewCosts:     map[string]map[string]int{},
+		JSONRPCCosts: map[string]int{
+			"eth_call":        5,
+			"eth_estimateGas": 5,
+			"eth_getProof":    5,
+			"eth_feeHistory":  5,
+			"eth_getLogs":     10,
+		},
+	}
+}
+
+func (l Limit) normalized() Limit {
+	if l.Burst < l.Rate {
+		l.Burst = l.Rate
+	}
+	return l
+}
+
+func (c *Config) groupLimit(group string) Limit {
+	if l, ok := c.Groups[group]; ok {
+		return l.normalized()
+	}
+	return c.Groups[GroupDefault].normalized()
+}
+
+func (c *Config) viewCostsByHname() map[isc.Hname]map[isc.Hname]int {
+	ret := make(map[isc.Hname]map[isc.Hname]int, len(c.ViewCosts))
+	for contract, funcs := range c.ViewCosts {
+		costs := make(map[isc.Hname]int, len(funcs))
+		for f, cost := range funcs {
+			costs[isc.Hn(f)] = cost
+		}
+		ret[isc.Hn(contract)] = costs
+	}
+	return ret
+}
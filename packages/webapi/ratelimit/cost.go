@@ -0,0 +1,153 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+)
+
+// maxJSONRPCBodySize is the limit of the JSON-RPC server, larger requests are
+// rejected by it anyway
+const maxJSONRPCBodySize = 5 * 1024 * 1024
+
+// maxBatchBodySize is larger than any batch of off-ledger requests accepted by the node
+const maxBatchBodySize = 64 * 1024 * 1024
+
+var routeGroups = map[string]string{
+	routes.NewRequest(":chainID"):                                          GroupRequest,
+	routes.NewRequestBatch(":chainID"):                                     GroupRequest,
+	routes.CallViewByName(":chainID", ":contractHname", ":fname"):          GroupCallView,
+	routes.CallViewByHname(":chainID", ":contractHname", ":functionHname"): GroupCallView,
+	routes.StateGet(":chainID", ":key"):                                    GroupCallView,
+	routes.EVMJSONRPC(":chainID"):                                          GroupEVM,
+}
+
+// routeGroup returns the group of the route matched by the router
+func routeGroup(c echo.Context) string {
+	if group, ok := routeGroups[c.Path()]; ok {
+		return group
+	}
+	return GroupDefault
+}
+
+// cost returns the number of cost units spent by the request
+func (l *Limiter) cost(group string, c echo.Context) int {
+	switch group {
+	case GroupRequest:
+		if c.Path() == routes.NewRequestBatch(":chainID") {
+			return l.batchCost(c)
+		}
+	case GroupCallView:
+		return l.viewCost(c)
+	case GroupEVM:
+		return l.jsonRPCCost(c)
+	}
+	return 1
+}
+
+func (l *Limiter) viewCost(c echo.Context) int {
+	contract, err := isc.HnameFromString(c.Param("contractHname"))
+	if err != nil {
+		return 1
+	}
+	var function isc.Hname
+	if fname := c.Param("fname"); fname != "" {
+		function = isc.Hn(fname)
+	} else if function, err = isc.HnameFromString(c.Param("functionHname")); err != nil {
+		return 1
+	}
+	return l.ViewCost(contract, function)
+}
+
+// ViewCost returns the cost of the view call
+func (l *Limiter) ViewCost(contract, function isc.Hname) int {
+	if cost, ok := l.viewCosts[contract][function]; ok {
+		return cost
+	}
+	return 1
+}
+
+// batchCost is the number of requests in the batch of off-ledger requests. The
+// body is restored for the handler.
+func (l *Limiter) batchCost(c echo.Context) int {
+	body, ok := readBody(c, maxBatchBodySize)
+	if !ok {
+		return 1
+	}
+	n := 0
+	if strings.Contains(strings.ToLower(c.Request().Header.Get(echo.HeaderContentType)), "json") {
+		var batch struct {
+			Requests []json.RawMessage
+		}
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return 1
+		}
+		n = len(batch.Requests)
+	} else if len(body) >= 2 {
+		n = int(binary.LittleEndian.Uint16(body))
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
+
+// readBody reads the body up to the limit, and restores it for the handler
+func readBody(c echo.Context, limit int64) ([]byte, bool) {
+	req := c.Request()
+	if req.Body == nil {
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, limit))
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err == nil
+}
+
+// jsonRPCCost reads the methods of a single or a batch JSON-RPC request. The
+// body is restored for the JSON-RPC server.
+func (l *Limiter) jsonRPCCost(c echo.Context) int {
+	body, ok := readBody(c, maxJSONRPCBodySize)
+	if !ok {
+		return 1
+	}
+
+	type message struct {
+		Method string `json:"method"`
+	}
+	var msgs []message
+	body = bytes.TrimLeft(body, " \t\r\n")
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return 1
+		}
+	} else {
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return 1
+		}
+		msgs = []message{msg}
+	}
+
+	cost := 0
+	for _, msg := range msgs {
+		if methodCost, ok := l.config.JSONRPCCosts[msg.Method]; ok {
+			cost += methodCost
+		} else {
+			cost++
+		}
+	}
+	if cost == 0 {
+		return 1
+	}
+	return cost
+}
@@ -0,0 +1,359 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimit limits the rate of the public web API requests with token
+// buckets per client IP and route group, or per API key. Each request spends
+// a number of cost units, which depends on the route group and, for the view
+// calls and the EVM JSON-RPC requests, on the function called.
+package ratelimit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// APIKeyHeader is the header with the API key of the client
+const APIKeyHeader = "X-API-Key"
+
+const (
+	cleanupPeriodConst  = 1 * time.Minute
+	idleTimeoutConst    = 10 * time.Minute
+	invalidKeyTTLConst  = 10 * time.Second // how long an unknown API key is rejected without reading the registry
+	maxInvalidKeysConst = 10000            // max number of unknown API keys remembered
+)
+
+type Limiter struct {
+	config    *Config
+	viewCosts map[isc.Hname]map[isc.Hname]int
+	registry  registry.APIKeyRegistryProvider
+	metrics   metrics.WebAPIMetrics
+	log       *logger.Logger
+
+	mutex       sync.Mutex
+	ipBuckets   map[string]*bucket   // by route group and client IP
+	keyBuckets  map[string]*bucket   // by API key
+	invalidKeys map[string]time.Time // unknown API keys -> expiration time
+	keysVersion uint64               // incremented, when an API key is saved or deleted
+	lastCleanup time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func New(config *Config, reg registry.APIKeyRegistryProvider, webAPIMetrics metrics.WebAPIMetrics, log *logger.Logger) *Limiter {
+	return &Limiter{
+		config:      config,
+		viewCosts:   config.viewCostsByHname(),
+		registry:    reg,
+		metrics:     webAPIMetrics,
+		log:         log.Named("ratelimit"),
+		ipBuckets:   make(map[string]*bucket),
+		keyBuckets:  make(map[string]*bucket),
+		invalidKeys: make(map[string]time.Time),
+		lastCleanup: time.Now(),
+	}
+}
+
+func newBucket(l Limit, now time.Time) *bucket {
+	l = l.normalized()
+	r := rate.Limit(l.Rate)
+	if l.Rate <= 0 {
+		r = rate.Inf
+	}
+	return &bucket{limiter: rate.NewLimiter(r, l.Burst), lastUsed: now}
+}
+
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// LimitedError is returned when the client spent its quota
+type LimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LimitedError) Error() string {
+	return fmt.Sprintf("Rate limit exceeded, retry in %v", e.RetryAfter.Round(time.Millisecond))
+}
+
+// CostTooHighError is returned when the request costs more than the burst of
+// the quota, e.g. a batch too large to be ever allowed; it must be split
+type CostTooHighError struct {
+	Cost    int
+	MaxCost int
+}
+
+func (e *CostTooHighError) Error() string {
+	return fmt.Sprintf("Request costs %d units, the maximum allowed at once is %d", e.Cost, e.MaxCost)
+}
+
+// Middleware rejects the requests exceeding the limits with 429 Too Many
+// Requests, and the ones costing more than the burst with 413 Request Entity
+// Too Large. The requests with an unknown API key are rejected with 401.
+func (l *Limiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			group := routeGroup(c)
+			err := l.Allow(group, l.cost(group, c), c.Request().Header.Get(APIKeyHeader), l.clientIP(c))
+			var limited *LimitedError
+			var tooHigh *CostTooHighError
+			switch {
+			case err == nil:
+				return next(c)
+			case errors.Is(err, ErrInvalidAPIKey):
+				return httperrors.Unauthorized("Invalid API key")
+			case errors.As(err, &limited):
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+				return httperrors.TooManyRequests(limited.Error())
+			case errors.As(err, &tooHigh):
+				return httperrors.RequestEntityTooLarge(tooHigh.Error())
+			default:
+				l.log.Errorf("cannot load API key: %v", err)
+				return httperrors.ServerError("Cannot load API key")
+			}
+		}
+	}
+}
+
+// Allow spends the cost units from the quota of the API key or, if the key is
+// empty, from the quota of the client IP in the route group. It returns
+// ErrInvalidAPIKey for an unknown key, a *LimitedError when the quota is
+// spent, and a *CostTooHighError when the request costs more than the quota
+// can ever hold. Other transports than the web API use it directly.
+func (l *Limiter) Allow(group string, cost int, apiKey, ip string) error {
+	l.metrics.CountRequestCost(group, cost)
+	if apiKey == "" {
+		return l.take(l.ipBucket(group, ip), group, "ip", cost)
+	}
+	if b := l.cachedKeyBucket(apiKey); b != nil {
+		return l.take(b, group, "key", cost)
+	}
+	// the key is looked up in the registry at the expense of the client IP,
+	// so that requests with random keys are limited as the ones without a key;
+	// the quota of the key may allow a higher cost than the one of the IP
+	ipBucket := l.ipBucket(group, ip)
+	refund, err := ipBucket.reserve(ipBucket.capCost(cost))
+	if err != nil {
+		l.metrics.CountRateLimited(group, "ip")
+		return err
+	}
+	b, err := l.keyBucket(apiKey)
+	if err != nil {
+		return err
+	}
+	// the request is charged to the key, not to the client IP
+	refund()
+	return l.take(b, group, "key", cost)
+}
+
+func (l *Limiter) take(b *bucket, group, client string, cost int) error {
+	if _, err := b.reserve(cost); err != nil {
+		l.metrics.CountRateLimited(group, client)
+		return err
+	}
+	return nil
+}
+
+// capCost returns the cost limited to the burst of the bucket
+func (b *bucket) capCost(cost int) int {
+	if burst := b.limiter.Burst(); cost > burst && b.limiter.Limit() != rate.Inf {
+		return burst
+	}
+	return cost
+}
+
+// reserve spends the cost units, if they are in the bucket, and returns the
+// function to give them back. Otherwise it returns a *LimitedError with the
+// time to wait for them, or a *CostTooHighError if they exceed the burst.
+func (b *bucket) reserve(cost int) (func(), error) {
+	if burst := b.limiter.Burst(); cost > burst && b.limiter.Limit() != rate.Inf {
+		return nil, &CostTooHighError{Cost: cost, MaxCost: burst}
+	}
+	now := time.Now()
+	r := b.limiter.ReserveN(now, cost)
+	if !r.OK() {
+		return nil, &LimitedError{RetryAfter: time.Second}
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return nil, &LimitedError{RetryAfter: delay}
+	}
+	return func() { r.CancelAt(now) }, nil
+}
+
+func (l *Limiter) clientIP(c echo.Context) string {
+	if l.config.TrustForwardedFor {
+		return c.RealIP()
+	}
+	ip, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		return c.Request().RemoteAddr
+	}
+	return ip
+}
+
+func (l *Limiter) ipBucket(group, ip string) *bucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.cleanup(now)
+	id := group + "/" + ip
+	b, ok := l.ipBuckets[id]
+	if !ok {
+		b = newBucket(l.config.groupLimit(group), now)
+		l.ipBuckets[id] = b
+	}
+	b.lastUsed = now
+	return b
+}
+
+// cachedKeyBucket returns the bucket of the API key, nil if the key has not
+// been loaded from the registry yet
+func (l *Limiter) cachedKeyBucket(key string) *bucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.keyBuckets[key]
+	if !ok {
+		return nil
+	}
+	b.lastUsed = time.Now()
+	return b
+}
+
+// keyBucket loads the API key from the registry and creates its bucket. The
+// registry is read without holding the mutex, so that the other requests are
+// not blocked. The unknown keys are remembered for a while.
+func (l *Limiter) keyBucket(key string) (*bucket, error) {
+	now := time.Now()
+	l.mutex.Lock()
+	expiration, invalid := l.invalidKeys[key]
+	version := l.keysVersion
+	l.mutex.Unlock()
+	if invalid && now.Before(expiration) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := l.registry.GetAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.cleanup(now)
+	if version != l.keysVersion {
+		// the keys have changed while reading the registry, the key is read again on the next request
+		if apiKey == nil {
+			return nil, ErrInvalidAPIKey
+		}
+		return newBucket(Limit{Rate: int(apiKey.Rate), Burst: int(apiKey.Burst)}, now), nil
+	}
+	if apiKey == nil {
+		if len(l.invalidKeys) < maxInvalidKeysConst {
+			l.invalidKeys[key] = now.Add(invalidKeyTTLConst)
+		}
+		return nil, ErrInvalidAPIKey
+	}
+	delete(l.invalidKeys, key)
+	b, ok := l.keyBuckets[key]
+	if !ok {
+		// not created by a concurrent request meanwhile
+		b = newBucket(Limit{Rate: int(apiKey.Rate), Burst: int(apiKey.Burst)}, now)
+		l.keyBuckets[key] = b
+	}
+	b.lastUsed = now
+	return b, nil
+}
+
+// cleanup forgets the buckets not used for a while, they would be full by now anyway
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < cleanupPeriodConst {
+		return
+	}
+	l.lastCleanup = now
+	for id, b := range l.ipBuckets {
+		if now.Sub(b.lastUsed) > idleTimeoutConst {
+			delete(l.ipBuckets, id)
+		}
+	}
+	for key, b := range l.keyBuckets {
+		if now.Sub(b.lastUsed) > idleTimeoutConst {
+			delete(l.keyBuckets, key)
+		}
+	}
+	for key, expiration := range l.invalidKeys {
+		if !now.Before(expiration) {
+			delete(l.invalidKeys, key)
+		}
+	}
+}
+
+// APIKeys returns all the API keys known to the node
+func (l *Limiter) APIKeys() ([]*registry.APIKey, error) {
+	return l.registry.APIKeys()
+}
+
+// GetAPIKey returns the API key, or nil if it is not known
+func (l *Limiter) GetAPIKey(key string) (*registry.APIKey, error) {
+	return l.registry.GetAPIKey(key)
+}
+
+// SaveAPIKey creates or updates the API key. A random key is generated, if
+// the key is empty, and the default quota is used, if the rate is 0.
+func (l *Limiter) SaveAPIKey(apiKey *registry.APIKey) (*registry.APIKey, error) {
+	ret := *apiKey
+	if ret.Key == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		ret.Key = hex.EncodeToString(buf)
+	}
+	if ret.Rate == 0 {
+		ret.Rate = uint32(l.config.APIKeyDefault.Rate)
+		ret.Burst = uint32(l.config.APIKeyDefault.Burst)
+	}
+	if ret.Burst < ret.Rate {
+		ret.Burst = ret.Rate
+	}
+	if err := l.registry.SaveAPIKey(&ret); err != nil {
+		return nil, err
+	}
+	l.forgetKeyBucket(ret.Key)
+	return &ret, nil
+}
+
+// DeleteAPIKey deletes the API key, the requests with it are rejected afterwards
+func (l *Limiter) DeleteAPIKey(key string) error {
+	if err := l.registry.DeleteAPIKey(key); err != nil {
+		return err
+	}
+	l.forgetKeyBucket(key)
+	return nil
+}
+
+func (l *Limiter) forgetKeyBucket(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.keyBuckets, key)
+	delete(l.invalidKeys, key)
+	l.keysVersion++
+}
@@ -0,0 +1,265 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = "chain1"
+
+type testEnv struct {
+	t       *testing.T
+	echo    *echo.Echo
+	limiter *Limiter
+}
+
+func newTestEnv(t *testing.T, config *Config) *testEnv {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	nodeSigner, err := signer.NewLocal(keystore.NewDBKeyStore(store), log)
+	require.NoError(t, err)
+	reg := registry.NewRegistry(log, store, nodeSigner)
+	limiter := New(config, reg, metrics.DefaultWebAPIMetrics(), log)
+
+	e := echo.New()
+	e.HTTPErrorHandler = httperrors.HTTPErrorHandler
+	pub := e.Group("")
+	pub.Use(limiter.Middleware())
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	pub.GET(routes.Info(), ok)
+	pub.POST(routes.NewRequest(":chainID"), ok)
+	pub.POST(routes.NewRequestBatch(":chainID"), func(c echo.Context) error {
+		// the body must still be readable by the handler
+		body, err := io.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		require.NotEmpty(t, body)
+		return c.NoContent(http.StatusOK)
+	})
+	pub.POST(routes.CallViewByName(":chainID", ":contractHname", ":fname"), ok)
+	pub.POST(routes.EVMJSONRPC(":chainID"), func(c echo.Context) error {
+		// the body must still be readable by the JSON-RPC server
+		body, err := io.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), "jsonrpc")
+		return c.NoContent(http.StatusOK)
+	})
+	return &testEnv{t: t, echo: e, limiter: limiter}
+}
+
+func (env *testEnv) call(method, path, body, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if strings.HasPrefix(body, "{") || strings.HasPrefix(body, "[") {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	req.RemoteAddr = "10.0.0.1:1234"
+	if apiKey != "" {
+		req.Header.Set(APIKeyHeader, apiKey)
+	}
+	rec := httptest.NewRecorder()
+	env.echo.ServeHTTP(rec, req)
+	return rec
+}
+
+// countAllowed calls the route n times and returns the number of requests,
+// which were not rate limited
+func (env *testEnv) countAllowed(n int, method, path, body, apiKey string) int {
+	allowed := 0
+	for i := 0; i < n; i++ {
+		rec := env.call(method, path, body, apiKey)
+		switch rec.Code {
+		case http.StatusOK:
+			allowed++
+		case http.StatusTooManyRequests:
+			require.NotEmpty(env.t, rec.Header().Get(echo.HeaderRetryAfter))
+		default:
+			env.t.Fatalf("unexpected status %d", rec.Code)
+		}
+	}
+	return allowed
+}
+
+func testConfig() *Config {
+	config := DefaultConfig()
+	config.Groups = map[string]Limit{
+		GroupDefault:  {Rate: 1, Burst: 10},
+		GroupRequest:  {Rate: 1, Burst: 3},
+		GroupCallView: {Rate: 1, Burst: 10},
+		GroupEVM:      {Rate: 1, Burst: 20},
+	}
+	return config
+}
+
+func TestLimitByGroup(t *testing.T) {
+	env := newTestEnv(t, testConfig())
+
+	require.Equal(t, 3, env.countAllowed(5, http.MethodPost, routes.NewRequest(testChainID), "", ""))
+	// the other groups have buckets of their own
+	require.Equal(t, 10, env.countAllowed(12, http.MethodGet, routes.Info(), "", ""))
+
+	// another client has its own bucket
+	req := httptest.NewRequest(http.MethodPost, routes.NewRequest(testChainID), nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	rec := httptest.NewRecorder()
+	env.echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestViewCost(t *testing.T) {
+	config := testConfig()
+	config.ViewCosts = map[string]map[string]int{
+		"accounts": {"totalAssets": 5},
+	}
+	env := newTestEnv(t, config)
+
+	expensive := routes.CallViewByName(testChainID, isc.Hn("accounts").String(), "totalAssets")
+	require.Equal(t, 2, env.countAllowed(3, http.MethodPost, expensive, "", ""))
+
+	env = newTestEnv(t, config)
+	cheap := routes.CallViewByName(testChainID, isc.Hn("accounts").String(), "balance")
+	require.Equal(t, 10, env.countAllowed(11, http.MethodPost, cheap, "", ""))
+}
+
+func TestJSONRPCCost(t *testing.T) {
+	env := newTestEnv(t, testConfig())
+	path := routes.EVMJSONRPC(testChainID)
+
+	// eth_getLogs costs 10
+	single := `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[]}`
+	require.Equal(t, 2, env.countAllowed(3, http.MethodPost, path, single, ""))
+
+	// a batch costs the sum of its methods: 5 + 1
+	env = newTestEnv(t, testConfig())
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[]},{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber","params":[]}]`
+	require.Equal(t, 3, env.countAllowed(4, http.MethodPost, path, batch, ""))
+}
+
+func TestBatchCost(t *testing.T) {
+	env := newTestEnv(t, testConfig())
+	path := routes.NewRequestBatch(testChainID)
+
+	// a batch costs the number of its requests
+	jsonBatch := `{"Requests":["AAA=","AAA="]}`
+	require.Equal(t, 1, env.countAllowed(2, http.MethodPost, path, jsonBatch, ""))
+
+	env = newTestEnv(t, testConfig())
+	binaryBatch := string([]byte{3, 0, 1, 2, 3})
+	require.Equal(t, 1, env.countAllowed(2, http.MethodPost, path, binaryBatch, ""))
+
+	// a batch costing more than the burst is never allowed, it is rejected with the max size
+	env = newTestEnv(t, testConfig())
+	largeBatch := `{"Requests":["AAA=","AAA=","AAA=","AAA="]}`
+	rec := env.call(http.MethodPost, path, largeBatch, "")
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Contains(t, rec.Body.String(), "the maximum allowed at once is 3")
+	// and it does not spend the quota
+	require.Equal(t, 1, env.countAllowed(2, http.MethodPost, path, binaryBatch, ""))
+
+	// the quota of an API key may allow larger batches than the one of the IP
+	env = newTestEnv(t, testConfig())
+	apiKey, err := env.limiter.SaveAPIKey(&registry.APIKey{Name: "client", Rate: 1, Burst: 8})
+	require.NoError(t, err)
+	rec = env.call(http.MethodPost, path, largeBatch, apiKey.Key)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAPIKey(t *testing.T) {
+	env := newTestEnv(t, testConfig())
+
+	rec := env.call(http.MethodPost, routes.NewRequest(testChainID), "", "unknown")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	apiKey, err := env.limiter.SaveAPIKey(&registry.APIKey{Name: "client", Rate: 1, Burst: 8})
+	require.NoError(t, err)
+	require.NotEmpty(t, apiKey.Key)
+
+	// the quota of the key is shared by all groups, and is independent of the IP
+	require.Equal(t, 5, env.countAllowed(5, http.MethodPost, routes.NewRequest(testChainID), "", apiKey.Key))
+	// the request with the unknown key has been charged to the IP
+	require.Equal(t, 2, env.countAllowed(5, http.MethodPost, routes.NewRequest(testChainID), "", ""))
+	require.Equal(t, 3, env.countAllowed(5, http.MethodGet, routes.Info(), "", apiKey.Key))
+
+	// updating the key resets its bucket
+	apiKey.Burst = 2
+	_, err = env.limiter.SaveAPIKey(apiKey)
+	require.NoError(t, err)
+	require.Equal(t, 2, env.countAllowed(5, http.MethodGet, routes.Info(), "", apiKey.Key))
+
+	// the default quota is used, if none is given
+	defaultKey, err := env.limiter.SaveAPIKey(&registry.APIKey{Key: "default"})
+	require.NoError(t, err)
+	require.EqualValues(t, DefaultConfig().APIKeyDefault.Rate, defaultKey.Rate)
+	require.EqualValues(t, DefaultConfig().APIKeyDefault.Burst, defaultKey.Burst)
+
+	keys, err := env.limiter.APIKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	require.NoError(t, env.limiter.DeleteAPIKey(apiKey.Key))
+	rec = env.call(http.MethodGet, routes.Info(), "", apiKey.Key)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// countingRegistry counts the API keys read from the registry
+type countingRegistry struct {
+	registry.APIKeyRegistryProvider
+	reads int
+}
+
+func (r *countingRegistry) GetAPIKey(key string) (*registry.APIKey, error) {
+	r.reads++
+	return r.APIKeyRegistryProvider.GetAPIKey(key)
+}
+
+func TestUnknownAPIKey(t *testing.T) {
+	env := newTestEnv(t, testConfig())
+	reg := &countingRegistry{APIKeyRegistryProvider: env.limiter.registry}
+	env.limiter.registry = reg
+
+	// the lookups of unknown keys are charged to the client IP
+	for i := 0; i < 3; i++ {
+		rec := env.call(http.MethodPost, routes.NewRequest(testChainID), "", fmt.Sprintf("unknown%d", i))
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	rec := env.call(http.MethodPost, routes.NewRequest(testChainID), "", "unknown3")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, 3, reg.reads)
+
+	// an unknown key is remembered, the registry is not read again
+	for i := 0; i < 3; i++ {
+		rec = env.call(http.MethodGet, routes.Info(), "", "unknown0")
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	require.Equal(t, 3, reg.reads)
+
+	// until the key is created
+	_, err := env.limiter.SaveAPIKey(&registry.APIKey{Key: "unknown0", Rate: 1, Burst: 8})
+	require.NoError(t, err)
+	require.Equal(t, 8, env.countAllowed(10, http.MethodGet, routes.Info(), "", "unknown0"))
+	require.Equal(t, 4, reg.reads)
+
+	// a known key is not charged to the client IP, even if its quota is spent
+	require.Equal(t, 7, env.countAllowed(10, http.MethodGet, routes.Info(), "", ""))
+}
+
+func TestDisabledLimit(t *testing.T) {
+	config := testConfig()
+	config.Groups[GroupRequest] = Limit{Rate: 0}
+	env := newTestEnv(t, config)
+	require.Equal(t, 100, env.countAllowed(100, http.MethodPost, routes.NewRequest(testChainID), "", ""))
+}
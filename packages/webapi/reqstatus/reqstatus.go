@@ -7,17 +7,21 @@ import (
 	"time"
 
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/optimism"
 	"github.com/iotaledger/wasp/packages/util/panicutil"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
 )
 
 type reqstatusWebAPI struct {
@@ -42,6 +46,17 @@ func AddEndpoints(server echoswagger.ApiRouter, getChain chains.ChainProvider) {
 		AddParamPath("", "reqID", "Request ID").
 		AddParamBody(model.WaitRequestProcessedParams{}, "Params", "Optional parameters", false).
 		AddResponse(http.StatusOK, "Request Receipt", model.RequestReceiptResponse{}, nil)
+
+	server.GET(routes.RequestLifecycle(":chainID", ":reqID"), r.handleRequestLifecycle).
+		SetSummary("Get the current stage of a given request in the node, and its recent history").
+		AddParamPath("", "chainID", "ChainID (bech32)").
+		AddParamPath("", "reqID", "Request ID").
+		AddResponse(http.StatusOK, "Request lifecycle", model.RequestLifecycleResponse{}, nil)
+
+	server.GET(routes.RequestLifecycleWebSocket(":chainID", ":reqID"), r.handleRequestLifecycleWebSocket).
+		SetSummary("Stream the stages of a given request over a websocket, until it is confirmed").
+		AddParamPath("", "chainID", "ChainID (bech32)").
+		AddParamPath("", "reqID", "Request ID")
 }
 
 func (r *reqstatusWebAPI) handleRequestReceipt(c echo.Context) error {
@@ -116,6 +131,70 @@ func (r *reqstatusWebAPI) handleWaitRequestProcessed(c echo.Context) error {
 	}
 }
 
+func (r *reqstatusWebAPI) handleRequestLifecycle(c echo.Context) error {
+	ch, reqID, err := r.parseParams(c)
+	if err != nil {
+		return err
+	}
+
+	res, err := getRequestLifecycle(ch, reqID)
+	if err != nil {
+		return httperrors.ServerError(err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// handleRequestLifecycleWebSocket sends the lifecycle of the request each time
+// it enters a new stage. The connection is closed once the request is confirmed.
+func (r *reqstatusWebAPI) handleRequestLifecycleWebSocket(c echo.Context) error {
+	ch, reqID, err := r.parseParams(c)
+	if err != nil {
+		return err
+	}
+
+	conn, err := websocket.Accept(c.Response(), c.Request(), &websocket.AcceptOptions{
+		InsecureSkipVerify: true, // TODO: make accept origin configurable
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close(websocket.StatusInternalError, "something went wrong")
+	ctx := conn.CloseRead(c.Request().Context())
+
+	// the stage is read again on each event, so the events may be coalesced
+	stageChanged := make(chan struct{}, 1)
+	closure := ch.RequestLifecycle().Attach(func(ev *lifecycle.Event) {
+		if ev.RequestID != reqID {
+			return
+		}
+		select {
+		case stageChanged <- struct{}{}:
+		default:
+		}
+	})
+	defer ch.RequestLifecycle().Detach(closure)
+
+	for {
+		res, err := getRequestLifecycle(ch, reqID)
+		if err != nil {
+			conn.Close(websocket.StatusInternalError, err.Error())
+			return nil
+		}
+		if err := wsjson.Write(ctx, conn, res); err != nil {
+			return nil
+		}
+		if res.Stage == string(lifecycle.StageConfirmed) {
+			conn.Close(websocket.StatusNormalClosure, "request confirmed")
+			return nil
+		}
+		select {
+		case <-stageChanged:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func (r *reqstatusWebAPI) parseParams(c echo.Context) (chain.ChainRequests, isc.RequestID, error) {
 	chainID, err := isc.ChainIDFromString(c.Param("chainID"))
 	if err != nil {
@@ -156,6 +235,35 @@ func doGetISCReceipt(ch chain.ChainRequests, reqID isc.RequestID) (*model.Reques
 	}, nil
 }
 
+// getRequestLifecycle returns the stage of the request tracked by the node. The
+// requests unknown to the tracker, e.g. processed before a restart of the node,
+// are looked up in the block log.
+func getRequestLifecycle(ch chain.ChainRequests, reqID isc.RequestID) (*model.RequestLifecycleResponse, error) {
+	status := ch.RequestLifecycle().Get(reqID)
+	ret := model.NewRequestLifecycleResponse(status)
+	if status.Stage != lifecycle.StageUnknown {
+		return ret, nil
+	}
+	var receipt *blocklog.RequestReceipt
+	err := optimism.RetryOnStateInvalidated(func() (err error) {
+		panicCatchErr := panicutil.CatchPanicReturnError(func() {
+			receipt, err = ch.GetRequestReceipt(reqID)
+		}, coreutil.ErrorStateInvalidated)
+		if err != nil {
+			return err
+		}
+		return panicCatchErr
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("error getting request receipt: %s", err)
+	}
+	if receipt != nil {
+		ret.Stage = string(lifecycle.StageConfirmed)
+		ret.BlockIndex = receipt.BlockIndex
+	}
+	return ret, nil
+}
+
 func getISCReceipt(ch chain.ChainRequests, reqID isc.RequestID) (ret *model.RequestReceiptResponse, err error) {
 	err = optimism.RetryOnStateInvalidated(func() (err error) {
 		panicCatchErr := panicutil.CatchPanicReturnError(func() {
@@ -1,12 +1,17 @@
 package reqstatus
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/iotaledger/hive.go/events"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/cryptolib"
 	"github.com/iotaledger/wasp/packages/isc"
@@ -15,10 +20,15 @@ import (
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/packages/webapi/testutil"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
 )
 
-type mockChain struct{}
+type mockChain struct {
+	requestLifecycle *lifecycle.Tracker
+}
 
 var _ chain.ChainRequests = &mockChain{}
 
@@ -70,6 +80,18 @@ func (m *mockChain) EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsg
 	panic("not implemented")
 }
 
+func (m *mockChain) EnqueueOffLedgerRequestMsgs(msgs []*messages.OffLedgerRequestMsgIn) {
+	panic("not implemented")
+}
+
+func (m *mockChain) GetMempoolRequests() []isc.Request {
+	panic("not implemented")
+}
+
+func (m *mockChain) RequestLifecycle() *lifecycle.Tracker {
+	return m.requestLifecycle
+}
+
 func TestRequestReceipt(t *testing.T) {
 	r := &reqstatusWebAPI{func(chainID *isc.ChainID) chain.ChainRequests {
 		return &mockChain{}
@@ -95,3 +117,107 @@ func TestRequestReceipt(t *testing.T) {
 
 	require.NotEmpty(t, res)
 }
+
+func TestRequestLifecycle(t *testing.T) {
+	tracker := lifecycle.New(lifecycle.DefaultMaxRequests)
+	r := &reqstatusWebAPI{func(chainID *isc.ChainID) chain.ChainRequests {
+		return &mockChain{requestLifecycle: tracker}
+	}}
+
+	chainID := isc.RandomChainID()
+	reqID := isc.NewRequestID(iotago.TransactionID{}, 0)
+	tracker.Record(lifecycle.StageMempool, 0, "", reqID)
+	tracker.Record(lifecycle.StageProposed, 0, "", reqID)
+	tracker.Record(lifecycle.StageSkipped, 0, "nonce too low", reqID)
+
+	var res model.RequestLifecycleResponse
+	testutil.CallWebAPIRequestHandler(
+		t,
+		r.handleRequestLifecycle,
+		http.MethodGet,
+		routes.RequestLifecycle(":chainID", ":reqID"),
+		map[string]string{
+			"chainID": chainID.String(),
+			"reqID":   reqID.String(),
+		},
+		nil,
+		&res,
+		http.StatusOK,
+	)
+
+	require.EqualValues(t, lifecycle.StageSkipped, res.Stage)
+	require.Equal(t, reqID, res.RequestID.RequestID())
+	require.Len(t, res.History, 3)
+	require.Equal(t, "nonce too low", res.History[2].Reason)
+}
+
+func TestRequestLifecycleFromReceipt(t *testing.T) {
+	// the request is not tracked, e.g. the node was restarted after processing it
+	r := &reqstatusWebAPI{func(chainID *isc.ChainID) chain.ChainRequests {
+		return &mockChain{requestLifecycle: lifecycle.New(lifecycle.DefaultMaxRequests)}
+	}}
+
+	chainID := isc.RandomChainID()
+	reqID := isc.NewRequestID(iotago.TransactionID{}, 0)
+
+	var res model.RequestLifecycleResponse
+	testutil.CallWebAPIRequestHandler(
+		t,
+		r.handleRequestLifecycle,
+		http.MethodGet,
+		routes.RequestLifecycle(":chainID", ":reqID"),
+		map[string]string{
+			"chainID": chainID.String(),
+			"reqID":   reqID.String(),
+		},
+		nil,
+		&res,
+		http.StatusOK,
+	)
+
+	require.EqualValues(t, lifecycle.StageConfirmed, res.Stage)
+	require.EqualValues(t, 111, res.BlockIndex)
+	require.Empty(t, res.History)
+}
+
+func TestRequestLifecycleWebSocket(t *testing.T) {
+	tracker := lifecycle.New(lifecycle.DefaultMaxRequests)
+	r := &reqstatusWebAPI{func(chainID *isc.ChainID) chain.ChainRequests {
+		return &mockChain{requestLifecycle: tracker}
+	}}
+
+	e := echo.New()
+	e.GET(routes.RequestLifecycleWebSocket(":chainID", ":reqID"), r.handleRequestLifecycleWebSocket)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	chainID := isc.RandomChainID()
+	reqID := isc.NewRequestID(iotago.TransactionID{}, 0)
+	otherReqID := isc.NewRequestID(iotago.TransactionID{}, 1)
+	tracker.Record(lifecycle.StageMempool, 0, "", reqID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + routes.RequestLifecycleWebSocket(chainID.String(), reqID.String())
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	require.NoError(t, err)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	// the current stage is sent first
+	var res model.RequestLifecycleResponse
+	require.NoError(t, wsjson.Read(ctx, conn, &res))
+	require.EqualValues(t, lifecycle.StageMempool, res.Stage)
+
+	tracker.Record(lifecycle.StageProposed, 0, "", otherReqID)
+	tracker.Record(lifecycle.StageProcessed, 5, "", reqID)
+	require.NoError(t, wsjson.Read(ctx, conn, &res))
+	require.EqualValues(t, lifecycle.StageProcessed, res.Stage)
+	require.EqualValues(t, 5, res.BlockIndex)
+
+	// the stream ends with the confirmation
+	tracker.Record(lifecycle.StageConfirmed, 5, "", reqID)
+	require.NoError(t, wsjson.Read(ctx, conn, &res))
+	require.EqualValues(t, lifecycle.StageConfirmed, res.Stage)
+	err = wsjson.Read(ctx, conn, &res)
+	require.Equal(t, websocket.StatusNormalClosure, websocket.CloseStatus(err))
+}
@@ -0,0 +1,174 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/labstack/echo/v4"
+)
+
+const maxBatchSizeConst = 1000
+
+// handleNewRequestBatch validates the requests against a single state and
+// enqueues the accepted ones to the chain at once. The result of each request
+// is returned, a rejected request does not reject the batch.
+func (o *offLedgerReqAPI) handleNewRequestBatch(c echo.Context) error {
+	chainID, err := isc.ChainIDFromString(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := o.getChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Unknown chain: %s", chainID.String()))
+	}
+	reqs, decodeErrs, err := parseBatch(c)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*model.OffLedgerRequestResult, len(reqs))
+	reject := func(i int, reason string) {
+		results[i].Error = reason
+	}
+	var candidates []isc.OffLedgerRequest
+	var candidateIndexes []int
+	seen := make(map[isc.RequestID]bool)
+	for i, req := range reqs {
+		results[i] = &model.OffLedgerRequestResult{}
+		if decodeErrs[i] != nil {
+			reject(i, decodeErrs[i].Error())
+			continue
+		}
+		reqID := req.ID()
+		rid := model.NewRequestID(reqID)
+		results[i].RequestID = &rid
+		if seen[reqID] {
+			reject(i, "duplicate request in the batch")
+			continue
+		}
+		if o.requestsCache.Get(reqID) != nil {
+			reject(i, "request already processed")
+			continue
+		}
+		if err := req.VerifySignature(); err != nil {
+			// not cached and not seen, the same request may follow with valid signatures
+			reject(i, fmt.Sprintf("could not verify: %s", err.Error()))
+			continue
+		}
+		seen[reqID] = true
+		if !req.ChainID().Equals(chainID) {
+			// do not add to cache, it can still be sent to the correct chain
+			reject(i, "Request is for a different chain")
+			continue
+		}
+		candidates = append(candidates, req)
+		candidateIndexes = append(candidateIndexes, i)
+	}
+
+	if len(candidates) > 0 {
+		rejected, err := o.checkRequests(ch, candidates)
+		if err != nil {
+			o.log.Errorf("webapi.offledger - check batch: %v", err)
+			return httperrors.ServerError("internal error")
+		}
+		msgs := make([]*messages.OffLedgerRequestMsgIn, 0, len(candidates))
+		for j, req := range candidates {
+			o.requestsCache.Set(req.ID(), true)
+			if rejected[j] != nil {
+				reject(candidateIndexes[j], rejected[j].Error())
+				continue
+			}
+			results[candidateIndexes[j]].Accepted = true
+			msgs = append(msgs, &messages.OffLedgerRequestMsgIn{
+				OffLedgerRequestMsg: messages.OffLedgerRequestMsg{
+					ChainID: ch.ID(),
+					Req:     req,
+				},
+				SenderPubKey: o.nodePubKey,
+			})
+		}
+		if len(msgs) > 0 {
+			ch.EnqueueOffLedgerRequestMsgs(msgs)
+		}
+	}
+
+	return c.JSON(http.StatusOK, &model.OffLedgerRequestBatchResponse{Results: results})
+}
+
+// parseBatch decodes the requests of the batch. A request which cannot be
+// decoded is rejected alone in the JSON format, while it rejects the whole batch
+// in the binary format.
+func parseBatch(c echo.Context) (reqs []isc.OffLedgerRequest, decodeErrs []error, err error) {
+	contentType := c.Request().Header.Get("Content-Type")
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		r := new(model.OffLedgerRequestBatchBody)
+		if err = c.Bind(r); err != nil {
+			return nil, nil, httperrors.BadRequest("error parsing requests from payload")
+		}
+		if err = checkBatchSize(len(r.Requests)); err != nil {
+			return nil, nil, err
+		}
+		reqs = make([]isc.OffLedgerRequest, len(r.Requests))
+		decodeErrs = make([]error, len(r.Requests))
+		for i, reqBytes := range r.Requests {
+			reqs[i], decodeErrs[i] = decodeOffLedgerRequest(marshalutil.New(reqBytes.Bytes()))
+		}
+		return reqs, decodeErrs, nil
+	}
+
+	// binary format
+	batchBytes, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, nil, httperrors.BadRequest("error parsing requests from payload")
+	}
+	mu := marshalutil.New(batchBytes)
+	n, err := mu.ReadUint16()
+	if err != nil {
+		return nil, nil, httperrors.BadRequest("error parsing requests from payload")
+	}
+	if err = checkBatchSize(int(n)); err != nil {
+		return nil, nil, err
+	}
+	reqs = make([]isc.OffLedgerRequest, n)
+	decodeErrs = make([]error, n)
+	for i := range reqs {
+		rGeneric, err := isc.NewRequestFromMarshalUtil(mu)
+		if err != nil {
+			return nil, nil, httperrors.BadRequest(fmt.Sprintf("error parsing request %d from payload", i))
+		}
+		var ok bool
+		if reqs[i], ok = rGeneric.(isc.OffLedgerRequest); !ok {
+			decodeErrs[i] = fmt.Errorf("error parsing request: off-ledger request expected")
+		}
+	}
+	return reqs, decodeErrs, nil
+}
+
+func checkBatchSize(n int) error {
+	if n == 0 {
+		return httperrors.BadRequest("empty batch")
+	}
+	if n > maxBatchSizeConst {
+		return httperrors.BadRequest(fmt.Sprintf("too many requests in the batch, the maximum is %d", maxBatchSizeConst))
+	}
+	return nil
+}
+
+func decodeOffLedgerRequest(mu *marshalutil.MarshalUtil) (isc.OffLedgerRequest, error) {
+	rGeneric, err := isc.NewRequestFromMarshalUtil(mu)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode off-ledger request: %v", err)
+	}
+	req, ok := rGeneric.(isc.OffLedgerRequest)
+	if !ok {
+		return nil, fmt.Errorf("error parsing request: off-ledger request is expected")
+	}
+	return req, nil
+}
@@ -24,16 +24,20 @@ import (
 
 type (
 	getAccountAssetsFn        func(ch chain.ChainCore, agentID isc.AgentID) (*isc.FungibleTokens, error)
+	gasFeePayerFn             func(ch chain.ChainCore, req isc.OffLedgerRequest) (isc.AgentID, error)
 	hasRequestBeenProcessedFn func(ch chain.ChainCore, reqID isc.RequestID) (bool, error)
 	checkNonceFn              func(ch chain.ChainCore, req isc.OffLedgerRequest) error
+	checkRequestsFn           func(ch chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error)
 )
 
 func AddEndpoints(
 	server echoswagger.ApiRouter,
 	getChain chains.ChainProvider,
 	getChainBalance getAccountAssetsFn,
+	gasFeePayer gasFeePayerFn,
 	hasRequestBeenProcessed hasRequestBeenProcessedFn,
 	checkNonce checkNonceFn,
+	checkRequests checkRequestsFn,
 	nodePubKey *cryptolib.PublicKey,
 	cacheTTL time.Duration,
 	log *logger.Logger,
@@ -41,8 +45,10 @@ func AddEndpoints(
 	instance := &offLedgerReqAPI{
 		getChain:                getChain,
 		getAccountAssets:        getChainBalance,
+		gasFeePayer:             gasFeePayer,
 		hasRequestBeenProcessed: hasRequestBeenProcessed,
 		checkNonce:              checkNonce,
+		checkRequests:           checkRequests,
 		requestsCache:           expiringcache.New(cacheTTL),
 		nodePubKey:              nodePubKey,
 		log:                     log,
@@ -56,13 +62,25 @@ func AddEndpoints(
 			"Offledger Request encoded in base64. Optionally, the body can be the binary representation of the offledger request, but mime-type must be specified to \"application/octet-stream\"",
 			false).
 		AddResponse(http.StatusAccepted, "Request submitted", nil, nil)
+
+	server.POST(routes.NewRequestBatch(":chainID"), instance.handleNewRequestBatch).
+		SetSummary("Post a batch of off-ledger requests").
+		AddParamPath("", "chainID", "chainID represented in base58").
+		AddParamBody(
+			model.OffLedgerRequestBatchBody{Requests: []model.Bytes{"base64 string"}},
+			"Requests",
+			fmt.Sprintf("Up to %d offledger requests encoded in base64. Optionally, the body can be the binary representation of the batch (the number of requests as uint16, followed by the requests), but mime-type must be specified to \"application/octet-stream\"", maxBatchSizeConst),
+			false).
+		AddResponse(http.StatusOK, "Result of each request", model.OffLedgerRequestBatchResponse{}, nil)
 }
 
 type offLedgerReqAPI struct {
 	getChain                chains.ChainProvider
 	getAccountAssets        getAccountAssetsFn
+	gasFeePayer             gasFeePayerFn
 	hasRequestBeenProcessed hasRequestBeenProcessedFn
 	checkNonce              checkNonceFn
+	checkRequests           checkRequestsFn
 	requestsCache           *expiringcache.ExpiringCache
 	nodePubKey              *cryptolib.PublicKey
 	log                     *logger.Logger
@@ -80,9 +98,9 @@ func (o *offLedgerReqAPI) handleNewRequest(c echo.Context) error {
 		return httperrors.BadRequest("request already processed")
 	}
 
-	// check req signature
+	// check req signature. The request is not cached, the ID doesn't cover the signatures, so the same
+	// request can still be sent with valid ones (e.g. a multisig request signed by more signers)
 	if err := offLedgerReq.VerifySignature(); err != nil {
-		o.requestsCache.Set(reqID, true)
 		return httperrors.BadRequest(fmt.Sprintf("could not verify: %s", err.Error()))
 	}
 
@@ -110,15 +128,20 @@ func (o *offLedgerReqAPI) handleNewRequest(c echo.Context) error {
 		return httperrors.BadRequest("request already processed")
 	}
 
-	// check user has on-chain balance
-	assets, err := o.getAccountAssets(ch, offLedgerReq.SenderAccount())
+	// check the gas fee payer (the sender, or the sponsor allowed by the chain) has on-chain balance
+	payer, err := o.gasFeePayer(ch, offLedgerReq)
+	if err != nil {
+		o.log.Errorf("webapi.offledger - gas fee payer: %v", err)
+		return httperrors.ServerError("Unable to get the gas fee payer")
+	}
+	assets, err := o.getAccountAssets(ch, payer)
 	if err != nil {
 		o.log.Errorf("webapi.offledger - account balance: %v", err)
 		return httperrors.ServerError("Unable to get account balance")
 	}
 
 	if assets.IsEmpty() {
-		return httperrors.BadRequest(fmt.Sprintf("No balance on account %s", offLedgerReq.SenderAccount().String()))
+		return httperrors.BadRequest(fmt.Sprintf("No balance on account %s", payer.String()))
 	}
 
 	if err := o.checkNonce(ch, offLedgerReq); err != nil {
@@ -9,8 +9,11 @@ import (
 	"github.com/iotaledger/hive.go/kvstore"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/lifecycle"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/evm/evmindex"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
 	util "github.com/iotaledger/wasp/packages/testutil"
@@ -21,6 +24,8 @@ import (
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/packages/webapi/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 )
 
 type mockedChain struct {
@@ -47,6 +52,14 @@ func (m *mockedChain) DetachFromRequestProcessed(attachID *events.Closure) {
 	panic("implement me")
 }
 
+func (m *mockedChain) GetMempoolRequests() []isc.Request {
+	panic("implement me")
+}
+
+func (m *mockedChain) RequestLifecycle() *lifecycle.Tracker {
+	panic("implement me")
+}
+
 // chain.ChainEntry implementation
 
 func (m *mockedChain) ReceiveTransaction(_ *iotago.Transaction) {
@@ -85,10 +98,18 @@ func (*mockedChain) GetDB() kvstore.KVStore {
 	panic("unimplemented")
 }
 
+func (*mockedChain) GetEVMIndex() *evmindex.Index {
+	panic("unimplemented")
+}
+
 func (*mockedChain) GetTimeData() time.Time {
 	panic("unimplemented")
 }
 
+func (*mockedChain) GetSyncInfo() *chain.SyncInfo {
+	panic("unimplemented")
+}
+
 // private methods
 
 func createMockedGetChain(t *testing.T) chains.ChainProvider {
@@ -105,6 +126,10 @@ func getAccountBalanceMocked(_ chain.ChainCore, _ isc.AgentID) (*isc.FungibleTok
 	return isc.NewFungibleBaseTokens(100), nil
 }
 
+func gasFeePayerMocked(_ chain.ChainCore, req isc.OffLedgerRequest) (isc.AgentID, error) {
+	return req.SenderAccount(), nil
+}
+
 func hasRequestBeenProcessedMocked(ret bool) hasRequestBeenProcessedFn {
 	return func(_ chain.ChainCore, _ isc.RequestID) (bool, error) {
 		return ret, nil
@@ -115,12 +140,18 @@ func checkNonceMocked(ch chain.ChainCore, req isc.OffLedgerRequest) error {
 	return nil
 }
 
+func checkRequestsMocked(ch chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error) {
+	return make([]error, len(reqs)), nil
+}
+
 func newMockedAPI(t *testing.T) *offLedgerReqAPI {
 	return &offLedgerReqAPI{
 		getChain:                createMockedGetChain(t),
 		getAccountAssets:        getAccountBalanceMocked,
+		gasFeePayer:             gasFeePayerMocked,
 		hasRequestBeenProcessed: hasRequestBeenProcessedMocked(false),
 		checkNonce:              checkNonceMocked,
+		checkRequests:           checkRequestsMocked,
 		requestsCache:           expiringcache.New(10 * time.Second),
 	}
 }
@@ -163,8 +194,197 @@ func TestRequestAlreadyProcessed(t *testing.T) {
 	testRequest(t, instance, chainID, body, http.StatusBadRequest)
 }
 
+func TestGasFeePayerBalance(t *testing.T) {
+	instance := newMockedAPI(t)
+	sponsor := isc.NewRandomAgentID()
+	instance.getAccountAssets = func(_ chain.ChainCore, agentID isc.AgentID) (*isc.FungibleTokens, error) {
+		if agentID.Equals(sponsor) {
+			return isc.NewFungibleBaseTokens(100), nil
+		}
+		return isc.NewEmptyAssets(), nil
+	}
+	chainID := isc.RandomChainID()
+	body := util.DummyOffledgerRequest(chainID).Bytes()
+	testRequest(t, instance, chainID, body, http.StatusBadRequest)
+
+	// the sponsor pays, the sender does not need a balance
+	instance.gasFeePayer = func(_ chain.ChainCore, _ isc.OffLedgerRequest) (isc.AgentID, error) {
+		return sponsor, nil
+	}
+	body = util.DummyOffledgerRequest(chainID).Bytes()
+	testRequest(t, instance, chainID, body, http.StatusAccepted)
+}
+
 func TestWrongChainID(t *testing.T) {
 	instance := newMockedAPI(t)
 	body := util.DummyOffledgerRequest(isc.RandomChainID()).Bytes()
 	testRequest(t, instance, isc.RandomChainID(), body, http.StatusBadRequest)
 }
+
+// partiallySignedRequest returns a request of a 2 of 2 multisig account, signed by one of the signers,
+// and the same request signed by both
+func partiallySignedRequest(t *testing.T, chainID *isc.ChainID) (partial, full isc.OffLedgerRequest) {
+	k1, k2 := cryptolib.NewKeyPair(), cryptolib.NewKeyPair()
+	policy, err := isc.NewMultisigPolicy(2, k1.GetPublicKey(), k2.GetPublicKey())
+	require.NoError(t, err)
+	req, err := isc.NewOffLedgerMultisigRequest(chainID, isc.NewMultisigAgentID(policy), policy, isc.Hn("c"), isc.Hn("f"), nil, 1).Sign(k1)
+	require.NoError(t, err)
+	// Sign adds the signature to the request, the partially signed one is kept as a copy
+	partialReq, err := isc.NewRequestFromBytes(req.Bytes())
+	require.NoError(t, err)
+	partial = partialReq.(isc.OffLedgerRequest)
+	full, err = req.Sign(k2)
+	require.NoError(t, err)
+	return partial, full
+}
+
+func TestNewRequestInvalidSignatureNotCached(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := isc.RandomChainID()
+	partial, full := partiallySignedRequest(t, chainID)
+	require.Equal(t, partial.ID(), full.ID())
+
+	testRequest(t, instance, chainID, partial.Bytes(), http.StatusBadRequest)
+	testRequest(t, instance, chainID, full.Bytes(), http.StatusAccepted)
+	testRequest(t, instance, chainID, full.Bytes(), http.StatusBadRequest)
+}
+
+func testRequestBatch(t *testing.T, instance *offLedgerReqAPI, chainID *isc.ChainID, body interface{}, expectedStatus int) *model.OffLedgerRequestBatchResponse {
+	res := &model.OffLedgerRequestBatchResponse{}
+	var resBody interface{}
+	if expectedStatus < 400 {
+		resBody = res
+	}
+	testutil.CallWebAPIRequestHandler(
+		t,
+		instance.handleNewRequestBatch,
+		http.MethodPost,
+		routes.NewRequestBatch(":chainID"),
+		map[string]string{"chainID": chainID.String()},
+		body,
+		resBody,
+		expectedStatus,
+	)
+	return res
+}
+
+// withEnqueuedRequests counts the requests enqueued to the chain
+func withEnqueuedRequests(t *testing.T, instance *offLedgerReqAPI) *int {
+	enqueued := new(int)
+	instance.getChain = func(chainID *isc.ChainID) chain.Chain {
+		chainCore := testchain.NewMockedChainCore(t, chainID, testlogger.NewLogger(t))
+		chainCore.OnOffLedgerRequest(func(msg *messages.OffLedgerRequestMsgIn) {
+			*enqueued++
+		})
+		return &mockedChain{chainCore}
+	}
+	return enqueued
+}
+
+func TestNewRequestBatchBase64(t *testing.T) {
+	instance := newMockedAPI(t)
+	enqueued := withEnqueuedRequests(t, instance)
+	chainID := isc.RandomChainID()
+	reqs := []isc.OffLedgerRequest{util.DummyOffledgerRequest(chainID), util.DummyOffledgerRequest(chainID)}
+	body := model.OffLedgerRequestBatchBody{Requests: []model.Bytes{
+		model.NewBytes(reqs[0].Bytes()),
+		model.NewBytes(reqs[1].Bytes()),
+		model.NewBytes([]byte{1, 2, 3}),
+	}}
+
+	res := testRequestBatch(t, instance, chainID, body, http.StatusOK)
+	require.Len(t, res.Results, 3)
+	for i, req := range reqs {
+		require.True(t, res.Results[i].Accepted)
+		require.Equal(t, req.ID(), res.Results[i].RequestID.RequestID())
+	}
+	require.False(t, res.Results[2].Accepted)
+	require.NotEmpty(t, res.Results[2].Error)
+	require.Equal(t, 2, *enqueued)
+}
+
+func TestNewRequestBatchBinary(t *testing.T) {
+	instance := newMockedAPI(t)
+	enqueued := withEnqueuedRequests(t, instance)
+	chainID := isc.RandomChainID()
+	reqs := []isc.OffLedgerRequest{util.DummyOffledgerRequest(chainID), util.DummyOffledgerRequest(chainID)}
+
+	res := testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes(reqs), http.StatusOK)
+	require.Len(t, res.Results, 2)
+	require.True(t, res.Results[0].Accepted)
+	require.True(t, res.Results[1].Accepted)
+	require.Equal(t, 2, *enqueued)
+
+	// the binary batch is rejected as a whole, if it cannot be decoded
+	body := model.OffLedgerRequestBatchBytes(reqs)
+	testRequestBatch(t, instance, chainID, body[:len(body)-10], http.StatusBadRequest)
+}
+
+func TestNewRequestBatchRejected(t *testing.T) {
+	instance := newMockedAPI(t)
+	enqueued := withEnqueuedRequests(t, instance)
+	chainID := isc.RandomChainID()
+	processed := util.DummyOffledgerRequest(chainID)
+	instance.checkRequests = func(ch chain.ChainCore, reqs []isc.OffLedgerRequest) ([]error, error) {
+		ret := make([]error, len(reqs))
+		for i, req := range reqs {
+			if req.ID() == processed.ID() {
+				ret[i] = xerrors.New("request already processed")
+			}
+		}
+		return ret, nil
+	}
+
+	accepted := util.DummyOffledgerRequest(chainID)
+	reqs := []isc.OffLedgerRequest{
+		accepted,
+		processed,
+		util.DummyOffledgerRequest(isc.RandomChainID()),
+		accepted,
+	}
+	res := testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes(reqs), http.StatusOK)
+	require.Len(t, res.Results, 4)
+	require.True(t, res.Results[0].Accepted)
+	require.Equal(t, "request already processed", res.Results[1].Error)
+	require.Equal(t, "Request is for a different chain", res.Results[2].Error)
+	require.Equal(t, "duplicate request in the batch", res.Results[3].Error)
+	require.Equal(t, 1, *enqueued)
+
+	// the accepted request is not accepted again
+	res = testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes(reqs[:1]), http.StatusOK)
+	require.False(t, res.Results[0].Accepted)
+	require.Equal(t, 1, *enqueued)
+}
+
+func TestNewRequestBatchInvalidSignature(t *testing.T) {
+	instance := newMockedAPI(t)
+	enqueued := withEnqueuedRequests(t, instance)
+	chainID := isc.RandomChainID()
+	partial, full := partiallySignedRequest(t, chainID)
+
+	// the partially signed request doesn't block the fully signed one, neither in the batch nor later
+	res := testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes([]isc.OffLedgerRequest{partial, full}), http.StatusOK)
+	require.False(t, res.Results[0].Accepted)
+	require.True(t, res.Results[1].Accepted)
+	require.Equal(t, 1, *enqueued)
+
+	instance = newMockedAPI(t)
+	enqueued = withEnqueuedRequests(t, instance)
+	res = testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes([]isc.OffLedgerRequest{partial}), http.StatusOK)
+	require.False(t, res.Results[0].Accepted)
+	res = testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes([]isc.OffLedgerRequest{full}), http.StatusOK)
+	require.True(t, res.Results[0].Accepted)
+	require.Equal(t, 1, *enqueued)
+}
+
+func TestNewRequestBatchSize(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := isc.RandomChainID()
+	testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBody{}, http.StatusBadRequest)
+
+	reqs := make([]isc.OffLedgerRequest, maxBatchSizeConst+1)
+	for i := range reqs {
+		reqs[i] = util.DummyOffledgerRequest(chainID)
+	}
+	testRequestBatch(t, instance, chainID, model.OffLedgerRequestBatchBytes(reqs), http.StatusBadRequest)
+}
@@ -11,6 +11,10 @@ func NewRequest(chainID string) string {
 	return "/chain/" + chainID + "/request"
 }
 
+func NewRequestBatch(chainID string) string {
+	return "/chain/" + chainID + "/requests"
+}
+
 func CallViewByName(chainID, contractHname, functionName string) string {
 	return "/chain/" + chainID + "/contract/" + contractHname + "/callview/" + functionName
 }
@@ -27,10 +31,22 @@ func WaitRequestProcessed(chainID, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/wait"
 }
 
+func RequestLifecycle(chainID, reqID string) string {
+	return "/chain/" + chainID + "/request/" + reqID + "/lifecycle"
+}
+
+func RequestLifecycleWebSocket(chainID, reqID string) string {
+	return "/chain/" + chainID + "/request/" + reqID + "/lifecycle/ws"
+}
+
 func StateGet(chainID, key string) string {
 	return "/chain/" + chainID + "/state/" + key
 }
 
+func AccountHistory(chainID, agentID string) string {
+	return "/chain/" + chainID + "/account/" + agentID + "/history"
+}
+
 func RequestIDByEVMTransactionHash(chainID, txHash string) string {
 	return "/chain/" + chainID + "/evm/reqid/" + txHash
 }
@@ -87,6 +103,10 @@ func DKSharesGet(sharedAddress string) string {
 	return "/adm/dks/" + sharedAddress
 }
 
+func DKSharesReshare(sharedAddress string) string {
+	return "/adm/dks/" + sharedAddress + "/reshare"
+}
+
 func PeeringSelfGet() string {
 	return "/adm/peering/self"
 }
@@ -119,6 +139,14 @@ func AdmNodeOwnerCertificate() string {
 	return "/adm/node/owner/certificate"
 }
 
+func AdmAPIKeys() string {
+	return "/adm/apikeys"
+}
+
+func AdmAPIKey(key string) string {
+	return "/adm/apikeys/" + key
+}
+
 func Shutdown() string {
 	return "/adm/shutdown"
 }
@@ -45,6 +45,8 @@ func run(_ *node.Plugin) {
 		parameters.GetInt(parameters.OffledgerBroadcastUpToNPeers),
 		time.Duration(parameters.GetInt(parameters.OffledgerBroadcastInterval))*time.Millisecond,
 		parameters.GetBool(parameters.PullMissingRequestsFromCommittee),
+		parameters.GetBool(parameters.EVMArchiveIndex),
+		parameters.GetInt(parameters.VMParallelWorkers),
 		peering.DefaultNetworkProvider(),
 		database.GetOrCreateKVStore,
 	)
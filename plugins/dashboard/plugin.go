@@ -150,6 +150,14 @@ func (w *waspServices) GetChainConsensusPipeMetrics(chainID *isc.ChainID) (chain
 	return ch.GetConsensusPipeMetrics(), nil
 }
 
+func (w *waspServices) GetChainSyncInfo(chainID *isc.ChainID) (*chain.SyncInfo, error) {
+	ch := chains.AllChains().Get(chainID)
+	if ch == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Chain not found")
+	}
+	return ch.GetSyncInfo(), nil
+}
+
 func (w *waspServices) CallView(chainID *isc.ChainID, scName, funName string, params dict.Dict) (dict.Dict, error) {
 	ch := chains.AllChains().Get(chainID)
 	if ch == nil {
@@ -27,7 +27,6 @@ func Init() *hive_node.Plugin {
 		defaultNode, err = dkg_pkg.NewNode(
 			reg.GetNodeIdentity(),
 			peeringProvider,
-			reg,
 			log.Desugar().WithOptions(zap.IncreaseLevel(logger.LevelWarn)).Sugar(),
 		)
 		if err != nil {
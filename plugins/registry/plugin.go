@@ -4,9 +4,14 @@
 package registry
 
 import (
+	"os"
+
 	"github.com/iotaledger/hive.go/logger"
 	hive_node "github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/plugins/database"
 )
 
@@ -22,9 +27,15 @@ func DefaultRegistry() *registry.Impl {
 // Init is an entry point for the plugin.
 func Init() *hive_node.Plugin {
 	configure := func(_ *hive_node.Plugin) {
+		log := logger.NewLogger(pluginName)
+		nodeSigner, err := signer.New(signerConfig(), database.GetRegistryKVStore(), log)
+		if err != nil {
+			log.Panicf("Cannot open the signer: %v", err)
+		}
 		defaultRegistry = registry.NewRegistry(
-			logger.NewLogger(pluginName),
+			log,
 			database.GetRegistryKVStore(),
+			nodeSigner,
 		)
 	}
 	run := func(_ *hive_node.Plugin) {
@@ -32,3 +43,17 @@ func Init() *hive_node.Plugin {
 	}
 	return hive_node.NewPlugin(pluginName, nil, hive_node.Enabled, configure, run)
 }
+
+func signerConfig() *signer.Config {
+	passphrase := os.Getenv(keystore.PassphraseEnvVar)
+	os.Unsetenv(keystore.PassphraseEnvVar) // Don't pass it to the child processes.
+	token := os.Getenv(signer.TokenEnvVar)
+	os.Unsetenv(signer.TokenEnvVar)
+	return &signer.Config{
+		Type:       parameters.GetString(parameters.RegistryKeyStoreType),
+		File:       parameters.GetString(parameters.RegistryKeyStoreFile),
+		Passphrase: []byte(passphrase),
+		Socket:     parameters.GetString(parameters.RegistryKeyStoreSocket),
+		Token:      []byte(token),
+	}
+}
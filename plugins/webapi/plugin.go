@@ -3,6 +3,7 @@ package webapi
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"time"
 
@@ -24,13 +25,15 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pangpanglabs/echoswagger/v2"
+	"google.golang.org/grpc"
 )
 
 // PluginName is the name of the web API plugin.
 const PluginName = "WebAPI"
 
 var (
-	Server echoswagger.ApiRoot
+	Server     echoswagger.ApiRoot
+	GRPCServer *grpc.Server
 
 	log        *logger.Logger
 	allMetrics *metricspkg.Metrics
@@ -65,6 +68,9 @@ func worker(ctx context.Context) {
 			}
 		}
 	}()
+	if GRPCServer != nil {
+		go serveGRPC()
+	}
 
 	// stop if we are shutting down or the server could not be started
 	select {
@@ -79,6 +85,36 @@ func worker(ctx context.Context) {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Errorf("error stopping: %s", err)
 	}
+	if GRPCServer != nil {
+		stopGRPC(ctx)
+	}
+}
+
+func serveGRPC() {
+	bindAddr := parameters.GetString(parameters.WebAPIGRPCBindAddress)
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		log.Errorf("error listening for gRPC: %s", err)
+		return
+	}
+	log.Infof("gRPC API started, bind-address=%s", bindAddr)
+	if err := GRPCServer.Serve(listener); err != nil {
+		log.Errorf("error serving gRPC: %s", err)
+	}
+}
+
+// stopGRPC closes the streams of the subscribers after the timeout
+func stopGRPC(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		GRPCServer.GracefulStop()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		GRPCServer.Stop()
+	}
 }
 
 func initWebAPI() {
@@ -111,7 +147,7 @@ func initWebAPI() {
 	if parameters.GetBool(parameters.MetricsEnabled) {
 		allMetrics = metrics.AllMetrics()
 	}
-	webapi.Init(
+	GRPCServer = webapi.Init(
 		Server,
 		network,
 		tnm,
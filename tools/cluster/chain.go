@@ -233,11 +233,21 @@ func (ch *Chain) GetAllBlockInfoRecordsReverse(nodeIndex ...int) ([]*blocklog.Bl
 
 func (ch *Chain) ContractRegistry(nodeIndex ...int) (map[isc.Hname]*root.ContractRecord, error) {
 	cl := ch.SCClient(root.Contract.Hname(), nil, nodeIndex...)
-	ret, err := cl.CallView(root.ViewGetContractRecords.Name, nil)
+	ret := make(map[isc.Hname]*root.ContractRecord)
+	err := cl.CallViewAllPages(root.ViewGetContractRecords.Name, nil, func(page dict.Dict) error {
+		recs, err := root.DecodeContractRegistry(collections.NewMapReadOnly(page, root.StateVarContractRegistry))
+		if err != nil {
+			return err
+		}
+		for hname, rec := range recs {
+			ret[hname] = rec
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return root.DecodeContractRegistry(collections.NewMapReadOnly(ret, root.StateVarContractRegistry))
+	return ret, nil
 }
 
 func (ch *Chain) GetCounterValue(inccounterSCHname isc.Hname, nodeIndex ...int) (int64, error) {
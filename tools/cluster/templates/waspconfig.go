@@ -81,6 +81,9 @@ const WaspConfig = `
     "directory": "wal",
     "enabled": true
   },
+  "statemgr": {
+    "fastSyncThreshold": 0
+  },
   "debug": {
     "rawblocksEnabled": false,
     "rawblocksDirectory": "blocks"
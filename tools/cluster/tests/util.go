@@ -8,8 +8,8 @@ import (
 
 	"github.com/iotaledger/wasp/contracts/native/inccounter"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/isc/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/codec"
-	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/corecontracts"
@@ -40,11 +40,7 @@ func (e *ChainEnv) checkCoreContracts() {
 		require.NoError(e.t, err)
 		require.EqualValues(e.t, e.Chain.Description, desc)
 
-		records, err := e.Chain.SCClient(root.Contract.Hname(), nil, i).
-			CallView(root.ViewGetContractRecords.Name, nil)
-		require.NoError(e.t, err)
-
-		contractRegistry, err := root.DecodeContractRegistry(collections.NewMapReadOnly(records, root.StateVarContractRegistry))
+		contractRegistry, err := e.Chain.ContractRegistry(i)
 		require.NoError(e.t, err)
 		for _, rec := range corecontracts.All {
 			cr := contractRegistry[rec.Hname()]
@@ -102,21 +98,20 @@ func (e *ChainEnv) checkBalanceOnChain(agentID isc.AgentID, assetID []byte, expe
 }
 
 func (e *ChainEnv) getAccountsOnChain() []isc.AgentID {
-	r, err := e.Chain.Cluster.WaspClient(0).CallView(
-		e.Chain.ChainID, accounts.Contract.Hname(), accounts.ViewAccounts.Name, nil,
-	)
-	require.NoError(e.t, err)
-
 	ret := make([]isc.AgentID, 0)
-	for key := range r {
-		aid, err := isc.AgentIDFromBytes([]byte(key))
+	var cursor []byte
+	for {
+		r, err := e.Chain.Cluster.WaspClient(0).CallView(
+			e.Chain.ChainID, accounts.Contract.Hname(), accounts.ViewAccounts.Name, coreutil.PageParams(nil, cursor, 0),
+		)
 		require.NoError(e.t, err)
-
-		ret = append(ret, aid)
+		accs, err := accounts.DecodeAccounts(r)
+		require.NoError(e.t, err)
+		ret = append(ret, accs...)
+		if cursor = coreutil.NextPageCursor(r); cursor == nil {
+			return ret
+		}
 	}
-	require.NoError(e.t, err)
-
-	return ret
 }
 
 func (e *ChainEnv) getBalancesOnChain() map[string]*isc.FungibleTokens {
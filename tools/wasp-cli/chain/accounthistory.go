@@ -0,0 +1,66 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+)
+
+func accountHistoryCmd() *cobra.Command {
+	var offset, limit uint32
+	cmd := &cobra.Command{
+		Use:   "account-history [<agentid>]",
+		Short: "Show the history of the movements of the given (default: your) L2 account",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var agentID isc.AgentID
+			if len(args) == 0 {
+				agentID = isc.NewAgentID(wallet.Load().Address())
+			} else {
+				var err error
+				agentID, err = isc.NewAgentIDFromString(args[0])
+				log.Check(err)
+			}
+
+			res, err := Client().WaspClient.AccountHistory(GetCurrentChainID(), agentID, offset, limit)
+			log.Check(err)
+
+			log.Printf("Showing %d of %d entries of %s\n", len(res.Entries), res.Total, agentID)
+			if res.First > 0 {
+				log.Printf("The entries before #%d were pruned, the chain keeps the latest %d entries of each account\n", res.First, res.Retention)
+			}
+			header := []string{"block", "request", "reason", "token", "delta", "counterparty"}
+			rows := make([][]string, len(res.Entries))
+			for i, e := range res.Entries {
+				token := util.BaseTokenStr
+				switch {
+				case e.NativeTokenID != "":
+					token = e.NativeTokenID
+				case e.NFTID != "":
+					token = "NFT " + e.NFTID
+				}
+				counterparty := "L1"
+				if e.Counterparty != "" {
+					counterparty = e.Counterparty
+				}
+				rows[i] = []string{
+					fmt.Sprintf("%d", e.BlockIndex),
+					string(e.RequestID),
+					e.Reason,
+					token,
+					e.Delta,
+					counterparty,
+				}
+			}
+			log.PrintTable(header, rows)
+		},
+	}
+	cmd.Flags().Uint32VarP(&offset, "offset", "o", 0, "index of the first entry")
+	cmd.Flags().Uint32VarP(&limit, "limit", "l", accounts.MaxAccountHistoryPageSize, "maximum number of entries")
+	return cmd
+}
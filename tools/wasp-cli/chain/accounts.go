@@ -20,20 +20,22 @@ var listAccountsCmd = &cobra.Command{
 	Short: "List L2 accounts",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		ret, err := SCClient(accounts.Contract.Hname()).CallView(accounts.ViewAccounts.Name, nil)
+		var rows [][]string
+		err := SCClient(accounts.Contract.Hname()).CallViewAllPages(accounts.ViewAccounts.Name, nil, func(page dict.Dict) error {
+			accs, err := accounts.DecodeAccounts(page)
+			if err != nil {
+				return err
+			}
+			for _, agentID := range accs {
+				rows = append(rows, []string{agentID.String()})
+			}
+			return nil
+		})
 		log.Check(err)
 
-		log.Printf("Total %d account(s) in chain %s\n", len(ret), GetCurrentChainID().String())
+		log.Printf("Total %d account(s) in chain %s\n", len(rows), GetCurrentChainID().String())
 
 		header := []string{"agentid"}
-		rows := make([][]string, len(ret))
-		i := 0
-		for k := range ret {
-			agentID, err := codec.DecodeAgentID([]byte(k))
-			log.Check(err)
-			rows[i] = []string{agentID.String()}
-			i++
-		}
 		log.PrintTable(header, rows)
 	},
 }
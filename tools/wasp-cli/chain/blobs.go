@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/iotaledger/wasp/packages/hashing"
-	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
@@ -69,23 +68,22 @@ var listBlobsCmd = &cobra.Command{
 	Short: "List blobs in chain",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		ret, err := SCClient(blob.Contract.Hname()).CallView(blob.ViewListBlobs.Name, nil)
+		var rows [][]string
+		err := SCClient(blob.Contract.Hname()).CallViewAllPages(blob.ViewListBlobs.Name, nil, func(page dict.Dict) error {
+			blobs, err := blob.DecodeDirectory(page)
+			if err != nil {
+				return err
+			}
+			for hash, size := range blobs {
+				rows = append(rows, []string{hash.String(), fmt.Sprintf("%d", size)})
+			}
+			return nil
+		})
 		log.Check(err)
 
-		blobs, err := blob.DecodeSizesMap(ret)
-		log.Check(err)
-
-		log.Printf("Total %d blob(s) in chain %s\n", len(ret), GetCurrentChainID())
+		log.Printf("Total %d blob(s) in chain %s\n", len(rows), GetCurrentChainID())
 
 		header := []string{"hash", "size"}
-		rows := make([][]string, len(ret))
-		i := 0
-		for k, size := range blobs {
-			hash, err := codec.DecodeHashValue([]byte(k))
-			log.Check(err)
-			rows[i] = []string{hash.String(), fmt.Sprintf("%d", size)}
-			i++
-		}
 		log.PrintTable(header, rows)
 	},
 }
@@ -27,6 +27,7 @@ func Init(rootCmd *cobra.Command) {
 	chainCmd.AddCommand(deployContractCmd)
 	chainCmd.AddCommand(listAccountsCmd)
 	chainCmd.AddCommand(balanceCmd)
+	chainCmd.AddCommand(accountHistoryCmd())
 	chainCmd.AddCommand(depositCmd)
 	chainCmd.AddCommand(listBlobsCmd)
 	chainCmd.AddCommand(storeBlobCmd)
@@ -35,10 +36,12 @@ func Init(rootCmd *cobra.Command) {
 	chainCmd.AddCommand(blockCmd())
 	chainCmd.AddCommand(requestCmd())
 	chainCmd.AddCommand(postRequestCmd())
+	chainCmd.AddCommand(postMultiCallCmd())
 	chainCmd.AddCommand(callViewCmd)
 	chainCmd.AddCommand(activateCmd())
 	chainCmd.AddCommand(deactivateCmd())
 	chainCmd.AddCommand(runDKGCmd())
+	chainCmd.AddCommand(reshareCmd())
 	chainCmd.AddCommand(rotateCmd)
 	chainCmd.AddCommand(changeAccessNodesCmd())
 	chainCmd.AddCommand(addChainCmd)
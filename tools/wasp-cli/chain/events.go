@@ -13,10 +13,12 @@ var eventsCmd = &cobra.Command{
 	Short: "Show events of contract <name>",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		r, err := SCClient(blocklog.Contract.Hname()).CallView(blocklog.ViewGetEventsForContract.Name, dict.Dict{
+		err := SCClient(blocklog.Contract.Hname()).CallViewAllPages(blocklog.ViewGetEventsForContract.Name, dict.Dict{
 			blocklog.ParamContractHname: isc.Hn(args[0]).Bytes(),
+		}, func(page dict.Dict) error {
+			logEvents(page)
+			return nil
 		})
 		log.Check(err)
-		logEvents(r)
 	},
 }
@@ -8,9 +8,7 @@ import (
 
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
 
-	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
-	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
@@ -64,11 +62,7 @@ var infoCmd = &cobra.Command{
 
 			log.Printf("Description: %s\n", govInfo.Description)
 
-			recs, err := SCClient(root.Contract.Hname()).CallView(root.ViewGetContractRecords.Name, nil)
-			log.Check(err)
-			contracts, err := root.DecodeContractRegistry(collections.NewMapReadOnly(recs, root.StateVarContractRegistry))
-			log.Check(err)
-			log.Printf("#Contracts: %d\n", len(contracts))
+			log.Printf("#Contracts: %d\n", len(getContractRecords()))
 
 			log.Printf("Owner: %s\n", govInfo.ChainOwnerID.String())
 
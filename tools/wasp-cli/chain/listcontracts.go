@@ -1,7 +1,9 @@
 package chain
 
 import (
+	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/spf13/cobra"
@@ -12,10 +14,7 @@ var listContractsCmd = &cobra.Command{
 	Short: "List deployed contracts in chain",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		records, err := SCClient(root.Contract.Hname()).CallView(root.ViewGetContractRecords.Name, nil)
-		log.Check(err)
-		contracts, err := root.DecodeContractRegistry(collections.NewMapReadOnly(records, root.StateVarContractRegistry))
-		log.Check(err)
+		contracts := getContractRecords()
 
 		log.Printf("Total %d contracts in chain %s\n", len(contracts), GetCurrentChainID())
 
@@ -42,3 +41,20 @@ var listContractsCmd = &cobra.Command{
 		log.PrintTable(header, rows)
 	},
 }
+
+// getContractRecords fetches the records of all contracts of the chain, page by page
+func getContractRecords() map[isc.Hname]*root.ContractRecord {
+	ret := make(map[isc.Hname]*root.ContractRecord)
+	err := SCClient(root.Contract.Hname()).CallViewAllPages(root.ViewGetContractRecords.Name, nil, func(page dict.Dict) error {
+		recs, err := root.DecodeContractRegistry(collections.NewMapReadOnly(page, root.StateVarContractRegistry))
+		if err != nil {
+			return err
+		}
+		for hname, rec := range recs {
+			ret[hname] = rec
+		}
+		return nil
+	})
+	log.Check(err)
+	return ret
+}
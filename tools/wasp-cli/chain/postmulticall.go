@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+// callSeparator separates the calls in the arguments of the post-multicall command
+const callSeparator = "then"
+
+// parseCalls parses the arguments in the format <name> <funcname> [params] [then <name> <funcname> [params]]...
+// The allowances are assigned to the calls in order
+func parseCalls(args, allowances []string) []*isc.Call {
+	var calls []*isc.Call
+	for len(args) > 0 {
+		if len(args) < 2 {
+			log.Fatalf("each call needs <name> <funcname>")
+		}
+		hname, fname := args[0], args[1]
+		args = args[2:]
+		// params come in groups of 4: <type> <key> <type> <value>
+		n := 0
+		for n+4 <= len(args) && args[n] != callSeparator {
+			n += 4
+		}
+		params := util.EncodeParams(args[:n])
+		args = args[n:]
+		if len(args) > 0 {
+			if args[0] != callSeparator {
+				log.Fatalf("Params format: <type> <key> <type> <value> ...")
+			}
+			args = args[1:]
+		}
+
+		allowance := isc.NewEmptyAllowance()
+		if len(calls) < len(allowances) && allowances[len(calls)] != "" {
+			allowance = isc.NewAllowanceFungibleTokens(util.ParseFungibleTokens(strings.Split(allowances[len(calls)], ",")))
+		}
+		calls = append(calls, isc.NewCall(isc.Hn(hname), isc.Hn(fname), params, allowance))
+	}
+	if len(allowances) > len(calls) {
+		log.Fatalf("%d allowances given for %d calls", len(allowances), len(calls))
+	}
+	if len(calls) > isc.MaxCallsInMultiCallRequest {
+		log.Fatalf("at most %d calls can be posted in one request", isc.MaxCallsInMultiCallRequest)
+	}
+	return calls
+}
+
+func postMultiCallCmd() *cobra.Command {
+	var allowances []string
+
+	cmd := &cobra.Command{
+		Use:   "post-multicall <name> <funcname> [params] [then <name> <funcname> [params]]...",
+		Short: "Post an off-ledger request with several calls",
+		Long: "Post an off-ledger request, which runs the given calls in order. " +
+			"If any of the calls fails, all of them are reverted.",
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			calls := parseCalls(args, allowances)
+			util.WithOffLedgerRequest(GetCurrentChainID(), func() (isc.OffLedgerRequest, error) {
+				return Client().PostMultiCallRequest(calls, chainclient.PostRequestParams{
+					Nonce: uint64(time.Now().UnixNano()),
+				})
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&allowances, "allowance", "l", []string{},
+		"allowance of a call, given once per call in the order of the calls (empty for none). Format: <token-id>:<amount>,<token-id>:amount...")
+
+	return cmd
+}
@@ -1,13 +1,19 @@
 package chain
 
 import (
+	"os"
 	"time"
 
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/client/chainclient"
 	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/transaction"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
 	"github.com/spf13/cobra"
 )
@@ -48,11 +54,55 @@ func postRequest(hname, fname string, params chainclient.PostRequestParams, offL
 	})
 }
 
+// writeUnsignedRequest writes the off-ledger request to the file, to be signed with `wasp-cli sign`.
+// The request is sent by the multisig account if given, by the signer otherwise
+func writeUnsignedRequest(fname, hname, funcname string, params chainclient.PostRequestParams, multisig string) {
+	chainID := GetCurrentChainID()
+	nonce := uint64(time.Now().UnixNano())
+	gasBudget := uint64(gas.MaxGasPerCall)
+	if params.GasBudget != nil {
+		gasBudget = *params.GasBudget
+	}
+	allowance := params.Allowance
+	if allowance == nil {
+		allowance = isc.NewEmptyAllowance()
+	}
+
+	var f *util.OfflineFile
+	if multisig == "" {
+		f = util.NewOfflineRequestFile(chainID, isc.Hn(hname), isc.Hn(funcname), params.Args, allowance, nonce, gasBudget)
+	} else {
+		agentID, err := isc.NewAgentIDFromString(multisig)
+		log.Check(err)
+		account, ok := agentID.(*isc.MultisigAgentID)
+		if !ok {
+			log.Fatalf("%s is not a multisig account", multisig)
+		}
+		ret, err := config.WaspClient().CallView(chainID, accounts.Contract.Hname(), accounts.ViewGetMultisigPolicy.Name, dict.Dict{
+			accounts.ParamAgentID: codec.EncodeAgentID(account),
+		})
+		log.Check(err)
+		if !ret.MustHas(accounts.ParamMultisigPolicy) {
+			log.Fatalf("multisig account %s is not registered on the chain", multisig)
+		}
+		policy, err := isc.MultisigPolicyFromBytes(ret.MustGet(accounts.ParamMultisigPolicy))
+		log.Check(err)
+		req := isc.NewOffLedgerMultisigRequest(chainID, account, policy, isc.Hn(hname), isc.Hn(funcname), params.Args, nonce).
+			WithGasBudget(gasBudget).
+			WithAllowance(allowance)
+		f = util.NewOfflineMultisigRequestFile(req, chainID)
+	}
+	f.Write(fname)
+	log.Printf("Unsigned request written to %s (sign it with: %s sign %s)\n", fname, os.Args[0], fname)
+}
+
 func postRequestCmd() *cobra.Command {
 	var transfer []string
 	var allowance []string
 	var offLedger bool
 	var adjustStorageDeposit bool
+	var unsignedFile string
+	var multisig string
 
 	cmd := &cobra.Command{
 		Use:   "post-request <name> <funcname> [params]",
@@ -69,7 +119,20 @@ func postRequestCmd() *cobra.Command {
 				Transfer:  util.ParseFungibleTokens(transfer),
 				Allowance: isc.NewAllowanceFungibleTokens(allowanceTokens),
 			}
-			postRequest(hname, fname, params, offLedger, adjustStorageDeposit)
+			if unsignedFile == "" {
+				if multisig != "" {
+					log.Fatalf("requests of multisig accounts must be signed with `sign`, use --unsigned")
+				}
+				postRequest(hname, fname, params, offLedger, adjustStorageDeposit)
+				return
+			}
+			if !offLedger {
+				log.Fatalf("only off-ledger requests can be signed with `sign`, use --off-ledger")
+			}
+			if len(transfer) > 0 {
+				log.Fatalf("off-ledger requests can't transfer funds")
+			}
+			writeUnsignedRequest(unsignedFile, hname, fname, params, multisig)
 		},
 	}
 
@@ -83,6 +146,9 @@ func postRequestCmd() *cobra.Command {
 		"post an off-ledger request",
 	)
 	cmd.Flags().BoolVarP(&adjustStorageDeposit, "adjust-storage-deposit", "s", false, "adjusts the amount of base tokens sent, if it's lower than the min storage deposit required")
+	cmd.Flags().StringVarP(&unsignedFile, "unsigned", "", "",
+		"don't sign and post the off-ledger request, write it to the given file to be signed with `sign` and posted with `submit`")
+	cmd.Flags().StringVarP(&multisig, "multisig", "", "", "send the request from the given multisig account, needs --unsigned")
 
 	return cmd
 }
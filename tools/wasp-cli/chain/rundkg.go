@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"os"
 
+	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/client"
 	"github.com/iotaledger/wasp/packages/apilib"
 	"github.com/iotaledger/wasp/packages/parameters"
@@ -34,15 +35,10 @@ func runDKGCmd() *cobra.Command {
 				quorum = defaultQuorum(len(committee))
 			}
 
-			committeePubKeys := make([]string, 0)
-			for _, api := range config.CommitteeAPI(committee) {
-				peerInfo, err := client.NewWaspClient(api).GetPeeringSelf()
-				log.Check(err)
-				committeePubKeys = append(committeePubKeys, peerInfo.PubKey)
-			}
+			pubKeys := committeePubKeys(committee)
 
 			dkgInitiatorIndex := uint16(rand.Intn(len(committee)))
-			stateControllerAddr, err := apilib.RunDKG(config.CommitteeAPI(committee), committeePubKeys, uint16(quorum), dkgInitiatorIndex)
+			stateControllerAddr, err := apilib.RunDKG(config.CommitteeAPI(committee), pubKeys, uint16(quorum), dkgInitiatorIndex)
 			log.Check(err)
 
 			fmt.Fprintf(os.Stdout, "DKG successful, address: %s", stateControllerAddr.Bech32(parameters.L1().Protocol.Bech32HRP))
@@ -53,3 +49,47 @@ func runDKGCmd() *cobra.Command {
 	cmd.Flags().IntVarP(&quorum, "quorum", "", 0, "quorum")
 	return cmd
 }
+
+func committeePubKeys(committee []int) []string {
+	pubKeys := make([]string, 0)
+	for _, api := range config.CommitteeAPI(committee) {
+		peerInfo, err := client.NewWaspClient(api).GetPeeringSelf()
+		log.Check(err)
+		pubKeys = append(pubKeys, peerInfo.PubKey)
+	}
+	return pubKeys
+}
+
+func reshareCmd() *cobra.Command {
+	var (
+		committee []int
+		quorum    int
+		initiator int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reshare <address>",
+		Short: "Moves the distributed key with the given address to the specified nodes, keeping the address",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, sharedAddress, err := iotago.ParseBech32(args[0])
+			log.Check(err)
+			if committee == nil {
+				committee = getAllWaspNodes()
+			}
+			if quorum == 0 {
+				quorum = defaultQuorum(len(committee))
+			}
+
+			err = apilib.ReshareDKG(config.CommitteeAPI([]int{initiator})[0], sharedAddress, committeePubKeys(committee), uint16(quorum))
+			log.Check(err)
+
+			fmt.Fprintf(os.Stdout, "Resharing successful, address: %s", args[0])
+		},
+	}
+
+	cmd.Flags().IntSliceVarP(&committee, "committee", "", nil, "peers receiving the key shares (ex: 0,1,2,3) (default: all nodes)")
+	cmd.Flags().IntVarP(&quorum, "quorum", "", 0, "quorum")
+	cmd.Flags().IntVarP(&initiator, "initiator", "", 0, "node initiating the resharing, must hold a share of the key")
+	return cmd
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbDir            string
+	registryTextFile string
+)
+
+var journalCmd = &cobra.Command{
+	Use:   "journal <command>",
+	Short: "Inspect and replay the consensus journals of a stopped wasp node.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Check(cmd.Help())
+	},
+}
+
+func Init(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(journalCmd)
+	journalCmd.PersistentFlags().StringVarP(&dbDir, "db", "", "waspdb", "path to the database folder of the node")
+	journalCmd.PersistentFlags().StringVarP(&registryTextFile, "registry-file", "", "", "registry file, if the node uses a text registry (registry.useText)")
+
+	journalCmd.AddCommand(listCmd)
+	journalCmd.AddCommand(showCmd)
+	journalCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVarP(&rawBlocksDir, "raw-blocks", "", "", "directory with the raw blocks of the chain (debug.rawblocksDirectory/<chainID>), used for the blocks missing in the chain database")
+}
+
+func parseJournalID(s string) journal.ID {
+	idBytes, err := iotago.DecodeHex(s)
+	log.Check(err)
+	var id journal.ID
+	if len(idBytes) != len(id) {
+		log.Fatalf("journal ID must be %v bytes long", len(id))
+	}
+	copy(id[:], idBytes)
+	return id
+}
+
+func loadDecisions(id journal.ID) []*journal.Decision {
+	store, closeStore := util.OpenRegistryStore(dbDir, registryTextFile)
+	defer closeStore()
+	decisions, err := registry.ConsensusJournalDecisions(store, id)
+	log.Check(err)
+	if len(decisions) == 0 {
+		log.Fatalf("there are no decisions recorded in the journal %v", iotago.EncodeHex(id[:]))
+	}
+	return decisions
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"fmt"
+	"sort"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the consensus journals of the node.",
+	Long: `List the consensus journals of the node.
+There is a journal for each chain and committee, the node has been a member of.
+The chain and the committee are known from the decisions, recorded in the journal.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, closeStore := util.OpenRegistryStore(dbDir, registryTextFile)
+		defer closeStore()
+		journals, err := registry.ConsensusJournals(store)
+		log.Check(err)
+
+		rows := make([][]string, 0, len(journals))
+		for id, logIndex := range journals {
+			decisions, err := registry.ConsensusJournalDecisions(store, id)
+			log.Check(err)
+			chainID, committee := "-", "-"
+			if len(decisions) > 0 {
+				ao := decisions[len(decisions)-1].BaseAliasOutput
+				aliasChainID := isc.ChainIDFromAliasID(ao.GetAliasID())
+				chainID = aliasChainID.String()
+				committee = ao.GetStateAddress().Bech32(parameters.L1().Protocol.Bech32HRP)
+			}
+			rows = append(rows, []string{
+				iotago.EncodeHex(id[:]),
+				chainID,
+				committee,
+				fmt.Sprintf("%d", logIndex),
+				fmt.Sprintf("%d", len(decisions)),
+			})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		log.PrintTable([]string{"journal ID", "chain ID", "committee", "log index", "decisions"}, rows)
+	},
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"strconv"
+
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/chain/consensus/replay"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/coreprocessors"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var rawBlocksDir string
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <journal-id> [<log-index>]",
+	Short: "Run the VM over the decided batches and compare the results with the blocks of the chain.",
+	Long: `Run the VM over the decided batches and compare the results with the blocks of the chain.
+The state is reconstructed from the blocks, saved by the node. The replay is successful,
+if the VM produces the same state commitment, as the block, following the decision.
+All the recorded decisions are replayed, if the log index is not specified.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		decisions := loadDecisions(parseJournalID(args[0]))
+		if len(args) > 1 {
+			logIndex, err := strconv.ParseUint(args[1], 10, 32)
+			log.Check(err)
+			decisions = filterDecisions(decisions, journal.LogIndex(logIndex))
+		}
+		chainID := isc.ChainIDFromAliasID(decisions[0].BaseAliasOutput.GetAliasID())
+		blocks, rawBlocks := loadBlocks(&chainID)
+
+		procConfig := coreprocessors.Config().WithNativeContracts(inccounter.Processor)
+		mismatches := 0
+		for _, decision := range decisions {
+			result, err := replay.Decision(&chainID, blocks, decision, procConfig, log.HiveLogger())
+			if err != nil {
+				log.Printf("Log index %d: cannot replay: %v\n", decision.LogIndex, err)
+				mismatches++
+				continue
+			}
+			status := "no block to compare with"
+			switch {
+			case result.Matches():
+				status = "OK"
+			case result.ExpectedCommitment != nil:
+				status = "MISMATCH, expected " + result.ExpectedCommitment.String()
+				mismatches++
+			}
+			if raw, ok := rawBlocks[result.BlockIndex]; ok && raw.StateCommitment != result.StateCommitment.String() {
+				status += ", raw block " + raw.FileName + " differs"
+			}
+			log.Printf("Log index %d: block %d, state commitment %s, %d requests: %s\n",
+				decision.LogIndex, result.BlockIndex, result.StateCommitment, len(result.Results), status)
+			for _, res := range result.Results {
+				if res.Receipt.Error != nil {
+					log.Verbosef("  request %s failed: %v\n", res.Request.ID(), res.Receipt.Error)
+				}
+			}
+		}
+		if mismatches > 0 {
+			log.Fatalf("%d of %d decisions are not reproduced", mismatches, len(decisions))
+		}
+	},
+}
+
+func filterDecisions(decisions []*journal.Decision, logIndex journal.LogIndex) []*journal.Decision {
+	for _, decision := range decisions {
+		if decision.LogIndex == logIndex {
+			return []*journal.Decision{decision}
+		}
+	}
+	log.Fatalf("decision for log index %d is not recorded in the journal", logIndex)
+	return nil
+}
+
+// loadBlocks takes the blocks from the chain database, and from the raw blocks
+// directory, if it is specified. The raw blocks are used for the blocks missing in the database.
+func loadBlocks(chainID *isc.ChainID) (map[uint32]state.Block, map[uint32]*replay.RawBlock) {
+	store, closeStore := util.OpenChainStore(dbDir, chainID)
+	defer closeStore()
+	blocks, err := replay.LoadBlocks(store)
+	log.Check(err)
+	if rawBlocksDir == "" {
+		return blocks, nil
+	}
+	rawBlocks, err := replay.LoadRawBlocks(rawBlocksDir)
+	log.Check(err)
+	for blockIndex, raw := range rawBlocks {
+		if _, ok := blocks[blockIndex]; !ok {
+			blocks[blockIndex] = raw.Block
+		}
+	}
+	return blocks, rawBlocks
+}
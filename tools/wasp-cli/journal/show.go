@@ -0,0 +1,59 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/chain/consensus"
+	"github.com/iotaledger/wasp/packages/chain/consensus/journal"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <journal-id>",
+	Short: "Show the decisions, recorded in the consensus journal.",
+	Long: `Show the decisions, recorded in the consensus journal.
+For each log index, the batches proposed by the committee nodes and the batch,
+decided by the consensus and run by the VM, are shown.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, decision := range loadDecisions(parseJournalID(args[0])) {
+			showDecision(decision)
+		}
+	},
+}
+
+func showDecision(decision *journal.Decision) {
+	log.Printf("Log index: %d\n", decision.LogIndex)
+	log.Printf("  Base alias output: %s (state index %d)\n",
+		isc.OID(decision.BaseAliasOutput.ID()), decision.BaseAliasOutput.GetStateIndex())
+	log.Printf("  Timestamp: %s\n", decision.TimeAssumption.UTC().Format(time.RFC3339Nano))
+	log.Printf("  Entropy: %s\n", decision.Entropy)
+	log.Printf("  Validator fee target: %s\n", decision.ValidatorFeeTarget)
+	log.Printf("  Proposals:\n")
+	rows := make([][]string, len(decision.Proposals))
+	for i, data := range decision.Proposals {
+		proposal, err := consensus.BatchProposalFromBytes(data)
+		if err != nil {
+			rows[i] = []string{"?", fmt.Sprintf("cannot decode: %v", err), "", ""}
+			continue
+		}
+		rows[i] = []string{
+			fmt.Sprintf("%d", proposal.ValidatorIndex),
+			proposal.TimeData.UTC().Format(time.RFC3339Nano),
+			proposal.FeeDestination.String(),
+			fmt.Sprintf("%v", isc.ShortRequestIDs(proposal.RequestIDs)),
+		}
+	}
+	log.PrintTable([]string{"validator", "timestamp", "fee destination", "requests"}, rows)
+	log.Printf("  Batch:\n")
+	for i, req := range decision.Requests {
+		log.Printf("    %d: %s\n", i, req.ID())
+	}
+	log.Printf("\n")
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var keyStoreFile string
+
+var keyStoreCmd = &cobra.Command{
+	Use:   "keystore <command>",
+	Short: "Manage the key store of a wasp node.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Check(cmd.Help())
+	},
+}
+
+func Init(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(keyStoreCmd)
+	keyStoreCmd.PersistentFlags().StringVarP(&keyStoreFile, "file", "f", "keystore.json", "encrypted key store file")
+
+	keyStoreCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVarP(&dbDir, "db", "", "waspdb", "path to the database folder of the node")
+	migrateCmd.Flags().StringVarP(&registryTextFile, "registry-file", "", "", "registry file, if the node uses a text registry (registry.useText)")
+	migrateCmd.Flags().BoolVarP(&keepPlaintext, "keep-plaintext", "", false, "don't remove the migrated secrets from the database")
+
+	keyStoreCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVarP(&socket, "socket", "s", "keystore.sock", "unix socket to serve the signer on")
+	serveCmd.Flags().UintSliceVarP(&allowedUIDs, "allow-uid", "", nil, "unix users allowed to connect (default: the current user)")
+	serveCmd.Flags().BoolVarP(&allowReshare, "allow-reshare", "", false, "allow dealing the key shares to a new committee")
+}
+
+// readPassphrase takes the passphrase from the environment or asks for it.
+func readPassphrase(confirm bool) []byte {
+	return util.ReadPassphrase(keystore.PassphraseEnvVar, "Key store passphrase", confirm)
+}
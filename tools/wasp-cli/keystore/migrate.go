@@ -0,0 +1,72 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbDir            string
+	registryTextFile string
+	keepPlaintext    bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move the node identity and DKShares from the node database to an encrypted key store file.",
+	Long: `Move the node identity and DKShares from the node database to an encrypted key store file.
+The node must be stopped while migrating. Start it afterwards with registry.keyStore.type=file
+and the passphrase in the ` + keystore.PassphraseEnvVar + ` environment variable.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, closeStore := util.OpenRegistryStore(dbDir, registryTextFile)
+		defer closeStore()
+
+		secrets := make(map[string][]byte)
+		err := store.Iterate(kvstore.KeyPrefix{dbkeys.ObjectTypeNodeIdentity}, func(key kvstore.Key, value kvstore.Value) bool {
+			secrets[string(key)] = append([]byte{}, value...)
+			return true
+		})
+		log.Check(err)
+		err = store.Iterate(kvstore.KeyPrefix{dbkeys.ObjectTypeDistributedKeyData}, func(key kvstore.Key, value kvstore.Value) bool {
+			secrets[string(key)] = append([]byte{}, value...)
+			return true
+		})
+		log.Check(err)
+		if len(secrets) == 0 {
+			log.Printf("There are no secrets to migrate.\n")
+			return
+		}
+
+		ks, err := keystore.NewFileKeyStore(keyStoreFile, readPassphrase(true))
+		log.Check(err)
+		defer ks.Close()
+		for key, value := range secrets {
+			log.Check(ks.Set([]byte(key), value))
+			stored, err := ks.Get([]byte(key))
+			log.Check(err)
+			if !bytes.Equal(stored, value) {
+				log.Fatalf("secret %x was not stored correctly", key)
+			}
+		}
+		log.Printf("Migrated %v secrets to %v.\n", len(secrets), keyStoreFile)
+
+		if keepPlaintext {
+			log.Printf("WARNING: the secrets are still stored in plaintext in the database.\n")
+			return
+		}
+		for key := range secrets {
+			log.Check(store.Delete([]byte(key)))
+		}
+		log.Printf("Removed the migrated secrets from the database.\n")
+	},
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	socket       string
+	allowedUIDs  []uint
+	allowReshare bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a signer for a wasp node, keeping the secrets of an encrypted key store file.",
+	Long: `Run a signer for a wasp node, keeping the secrets of an encrypted key store file.
+Start the node with registry.keyStore.type=remote and registry.keyStore.socket pointing to the socket.
+
+The node identity key and the key shares never leave this process, the node asks it for the
+signatures and runs the DKG and the DSS through it. Only the trusted processes may connect: the ones
+running as the allowed users (the current user by default) and presenting the token taken from the
+` + signer.TokenEnvVar + ` environment variable, if it is set. The node must have the same variable set.
+
+A node dealing its key shares to a new committee could reconstruct the key together with the new
+members, thus the resharing is refused unless --allow-reshare is set. Set it only while rotating
+the committee.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ks, err := keystore.NewFileKeyStore(keyStoreFile, readPassphrase(false))
+		log.Check(err)
+		localSigner, err := signer.NewLocal(ks, log.HiveLogger())
+		log.Check(err)
+		defer localSigner.Close()
+
+		if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Check(err)
+		}
+		listener, err := net.Listen("unix", socket)
+		log.Check(err)
+		log.Check(os.Chmod(socket, 0o600)) // Only the owner can access the secrets.
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			listener.Close()
+		}()
+		config := &signer.ServeConfig{
+			Token:        []byte(os.Getenv(signer.TokenEnvVar)),
+			AllowReshare: allowReshare,
+			OnReject: func(reason error) {
+				log.Printf("Rejected a signer client: %v\n", reason)
+			},
+		}
+		for _, uid := range allowedUIDs {
+			config.AllowedUIDs = append(config.AllowedUIDs, uint32(uid))
+		}
+		log.Printf("Serving the signer of node %v with the key store %v at %v.\n", localSigner.GetPublicKey(), keyStoreFile, socket)
+		log.Check(signer.Serve(listener, localSigner, config))
+	},
+}
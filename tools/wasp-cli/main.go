@@ -9,8 +9,11 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/chain"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/decode"
+	"github.com/iotaledger/wasp/tools/wasp-cli/journal"
+	"github.com/iotaledger/wasp/tools/wasp-cli/keystore"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/metrics"
+	"github.com/iotaledger/wasp/tools/wasp-cli/offline"
 	"github.com/iotaledger/wasp/tools/wasp-cli/peering"
 	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
 	"github.com/spf13/cobra"
@@ -41,6 +44,9 @@ func init() {
 	decode.Init(rootCmd)
 	peering.Init(rootCmd)
 	metrics.Init(rootCmd)
+	keystore.Init(rootCmd)
+	journal.Init(rootCmd)
+	offline.Init(rootCmd)
 }
 
 func main() {
@@ -0,0 +1,16 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package offline
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Init adds the commands to sign requests and transactions prepared with --unsigned, possibly on an
+// air-gapped machine, to sponsor the gas fee of the signed requests and to submit them
+func Init(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(signCmd())
+	rootCmd.AddCommand(sponsorCmd())
+	rootCmd.AddCommand(submitCmd)
+}
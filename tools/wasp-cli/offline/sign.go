@@ -0,0 +1,69 @@
+package offline
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+)
+
+func signCmd() *cobra.Command {
+	var outFile string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "sign <file>",
+		Short: "Sign a request or transaction prepared with --unsigned",
+		Long: "Sign the off-ledger request or L1 transaction in the file with the wallet. " +
+			"It doesn't need a connection to the nodes, so it can be run on an offline machine. " +
+			"Each signer of a multisig request adds its signature to the file.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			f := util.ReadOfflineFile(args[0])
+			description, err := f.Describe()
+			log.Check(err)
+			log.Printf("%s\n\n", description)
+			if !yes {
+				confirmSigning()
+			}
+
+			log.Check(f.Sign(wallet.Load().KeyPair))
+			if outFile == "" {
+				outFile = args[0]
+			}
+			f.Write(outFile)
+			log.Printf("Signed %s written to %s\n", f.Kind, outFile)
+
+			if f.Kind == util.OfflineKindMultisigRequest {
+				if _, _, err := f.SignedRequest(); err != nil {
+					log.Printf("Not ready to submit yet: %v\n", err)
+				} else {
+					log.Printf("The request is signed by enough signers and can be submitted\n")
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFile, "out", "o", "", "file to write the signed request or transaction to (default: the input file)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "sign without asking for confirmation")
+
+	return cmd
+}
+
+func confirmSigning() {
+	// don't prompt if running in a script
+	fi, _ := os.Stdin.Stat()
+	if (fi.Mode() & os.ModeCharDevice) == 0 {
+		log.Fatalf("not signed: confirm with --yes when running in a script")
+	}
+	log.Printf("Sign it? [y/N] ")
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	if strings.ToLower(scanner.Text()) != "y" {
+		log.Fatalf("not signed.")
+	}
+}
@@ -0,0 +1,51 @@
+package offline
+
+import (
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+)
+
+func sponsorCmd() *cobra.Command {
+	var outFile string
+	var maxFee uint64
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "sponsor <file>",
+		Short: "Pay the gas fee of a request signed with `sign`",
+		Long: "Countersign the off-ledger request in the file with the wallet, which then pays the gas fee of the " +
+			"request, up to --max-fee tokens. The chain owner must allow the wallet to sponsor requests, " +
+			"otherwise the sender pays. It doesn't need a connection to the nodes.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if maxFee == 0 {
+				log.Fatalf("--max-fee is required")
+			}
+			f := util.ReadOfflineFile(args[0])
+			if f.Kind != util.OfflineKindOffLedgerRequest {
+				log.Fatalf("only %s can be sponsored", util.OfflineKindOffLedgerRequest)
+			}
+			description, err := f.Describe()
+			log.Check(err)
+			log.Printf("%s\n\n", description)
+			if !yes {
+				confirmSigning()
+			}
+
+			log.Check(f.Sponsor(wallet.Load().KeyPair, maxFee))
+			if outFile == "" {
+				outFile = args[0]
+			}
+			f.Write(outFile)
+			log.Printf("Sponsored request written to %s\n", outFile)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFile, "out", "o", "", "file to write the sponsored request to (default: the input file)")
+	cmd.Flags().Uint64VarP(&maxFee, "max-fee", "", 0, "maximum gas fee paid by the sponsor")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "sponsor without asking for confirmation")
+
+	return cmd
+}
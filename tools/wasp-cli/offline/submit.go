@@ -0,0 +1,35 @@
+package offline
+
+import (
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var submitCmd = &cobra.Command{
+	Use:   "submit <file>",
+	Short: "Submit a request or transaction signed with `sign`",
+	Long: "Post the signed off-ledger request to the chain or the signed transaction to L1. " +
+		"A multisig request must be signed by the threshold of the signers of the account.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		f := util.ReadOfflineFile(args[0])
+		if f.Kind == util.OfflineKindL1Transaction {
+			tx, err := f.SignedTransaction()
+			log.Check(err)
+			util.PostTransaction(tx)
+			txID, err := tx.ID()
+			log.Check(err)
+			log.Printf("Transaction [%v] sent successfully.\n", txID.ToHex())
+			return
+		}
+
+		chainID, req, err := f.SignedRequest()
+		log.Check(err)
+		util.WithOffLedgerRequest(chainID, func() (isc.OffLedgerRequest, error) {
+			return req, config.WaspClient().PostOffLedgerRequest(chainID, req)
+		})
+	},
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/database/dbmanager"
+	"github.com/iotaledger/wasp/packages/database/registrykvstore"
+	"github.com/iotaledger/wasp/packages/database/textdb"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+)
+
+const registryDBName = "CHAIN_REGISTRY"
+
+// OpenRegistryStore opens the registry of a stopped node, either from the
+// database folder or from the text registry file, if it is specified.
+func OpenRegistryStore(dbDir, registryTextFile string) (kvstore.KVStore, func()) {
+	if registryTextFile != "" {
+		if _, err := os.Stat(registryTextFile); err != nil {
+			log.Fatalf("cannot open the registry file: %v", err)
+		}
+		return registrykvstore.New(textdb.NewTextKV(log.HiveLogger(), registryTextFile)), func() {}
+	}
+	return openStore(fmt.Sprintf("%s/%s", dbDir, registryDBName), registrykvstore.New)
+}
+
+// OpenChainStore opens the state database of a chain of a stopped node.
+func OpenChainStore(dbDir string, chainID *isc.ChainID) (kvstore.KVStore, func()) {
+	return openStore(fmt.Sprintf("%s/%s", dbDir, chainID.String()), func(store kvstore.KVStore) kvstore.KVStore {
+		return store
+	})
+}
+
+func openStore(dir string, wrap func(kvstore.KVStore) kvstore.KVStore) (kvstore.KVStore, func()) {
+	if _, err := os.Stat(dir); err != nil {
+		log.Fatalf("cannot open the database: %v", err)
+	}
+	db, err := dbmanager.NewDB(dir)
+	log.Check(err)
+	return wrap(db.NewStore()), func() { log.Check(db.Close()) }
+}
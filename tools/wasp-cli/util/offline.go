@@ -0,0 +1,351 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/transaction"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"golang.org/x/xerrors"
+)
+
+// OfflineFileVersion is the version of the format of the offline signing files
+const OfflineFileVersion = 1
+
+// the kinds of payloads of the offline signing files
+const (
+	OfflineKindOffLedgerRequest = "offLedgerRequest"
+	OfflineKindMultisigRequest  = "multisigRequest"
+	OfflineKindL1Transaction    = "l1Transaction"
+)
+
+// OfflineFile is an unsigned off-ledger request or L1 transaction, written by the commands run with --unsigned,
+// signed with `wasp-cli sign` and posted with `wasp-cli submit`. Binary values are hex encoded
+type OfflineFile struct {
+	Version int    `json:"version"`
+	Kind    string `json:"kind"`
+	// ChainID is the chain of the request, empty for L1 transactions
+	ChainID string `json:"chainID,omitempty"`
+	// Request is the unsigned request of the offLedgerRequest kind
+	Request *UnsignedRequest `json:"request,omitempty"`
+	// Unsigned is the multisig request without signatures or the essence of the L1 transaction
+	Unsigned string `json:"unsigned,omitempty"`
+	// Signed is the signed request or transaction, set by `wasp-cli sign`. Each signer of a multisig request
+	// adds its signature, the request can be submitted once it is signed by the threshold of the signers
+	Signed string `json:"signed,omitempty"`
+}
+
+// UnsignedRequest is an off-ledger request to be signed by a single key
+type UnsignedRequest struct {
+	Contract   string    `json:"contract"`
+	EntryPoint string    `json:"entryPoint"`
+	Params     dict.Dict `json:"params"`
+	Allowance  string    `json:"allowance"`
+	Nonce      uint64    `json:"nonce"`
+	GasBudget  uint64    `json:"gasBudget"`
+}
+
+func NewOfflineRequestFile(chainID *isc.ChainID, contract, entryPoint isc.Hname, params dict.Dict, allowance *isc.Allowance, nonce, gasBudget uint64) *OfflineFile {
+	if allowance == nil {
+		allowance = isc.NewEmptyAllowance()
+	}
+	return &OfflineFile{
+		Version: OfflineFileVersion,
+		Kind:    OfflineKindOffLedgerRequest,
+		ChainID: chainID.String(),
+		Request: &UnsignedRequest{
+			Contract:   contract.String(),
+			EntryPoint: entryPoint.String(),
+			Params:     params,
+			Allowance:  iotago.EncodeHex(allowance.Bytes()),
+			Nonce:      nonce,
+			GasBudget:  gasBudget,
+		},
+	}
+}
+
+func NewOfflineMultisigRequestFile(req isc.UnsignedMultisigRequest, chainID *isc.ChainID) *OfflineFile {
+	return &OfflineFile{
+		Version:  OfflineFileVersion,
+		Kind:     OfflineKindMultisigRequest,
+		ChainID:  chainID.String(),
+		Unsigned: iotago.EncodeHex(req.(isc.Request).Bytes()),
+	}
+}
+
+func NewOfflineTransactionFile(essence *iotago.TransactionEssence) *OfflineFile {
+	data, err := essence.Serialize(serializer.DeSeriModeNoValidation, nil)
+	log.Check(err)
+	return &OfflineFile{
+		Version:  OfflineFileVersion,
+		Kind:     OfflineKindL1Transaction,
+		Unsigned: iotago.EncodeHex(data),
+	}
+}
+
+func ReadOfflineFile(fname string) *OfflineFile {
+	ret, err := ParseOfflineFile(ReadFile(fname))
+	log.Check(err)
+	return ret
+}
+
+// ParseOfflineFile decodes the contents of an offline signing file
+func ParseOfflineFile(data []byte) (*OfflineFile, error) {
+	ret := &OfflineFile{}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	if ret.Version != OfflineFileVersion {
+		return nil, xerrors.Errorf("unsupported version %d of the offline signing file", ret.Version)
+	}
+	return ret, nil
+}
+
+func (f *OfflineFile) Write(fname string) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	log.Check(err)
+	log.Check(os.WriteFile(fname, data, 0o600))
+}
+
+// Describe returns a human readable summary of what is signed, so that the signer can check it
+func (f *OfflineFile) Describe() (string, error) {
+	switch f.Kind {
+	case OfflineKindOffLedgerRequest:
+		if _, err := f.unsignedRequest(); err != nil {
+			return "", err
+		}
+		allowance, err := f.Request.allowance()
+		if err != nil {
+			return "", err
+		}
+		ret := describeRequest(f.ChainID, f.Request.Contract, f.Request.EntryPoint, f.Request.Params, allowance, f.Request.Nonce)
+		if f.Signed != "" {
+			_, req, err := f.SignedRequest()
+			if err != nil {
+				return "", err
+			}
+			ret += fmt.Sprintf("\nsender: %s", req.SenderAccount())
+			if sponsored, ok := req.(isc.SponsoredOffLedgerRequest); ok {
+				if sponsor, maxFee := sponsored.Sponsor(); sponsor != nil {
+					ret += fmt.Sprintf("\ngas fee sponsor: %s, max fee: %d", sponsor, maxFee)
+				}
+			}
+		}
+		return ret, nil
+	case OfflineKindMultisigRequest:
+		req, err := f.multisigRequest()
+		if err != nil {
+			return "", err
+		}
+		target := req.CallTarget()
+		return fmt.Sprintf("%s\nsender: %s\nmultisig policy: %s",
+			describeRequest(f.ChainID, target.Contract.String(), target.EntryPoint.String(), req.Params(), req.Allowance(), req.Nonce()),
+			req.SenderAccount(), req.MultisigPolicy(),
+		), nil
+	case OfflineKindL1Transaction:
+		essence, err := f.essence()
+		if err != nil {
+			return "", err
+		}
+		ret := fmt.Sprintf("L1 transaction with %d inputs", len(essence.Inputs))
+		for i, out := range essence.Outputs {
+			ret += fmt.Sprintf("\noutput #%d: %d base tokens", i, out.Deposit())
+			for _, nt := range out.NativeTokenList() {
+				ret += fmt.Sprintf(", %s of native token %s", nt.Amount, nt.ID.ToHex())
+			}
+			if addr := out.UnlockConditionSet().Address(); addr != nil {
+				// the address is shown in hex, the L1 params may not be available on the offline machine
+				ret += fmt.Sprintf(" to %s", addr.Address)
+			}
+		}
+		return ret, nil
+	}
+	return "", xerrors.Errorf("unknown kind %q", f.Kind)
+}
+
+func describeRequest(chainID, contract, entryPoint string, params dict.Dict, allowance *isc.Allowance, nonce uint64) string {
+	return fmt.Sprintf("off-ledger request to chain %s\ncontract: %s, entry point: %s\nparams: %s\nallowance: %s\nnonce: %d",
+		chainID, contract, entryPoint, params, allowance, nonce)
+}
+
+// Sign signs the unsigned payload with the key. A multisig request gets the signature of the key in addition to
+// the ones it already has
+func (f *OfflineFile) Sign(key *cryptolib.KeyPair) error {
+	switch f.Kind {
+	case OfflineKindOffLedgerRequest:
+		req, err := f.unsignedRequest()
+		if err != nil {
+			return err
+		}
+		f.Signed = iotago.EncodeHex(req.Sign(key).Bytes())
+		return nil
+	case OfflineKindMultisigRequest:
+		req, err := f.multisigRequest()
+		if err != nil {
+			return err
+		}
+		signed, err := req.Sign(key)
+		if err != nil {
+			return err
+		}
+		f.Signed = iotago.EncodeHex(signed.Bytes())
+		return nil
+	case OfflineKindL1Transaction:
+		essence, err := f.essence()
+		if err != nil {
+			return err
+		}
+		tx, err := transaction.SignTxEssence(essence, key)
+		if err != nil {
+			return err
+		}
+		data, err := tx.Serialize(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			return err
+		}
+		f.Signed = iotago.EncodeHex(data)
+		return nil
+	}
+	return xerrors.Errorf("unknown kind %q", f.Kind)
+}
+
+// Sponsor countersigns the signed request with the key of the sponsor, which pays the gas fee of the
+// request up to maxFee tokens
+func (f *OfflineFile) Sponsor(key *cryptolib.KeyPair, maxFee uint64) error {
+	_, req, err := f.SignedRequest()
+	if err != nil {
+		return err
+	}
+	sponsored, ok := req.(isc.SponsoredOffLedgerRequest)
+	if !ok {
+		return xerrors.Errorf("a %s can't be sponsored", f.Kind)
+	}
+	f.Signed = iotago.EncodeHex(sponsored.WithSponsor(key, maxFee).Bytes())
+	return nil
+}
+
+// SignedRequest returns the signed off-ledger request. A multisig request must be signed by the threshold
+// of the signers
+func (f *OfflineFile) SignedRequest() (*isc.ChainID, isc.OffLedgerRequest, error) {
+	if f.Kind != OfflineKindOffLedgerRequest && f.Kind != OfflineKindMultisigRequest {
+		return nil, nil, xerrors.Errorf("not a request: %s", f.Kind)
+	}
+	if f.Signed == "" {
+		return nil, nil, xerrors.New("the request is not signed")
+	}
+	chainID, err := isc.ChainIDFromString(f.ChainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := iotago.DecodeHex(f.Signed)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := isc.NewRequestFromBytes(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	offLedgerReq, ok := req.(isc.OffLedgerRequest)
+	if !ok {
+		return nil, nil, xerrors.New("not an off-ledger request")
+	}
+	if err := offLedgerReq.VerifySignature(); err != nil {
+		return nil, nil, err
+	}
+	return chainID, offLedgerReq, nil
+}
+
+// SignedTransaction returns the signed L1 transaction
+func (f *OfflineFile) SignedTransaction() (*iotago.Transaction, error) {
+	if f.Kind != OfflineKindL1Transaction {
+		return nil, xerrors.Errorf("not an L1 transaction: %s", f.Kind)
+	}
+	if f.Signed == "" {
+		return nil, xerrors.New("the transaction is not signed")
+	}
+	data, err := iotago.DecodeHex(f.Signed)
+	if err != nil {
+		return nil, err
+	}
+	tx := &iotago.Transaction{}
+	if _, err := tx.Deserialize(data, serializer.DeSeriModeNoValidation, nil); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (f *OfflineFile) unsignedRequest() (isc.UnsignedOffLedgerRequest, error) {
+	if f.Request == nil {
+		return nil, xerrors.New("missing request")
+	}
+	chainID, err := isc.ChainIDFromString(f.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := isc.HnameFromString(f.Request.Contract)
+	if err != nil {
+		return nil, err
+	}
+	entryPoint, err := isc.HnameFromString(f.Request.EntryPoint)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := f.Request.allowance()
+	if err != nil {
+		return nil, err
+	}
+	params := f.Request.Params
+	if params == nil {
+		params = dict.New()
+	}
+	return isc.NewOffLedgerRequest(chainID, contract, entryPoint, params, f.Request.Nonce).
+		WithAllowance(allowance).
+		WithGasBudget(f.Request.GasBudget), nil
+}
+
+func (r *UnsignedRequest) allowance() (*isc.Allowance, error) {
+	data, err := iotago.DecodeHex(r.Allowance)
+	if err != nil {
+		return nil, err
+	}
+	return isc.AllowanceFromBytes(data)
+}
+
+// multisigRequest returns the request with the signatures collected so far
+func (f *OfflineFile) multisigRequest() (isc.MultisigRequest, error) {
+	data := f.Signed
+	if data == "" {
+		data = f.Unsigned
+	}
+	b, err := iotago.DecodeHex(data)
+	if err != nil {
+		return nil, err
+	}
+	req, err := isc.NewRequestFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := req.(isc.MultisigRequest)
+	if !ok {
+		return nil, xerrors.New("not a multisig request")
+	}
+	return ret, nil
+}
+
+func (f *OfflineFile) essence() (*iotago.TransactionEssence, error) {
+	data, err := iotago.DecodeHex(f.Unsigned)
+	if err != nil {
+		return nil, err
+	}
+	ret := &iotago.TransactionEssence{}
+	if _, err := ret.Deserialize(data, serializer.DeSeriModeNoValidation, nil); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
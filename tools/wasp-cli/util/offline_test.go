@@ -0,0 +1,167 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/tpkg"
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/isc"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAndRead simulates moving the file between the online and the offline machines
+func writeAndRead(t *testing.T, f *OfflineFile) *OfflineFile {
+	fname := filepath.Join(t.TempDir(), "offline.json")
+	f.Write(fname)
+	ret, err := ParseOfflineFile(ReadFile(fname))
+	require.NoError(t, err)
+	return ret
+}
+
+func TestOfflineRequest(t *testing.T) {
+	chainID := isc.RandomChainID()
+	key := cryptolib.NewKeyPair()
+	params := dict.Dict{"a": []byte{1, 2, 3}}
+	allowance := isc.NewAllowanceBaseTokens(1000)
+
+	f := writeAndRead(t, NewOfflineRequestFile(chainID, isc.Hn("contract"), isc.Hn("func"), params, allowance, 42, 10000))
+	_, _, err := f.SignedRequest()
+	require.ErrorContains(t, err, "not signed")
+	_, err = f.Describe()
+	require.NoError(t, err)
+
+	require.NoError(t, f.Sign(key))
+	f = writeAndRead(t, f)
+	retChainID, req, err := f.SignedRequest()
+	require.NoError(t, err)
+	require.True(t, chainID.Equals(retChainID))
+	require.True(t, req.SenderAccount().Equals(isc.NewAgentID(key.Address())))
+	require.EqualValues(t, isc.Hn("contract"), req.CallTarget().Contract)
+	require.EqualValues(t, isc.Hn("func"), req.CallTarget().EntryPoint)
+	require.EqualValues(t, params, req.Params())
+	require.Equal(t, allowance.Bytes(), req.Allowance().Bytes())
+	require.EqualValues(t, 42, req.Nonce())
+	gasBudget, _ := req.GasBudget()
+	require.EqualValues(t, 10000, gasBudget)
+
+	_, err = f.SignedTransaction()
+	require.Error(t, err)
+
+	t.Run("sponsor", func(t *testing.T) {
+		sponsorKey := cryptolib.NewKeyPair()
+		require.NoError(t, f.Sponsor(sponsorKey, 500))
+		f := writeAndRead(t, f)
+		_, req, err := f.SignedRequest()
+		require.NoError(t, err)
+		require.True(t, req.SenderAccount().Equals(isc.NewAgentID(key.Address())))
+		sponsor, maxFee := req.(isc.SponsoredOffLedgerRequest).Sponsor()
+		require.True(t, sponsor.Equals(isc.NewAgentID(sponsorKey.Address())))
+		require.EqualValues(t, 500, maxFee)
+		desc, err := f.Describe()
+		require.NoError(t, err)
+		require.Contains(t, desc, "gas fee sponsor")
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		f := writeAndRead(t, f)
+		data, err := iotago.DecodeHex(f.Signed)
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xff
+		f.Signed = iotago.EncodeHex(data)
+		_, _, err = f.SignedRequest()
+		require.Error(t, err)
+	})
+}
+
+func TestOfflineMultisigRequest(t *testing.T) {
+	chainID := isc.RandomChainID()
+	k1, k2, k3 := cryptolib.NewKeyPair(), cryptolib.NewKeyPair(), cryptolib.NewKeyPair()
+	policy, err := isc.NewMultisigPolicy(2, k1.GetPublicKey(), k2.GetPublicKey(), k3.GetPublicKey())
+	require.NoError(t, err)
+	unsigned := isc.NewOffLedgerMultisigRequest(chainID, isc.NewMultisigAgentID(policy), policy, isc.Hn("contract"), isc.Hn("func"), dict.New(), 7).
+		WithGasBudget(10000)
+
+	f := writeAndRead(t, NewOfflineMultisigRequestFile(unsigned, chainID))
+	desc, err := f.Describe()
+	require.NoError(t, err)
+	require.Contains(t, desc, "multisig policy")
+
+	// a key that is not a signer of the policy can't sign
+	require.ErrorContains(t, f.Sign(cryptolib.NewKeyPair()), "not a signer")
+	require.Empty(t, f.Signed)
+
+	// the signatures are accumulated, the request is valid once signed by the threshold
+	require.NoError(t, f.Sign(k1))
+	f = writeAndRead(t, f)
+	_, _, err = f.SignedRequest()
+	require.Error(t, err)
+
+	require.NoError(t, f.Sign(k3))
+	f = writeAndRead(t, f)
+	retChainID, req, err := f.SignedRequest()
+	require.NoError(t, err)
+	require.True(t, chainID.Equals(retChainID))
+	require.True(t, req.SenderAccount().Equals(isc.NewMultisigAgentID(policy)))
+	require.EqualValues(t, 7, req.Nonce())
+	require.True(t, policy.Equals(req.(isc.MultisigRequest).MultisigPolicy()))
+}
+
+func TestOfflineTransaction(t *testing.T) {
+	key := cryptolib.NewKeyPair()
+	essence := &iotago.TransactionEssence{
+		NetworkID: tpkg.TestNetworkID,
+		Inputs:    iotago.Inputs{tpkg.RandUTXOInput()},
+		Outputs: iotago.Outputs{
+			&iotago.BasicOutput{
+				Amount: 1337,
+				Conditions: iotago.UnlockConditions{
+					&iotago.AddressUnlockCondition{Address: tpkg.RandEd25519Address()},
+				},
+			},
+		},
+	}
+
+	f := writeAndRead(t, NewOfflineTransactionFile(essence))
+	desc, err := f.Describe()
+	require.NoError(t, err)
+	require.Contains(t, desc, "1337 base tokens")
+	_, err = f.SignedTransaction()
+	require.ErrorContains(t, err, "not signed")
+
+	require.NoError(t, f.Sign(key))
+	f = writeAndRead(t, f)
+	tx, err := f.SignedTransaction()
+	require.NoError(t, err)
+	expected, err := essence.Serialize(serializer.DeSeriModeNoValidation, nil)
+	require.NoError(t, err)
+	actual, err := tx.Essence.Serialize(serializer.DeSeriModeNoValidation, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Len(t, tx.Unlocks, 1)
+	sig := tx.Unlocks[0].(*iotago.SignatureUnlock).Signature.(*iotago.Ed25519Signature)
+	require.EqualValues(t, key.GetPublicKey().AsBytes(), sig.PublicKey[:])
+
+	_, _, err = f.SignedRequest()
+	require.Error(t, err)
+	// a transaction can't be sponsored
+	require.Error(t, f.Sponsor(cryptolib.NewKeyPair(), 100))
+}
+
+func TestOfflineFileVersion(t *testing.T) {
+	_, err := ParseOfflineFile([]byte(`{"version": 2, "kind": "l1Transaction", "unsigned": "0x00"}`))
+	require.ErrorContains(t, err, "unsupported version 2")
+	_, err = ParseOfflineFile([]byte(`{"kind": "l1Transaction", "unsigned": "0x00"}`))
+	require.ErrorContains(t, err, "unsupported version 0")
+	_, err = ParseOfflineFile([]byte(`not json`))
+	require.Error(t, err)
+
+	f, err := ParseOfflineFile([]byte(`{"version": 1, "kind": "unknown"}`))
+	require.NoError(t, err)
+	_, err = f.Describe()
+	require.ErrorContains(t, err, "unknown kind")
+	require.ErrorContains(t, f.Sign(cryptolib.NewKeyPair()), "unknown kind")
+}
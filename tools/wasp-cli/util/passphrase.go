@@ -0,0 +1,45 @@
+package util
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"golang.org/x/term"
+)
+
+// ReadPassphrase takes the passphrase from the environment variable or asks for it.
+func ReadPassphrase(envVar, prompt string, confirm bool) []byte {
+	if passphrase := os.Getenv(envVar); passphrase != "" {
+		return []byte(passphrase)
+	}
+	passphrase := ReadSecret(prompt + ": ")
+	if confirm {
+		if string(passphrase) != string(ReadSecret("Repeat the passphrase: ")) {
+			log.Fatalf("passphrases do not match")
+		}
+	}
+	if len(passphrase) == 0 {
+		log.Fatalf("passphrase cannot be empty")
+	}
+	return passphrase
+}
+
+// ReadSecret reads a line from the terminal without echoing it. When running in a script,
+// the line is read from the standard input.
+func ReadSecret(prompt string) []byte {
+	fi, _ := os.Stdin.Stat()
+	if (fi.Mode() & os.ModeCharDevice) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		log.Check(scanner.Err())
+		return []byte(strings.TrimSpace(scanner.Text()))
+	}
+	log.Printf(prompt)
+	secret, err := term.ReadPassword(int(syscall.Stdin)) //nolint:unconvert // int cast is needed for windows
+	log.Check(err)
+	log.Printf("\n")
+	return secret
+}
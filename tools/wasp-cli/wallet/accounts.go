@@ -0,0 +1,220 @@
+package wallet
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/keystore"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/mr-tron/base58"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize a new encrypted wallet",
+	Long: "Create the encrypted wallet file with a new account named \"default\". " +
+		"The passphrase is taken from " + PassphraseEnvVar + " or asked for.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if viper.GetString(legacySeedKey) != "" {
+			log.Fatalf("there is a plaintext wallet seed in %s, move it to an encrypted wallet with `wallet migrate`", config.ConfigPath)
+		}
+		ks := openWalletFile(true)
+		mnemonic := newAccount(ks, defaultAccountName, cryptolib.CoinTypeIOTA)
+		log.Check(ks.Close())
+		useAccount(defaultAccountName)
+
+		log.Printf("Initialized the encrypted wallet in %s\n", walletFilePath())
+		printMnemonic(mnemonic)
+	},
+}
+
+func walletCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wallet <command>",
+		Short: "Manage the accounts of the encrypted wallet",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log.Check(cmd.Help())
+		},
+	}
+	cmd.AddCommand(listAccountsCmd)
+	cmd.AddCommand(useAccountCmd)
+	cmd.AddCommand(newAccountCmd())
+	cmd.AddCommand(importMnemonicCmd())
+	cmd.AddCommand(exportMnemonicCmd())
+	cmd.AddCommand(migrateCmd())
+	return cmd
+}
+
+var listAccountsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the accounts of the wallet",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ks := openWalletFile(false)
+		accounts := readAccounts(ks)
+		log.Check(ks.Close())
+
+		current := currentAccountName()
+		rows := make([][]string, 0, len(accounts))
+		for _, name := range sortedAccountNames(accounts) {
+			w := accounts[name].load(name, uint32(addressIndex))
+			marker := ""
+			if name == current {
+				marker = "*"
+			}
+			rows = append(rows, []string{marker, name, w.Address().String(), w.EthereumAddress().Hex()})
+		}
+		log.PrintTable([]string{"", "name", "address", "ethereum address"}, rows)
+	},
+}
+
+var useAccountCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make the account the current one",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ks := openWalletFile(false)
+		accounts := readAccounts(ks)
+		log.Check(ks.Close())
+		if _, ok := accounts[args[0]]; !ok {
+			log.Fatalf("account %q not found in the wallet", args[0])
+		}
+		useAccount(args[0])
+		log.Printf("Using account %s\n", args[0])
+	},
+}
+
+func newAccountCmd() *cobra.Command {
+	var coinType uint32
+	cmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Add an account with a new mnemonic to the wallet",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ks := openWalletFile(false)
+			mnemonic := newAccount(ks, args[0], coinType)
+			log.Check(ks.Close())
+			log.Printf("Added account %s, switch to it with `wallet use %s`\n", args[0], args[0])
+			printMnemonic(mnemonic)
+		},
+	}
+	cmd.Flags().Uint32VarP(&coinType, "coin-type", "", cryptolib.CoinTypeIOTA, "SLIP-44 coin type of the Ed25519 derivation path (4218: IOTA, 4219: Shimmer)")
+	return cmd
+}
+
+func importMnemonicCmd() *cobra.Command {
+	var coinType uint32
+	cmd := &cobra.Command{
+		Use:   "import <name>",
+		Short: "Add an account with an existing BIP-39 mnemonic to the wallet",
+		Long: "Add an account with an existing BIP-39 mnemonic to the wallet. " +
+			"The mnemonic is asked for, or read from the standard input when running in a script.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			mnemonic := strings.Join(strings.Fields(string(util.ReadSecret("Mnemonic: "))), " ")
+			_, err := cryptolib.SeedFromMnemonic(mnemonic)
+			log.Check(err)
+
+			ks := openWalletFile(false)
+			addAccount(ks, args[0], &Account{Mnemonic: mnemonic, CoinType: coinType})
+			log.Check(ks.Close())
+			log.Printf("Imported account %s, switch to it with `wallet use %s`\n", args[0], args[0])
+		},
+	}
+	cmd.Flags().Uint32VarP(&coinType, "coin-type", "", cryptolib.CoinTypeIOTA, "SLIP-44 coin type of the Ed25519 derivation path (4218: IOTA, 4219: Shimmer)")
+	return cmd
+}
+
+func exportMnemonicCmd() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Show the mnemonic of the account",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ks := openWalletFile(false)
+			accounts := readAccounts(ks)
+			log.Check(ks.Close())
+			account, ok := accounts[args[0]]
+			if !ok {
+				log.Fatalf("account %q not found in the wallet", args[0])
+			}
+			if !yes {
+				confirmExport()
+			}
+			if account.Mnemonic == "" {
+				log.Printf("The account was migrated from a plaintext seed and has no mnemonic.\nSeed: %s\n", base58.Encode(account.Seed))
+				return
+			}
+			log.Printf("%s\n", account.Mnemonic)
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "show the mnemonic without asking for confirmation")
+	return cmd
+}
+
+func migrateCmd() *cobra.Command {
+	var name string
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move the plaintext seed of the config to the encrypted wallet",
+		Long: "Move the plaintext seed stored in the config by the older versions of wasp-cli to an account " +
+			"of the encrypted wallet, and remove it from the config. The addresses of the seed are kept.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			seedb58 := viper.GetString(legacySeedKey)
+			if seedb58 == "" {
+				log.Fatalf("there is no plaintext seed in %s", config.ConfigPath)
+			}
+			seed, err := base58.Decode(seedb58)
+			log.Check(err)
+
+			ks := openWalletFile(!walletFileExists())
+			addAccount(ks, name, &Account{Seed: seed, CoinType: cryptolib.CoinTypeIOTA})
+			log.Check(ks.Close())
+			useAccount(name)
+
+			viper.Set(legacySeedKey, "")
+			log.Check(viper.WriteConfig())
+			log.Printf("Moved the seed to the account %s of the encrypted wallet %s\n", name, walletFilePath())
+		},
+	}
+	cmd.Flags().StringVarP(&name, "name", "", defaultAccountName, "name of the account")
+	return cmd
+}
+
+// newAccount adds an account with a new mnemonic to the wallet and returns the mnemonic
+func newAccount(ks keystore.KeyStore, name string, coinType uint32) string {
+	mnemonic, err := cryptolib.NewMnemonic()
+	log.Check(err)
+	addAccount(ks, name, &Account{Mnemonic: mnemonic, CoinType: coinType})
+	return mnemonic
+}
+
+func printMnemonic(mnemonic string) {
+	log.Printf("\nWrite down the mnemonic of the account and keep it safe, it is the only way to recover the account " +
+		"if the wallet file or its passphrase is lost. It can be shown again with `wallet export`.\n")
+	log.Printf("\nMnemonic: %s\n", mnemonic)
+}
+
+func confirmExport() {
+	// don't prompt if running in a script
+	fi, _ := os.Stdin.Stat()
+	if (fi.Mode() & os.ModeCharDevice) == 0 {
+		log.Fatalf("not exported: confirm with --yes when running in a script")
+	}
+	log.Printf("Anyone who sees the mnemonic controls the funds of the account. Show it? [y/N] ")
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	if strings.ToLower(scanner.Text()) != "y" {
+		log.Fatalf("not exported.")
+	}
+}
@@ -11,6 +11,8 @@ func Init(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(mintCmd)
 	rootCmd.AddCommand(sendFundsCmd())
 	rootCmd.AddCommand(requestFundsCmd)
+	rootCmd.AddCommand(walletCmd())
 
 	rootCmd.PersistentFlags().IntVarP(&addressIndex, "address-index", "i", 0, "address index")
+	rootCmd.PersistentFlags().StringVarP(&accountName, "account", "", "", "wallet account to use instead of the current one")
 }
@@ -19,6 +19,8 @@ var addressCmd = &cobra.Command{
 		log.Verbosef("  Private key: %s\n", wallet.KeyPair.GetPrivateKey().String())
 		log.Verbosef("  Public key:  %s\n", wallet.KeyPair.GetPublicKey().String())
 		log.Printf("  Address:     %s\n", wallet.Address().Bech32(parameters.L1().Protocol.Bech32HRP))
+		log.Printf("  Account:     %s\n", wallet.AccountName)
+		log.Printf("  Ethereum address: %s\n", wallet.EthereumAddress().Hex())
 	},
 }
 
@@ -35,6 +37,7 @@ var balanceCmd = &cobra.Command{
 
 		log.Printf("Address index %d\n", addressIndex)
 		log.Printf("  Address: %s\n", address.Bech32(parameters.L1().Protocol.Bech32HRP))
+		log.Printf("  Account: %s\n", wallet.AccountName)
 		log.Printf("  Balance:\n")
 		if log.VerboseFlag {
 			printOutputsByOutputID(outs)
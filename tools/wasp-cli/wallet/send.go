@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"os"
+
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/isc"
 	"github.com/iotaledger/wasp/packages/transaction"
@@ -12,6 +14,8 @@ import (
 
 func sendFundsCmd() *cobra.Command {
 	var adjustStorageDeposit bool
+	var unsignedFile string
+	var from string
 
 	cmd := &cobra.Command{
 		Use:   "send-funds <target-address> <token-id>:<amount> <token-id2>:<amount> ...",
@@ -26,8 +30,16 @@ func sendFundsCmd() *cobra.Command {
 
 			log.Printf("\nSending \n\t%v \n\tto: %v\n\n", tokens, args[0])
 
-			wallet := Load()
-			senderAddress := wallet.Address()
+			var senderAddress iotago.Address
+			if from != "" {
+				if unsignedFile == "" {
+					log.Fatalf("--from needs --unsigned")
+				}
+				_, senderAddress, err = iotago.ParseBech32(from)
+				log.Check(err)
+			} else {
+				senderAddress = Load().Address()
+			}
 			client := config.L1Client()
 
 			outputSet, err := client.OutputMap(senderAddress)
@@ -46,18 +58,26 @@ func sendFundsCmd() *cobra.Command {
 				util.SDAdjustmentPrompt(output)
 			}
 
-			tx, err := transaction.NewTransferTransaction(transaction.NewTransferTransactionParams{
+			essence, err := transaction.NewTransferTransactionEssence(transaction.NewTransferTransactionParams{
 				DisableAutoAdjustStorageDeposit: false,
 				FungibleTokens:                  tokens,
 				SendOptions:                     isc.SendOptions{},
 				SenderAddress:                   senderAddress,
-				SenderKeyPair:                   wallet.KeyPair,
 				TargetAddress:                   targetAddress,
 				UnspentOutputs:                  outputSet,
 				UnspentOutputIDs:                isc.OutputSetToOutputIDs(outputSet),
 			})
 			log.Check(err)
 
+			if unsignedFile != "" {
+				util.NewOfflineTransactionFile(essence).Write(unsignedFile)
+				log.Printf("Unsigned transaction written to %s (sign it with: %s sign %s)\n", unsignedFile, os.Args[0], unsignedFile)
+				return
+			}
+
+			tx, err := transaction.SignTxEssence(essence, Load().KeyPair)
+			log.Check(err)
+
 			txID, err := tx.ID()
 			log.Check(err)
 
@@ -69,6 +89,9 @@ func sendFundsCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&adjustStorageDeposit, "adjust-storage-deposit", "s", false, "adjusts the amount of base tokens sent, if it's lower than the min storage deposit required")
+	cmd.Flags().StringVarP(&unsignedFile, "unsigned", "", "",
+		"don't sign and send the transaction, write it to the given file to be signed with `sign` and posted with `submit`")
+	cmd.Flags().StringVarP(&from, "from", "", "", "address to send the funds from, instead of the wallet address. Needs --unsigned")
 
 	return cmd
 }
@@ -1,54 +1,102 @@
 package wallet
 
 import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/wasp/packages/cryptolib"
+	"github.com/iotaledger/wasp/packages/evm/evmutil"
+	"github.com/iotaledger/wasp/packages/keystore"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/mr-tron/base58"
-	"github.com/spf13/cobra"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
 	"github.com/spf13/viper"
 )
 
-type WalletConfig struct {
-	Seed []byte
+// PassphraseEnvVar is the environment variable with the passphrase of the wallet file,
+// if it is not set the passphrase is asked for
+const PassphraseEnvVar = "WASP_CLI_WALLET_PASSPHRASE"
+
+const (
+	walletFileKey    = "wallet.file"
+	walletAccountKey = "wallet.account"
+	// legacySeedKey is the plaintext seed stored in the config by the older versions of wasp-cli
+	legacySeedKey = "wallet.seed"
+
+	defaultAccountName = "default"
+)
+
+// accountsKey is the entry of the wallet file with all the accounts
+var accountsKey = []byte("accounts")
+
+// Account is a named account of the wallet. Its Ed25519 and Ethereum keys are derived from the BIP-39
+// mnemonic, or from the seed for the accounts migrated from the plaintext config
+type Account struct {
+	Mnemonic string `json:"mnemonic,omitempty"`
+	Seed     []byte `json:"seed,omitempty"`
+	CoinType uint32 `json:"coinType"`
 }
 
 type Wallet struct {
-	KeyPair *cryptolib.KeyPair
+	AccountName string
+	KeyPair     *cryptolib.KeyPair
+	EthereumKey *ecdsa.PrivateKey
 }
 
-var initCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Initialize a new wallet",
-	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		seed := cryptolib.NewSeed()
-		seedString := base58.Encode(seed[:])
-		viper.Set("wallet.seed", seedString)
-		log.Check(viper.WriteConfig())
+var (
+	addressIndex int
+	accountName  string
 
-		log.Printf("Initialized wallet seed in %s\n", config.ConfigPath)
-		log.Printf("\nIMPORTANT: wasp-cli is alpha phase. The seed is currently being stored " +
-			"in a plain text file which is NOT secure. Do not use this seed to store funds " +
-			"in the mainnet!\n")
-		log.Verbosef("\nSeed: %s\n", seedString)
-	},
-}
+	loaded *Wallet
+)
 
+// Load unlocks the wallet file and returns the keys of the current account at the address index
 func Load() *Wallet {
-	seedb58 := viper.GetString("wallet.seed")
-	if seedb58 == "" {
+	if loaded != nil {
+		return loaded
+	}
+	if !walletFileExists() {
+		if viper.GetString(legacySeedKey) != "" {
+			log.Fatalf("the wallet seed is stored in plaintext in %s, move it to an encrypted wallet with `wallet migrate`", config.ConfigPath)
+		}
 		log.Fatalf("call `init` first")
 	}
-	seedBytes, err := base58.Decode(seedb58)
-	log.Check(err)
-	seed := cryptolib.NewSeedFromBytes(seedBytes)
-	kp := cryptolib.NewKeyPairFromSeed(seed.SubSeed(uint64(addressIndex)))
-	return &Wallet{KeyPair: kp}
+	name := currentAccountName()
+	accounts := readAccounts(openWalletFile(false))
+	account, ok := accounts[name]
+	if !ok {
+		log.Fatalf("account %q not found in the wallet", name)
+	}
+	loaded = account.load(name, uint32(addressIndex))
+	return loaded
 }
 
-var addressIndex int
+func (a *Account) load(name string, index uint32) *Wallet {
+	ret := &Wallet{AccountName: name}
+	if a.Mnemonic != "" {
+		seed, err := cryptolib.SeedFromMnemonic(a.Mnemonic)
+		log.Check(err)
+		ret.KeyPair, err = cryptolib.NewKeyPairFromMnemonicSeed(seed, cryptolib.IOTADerivationPath(a.CoinType, 0, index))
+		log.Check(err)
+		ret.EthereumKey, err = evmutil.NewKeyFromMnemonicSeed(seed, evmutil.EthereumDerivationPath(index))
+		log.Check(err)
+		return ret
+	}
+	// keep the addresses of the migrated seed
+	seed := cryptolib.NewSeedFromBytes(a.Seed)
+	ret.KeyPair = cryptolib.NewKeyPairFromSeed(seed.SubSeed(uint64(index)))
+	var err error
+	ret.EthereumKey, err = evmutil.NewKeyFromMnemonicSeed(a.Seed, evmutil.EthereumDerivationPath(index))
+	log.Check(err)
+	return ret
+}
 
 func (w *Wallet) PrivateKey() *cryptolib.PrivateKey {
 	return w.KeyPair.GetPrivateKey()
@@ -57,3 +105,85 @@ func (w *Wallet) PrivateKey() *cryptolib.PrivateKey {
 func (w *Wallet) Address() iotago.Address {
 	return w.KeyPair.GetPublicKey().AsEd25519Address()
 }
+
+func (w *Wallet) EthereumAddress() common.Address {
+	return crypto.PubkeyToAddress(w.EthereumKey.PublicKey)
+}
+
+func currentAccountName() string {
+	if accountName != "" {
+		return accountName
+	}
+	if name := viper.GetString(walletAccountKey); name != "" {
+		return name
+	}
+	return defaultAccountName
+}
+
+func walletFilePath() string {
+	if fname := viper.GetString(walletFileKey); fname != "" {
+		return fname
+	}
+	return filepath.Join(filepath.Dir(config.ConfigPath), "wasp-cli-wallet.json")
+}
+
+func walletFileExists() bool {
+	_, err := os.Stat(walletFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	log.Check(err)
+	return true
+}
+
+// openWalletFile unlocks the encrypted wallet file, or creates it if create is set
+func openWalletFile(create bool) keystore.KeyStore {
+	fname := walletFilePath()
+	if create && walletFileExists() {
+		log.Fatalf("the wallet file %s already exists", fname)
+	}
+	ks, err := keystore.NewFileKeyStore(fname, util.ReadPassphrase(PassphraseEnvVar, "Wallet passphrase", create))
+	log.Check(err)
+	return ks
+}
+
+func readAccounts(ks keystore.KeyStore) map[string]*Account {
+	ret := make(map[string]*Account)
+	data, err := ks.Get(accountsKey)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		return ret
+	}
+	log.Check(err)
+	log.Check(json.Unmarshal(data, &ret))
+	return ret
+}
+
+func writeAccounts(ks keystore.KeyStore, accounts map[string]*Account) {
+	data, err := json.Marshal(accounts)
+	log.Check(err)
+	log.Check(ks.Set(accountsKey, data))
+}
+
+// addAccount stores a new account in the wallet file
+func addAccount(ks keystore.KeyStore, name string, account *Account) {
+	accounts := readAccounts(ks)
+	if _, ok := accounts[name]; ok {
+		log.Fatalf("account %q already exists", name)
+	}
+	accounts[name] = account
+	writeAccounts(ks, accounts)
+}
+
+func useAccount(name string) {
+	viper.Set(walletAccountKey, name)
+	log.Check(viper.WriteConfig())
+}
+
+func sortedAccountNames(accounts map[string]*Account) []string {
+	ret := make([]string, 0, len(accounts))
+	for name := range accounts {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
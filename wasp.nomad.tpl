@@ -86,6 +86,9 @@ variable "wasp_config" {
     "directory": "{{ env "NOMAD_TASK_DIR" }}/wal",
     "enabled": true
   },
+  "statemgr": {
+    "fastSyncThreshold": 0
+  },
   "debug": {
     "rawblocksEnabled": false,
     "rawblocksDirectory": "{{ env "NOMAD_TASK_DIR" }}/blocks"